@@ -130,6 +130,9 @@ func createPrecompileHook(abifilename string, template string) bind.BindHook {
 			for _, key := range allowlist.AllowListFuncKeys {
 				delete(funcs, key)
 			}
+			// signed role approvals are also provided by the AllowList if present in the ABI.
+			delete(funcs, allowlist.SetRoleWithSignatureFuncKey)
+			delete(funcs, allowlist.GetRoleNonceFuncKey)
 		}
 
 		precompileContract := &tmplPrecompileContract{
@@ -262,6 +262,12 @@ func {{decapitalise .Normalized.Name}}(accessibleState contract.AccessibleState,
 	{{- if len .Normalized.Inputs | ne 0}}
 	_ = inputStruct // CUSTOM CODE OPERATES ON INPUT
 	{{- end}}
+	{{- if .Original.IsPayable}}
+	// {{.Normalized.Name}} is payable. The value sent by the caller has been credited to ContractAddress
+	// and can be forwarded with contract.TransferBalance.
+	value := contract.CallValue(accessibleState)
+	_ = value // CUSTOM CODE OPERATES ON VALUE
+	{{- end}}
 
 	{{- if len .Normalized.Outputs | eq 0}}
 	// this function does not return an output, leave this one as is
@@ -341,7 +347,11 @@ func create{{.Contract.Type}}Precompile() contract.StatefulPrecompiledContract {
 		if !ok {
 			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
 		}
-		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+		if method.IsPayable() {
+			functions = append(functions, contract.NewPayableStatefulPrecompileFunction(method.ID, function))
+		} else {
+			functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+		}
 	}
 
 	{{- if .Contract.Fallback}}
@@ -60,6 +60,19 @@ func NewExternalBackend(endpoint string) (*ExternalBackend, error) {
 	}, nil
 }
 
+// NewExternalBackendWithPolicy returns an ExternalBackend connected to the external signer at
+// [endpoint] (an IPC path or HTTP/WS URL) which applies [policy] to every signing request
+// before it is forwarded to the external signer.
+func NewExternalBackendWithPolicy(endpoint string, policy *Policy) (*ExternalBackend, error) {
+	signer, err := NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalBackend{
+		signers: []accounts.Wallet{&policyWallet{Wallet: signer, policy: policy}},
+	}, nil
+}
+
 func (eb *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
 	return event.NewSubscription(func(quit <-chan struct{}) error {
 		<-quit
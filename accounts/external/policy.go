@@ -0,0 +1,122 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package external
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	errAccountNotAllowed    = errors.New("account is not allowed to sign by the external signer policy")
+	errRecipientNotAllowed  = errors.New("transaction recipient is not allowed by the external signer policy")
+	errCreateNotAllowed     = errors.New("contract creation is not allowed by the external signer policy")
+	errValueExceedsMax      = errors.New("transaction value exceeds the maximum allowed by the external signer policy")
+	errDataSigningForbidden = errors.New("data signing is not allowed by the external signer policy")
+)
+
+// Policy defines the approval rules the node applies to signing requests before
+// they are forwarded to the external signer. The external signer may apply its own
+// rules on top of these. An empty policy approves every request.
+type Policy struct {
+	// AllowedAccounts restricts which accounts may sign. If empty, all accounts
+	// exposed by the external signer may sign.
+	AllowedAccounts []common.Address `json:"allowedAccounts,omitempty"`
+	// AllowedRecipients restricts the recipients of signed transactions. If empty,
+	// transactions to any recipient may be signed.
+	AllowedRecipients []common.Address `json:"allowedRecipients,omitempty"`
+	// AllowContractCreation permits signing contract creation transactions when
+	// [AllowedRecipients] is non-empty.
+	AllowContractCreation bool `json:"allowContractCreation,omitempty"`
+	// MaxValue is the maximum value (in wei) a signed transaction may transfer.
+	// If nil, no maximum is enforced.
+	MaxValue *math.HexOrDecimal256 `json:"maxValue,omitempty"`
+	// DisallowDataSigning rejects all requests to sign arbitrary data or text.
+	DisallowDataSigning bool `json:"disallowDataSigning,omitempty"`
+}
+
+// CheckAccount returns an error if [account] is not permitted to sign by [p].
+func (p *Policy) CheckAccount(account common.Address) error {
+	if len(p.AllowedAccounts) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowedAccounts {
+		if allowed == account {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errAccountNotAllowed, account)
+}
+
+// CheckTx returns an error if [account] is not permitted to sign [tx] by [p].
+func (p *Policy) CheckTx(account common.Address, tx *types.Transaction) error {
+	if err := p.CheckAccount(account); err != nil {
+		return err
+	}
+	if p.MaxValue != nil && tx.Value().Cmp((*big.Int)(p.MaxValue)) > 0 {
+		return fmt.Errorf("%w: %s > %s", errValueExceedsMax, tx.Value(), (*big.Int)(p.MaxValue))
+	}
+	if len(p.AllowedRecipients) == 0 {
+		return nil
+	}
+	to := tx.To()
+	if to == nil {
+		if p.AllowContractCreation {
+			return nil
+		}
+		return errCreateNotAllowed
+	}
+	for _, allowed := range p.AllowedRecipients {
+		if allowed == *to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errRecipientNotAllowed, *to)
+}
+
+// CheckData returns an error if [account] is not permitted to sign arbitrary data by [p].
+func (p *Policy) CheckData(account common.Address) error {
+	if p.DisallowDataSigning {
+		return errDataSigningForbidden
+	}
+	return p.CheckAccount(account)
+}
+
+// policyWallet wraps a wallet backed by an external signer and applies [policy]
+// to every signing request before forwarding it.
+type policyWallet struct {
+	accounts.Wallet
+	policy *Policy
+}
+
+func (w *policyWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	if err := w.policy.CheckData(account.Address); err != nil {
+		log.Warn("Rejected external signer data signing request", "account", account.Address, "err", err)
+		return nil, err
+	}
+	return w.Wallet.SignData(account, mimeType, data)
+}
+
+func (w *policyWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	if err := w.policy.CheckData(account.Address); err != nil {
+		log.Warn("Rejected external signer text signing request", "account", account.Address, "err", err)
+		return nil, err
+	}
+	return w.Wallet.SignText(account, text)
+}
+
+func (w *policyWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if err := w.policy.CheckTx(account.Address, tx); err != nil {
+		log.Warn("Rejected external signer transaction signing request", "account", account.Address, "txHash", tx.Hash(), "err", err)
+		return nil, err
+	}
+	return w.Wallet.SignTx(account, tx, chainID)
+}
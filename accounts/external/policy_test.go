@@ -0,0 +1,103 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package external
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheckTx(t *testing.T) {
+	var (
+		allowedAccount   = common.Address{1}
+		otherAccount     = common.Address{2}
+		allowedRecipient = common.Address{3}
+		otherRecipient   = common.Address{4}
+		maxValue         = math.HexOrDecimal256(*big.NewInt(100))
+	)
+	newTx := func(to *common.Address, value int64) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: to, Value: big.NewInt(value)})
+	}
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		account     common.Address
+		tx          *types.Transaction
+		expectedErr error
+	}{
+		{
+			name:    "empty policy",
+			policy:  Policy{},
+			account: otherAccount,
+			tx:      newTx(&otherRecipient, 1_000),
+		},
+		{
+			name:        "account not allowed",
+			policy:      Policy{AllowedAccounts: []common.Address{allowedAccount}},
+			account:     otherAccount,
+			tx:          newTx(&otherRecipient, 0),
+			expectedErr: errAccountNotAllowed,
+		},
+		{
+			name:        "recipient not allowed",
+			policy:      Policy{AllowedRecipients: []common.Address{allowedRecipient}},
+			account:     allowedAccount,
+			tx:          newTx(&otherRecipient, 0),
+			expectedErr: errRecipientNotAllowed,
+		},
+		{
+			name:    "recipient allowed",
+			policy:  Policy{AllowedRecipients: []common.Address{allowedRecipient}},
+			account: allowedAccount,
+			tx:      newTx(&allowedRecipient, 0),
+		},
+		{
+			name:        "contract creation not allowed",
+			policy:      Policy{AllowedRecipients: []common.Address{allowedRecipient}},
+			account:     allowedAccount,
+			tx:          newTx(nil, 0),
+			expectedErr: errCreateNotAllowed,
+		},
+		{
+			name:    "contract creation allowed",
+			policy:  Policy{AllowedRecipients: []common.Address{allowedRecipient}, AllowContractCreation: true},
+			account: allowedAccount,
+			tx:      newTx(nil, 0),
+		},
+		{
+			name:        "value exceeds max",
+			policy:      Policy{MaxValue: &maxValue},
+			account:     allowedAccount,
+			tx:          newTx(&allowedRecipient, 101),
+			expectedErr: errValueExceedsMax,
+		},
+		{
+			name:    "value at max",
+			policy:  Policy{MaxValue: &maxValue},
+			account: allowedAccount,
+			tx:      newTx(&allowedRecipient, 100),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.policy.CheckTx(test.account, test.tx)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestPolicyCheckData(t *testing.T) {
+	policy := Policy{DisallowDataSigning: true}
+	require.ErrorIs(t, policy.CheckData(common.Address{1}), errDataSigningForbidden)
+
+	policy = Policy{AllowedAccounts: []common.Address{{1}}}
+	require.NoError(t, policy.CheckData(common.Address{1}))
+	require.ErrorIs(t, policy.CheckData(common.Address{2}), errAccountNotAllowed)
+}
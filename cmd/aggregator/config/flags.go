@@ -0,0 +1,177 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/warp/aggregator"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const Version = "v0.1.0"
+
+const (
+	ConfigFilePathKey        = "config-file"
+	LogLevelKey              = "log-level"
+	VersionKey               = "version"
+	SubnetIDKey              = "subnet-id"
+	SourceChainIDKey         = "source-chain-id"
+	PChainAPIKey             = "p-chain-api"
+	ValidatorEndpointsKey    = "validator-endpoints"
+	HTTPHostKey              = "http-host"
+	HTTPPortKey              = "http-port"
+	QuorumNumKey             = "quorum-num"
+	QuorumDenKey             = "quorum-den"
+	AggregationTimeoutKey    = "aggregation-timeout"
+	MaxConcurrentRequestsKey = "max-concurrent-requests"
+	RequestIntervalKey       = "request-interval"
+	TransportKey             = "transport"
+	JWTSecretFileKey         = "jwt-secret-file"
+)
+
+// Transports used to fetch signatures from validators.
+const (
+	// APITransport fetches signatures with the warp_getSignature JSON-RPC method.
+	APITransport = "api"
+	// HTTPSignTransport fetches signatures from the authenticated warp sign HTTP
+	// endpoint of validators, which requires [JWTSecretFileKey].
+	HTTPSignTransport = "http-sign"
+)
+
+var (
+	ErrNoSubnetID           = errors.New("must specify the subnet ID")
+	ErrNoSourceChainID      = errors.New("must specify the source chain ID")
+	ErrNoPChainAPI          = errors.New("must specify the P-Chain API endpoint")
+	ErrNoValidatorEndpoints = errors.New("must specify at least one validator endpoint")
+	ErrNoJWTSecretFile      = errors.New("must specify a jwt secret file to use the http-sign transport")
+)
+
+type Config struct {
+	SubnetID              ids.ID                `json:"subnet-id"`
+	SourceChainID         ids.ID                `json:"source-chain-id"`
+	PChainAPI             string                `json:"p-chain-api"`
+	ValidatorEndpoints    map[ids.NodeID]string `json:"validator-endpoints"`
+	HTTPHost              string                `json:"http-host"`
+	HTTPPort              uint16                `json:"http-port"`
+	QuorumNum             uint64                `json:"quorum-num"`
+	QuorumDen             uint64                `json:"quorum-den"`
+	AggregationTimeout    time.Duration         `json:"aggregation-timeout"`
+	MaxConcurrentRequests int                   `json:"max-concurrent-requests"`
+	RequestInterval       time.Duration         `json:"request-interval"`
+	Transport             string                `json:"transport"`
+	JWTSecretFile         string                `json:"jwt-secret-file"`
+}
+
+func BuildConfig(v *viper.Viper) (Config, error) {
+	c := Config{
+		PChainAPI:             v.GetString(PChainAPIKey),
+		ValidatorEndpoints:    make(map[ids.NodeID]string),
+		HTTPHost:              v.GetString(HTTPHostKey),
+		HTTPPort:              v.GetUint16(HTTPPortKey),
+		QuorumNum:             v.GetUint64(QuorumNumKey),
+		QuorumDen:             v.GetUint64(QuorumDenKey),
+		AggregationTimeout:    v.GetDuration(AggregationTimeoutKey),
+		MaxConcurrentRequests: v.GetInt(MaxConcurrentRequestsKey),
+		RequestInterval:       v.GetDuration(RequestIntervalKey),
+		Transport:             v.GetString(TransportKey),
+		JWTSecretFile:         v.GetString(JWTSecretFileKey),
+	}
+	var err error
+	if !v.IsSet(SubnetIDKey) {
+		return c, ErrNoSubnetID
+	}
+	if c.SubnetID, err = ids.FromString(v.GetString(SubnetIDKey)); err != nil {
+		return c, fmt.Errorf("invalid subnet ID: %w", err)
+	}
+	if !v.IsSet(SourceChainIDKey) {
+		return c, ErrNoSourceChainID
+	}
+	if c.SourceChainID, err = ids.FromString(v.GetString(SourceChainIDKey)); err != nil {
+		return c, fmt.Errorf("invalid source chain ID: %w", err)
+	}
+	if len(c.PChainAPI) == 0 {
+		return c, ErrNoPChainAPI
+	}
+	for nodeIDStr, uri := range v.GetStringMapString(ValidatorEndpointsKey) {
+		nodeID, err := ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return c, fmt.Errorf("invalid validator endpoint node ID %q: %w", nodeIDStr, err)
+		}
+		c.ValidatorEndpoints[nodeID] = uri
+	}
+	if len(c.ValidatorEndpoints) == 0 {
+		return c, ErrNoValidatorEndpoints
+	}
+	if err := aggregator.ValidateQuorum(c.QuorumNum, c.QuorumDen); err != nil {
+		return c, fmt.Errorf("invalid default quorum: %w", err)
+	}
+	if c.MaxConcurrentRequests < 0 || c.RequestInterval < 0 {
+		return c, fmt.Errorf("request limits must be non-negative (max concurrent requests: %d, request interval: %s)", c.MaxConcurrentRequests, c.RequestInterval)
+	}
+	switch c.Transport {
+	case APITransport:
+	case HTTPSignTransport:
+		if len(c.JWTSecretFile) == 0 {
+			return c, ErrNoJWTSecretFile
+		}
+	default:
+		return c, fmt.Errorf("invalid transport %q: must be %q or %q", c.Transport, APITransport, HTTPSignTransport)
+	}
+	return c, nil
+}
+
+func BuildViper(fs *pflag.FlagSet, args []string) (*viper.Viper, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.SetEnvPrefix("signature_aggregator")
+	if err := v.BindPFlags(fs); err != nil {
+		return nil, err
+	}
+
+	if v.IsSet(ConfigFilePathKey) {
+		v.SetConfigFile(v.GetString(ConfigFilePathKey))
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// BuildFlagSet returns a complete set of flags for the signature aggregator
+func BuildFlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("signature-aggregator", pflag.ContinueOnError)
+	addAggregatorFlags(fs)
+	return fs
+}
+
+func addAggregatorFlags(fs *pflag.FlagSet) {
+	fs.Bool(VersionKey, false, "Print the version and exit")
+	fs.String(ConfigFilePathKey, "", "Specify the config path to use to load a YAML or JSON config for the signature aggregator")
+	fs.String(LogLevelKey, "info", "Specify the log level to use in the signature aggregator")
+	fs.String(SubnetIDKey, "", "Specify the ID of the subnet whose validators sign the warp messages")
+	fs.String(SourceChainIDKey, "", "Specify the ID of the blockchain sending the warp messages")
+	fs.String(PChainAPIKey, "http://127.0.0.1:9650", "Specify the URI of a node serving the P-Chain API, used to look up validator sets")
+	fs.StringToString(ValidatorEndpointsKey, nil, "Specify the base API URI of each validator as a comma separated list of NodeID=URI pairs")
+	fs.String(HTTPHostKey, "127.0.0.1", "Specify the host to serve the HTTP API on")
+	fs.Uint16(HTTPPortKey, 8090, "Specify the port to serve the HTTP API on")
+	fs.Uint64(QuorumNumKey, params.WarpDefaultQuorumNumerator, "Specify the numerator of the default fraction of stake that must sign a message")
+	fs.Uint64(QuorumDenKey, params.WarpQuorumDenominator, "Specify the denominator of the default fraction of stake that must sign a message")
+	fs.Duration(AggregationTimeoutKey, 30*time.Second, "Specify the timeout to aggregate the signatures of a message (0 indicates no timeout)")
+	fs.Int(MaxConcurrentRequestsKey, 256, "Specify the maximum number of signature requests in flight at once while aggregating (0 indicates no limit)")
+	fs.Duration(RequestIntervalKey, 0, "Specify the minimum time between sending consecutive signature requests (0 indicates no pacing)")
+	fs.String(TransportKey, APITransport, fmt.Sprintf("Specify how to fetch signatures from validators: %q uses the warp API, %q uses the authenticated warp sign HTTP endpoint", APITransport, HTTPSignTransport))
+	fs.String(JWTSecretFileKey, "", "Specify the path to a file holding the hex encoded 32 byte secret used to authenticate requests to the warp sign HTTP endpoint")
+}
@@ -0,0 +1,136 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/subnet-evm/cmd/aggregator/config"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/warp"
+	"github.com/ava-labs/subnet-evm/warp/aggregator"
+	warpValidators "github.com/ava-labs/subnet-evm/warp/validators"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/spf13/pflag"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	fs := config.BuildFlagSet()
+	v, err := config.BuildViper(fs, os.Args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	if err != nil {
+		fmt.Printf("couldn't build viper: %s\n", err)
+		os.Exit(1)
+	}
+
+	if v.GetBool(config.VersionKey) {
+		fmt.Printf("%s\n", config.Version)
+		os.Exit(0)
+	}
+
+	logLevel, err := log.LvlFromString(v.GetString(config.LogLevelKey))
+	if err != nil {
+		fmt.Printf("couldn't parse log level: %s\n", err)
+		os.Exit(1)
+	}
+	log.Root().SetHandler(log.LvlFilterHandler(logLevel, log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	config, err := config.BuildConfig(v)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
+	}
+	if err := run(config); err != nil {
+		fmt.Printf("signature aggregator failed: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run serves signature aggregation over HTTP as specified by [c] until it receives
+// an interrupt or termination signal.
+func run(c config.Config) error {
+	signatureGetter, err := newSignatureGetter(c)
+	if err != nil {
+		return err
+	}
+	var (
+		state  = warpValidators.NewPChainState(platformvm.NewClient(c.PChainAPI))
+		signer = aggregator.NewWithConfig(c.SubnetID, state, signatureGetter, aggregator.Config{
+			MaxConcurrentRequests: c.MaxConcurrentRequests,
+			RequestInterval:       c.RequestInterval,
+		})
+		server = &http.Server{
+			Addr:              net.JoinHostPort(c.HTTPHost, strconv.Itoa(int(c.HTTPPort))),
+			Handler:           aggregator.NewHTTPHandler(signer, c.QuorumNum, c.QuorumDen, c.AggregationTimeout),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+	)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("Serving signature aggregation", "addr", server.Addr, "subnetID", c.SubnetID, "sourceChainID", c.SourceChainID, "validators", len(c.ValidatorEndpoints), "transport", c.Transport)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		return err
+	case sig := <-signals:
+		log.Info("Shutting down signature aggregator", "signal", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// newSignatureGetter returns the SignatureGetter that fetches signatures from the
+// validators in [c] with the configured transport.
+func newSignatureGetter(c config.Config) (aggregator.SignatureGetter, error) {
+	if c.Transport == config.HTTPSignTransport {
+		secretBytes, err := os.ReadFile(c.JWTSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt secret: %w", err)
+		}
+		secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(secretBytes)), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode jwt secret: %w", err)
+		}
+		auth, err := rpc.NewJWTAuth(secret)
+		if err != nil {
+			return nil, err
+		}
+		return warp.NewHTTPSignatureGetter(c.ValidatorEndpoints, c.SourceChainID.String(), auth), nil
+	}
+
+	clients := make(map[ids.NodeID]warp.Client, len(c.ValidatorEndpoints))
+	for nodeID, uri := range c.ValidatorEndpoints {
+		client, err := warp.NewClient(uri, c.SourceChainID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create warp client for %s: %w", nodeID, err)
+		}
+		clients[nodeID] = client
+	}
+	return warp.NewAPIFetcher(clients), nil
+}
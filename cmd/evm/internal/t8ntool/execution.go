@@ -171,6 +171,17 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 	// 	misc.ApplyDAOHardFork(statedb)
 	// }
 
+	// Apply any precompile or state upgrades activated by the transition from the
+	// parent block to this block. Upgrades activated at or before the parent timestamp
+	// are expected to already be reflected in the prestate.
+	upgradeContext := types.NewBlockWithHeader(&types.Header{
+		Number: vmContext.BlockNumber,
+		Time:   vmContext.Time,
+	})
+	if err := core.ApplyUpgrades(chainConfig, &pre.Env.ParentTimestamp, upgradeContext, statedb); err != nil {
+		return nil, nil, NewError(ErrorEVM, fmt.Errorf("could not apply upgrades: %v", err))
+	}
+
 	for i, tx := range txs {
 		msg, err := core.TransactionToMessage(tx, signer, pre.Env.BaseFee)
 		if err != nil {
@@ -113,6 +113,12 @@ var (
 			"The '.rlp' format is identical to the output.body format.",
 		Value: "txs.json",
 	}
+	InputGenesisFlag = &cli.StringFlag{
+		Name: "input.genesis",
+		Usage: "File name of a subnet-evm genesis whose chain config (including fee config, precompile " +
+			"and state upgrades) is used instead of --state.fork. Upgrades activating between the parent and " +
+			"current timestamp of the env are applied before the transactions.",
+	}
 	InputHeaderFlag = &cli.StringFlag{
 		Name:  "input.header",
 		Usage: "`stdin` or file name of where to find the block header to use.",
@@ -192,15 +192,34 @@ func Transition(ctx *cli.Context) error {
 		Tracer: tracer,
 	}
 	// Construct the chainconfig
-	var chainConfig *params.ChainConfig
-	if cConf, extraEips, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name)); err != nil {
+	var (
+		chainConfig *params.ChainConfig
+		feeConfig   = params.DefaultFeeConfig
+	)
+	if genesisStr := ctx.String(InputGenesisFlag.Name); len(genesisStr) > 0 {
+		// Use the subnet-evm rules (including precompile and state upgrades) from the genesis.
+		var genesis core.Genesis
+		if err := readFile(genesisStr, "genesis", &genesis); err != nil {
+			return err
+		}
+		if genesis.Config == nil {
+			return NewError(ErrorConfig, errors.New("genesis is missing chain config"))
+		}
+		if err := genesis.Verify(); err != nil {
+			return NewError(ErrorConfig, fmt.Errorf("invalid genesis: %v", err))
+		}
+		chainConfig = genesis.Config
+		feeConfig = chainConfig.FeeConfig
+	} else if cConf, extraEips, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name)); err != nil {
 		return NewError(ErrorConfig, fmt.Errorf("failed constructing chain configuration: %v", err))
 	} else {
 		chainConfig = cConf
 		vmConfig.ExtraEips = extraEips
 	}
-	// Set the chain id
-	chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
+	// Set the chain id, unless it was taken from the genesis
+	if chainConfig.ChainID == nil || ctx.IsSet(ChainIDFlag.Name) {
+		chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
+	}
 
 	var txsWithKeys []*txWithKey
 	if txStr != stdinSelector {
@@ -269,7 +288,6 @@ func Transition(ctx *cli.Context) error {
 				GasLimit: prestate.Env.ParentGasLimit,
 				Extra:    make([]byte, params.DynamicFeeExtraDataSize), // TODO: consider passing extra through env
 			}
-			feeConfig := params.DefaultFeeConfig
 			if prestate.Env.MinBaseFee != nil {
 				// Override the default min base fee if it's set in the env
 				feeConfig.MinBaseFee = prestate.Env.MinBaseFee
@@ -16,4 +16,40 @@ interface IAllowList {
 
   // Read the status of [addr].
   function readAllowList(address addr) external view returns (uint256 role);
+
+  // Set [addr] to have [role], as approved by an EIP-712 signature of an address
+  // permitted to assign [role]. Can be submitted by anyone.
+  function setRoleWithSignature(
+    address addr,
+    uint256 role,
+    uint256 nonce,
+    uint256 deadline,
+    uint8 v,
+    bytes32 r,
+    bytes32 s
+  ) external;
+
+  // Read the nonce of the next role approval signed by [signer].
+  function getRoleNonce(address signer) external view returns (uint256 nonce);
+
+  // Propose a call to the precompile to be executed as its threshold admin. The proposal
+  // is confirmed by the caller, who must be a threshold signer.
+  function proposeCall(bytes calldata data) external returns (uint256 proposalID);
+
+  // Confirm a call proposal. The caller must be a threshold signer.
+  function confirmCall(uint256 proposalID) external;
+
+  // Call the precompile with [data] as its threshold admin, once the proposal of [data]
+  // has the threshold of confirmations. Can be submitted by anyone.
+  function executeCall(uint256 proposalID, bytes calldata data) external;
+
+  // Read the hash of the proposed call, the number of confirmations and whether it was executed.
+  function getCallProposal(uint256 proposalID)
+    external
+    view
+    returns (
+      bytes32 callHash,
+      uint256 confirmations,
+      bool executed
+    );
 }
@@ -0,0 +1,9 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+interface IContractDeployerAllowList is IAllowList {
+  // Revoke the right to deploy contracts that [factory] inherited from an enabled deployer.
+  // Only callable by admins and managers, from the DUpgrade onwards.
+  function revokeFactory(address factory) external;
+}
@@ -0,0 +1,11 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+interface ICreate2Deployer {
+  // Deploy a contract with [initCode] at the address derived from the precompile address,
+  // [salt] and the hash of [initCode], which is the same on every chain
+  function deploy(bytes32 salt, bytes calldata initCode) external returns (address contractAddr);
+
+  // Returns the address a contract with init code hashing to [initCodeHash] is deployed at with [salt]
+  function computeAddress(bytes32 salt, bytes32 initCodeHash) external view returns (address contractAddr);
+}
@@ -0,0 +1,43 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// IDeliveryFee escrows fees paid to relayers for delivering warp messages.
+interface IDeliveryFee {
+  event DeliveryFeeAdded(bytes32 indexed messageID, address indexed depositor, address feeToken, uint256 amount);
+  event DeliveryFeeClaimed(bytes32 indexed messageID, address indexed relayer, address depositor, uint256 amount);
+  event DeliveryFeeReclaimed(bytes32 indexed messageID, address indexed depositor, uint256 amount);
+  event DeliveryRecorded(bytes32 indexed messageID, address indexed relayer);
+
+  // addDeliveryFee escrows [amount] of [feeToken] for the delivery of [messageID] to [destinationChainID].
+  // The zero address denotes native tokens, which must be sent as the value of the call. Other fee tokens
+  // must be precompiles implementing ERC-20, and the fee must be approved for this contract. Fees are escrowed
+  // per message and depositor, so each depositor can escrow one fee for a message.
+  function addDeliveryFee(bytes32 messageID, bytes32 destinationChainID, address feeToken, uint256 amount) external payable;
+
+  // recordDelivery must be called by the destination of the warp message verified at [index] when it receives
+  // the message. It sends a receipt crediting [relayer] to the source chain of the message.
+  function recordDelivery(uint32 index, address relayer) external;
+
+  // claimDeliveryFee pays the fees escrowed by [depositors] to the relayer named in the receipt verified at [index].
+  function claimDeliveryFee(uint32 index, address[] calldata depositors) external;
+
+  // reclaimDeliveryFee returns the unclaimed fee escrowed by the caller after the reclaim delay.
+  function reclaimDeliveryFee(bytes32 messageID) external;
+
+  // getDeliveryFee returns the fee escrowed for [messageID] by [depositor]
+  function getDeliveryFee(bytes32 messageID, address depositor)
+    external
+    view
+    returns (address depositor, bytes32 destinationChainID, address feeToken, uint256 amount, uint64 reclaimableAt);
+
+  // isDelivered returns whether the delivery of [messageID] to this chain has been recorded
+  function isDelivered(bytes32 messageID) external view returns (bool delivered);
+
+  // getMessageID returns the ID of the warp message sent from this chain with the given fields
+  function getMessageID(
+    address originSenderAddress,
+    bytes32 destinationChainID,
+    address destinationAddress,
+    bytes calldata payload
+  ) external view returns (bytes32 messageID);
+}
@@ -32,4 +32,62 @@ interface IFeeManager is IAllowList {
 
   // Get the last block number changed the fee config from the contract storage
   function getFeeConfigLastChangedAt() external view returns (uint256 blockNumber);
+
+  // Get the fee config that was in effect at the given block number.
+  // Reverts if the fee config history does not reach back to the block.
+  function getFeeConfigAt(uint256 blockNumber)
+    external
+    view
+    returns (
+      uint256 gasLimit,
+      uint256 targetBlockRate,
+      uint256 minBaseFee,
+      uint256 targetGas,
+      uint256 baseFeeChangeDenominator,
+      uint256 minBlockGasCost,
+      uint256 maxBlockGasCost,
+      uint256 blockGasCostStep
+    );
+
+  // Propose a fee config to be voted on by locking native tokens.
+  // Reverts unless the precompile is configured with a vote config.
+  function proposeFeeConfig(
+    uint256 gasLimit,
+    uint256 targetBlockRate,
+    uint256 minBaseFee,
+    uint256 targetGas,
+    uint256 baseFeeChangeDenominator,
+    uint256 minBlockGasCost,
+    uint256 maxBlockGasCost,
+    uint256 blockGasCostStep
+  ) external returns (uint256 proposalID);
+
+  // Vote on an open proposal by locking the sent value until voting closes.
+  function voteFeeConfig(uint256 proposalID, bool support) external payable;
+
+  // Set the fee config of a proposal once voting has closed, if it reached the quorum
+  // and more value was locked in favor than against.
+  function executeFeeConfig(uint256 proposalID) external;
+
+  // Return the value locked by the caller on a proposal once voting has closed.
+  function withdrawFeeConfigVote(uint256 proposalID) external;
+
+  // Get a fee config proposal and the tally of its votes.
+  function getFeeConfigProposal(uint256 proposalID)
+    external
+    view
+    returns (
+      uint256 gasLimit,
+      uint256 targetBlockRate,
+      uint256 minBaseFee,
+      uint256 targetGas,
+      uint256 baseFeeChangeDenominator,
+      uint256 minBlockGasCost,
+      uint256 maxBlockGasCost,
+      uint256 blockGasCostStep,
+      uint256 deadline,
+      uint256 yesWeight,
+      uint256 noWeight,
+      bool executed
+    );
 }
@@ -0,0 +1,27 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+interface IGasSponsor is IAllowList {
+  // Emitted when [sender] adds [amount] to the sponsor balance
+  event Deposit(address indexed sender, uint256 amount);
+  // Emitted when the gas of transactions sent to [contractAddr] becomes sponsored or unsponsored
+  event SponsoredSet(address indexed contractAddr, bool sponsored);
+  // Emitted when [amount] of the sponsor balance is withdrawn to [to]
+  event Withdrawal(address indexed to, uint256 amount);
+
+  // Add the value sent to the sponsor balance
+  function deposit() external payable;
+
+  // Withdraw [amount] of the sponsor balance to [to]
+  function withdraw(address to, uint256 amount) external;
+
+  // Set whether the gas of transactions sent to [contractAddr] is sponsored
+  function setSponsored(address contractAddr, bool sponsored) external;
+
+  // Returns true if the gas of transactions sent to [contractAddr] is sponsored
+  function isSponsored(address contractAddr) external view returns (bool sponsored);
+
+  // Returns the balance available to sponsor gas
+  function sponsorBalance() external view returns (uint256 balance);
+}
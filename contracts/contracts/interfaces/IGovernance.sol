@@ -0,0 +1,35 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+interface IGovernance is IAllowList {
+  // createProposal creates a proposal for [payloadHash], open for votes for the configured voting period.
+  // The transaction must include an empty governance predicate at index 0 in its access list, which
+  // snapshots the validator set the votes on the proposal are weighed against.
+  // Only callable by enabled addresses.
+  function createProposal(bytes32 payloadHash) external returns (uint256 proposalID);
+
+  // castVote tallies the validator vote included at [index] of the governance predicates in the
+  // access list of the transaction, weighted by the stake of the validator in the snapshot of the proposal.
+  // Reverts if the vote is invalid or not signed over the P-Chain height of the snapshot, the proposal
+  // is closed or the validator already voted.
+  function castVote(uint32 index) external;
+
+  // getProposal returns the payload hash, deadline and vote tally of [proposalID], along with the
+  // total weight and P-Chain height of its validator set snapshot.
+  function getProposal(uint256 proposalID)
+    external
+    view
+    returns (
+      bytes32 payloadHash,
+      uint64 deadline,
+      uint64 yesWeight,
+      uint64 noWeight,
+      uint64 totalWeight,
+      uint64 pChainHeight
+    );
+
+  // proposalPassed returns true if the validators in favor of [proposalID] hold at least
+  // the quorum percentage of the weight of its validator set snapshot.
+  function proposalPassed(uint256 proposalID) external view returns (bool passed);
+}
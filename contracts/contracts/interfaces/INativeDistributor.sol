@@ -0,0 +1,19 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+interface INativeDistributor is IAllowList {
+  // Emitted when [sender] adds [amount] to the balance of the distributor
+  event Deposit(address indexed sender, uint256 amount);
+  // Emitted when [sender] distributes [total] to [recipients] addresses
+  event Distributed(address indexed sender, uint256 recipients, uint256 total);
+
+  // Add the value sent to the balance of the distributor
+  function deposit() external payable;
+
+  // Send amounts[i] of the balance of the distributor to recipients[i] for each i
+  function distribute(address[] calldata recipients, uint256[] calldata amounts) external;
+
+  // Returns the maximum total amount distributed by a single call of distribute
+  function maxDistributionPerCall() external view returns (uint256 amount);
+}
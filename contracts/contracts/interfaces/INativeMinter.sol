@@ -3,6 +3,9 @@ pragma solidity ^0.8.0;
 import "./IAllowList.sol";
 
 interface INativeMinter is IAllowList {
+  // Emitted when [minter] mints [amount] number of native coins to [recipient]
+  event NativeCoinMinted(address indexed recipient, uint256 amount, address indexed minter);
+
   // Mint [amount] number of native coins and send to [addr]
   function mintNativeCoin(address addr, uint256 amount) external;
 }
@@ -17,4 +17,13 @@ interface IRewardManager is IAllowList {
 
   // areFeeRecipientsAllowed returns true if fee recipients are allowed
   function areFeeRecipientsAllowed() external view returns (bool isAllowed);
+
+  // The fee burn functions below are only available from the DUpgrade.
+
+  // setFeeBurnPercentage sets the percentage of fees that is burned, the remainder
+  // going to the reward address or fee recipient
+  function setFeeBurnPercentage(uint256 percentage) external;
+
+  // feeBurnPercentage returns the percentage of fees that is burned
+  function feeBurnPercentage() external view returns (uint256 percentage);
 }
@@ -0,0 +1,19 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+interface ITokenMulticall {
+  // Returns the balance of holders[i] of tokens[i] for each i, where the zero address is the
+  // native token. success[i] is false if the balanceOf call of tokens[i] failed.
+  function balances(
+    address[] calldata tokens,
+    address[] calldata holders
+  ) external view returns (bool[] memory success, uint256[] memory amounts);
+
+  // Returns the amount of tokens[i] spenders[i] can transfer from owners[i] for each i.
+  // success[i] is false if the allowance call of tokens[i] failed.
+  function allowances(
+    address[] calldata tokens,
+    address[] calldata owners,
+    address[] calldata spenders
+  ) external view returns (bool[] memory success, uint256[] memory amounts);
+}
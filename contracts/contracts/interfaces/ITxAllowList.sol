@@ -0,0 +1,8 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+interface ITxAllowList is IAllowList {
+  // Set [addr] to be enabled on the precompile contract until the block timestamp reaches [timestamp].
+  function setEnabledUntil(address addr, uint256 timestamp) external;
+}
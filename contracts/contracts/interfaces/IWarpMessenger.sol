@@ -61,4 +61,9 @@ interface IWarpMessenger {
     // This blockchainID is the hash of the transaction that created this blockchain on the P-Chain
     // and is not related to the Ethereum ChainID.
     function getBlockchainID() external view returns (bytes32 blockchainID);
+
+    // getPChainHeight returns the ProposerVM P-Chain height that the warp messages of the current
+    // transaction were verified against and true.
+    // If the transaction does not include a warp message or the height is not recorded, returns false.
+    function getPChainHeight() external view returns (uint64 pChainHeight, bool valid);
 }
@@ -0,0 +1,43 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// IWrappedNativeToken is the WETH9-compatible wrapped native token precompile.
+interface IWrappedNativeToken {
+  event Approval(address indexed src, address indexed guy, uint256 wad);
+  event Transfer(address indexed src, address indexed dst, uint256 wad);
+  event Deposit(address indexed dst, uint256 wad);
+  event Withdrawal(address indexed src, uint256 wad);
+
+  // name returns the name of the token
+  function name() external view returns (string memory);
+
+  // symbol returns the symbol of the token
+  function symbol() external view returns (string memory);
+
+  // decimals returns 18, the decimals of the native token
+  function decimals() external view returns (uint8);
+
+  // totalSupply returns the native balance held by the precompile
+  function totalSupply() external view returns (uint256);
+
+  // balanceOf returns the wrapped balance of [account]
+  function balanceOf(address account) external view returns (uint256);
+
+  // allowance returns the amount [spender] can transfer from [owner]
+  function allowance(address owner, address spender) external view returns (uint256);
+
+  // deposit wraps the native tokens sent with the call. Sending native tokens without calldata also deposits them.
+  function deposit() external payable;
+
+  // withdraw unwraps [wad] tokens and returns the native tokens to the caller
+  function withdraw(uint256 wad) external;
+
+  // approve allows [guy] to transfer [wad] tokens of the caller
+  function approve(address guy, uint256 wad) external returns (bool);
+
+  // transfer moves [wad] tokens from the caller to [dst]
+  function transfer(address dst, uint256 wad) external returns (bool);
+
+  // transferFrom moves [wad] tokens from [src] to [dst], using the allowance of the caller unless it is [src]
+  function transferFrom(address src, address dst, uint256 wad) external returns (bool);
+}
@@ -82,6 +82,10 @@ var (
 	blockWriteTimer             = metrics.NewRegisteredCounter("chain/block/writes", nil)
 
 	acceptorQueueGauge            = metrics.NewRegisteredGauge("chain/acceptor/queue/size", nil)
+	acceptorQueueFullCounter      = metrics.NewRegisteredCounter("chain/acceptor/queue/full", nil)
+	acceptorQueueBlockedTimer     = metrics.NewRegisteredCounter("chain/acceptor/queue/blocked", nil)
+	acceptorLogsWaitTimer         = metrics.NewRegisteredCounter("chain/acceptor/logs/wait", nil)
+	acceptorFeedsTimer            = metrics.NewRegisteredCounter("chain/acceptor/feeds", nil)
 	acceptorWorkTimer             = metrics.NewRegisteredCounter("chain/acceptor/work", nil)
 	acceptorWorkCount             = metrics.NewRegisteredCounter("chain/acceptor/work/count", nil)
 	lastAcceptedBlockBaseFeeGauge = metrics.NewRegisteredGauge("chain/block/fee/basefee", nil)
@@ -90,6 +94,11 @@ var (
 	acceptedBlockGasUsedCounter   = metrics.NewRegisteredCounter("chain/block/gas/used/accepted", nil)
 	badBlockCounter               = metrics.NewRegisteredCounter("chain/block/bad/count", nil)
 
+	blockReorgCounter     = metrics.NewRegisteredCounter("chain/reorg/executes", nil)
+	blockReorgAddCounter  = metrics.NewRegisteredCounter("chain/reorg/add", nil)
+	blockReorgDropCounter = metrics.NewRegisteredCounter("chain/reorg/drop", nil)
+	blockReorgDepthGauge  = metrics.NewRegisteredGauge("chain/reorg/depth", nil)
+
 	txUnindexTimer      = metrics.NewRegisteredCounter("chain/txs/unindex", nil)
 	acceptedTxsCounter  = metrics.NewRegisteredCounter("chain/txs/accepted", nil)
 	processedTxsCounter = metrics.NewRegisteredCounter("chain/txs/processed", nil)
@@ -112,6 +121,10 @@ const (
 	coinbaseConfigCacheLimit = 256
 	badBlockLimit            = 10
 
+	// acceptorQueueBlockedWarnThreshold is how long acceptance may block on a full
+	// acceptor queue before a warning is logged.
+	acceptorQueueBlockedWarnThreshold = time.Second
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	//
 	// Changelog:
@@ -171,11 +184,13 @@ type CacheConfig struct {
 	CommitInterval                  uint64        // Commit the trie every [CommitInterval] blocks.
 	Pruning                         bool          // Whether to disable trie write caching and GC altogether (archive node)
 	AcceptorQueueLimit              int           // Blocks to queue before blocking during acceptance
+	AcceptorIndexingParallelism     int           // Queued blocks whose logs are collected concurrently ahead of the acceptor (0 collects them in the acceptor)
 	PopulateMissingTries            *uint64       // If non-nil, sets the starting height for re-generating historical tries.
 	PopulateMissingTriesParallelism int           // Is the number of readers to use when trying to populate missing tries.
 	AllowMissingTries               bool          // Whether to allow an archive node to run with pruning enabled
 	SnapshotDelayInit               bool          // Whether to initialize snapshots on startup or wait for external call
 	SnapshotLimit                   int           // Memory allowance (MB) to use for caching snapshot entries in memory
+	SnapshotCacheJournal            string        // Disk journal for saving the snapshot cache on shutdown
 	SnapshotVerify                  bool          // Verify generated snapshots
 	Preimages                       bool          // Whether to store preimage of trie key to the disk
 	AcceptedCacheSize               int           // Depth of accepted headers cache and accepted logs cache at the accepted tip
@@ -183,6 +198,8 @@ type CacheConfig struct {
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
 	SnapshotWait    bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
+
+	ReceiptCompression rawdb.ReceiptCompression // Compression applied to newly stored receipts
 }
 
 var DefaultCacheConfig = &CacheConfig{
@@ -225,6 +242,7 @@ type BlockChain struct {
 	chainFeed         event.Feed
 	chainSideFeed     event.Feed
 	chainHeadFeed     event.Feed
+	chainReorgFeed    event.Feed
 	chainAcceptedFeed event.Feed
 	logsFeed          event.Feed
 	logsAcceptedFeed  event.Feed
@@ -239,6 +257,8 @@ type BlockChain struct {
 
 	currentBlock atomic.Pointer[types.Header] // Current head of the block chain
 
+	txUnindexing atomic.Bool // True while transaction lookup indices are being deleted
+
 	bodyCache           *lru.Cache[common.Hash, *types.Body]                // Cache for the most recent block bodies
 	receiptsCache       *lru.Cache[common.Hash, []*types.Receipt]           // Cache for the most recent receipts per block
 	blockCache          *lru.Cache[common.Hash, *types.Block]               // Cache for the most recent entire blocks
@@ -263,7 +283,11 @@ type BlockChain struct {
 	// different than [chainAcceptedFeed], which is sent an event after an accepted
 	// block is processed (after each loop of the accepted worker). If there is a
 	// clean shutdown, all items inserted into the [acceptorQueue] will be processed.
-	acceptorQueue chan *types.Block
+	acceptorQueue chan *acceptorTask
+
+	// [acceptorIndexingSem] limits the number of queued blocks whose logs are
+	// collected concurrently to [AcceptorIndexingParallelism].
+	acceptorIndexingSem chan struct{}
 
 	// [acceptorClosingLock], and [acceptorClosed] are used
 	// to synchronize the closing of the [acceptorQueue] channel.
@@ -350,7 +374,8 @@ func NewBlockChain(
 		engine:              engine,
 		vmConfig:            vmConfig,
 		senderCacher:        NewTxSenderCacher(runtime.NumCPU()),
-		acceptorQueue:       make(chan *types.Block, cacheConfig.AcceptorQueueLimit),
+		acceptorQueue:       make(chan *acceptorTask, cacheConfig.AcceptorQueueLimit),
+		acceptorIndexingSem: make(chan struct{}, cacheConfig.AcceptorIndexingParallelism),
 		quit:                make(chan struct{}),
 		acceptedLogsCache:   NewFIFOCache[common.Hash, [][]*types.Log](cacheConfig.AcceptedCacheSize),
 	}
@@ -430,6 +455,9 @@ func NewBlockChain(
 	if bc.cacheConfig.TxLookupLimit != 0 {
 		bc.wg.Add(1)
 		go bc.dispatchTxUnindexer()
+	} else if tail := rawdb.ReadTxIndexTail(bc.db); tail != nil && *tail > 0 {
+		// Indices deleted while a limit was configured are not restored.
+		log.Warn("Transaction lookup limit is disabled, but transactions are only indexed from tail", "tail", *tail)
 	}
 	return bc, nil
 }
@@ -438,8 +466,10 @@ func NewBlockChain(
 func (bc *BlockChain) unindexBlocks(tail uint64, head uint64, done chan struct{}) {
 	start := time.Now()
 	txLookupLimit := bc.cacheConfig.TxLookupLimit
+	bc.txUnindexing.Store(true)
 	defer func() {
 		txUnindexTimer.Inc(time.Since(start).Milliseconds())
+		bc.txUnindexing.Store(false)
 		close(done)
 	}()
 
@@ -505,9 +535,14 @@ func (bc *BlockChain) dispatchTxUnindexer() {
 // This includes the following:
 // - transaction lookup indices
 // - updating the acceptor tip index
-func (bc *BlockChain) writeBlockAcceptedIndices(b *types.Block) error {
+// - the acceptance time of the block, if [acceptedAt] is known (non-zero)
+func (bc *BlockChain) writeBlockAcceptedIndices(b *types.Block, acceptedAt uint64) error {
 	batch := bc.db.NewBatch()
 	rawdb.WriteTxLookupEntriesByBlock(batch, b)
+	rawdb.WriteBlockTimestamp(batch, b.Time(), b.NumberU64(), b.Hash())
+	if acceptedAt != 0 {
+		rawdb.WriteBlockAcceptedAt(batch, b.Hash(), acceptedAt)
+	}
 	if err := rawdb.WriteAcceptorTip(batch, b.Hash()); err != nil {
 		return fmt.Errorf("%w: failed to write acceptor tip key", err)
 	}
@@ -582,13 +617,40 @@ func (bc *BlockChain) warmAcceptedCaches() {
 	log.Info("Warmed accepted caches", "start", startIndex, "end", lastAccepted, "t", time.Since(startTime))
 }
 
+// acceptorTask is a block queued for the Acceptor. If [logs] is non-nil, the logs
+// of [block] are being collected concurrently and are sent on [logs] once ready.
+type acceptorTask struct {
+	block *types.Block
+	logs  chan [][]*types.Log
+	// acceptedAt is the unix time at which [block] was accepted by consensus.
+	acceptedAt uint64
+}
+
+// newAcceptorTask returns the acceptorTask for [b], starting to collect its logs
+// concurrently if [AcceptorIndexingParallelism] is non-zero.
+func (bc *BlockChain) newAcceptorTask(b *types.Block) *acceptorTask {
+	task := &acceptorTask{block: b, acceptedAt: uint64(time.Now().Unix())}
+	if bc.cacheConfig.AcceptorIndexingParallelism <= 0 {
+		return task
+	}
+	task.logs = make(chan [][]*types.Log, 1)
+	go func() {
+		bc.acceptorIndexingSem <- struct{}{}
+		defer func() { <-bc.acceptorIndexingSem }()
+
+		task.logs <- bc.collectUnflattenedLogs(b, false)
+	}()
+	return task
+}
+
 // startAcceptor starts processing items on the [acceptorQueue]. If a [nil]
 // object is placed on the [acceptorQueue], the [startAcceptor] will exit.
 func (bc *BlockChain) startAcceptor() {
-	log.Info("Starting Acceptor", "queue length", bc.cacheConfig.AcceptorQueueLimit)
+	log.Info("Starting Acceptor", "queue length", bc.cacheConfig.AcceptorQueueLimit, "indexing parallelism", bc.cacheConfig.AcceptorIndexingParallelism)
 
-	for next := range bc.acceptorQueue {
+	for task := range bc.acceptorQueue {
 		start := time.Now()
+		next := task.block
 		acceptorQueueGauge.Dec(1)
 
 		if err := bc.flattenSnapshot(func() error {
@@ -598,16 +660,25 @@ func (bc *BlockChain) startAcceptor() {
 		}
 
 		// Update last processed and transaction lookup index
-		if err := bc.writeBlockAcceptedIndices(next); err != nil {
+		if err := bc.writeBlockAcceptedIndices(next, task.acceptedAt); err != nil {
 			log.Crit("failed to write accepted block effects", "err", err)
 		}
 
 		// Ensure [hc.acceptedNumberCache] and [acceptedLogsCache] have latest content
 		bc.hc.acceptedNumberCache.Put(next.NumberU64(), next.Header())
-		logs := bc.collectUnflattenedLogs(next, false)
+		var logs [][]*types.Log
+		if task.logs != nil {
+			waitStart := time.Now()
+			logs = <-task.logs
+			acceptorLogsWaitTimer.Inc(time.Since(waitStart).Milliseconds())
+		} else {
+			logs = bc.collectUnflattenedLogs(next, false)
+		}
 		bc.acceptedLogsCache.Put(next.Hash(), logs)
 
-		// Update accepted feeds
+		// Update accepted feeds. Sending blocks until every subscriber has
+		// received the event, so slow subscribers delay the acceptor.
+		feedsStart := time.Now()
 		flattenedLogs := types.FlattenLogs(logs)
 		bc.chainAcceptedFeed.Send(ChainEvent{Block: next, Hash: next.Hash(), Logs: flattenedLogs})
 		if len(flattenedLogs) > 0 {
@@ -616,6 +687,7 @@ func (bc *BlockChain) startAcceptor() {
 		if len(next.Transactions()) != 0 {
 			bc.txAcceptedFeed.Send(NewTxsEvent{next.Transactions()})
 		}
+		acceptorFeedsTimer.Inc(time.Since(feedsStart).Milliseconds())
 
 		bc.acceptorTipLock.Lock()
 		bc.acceptorTip = next
@@ -644,7 +716,20 @@ func (bc *BlockChain) addAcceptorQueue(b *types.Block) {
 
 	acceptorQueueGauge.Inc(1)
 	bc.acceptorWg.Add(1)
-	bc.acceptorQueue <- b
+	task := bc.newAcceptorTask(b)
+	select {
+	case bc.acceptorQueue <- task:
+	default:
+		// The queue is full, so acceptance is blocked until the Acceptor catches up.
+		start := time.Now()
+		acceptorQueueFullCounter.Inc(1)
+		bc.acceptorQueue <- task
+		blocked := time.Since(start)
+		acceptorQueueBlockedTimer.Inc(blocked.Milliseconds())
+		if blocked > acceptorQueueBlockedWarnThreshold {
+			log.Warn("Acceptance blocked on a full acceptor queue", "block", b.Hash(), "number", b.NumberU64(), "blocked", blocked, "limit", bc.cacheConfig.AcceptorQueueLimit)
+		}
+	}
 }
 
 // DrainAcceptorQueue blocks until all items in [acceptorQueue] have been
@@ -982,10 +1067,46 @@ func (bc *BlockChain) Stop() {
 	if err := bc.stateCache.TrieDB().CommitPreimages(); err != nil {
 		log.Error("Failed to commit trie preimages", "err", err)
 	}
+	bc.saveCaches()
 
 	log.Info("Blockchain stopped")
 }
 
+// saveCaches writes the clean trie cache and the snapshot cache to their
+// journals, so that a restarted node does not start with cold caches. Dirty trie
+// nodes do not need saving, since the state manager commits the last accepted
+// trie on shutdown and the snapshot diff layers are flattened on accept.
+func (bc *BlockChain) saveCaches() {
+	if len(bc.cacheConfig.TrieCleanJournal) > 0 {
+		if err := bc.triedb.SaveCache(bc.cacheConfig.TrieCleanJournal); err != nil {
+			log.Error("Failed to save trie clean cache", "err", err)
+		}
+	}
+	if bc.snaps != nil && len(bc.cacheConfig.SnapshotCacheJournal) > 0 {
+		if err := bc.snaps.SaveCache(bc.cacheConfig.SnapshotCacheJournal); err != nil {
+			log.Error("Failed to save snapshot cache", "err", err)
+		}
+	}
+}
+
+// ShrinkCaches frees memory under memory pressure by purging the caches of recent
+// blocks, receipts and logs, and flushing dirty trie nodes to disk until they are
+// below half of [TrieDirtyLimit]. The clean trie and snapshot caches are not
+// purged, since their memory is reused rather than returned to the OS.
+func (bc *BlockChain) ShrinkCaches() error {
+	bc.bodyCache.Purge()
+	bc.receiptsCache.Purge()
+	bc.blockCache.Purge()
+	bc.txLookupCache.Purge()
+	bc.acceptedLogsCache.Purge()
+
+	limit := common.StorageSize(bc.cacheConfig.TrieDirtyLimit) * 1024 * 1024 / 2
+	if err := bc.triedb.Cap(limit); err != nil {
+		return fmt.Errorf("failed to cap dirty trie nodes: %w", err)
+	}
+	return nil
+}
+
 // SetPreference attempts to update the head block to be the provided block and
 // emits a ChainHeadEvent if successful. This function will handle all reorg
 // side effects, if necessary.
@@ -1117,13 +1238,14 @@ func TotalFees(block *types.Block, receipts []*types.Receipt) (*big.Int, error)
 	return feesWei, nil
 }
 
-// TotalFees computes total consumed fees in ether. Block transactions and receipts have to have the same order.
-func TotalFeesFloat(block *types.Block, receipts []*types.Receipt) (*big.Float, error) {
+// TotalFeesFloat computes total consumed fees in native tokens, as denominated by [config].
+// Block transactions and receipts have to have the same order.
+func TotalFeesFloat(config *params.ChainConfig, block *types.Block, receipts []*types.Receipt) (*big.Float, error) {
 	total, err := TotalFees(block, receipts)
 	if err != nil {
 		return nil, err
 	}
-	return new(big.Float).Quo(new(big.Float).SetInt(total), new(big.Float).SetInt(big.NewInt(params.Ether))), nil
+	return config.ToNativeTokens(total), nil
 }
 
 func (bc *BlockChain) Reject(block *types.Block) error {
@@ -1211,7 +1333,7 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// should be written atomically. BlockBatch is used for containing all components.
 	blockBatch := bc.db.NewBatch()
 	rawdb.WriteBlock(blockBatch, block)
-	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	rawdb.WriteCompressedReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts, bc.cacheConfig.ReceiptCompression)
 	rawdb.WritePreimages(blockBatch, state.Preimages())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
@@ -1618,6 +1740,20 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 	if len(rebirthLogs) > 0 {
 		bc.logsFeed.Send(rebirthLogs)
 	}
+
+	if len(oldChain) > 0 {
+		blockReorgCounter.Inc(1)
+		blockReorgAddCounter.Inc(int64(len(newChain)))
+		blockReorgDropCounter.Inc(int64(len(oldChain)))
+		blockReorgDepthGauge.Update(int64(len(oldChain)))
+		bc.chainReorgFeed.Send(ChainReorgEvent{
+			Common:  commonBlock.Header(),
+			OldHead: oldHead,
+			NewHead: newHead.Header(),
+			Dropped: len(oldChain),
+			Added:   len(newChain),
+		})
+	}
 	return nil
 }
 
@@ -1792,6 +1928,8 @@ func (bc *BlockChain) initSnapshot(b *types.Header) {
 		NoBuild:    noBuild,
 		AsyncBuild: asyncBuild,
 		SkipVerify: !bc.cacheConfig.SnapshotVerify,
+
+		CacheJournal: bc.cacheConfig.SnapshotCacheJournal,
 	}
 	var err error
 	bc.snaps, err = snapshot.New(snapconfig, bc.db, bc.triedb, b.Hash(), b.Root)
@@ -1922,7 +2060,8 @@ func (bc *BlockChain) reprocessState(current *types.Block, reexec uint64) error
 
 		// Write any unsaved indices to disk
 		if writeIndices {
-			if err := bc.writeBlockAcceptedIndices(current); err != nil {
+			// The acceptance time of reprocessed blocks is not known.
+			if err := bc.writeBlockAcceptedIndices(current, 0); err != nil {
 				return fmt.Errorf("%w: failed to process accepted block indices", err)
 			}
 		}
@@ -81,6 +81,30 @@ func TestArchiveBlockChain(t *testing.T) {
 	}
 }
 
+func TestArchiveBlockChainIndexingParallelism(t *testing.T) {
+	create := func(db ethdb.Database, gspec *Genesis, lastAcceptedHash common.Hash) (*BlockChain, error) {
+		return createBlockChain(
+			db,
+			&CacheConfig{
+				TrieCleanLimit:              256,
+				TrieDirtyLimit:              256,
+				TrieDirtyCommitTarget:       20,
+				Pruning:                     false, // Archive mode
+				SnapshotLimit:               256,
+				AcceptorQueueLimit:          64,
+				AcceptorIndexingParallelism: 4,
+			},
+			gspec,
+			lastAcceptedHash,
+		)
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.testFunc(t, create)
+		})
+	}
+}
+
 // awaitWatcherEventsSubside waits for at least one event on [watcher] and then waits
 // for at least [subsideTimeout] before returning
 func awaitWatcherEventsSubside(watcher *fsnotify.Watcher, subsideTimeout time.Duration) {
@@ -853,6 +877,61 @@ func TestCanonicalHashMarker(t *testing.T) {
 	}
 }
 
+func TestChainReorgEvent(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   GenesisAlloc{},
+			BaseFee: big.NewInt(params.TestInitialBaseFee),
+		}
+		engine = dummy.NewCoinbaseFaker()
+	)
+	_, forkA, _, err := GenerateChainWithGenesis(gspec, engine, 3, 10, func(i int, gen *BlockGen) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, forkB, _, err := GenerateChainWithGenesis(gspec, engine, 2, 10, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(common.Address{1})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(forkA); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	if n, err := chain.InsertChain(forkB); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+
+	reorgs := make(chan ChainReorgEvent, 1)
+	sub := chain.SubscribeChainReorgEvent(reorgs)
+	defer sub.Unsubscribe()
+
+	if err := chain.SetPreference(forkB[len(forkB)-1]); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-reorgs:
+		if ev.Dropped != len(forkA) || ev.Added != len(forkB) {
+			t.Fatalf("unexpected reorg size: dropped %d, added %d", ev.Dropped, ev.Added)
+		}
+		if ev.Common.Number.Uint64() != 0 {
+			t.Fatalf("unexpected common ancestor at height %d", ev.Common.Number)
+		}
+		if ev.OldHead.Hash() != forkA[len(forkA)-1].Hash() || ev.NewHead.Hash() != forkB[len(forkB)-1].Hash() {
+			t.Fatal("unexpected reorg heads")
+		}
+	default:
+		t.Fatal("expected a reorg event")
+	}
+}
+
 func TestTxLookupBlockChain(t *testing.T) {
 	cacheConf := &CacheConfig{
 		TrieCleanLimit:        256,
@@ -55,3 +55,14 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// ChainReorgEvent is posted when the preferred chain is reorganized, dropping
+// [Dropped] blocks above [Common] from the old head and adding [Added] blocks
+// up to the new head.
+type ChainReorgEvent struct {
+	Common  *types.Header
+	OldHead *types.Header
+	NewHead *types.Header
+	Dropped int
+	Added   int
+}
@@ -17,6 +17,8 @@ var (
 type FIFOCache[K comparable, V any] interface {
 	Put(K, V)
 	Get(K) (V, bool)
+	// Purge removes all elements from the cache.
+	Purge()
 }
 
 // NewFIFOCache creates a new First-In-First-Out cache of size [limit].
@@ -29,7 +31,8 @@ func NewFIFOCache[K comparable, V any](limit int) FIFOCache[K, V] {
 	}
 
 	c := &BufferFIFOCache[K, V]{
-		m: make(map[K]V, limit),
+		limit: limit,
+		m:     make(map[K]V, limit),
 	}
 	c.buffer = NewBoundedBuffer(limit, c.remove)
 	return c
@@ -38,6 +41,7 @@ func NewFIFOCache[K comparable, V any](limit int) FIFOCache[K, V] {
 type BufferFIFOCache[K comparable, V any] struct {
 	l sync.RWMutex
 
+	limit  int
 	buffer *BoundedBuffer[K]
 	m      map[K]V
 }
@@ -58,6 +62,14 @@ func (f *BufferFIFOCache[K, V]) Get(key K) (V, bool) {
 	return v, ok
 }
 
+func (f *BufferFIFOCache[K, V]) Purge() {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	f.buffer = NewBoundedBuffer(f.limit, f.remove)
+	f.m = make(map[K]V, f.limit)
+}
+
 // remove is used as the callback in [BoundedBuffer]. It is assumed that the
 // [WriteLock] is held when this is accessed.
 func (f *BufferFIFOCache[K, V]) remove(key K) {
@@ -70,3 +82,4 @@ func (f *NoOpFIFOCache[K, V]) Put(_ K, _ V) {}
 func (f *NoOpFIFOCache[K, V]) Get(_ K) (V, bool) {
 	return *new(V), false
 }
+func (f *NoOpFIFOCache[K, V]) Purge() {}
@@ -0,0 +1,88 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/core/vm"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/gassponsor"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGasSponsorSenderQuota checks that an unfunded sender cannot have more gas
+// sponsored than its quota per window, nor make the sponsor pay a tip.
+func TestGasSponsorSenderQuota(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		sender   = common.Address{'s'}
+		target   = common.Address{'t'}
+		baseFee  = big.NewInt(params.TestInitialBaseFee)
+		funding  = big.NewInt(params.Ether)
+		window   = uint64(60)
+		cpConfig = *params.TestChainConfig
+		config   = &cpConfig
+	)
+	config.GenesisPrecompiles = params.Precompiles{
+		gassponsor.ConfigKey: gassponsor.NewConfig(utils.NewUint64(0), nil, nil, nil, nil, 2*params.TxGas, window),
+	}
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(err)
+	gassponsor.SetSponsored(statedb, target, true)
+	gassponsor.SetSenderQuota(statedb, 2*params.TxGas, window)
+	statedb.AddBalance(gassponsor.ContractAddress, funding)
+
+	apply := func(timestamp uint64, tip *big.Int) error {
+		gasPrice := new(big.Int).Add(baseFee, tip)
+		msg := &Message{
+			From:      sender,
+			To:        &target,
+			Nonce:     statedb.GetNonce(sender),
+			Value:     new(big.Int),
+			GasLimit:  params.TxGas,
+			GasPrice:  gasPrice,
+			GasFeeCap: gasPrice,
+			GasTipCap: tip,
+		}
+		blockContext := vm.BlockContext{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			BlockNumber: big.NewInt(1),
+			Time:        timestamp,
+			Difficulty:  new(big.Int),
+			BaseFee:     baseFee,
+			GasLimit:    math.MaxUint64,
+		}
+		evm := vm.NewEVM(blockContext, NewEVMTxContext(msg), statedb, config, vm.Config{})
+		_, err := ApplyMessage(evm, msg, new(GasPool).AddGas(math.MaxUint64))
+		return err
+	}
+
+	// The sponsor does not pay tips.
+	require.ErrorIs(apply(window, common.Big1), ErrInsufficientFunds)
+
+	// The quota covers two transfers per window.
+	require.NoError(apply(window, common.Big0))
+	require.NoError(apply(window+1, common.Big0))
+	require.ErrorIs(apply(2*window-1, common.Big0), ErrInsufficientFunds)
+	require.Zero(gassponsor.AvailableSponsoredGas(statedb, sender, 2*window-1))
+
+	// The quota is restored in the next window.
+	require.NoError(apply(2*window, common.Big0))
+	require.Equal(params.TxGas, gassponsor.AvailableSponsoredGas(statedb, sender, 2*window))
+
+	// The sender never paid for gas.
+	require.Zero(statedb.GetBalance(sender).Sign())
+	spent := new(big.Int).Mul(big.NewInt(3*int64(params.TxGas)), baseFee)
+	require.Equal(new(big.Int).Sub(funding, spent), gassponsor.GetSponsorBalance(statedb))
+}
@@ -187,6 +187,9 @@ func SetupGenesisBlock(
 	// Just commit the new block if there is no stored genesis block.
 	stored := rawdb.ReadCanonicalHash(db, 0)
 	if (stored == common.Hash{}) {
+		if err := genesis.verifyPrecompileAddressCode(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
 		log.Info("Writing genesis to database")
 		block, err := genesis.Commit(db, triedb)
 		if err != nil {
@@ -194,6 +197,9 @@ func SetupGenesisBlock(
 		}
 		return genesis.Config, block.Hash(), nil
 	}
+	if err := genesis.verifyPrecompileAddressCode(); err != nil {
+		log.Warn("Genesis of existing chain has code in the precompile address space", "err", err)
+	}
 	// We have the genesis block in database but the corresponding state is missing.
 	header := rawdb.ReadHeader(db, stored, 0)
 	if header.Root != types.EmptyRootHash && !rawdb.HasLegacyTrieNode(db, header.Root) {
@@ -399,6 +405,22 @@ func (g *Genesis) Verify() error {
 	return nil
 }
 
+// verifyPrecompileAddressCode returns an error if the genesis allocates code in the
+// precompile address space, where it would be shadowed by the precompile registered
+// at its address, now or in the future. This is only enforced for new chains, since
+// the genesis of an existing chain cannot be changed.
+func (g *Genesis) verifyPrecompileAddressCode() error {
+	for addr, account := range g.Alloc {
+		if len(account.Code) == 0 {
+			continue
+		}
+		if addressRange, ok := params.GetPrecompileAddressRange(addr); ok {
+			return fmt.Errorf("genesis account %s has code in the %s precompile address range", addr, addressRange.Name)
+		}
+	}
+	return nil
+}
+
 // GenesisBlockForTesting creates and writes a block in which addr has the given wei balance.
 func GenesisBlockForTesting(db ethdb.Database, addr common.Address, balance *big.Int) *types.Block {
 	g := Genesis{
@@ -328,3 +328,29 @@ func TestGenesisWriteUpgradesRegression(t *testing.T) {
 	_, _, err = SetupGenesisBlock(db, trieDB, genesis, lastAcceptedBlock.Hash(), false)
 	require.NoError(err)
 }
+
+func TestGenesisPrecompileRangeCode(t *testing.T) {
+	require := require.New(t)
+	genesis := &Genesis{
+		Config:   params.TestChainConfig,
+		GasLimit: params.TestChainConfig.FeeConfig.GasLimit.Uint64(),
+		Alloc: GenesisAlloc{
+			common.HexToAddress("0x0300000000000000000000000000000000000001"): {Balance: big.NewInt(1)},
+			common.HexToAddress("0x0300000000000000000000000000000000000002"): {Code: []byte{0x1}, Balance: common.Big0},
+		},
+	}
+	genesisBlock := genesis.ToBlock()
+
+	// A new chain cannot be created with code in the precompile address space.
+	db := rawdb.NewMemoryDatabase()
+	_, _, err := SetupGenesisBlock(db, trie.NewDatabase(db), genesis, genesisBlock.Hash(), false)
+	require.ErrorContains(err, "downstream-fork precompile address range")
+
+	// An existing chain with such a genesis can still be started.
+	db = rawdb.NewMemoryDatabase()
+	trieDB := trie.NewDatabase(db)
+	_, err = genesis.Commit(db, trieDB)
+	require.NoError(err)
+	_, _, err = SetupGenesisBlock(db, trieDB, genesis, genesisBlock.Hash(), false)
+	require.NoError(err)
+}
@@ -4,6 +4,7 @@
 package core
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -48,6 +49,7 @@ func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.Pred
 		return nil, ErrMissingPredicateContext
 	}
 
+	recordPChainHeight := false
 	for address, predicates := range predicateArguments {
 		// Since [address] is only added to [predicateArguments] when there's a valid predicate in the ruleset
 		// there's no need to check if the predicate exists here.
@@ -55,6 +57,12 @@ func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.Pred
 		res := predicate.VerifyPredicate(predicateContext, predicates)
 		log.Debug("predicate verify", "tx", tx.Hash(), "address", address, "res", res)
 		predicateResults[address] = res
+		if recorder, ok := predicate.(precompileconfig.PChainHeightRecorder); ok && recorder.RecordsPChainHeight() {
+			recordPChainHeight = true
+		}
+	}
+	if recordPChainHeight {
+		predicateResults[precompileconfig.PChainHeightResultsKey] = binary.BigEndian.AppendUint64(nil, predicateContext.ProposerVMBlockCtx.PChainHeight)
 	}
 
 	return predicateResults, nil
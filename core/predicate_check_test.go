@@ -16,6 +16,13 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+// pChainHeightRecordingPredicater wraps a Predicater to record the P-Chain height its predicates are verified against.
+type pChainHeightRecordingPredicater struct {
+	precompileconfig.Predicater
+}
+
+func (pChainHeightRecordingPredicater) RecordsPChainHeight() bool { return true }
+
 type predicateCheckTest struct {
 	accessList       types.AccessList
 	gas              uint64
@@ -160,6 +167,32 @@ func TestCheckPredicate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		"predicate recording p-chain height returns height": {
+			gas:              53000,
+			predicateContext: predicateContext,
+			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
+				predicate := precompileconfig.NewMockPredicater(gomock.NewController(t))
+				arg := common.Hash{1}
+				predicate.EXPECT().PredicateGas(arg[:]).Return(uint64(0), nil).Times(2)
+				predicate.EXPECT().VerifyPredicate(gomock.Any(), [][]byte{arg[:]}).Return(predicateResultBytes1)
+				return map[common.Address]precompileconfig.Predicater{
+					addr1: pChainHeightRecordingPredicater{predicate},
+				}
+			},
+			accessList: types.AccessList([]types.AccessTuple{
+				{
+					Address: addr1,
+					StorageKeys: []common.Hash{
+						{1},
+					},
+				},
+			}),
+			expectedRes: map[common.Address][]byte{
+				addr1:                                   predicateResultBytes1,
+				precompileconfig.PChainHeightResultsKey: {0, 0, 0, 0, 0, 0, 0, 10},
+			},
+			expectedErr: nil,
+		},
 		"predicate returns gas err": {
 			gas:              53000,
 			predicateContext: predicateContext,
@@ -346,12 +346,18 @@ func HasReceipts(db ethdb.Reader, hash common.Hash, number uint64) bool {
 	return true
 }
 
-// ReadReceiptsRLP retrieves all the transaction receipts belonging to a block in RLP encoding.
+// ReadReceiptsRLP retrieves all the transaction receipts belonging to a block in RLP encoding,
+// decompressing them if they were stored with compression.
 func ReadReceiptsRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	// Then try to look up the data in leveldb.
 	data, _ := db.Get(blockReceiptsKey(number, hash))
 	if len(data) > 0 {
-		return data
+		decompressed, err := decompressReceipts(data)
+		if err != nil {
+			log.Error("Invalid compressed receipts", "hash", hash, "number", number, "err", err)
+			return nil
+		}
+		return decompressed
 	}
 	return nil // Can't find the data anywhere.
 }
@@ -412,6 +418,12 @@ func ReadReceipts(db ethdb.Reader, hash common.Hash, number uint64, config *para
 
 // WriteReceipts stores all the transaction receipts belonging to a block.
 func WriteReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64, receipts types.Receipts) {
+	WriteCompressedReceipts(db, hash, number, receipts, NoReceiptCompression)
+}
+
+// WriteCompressedReceipts stores all the transaction receipts belonging to a block
+// compressed with [compression].
+func WriteCompressedReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64, receipts types.Receipts, compression ReceiptCompression) {
 	// Convert the receipts into their storage form and serialize them
 	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
 	for i, receipt := range receipts {
@@ -421,6 +433,10 @@ func WriteReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rec
 	if err != nil {
 		log.Crit("Failed to encode block receipts", "err", err)
 	}
+	bytes, err = compressReceipts(bytes, compression)
+	if err != nil {
+		log.Crit("Failed to compress block receipts", "err", err)
+	}
 	// Store the flattened receipt slice
 	if err := db.Put(blockReceiptsKey(number, hash), bytes); err != nil {
 		log.Crit("Failed to store block receipts", "err", err)
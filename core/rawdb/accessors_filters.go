@@ -0,0 +1,37 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rawdb
+
+import (
+	"github.com/ava-labs/subnet-evm/ethdb"
+)
+
+// ReadResumableFilter retrieves the encoded resumable log filter with [id], or nil
+// if there is none.
+func ReadResumableFilter(db ethdb.KeyValueReader, id string) []byte {
+	data, _ := db.Get(resumableFilterKey(id))
+	return data
+}
+
+// WriteResumableFilter stores the encoded resumable log filter with [id].
+func WriteResumableFilter(db ethdb.KeyValueWriter, id string, data []byte) error {
+	return db.Put(resumableFilterKey(id), data)
+}
+
+// ReadResumableFilterIDs returns the ids of all resumable log filters.
+func ReadResumableFilterIDs(db ethdb.Iteratee) []string {
+	it := db.NewIterator(resumableFilterPrefix, nil)
+	defer it.Release()
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, string(it.Key()[len(resumableFilterPrefix):]))
+	}
+	return ids
+}
+
+// DeleteResumableFilter removes the resumable log filter with [id].
+func DeleteResumableFilter(db ethdb.KeyValueWriter, id string) error {
+	return db.Delete(resumableFilterKey(id))
+}
@@ -28,6 +28,7 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/ava-labs/subnet-evm/core/types"
@@ -185,3 +186,44 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// WriteBlockTimestamp stores the timestamp index entry of an accepted block, enabling
+// lookups of accepted blocks by timestamp.
+func WriteBlockTimestamp(db ethdb.KeyValueWriter, time uint64, number uint64, hash common.Hash) {
+	if err := db.Put(blockTimestampKey(time, number), hash.Bytes()); err != nil {
+		log.Crit("Failed to store block timestamp index entry", "err", err)
+	}
+}
+
+// WriteBlockAcceptedAt stores the unix time at which the block with [hash] was accepted.
+func WriteBlockAcceptedAt(db ethdb.KeyValueWriter, hash common.Hash, time uint64) {
+	if err := db.Put(blockAcceptedAtKey(hash), encodeBlockNumber(time)); err != nil {
+		log.Crit("Failed to store block acceptance time", "err", err)
+	}
+}
+
+// ReadBlockAcceptedAt returns the unix time at which the block with [hash] was accepted,
+// or false if it is unknown. Blocks accepted before acceptance times were recorded
+// have no acceptance time.
+func ReadBlockAcceptedAt(db ethdb.KeyValueReader, hash common.Hash) (uint64, bool) {
+	data, _ := db.Get(blockAcceptedAtKey(hash))
+	if len(data) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(data), true
+}
+
+// ReadBlockNumberAtOrAfterTimestamp returns the number of the first indexed block with
+// a timestamp at or after [time], or false if there is no such block. Blocks accepted
+// before the timestamp index was maintained are not indexed.
+func ReadBlockNumberAtOrAfterTimestamp(db ethdb.Iteratee, time uint64) (uint64, bool) {
+	it := db.NewIterator(blockTimestampPrefix, encodeBlockNumber(time))
+	defer it.Release()
+
+	for it.Next() {
+		if key := it.Key(); len(key) == len(blockTimestampPrefix)+16 && len(it.Value()) == common.HashLength {
+			return binary.BigEndian.Uint64(key[len(blockTimestampPrefix)+8:]), true
+		}
+	}
+	return 0, false
+}
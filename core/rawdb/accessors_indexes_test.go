@@ -179,3 +179,44 @@ func TestDeleteBloomBits(t *testing.T) {
 	check(1, 1, genesisHash0, true)
 	check(1, 1, genesisHash1, true)
 }
+
+func TestBlockTimestampStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+	if _, ok := ReadBlockNumberAtOrAfterTimestamp(db, 0); ok {
+		t.Fatal("found block in empty timestamp index")
+	}
+	// Blocks 2 and 3 share a timestamp
+	for number, time := range []uint64{10, 20, 30, 30, 40} {
+		WriteBlockTimestamp(db, time, uint64(number), common.Hash{byte(number)})
+	}
+	tests := []struct {
+		time   uint64
+		number uint64
+		found  bool
+	}{
+		{time: 0, number: 0, found: true},
+		{time: 10, number: 0, found: true},
+		{time: 11, number: 1, found: true},
+		{time: 30, number: 2, found: true},
+		{time: 31, number: 4, found: true},
+		{time: 41, found: false},
+	}
+	for _, test := range tests {
+		number, found := ReadBlockNumberAtOrAfterTimestamp(db, test.time)
+		if found != test.found || number != test.number {
+			t.Fatalf("time %d: have (%d, %t), want (%d, %t)", test.time, number, found, test.number, test.found)
+		}
+	}
+}
+
+func TestBlockAcceptedAtStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+	hash := common.Hash{1}
+	if _, ok := ReadBlockAcceptedAt(db, hash); ok {
+		t.Fatal("found acceptance time of unknown block")
+	}
+	WriteBlockAcceptedAt(db, hash, 1234)
+	if time, ok := ReadBlockAcceptedAt(db, hash); !ok || time != 1234 {
+		t.Fatalf("acceptance time mismatch: have (%d, %t), want (1234, true)", time, ok)
+	}
+}
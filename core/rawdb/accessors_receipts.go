@@ -0,0 +1,165 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ReceiptCompression is the compression applied to the receipts of a block when they
+// are stored in the database.
+//
+// Compressed receipts are prefixed with a byte identifying the compression, which
+// cannot be mistaken for uncompressed receipts as their RLP list prefix is at least
+// 0xc0. This allows receipts written with any compression to be read transparently.
+type ReceiptCompression byte
+
+const (
+	NoReceiptCompression     ReceiptCompression = 0x00 // Stored as RLP without a prefix
+	SnappyReceiptCompression ReceiptCompression = 0x01
+	ZstdReceiptCompression   ReceiptCompression = 0x02
+)
+
+var (
+	errUnknownReceiptCompression = errors.New("unknown receipt compression")
+
+	// zstd encoders and decoders are safe for concurrent use with EncodeAll and DecodeAll.
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// ParseReceiptCompression returns the ReceiptCompression named [name], which is one
+// of "none", "snappy" or "zstd". An empty name is parsed as "none".
+func ParseReceiptCompression(name string) (ReceiptCompression, error) {
+	switch name {
+	case "", "none":
+		return NoReceiptCompression, nil
+	case "snappy":
+		return SnappyReceiptCompression, nil
+	case "zstd":
+		return ZstdReceiptCompression, nil
+	default:
+		return NoReceiptCompression, fmt.Errorf("%w: %q", errUnknownReceiptCompression, name)
+	}
+}
+
+func (c ReceiptCompression) String() string {
+	switch c {
+	case NoReceiptCompression:
+		return "none"
+	case SnappyReceiptCompression:
+		return "snappy"
+	case ZstdReceiptCompression:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// storedReceiptCompression returns the compression of the stored receipts [data].
+func storedReceiptCompression(data []byte) ReceiptCompression {
+	if len(data) == 0 || data[0] >= 0xc0 {
+		return NoReceiptCompression
+	}
+	return ReceiptCompression(data[0])
+}
+
+// compressReceipts returns the stored form of the RLP encoded receipts [data]
+// compressed with [compression].
+func compressReceipts(data []byte, compression ReceiptCompression) ([]byte, error) {
+	switch compression {
+	case NoReceiptCompression:
+		return data, nil
+	case SnappyReceiptCompression:
+		return append([]byte{byte(compression)}, snappy.Encode(nil, data)...), nil
+	case ZstdReceiptCompression:
+		return zstdEncoder.EncodeAll(data, []byte{byte(compression)}), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownReceiptCompression, compression)
+	}
+}
+
+// decompressReceipts returns the RLP encoded receipts stored as [data].
+func decompressReceipts(data []byte) ([]byte, error) {
+	switch compression := storedReceiptCompression(data); compression {
+	case NoReceiptCompression:
+		return data, nil
+	case SnappyReceiptCompression:
+		return snappy.Decode(nil, data[1:])
+	case ZstdReceiptCompression:
+		return zstdDecoder.DecodeAll(data[1:], nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownReceiptCompression, compression)
+	}
+}
+
+// RecompressReceipts rewrites the receipts of every block stored in [db] that are
+// not compressed with [compression], so that receipts written before the compression
+// was changed are migrated. Returns the number of blocks whose receipts were rewritten.
+func RecompressReceipts(db ethdb.Database, compression ReceiptCompression, interrupt <-chan struct{}) (int, error) {
+	var (
+		start    = time.Now()
+		logged   = start
+		migrated = 0
+		batch    = db.NewBatch()
+		it       = db.NewIterator(blockReceiptsPrefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		select {
+		case <-interrupt:
+			return migrated, errors.New("receipt migration interrupted")
+		default:
+		}
+		key := it.Key()
+		if len(key) != len(blockReceiptsPrefix)+8+common.HashLength || !bytes.HasPrefix(key, blockReceiptsPrefix) {
+			continue
+		}
+		data := it.Value()
+		if storedReceiptCompression(data) == compression {
+			continue
+		}
+		decompressed, err := decompressReceipts(data)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to decompress receipts at key %x: %w", key, err)
+		}
+		recompressed, err := compressReceipts(decompressed, compression)
+		if err != nil {
+			return migrated, err
+		}
+		if err := batch.Put(common.CopyBytes(key), recompressed); err != nil {
+			return migrated, err
+		}
+		migrated++
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return migrated, err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Migrating receipt compression", "compression", compression, "blocks", migrated, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return migrated, err
+	}
+	if err := batch.Write(); err != nil {
+		return migrated, err
+	}
+	log.Info("Migrated receipt compression", "compression", compression, "blocks", migrated, "elapsed", common.PrettyDuration(time.Since(start)))
+	return migrated, nil
+}
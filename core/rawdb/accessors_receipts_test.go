@@ -0,0 +1,85 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func testReceipts() types.Receipts {
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 1,
+		Logs: []*types.Log{
+			{Address: common.BytesToAddress([]byte{0x11}), Topics: []common.Hash{{0x01}}, Data: make([]byte, 128)},
+			{Address: common.BytesToAddress([]byte{0x11}), Topics: []common.Hash{{0x01}}, Data: make([]byte, 128)},
+		},
+	}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return types.Receipts{receipt}
+}
+
+func TestCompressedReceiptStorage(t *testing.T) {
+	receipts := testReceipts()
+	for _, compression := range []ReceiptCompression{NoReceiptCompression, SnappyReceiptCompression, ZstdReceiptCompression} {
+		t.Run(compression.String(), func(t *testing.T) {
+			db := NewMemoryDatabase()
+			hash := common.Hash{0x01}
+
+			WriteCompressedReceipts(db, hash, 1, receipts, compression)
+			data, _ := db.Get(blockReceiptsKey(1, hash))
+			if have := storedReceiptCompression(data); have != compression {
+				t.Fatalf("stored compression mismatch: have %s, want %s", have, compression)
+			}
+			if err := checkReceiptsRLP(ReadRawReceipts(db, hash, 1), receipts); err != nil {
+				t.Fatal(err)
+			}
+			if logs := ReadLogs(db, hash, 1); len(logs) != 1 || len(logs[0]) != 2 {
+				t.Fatalf("logs mismatch: have %v", logs)
+			}
+		})
+	}
+}
+
+func TestRecompressReceipts(t *testing.T) {
+	db := NewMemoryDatabase()
+	receipts := testReceipts()
+	for i := uint64(0); i < 3; i++ {
+		WriteCompressedReceipts(db, common.Hash{byte(i)}, i, receipts, ReceiptCompression(i))
+	}
+
+	migrated, err := RecompressReceipts(db, ZstdReceiptCompression, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Receipts already compressed with zstd are not rewritten.
+	if migrated != 2 {
+		t.Fatalf("migrated blocks mismatch: have %d, want %d", migrated, 2)
+	}
+	for i := uint64(0); i < 3; i++ {
+		hash := common.Hash{byte(i)}
+		data, _ := db.Get(blockReceiptsKey(i, hash))
+		if have := storedReceiptCompression(data); have != ZstdReceiptCompression {
+			t.Fatalf("block %d: stored compression mismatch: have %s, want %s", i, have, ZstdReceiptCompression)
+		}
+		if err := checkReceiptsRLP(ReadRawReceipts(db, hash, i), receipts); err != nil {
+			t.Fatalf("block %d: %v", i, err)
+		}
+	}
+}
+
+func TestParseReceiptCompression(t *testing.T) {
+	for _, compression := range []ReceiptCompression{NoReceiptCompression, SnappyReceiptCompression, ZstdReceiptCompression} {
+		parsed, err := ParseReceiptCompression(compression.String())
+		if err != nil || parsed != compression {
+			t.Fatalf("failed to parse %s: have %s, err %v", compression, parsed, err)
+		}
+	}
+	if _, err := ParseReceiptCompression("gzip"); err == nil {
+		t.Fatal("expected error parsing unknown compression")
+	}
+}
@@ -84,6 +84,7 @@ var (
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 
 	txLookupPrefix        = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
+	blockAcceptedAtPrefix = []byte("T") // blockAcceptedAtPrefix + hash -> acceptance time (uint64 big endian unix seconds)
 	bloomBitsPrefix       = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
@@ -97,9 +98,16 @@ var (
 	configPrefix        = []byte("ethereum-config-") // config prefix for the db
 	upgradeConfigPrefix = []byte("upgrade-config-")  // upgrade bytes passed to the chain are stored with this prefix
 
+	// blockTimestampPrefix + time (uint64 big endian) + num (uint64 big endian) -> hash. The prefix
+	// is not a single byte, so that iterating it does not visit hash-scheme trie nodes.
+	blockTimestampPrefix = []byte("block-time-")
+
 	// BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 	BloomBitsIndexPrefix = []byte("iB")
 
+	// resumableFilterPrefix + filter id -> JSON encoded resumable log filter
+	resumableFilterPrefix = []byte("resumable-filter-")
+
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 
@@ -164,6 +172,21 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// blockTimestampKey = blockTimestampPrefix + time (uint64 big endian) + num (uint64 big endian)
+func blockTimestampKey(time uint64, number uint64) []byte {
+	return append(append(append([]byte{}, blockTimestampPrefix...), encodeBlockNumber(time)...), encodeBlockNumber(number)...)
+}
+
+// blockAcceptedAtKey = blockAcceptedAtPrefix + hash
+func blockAcceptedAtKey(hash common.Hash) []byte {
+	return append(append([]byte{}, blockAcceptedAtPrefix...), hash.Bytes()...)
+}
+
+// resumableFilterKey = resumableFilterPrefix + id
+func resumableFilterKey(id string) []byte {
+	return append(append([]byte{}, resumableFilterPrefix...), id...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -0,0 +1,360 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// importScratchPrefix is the prefix that ImportState writes the trie nodes and code of an
+// import under until the state root is verified, so that a rejected import never leaves
+// unverified data in the database.
+const importScratchPrefix = "state-import-"
+
+var (
+	errExportOutOfOrder   = errors.New("state export entries are not in ascending order")
+	errExportRootMismatch = errors.New("imported state root does not match the export")
+	errExportOrphanSlot   = errors.New("state export storage slot does not follow an account with storage")
+)
+
+// ExportHeader is the first entry of a state export and identifies the exported state.
+type ExportHeader struct {
+	Root common.Hash `json:"root"`
+	// Block is the RLP encoded block whose state is exported, if any.
+	Block hexutil.Bytes `json:"block,omitempty"`
+}
+
+// ExportAccount is a single account of a state export. Accounts and storage slots are
+// keyed by the hash of their address and slot respectively, so that an export can be
+// produced and imported without access to the preimages.
+type ExportAccount struct {
+	Hash     common.Hash   `json:"hash"`
+	Nonce    uint64        `json:"nonce"`
+	Balance  *hexutil.Big  `json:"balance"`
+	Root     common.Hash   `json:"root"`
+	CodeHash common.Hash   `json:"codeHash"`
+	Code     hexutil.Bytes `json:"code,omitempty"`
+}
+
+// ExportSlot is a single storage slot of the account preceding it in a state export.
+type ExportSlot struct {
+	Hash  common.Hash   `json:"hash"`
+	Value hexutil.Bytes `json:"value"` // RLP encoded value
+}
+
+// exportEntry is a line of a state export following the header, holding either an
+// account or a storage slot.
+type exportEntry struct {
+	Account *ExportAccount `json:"account,omitempty"`
+	Slot    *ExportSlot    `json:"slot,omitempty"`
+}
+
+// ExportState writes the full state (accounts, storage and code) at [header.Root] to [w]
+// as a stream of newline delimited JSON objects: [header] followed by one entry per account
+// in ascending order of the account hash, each followed by one entry per storage slot of
+// the account in ascending order of the slot hash. Storage is streamed slot by slot, so
+// the memory used does not depend on the size of the state. Returns the number of accounts
+// exported.
+func ExportState(db Database, header ExportHeader, w io.Writer) (uint64, error) {
+	root := header.Root
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		enc      = json.NewEncoder(w)
+		accounts uint64
+		slots    uint64
+		start    = time.Now()
+		logged   = time.Now()
+	)
+	if err := enc.Encode(header); err != nil {
+		return 0, err
+	}
+	log.Info("State export started", "root", root)
+
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		var data types.StateAccount
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return accounts, err
+		}
+		account := ExportAccount{
+			Hash:     common.BytesToHash(it.Key),
+			Nonce:    data.Nonce,
+			Balance:  (*hexutil.Big)(data.Balance),
+			Root:     data.Root,
+			CodeHash: common.BytesToHash(data.CodeHash),
+		}
+		if !bytes.Equal(data.CodeHash, types.EmptyCodeHash.Bytes()) {
+			code, err := db.ContractCode(account.Hash, account.CodeHash)
+			if err != nil {
+				return accounts, fmt.Errorf("failed to read code of account %s: %w", account.Hash, err)
+			}
+			account.Code = code
+		}
+		if err := enc.Encode(exportEntry{Account: &account}); err != nil {
+			return accounts, err
+		}
+		if data.Root != types.EmptyRootHash {
+			storageTrie, err := db.OpenStorageTrie(root, account.Hash, data.Root)
+			if err != nil {
+				return accounts, fmt.Errorf("failed to open storage trie of account %s: %w", account.Hash, err)
+			}
+			storageIt := trie.NewIterator(storageTrie.NodeIterator(nil))
+			for storageIt.Next() {
+				slot := ExportSlot{Hash: common.BytesToHash(storageIt.Key), Value: storageIt.Value}
+				if err := enc.Encode(exportEntry{Slot: &slot}); err != nil {
+					return accounts, err
+				}
+				slots++
+			}
+			if storageIt.Err != nil {
+				return accounts, fmt.Errorf("failed to iterate storage of account %s: %w", account.Hash, storageIt.Err)
+			}
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("State export in progress", "at", account.Hash, "accounts", accounts, "slots", slots,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if it.Err != nil {
+		return accounts, it.Err
+	}
+	log.Info("State export complete", "root", root, "accounts", accounts, "slots", slots,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return accounts, nil
+}
+
+// ImportState reads a state export produced by ExportState from [r] and writes the trie
+// nodes and contract code it contains to [db]. The tries are rebuilt from the exported
+// leaves under a scratch prefix, and are only moved into place once the resulting state
+// root matches the root of the export. If [checkHeader] is not nil, it is called with the
+// header of the export before anything is written, and an error aborts the import.
+// Returns the header of the export.
+//
+// ImportState does not update the chain markers of [db], which is left to the caller.
+func ImportState(db ethdb.Database, r io.Reader, checkHeader func(ExportHeader) error) (ExportHeader, error) {
+	scratch := rawdb.NewTable(db, importScratchPrefix)
+	// Clear the leftovers of an interrupted import.
+	if err := clearScratch(scratch); err != nil {
+		return ExportHeader{}, err
+	}
+	header, err := importScratch(scratch, r, checkHeader)
+	if err != nil {
+		if clearErr := clearScratch(scratch); clearErr != nil {
+			log.Error("Failed to clear rejected state import", "err", clearErr)
+		}
+		return ExportHeader{}, err
+	}
+	if err := moveScratch(db, scratch); err != nil {
+		return ExportHeader{}, err
+	}
+	return header, nil
+}
+
+// importScratch rebuilds the tries of the state export read from [r] into [db] and
+// verifies them against the root of the export.
+func importScratch(db ethdb.Database, r io.Reader, checkHeader func(ExportHeader) error) (ExportHeader, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var header ExportHeader
+	if err := dec.Decode(&header); err != nil {
+		return ExportHeader{}, fmt.Errorf("failed to read state export header: %w", err)
+	}
+	if checkHeader != nil {
+		if err := checkHeader(header); err != nil {
+			return ExportHeader{}, err
+		}
+	}
+
+	var (
+		batch   = db.NewBatch()
+		writeFn = func(owner common.Hash, path []byte, hash common.Hash, blob []byte) {
+			rawdb.WriteTrieNode(batch, owner, path, hash, blob, rawdb.HashScheme)
+		}
+		accountTrie = trie.NewStackTrie(writeFn)
+		account     *ExportAccount  // account the following slots belong to
+		storageTrie *trie.StackTrie // storage trie of [account], nil if it has no storage
+		lastSlot    *common.Hash    // last slot of [account]
+		accounts    uint64
+		slots       uint64
+		start       = time.Now()
+		logged      = time.Now()
+	)
+	log.Info("State import started", "root", header.Root)
+	for {
+		var entry exportEntry
+		err := dec.Decode(&entry)
+		if err != nil && err != io.EOF {
+			return ExportHeader{}, fmt.Errorf("failed to read entry %d of state export: %w", accounts+slots, err)
+		}
+
+		if entry.Slot != nil {
+			if storageTrie == nil {
+				return ExportHeader{}, fmt.Errorf("%w: %s", errExportOrphanSlot, entry.Slot.Hash)
+			}
+			if lastSlot != nil && bytes.Compare(entry.Slot.Hash[:], lastSlot[:]) <= 0 {
+				return ExportHeader{}, fmt.Errorf("%w: slot %s after %s of account %s", errExportOutOfOrder, entry.Slot.Hash, lastSlot, account.Hash)
+			}
+			lastSlot = &entry.Slot.Hash
+			if err := storageTrie.TryUpdate(entry.Slot.Hash[:], entry.Slot.Value); err != nil {
+				return ExportHeader{}, err
+			}
+			slots++
+			continue
+		}
+
+		// Any other entry completes the storage of the previous account.
+		if storageTrie != nil {
+			root, err := storageTrie.Commit()
+			if err != nil {
+				return ExportHeader{}, err
+			}
+			if root != account.Root {
+				return ExportHeader{}, fmt.Errorf("%w: storage of account %s: have %s, want %s", errExportRootMismatch, account.Hash, root, account.Root)
+			}
+			storageTrie, lastSlot = nil, nil
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return ExportHeader{}, err
+			}
+			batch.Reset()
+		}
+		if err == io.EOF {
+			break
+		}
+		if entry.Account == nil {
+			return ExportHeader{}, fmt.Errorf("empty entry %d in state export", accounts+slots)
+		}
+
+		if account != nil && bytes.Compare(entry.Account.Hash[:], account.Hash[:]) <= 0 {
+			return ExportHeader{}, fmt.Errorf("%w: account %s after %s", errExportOutOfOrder, entry.Account.Hash, account.Hash)
+		}
+		account = entry.Account
+		if len(account.Code) > 0 {
+			if codeHash := crypto.Keccak256Hash(account.Code); codeHash != account.CodeHash {
+				return ExportHeader{}, fmt.Errorf("code hash mismatch for account %s: have %s, want %s", account.Hash, codeHash, account.CodeHash)
+			}
+			rawdb.WriteCode(batch, account.CodeHash, account.Code)
+		}
+		if account.Root != types.EmptyRootHash {
+			storageTrie = trie.NewStackTrieWithOwner(writeFn, account.Hash)
+		}
+		if account.Balance == nil {
+			account.Balance = new(hexutil.Big)
+		}
+		data, err := rlp.EncodeToBytes(&types.StateAccount{
+			Nonce:    account.Nonce,
+			Balance:  account.Balance.ToInt(),
+			Root:     account.Root,
+			CodeHash: account.CodeHash.Bytes(),
+		})
+		if err != nil {
+			return ExportHeader{}, err
+		}
+		if err := accountTrie.TryUpdate(account.Hash[:], data); err != nil {
+			return ExportHeader{}, err
+		}
+
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("State import in progress", "at", account.Hash, "accounts", accounts, "slots", slots,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	root, err := accountTrie.Commit()
+	if err != nil {
+		return ExportHeader{}, err
+	}
+	if root != header.Root {
+		return ExportHeader{}, fmt.Errorf("%w: have %s, want %s", errExportRootMismatch, root, header.Root)
+	}
+	if err := batch.Write(); err != nil {
+		return ExportHeader{}, err
+	}
+	log.Info("State import verified", "root", root, "accounts", accounts, "slots", slots,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return header, nil
+}
+
+// moveScratch moves the verified data in [scratch] to [db].
+func moveScratch(db ethdb.Database, scratch ethdb.Database) error {
+	var (
+		batch        = db.NewBatch()
+		scratchBatch = scratch.NewBatch()
+		it           = scratch.NewIterator(nil, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+		if err := scratchBatch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+			if err := scratchBatch.Write(); err != nil {
+				return err
+			}
+			scratchBatch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	return scratchBatch.Write()
+}
+
+// clearScratch deletes all data in [scratch].
+func clearScratch(scratch ethdb.Database) error {
+	var (
+		batch = scratch.NewBatch()
+		it    = scratch.NewIterator(nil, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
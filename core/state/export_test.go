@@ -0,0 +1,162 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func makeExportTestState(t *testing.T) (Database, common.Hash) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(common.Hash{}, db, nil)
+	for i := byte(0); i < 255; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		state.AddBalance(addr, big.NewInt(int64(11*i)))
+		state.SetNonce(addr, uint64(42*i))
+		if i%3 == 0 {
+			state.SetCode(addr, []byte{i, i, i})
+		}
+		if i%5 == 0 {
+			for j := byte(0); j < i; j++ {
+				state.SetState(addr, common.BytesToHash([]byte{j}), common.BytesToHash([]byte{i, j}))
+			}
+		}
+	}
+	root, err := state.Commit(false, false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	return db, root
+}
+
+func TestExportImportState(t *testing.T) {
+	srcDb, root := makeExportTestState(t)
+
+	var buf bytes.Buffer
+	accounts, err := ExportState(srcDb, ExportHeader{Root: root}, &buf)
+	if err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	if accounts != 255 {
+		t.Fatalf("exported account count mismatch: have %d, want %d", accounts, 255)
+	}
+
+	dstDisk := rawdb.NewMemoryDatabase()
+	header, err := ImportState(dstDisk, &buf, nil)
+	if err != nil {
+		t.Fatalf("failed to import state: %v", err)
+	}
+	if header.Root != root {
+		t.Fatalf("imported root mismatch: have %x, want %x", header.Root, root)
+	}
+	it := dstDisk.NewIterator([]byte(importScratchPrefix), nil)
+	if it.Next() {
+		t.Fatalf("scratch data left after import: %x", it.Key())
+	}
+	it.Release()
+
+	src, _ := New(root, srcDb, nil)
+	dst, err := New(root, NewDatabase(dstDisk), nil)
+	if err != nil {
+		t.Fatalf("failed to open imported state: %v", err)
+	}
+	for i := byte(0); i < 255; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		if have, want := dst.GetBalance(addr), src.GetBalance(addr); have.Cmp(want) != 0 {
+			t.Errorf("account %x: balance mismatch: have %v, want %v", addr, have, want)
+		}
+		if have, want := dst.GetNonce(addr), src.GetNonce(addr); have != want {
+			t.Errorf("account %x: nonce mismatch: have %d, want %d", addr, have, want)
+		}
+		if have, want := dst.GetCode(addr), src.GetCode(addr); !bytes.Equal(have, want) {
+			t.Errorf("account %x: code mismatch: have %x, want %x", addr, have, want)
+		}
+		for j := byte(0); j < i; j++ {
+			key := common.BytesToHash([]byte{j})
+			if have, want := dst.GetState(addr, key), src.GetState(addr, key); have != want {
+				t.Errorf("account %x: storage %x mismatch: have %x, want %x", addr, key, have, want)
+			}
+		}
+	}
+}
+
+func TestImportStateRejectsTampering(t *testing.T) {
+	srcDb, root := makeExportTestState(t)
+
+	var buf bytes.Buffer
+	if _, err := ExportState(srcDb, ExportHeader{Root: root}, &buf); err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	// Find two consecutive accounts without storage.
+	var first int
+	for i := 1; i+2 < len(lines); i++ {
+		if strings.HasPrefix(lines[i], `{"account"`) && strings.HasPrefix(lines[i+1], `{"account"`) && strings.HasPrefix(lines[i+2], `{"account"`) {
+			first = i
+			break
+		}
+	}
+	if first == 0 {
+		t.Fatal("no consecutive accounts without storage in export")
+	}
+	// Find a storage slot.
+	var slot int
+	for i := 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], `{"slot"`) {
+			slot = i
+			break
+		}
+	}
+
+	tests := map[string]struct {
+		lines []string
+		err   error
+	}{
+		"swapped accounts": {
+			lines: func() []string {
+				swapped := append([]string{}, lines...)
+				swapped[first], swapped[first+1] = swapped[first+1], swapped[first]
+				return swapped
+			}(),
+			err: errExportOutOfOrder,
+		},
+		"dropped account": {
+			lines: append(append([]string{}, lines[:first]...), lines[first+1:]...),
+			err:   errExportRootMismatch,
+		},
+		"dropped slot": {
+			lines: append(append([]string{}, lines[:slot]...), lines[slot+1:]...),
+			err:   errExportRootMismatch,
+		},
+		"orphan slot": {
+			lines: append(append([]string{lines[0]}, lines[slot]), lines[1:]...),
+			err:   errExportOrphanSlot,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			disk := rawdb.NewMemoryDatabase()
+			if _, err := ImportState(disk, strings.NewReader(strings.Join(test.lines, "\n")), nil); !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			// A rejected import leaves nothing in the database.
+			it := disk.NewIterator(nil, nil)
+			defer it.Release()
+			if it.Next() {
+				t.Fatalf("rejected import wrote %x", it.Key())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	cacheJournalDataDir = "data"  // Directory holding the fastcache files
+	cacheJournalLayer   = "layer" // File holding the block hash and root of the saved disk layer
+)
+
+// SaveCache writes the read cache of the disk layer to [dir], together with the
+// block hash and root of the disk layer, so that it can be restored by
+// [loadCacheJournal] when the node restarts on the same disk layer.
+func (t *Tree) SaveCache(dir string) error {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	dl := t.disklayer()
+	if dl == nil {
+		return errors.New("snapshot tree has no disk layer")
+	}
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	// Remove any previous journal first, so that a failure below never leaves
+	// the cache of one disk layer tagged with another.
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove snapshot cache journal: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot cache journal: %w", err)
+	}
+	if err := dl.cache.SaveToFileConcurrent(filepath.Join(dir, cacheJournalDataDir), runtime.GOMAXPROCS(0)); err != nil {
+		return fmt.Errorf("failed to save snapshot cache: %w", err)
+	}
+	layer := append(dl.blockHash.Bytes(), dl.root.Bytes()...)
+	if err := os.WriteFile(filepath.Join(dir, cacheJournalLayer), layer, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot cache layer: %w", err)
+	}
+	log.Info("Saved snapshot cache", "dir", dir, "blockHash", dl.blockHash, "root", dl.root)
+	return nil
+}
+
+// loadCacheJournal returns the read cache saved by [Tree.SaveCache] in [dir] if it
+// was saved for the disk layer at [blockHash] and [root], or an empty cache
+// otherwise. The journal is removed once read, since it is stale as soon as the
+// disk layer is modified.
+func loadCacheJournal(dir string, size int, blockHash, root common.Hash) *utils.MeteredCache {
+	if dir == "" {
+		return newMeteredSnapshotCache(size)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warn("Failed to remove snapshot cache journal", "dir", dir, "err", err)
+		}
+	}()
+
+	layer, err := os.ReadFile(filepath.Join(dir, cacheJournalLayer))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("Failed to read snapshot cache layer", "dir", dir, "err", err)
+		}
+		return newMeteredSnapshotCache(size)
+	}
+	if !bytes.Equal(layer, append(blockHash.Bytes(), root.Bytes()...)) {
+		log.Info("Discarding snapshot cache saved for a different disk layer", "dir", dir)
+		return newMeteredSnapshotCache(size)
+	}
+	return utils.NewMeteredCache(size, filepath.Join(dir, cacheJournalDataDir), snapshotCacheNamespace, snapshotCacheStatsUpdateFrequency)
+}
@@ -0,0 +1,55 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCacheJournal(t *testing.T) {
+	var (
+		blockHash = common.HexToHash("0x01")
+		root      = common.HexToHash("0xff01")
+		key       = []byte("key")
+		value     = []byte("value")
+		dir       = filepath.Join(t.TempDir(), "snapshot-cache")
+	)
+	snaps := NewTestTree(rawdb.NewMemoryDatabase(), blockHash, root)
+	snaps.disklayer().cache.Set(key, value)
+	if err := snaps.SaveCache(dir); err != nil {
+		t.Fatalf("failed to save cache: %v", err)
+	}
+
+	// A journal saved for another disk layer must not be restored.
+	cache := loadCacheJournal(dir, 128*256, blockHash, common.HexToHash("0xff02"))
+	if got := cache.Get(nil, key); len(got) != 0 {
+		t.Fatalf("restored cache of a different disk layer: %x", got)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected journal to be removed after loading, err: %v", err)
+	}
+
+	if err := snaps.SaveCache(dir); err != nil {
+		t.Fatalf("failed to save cache: %v", err)
+	}
+	cache = loadCacheJournal(dir, 128*256, blockHash, root)
+	if got := cache.Get(nil, key); !bytes.Equal(got, value) {
+		t.Fatalf("unexpected cached value: have %x, want %x", got, value)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected journal to be removed after loading, err: %v", err)
+	}
+
+	// The journal can only be restored once.
+	cache = loadCacheJournal(dir, 128*256, blockHash, root)
+	if got := cache.Get(nil, key); len(got) != 0 {
+		t.Fatalf("restored cache from a consumed journal: %x", got)
+	}
+}
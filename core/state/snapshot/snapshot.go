@@ -173,6 +173,8 @@ type Config struct {
 	NoBuild    bool // Indicator that the snapshots generation is disallowed
 	AsyncBuild bool // The snapshot generation is allowed to be constructed asynchronously
 	SkipVerify bool // Indicator that all verification should be bypassed
+
+	CacheJournal string // Directory to restore the read cache of the disk layer from, if saved for the same layer
 }
 
 // Tree is an Ethereum state snapshot tree. It consists of one persistent base
@@ -221,7 +223,7 @@ func New(config Config, diskdb ethdb.KeyValueStore, triedb *trie.Database, block
 	}
 
 	// Attempt to load a previously persisted snapshot and rebuild one if failed
-	head, generated, err := loadSnapshot(diskdb, triedb, config.CacheSize, blockHash, root, config.NoBuild)
+	head, generated, err := loadSnapshot(diskdb, triedb, config.CacheSize, config.CacheJournal, blockHash, root, config.NoBuild)
 	if err != nil {
 		log.Warn("Failed to load snapshot, regenerating", "err", err)
 		if !config.NoBuild {
@@ -188,6 +188,11 @@ func ApplyPrecompileActivations(c *params.ChainConfig, parentTimestamp *uint64,
 			// (or deconfigure it if it is being disabled.)
 			if activatingConfig.IsDisabled() {
 				log.Info("Disabling precompile", "name", key)
+				if deconfigurator, ok := module.Configurator.(contract.Deconfigurator); ok {
+					if err := deconfigurator.Deconfigure(statedb, blockContext); err != nil {
+						return fmt.Errorf("could not deconfigure precompile, name: %s, reason: %w", key, err)
+					}
+				}
 				statedb.Suicide(module.Address)
 				// Calling Finalise here effectively commits Suicide call and wipes the contract state.
 				// This enables re-configuration of the same contract state in the same block.
@@ -31,9 +31,12 @@ import (
 	"math"
 	"math/big"
 
+	"github.com/ava-labs/subnet-evm/constants"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/core/vm"
 	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/gassponsor"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
 	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
 	"github.com/ava-labs/subnet-evm/vmerrs"
@@ -264,6 +267,9 @@ type StateTransition struct {
 	initialGas   uint64
 	state        vm.StateDB
 	evm          *vm.EVM
+	// gasPayer is the account that pays for gas, which is the sender unless the
+	// gas is sponsored by the gas sponsor precompile.
+	gasPayer common.Address
 }
 
 // NewStateTransition initialises and returns a new state transition object.
@@ -284,14 +290,37 @@ func (st *StateTransition) to() common.Address {
 	return *st.msg.To
 }
 
+// gasSponsored returns true if the gas of the message is paid by the gas sponsor
+// precompile, which requires the message to be sent to a sponsored contract, to pay
+// no tip above the base fee, to fit in the sponsored gas quota of the sender and the
+// sponsor balance to cover [gasCost].
+func (st *StateTransition) gasSponsored(gasCost *big.Int) bool {
+	if st.msg.To == nil || !st.evm.ChainConfig().IsPrecompileEnabled(gassponsor.ContractAddress, st.evm.Context.Time) {
+		return false
+	}
+	// The sponsor must not pay a tip chosen by the sender.
+	if baseFee := st.evm.Context.BaseFee; baseFee == nil || st.msg.GasPrice.Cmp(baseFee) > 0 {
+		return false
+	}
+	return gassponsor.CanSponsor(st.state, st.msg.From, *st.msg.To, st.evm.Context.Time, st.msg.GasLimit, 0, gasCost)
+}
+
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).SetUint64(st.msg.GasLimit)
 	mgval = mgval.Mul(mgval, st.msg.GasPrice)
+	gasCheck := mgval
 	balanceCheck := mgval
 	if st.msg.GasFeeCap != nil {
-		balanceCheck = new(big.Int).SetUint64(st.msg.GasLimit)
-		balanceCheck.Mul(balanceCheck, st.msg.GasFeeCap)
-		balanceCheck.Add(balanceCheck, st.msg.Value)
+		gasCheck = new(big.Int).SetUint64(st.msg.GasLimit)
+		gasCheck.Mul(gasCheck, st.msg.GasFeeCap)
+		balanceCheck = new(big.Int).Add(gasCheck, st.msg.Value)
+	}
+	st.gasPayer = st.msg.From
+	sponsored := st.gasSponsored(gasCheck)
+	if sponsored {
+		// The sponsor pays for gas, so the sender only needs to cover the value.
+		st.gasPayer = gassponsor.ContractAddress
+		balanceCheck = st.msg.Value
 	}
 	if have, want := st.state.GetBalance(st.msg.From), balanceCheck; have.Cmp(want) < 0 {
 		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
@@ -302,7 +331,11 @@ func (st *StateTransition) buyGas() error {
 	st.gasRemaining += st.msg.GasLimit
 
 	st.initialGas = st.msg.GasLimit
-	st.state.SubBalance(st.msg.From, mgval)
+	st.state.SubBalance(st.gasPayer, mgval)
+	if sponsored {
+		// The gas limit counts against the quota of the sender, whatever the gas used.
+		gassponsor.UseSponsoredGas(st.state, st.msg.From, st.evm.Context.Time, st.msg.GasLimit)
+	}
 	return nil
 }
 
@@ -335,7 +368,7 @@ func (st *StateTransition) preCheck() error {
 
 		// Check that the sender is on the tx allow list if enabled
 		if st.evm.ChainConfig().IsPrecompileEnabled(txallowlist.ContractAddress, st.evm.Context.Time) {
-			txAllowListRole := txallowlist.GetTxAllowListStatus(st.state, msg.From)
+			txAllowListRole := txallowlist.GetTxAllowListStatusAt(st.state, msg.From, st.evm.Context.Time)
 			if !txAllowListRole.IsEnabled() {
 				return fmt.Errorf("%w: %s", vmerrs.ErrSenderAddressNotAllowListed, msg.From)
 			}
@@ -446,7 +479,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), msg.Data, st.gasRemaining, msg.Value)
 	}
 	st.refundGas(rules.IsSubnetEVM)
-	st.state.AddBalance(st.evm.Context.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), msg.GasPrice))
+	st.payFee(rules, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), msg.GasPrice))
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -455,6 +488,20 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}, nil
 }
 
+// payFee pays [fee] to the coinbase. From DUpgrade onwards, the fee burn percentage
+// configured in the RewardManager is burned instead.
+func (st *StateTransition) payFee(rules params.Rules, fee *big.Int) {
+	if !rules.IsDUpgrade || !rules.IsPrecompileEnabled(rewardmanager.ContractAddress) {
+		st.state.AddBalance(st.evm.Context.Coinbase, fee)
+		return
+	}
+	reward, burned := rewardmanager.SplitFee(st.state, fee)
+	st.state.AddBalance(st.evm.Context.Coinbase, reward)
+	if burned.Sign() > 0 {
+		st.state.AddBalance(constants.BlackholeAddr, burned)
+	}
+}
+
 func (st *StateTransition) refundGas(subnetEVM bool) {
 	// Inspired by: https://gist.github.com/holiman/460f952716a74eeb9ab358bb1836d821#gistcomment-3642048
 	if !subnetEVM {
@@ -468,7 +515,7 @@ func (st *StateTransition) refundGas(subnetEVM bool) {
 
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gasRemaining), st.msg.GasPrice)
-	st.state.AddBalance(st.msg.From, remaining)
+	st.state.AddBalance(st.gasPayer, remaining)
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
@@ -0,0 +1,157 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// MaxBundleSize is the maximum number of transactions in a bundle.
+	MaxBundleSize = 16
+	// maxPendingBundles is the maximum number of bundles waiting to be built into
+	// a block.
+	maxPendingBundles = 64
+)
+
+var (
+	ErrEmptyBundle     = errors.New("bundle has no transactions")
+	ErrBundleTooLarge  = errors.New("bundle has too many transactions")
+	ErrBundlePoolFull  = errors.New("too many pending bundles")
+	ErrDuplicateBundle = errors.New("bundle already pending")
+	ErrBundleNonceGap  = errors.New("bundle transactions of a sender do not have consecutive nonces")
+)
+
+// Bundle is an ordered list of transactions that must be included in the same
+// block, in order and without other transactions between them, or not at all.
+type Bundle struct {
+	Hash common.Hash
+	Txs  types.Transactions
+}
+
+// bundleHash returns the hash identifying the bundle of [txs], which is the
+// hash of the concatenated transaction hashes.
+func bundleHash(txs types.Transactions) common.Hash {
+	hashes := make([][]byte, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash().Bytes())
+	}
+	return crypto.Keccak256Hash(hashes...)
+}
+
+// AddBundle validates each transaction of [txs] against the current state and
+// queues them as a bundle for the next block built by this node. Bundles are
+// never gossiped, and are dropped if they cannot be included in that block.
+//
+// Bundles are submitted by RPC clients, so their transactions are validated as
+// remote transactions, and the transactions of each sender are checked to be
+// able to execute one after the other.
+func (pool *TxPool) AddBundle(txs types.Transactions) (common.Hash, error) {
+	if len(txs) == 0 {
+		return common.Hash{}, ErrEmptyBundle
+	}
+	if len(txs) > MaxBundleSize {
+		return common.Hash{}, fmt.Errorf("%w: %d > max %d", ErrBundleTooLarge, len(txs), MaxBundleSize)
+	}
+	bundle := &Bundle{
+		Hash: bundleHash(txs),
+		Txs:  txs,
+	}
+
+	pool.mu.Lock()
+	if len(pool.bundles) >= maxPendingBundles {
+		pool.mu.Unlock()
+		return common.Hash{}, ErrBundlePoolFull
+	}
+	for _, pending := range pool.bundles {
+		if pending.Hash == bundle.Hash {
+			pool.mu.Unlock()
+			return common.Hash{}, ErrDuplicateBundle
+		}
+	}
+	for i, tx := range txs {
+		if err := pool.validateTx(tx, false); err != nil {
+			pool.mu.Unlock()
+			return common.Hash{}, fmt.Errorf("invalid bundle transaction %d (%s): %w", i, tx.Hash(), err)
+		}
+	}
+	if err := pool.checkBundleSequence(txs); err != nil {
+		pool.mu.Unlock()
+		return common.Hash{}, err
+	}
+	pool.bundles = append(pool.bundles, bundle)
+	pool.mu.Unlock()
+
+	log.Debug("Added transaction bundle", "hash", bundle.Hash, "txs", len(txs))
+	pool.bundleFeed.Send(core.NewTxsEvent{Txs: txs})
+	return bundle.Hash, nil
+}
+
+// checkBundleSequence checks that the transactions of each sender in [txs], which
+// passed validateTx on their own, can all execute in order: their nonces must be
+// consecutive and the balance of the sender must cover their combined cost. The
+// combined cost includes the gas of sponsored transactions, since whether the
+// sponsor covers all of them is only known once they execute.
+func (pool *TxPool) checkBundleSequence(txs types.Transactions) error {
+	pool.currentStateLock.Lock()
+	defer pool.currentStateLock.Unlock()
+
+	nonces := make(map[common.Address]uint64)
+	costs := make(map[common.Address]*big.Int)
+	for i, tx := range txs {
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil {
+			return ErrInvalidSender
+		}
+		if next, ok := nonces[from]; ok && tx.Nonce() != next {
+			return fmt.Errorf("%w: transaction %d (%s) of %s has nonce %d, want %d", ErrBundleNonceGap, i, tx.Hash(), from, tx.Nonce(), next)
+		}
+		nonces[from] = tx.Nonce() + 1
+
+		cost, ok := costs[from]
+		if !ok {
+			cost = new(big.Int)
+			costs[from] = cost
+		}
+		cost.Add(cost, tx.Cost())
+		if balance := pool.currentState.GetBalance(from); balance.Cmp(cost) < 0 {
+			return fmt.Errorf("%w: bundle transactions of %s up to %d (%s) cost %d, have %d", core.ErrInsufficientFunds, from, i, tx.Hash(), cost, balance)
+		}
+	}
+	return nil
+}
+
+// PendingBundles returns the number of bundles waiting to be built into a block.
+func (pool *TxPool) PendingBundles() int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return len(pool.bundles)
+}
+
+// TakeBundles removes and returns the pending bundles in the order they were
+// added. The caller is responsible for including or dropping them.
+func (pool *TxPool) TakeBundles() []*Bundle {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	bundles := pool.bundles
+	pool.bundles = nil
+	return bundles
+}
+
+// SubscribeNewBundleEvent registers a subscription of NewTxsEvent and starts
+// sending the transactions of each bundle added to the pool to the given channel.
+func (pool *TxPool) SubscribeNewBundleEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.bundleFeed.Subscribe(ch))
+}
@@ -0,0 +1,87 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAddBundle(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	if _, err := pool.AddBundle(nil); !errors.Is(err, ErrEmptyBundle) {
+		t.Errorf("expected %v, got %v", ErrEmptyBundle, err)
+	}
+	large := make(types.Transactions, MaxBundleSize+1)
+	for i := range large {
+		large[i] = transaction(uint64(i), 100000, key)
+	}
+	if _, err := pool.AddBundle(large); !errors.Is(err, ErrBundleTooLarge) {
+		t.Errorf("expected %v, got %v", ErrBundleTooLarge, err)
+	}
+	underfunded := types.Transactions{transaction(0, 100000, key), pricedTransaction(1, 100000, big.NewInt(100), key)}
+	if _, err := pool.AddBundle(underfunded); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Errorf("expected %v, got %v", core.ErrInsufficientFunds, err)
+	}
+	// Each transaction is affordable on its own, but not all of them together.
+	overdrawn := make(types.Transactions, 10)
+	for i := range overdrawn {
+		overdrawn[i] = transaction(uint64(i), 100000, key)
+	}
+	if _, err := pool.AddBundle(overdrawn); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Errorf("expected %v, got %v", core.ErrInsufficientFunds, err)
+	}
+	gapped := types.Transactions{transaction(0, 100000, key), transaction(2, 100000, key)}
+	if _, err := pool.AddBundle(gapped); !errors.Is(err, ErrBundleNonceGap) {
+		t.Errorf("expected %v, got %v", ErrBundleNonceGap, err)
+	}
+	// Bundle transactions are held to the price limit of remote transactions.
+	underpriced := types.Transactions{pricedTransaction(0, 100000, big.NewInt(0), key)}
+	if _, err := pool.AddBundle(underpriced); !errors.Is(err, ErrUnderpriced) {
+		t.Errorf("expected %v, got %v", ErrUnderpriced, err)
+	}
+
+	events := make(chan core.NewTxsEvent, 1)
+	sub := pool.SubscribeNewBundleEvent(events)
+	defer sub.Unsubscribe()
+
+	txs := types.Transactions{transaction(0, 100000, key), transaction(1, 100000, key)}
+	hash, err := pool.AddBundle(txs)
+	if err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if _, err := pool.AddBundle(txs); !errors.Is(err, ErrDuplicateBundle) {
+		t.Errorf("expected %v, got %v", ErrDuplicateBundle, err)
+	}
+	if ev := <-events; len(ev.Txs) != len(txs) {
+		t.Errorf("bundle event transaction count mismatch: have %d, want %d", len(ev.Txs), len(txs))
+	}
+	// Bundle transactions must not enter the pool, so that they are not gossiped.
+	for _, tx := range txs {
+		if pool.Has(tx.Hash()) {
+			t.Errorf("bundle transaction %s added to the pool", tx.Hash())
+		}
+	}
+	if pending := pool.PendingBundles(); pending != 1 {
+		t.Fatalf("pending bundles mismatch: have %d, want 1", pending)
+	}
+	bundles := pool.TakeBundles()
+	if len(bundles) != 1 || bundles[0].Hash != hash || len(bundles[0].Txs) != len(txs) {
+		t.Fatalf("taken bundles mismatch: %v", bundles)
+	}
+	if pending := pool.PendingBundles(); pending != 0 {
+		t.Errorf("pending bundles mismatch after take: have %d, want 0", pending)
+	}
+}
@@ -0,0 +1,121 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool
+
+import (
+	"math/big"
+	"sync"
+)
+
+// DropReason is the reason a transaction was rejected by or removed from the pool
+// without being included in a block.
+type DropReason string
+
+const (
+	DropUnderpriced  DropReason = "underpriced"  // Rejected for paying no more than the cheapest remote transaction of a full pool
+	DropPriceEvicted DropReason = "priceEvicted" // Evicted from a full pool to make room for a better paying transaction
+	DropOverflowed   DropReason = "overflowed"   // Rejected because no room could be made for it in a full pool
+	DropThrottled    DropReason = "throttled"    // Rejected because too many transactions were evicted since the last reorg
+	DropReplaced     DropReason = "replaced"     // Replaced by a transaction with the same nonce and a higher price
+	DropNoFunds      DropReason = "noFunds"      // Removed because the sender can no longer pay for it
+	DropRateLimit    DropReason = "rateLimit"    // Removed because the sender or the pool exceeded its slot allowance
+	DropLifetime     DropReason = "lifetime"     // Removed because it was queued for longer than the configured lifetime
+	DropNonceGap     DropReason = "nonceGap"     // Rejected for a nonce too far ahead of the sender's next nonce
+)
+
+// dropCounter counts the transactions dropped by the pool for each reason. Unlike the
+// pool metrics, these are counted even if metrics are disabled.
+type dropCounter struct {
+	lock   sync.Mutex
+	counts map[DropReason]uint64
+}
+
+func newDropCounter() *dropCounter {
+	return &dropCounter{counts: make(map[DropReason]uint64)}
+}
+
+// add counts [count] transactions dropped for [reason].
+func (c *dropCounter) add(reason DropReason, count int64) {
+	if count <= 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.counts[reason] += uint64(count)
+}
+
+// copy returns a copy of the counts by reason.
+func (c *dropCounter) copy() map[DropReason]uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	counts := make(map[DropReason]uint64, len(c.counts))
+	for reason, count := range c.counts {
+		counts[reason] = count
+	}
+	return counts
+}
+
+// PriceDiagnostics describes the prices a transaction must pay to be accepted and
+// kept by the pool, and how many transactions the pool has dropped and why.
+type PriceDiagnostics struct {
+	GasTip     *big.Int // Minimum gas tip cap of remote transactions
+	MinimumFee *big.Int // Minimum gas fee cap of all transactions, nil if unset
+	BaseFee    *big.Int // Estimated base fee of the next block, nil before SubnetEVM
+
+	// MinExecutableFeeCap is the minimum gas fee cap for a transaction to be accepted
+	// and executable in the next block.
+	MinExecutableFeeCap *big.Int
+
+	// Full is whether the pool has no free slots, in which case remote transactions
+	// must outbid the cheapest remote transaction to be accepted.
+	Full bool
+	// CheapestFeeCap and CheapestTipCap are the prices of the cheapest remote transaction,
+	// which is the next to be evicted when the pool is full, or nil if there is none.
+	CheapestFeeCap *big.Int
+	CheapestTipCap *big.Int
+
+	// Drops counts the transactions dropped by the pool since it started by reason.
+	Drops map[DropReason]uint64
+}
+
+// PriceDiagnostics returns the current prices required by the pool and the number
+// of transactions it has dropped by reason.
+func (pool *TxPool) PriceDiagnostics() *PriceDiagnostics {
+	// Looking up the cheapest transaction may discard stale entries of the priced list,
+	// so the write lock is required.
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	diagnostics := &PriceDiagnostics{
+		GasTip: new(big.Int).Set(pool.gasPrice),
+		Full:   uint64(pool.all.Slots()) >= pool.config.GlobalSlots+pool.config.GlobalQueue,
+		Drops:  pool.drops.copy(),
+	}
+	if pool.minimumFee != nil {
+		diagnostics.MinimumFee = new(big.Int).Set(pool.minimumFee)
+	}
+	if baseFee := pool.priced.urgent.baseFee; baseFee != nil {
+		diagnostics.BaseFee = new(big.Int).Set(baseFee)
+	}
+	diagnostics.MinExecutableFeeCap = pool.minExecutableFeeCap()
+	if cheapest := pool.priced.Cheapest(); cheapest != nil {
+		diagnostics.CheapestFeeCap = cheapest.GasFeeCap()
+		diagnostics.CheapestTipCap = cheapest.GasTipCap()
+	}
+	return diagnostics
+}
+
+// minExecutableFeeCap returns the minimum gas fee cap of a transaction accepted by the
+// pool that is executable in the next block. Assumes the lock is held.
+func (pool *TxPool) minExecutableFeeCap() *big.Int {
+	minFeeCap := new(big.Int)
+	for _, price := range []*big.Int{pool.minimumFee, pool.priced.urgent.baseFee} {
+		if price != nil && price.Cmp(minFeeCap) > 0 {
+			minFeeCap.Set(price)
+		}
+	}
+	return minFeeCap
+}
@@ -0,0 +1,77 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Tests that the price diagnostics report the cheapest remote transaction of a full
+// pool and count underpriced rejections and evictions.
+func TestPriceDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockchain(statedb, 1000000, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 1
+	config.GlobalQueue = 1
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	if diagnostics := pool.PriceDiagnostics(); diagnostics.Full || diagnostics.CheapestFeeCap != nil || len(diagnostics.Drops) != 0 {
+		t.Fatalf("unexpected diagnostics of empty pool: %+v", diagnostics)
+	}
+
+	// Fill the pool
+	for i, price := range []int64{1, 2} {
+		if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(price), keys[i])); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	diagnostics := pool.PriceDiagnostics()
+	if !diagnostics.Full {
+		t.Fatalf("pool should be full")
+	}
+	if diagnostics.CheapestFeeCap.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("cheapest fee cap mismatch: have %d, want %d", diagnostics.CheapestFeeCap, 1)
+	}
+
+	// Underpriced transactions are rejected and better priced ones evict the cheapest
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(1), keys[2])); err != ErrUnderpriced {
+		t.Fatalf("adding underpriced transaction error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(3), keys[2])); err != nil {
+		t.Fatalf("failed to add well priced transaction: %v", err)
+	}
+	diagnostics = pool.PriceDiagnostics()
+	if diagnostics.CheapestFeeCap.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("cheapest fee cap mismatch: have %d, want %d", diagnostics.CheapestFeeCap, 2)
+	}
+	if count := diagnostics.Drops[DropUnderpriced]; count != 1 {
+		t.Fatalf("underpriced count mismatch: have %d, want %d", count, 1)
+	}
+	if count := diagnostics.Drops[DropPriceEvicted]; count != 1 {
+		t.Fatalf("price eviction count mismatch: have %d, want %d", count, 1)
+	}
+	if diagnostics.BaseFee != nil && diagnostics.MinExecutableFeeCap.Cmp(diagnostics.BaseFee) < 0 {
+		t.Fatalf("minimum executable fee cap %d below base fee %d", diagnostics.MinExecutableFeeCap, diagnostics.BaseFee)
+	}
+}
@@ -267,17 +267,47 @@ type list struct {
 	costcap   *big.Int // Price of the highest costing transaction (reset only if exceeds balance)
 	gascap    uint64   // Gas limit of the highest spending transaction (reset only if exceeds block limit)
 	totalcost *big.Int // Total cost of all transactions in the list
+
+	// cost returns the cost of a transaction to its sender, which excludes gas
+	// sponsored by the gas sponsor precompile, given the gas already sponsored for
+	// the other transactions in the list and the gas cost the sponsor must already
+	// cover for other transactions, and whether its gas is sponsored. The cost of
+	// each transaction when it was added is kept in costs, so that totalcost stays
+	// consistent if the sponsorship changes while it is in the list.
+	cost  func(tx *types.Transaction, sponsoredGas uint64, sponsoredCost *big.Int) (*big.Int, bool)
+	costs map[uint64]*big.Int
+
+	sponsored      map[uint64]uint64   // Gas limit of the sponsored transactions by nonce
+	sponsoredGas   uint64              // Total gas limit of the sponsored transactions
+	sponsoredCosts map[uint64]*big.Int // Maximum gas cost of the sponsored transactions by nonce
+	sponsoredCost  *big.Int            // Total gas cost of sponsored transactions, which may be shared between lists
 }
 
 // newList create a new transaction list for maintaining nonce-indexable fast,
 // gapped, sortable transaction lists.
 func newList(strict bool) *list {
 	return &list{
-		strict:    strict,
-		txs:       newSortedMap(),
-		costcap:   new(big.Int),
-		totalcost: new(big.Int),
+		strict:         strict,
+		txs:            newSortedMap(),
+		costcap:        new(big.Int),
+		totalcost:      new(big.Int),
+		cost:           func(tx *types.Transaction, _ uint64, _ *big.Int) (*big.Int, bool) { return tx.Cost(), false },
+		costs:          make(map[uint64]*big.Int),
+		sponsored:      make(map[uint64]uint64),
+		sponsoredCosts: make(map[uint64]*big.Int),
+		sponsoredCost:  new(big.Int),
+	}
+}
+
+// senderCost returns the cost of [tx] to its sender, and whether its gas is
+// sponsored, given the gas and gas cost sponsored for the other transactions.
+func (l *list) senderCost(tx *types.Transaction) (*big.Int, bool) {
+	nonce := tx.Nonce()
+	sponsoredCost := new(big.Int).Set(l.sponsoredCost)
+	if cost, ok := l.sponsoredCosts[nonce]; ok {
+		sponsoredCost.Sub(sponsoredCost, cost)
 	}
+	return l.cost(tx, l.sponsoredGas-l.sponsored[nonce], sponsoredCost)
 }
 
 // Overlaps returns whether the transaction specified has the same nonce as one
@@ -318,9 +348,20 @@ func (l *list) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Transa
 		l.subTotalCost([]*types.Transaction{old})
 	}
 	// Add new tx cost to totalcost
-	l.totalcost.Add(l.totalcost, tx.Cost())
+	cost, sponsored := l.senderCost(tx)
+	l.totalcost.Add(l.totalcost, cost)
+	l.costs[tx.Nonce()] = cost
+	if sponsored {
+		gasCost := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		l.sponsored[tx.Nonce()] = tx.Gas()
+		l.sponsoredGas += tx.Gas()
+		l.sponsoredCosts[tx.Nonce()] = gasCost
+		l.sponsoredCost.Add(l.sponsoredCost, gasCost)
+	}
 	// Otherwise overwrite the old transaction with the current one
 	l.txs.Put(tx)
+	// The cost cap bounds the cost of sponsored transactions too, in case their
+	// sponsorship ends, so that Filter does not skip them.
 	if cost := tx.Cost(); l.costcap.Cmp(cost) < 0 {
 		l.costcap = cost
 	}
@@ -358,7 +399,8 @@ func (l *list) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions,
 
 	// Filter out all the transactions above the account's funds
 	removed := l.txs.Filter(func(tx *types.Transaction) bool {
-		return tx.Gas() > gasLimit || tx.Cost().Cmp(costLimit) > 0
+		cost, _ := l.senderCost(tx)
+		return tx.Gas() > gasLimit || cost.Cmp(costLimit) > 0
 	})
 
 	if len(removed) == 0 {
@@ -449,7 +491,18 @@ func (l *list) LastElement() *types.Transaction {
 // total cost of all transactions.
 func (l *list) subTotalCost(txs []*types.Transaction) {
 	for _, tx := range txs {
-		l.totalcost.Sub(l.totalcost, tx.Cost())
+		if cost, ok := l.costs[tx.Nonce()]; ok {
+			l.totalcost.Sub(l.totalcost, cost)
+			delete(l.costs, tx.Nonce())
+		}
+		if gas, ok := l.sponsored[tx.Nonce()]; ok {
+			l.sponsoredGas -= gas
+			delete(l.sponsored, tx.Nonce())
+		}
+		if gasCost, ok := l.sponsoredCosts[tx.Nonce()]; ok {
+			l.sponsoredCost.Sub(l.sponsoredCost, gasCost)
+			delete(l.sponsoredCosts, tx.Nonce())
+		}
 	}
 }
 
@@ -595,6 +648,26 @@ func (l *pricedList) underpricedFor(h *priceHeap, tx *types.Transaction) bool {
 	return h.cmp(h.list[0], tx) >= 0
 }
 
+// Cheapest returns the remote transaction with the lowest gas fee cap among the
+// cheapest transactions of each heap, or nil if no remote transactions are tracked.
+func (l *pricedList) Cheapest() *types.Transaction {
+	var cheapest *types.Transaction
+	for _, h := range []*priceHeap{&l.urgent, &l.floating} {
+		// Discard stale price points if found at the heap start
+		for len(h.list) > 0 && l.all.GetRemote(h.list[0].Hash()) == nil {
+			atomic.AddInt64(&l.stales, -1)
+			heap.Pop(h)
+		}
+		if len(h.list) == 0 {
+			continue
+		}
+		if cheapest == nil || h.list[0].GasFeeCapCmp(cheapest) < 0 {
+			cheapest = h.list[0]
+		}
+	}
+	return cheapest
+}
+
 // Discard finds a number of most underpriced transactions, removes them from the
 // priced list and returns them for further removal from the entire pool.
 // If noPending is set to true, we will only consider the floating list
@@ -61,6 +61,47 @@ func TestStrictListAdd(t *testing.T) {
 	}
 }
 
+// Tests that lists sharing the sponsored gas cost only sponsor transactions while
+// the total stays within the sponsor budget, and release it on removal.
+func TestListSharedSponsoredCost(t *testing.T) {
+	var (
+		shared = new(big.Int)
+		budget = big.NewInt(150)
+	)
+	sponsoredList := func() *list {
+		l := newList(true)
+		l.sponsoredCost = shared
+		l.cost = func(tx *types.Transaction, _ uint64, sponsoredCost *big.Int) (*big.Int, bool) {
+			gasCost := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+			if new(big.Int).Add(sponsoredCost, gasCost).Cmp(budget) > 0 {
+				return tx.Cost(), false
+			}
+			return tx.Value(), true
+		}
+		return l
+	}
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	list1, list2 := sponsoredList(), sponsoredList()
+
+	tx1, tx2 := transaction(0, 100, key1), transaction(0, 100, key2)
+	list1.Add(tx1, DefaultConfig.PriceBump)
+	list2.Add(tx2, DefaultConfig.PriceBump)
+	if _, ok := list1.sponsored[0]; !ok {
+		t.Fatalf("first transaction not sponsored")
+	}
+	if _, ok := list2.sponsored[0]; ok {
+		t.Fatalf("transaction exceeding the sponsor budget sponsored")
+	}
+	if shared.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("shared sponsored cost mismatch: have %d, want %d", shared, 100)
+	}
+	list1.Remove(tx1)
+	if shared.Sign() != 0 {
+		t.Fatalf("shared sponsored cost not released: have %d", shared)
+	}
+}
+
 func BenchmarkListAdd(b *testing.B) {
 	// Generate a list of transactions to insert
 	key, _ := crypto.GenerateKey()
@@ -37,6 +37,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/consensus/dummy"
 	"github.com/ava-labs/subnet-evm/core"
@@ -45,6 +46,7 @@ import (
 	"github.com/ava-labs/subnet-evm/metrics"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/gassponsor"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
 	"github.com/ava-labs/subnet-evm/utils"
 	"github.com/ava-labs/subnet-evm/vmerrs"
@@ -114,6 +116,10 @@ var (
 	// ErrOverdraft is returned if a transaction would cause the senders balance to go negative
 	// thus invalidating a potential large number of transactions.
 	ErrOverdraft = errors.New("transaction would cause overdraft")
+
+	// ErrNonceGapTooLarge is returned if a remote transaction's nonce is further ahead
+	// of the sender's next executable nonce than the configured maximum nonce gap.
+	ErrNonceGapTooLarge = errors.New("nonce gap too large")
 )
 
 var (
@@ -135,6 +141,11 @@ var (
 	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
+	queuedNonceGapMeter  = metrics.NewRegisteredMeter("txpool/queued/noncegap", nil)  // Rejected due to nonce gap
+
+	// Metrics for price based eviction
+	priceEvictionMeter      = metrics.NewRegisteredMeter("txpool/priced/eviction", nil)
+	minExecutablePriceGauge = metrics.NewRegisteredGauge("txpool/priced/minexecutable", nil)
 
 	// General tx metrics
 	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
@@ -196,7 +207,8 @@ type Config struct {
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
-	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+	Lifetime    time.Duration // Maximum amount of time non-executable transaction are queued
+	MaxNonceGap uint64        // Maximum distance of a remote transaction's nonce ahead of the account's next executable nonce (0 = unlimited)
 }
 
 // DefaultConfig contains the default configurations for the transaction
@@ -269,6 +281,8 @@ type TxPool struct {
 	gasPrice    *big.Int
 	minimumFee  *big.Int
 	txFeed      event.Feed
+	publicFeed  event.Feed
+	bundleFeed  event.Feed
 	headFeed    event.Feed
 	reorgFeed   event.Feed
 	scope       event.SubscriptionScope
@@ -299,7 +313,11 @@ type TxPool struct {
 	queue   map[common.Address]*list     // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *lookup                      // All transactions to allow lookups
+	private map[common.Hash]struct{}     // Transactions that must not be gossiped or announced
+	sponsor *big.Int                     // Gas cost of the sponsored pending transactions
+	bundles []*Bundle                    // Bundles waiting to be built into a block
 	priced  *pricedList                  // All transactions sorted by price
+	drops   *dropCounter                 // Transactions dropped from the pool by reason
 
 	chainHeadCh         chan core.ChainHeadEvent
 	chainHeadSub        event.Subscription
@@ -314,6 +332,8 @@ type TxPool struct {
 	initDoneCh chan struct{}  // is closed once the pool is initialized (for tests)
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
+
+	clock *mockable.Clock // Decides when the base fee is estimated, allows tests to fast-forward time
 }
 
 type txpoolResetRequest struct {
@@ -323,6 +343,12 @@ type txpoolResetRequest struct {
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
 // transactions from the network.
 func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain) *TxPool {
+	return NewTxPoolWithClock(config, chainconfig, chain, &mockable.Clock{})
+}
+
+// NewTxPoolWithClock creates a new transaction pool that estimates the base fee
+// of the next block according to [clock].
+func NewTxPoolWithClock(config Config, chainconfig *params.ChainConfig, chain blockChain, clock *mockable.Clock) *TxPool {
 	// Sanitize the input to ensure no vulnerable gas prices are set
 	config = (&config).sanitize()
 
@@ -336,6 +362,8 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 		queue:               make(map[common.Address]*list),
 		beats:               make(map[common.Address]time.Time),
 		all:                 newLookup(),
+		private:             make(map[common.Hash]struct{}),
+		sponsor:             new(big.Int),
 		chainHeadCh:         make(chan core.ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:          make(chan *txpoolResetRequest),
 		reqPromoteCh:        make(chan *accountSet),
@@ -345,6 +373,7 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 		initDoneCh:          make(chan struct{}),
 		generalShutdownChan: make(chan struct{}),
 		gasPrice:            new(big.Int).SetUint64(config.PriceLimit),
+		clock:               clock,
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -352,6 +381,7 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 		pool.locals.add(addr)
 	}
 	pool.priced = newPricedList(pool.all)
+	pool.drops = newDropCounter()
 	pool.reset(nil, chain.CurrentBlock())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -443,6 +473,7 @@ func (pool *TxPool) loop() {
 						pool.removeTx(tx.Hash(), true)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
+					pool.drops.add(DropLifetime, int64(len(list)))
 				}
 			}
 			pool.mu.Unlock()
@@ -482,6 +513,12 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subsc
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeNewPublicTxsEvent registers a subscription of NewTxsEvent and starts
+// sending events to the given channel for the transactions that are not private.
+func (pool *TxPool) SubscribeNewPublicTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.publicFeed.Subscribe(ch))
+}
+
 // SubscribeNewHeadEvent registers a subscription of NewHeadEvent and
 // starts sending event to the given channel.
 func (pool *TxPool) SubscribeNewHeadEvent(ch chan<- core.NewTxPoolHeadEvent) event.Subscription {
@@ -528,6 +565,7 @@ func (pool *TxPool) SetMinFee(minFee *big.Int) {
 	defer pool.mu.Unlock()
 
 	pool.minimumFee = minFee
+	minExecutablePriceGauge.Update(pool.minExecutableFeeCap().Int64())
 }
 
 // Nonce returns the next nonce of an account, with all transactions executable
@@ -668,13 +706,17 @@ func (pool *TxPool) PendingFrom(addrs []common.Address, enforceTips bool) map[co
 }
 
 // IteratePending iterates over [pool.pending] until [f] returns false.
-// The caller must not modify [tx].
+// The caller must not modify [tx]. Private transactions are skipped, since
+// they must not be gossiped.
 func (pool *TxPool) IteratePending(f func(tx *types.Transaction) bool) {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
 	for _, list := range pool.pending {
 		for _, tx := range list.txs.items {
+			if _, ok := pool.private[tx.Hash()]; ok {
+				continue
+			}
 			if !f(tx) {
 				return
 			}
@@ -706,6 +748,74 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// newList creates a list for the transactions of [from], charging them the cost
+// of their transactions as determined by [pool.senderCost]. The lists of pending
+// transactions share the total gas cost the sponsor must cover.
+func (pool *TxPool) newList(from common.Address, strict bool) *list {
+	l := newList(strict)
+	l.cost = func(tx *types.Transaction, sponsoredGas uint64, sponsoredCost *big.Int) (*big.Int, bool) {
+		// Queued transactions are checked against the gas cost of the pending ones.
+		if !strict {
+			sponsoredCost = pool.sponsor
+		}
+		return pool.senderCost(from, tx, sponsoredGas, sponsoredCost)
+	}
+	if strict {
+		l.sponsoredCost = pool.sponsor
+	}
+	return l
+}
+
+// senderCost returns the cost of [tx] to [from] under the current state, which
+// excludes gas paid for by the gas sponsor precompile, and whether its gas is
+// sponsored. [sponsoredGas] is the gas of other pending transactions of [from]
+// that counts against its sponsored gas quota, and [sponsoredCost] the gas cost
+// of the other pending transactions the sponsor must cover.
+//
+// The gas of [tx] is only sponsored if the sponsor balance covers the gas cost
+// of every sponsored pending transaction, so that senders without funds cannot
+// fill the pool with transactions the sponsor cannot pay for. Once the balance
+// no longer covers them, the transactions are charged to their senders again and
+// dropped by demoteUnexecutables if their senders cannot pay.
+func (pool *TxPool) senderCost(from common.Address, tx *types.Transaction, sponsoredGas uint64, sponsoredCost *big.Int) (*big.Int, bool) {
+	to := tx.To()
+	// The sponsor does not pay tips, so only transactions without a tip are sponsored.
+	if to == nil || tx.GasTipCap().Sign() != 0 || !pool.rules.IsPrecompileEnabled(gassponsor.ContractAddress) {
+		return tx.Cost(), false
+	}
+	gasCost := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+	if !gassponsor.CanSponsor(pool.currentState, from, *to, pool.currentHead.Time, tx.Gas(), sponsoredGas, new(big.Int).Add(sponsoredCost, gasCost)) {
+		return tx.Cost(), false
+	}
+	return tx.Value(), true
+}
+
+// pendingSponsored returns the gas of the pending transactions of [from] that
+// counts against its sponsored gas quota and the gas cost of the sponsored
+// pending transactions, both excluding a transaction from [from] with [nonce].
+func (pool *TxPool) pendingSponsored(from common.Address, nonce uint64) (uint64, *big.Int) {
+	list := pool.pending[from]
+	if list == nil {
+		return 0, new(big.Int).Set(pool.sponsor)
+	}
+	sponsoredCost := new(big.Int).Set(pool.sponsor)
+	if cost, ok := list.sponsoredCosts[nonce]; ok {
+		sponsoredCost.Sub(sponsoredCost, cost)
+	}
+	return list.sponsoredGas - list.sponsored[nonce], sponsoredCost
+}
+
+// isSponsored returns true if the gas of [tx] from [from] would be sponsored under
+// the current state.
+func (pool *TxPool) isSponsored(from common.Address, tx *types.Transaction) bool {
+	pool.currentStateLock.Lock()
+	defer pool.currentStateLock.Unlock()
+
+	sponsoredGas, sponsoredCost := pool.pendingSponsored(from, tx.Nonce())
+	_, sponsored := pool.senderCost(from, tx, sponsoredGas, sponsoredCost)
+	return sponsored
+}
+
 // checks transaction validity against the current state.
 func (pool *TxPool) checkTxState(from common.Address, tx *types.Transaction) error {
 	pool.currentStateLock.Lock()
@@ -718,19 +828,21 @@ func (pool *TxPool) checkTxState(from common.Address, tx *types.Transaction) err
 			core.ErrNonceTooLow, from.Hex(), currentNonce, txNonce)
 	}
 
-	// cost == V + GP * GL
+	// cost == V + GP * GL, or V if the gas is sponsored
 	balance := pool.currentState.GetBalance(from)
-	if balance.Cmp(tx.Cost()) < 0 {
-		return fmt.Errorf("%w: address %s have (%d) want (%d)", core.ErrInsufficientFunds, from.Hex(), balance, tx.Cost())
+	sponsoredGas, sponsoredCost := pool.pendingSponsored(from, txNonce)
+	cost, _ := pool.senderCost(from, tx, sponsoredGas, sponsoredCost)
+	if balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w: address %s have (%d) want (%d)", core.ErrInsufficientFunds, from.Hex(), balance, cost)
 	}
 
 	// Verify that replacing transactions will not result in overdraft
 	list := pool.pending[from]
 	if list != nil { // Sender already has pending txs
-		sum := new(big.Int).Add(tx.Cost(), list.totalcost)
+		sum := new(big.Int).Add(cost, list.totalcost)
 		if repl := list.txs.Get(tx.Nonce()); repl != nil {
 			// Deduct the cost of a transaction replaced by this
-			sum.Sub(sum, repl.Cost())
+			sum.Sub(sum, list.costs[repl.Nonce()])
 		}
 		if balance.Cmp(sum) < 0 {
 			log.Trace("Replacing transactions would overdraft", "sender", from, "balance", pool.currentState.GetBalance(from), "required", sum)
@@ -740,7 +852,7 @@ func (pool *TxPool) checkTxState(from common.Address, tx *types.Transaction) err
 
 	// If the tx allow list is enabled, return an error if the from address is not allow listed.
 	if pool.rules.IsPrecompileEnabled(txallowlist.ContractAddress) {
-		txAllowListRole := txallowlist.GetTxAllowListStatus(pool.currentState, from)
+		txAllowListRole := txallowlist.GetTxAllowListStatusAt(pool.currentState, from, pool.currentHead.Time)
 		if !txAllowListRole.IsEnabled() {
 			return fmt.Errorf("%w: %s", vmerrs.ErrSenderAddressNotAllowListed, from)
 		}
@@ -793,8 +905,9 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err != nil {
 		return ErrInvalidSender
 	}
-	// Drop non-local transactions under our own minimal accepted gas price or tip
-	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
+	// Drop non-local transactions under our own minimal accepted gas price or tip,
+	// unless their gas is sponsored, which requires them to pay no tip.
+	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 && !pool.isSponsored(from, tx) {
 		return fmt.Errorf("%w: address %s have gas tip cap (%d) < pool gas tip cap (%d)", ErrUnderpriced, from.Hex(), tx.GasTipCap(), pool.gasPrice)
 	}
 	// Drop the transaction if the gas fee cap is below the pool's minimum fee
@@ -807,6 +920,15 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err := pool.checkTxState(from, tx); err != nil {
 		return err
 	}
+	// Drop non-local transactions that would leave too large a nonce gap
+	if !local && pool.config.MaxNonceGap > 0 {
+		if next := pool.pendingNonces.get(from); tx.Nonce() > next+pool.config.MaxNonceGap {
+			queuedNonceGapMeter.Mark(1)
+			pool.drops.add(DropNonceGap, 1)
+			return fmt.Errorf("%w: address %s next nonce (%d) tx nonce (%d) max gap (%d)",
+				ErrNonceGapTooLarge, from.Hex(), next, tx.Nonce(), pool.config.MaxNonceGap)
+		}
+	}
 	// Ensure the transaction has more gas than the basic tx fee.
 	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, pool.rules)
 	if err != nil {
@@ -853,6 +975,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		if !isLocal && pool.priced.Underpriced(tx) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
+			pool.drops.add(DropUnderpriced, 1)
 			return false, ErrUnderpriced
 		}
 
@@ -862,6 +985,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		// replacements to 25% of the slots
 		if pool.changesSinceReorg > int(pool.config.GlobalSlots/4) {
 			throttleTxMeter.Mark(1)
+			pool.drops.add(DropThrottled, 1)
 			return false, ErrTxPoolOverflow
 		}
 
@@ -874,6 +998,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		if !isLocal && !success {
 			log.Trace("Discarding overflown transaction", "hash", hash)
 			overflowedTxMeter.Mark(1)
+			pool.drops.add(DropOverflowed, 1)
 			return false, ErrTxPoolOverflow
 		}
 
@@ -901,6 +1026,8 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
+			priceEvictionMeter.Mark(1)
+			pool.drops.add(DropPriceEvicted, 1)
 			dropped := pool.removeTx(tx.Hash(), false)
 			pool.changesSinceReorg += dropped
 		}
@@ -919,6 +1046,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.drops.add(DropReplaced, 1)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
@@ -971,7 +1099,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 	// Try to insert the transaction into the future queue
 	from, _ := types.Sender(pool.signer, tx) // already validated
 	if pool.queue[from] == nil {
-		pool.queue[from] = newList(false)
+		pool.queue[from] = pool.newList(from, false)
 	}
 	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump)
 	if !inserted {
@@ -984,6 +1112,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.drops.add(DropReplaced, 1)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -1023,7 +1152,7 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.Transaction) bool {
 	// Try to insert the transaction into the pending queue
 	if pool.pending[addr] == nil {
-		pool.pending[addr] = newList(true)
+		pool.pending[addr] = pool.newList(addr, true)
 	}
 	list := pool.pending[addr]
 
@@ -1040,6 +1169,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.drops.add(DropReplaced, 1)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -1058,7 +1188,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 // This method is used to add transactions from the RPC API and performs synchronous pool
 // reorganization and event propagation.
 func (pool *TxPool) AddLocals(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, !pool.config.NoLocals, true)
+	return pool.addTxs(txs, !pool.config.NoLocals, false, true)
 }
 
 // AddLocal enqueues a single local transaction into the pool if it is valid. This is
@@ -1068,18 +1198,37 @@ func (pool *TxPool) AddLocal(tx *types.Transaction) error {
 	return errs[0]
 }
 
+// AddPrivate enqueues a single local transaction into the pool if it is valid, and
+// marks it as private. Private transactions are included in blocks built by this
+// node, but are never gossiped to peers or sent to subscribers of
+// SubscribeNewPublicTxsEvent.
+func (pool *TxPool) AddPrivate(tx *types.Transaction) error {
+	errs := pool.addTxs([]*types.Transaction{tx}, !pool.config.NoLocals, true, true)
+	return errs[0]
+}
+
+// IsPrivate returns whether the transaction with the given hash was added with
+// AddPrivate and is still in the pool.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	_, ok := pool.private[hash]
+	return ok
+}
+
 // AddRemotes enqueues a batch of transactions into the pool if they are valid. If the
 // senders are not among the locally tracked ones, full pricing constraints will apply.
 //
 // This method is used to add transactions from the p2p network and does not wait for pool
 // reorganization and internal event propagation.
 func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, false, false)
+	return pool.addTxs(txs, false, false, false)
 }
 
 // AddRemotesSync is like AddRemotes, but waits for pool reorganization. Tests use this method.
 func (pool *TxPool) AddRemotesSync(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, false, true)
+	return pool.addTxs(txs, false, false, true)
 }
 
 // This is like AddRemotes with a single transaction, but waits for pool reorganization. Tests use this method.
@@ -1097,8 +1246,9 @@ func (pool *TxPool) AddRemote(tx *types.Transaction) error {
 	return errs[0]
 }
 
-// addTxs attempts to queue a batch of transactions if they are valid.
-func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
+// addTxs attempts to queue a batch of transactions if they are valid. If [private]
+// is set, the new transactions are marked as private.
+func (pool *TxPool) addTxs(txs []*types.Transaction, local, private, sync bool) []error {
 	// Filter out known ones without obtaining the pool lock or recovering signatures
 	var (
 		errs = make([]error, len(txs))
@@ -1129,7 +1279,21 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
+	if private {
+		// Mark the transactions before adding them, so that they are never
+		// announced as public.
+		for _, tx := range news {
+			pool.private[tx.Hash()] = struct{}{}
+		}
+	}
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	if private {
+		for i, err := range newErrs {
+			if err != nil {
+				delete(pool.private, news[i].Hash())
+			}
+		}
+	}
 	pool.mu.Unlock()
 
 	var nilSlot = 0
@@ -1425,6 +1589,13 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	pool.truncatePending()
 	pool.truncateQueue()
 
+	// Forget private transactions that are no longer in the pool.
+	for hash := range pool.private {
+		if pool.all.Get(hash) == nil {
+			delete(pool.private, hash)
+		}
+	}
+
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
 	pool.mu.Unlock()
@@ -1447,7 +1618,24 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 			txs = append(txs, set.Flatten()...)
 		}
 		pool.txFeed.Send(core.NewTxsEvent{Txs: txs})
+		if public := pool.FilterPublic(txs); len(public) > 0 {
+			pool.publicFeed.Send(core.NewTxsEvent{Txs: public})
+		}
+	}
+}
+
+// FilterPublic returns the transactions in [txs] that are not private.
+func (pool *TxPool) FilterPublic(txs []*types.Transaction) []*types.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	public := make([]*types.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if _, ok := pool.private[tx.Hash()]; !ok {
+			public = append(public, tx)
+		}
 	}
+	return public
 }
 
 // reset retrieves the current state of the blockchain and ensures the content
@@ -1589,6 +1777,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
+		pool.drops.add(DropNoFunds, int64(len(drops)))
 
 		// Gather all executable transactions and promote them
 		readies := list.Ready(pool.pendingNonces.get(addr))
@@ -1611,6 +1800,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
+			pool.drops.add(DropRateLimit, int64(len(caps)))
 		}
 		// Mark all the items dropped as removed
 		pool.priced.Removed(len(forwards) + len(drops) + len(caps))
@@ -1712,6 +1902,7 @@ func (pool *TxPool) truncatePending() {
 		}
 	}
 	pendingRateLimitMeter.Mark(int64(pendingBeforeCap - pending))
+	pool.drops.add(DropRateLimit, int64(pendingBeforeCap-pending))
 }
 
 // truncateQueue drops the oldest transactions in the queue if the pool is above the global queue limit.
@@ -1747,6 +1938,7 @@ func (pool *TxPool) truncateQueue() {
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
+			pool.drops.add(DropRateLimit, int64(size))
 			continue
 		}
 		// Otherwise drop only last few transactions
@@ -1755,6 +1947,7 @@ func (pool *TxPool) truncateQueue() {
 			pool.removeTx(txs[i].Hash(), true)
 			drop--
 			queuedRateLimitMeter.Mark(1)
+			pool.drops.add(DropRateLimit, 1)
 		}
 	}
 }
@@ -1789,6 +1982,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
+		pool.drops.add(DropNoFunds, int64(len(drops)))
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
@@ -1827,7 +2021,7 @@ func (pool *TxPool) startPeriodicFeeUpdate() {
 
 	// Call updateBaseFee here to ensure that there is not a [baseFeeUpdateInterval] delay
 	// when starting up in Subnet EVM before the base fee is updated.
-	if time.Now().After(utils.Uint64ToTime(pool.chainconfig.SubnetEVMTimestamp)) {
+	if pool.clock.Time().After(utils.Uint64ToTime(pool.chainconfig.SubnetEVMTimestamp)) {
 		pool.updateBaseFee()
 	}
 
@@ -1840,7 +2034,7 @@ func (pool *TxPool) periodicBaseFeeUpdate() {
 
 	// Sleep until its time to start the periodic base fee update or the tx pool is shutting down
 	select {
-	case <-time.After(time.Until(utils.Uint64ToTime(pool.chainconfig.SubnetEVMTimestamp))):
+	case <-time.After(utils.Uint64ToTime(pool.chainconfig.SubnetEVMTimestamp).Sub(pool.clock.Time())):
 	case <-pool.generalShutdownChan:
 		return // Return early if shutting down
 	}
@@ -1873,11 +2067,12 @@ func (pool *TxPool) updateBaseFeeAt(head *types.Header) error {
 	if err != nil {
 		return err
 	}
-	_, baseFeeEstimate, err := dummy.EstimateNextBaseFee(pool.chainconfig, feeConfig, head, uint64(time.Now().Unix()))
+	_, baseFeeEstimate, err := dummy.EstimateNextBaseFee(pool.chainconfig, feeConfig, head, pool.clock.Unix())
 	if err != nil {
 		return err
 	}
 	pool.priced.SetBaseFee(baseFeeEstimate)
+	minExecutablePriceGauge.Update(pool.minExecutableFeeCap().Int64())
 	return nil
 }
 
@@ -39,6 +39,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/core/rawdb"
@@ -191,6 +192,42 @@ func setupPoolWithConfig(config *params.ChainConfig) (*TxPool, *ecdsa.PrivateKey
 	return pool, key
 }
 
+// Tests that the pool estimates the base fee once its clock passes the SubnetEVM
+// activation timestamp, regardless of the system time.
+func TestBaseFeeUpdateWithClock(t *testing.T) {
+	t.Parallel()
+
+	activation := uint64(time.Now().Add(time.Hour).Unix())
+	config := *params.TestChainConfig
+	config.SubnetEVMTimestamp = utils.NewUint64(activation)
+
+	for name, tt := range map[string]struct {
+		now         uint64
+		wantBaseFee bool
+	}{
+		"before activation": {now: activation - 1, wantBaseFee: false},
+		"after activation":  {now: activation + 1, wantBaseFee: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			blockchain := newTestBlockchain(statedb, 10000000, new(event.Feed))
+
+			clock := &mockable.Clock{}
+			clock.Set(time.Unix(int64(tt.now), 0))
+			pool := NewTxPoolWithClock(testTxPoolConfig, &config, blockchain, clock)
+			defer pool.Stop()
+			<-pool.initDoneCh
+
+			pool.mu.RLock()
+			baseFee := pool.priced.urgent.baseFee
+			pool.mu.RUnlock()
+			if got := baseFee != nil; got != tt.wantBaseFee {
+				t.Fatalf("base fee set mismatch: have %v, want %v", got, tt.wantBaseFee)
+			}
+		})
+	}
+}
+
 // validatePoolInternals checks various consistency invariants within the pool.
 func validatePoolInternals(pool *TxPool) error {
 	pool.mu.RLock()
@@ -446,6 +483,67 @@ func TestNegativeValue(t *testing.T) {
 	}
 }
 
+// Tests that private transactions are announced to the block builder, but are not
+// announced to public subscribers or iterated for gossip.
+func TestPrivateTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	events := make(chan core.NewTxsEvent, 32)
+	sub := pool.SubscribeNewTxsEvent(events)
+	defer sub.Unsubscribe()
+	publicEvents := make(chan core.NewTxsEvent, 32)
+	publicSub := pool.SubscribeNewPublicTxsEvent(publicEvents)
+	defer publicSub.Unsubscribe()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	private := transaction(0, 100000, key)
+	if err := pool.AddPrivate(private); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	public := transaction(1, 100000, key)
+	if err := pool.AddLocal(public); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := validateEvents(events, 2); err != nil {
+		t.Fatalf("transaction event firing failed: %v", err)
+	}
+	if err := validateEvents(publicEvents, 1); err != nil {
+		t.Fatalf("public transaction event firing failed: %v", err)
+	}
+	if !pool.IsPrivate(private.Hash()) {
+		t.Errorf("private transaction not marked private")
+	}
+	if pool.IsPrivate(public.Hash()) {
+		t.Errorf("public transaction marked private")
+	}
+	var iterated []common.Hash
+	pool.IteratePending(func(tx *types.Transaction) bool {
+		iterated = append(iterated, tx.Hash())
+		return true
+	})
+	if len(iterated) != 1 || iterated[0] != public.Hash() {
+		t.Errorf("iterated pending transactions mismatch: have %v, want [%v]", iterated, public.Hash())
+	}
+	pending, _ := pool.ContentFrom(crypto.PubkeyToAddress(key.PublicKey))
+	if filtered := pool.FilterPublic(pending); len(filtered) != 1 || filtered[0] != public {
+		t.Errorf("public pending transactions mismatch: have %v, want [%v]", filtered, public.Hash())
+	}
+	// Re-adding a known transaction as private must not hide it.
+	if err := pool.AddPrivate(public); err != ErrAlreadyKnown {
+		t.Errorf("expected %v, got %v", ErrAlreadyKnown, err)
+	}
+	if pool.IsPrivate(public.Hash()) {
+		t.Errorf("known public transaction marked private")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 func TestTipAboveFeeCap(t *testing.T) {
 	t.Parallel()
 
@@ -913,6 +1011,46 @@ func TestQueueAccountLimiting(t *testing.T) {
 	}
 }
 
+// Tests that remote transactions too far ahead of the account's next executable
+// nonce are rejected when a maximum nonce gap is configured, while local ones are not.
+func TestQueueNonceGapLimiting(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockchain(statedb, 1000000, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.MaxNonceGap = 4
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	if err := pool.addRemoteSync(transaction(config.MaxNonceGap, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction at the maximum nonce gap: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(config.MaxNonceGap+1, 100000, key)); !errors.Is(err, ErrNonceGapTooLarge) {
+		t.Fatalf("adding transaction beyond the maximum nonce gap error mismatch: have %v, want %v", err, ErrNonceGapTooLarge)
+	}
+	// The gap is measured from the next executable nonce, which advances as the gap is filled
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add executable transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(config.MaxNonceGap+1, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction within the maximum nonce gap: %v", err)
+	}
+	// Local transactions are not limited
+	if err := pool.AddLocal(transaction(3*config.MaxNonceGap, 100000, key)); err != nil {
+		t.Fatalf("failed to add local transaction beyond the maximum nonce gap: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 3 {
+		t.Fatalf("pool stats mismatch: have %d pending, %d queued, want 1 pending, 3 queued", pending, queued)
+	}
+}
+
 // Tests that if the transaction count belonging to multiple accounts go above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 //
@@ -100,6 +100,27 @@ func (b Bloom) Test(topic []byte) bool {
 		v3 == v3&b[i3]
 }
 
+// BloomBits are the positions of the bits a topic sets in a bloom filter. They can be
+// derived once and tested against many blooms without rehashing the topic.
+type BloomBits struct {
+	i1, i2, i3 uint
+	v1, v2, v3 byte
+}
+
+// NewBloomBits returns the positions of the bits [topic] sets in a bloom filter.
+func NewBloomBits(topic []byte) BloomBits {
+	var bits BloomBits
+	bits.i1, bits.v1, bits.i2, bits.v2, bits.i3, bits.v3 = bloomValues(topic, make([]byte, 6))
+	return bits
+}
+
+// TestBits checks if the topic with the given bit positions is present in the bloom filter
+func (b Bloom) TestBits(bits BloomBits) bool {
+	return bits.v1 == bits.v1&b[bits.i1] &&
+		bits.v2 == bits.v2&b[bits.i2] &&
+		bits.v3 == bits.v3&b[bits.i3]
+}
+
 // MarshalText encodes b as a hex string with 0x prefix.
 func (b Bloom) MarshalText() ([]byte, error) {
 	return hexutil.Bytes(b[:]).MarshalText()
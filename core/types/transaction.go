@@ -609,6 +609,103 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 	heap.Pop(&t.heads)
 }
 
+// TxByTime implements both the sort and the heap interface, making it useful
+// for all at once sorting as well as individually adding and removing elements.
+type TxByTime []*TxWithMinerFee
+
+func (s TxByTime) Len() int { return len(s) }
+func (s TxByTime) Less(i, j int) bool {
+	// If the times are equal, use the hash for deterministic sorting
+	if s[i].Tx.time.Equal(s[j].Tx.time) {
+		return bytes.Compare(s[i].Tx.Hash().Bytes(), s[j].Tx.Hash().Bytes()) < 0
+	}
+	return s[i].Tx.time.Before(s[j].Tx.time)
+}
+func (s TxByTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *TxByTime) Push(x interface{}) {
+	*s = append(*s, x.(*TxWithMinerFee))
+}
+
+func (s *TxByTime) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*s = old[0 : n-1]
+	return x
+}
+
+// TransactionsByTimeAndNonce represents a set of transactions that can return
+// transactions in the order they were first seen, while supporting removing
+// entire batches of transactions for non-executable accounts.
+type TransactionsByTimeAndNonce struct {
+	txs     map[common.Address]Transactions // Per account nonce-sorted list of transactions
+	heads   TxByTime                        // Next transaction for each unique account (time heap)
+	signer  Signer                          // Signer for the set of transactions
+	baseFee *big.Int                        // Current base fee
+}
+
+// NewTransactionsByTimeAndNonce creates a transaction set that can retrieve
+// transactions sorted by the time they were first seen in a nonce-honouring way.
+// A transaction first seen before one of its account's lower nonce transactions
+// is ordered after it.
+//
+// Note, the input map is reowned so the caller should not interact any more with
+// if after providing it to the constructor.
+func NewTransactionsByTimeAndNonce(signer Signer, txs map[common.Address]Transactions, baseFee *big.Int) *TransactionsByTimeAndNonce {
+	// Initialize a received time based heap with the head transactions
+	heads := make(TxByTime, 0, len(txs))
+	for from, accTxs := range txs {
+		acc, _ := Sender(signer, accTxs[0])
+		wrapped, err := NewTxWithMinerFee(accTxs[0], baseFee)
+		// Remove transaction if sender doesn't match from, or if wrapping fails.
+		if acc != from || err != nil {
+			delete(txs, from)
+			continue
+		}
+		heads = append(heads, wrapped)
+		txs[from] = accTxs[1:]
+	}
+	heap.Init(&heads)
+
+	// Assemble and return the transaction set
+	return &TransactionsByTimeAndNonce{
+		txs:     txs,
+		heads:   heads,
+		signer:  signer,
+		baseFee: baseFee,
+	}
+}
+
+// Peek returns the next transaction by time.
+func (t *TransactionsByTimeAndNonce) Peek() *Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0].Tx
+}
+
+// Shift replaces the current earliest head with the next one from the same account.
+func (t *TransactionsByTimeAndNonce) Shift() {
+	acc, _ := Sender(t.signer, t.heads[0].Tx)
+	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
+		if wrapped, err := NewTxWithMinerFee(txs[0], t.baseFee); err == nil {
+			t.heads[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(&t.heads, 0)
+			return
+		}
+	}
+	heap.Pop(&t.heads)
+}
+
+// Pop removes the earliest transaction, *not* replacing it with the next one from
+// the same account. This should be used when a transaction cannot be executed
+// and hence all subsequent ones should be discarded from the same account.
+func (t *TransactionsByTimeAndNonce) Pop() {
+	heap.Pop(&t.heads)
+}
+
 // copyAddressPtr copies an address.
 func copyAddressPtr(a *common.Address) *common.Address {
 	if a == nil {
@@ -415,6 +415,56 @@ func TestTransactionTimeSort(t *testing.T) {
 	}
 }
 
+// Tests that if multiple transactions have different prices, but different
+// receive times, they are ordered by receive time while honouring nonces.
+func TestTransactionTimeNonceSort(t *testing.T) {
+	// Generate a batch of accounts to start with
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	signer := HomesteadSigner{}
+
+	// Generate a batch of transactions with increasing prices, but earlier creation
+	// times for lower prices. The second transaction of each account is seen first.
+	groups := map[common.Address]Transactions{}
+	for start, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		for nonce := uint64(0); nonce < 2; nonce++ {
+			tx, _ := SignTx(NewTransaction(nonce, common.Address{}, big.NewInt(100), 100, big.NewInt(int64(start+1)), nil), signer, key)
+			tx.time = time.Unix(0, int64(2*start+1-int(nonce)))
+			groups[addr] = append(groups[addr], tx)
+		}
+	}
+	// Sort the transactions and cross check the nonce ordering
+	txset := NewTransactionsByTimeAndNonce(signer, groups, nil)
+
+	txs := Transactions{}
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		txs = append(txs, tx)
+		txset.Shift()
+	}
+	if len(txs) != 2*len(keys) {
+		t.Errorf("expected %d transactions, found %d", 2*len(keys), len(txs))
+	}
+	nonces := make(map[common.Address]uint64)
+	for i, txi := range txs {
+		fromi, _ := Sender(signer, txi)
+		if txi.Nonce() != nonces[fromi] {
+			t.Errorf("invalid nonce ordering: tx #%d (A=%x N=%v) expected nonce %d", i, fromi[:4], txi.Nonce(), nonces[fromi])
+		}
+		nonces[fromi]++
+		// Make sure the prices of later accounts do not move their transactions ahead
+		if i+1 < len(txs) {
+			next := txs[i+1]
+			if txi.GasPrice().Cmp(next.GasPrice()) > 0 {
+				fromNext, _ := Sender(signer, next)
+				t.Errorf("invalid received time ordering: tx #%d (A=%x P=%v) before tx #%d (A=%x P=%v)", i, fromi[:4], txi.GasPrice(), i+1, fromNext[:4], next.GasPrice())
+			}
+		}
+	}
+}
+
 // TestTransactionCoding tests serializing/de-serializing to/from rlp and JSON.
 func TestTransactionCoding(t *testing.T) {
 	key, err := crypto.GenerateKey()
@@ -4,6 +4,8 @@
 package vm
 
 import (
+	"math/big"
+
 	"github.com/ava-labs/subnet-evm/precompile/contract"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -29,3 +31,23 @@ func (w *wrappedPrecompiledContract) Run(accessibleState contract.AccessibleStat
 func RunStatefulPrecompiledContract(precompile contract.StatefulPrecompiledContract, accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
 	return precompile.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
 }
+
+// payableAccessibleState extends the AccessibleState of a call with the value transferred
+// to the precompile, so that it can be read by payable precompile functions.
+type payableAccessibleState struct {
+	contract.AccessibleState
+	value *big.Int
+}
+
+// GetCallValue implements the PayableAccessibleState interface
+func (p *payableAccessibleState) GetCallValue() *big.Int {
+	return p.value
+}
+
+// withCallValue returns [accessibleState] extended with [value] if any value is transferred.
+func withCallValue(accessibleState contract.AccessibleState, value *big.Int) contract.AccessibleState {
+	if value == nil || value.Sign() == 0 {
+		return accessibleState
+	}
+	return &payableAccessibleState{AccessibleState: accessibleState, value: value}
+}
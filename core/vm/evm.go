@@ -46,8 +46,10 @@ import (
 )
 
 var (
-	_ contract.AccessibleState = &EVM{}
-	_ contract.BlockContext    = &BlockContext{}
+	_ contract.AccessibleState                 = &EVM{}
+	_ contract.ContractDeployerAccessibleState = &EVM{}
+	_ contract.ContractCallerAccessibleState   = &EVM{}
+	_ contract.BlockContext                    = &BlockContext{}
 )
 
 // IsProhibited returns true if [addr] is in the prohibited list of addresses which should
@@ -299,7 +301,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	}
 
 	if isPrecompile {
-		ret, gas, err = RunStatefulPrecompiledContract(p, evm, caller.Address(), addr, input, gas, evm.interpreter.readOnly)
+		ret, gas, err = RunStatefulPrecompiledContract(p, withCallValue(evm, value), caller.Address(), addr, input, gas, evm.interpreter.readOnly)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
@@ -529,10 +531,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if evm.StateDB.GetNonce(address) != 0 || (contractHash != (common.Hash{}) && contractHash != emptyCodeHash) {
 		return nil, common.Address{}, 0, vmerrs.ErrContractAddressCollision
 	}
-	// If the allow list is enabled, check that [evm.TxContext.Origin] has permission to deploy a contract.
-	if evm.chainRules.IsPrecompileEnabled(deployerallowlist.ContractAddress) {
-		allowListRole := deployerallowlist.GetContractDeployerAllowListStatus(evm.StateDB, evm.TxContext.Origin)
-		if !allowListRole.IsEnabled() {
+	// If the allow list is enabled, check that [evm.TxContext.Origin] has permission to deploy a contract,
+	// or that [caller] inherited the permission from an enabled deployer.
+	deployerConfig, deployerAllowListEnabled := evm.chainRules.ActivePrecompiles[deployerallowlist.ContractAddress].(*deployerallowlist.Config)
+	if deployerAllowListEnabled {
+		if !deployerallowlist.CanDeploy(evm.StateDB, deployerConfig, evm.TxContext.Origin, caller.Address()) {
 			return nil, common.Address{}, 0, fmt.Errorf("tx.origin %s is not authorized to deploy a contract", evm.TxContext.Origin)
 		}
 	}
@@ -540,6 +543,14 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	// Create a new account on the state
 	snapshot := evm.StateDB.Snapshot()
 	evm.StateDB.CreateAccount(address)
+	// Storing the inherited right to deploy contracts is paid for by the creation.
+	if deployerAllowListEnabled && deployerallowlist.RecordDeployment(evm.StateDB, deployerConfig, caller.Address(), address) {
+		if gas < deployerallowlist.RecordDeploymentGasCost {
+			evm.StateDB.RevertToSnapshot(snapshot)
+			return nil, common.Address{}, 0, vmerrs.ErrOutOfGas
+		}
+		gas -= deployerallowlist.RecordDeploymentGasCost
+	}
 	if evm.chainRules.IsEIP158 {
 		evm.StateDB.SetNonce(address, 1)
 	}
@@ -619,6 +630,16 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *
 	return evm.create(caller, codeAndHash, gas, endowment, contractAddr, CREATE2)
 }
 
+// DeployContract2 implements ContractDeployerAccessibleState
+func (evm *EVM) DeployContract2(deployer common.Address, initCode []byte, salt common.Hash, gas uint64) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	return evm.Create2(AccountRef(deployer), initCode, gas, new(big.Int), new(uint256.Int).SetBytes(salt.Bytes()))
+}
+
+// StaticCallContract implements ContractCallerAccessibleState
+func (evm *EVM) StaticCallContract(caller common.Address, addr common.Address, input []byte, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	return evm.StaticCall(AccountRef(caller), addr, input, gas)
+}
+
 // ChainConfig returns the environment's chain configuration
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 
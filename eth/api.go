@@ -27,6 +27,7 @@
 package eth
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -39,7 +40,9 @@ import (
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/state/snapshot"
 	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb"
 	"github.com/ava-labs/subnet-evm/internal/ethapi"
 	"github.com/ava-labs/subnet-evm/rpc"
 	"github.com/ava-labs/subnet-evm/trie"
@@ -223,6 +226,39 @@ func (api *DebugAPI) Preimage(ctx context.Context, hash common.Hash) (hexutil.By
 	return nil, errors.New("unknown preimage")
 }
 
+// TxPoolPriceDiagnosticsResult is the result of TxPoolPriceDiagnostics.
+type TxPoolPriceDiagnosticsResult struct {
+	GasTip              *hexutil.Big              `json:"gasTip"`
+	MinimumFee          *hexutil.Big              `json:"minimumFee,omitempty"`
+	BaseFee             *hexutil.Big              `json:"baseFee,omitempty"`
+	MinExecutableFeeCap *hexutil.Big              `json:"minExecutableFeeCap"`
+	Full                bool                      `json:"full"`
+	CheapestFeeCap      *hexutil.Big              `json:"cheapestFeeCap,omitempty"`
+	CheapestTipCap      *hexutil.Big              `json:"cheapestTipCap,omitempty"`
+	Drops               map[string]hexutil.Uint64 `json:"drops"`
+}
+
+// TxPoolPriceDiagnostics returns the prices currently required by the transaction pool
+// and how many transactions it has dropped by reason, to diagnose transactions that
+// disappeared from the pool.
+func (api *DebugAPI) TxPoolPriceDiagnostics() *TxPoolPriceDiagnosticsResult {
+	diagnostics := api.eth.TxPool().PriceDiagnostics()
+	result := &TxPoolPriceDiagnosticsResult{
+		GasTip:              (*hexutil.Big)(diagnostics.GasTip),
+		MinimumFee:          (*hexutil.Big)(diagnostics.MinimumFee),
+		BaseFee:             (*hexutil.Big)(diagnostics.BaseFee),
+		MinExecutableFeeCap: (*hexutil.Big)(diagnostics.MinExecutableFeeCap),
+		Full:                diagnostics.Full,
+		CheapestFeeCap:      (*hexutil.Big)(diagnostics.CheapestFeeCap),
+		CheapestTipCap:      (*hexutil.Big)(diagnostics.CheapestTipCap),
+		Drops:               make(map[string]hexutil.Uint64, len(diagnostics.Drops)),
+	}
+	for reason, count := range diagnostics.Drops {
+		result.Drops[string(reason)] = hexutil.Uint64(count)
+	}
+	return result
+}
+
 // GetBadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
 // and returns them as a JSON list of block hashes.
 func (api *DebugAPI) GetBadBlocks(ctx context.Context) ([]*ethapi.BadBlockArgs, error) {
@@ -233,40 +269,40 @@ func (api *DebugAPI) GetBadBlocks(ctx context.Context) ([]*ethapi.BadBlockArgs,
 // AccountRangeMaxResults is the maximum number of results to be returned per call
 const AccountRangeMaxResults = 256
 
-// AccountRange enumerates all accounts in the given block and start point in paging request
-func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (state.IteratorDump, error) {
-	var stateDb *state.StateDB
-	var err error
+// stateReexec is the number of blocks the debug state APIs are willing to
+// re-execute to regenerate state that has been pruned from the database.
+const stateReexec = uint64(128)
 
+// blockByNumberOrHash returns the block referenced by [blockNrOrHash].
+func (api *DebugAPI) blockByNumberOrHash(blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
 	if number, ok := blockNrOrHash.Number(); ok {
-		var header *types.Header
 		if number.IsAccepted() {
-			header = api.eth.LastAcceptedBlock().Header()
-		} else {
-			block := api.eth.blockchain.GetBlockByNumber(uint64(number))
-			if block == nil {
-				return state.IteratorDump{}, fmt.Errorf("block #%d not found", number)
-			}
-			header = block.Header()
+			return api.eth.LastAcceptedBlock(), nil
 		}
-		if header == nil {
-			return state.IteratorDump{}, fmt.Errorf("block #%d not found", number)
-		}
-		stateDb, err = api.eth.BlockChain().StateAt(header.Root)
-		if err != nil {
-			return state.IteratorDump{}, err
+		block := api.eth.blockchain.GetBlockByNumber(uint64(number))
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
 		}
-	} else if hash, ok := blockNrOrHash.Hash(); ok {
+		return block, nil
+	}
+	if hash, ok := blockNrOrHash.Hash(); ok {
 		block := api.eth.blockchain.GetBlockByHash(hash)
 		if block == nil {
-			return state.IteratorDump{}, fmt.Errorf("block %s not found", hash.Hex())
-		}
-		stateDb, err = api.eth.BlockChain().StateAt(block.Root())
-		if err != nil {
-			return state.IteratorDump{}, err
+			return nil, fmt.Errorf("block %s not found", hash.Hex())
 		}
-	} else {
-		return state.IteratorDump{}, errors.New("either block number or block hash must be specified")
+		return block, nil
+	}
+	return nil, errors.New("either block number or block hash must be specified")
+}
+
+// AccountRange enumerates all accounts in the given block and start point in paging request.
+// If the state of the block is covered by the snapshot, the accounts are read from the
+// snapshot layers. Otherwise, they are read from the state trie, which is regenerated by
+// re-executing recent blocks if it has been pruned.
+func (api *DebugAPI) AccountRange(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (state.IteratorDump, error) {
+	block, err := api.blockByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return state.IteratorDump{}, err
 	}
 
 	opts := &state.DumpConfig{
@@ -279,9 +315,95 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 	if maxResults > AccountRangeMaxResults || maxResults <= 0 {
 		opts.Max = AccountRangeMaxResults
 	}
+
+	if snaps := api.eth.blockchain.Snapshots(); snaps != nil && snaps.Snapshot(block.Root()) != nil {
+		dump, err := snapshotAccountRange(api.eth.ChainDb(), snaps, block.Root(), opts)
+		if err == nil {
+			return dump, nil
+		}
+		log.Debug("Falling back to trie for account range", "root", block.Root(), "err", err)
+	}
+
+	stateDb, release, err := api.eth.StateAtBlock(ctx, block, stateReexec, nil, true, false)
+	if err != nil {
+		return state.IteratorDump{}, err
+	}
+	defer release()
 	return stateDb.IteratorDump(opts), nil
 }
 
+// snapshotAccountRange collects up to [opts.Max] accounts starting at [opts.Start] from the
+// snapshot layers at [root]. Addresses and storage keys are resolved from the preimage store.
+func snapshotAccountRange(db ethdb.Database, snaps *snapshot.Tree, root common.Hash, opts *state.DumpConfig) (state.IteratorDump, error) {
+	it, err := snaps.AccountIterator(root, common.BytesToHash(opts.Start), false)
+	if err != nil {
+		return state.IteratorDump{}, err
+	}
+	defer it.Release()
+
+	dump := state.IteratorDump{
+		Root:     fmt.Sprintf("%x", root),
+		Accounts: make(map[common.Address]state.DumpAccount),
+	}
+	var accounts uint64
+	for it.Next() {
+		if accounts >= opts.Max {
+			dump.Next = it.Hash().Bytes()
+			break
+		}
+		accountHash := it.Hash()
+		data, err := snapshot.FullAccount(it.Account())
+		if err != nil {
+			return state.IteratorDump{}, err
+		}
+		addrBytes := rawdb.ReadPreimage(db, accountHash)
+		if addrBytes == nil && opts.OnlyWithAddresses {
+			continue
+		}
+		account := state.DumpAccount{
+			Balance:   data.Balance.String(),
+			Nonce:     data.Nonce,
+			Root:      data.Root,
+			CodeHash:  data.CodeHash,
+			SecureKey: accountHash.Bytes(),
+		}
+		if !opts.SkipCode && !bytes.Equal(data.CodeHash, types.EmptyCodeHash.Bytes()) {
+			account.Code = rawdb.ReadCode(db, common.BytesToHash(data.CodeHash))
+		}
+		if !opts.SkipStorage {
+			account.Storage = make(map[common.Hash]string)
+			storageIt, err := snaps.StorageIterator(root, accountHash, common.Hash{}, false)
+			if err != nil {
+				return state.IteratorDump{}, err
+			}
+			for storageIt.Next() {
+				_, content, _, err := rlp.Split(storageIt.Slot())
+				if err != nil {
+					storageIt.Release()
+					return state.IteratorDump{}, err
+				}
+				key := common.BytesToHash(rawdb.ReadPreimage(db, storageIt.Hash()))
+				account.Storage[key] = common.Bytes2Hex(content)
+			}
+			err = storageIt.Error()
+			storageIt.Release()
+			if err != nil {
+				return state.IteratorDump{}, err
+			}
+		}
+		// Accounts without a known address are counted towards the limit but cannot be
+		// included in the address keyed result, matching the trie based dump.
+		if addrBytes != nil {
+			dump.Accounts[common.BytesToAddress(addrBytes)] = account
+		}
+		accounts++
+	}
+	if err := it.Error(); err != nil {
+		return state.IteratorDump{}, err
+	}
+	return dump, nil
+}
+
 // StorageRangeResult is the result of a debug_storageRangeAt API call.
 type StorageRangeResult struct {
 	Storage storageMap   `json:"storage"`
@@ -302,7 +424,7 @@ func (api *DebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash,
 	if block == nil {
 		return StorageRangeResult{}, fmt.Errorf("block %#x not found", blockHash)
 	}
-	_, _, statedb, release, err := api.eth.stateAtTransaction(ctx, block, txIndex, 0)
+	_, _, statedb, release, err := api.eth.stateAtTransaction(ctx, block, txIndex, stateReexec)
 	if err != nil {
 		return StorageRangeResult{}, err
 	}
@@ -29,7 +29,9 @@ package eth
 import (
 	"context"
 	"errors"
+	"math"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/ava-labs/subnet-evm/accounts"
@@ -60,6 +62,10 @@ type EthAPIBackend struct {
 	allowUnprotectedTxHashes map[common.Hash]struct{} // Invariant: read-only after creation.
 	eth                      *Ethereum
 	gpo                      *gasprice.Oracle
+
+	// RPC caps are initialized from the config and can be adjusted at runtime.
+	rpcGasCap       atomic.Uint64
+	rpcTxFeeCapBits atomic.Uint64 // float64 bits of the tx fee cap
 }
 
 // ChainConfig returns the active chain configuration.
@@ -322,16 +328,35 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.eth.txPool.AddPrivate(signedTx)
+}
+
+func (b *EthAPIBackend) SendBundle(ctx context.Context, signedTxs types.Transactions) (common.Hash, error) {
+	if err := ctx.Err(); err != nil {
+		return common.Hash{}, err
+	}
+	return b.eth.txPool.AddBundle(signedTxs)
+}
+
+// Private transactions in the pool are not revealed to RPC clients.
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending := b.eth.txPool.Pending(false)
 	var txs types.Transactions
 	for _, batch := range pending {
 		txs = append(txs, batch...)
 	}
-	return txs, nil
+	return b.eth.txPool.FilterPublic(txs), nil
 }
 
 func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	if b.eth.txPool.IsPrivate(hash) {
+		return nil
+	}
 	return b.eth.txPool.Get(hash)
 }
 
@@ -363,15 +388,33 @@ func (b *EthAPIBackend) Stats() (pending int, queued int) {
 }
 
 func (b *EthAPIBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
-	return b.eth.txPool.Content()
+	pending, queued := b.eth.txPool.Content()
+	return b.filterPublicContent(pending), b.filterPublicContent(queued)
 }
 
 func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
-	return b.eth.txPool.ContentFrom(addr)
+	pending, queued := b.eth.txPool.ContentFrom(addr)
+	return b.eth.txPool.FilterPublic(pending), b.eth.txPool.FilterPublic(queued)
+}
+
+// filterPublicContent returns the public transactions of [content], leaving out the
+// accounts that only have private transactions.
+func (b *EthAPIBackend) filterPublicContent(content map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	public := make(map[common.Address]types.Transactions, len(content))
+	for addr, txs := range content {
+		if txs = b.eth.txPool.FilterPublic(txs); len(txs) > 0 {
+			public[addr] = txs
+		}
+	}
+	return public
 }
 
 func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
-	return b.eth.txPool.SubscribeNewTxsEvent(ch)
+	return b.eth.txPool.SubscribeNewPublicTxsEvent(ch)
+}
+
+func (b *EthAPIBackend) CurrentTime() time.Time {
+	return b.eth.clock.Time()
 }
 
 func (b *EthAPIBackend) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
@@ -394,6 +437,10 @@ func (b *EthAPIBackend) ChainDb() ethdb.Database {
 	return b.eth.ChainDb()
 }
 
+func (b *EthAPIBackend) TxUnindexing() bool {
+	return b.eth.blockchain.TxUnindexing()
+}
+
 func (b *EthAPIBackend) EventMux() *event.TypeMux {
 	return b.eth.EventMux()
 }
@@ -427,15 +474,37 @@ func (b *EthAPIBackend) UnprotectedAllowed(tx *types.Transaction) bool {
 }
 
 func (b *EthAPIBackend) RPCGasCap() uint64 {
-	return b.eth.config.RPCGasCap
+	return b.rpcGasCap.Load()
+}
+
+// SetRPCGasCap sets the gas cap for eth_call and eth_estimateGas (0 is unlimited).
+func (b *EthAPIBackend) SetRPCGasCap(gasCap uint64) {
+	b.rpcGasCap.Store(gasCap)
+}
+
+func (b *EthAPIBackend) RPCGasEstimateBuffer() (float64, uint64) {
+	return b.eth.config.RPCGasEstimateMultiplier, b.eth.config.RPCGasEstimatePadding
 }
 
 func (b *EthAPIBackend) RPCEVMTimeout() time.Duration {
 	return b.eth.config.RPCEVMTimeout
 }
 
+func (b *EthAPIBackend) RPCTraceTimeout() time.Duration {
+	return b.eth.config.RPCTraceTimeout
+}
+
 func (b *EthAPIBackend) RPCTxFeeCap() float64 {
-	return b.eth.config.RPCTxFeeCap
+	return math.Float64frombits(b.rpcTxFeeCapBits.Load())
+}
+
+// SetRPCTxFeeCap sets the fee cap (in ether) of transactions sent over RPC (0 is unlimited).
+func (b *EthAPIBackend) SetRPCTxFeeCap(feeCap float64) {
+	b.rpcTxFeeCapBits.Store(math.Float64bits(feeCap))
+}
+
+func (b *EthAPIBackend) HistoricalProofQueryWindow() uint64 {
+	return b.eth.config.HistoricalProofQueryWindow
 }
 
 func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
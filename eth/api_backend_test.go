@@ -71,3 +71,16 @@ func TestUnprotectedAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRPCCaps(t *testing.T) {
+	backend := &EthAPIBackend{}
+	backend.SetRPCGasCap(25_000_000)
+	backend.SetRPCTxFeeCap(1.5)
+	assert.Equal(t, uint64(25_000_000), backend.RPCGasCap())
+	assert.Equal(t, 1.5, backend.RPCTxFeeCap())
+
+	backend.SetRPCGasCap(0)
+	backend.SetRPCTxFeeCap(0)
+	assert.Zero(t, backend.RPCGasCap())
+	assert.Zero(t, backend.RPCTxFeeCap())
+}
@@ -104,6 +104,8 @@ type Ethereum struct {
 	stackRPCs []rpc.API
 
 	settings Settings // Settings for Ethereum API
+
+	clock *mockable.Clock // Decides the time of the pending block, allows tests to fast-forward time
 }
 
 // roundUpCacheSize returns [input] rounded up to the next multiple of [allocSize]
@@ -161,6 +163,7 @@ func New(
 		bloomIndexer:      core.NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 		settings:          settings,
 		shutdownTracker:   shutdowncheck.NewShutdownTracker(chainDb),
+		clock:             clock,
 	}
 
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
@@ -191,18 +194,21 @@ func New(
 			TrieDirtyCommitTarget:           config.TrieDirtyCommitTarget,
 			Pruning:                         config.Pruning,
 			AcceptorQueueLimit:              config.AcceptorQueueLimit,
+			AcceptorIndexingParallelism:     config.AcceptorIndexingParallelism,
 			CommitInterval:                  config.CommitInterval,
 			PopulateMissingTries:            config.PopulateMissingTries,
 			PopulateMissingTriesParallelism: config.PopulateMissingTriesParallelism,
 			AllowMissingTries:               config.AllowMissingTries,
 			SnapshotDelayInit:               config.SnapshotDelayInit,
 			SnapshotLimit:                   config.SnapshotCache,
+			SnapshotCacheJournal:            config.SnapshotCacheJournal,
 			SnapshotWait:                    config.SnapshotWait,
 			SnapshotVerify:                  config.SnapshotVerify,
 			SnapshotNoBuild:                 config.SkipSnapshotRebuild,
 			Preimages:                       config.Preimages,
 			AcceptedCacheSize:               config.AcceptedCacheSize,
 			TxLookupLimit:                   config.TxLookupLimit,
+			ReceiptCompression:              config.ReceiptCompression,
 		}
 	)
 
@@ -228,7 +234,7 @@ func New(
 	eth.bloomIndexer.Start(eth.blockchain)
 
 	config.TxPool.Journal = ""
-	eth.txPool = txpool.NewTxPool(config.TxPool, eth.blockchain.Config(), eth.blockchain)
+	eth.txPool = txpool.NewTxPoolWithClock(config.TxPool, eth.blockchain.Config(), eth.blockchain, clock)
 
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, clock)
 
@@ -243,11 +249,13 @@ func New(
 		allowUnprotectedTxHashes: allowUnprotectedTxHashes,
 		eth:                      eth,
 	}
+	eth.APIBackend.SetRPCGasCap(config.RPCGasCap)
+	eth.APIBackend.SetRPCTxFeeCap(config.RPCTxFeeCap)
 	if config.AllowUnprotectedTxs {
 		log.Info("Unprotected transactions allowed")
 	}
 	gpoParams := config.GPO
-	eth.APIBackend.gpo, err = gasprice.NewOracle(eth.APIBackend, gpoParams)
+	eth.APIBackend.gpo, err = gasprice.NewOracleWithClock(eth.APIBackend, gpoParams, clock)
 	if err != nil {
 		return nil, err
 	}
@@ -276,7 +284,11 @@ func (s *Ethereum) APIs() []rpc.API {
 
 	// Create [filterSystem] with the log cache size set in the config.
 	filterSystem := filters.NewFilterSystem(s.APIBackend, filters.Config{
-		Timeout: 5 * time.Minute,
+		Timeout:             5 * time.Minute,
+		LogsTimeout:         s.config.RPCLogsTimeout,
+		AcceptedEventBuffer: s.config.AcceptedEventBuffer,
+		ResumableFilterTTL:  s.config.RPCResumableFilterTTL,
+		MaxResumableFilters: s.config.RPCMaxResumableFilters,
 	})
 
 	// Append all the local APIs and return
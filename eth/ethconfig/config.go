@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/txpool"
 	"github.com/ava-labs/subnet-evm/eth/gasprice"
 	"github.com/ava-labs/subnet-evm/miner"
@@ -81,6 +82,8 @@ type Config struct {
 
 	Pruning                         bool    // Whether to disable pruning and flush everything to disk
 	AcceptorQueueLimit              int     // Maximum blocks to queue before blocking during acceptance
+	AcceptorIndexingParallelism     int     // Number of queued blocks whose logs are collected concurrently ahead of acceptance.
+	AcceptedEventBuffer             int     // Number of accepted events buffered for RPC subscriptions before the acceptor blocks on them.
 	CommitInterval                  uint64  // If pruning is enabled, specified the interval at which to commit an entire trie to disk.
 	PopulateMissingTries            *uint64 // Height at which to start re-populating missing tries on startup.
 	PopulateMissingTriesParallelism int     // Number of concurrent readers to use when re-populating missing tries on startup.
@@ -100,6 +103,7 @@ type Config struct {
 	TrieDirtyCache        int
 	TrieDirtyCommitTarget int
 	SnapshotCache         int
+	SnapshotCacheJournal  string
 	Preimages             bool
 
 	// AcceptedCacheSize is the depth of accepted headers cache and accepted
@@ -124,10 +128,36 @@ type Config struct {
 	// RPCEVMTimeout is the global timeout for eth-call.
 	RPCEVMTimeout time.Duration
 
+	// RPCGasEstimateMultiplier and RPCGasEstimatePadding buffer gas estimates,
+	// which are multiplied by the multiplier and increased by the padding, so
+	// that estimates stay sufficient as the state changes before inclusion.
+	// Buffered estimates never exceed the gas allowance of the estimation.
+	RPCGasEstimateMultiplier float64
+	RPCGasEstimatePadding    uint64
+
+	// RPCTraceTimeout is the default timeout of a single transaction trace, used
+	// when the trace config does not set one. Zero uses the tracer default.
+	RPCTraceTimeout time.Duration
+
+	// RPCLogsTimeout is the timeout for log queries. Zero is unlimited.
+	RPCLogsTimeout time.Duration
+
+	// RPCResumableFilterTTL is how long a resumable filter is kept without being
+	// polled, and RPCMaxResumableFilters is the maximum number of resumable filters.
+	// Zero uses the filter system defaults.
+	RPCResumableFilterTTL  time.Duration
+	RPCMaxResumableFilters int
+
 	// RPCTxFeeCap is the global transaction fee(price * gaslimit) cap for
-	// send-transaction variants. The unit is ether.
+	// send-transaction variants. The unit is the native token, as denominated
+	// by the chain config.
 	RPCTxFeeCap float64 `toml:",omitempty"`
 
+	// HistoricalProofQueryWindow is the number of blocks before the last accepted
+	// block for which eth_getProof is served. Zero serves proofs at any height
+	// for which the state is retained.
+	HistoricalProofQueryWindow uint64
+
 	// AllowUnfinalizedQueries allow unfinalized queries
 	AllowUnfinalizedQueries bool
 
@@ -156,4 +186,7 @@ type Config struct {
 	//  * 0:   means no limit
 	//  * N:   means N block limit [HEAD-N+1, HEAD] and delete extra indexes
 	TxLookupLimit uint64
+
+	// ReceiptCompression is the compression applied to newly stored receipts.
+	ReceiptCompression rawdb.ReceiptCompression
 }
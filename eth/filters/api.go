@@ -31,6 +31,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sync"
 	"time"
@@ -65,15 +66,24 @@ type FilterAPI struct {
 	filtersMu sync.Mutex
 	filters   map[rpc.ID]*filter
 	timeout   time.Duration
+
+	logsTimeout time.Duration // how long a log query may run before it is aborted (0 is unlimited)
+
+	resumableMu  sync.Mutex    // serializes access to resumable filters, which are stored in the database
+	resumableTTL time.Duration // how long a resumable filter is kept without being polled
+	maxResumable int           // maximum number of stored resumable filters
 }
 
 // NewFilterAPI returns a new FilterAPI instance.
 func NewFilterAPI(system *FilterSystem) *FilterAPI {
 	api := &FilterAPI{
-		sys:     system,
-		events:  NewEventSystem(system),
-		filters: make(map[rpc.ID]*filter),
-		timeout: system.cfg.Timeout,
+		sys:          system,
+		events:       NewEventSystem(system),
+		filters:      make(map[rpc.ID]*filter),
+		timeout:      system.cfg.Timeout,
+		logsTimeout:  system.cfg.LogsTimeout,
+		resumableTTL: system.cfg.ResumableFilterTTL,
+		maxResumable: system.cfg.MaxResumableFilters,
 	}
 	go api.timeoutLoop(system.cfg.Timeout)
 
@@ -107,6 +117,8 @@ func (api *FilterAPI) timeoutLoop(timeout time.Duration) {
 			s.Unsubscribe()
 		}
 		toUninstall = nil
+
+		api.expireResumableFilters()
 	}
 }
 
@@ -433,6 +445,20 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		if crit.ToBlock != nil {
 			end = crit.ToBlock.Int64()
 		}
+		// Resolve timestamp bounds into the accepted blocks they cover
+		if crit.FromTime != nil || crit.ToTime != nil {
+			var (
+				found bool
+				err   error
+			)
+			begin, end, found, err = api.resolveTimeRange(ctx, crit.FromTime, crit.ToTime, begin, end)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				return []*types.Log{}, nil
+			}
+		}
 		// Construct the range filter
 		var err error
 		filter, err = api.sys.NewRangeFilter(begin, end, crit.Addresses, crit.Topics)
@@ -441,13 +467,67 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		}
 	}
 	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
+	logs, err := api.runLogsQuery(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 	return returnLogs(logs), err
 }
 
+// runLogsQuery returns the logs matching [filter]. If the query does not complete
+// within the logs timeout, it is aborted and an error is returned rather than the
+// logs found so far, so that clients cannot mistake a partial result for a complete one.
+func (api *FilterAPI) runLogsQuery(ctx context.Context, filter *Filter) ([]*types.Log, error) {
+	if api.logsTimeout <= 0 {
+		return filter.Logs(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, api.logsTimeout)
+	defer cancel()
+
+	logs, err := filter.Logs(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("log query aborted (timeout = %v)", api.logsTimeout)
+	}
+	return logs, err
+}
+
+// resolveTimeRange returns the numbers of the first and last accepted blocks with
+// timestamps within [fromTime, toTime]. A nil bound leaves [begin] or [end] unchanged.
+// Returns false if no accepted block falls within the range.
+func (api *FilterAPI) resolveTimeRange(ctx context.Context, fromTime, toTime *uint64, begin, end int64) (int64, int64, bool, error) {
+	lastAccepted := api.sys.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		return 0, 0, false, errors.New("no accepted block")
+	}
+	head := lastAccepted.NumberU64()
+
+	if fromTime != nil {
+		number, err := ethapi.BlockNumberAtOrAfterTimestamp(ctx, api.sys.backend, *fromTime, head)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if number > head {
+			return 0, 0, false, nil
+		}
+		begin = int64(number)
+	}
+	if toTime != nil && *toTime < math.MaxUint64 {
+		// The last block at or before [toTime] precedes the first block after it.
+		number, err := ethapi.BlockNumberAtOrAfterTimestamp(ctx, api.sys.backend, *toTime+1, head)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if number == 0 {
+			return 0, 0, false, nil
+		}
+		end = int64(number - 1)
+	}
+	if begin >= 0 && end >= 0 && begin > end {
+		return 0, 0, false, nil
+	}
+	return begin, end, true, nil
+}
+
 // UninstallFilter removes the filter with the given filter id.
 func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
 	api.filtersMu.Lock()
@@ -460,7 +540,7 @@ func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
 		f.s.Unsubscribe()
 	}
 
-	return found
+	return found || api.uninstallResumableFilter(id)
 }
 
 // GetFilterLogs returns the logs for the filter with the given id.
@@ -470,6 +550,16 @@ func (api *FilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Lo
 	f, found := api.filters[id]
 	api.filtersMu.Unlock()
 
+	if !found {
+		// Resumable filters are stored in the database rather than installed
+		resumable, err := api.readResumableFilter(id)
+		if err != nil {
+			return nil, err
+		}
+		if resumable != nil {
+			f, found = &filter{typ: LogsSubscription, crit: FilterCriteria(resumable.Crit)}, true
+		}
+	}
 	if !found || f.typ != LogsSubscription {
 		return nil, fmt.Errorf("filter not found")
 	}
@@ -499,7 +589,7 @@ func (api *FilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Lo
 		}
 	}
 	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
+	logs, err := api.runLogsQuery(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -512,6 +602,19 @@ func (api *FilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Lo
 // For pending transaction and block filters the result is []common.Hash.
 // (pending)Log filters return []Log.
 func (api *FilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
+	api.filtersMu.Lock()
+	_, installed := api.filters[id]
+	api.filtersMu.Unlock()
+	if !installed {
+		// Resumable filters are stored in the database rather than installed
+		if logs, found, err := api.resumableFilterChanges(context.Background(), id); found {
+			if err != nil {
+				return nil, err
+			}
+			return returnLogs(logs), nil
+		}
+	}
+
 	api.filtersMu.Lock()
 	defer api.filtersMu.Unlock()
 
@@ -583,11 +686,13 @@ func returnLogs(logs []*types.Log) []*types.Log {
 // UnmarshalJSON sets *args fields with given data.
 func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	type input struct {
-		BlockHash *common.Hash     `json:"blockHash"`
-		FromBlock *rpc.BlockNumber `json:"fromBlock"`
-		ToBlock   *rpc.BlockNumber `json:"toBlock"`
-		Addresses interface{}      `json:"address"`
-		Topics    []interface{}    `json:"topics"`
+		BlockHash     *common.Hash     `json:"blockHash"`
+		FromBlock     *rpc.BlockNumber `json:"fromBlock"`
+		ToBlock       *rpc.BlockNumber `json:"toBlock"`
+		FromTimestamp *hexutil.Uint64  `json:"fromTimestamp"`
+		ToTimestamp   *hexutil.Uint64  `json:"toTimestamp"`
+		Addresses     interface{}      `json:"address"`
+		Topics        []interface{}    `json:"topics"`
 	}
 
 	var raw input
@@ -600,8 +705,19 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 			// BlockHash is mutually exclusive with FromBlock/ToBlock criteria
 			return fmt.Errorf("cannot specify both BlockHash and FromBlock/ToBlock, choose one or the other")
 		}
+		if raw.FromTimestamp != nil || raw.ToTimestamp != nil {
+			// BlockHash is mutually exclusive with FromTimestamp/ToTimestamp criteria
+			return fmt.Errorf("cannot specify both BlockHash and FromTimestamp/ToTimestamp, choose one or the other")
+		}
 		args.BlockHash = raw.BlockHash
 	} else {
+		if raw.FromBlock != nil && raw.FromTimestamp != nil {
+			return fmt.Errorf("cannot specify both FromBlock and FromTimestamp, choose one or the other")
+		}
+		if raw.ToBlock != nil && raw.ToTimestamp != nil {
+			return fmt.Errorf("cannot specify both ToBlock and ToTimestamp, choose one or the other")
+		}
+
 		if raw.FromBlock != nil {
 			args.FromBlock = big.NewInt(raw.FromBlock.Int64())
 		}
@@ -609,6 +725,16 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		if raw.ToBlock != nil {
 			args.ToBlock = big.NewInt(raw.ToBlock.Int64())
 		}
+
+		if raw.FromTimestamp != nil {
+			fromTime := uint64(*raw.FromTimestamp)
+			args.FromTime = &fromTime
+		}
+
+		if raw.ToTimestamp != nil {
+			toTime := uint64(*raw.ToTimestamp)
+			args.ToTime = &toTime
+		}
 	}
 
 	args.Addresses = []common.Address{}
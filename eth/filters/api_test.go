@@ -183,3 +183,29 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+func TestUnmarshalJSONTimeRange(t *testing.T) {
+	var crit FilterCriteria
+	if err := json.Unmarshal([]byte(`{"fromTimestamp":"0x64","toTimestamp":"0xc8"}`), &crit); err != nil {
+		t.Fatal(err)
+	}
+	if crit.FromTime == nil || *crit.FromTime != 100 {
+		t.Fatalf("expected FromTime 100, got %v", crit.FromTime)
+	}
+	if crit.ToTime == nil || *crit.ToTime != 200 {
+		t.Fatalf("expected ToTime 200, got %v", crit.ToTime)
+	}
+	if crit.FromBlock != nil || crit.ToBlock != nil {
+		t.Fatalf("expected nil block range, got %d-%d", crit.FromBlock, crit.ToBlock)
+	}
+
+	for _, vector := range []string{
+		`{"fromBlock":"0x1","fromTimestamp":"0x64"}`,
+		`{"toBlock":"0x1","toTimestamp":"0x64"}`,
+		`{"blockHash":"0x3ac225168df54212a25c1c01fd35bebfea408fdac2e31ddd6f80a4bbf9a5f1ca","fromTimestamp":"0x64"}`,
+	} {
+		if err := json.Unmarshal([]byte(vector), &crit); err == nil {
+			t.Fatalf("expected error unmarshalling %s", vector)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// logCriteria is an indexed form of the address and topic criteria of a log filter.
+//
+// Matching a log against it costs a set lookup per position rather than a scan of
+// every OR'd address or topic, and the bloom bits of each criterion are derived once
+// rather than rehashed for every block. This keeps queries with many OR'd values,
+// such as the addresses of hundreds of pools, from scaling with the size of the
+// criteria for every log and block they inspect.
+type logCriteria struct {
+	addresses map[common.Address]struct{} // nil matches any address
+	topics    []map[common.Hash]struct{}  // nil entry matches any topic at its position
+
+	addressBits []types.BloomBits   // empty matches any bloom
+	topicBits   [][]types.BloomBits // empty entry matches any bloom
+}
+
+// newLogCriteria indexes the [addresses] and [topics] criteria of a log filter.
+func newLogCriteria(addresses []common.Address, topics [][]common.Hash) *logCriteria {
+	c := &logCriteria{
+		topics:    make([]map[common.Hash]struct{}, len(topics)),
+		topicBits: make([][]types.BloomBits, len(topics)),
+	}
+	if len(addresses) > 0 {
+		c.addresses = make(map[common.Address]struct{}, len(addresses))
+		for _, addr := range addresses {
+			if _, ok := c.addresses[addr]; ok {
+				continue
+			}
+			c.addresses[addr] = struct{}{}
+			c.addressBits = append(c.addressBits, types.NewBloomBits(addr.Bytes()))
+		}
+	}
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			continue // empty rule set == wildcard
+		}
+		c.topics[i] = make(map[common.Hash]struct{}, len(sub))
+		for _, topic := range sub {
+			if _, ok := c.topics[i][topic]; ok {
+				continue
+			}
+			c.topics[i][topic] = struct{}{}
+			c.topicBits[i] = append(c.topicBits[i], types.NewBloomBits(topic.Bytes()))
+		}
+	}
+	return c
+}
+
+// matches returns whether [log] matches the criteria.
+func (c *logCriteria) matches(log *types.Log) bool {
+	if c.addresses != nil {
+		if _, ok := c.addresses[log.Address]; !ok {
+			return false
+		}
+	}
+	// If the to filtered topics is greater than the amount of topics in logs, skip.
+	if len(c.topics) > len(log.Topics) {
+		return false
+	}
+	for i, sub := range c.topics {
+		if sub == nil {
+			continue
+		}
+		if _, ok := sub[log.Topics[i]]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesBloom returns whether a block with [bloom] may contain logs matching the
+// criteria.
+func (c *logCriteria) matchesBloom(bloom types.Bloom) bool {
+	if !anyInBloom(bloom, c.addressBits) {
+		return false
+	}
+	for _, bits := range c.topicBits {
+		if !anyInBloom(bloom, bits) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyInBloom returns whether any of [bits] is present in [bloom], or true if [bits]
+// is empty.
+func anyInBloom(bloom types.Bloom, bits []types.BloomBits) bool {
+	if len(bits) == 0 {
+		return true
+	}
+	for _, b := range bits {
+		if bloom.TestBits(b) {
+			return true
+		}
+	}
+	return false
+}
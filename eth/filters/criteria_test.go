@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLogCriteria(t *testing.T) {
+	var (
+		addr0  = common.Address{0x01}
+		addr1  = common.Address{0x02}
+		topic0 = common.Hash{0x01}
+		topic1 = common.Hash{0x02}
+		topic2 = common.Hash{0x03}
+	)
+	log := &types.Log{Address: addr0, Topics: []common.Hash{topic0, topic1}}
+	bloom := types.BytesToBloom(types.LogsBloom([]*types.Log{log}))
+
+	for i, tc := range []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+		match     bool
+	}{
+		{match: true},
+		{addresses: []common.Address{addr1, addr0}, match: true},
+		{addresses: []common.Address{addr1}, match: false},
+		{topics: [][]common.Hash{{topic2, topic0}}, match: true},
+		{topics: [][]common.Hash{{}, {topic1}}, match: true},
+		{topics: [][]common.Hash{nil, {topic0}}, match: false},
+		{topics: [][]common.Hash{{topic0}, {topic1}, {}}, match: false},
+		{addresses: []common.Address{addr0, addr0}, topics: [][]common.Hash{{topic0, topic0}}, match: true},
+	} {
+		criteria := newLogCriteria(tc.addresses, tc.topics)
+		if have := criteria.matches(log); have != tc.match {
+			t.Errorf("test %d: log match mismatch: have %v, want %v", i, have, tc.match)
+		}
+		// A matching log must never be excluded by the bloom of its block.
+		if tc.match && !criteria.matchesBloom(bloom) {
+			t.Errorf("test %d: bloom should match", i)
+		}
+	}
+}
+
+func BenchmarkFilterLogsManyAddresses(b *testing.B) {
+	addresses := make([]common.Address, 500)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress([]byte{0xff, byte(i >> 8), byte(i)})
+	}
+	logs := make([]*types.Log, 1000)
+	for i := range logs {
+		logs[i] = &types.Log{Address: common.Address{byte(i)}, Topics: []common.Hash{{byte(i)}}}
+	}
+	criteria := newLogCriteria(addresses, [][]common.Hash{{{0x01}, {0x02}}})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filterLogs(logs, nil, nil, criteria)
+	}
+}
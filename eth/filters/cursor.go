@@ -0,0 +1,215 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxLogsSinceLimit is the maximum number of logs returned by a single call to
+// eth_getLogsSince.
+const maxLogsSinceLimit = 10_000
+
+// CursoredLog is a log pushed by a cursored logs subscription or returned by
+// eth_getLogsSince, along with the cursor identifying its position.
+type CursoredLog struct {
+	Cursor LogCursor  `json:"cursor"`
+	Log    *types.Log `json:"log"`
+}
+
+// CursoredHeader is a header pushed by a cursored heads subscription, along with the
+// cursor identifying its position, which is its block number.
+type CursoredHeader struct {
+	Cursor hexutil.Uint64 `json:"cursor"`
+	Header *types.Header  `json:"header"`
+}
+
+// LogsSinceResult is the result of eth_getLogsSince.
+type LogsSinceResult struct {
+	Logs []CursoredLog `json:"logs"`
+	// Cursor is the cursor to pass to the next call to continue after the returned
+	// logs. It is positioned after every log of the last scanned block if the scan
+	// reached the last accepted block without filling the limit.
+	Cursor LogCursor `json:"cursor"`
+}
+
+// CursoredLogs creates a subscription that fires for accepted logs matching the given
+// criteria, each pushed with the cursor of its position. Passing the cursor of the last
+// log received resumes delivery right after it, replaying any accepted logs missed in
+// between, so that the subscription delivers every log exactly once across reconnects.
+func (api *FilterAPI) CursoredLogs(ctx context.Context, crit FilterCriteria, cursor *LogCursor) (*rpc.Subscription, error) {
+	return api.subscribeResumableLogs(ctx, crit, cursor, func(log *types.Log, cursor LogCursor) interface{} {
+		return &CursoredLog{Cursor: cursor, Log: log}
+	})
+}
+
+// CursoredHeads creates a subscription that fires for each accepted header, pushed
+// with its block number as cursor. Passing the cursor of the last header received
+// replays the headers accepted since before new headers.
+func (api *FilterAPI) CursoredHeads(ctx context.Context, cursor *hexutil.Uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	var (
+		rpcSub     = notifier.CreateSubscription()
+		headers    = make(chan *types.Header)
+		headersSub = api.events.SubscribeAcceptedHeads(headers)
+	)
+	lastAccepted := api.sys.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		headersSub.Unsubscribe()
+		return nil, errors.New("no accepted block")
+	}
+
+	go func() {
+		replayCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer headersSub.Unsubscribe()
+
+		var (
+			last     *uint64
+			pending  []*types.Header // headers broadcast while replaying
+			replayed chan *types.Header
+			failed   chan error
+		)
+		deliver := func(header *types.Header) {
+			number := header.Number.Uint64()
+			if last != nil && number <= *last {
+				return // already delivered
+			}
+			last = &number
+			notifier.Notify(rpcSub.ID, &CursoredHeader{Cursor: hexutil.Uint64(number), Header: header})
+		}
+		if cursor != nil {
+			last = (*uint64)(cursor)
+			if uint64(*cursor) < lastAccepted.NumberU64() {
+				replayed, failed = make(chan *types.Header), make(chan error, 1)
+				go func() {
+					defer close(replayed)
+					for number := uint64(*cursor) + 1; number <= lastAccepted.NumberU64(); number++ {
+						header, err := api.sys.backend.HeaderByNumber(replayCtx, rpc.BlockNumber(number))
+						if header == nil && err == nil {
+							err = fmt.Errorf("header %d not found", number)
+						}
+						if err != nil {
+							failed <- err
+							return
+						}
+						select {
+						case replayed <- header:
+						case <-replayCtx.Done():
+							return
+						}
+					}
+				}()
+			}
+		}
+
+		for {
+			select {
+			case header, ok := <-replayed:
+				if !ok {
+					select {
+					case err := <-failed:
+						// End the subscription rather than skip headers, so the client resumes again.
+						log.Warn("Failed to replay headers of cursored subscription", "id", rpcSub.ID, "err", err)
+						return
+					default:
+					}
+					for _, header := range pending {
+						deliver(header)
+					}
+					pending, replayed = nil, nil
+					continue
+				}
+				deliver(header)
+			case header := <-headers:
+				if replayed != nil {
+					pending = append(pending, header)
+					continue
+				}
+				deliver(header)
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				return
+			case <-notifier.Closed(): // connection dropped
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// GetLogsSince returns up to [limit] accepted logs matching the given criteria after
+// [cursor], or from the first block of the criteria if [cursor] is nil, along with the
+// cursor to continue from. It allows the events pushed by a cursored logs subscription
+// to be replayed from any cursor.
+func (api *FilterAPI) GetLogsSince(ctx context.Context, crit FilterCriteria, cursor *LogCursor, limit *hexutil.Uint) (*LogsSinceResult, error) {
+	if err := checkResumableCriteria(crit); err != nil {
+		return nil, err
+	}
+	lastAccepted := api.sys.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		return nil, errors.New("no accepted block")
+	}
+	maxLogs := maxLogsSinceLimit
+	if limit != nil && *limit > 0 && *limit < maxLogsSinceLimit {
+		maxLogs = int(*limit)
+	}
+
+	begin, end := uint64(0), lastAccepted.NumberU64()
+	if cursor != nil {
+		begin = uint64(cursor.BlockNumber)
+	}
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 && crit.FromBlock.Uint64() > begin {
+		begin = crit.FromBlock.Uint64()
+	}
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 && crit.ToBlock.Uint64() < end {
+		end = crit.ToBlock.Uint64()
+	}
+
+	result := &LogsSinceResult{Logs: []CursoredLog{}}
+	if cursor != nil {
+		result.Cursor = *cursor
+	}
+	chunk := uint64(api.sys.backend.GetMaxBlocksPerRequest())
+	if chunk == 0 {
+		chunk = math.MaxUint64
+	}
+	for begin <= end {
+		// Logs are collected a chunk at a time to stop scanning once the limit is reached.
+		chunkEnd := end
+		if end-begin >= chunk {
+			chunkEnd = begin + chunk - 1
+		}
+		logs, err := api.acceptedLogs(ctx, crit, begin, chunkEnd)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			if cursor != nil && !cursor.precedes(log) {
+				continue
+			}
+			result.Cursor = *newLogCursor(log)
+			result.Logs = append(result.Logs, CursoredLog{Cursor: result.Cursor, Log: log})
+			if len(result.Logs) == maxLogs {
+				return result, nil
+			}
+		}
+		// Position the cursor after the scanned blocks, so they are not scanned again.
+		result.Cursor = LogCursor{BlockNumber: hexutil.Uint64(chunkEnd), LogIndex: math.MaxUint}
+		begin = chunkEnd + 1
+	}
+	return result, nil
+}
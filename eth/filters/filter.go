@@ -44,6 +44,7 @@ type Filter struct {
 
 	addresses []common.Address
 	topics    [][]common.Hash
+	criteria  *logCriteria
 
 	block      *common.Hash // Block hash if filtering a single block
 	begin, end int64        // Range interval if filtering multiple blocks
@@ -113,6 +114,7 @@ func newFilter(sys *FilterSystem, addresses []common.Address, topics [][]common.
 		sys:       sys,
 		addresses: addresses,
 		topics:    topics,
+		criteria:  newLogCriteria(addresses, topics),
 	}
 }
 
@@ -265,7 +267,7 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 
 // blockLogs returns the logs matching the filter criteria within a single block.
 func (f *Filter) blockLogs(ctx context.Context, header *types.Header) ([]*types.Log, error) {
-	if bloomFilter(header.Bloom, f.addresses, f.topics) {
+	if f.criteria.matchesBloom(header.Bloom) {
 		return f.checkMatches(ctx, header)
 	}
 	return nil, nil
@@ -280,7 +282,7 @@ func (f *Filter) checkMatches(ctx context.Context, header *types.Header) ([]*typ
 	}
 
 	unfiltered := types.FlattenLogs(logsList)
-	logs := filterLogs(unfiltered, nil, nil, f.addresses, f.topics)
+	logs := filterLogs(unfiltered, nil, nil, f.criteria)
 	if len(logs) > 0 {
 		// We have matching logs, check if we need to resolve full logs via the light client
 		if logs[0].TxHash == (common.Hash{}) {
@@ -292,27 +294,16 @@ func (f *Filter) checkMatches(ctx context.Context, header *types.Header) ([]*typ
 			for _, receipt := range receipts {
 				unfiltered = append(unfiltered, receipt.Logs...)
 			}
-			logs = filterLogs(unfiltered, nil, nil, f.addresses, f.topics)
+			logs = filterLogs(unfiltered, nil, nil, f.criteria)
 		}
 		return logs, nil
 	}
 	return nil, nil
 }
 
-func includes(addresses []common.Address, a common.Address) bool {
-	for _, addr := range addresses {
-		if addr == a {
-			return true
-		}
-	}
-
-	return false
-}
-
 // filterLogs creates a slice of logs matching the given criteria.
-func filterLogs(logs []*types.Log, fromBlock, toBlock *big.Int, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+func filterLogs(logs []*types.Log, fromBlock, toBlock *big.Int, criteria *logCriteria) []*types.Log {
 	var ret []*types.Log
-Logs:
 	for _, log := range logs {
 		if fromBlock != nil && fromBlock.Int64() >= 0 && fromBlock.Uint64() > log.BlockNumber {
 			continue
@@ -320,56 +311,10 @@ Logs:
 		if toBlock != nil && toBlock.Int64() >= 0 && toBlock.Uint64() < log.BlockNumber {
 			continue
 		}
-
-		if len(addresses) > 0 && !includes(addresses, log.Address) {
-			continue
-		}
-		// If the to filtered topics is greater than the amount of topics in logs, skip.
-		if len(topics) > len(log.Topics) {
+		if !criteria.matches(log) {
 			continue
 		}
-		for i, sub := range topics {
-			match := len(sub) == 0 // empty rule set == wildcard
-			for _, topic := range sub {
-				if log.Topics[i] == topic {
-					match = true
-					break
-				}
-			}
-			if !match {
-				continue Logs
-			}
-		}
 		ret = append(ret, log)
 	}
 	return ret
 }
-
-func bloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
-	if len(addresses) > 0 {
-		var included bool
-		for _, addr := range addresses {
-			if types.BloomLookup(bloom, addr) {
-				included = true
-				break
-			}
-		}
-		if !included {
-			return false
-		}
-	}
-
-	for _, sub := range topics {
-		included := len(sub) == 0 // empty rule set == wildcard
-		for _, topic := range sub {
-			if types.BloomLookup(bloom, topic) {
-				included = true
-				break
-			}
-		}
-		if !included {
-			return false
-		}
-	}
-	return true
-}
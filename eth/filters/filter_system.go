@@ -49,13 +49,32 @@ import (
 
 // Config represents the configuration of the filter system.
 type Config struct {
-	Timeout time.Duration // how long filters stay active (default: 5min)
+	Timeout     time.Duration // how long filters stay active (default: 5min)
+	LogsTimeout time.Duration // how long a log query may run before it is aborted (0 is unlimited)
+	// AcceptedEventBuffer is the number of accepted chain and log events buffered
+	// for the event system before the acceptor blocks on it (default: 10)
+	AcceptedEventBuffer int
+	// ResumableFilterTTL is how long a resumable filter is kept without being polled
+	// (default: 24h)
+	ResumableFilterTTL time.Duration
+	// MaxResumableFilters is the maximum number of resumable filters stored by the
+	// node (default: 1000)
+	MaxResumableFilters int
 }
 
 func (cfg Config) withDefaults() Config {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 5 * time.Minute
 	}
+	if cfg.AcceptedEventBuffer == 0 {
+		cfg.AcceptedEventBuffer = chainEvChanSize
+	}
+	if cfg.ResumableFilterTTL == 0 {
+		cfg.ResumableFilterTTL = 24 * time.Hour
+	}
+	if cfg.MaxResumableFilters == 0 {
+		cfg.MaxResumableFilters = 1000
+	}
 	return cfg
 }
 
@@ -164,6 +183,7 @@ type subscription struct {
 	typ       Type
 	created   time.Time
 	logsCrit  interfaces.FilterQuery
+	criteria  *logCriteria // indexed address and topic criteria of logsCrit
 	logs      chan []*types.Log
 	txs       chan []*types.Transaction
 	headers   chan *types.Header
@@ -214,11 +234,11 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 		uninstall:       make(chan *subscription),
 		txsCh:           make(chan core.NewTxsEvent, txChanSize),
 		logsCh:          make(chan []*types.Log, logsChanSize),
-		logsAcceptedCh:  make(chan []*types.Log, logsChanSize),
+		logsAcceptedCh:  make(chan []*types.Log, sys.cfg.AcceptedEventBuffer),
 		rmLogsCh:        make(chan core.RemovedLogsEvent, rmLogsChanSize),
 		pendingLogsCh:   make(chan []*types.Log, logsChanSize),
 		chainCh:         make(chan core.ChainEvent, chainEvChanSize),
-		chainAcceptedCh: make(chan core.ChainEvent, chainEvChanSize),
+		chainAcceptedCh: make(chan core.ChainEvent, sys.cfg.AcceptedEventBuffer),
 		txsAcceptedCh:   make(chan core.NewTxsEvent, txChanSize),
 	}
 
@@ -290,6 +310,9 @@ func (es *EventSystem) subscribe(sub *subscription) *Subscription {
 // given criteria to the given logs channel. Default value for the from and to
 // block is "latest". If the fromBlock > toBlock an error is returned.
 func (es *EventSystem) SubscribeLogs(crit interfaces.FilterQuery, logs chan []*types.Log) (*Subscription, error) {
+	if crit.FromTime != nil || crit.ToTime != nil {
+		return nil, fmt.Errorf("timestamp ranges are only supported by eth_getLogs")
+	}
 	var from, to rpc.BlockNumber
 	if crit.FromBlock == nil {
 		from = rpc.LatestBlockNumber
@@ -326,6 +349,9 @@ func (es *EventSystem) SubscribeLogs(crit interfaces.FilterQuery, logs chan []*t
 }
 
 func (es *EventSystem) SubscribeAcceptedLogs(crit interfaces.FilterQuery, logs chan []*types.Log) (*Subscription, error) {
+	if crit.FromTime != nil || crit.ToTime != nil {
+		return nil, fmt.Errorf("timestamp ranges are only supported by eth_getLogs")
+	}
 	var from, to rpc.BlockNumber
 	if crit.FromBlock == nil {
 		from = rpc.LatestBlockNumber
@@ -355,6 +381,7 @@ func (es *EventSystem) subscribeAcceptedLogs(crit interfaces.FilterQuery, logs c
 		id:        rpc.NewID(),
 		typ:       AcceptedLogsSubscription,
 		logsCrit:  crit,
+		criteria:  newLogCriteria(crit.Addresses, crit.Topics),
 		created:   time.Now(),
 		logs:      logs,
 		txs:       make(chan []*types.Transaction),
@@ -372,6 +399,7 @@ func (es *EventSystem) subscribeMinedPendingLogs(crit interfaces.FilterQuery, lo
 		id:        rpc.NewID(),
 		typ:       MinedAndPendingLogsSubscription,
 		logsCrit:  crit,
+		criteria:  newLogCriteria(crit.Addresses, crit.Topics),
 		created:   time.Now(),
 		logs:      logs,
 		txs:       make(chan []*types.Transaction),
@@ -389,6 +417,7 @@ func (es *EventSystem) subscribeLogs(crit interfaces.FilterQuery, logs chan []*t
 		id:        rpc.NewID(),
 		typ:       LogsSubscription,
 		logsCrit:  crit,
+		criteria:  newLogCriteria(crit.Addresses, crit.Topics),
 		created:   time.Now(),
 		logs:      logs,
 		txs:       make(chan []*types.Transaction),
@@ -406,6 +435,7 @@ func (es *EventSystem) subscribePendingLogs(crit interfaces.FilterQuery, logs ch
 		id:        rpc.NewID(),
 		typ:       PendingLogsSubscription,
 		logsCrit:  crit,
+		criteria:  newLogCriteria(crit.Addresses, crit.Topics),
 		created:   time.Now(),
 		logs:      logs,
 		txs:       make(chan []*types.Transaction),
@@ -487,7 +517,7 @@ func (es *EventSystem) handleLogs(filters filterIndex, ev []*types.Log) {
 		return
 	}
 	for _, f := range filters[LogsSubscription] {
-		matchedLogs := filterLogs(ev, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics)
+		matchedLogs := filterLogs(ev, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.criteria)
 		if len(matchedLogs) > 0 {
 			f.logs <- matchedLogs
 		}
@@ -499,7 +529,7 @@ func (es *EventSystem) handleAcceptedLogs(filters filterIndex, ev []*types.Log)
 		return
 	}
 	for _, f := range filters[AcceptedLogsSubscription] {
-		matchedLogs := filterLogs(ev, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics)
+		matchedLogs := filterLogs(ev, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.criteria)
 		if len(matchedLogs) > 0 {
 			f.logs <- matchedLogs
 		}
@@ -511,7 +541,7 @@ func (es *EventSystem) handlePendingLogs(filters filterIndex, ev []*types.Log) {
 		return
 	}
 	for _, f := range filters[PendingLogsSubscription] {
-		matchedLogs := filterLogs(ev, nil, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics)
+		matchedLogs := filterLogs(ev, nil, f.logsCrit.ToBlock, f.criteria)
 		if len(matchedLogs) > 0 {
 			f.logs <- matchedLogs
 		}
@@ -520,7 +550,7 @@ func (es *EventSystem) handlePendingLogs(filters filterIndex, ev []*types.Log) {
 
 func (es *EventSystem) handleRemovedLogs(filters filterIndex, ev core.RemovedLogsEvent) {
 	for _, f := range filters[LogsSubscription] {
-		matchedLogs := filterLogs(ev.Logs, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics)
+		matchedLogs := filterLogs(ev.Logs, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.criteria)
 		if len(matchedLogs) > 0 {
 			f.logs <- matchedLogs
 		}
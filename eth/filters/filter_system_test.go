@@ -266,6 +266,25 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestAcceptedEventBuffer tests that the accepted event channels of the event
+// system are buffered as configured.
+func TestAcceptedEventBuffer(t *testing.T) {
+	t.Parallel()
+
+	db := rawdb.NewMemoryDatabase()
+	_, sys := newTestFilterSystem(t, db, Config{})
+	es := NewEventSystem(sys)
+	if cap(es.chainAcceptedCh) != chainEvChanSize || cap(es.logsAcceptedCh) != chainEvChanSize {
+		t.Fatalf("default buffer mismatch: have %d and %d, want %d", cap(es.chainAcceptedCh), cap(es.logsAcceptedCh), chainEvChanSize)
+	}
+
+	_, sys = newTestFilterSystem(t, db, Config{AcceptedEventBuffer: 64})
+	es = NewEventSystem(sys)
+	if cap(es.chainAcceptedCh) != 64 || cap(es.logsAcceptedCh) != 64 {
+		t.Fatalf("configured buffer mismatch: have %d and %d, want 64", cap(es.chainAcceptedCh), cap(es.logsAcceptedCh))
+	}
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
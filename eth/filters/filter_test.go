@@ -263,3 +263,51 @@ func mustNewRangeFilter(t *testing.T, sys *FilterSystem, begin, end int64, addre
 	require.NoError(t, err)
 	return f
 }
+
+func TestResolveTimeRange(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		_, sys = newTestFilterSystem(t, db, Config{})
+		api    = NewFilterAPI(sys)
+		gspec  = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(1),
+		}
+	)
+	// Block i has timestamp 10*i.
+	_, chain, _, err := core.GenerateChainWithGenesis(gspec, dummy.NewFaker(), 10, 10, func(i int, gen *core.BlockGen) {})
+	require.NoError(t, err)
+	gspec.MustCommit(db)
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		// Blocks before 6 are accepted before the timestamp index was maintained.
+		if block.NumberU64() >= 6 {
+			rawdb.WriteBlockTimestamp(db, block.Time(), block.NumberU64(), block.Hash())
+		}
+	}
+
+	u64 := func(v uint64) *uint64 { return &v }
+	for i, tc := range []struct {
+		fromTime, toTime *uint64
+		begin, end       int64
+		found            bool
+	}{
+		{fromTime: u64(25), toTime: u64(55), begin: 3, end: 5, found: true},
+		{fromTime: u64(60), toTime: u64(60), begin: 6, end: 6, found: true},
+		{fromTime: u64(0), toTime: u64(5), begin: 0, end: 0, found: true},
+		{fromTime: u64(75), begin: 8, end: -1, found: true},
+		{toTime: u64(85), begin: 0, end: 8, found: true},
+		{fromTime: u64(31), toTime: u64(39)},
+		{fromTime: u64(101)},
+	} {
+		begin, end, found, err := api.resolveTimeRange(context.Background(), tc.fromTime, tc.toTime, 0, -1)
+		require.NoError(t, err, "test %d", i)
+		require.Equal(t, tc.found, found, "test %d", i)
+		if found {
+			require.Equal(t, tc.begin, begin, "test %d: begin", i)
+			require.Equal(t, tc.end, end, "test %d: end", i)
+		}
+	}
+}
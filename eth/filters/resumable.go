@@ -0,0 +1,371 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxResumablePendingLogs is the maximum number of logs a resumed subscription buffers
+// while it replays the logs since its cursor.
+const maxResumablePendingLogs = 10_000
+
+var (
+	errResumableBlockHash      = errors.New("resumable filters do not support blockHash")
+	errResumableTimeRange      = errors.New("resumable filters do not support timestamp ranges")
+	errResumablePending        = errors.New("resumable filters only return accepted logs")
+	errTooManyResumableFilters = errors.New("too many resumable filters")
+	errResumableReplayOverflow = errors.New("too many logs accepted while replaying, resume from the last received log")
+)
+
+// resumableFilter is a log filter persisted to the database, so that it survives node
+// restarts. Rather than buffering the logs broadcast by the event system, it keeps a
+// cursor to the next accepted block to scan, and each poll scans the accepted blocks
+// from the cursor onwards.
+type resumableFilter struct {
+	Crit     interfaces.FilterQuery `json:"crit"`
+	Next     uint64                 `json:"next"`     // Next accepted block to scan for logs
+	LastUsed uint64                 `json:"lastUsed"` // Unix time the filter was created or last polled
+}
+
+// expired returns whether [f] was not used within [ttl] of [now].
+func (f *resumableFilter) expired(now time.Time, ttl time.Duration) bool {
+	return now.Sub(time.Unix(int64(f.LastUsed), 0)) > ttl
+}
+
+// LogCursor is the position of a log in the accepted chain. It identifies the last log
+// delivered to a client, which can resume delivery after it.
+type LogCursor struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+}
+
+func newLogCursor(log *types.Log) *LogCursor {
+	return &LogCursor{BlockNumber: hexutil.Uint64(log.BlockNumber), LogIndex: hexutil.Uint(log.Index)}
+}
+
+// precedes returns whether [log] is positioned after the cursor.
+func (c *LogCursor) precedes(log *types.Log) bool {
+	if log.BlockNumber != uint64(c.BlockNumber) {
+		return log.BlockNumber > uint64(c.BlockNumber)
+	}
+	return log.Index > uint(c.LogIndex)
+}
+
+// checkResumableCriteria returns an error if [crit] cannot be used by a resumable
+// filter or subscription.
+func checkResumableCriteria(crit FilterCriteria) error {
+	switch {
+	case crit.BlockHash != nil:
+		return errResumableBlockHash
+	case crit.FromTime != nil || crit.ToTime != nil:
+		return errResumableTimeRange
+	case crit.FromBlock != nil && crit.FromBlock.Int64() == rpc.PendingBlockNumber.Int64(),
+		crit.ToBlock != nil && crit.ToBlock.Int64() == rpc.PendingBlockNumber.Int64():
+		return errResumablePending
+	}
+	return nil
+}
+
+// NewResumableFilter creates a log filter that is persisted to the database and returns
+// its id. Unlike filters created by NewFilter, it survives node restarts. It should be
+// removed with eth_uninstallFilter, and is otherwise removed once it has not been polled
+// for the resumable filter TTL. The number of resumable filters of the node is capped.
+//
+// eth_getFilterChanges returns the accepted logs since the previous call, starting at
+// fromBlock, or after the last accepted block if fromBlock is not a block number.
+// Each call scans at most the maximum number of blocks per request, so a filter that
+// has fallen behind catches up over several calls.
+func (api *FilterAPI) NewResumableFilter(crit FilterCriteria) (rpc.ID, error) {
+	if err := checkResumableCriteria(crit); err != nil {
+		return "", err
+	}
+	lastAccepted := api.sys.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		return "", errors.New("no accepted block")
+	}
+	f := &resumableFilter{
+		Crit:     interfaces.FilterQuery(crit),
+		Next:     lastAccepted.NumberU64() + 1,
+		LastUsed: uint64(time.Now().Unix()),
+	}
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 {
+		f.Next = crit.FromBlock.Uint64()
+	}
+
+	// Expired filters do not count towards the cap.
+	api.expireResumableFilters()
+
+	api.resumableMu.Lock()
+	defer api.resumableMu.Unlock()
+
+	if len(rawdb.ReadResumableFilterIDs(api.sys.backend.ChainDb())) >= api.maxResumable {
+		return "", errTooManyResumableFilters
+	}
+	id := rpc.NewID()
+	if err := api.writeResumableFilter(id, f); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// readResumableFilter returns the resumable filter with [id], or nil if there is none
+// or it has expired.
+func (api *FilterAPI) readResumableFilter(id rpc.ID) (*resumableFilter, error) {
+	data := rawdb.ReadResumableFilter(api.sys.backend.ChainDb(), string(id))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	f := new(resumableFilter)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid resumable filter %s: %w", id, err)
+	}
+	if f.expired(time.Now(), api.resumableTTL) {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func (api *FilterAPI) writeResumableFilter(id rpc.ID, f *resumableFilter) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteResumableFilter(api.sys.backend.ChainDb(), string(id), data)
+}
+
+// uninstallResumableFilter removes the resumable filter with [id] and returns whether
+// it existed.
+func (api *FilterAPI) uninstallResumableFilter(id rpc.ID) bool {
+	api.resumableMu.Lock()
+	defer api.resumableMu.Unlock()
+
+	db := api.sys.backend.ChainDb()
+	if len(rawdb.ReadResumableFilter(db, string(id))) == 0 {
+		return false
+	}
+	if err := rawdb.DeleteResumableFilter(db, string(id)); err != nil {
+		log.Warn("Failed to delete resumable filter", "id", id, "err", err)
+		return false
+	}
+	return true
+}
+
+// expireResumableFilters removes the resumable filters that have not been polled within
+// the resumable filter TTL.
+func (api *FilterAPI) expireResumableFilters() {
+	api.resumableMu.Lock()
+	defer api.resumableMu.Unlock()
+
+	var (
+		db  = api.sys.backend.ChainDb()
+		now = time.Now()
+	)
+	for _, id := range rawdb.ReadResumableFilterIDs(db) {
+		f := new(resumableFilter)
+		if err := json.Unmarshal(rawdb.ReadResumableFilter(db, id), f); err == nil && !f.expired(now, api.resumableTTL) {
+			continue
+		}
+		if err := rawdb.DeleteResumableFilter(db, id); err != nil {
+			log.Warn("Failed to delete expired resumable filter", "id", id, "err", err)
+		}
+	}
+}
+
+// resumableFilterChanges returns the accepted logs matching the resumable filter with
+// [id] since it was last polled and advances its cursor. Returns false if there is no
+// such filter.
+func (api *FilterAPI) resumableFilterChanges(ctx context.Context, id rpc.ID) ([]*types.Log, bool, error) {
+	api.resumableMu.Lock()
+	defer api.resumableMu.Unlock()
+
+	f, err := api.readResumableFilter(id)
+	if f == nil || err != nil {
+		return nil, err != nil, err
+	}
+	lastAccepted := api.sys.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		return nil, true, errors.New("no accepted block")
+	}
+	end := lastAccepted.NumberU64()
+	if f.Crit.ToBlock != nil && f.Crit.ToBlock.Sign() >= 0 && f.Crit.ToBlock.Uint64() < end {
+		end = f.Crit.ToBlock.Uint64()
+	}
+	if maxBlocks := api.sys.backend.GetMaxBlocksPerRequest(); maxBlocks > 0 && end >= f.Next+uint64(maxBlocks) {
+		end = f.Next + uint64(maxBlocks) - 1
+	}
+	f.LastUsed = uint64(time.Now().Unix())
+	if f.Next > end {
+		return nil, true, api.writeResumableFilter(id, f)
+	}
+
+	filter, err := api.sys.NewRangeFilter(int64(f.Next), int64(end), f.Crit.Addresses, f.Crit.Topics)
+	if err != nil {
+		return nil, true, err
+	}
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	f.Next = end + 1
+	if err := api.writeResumableFilter(id, f); err != nil {
+		return nil, true, err
+	}
+	return logs, true, nil
+}
+
+// acceptedLogs returns the logs matching [crit] in the accepted blocks [begin, end],
+// scanning at most the maximum number of blocks per request at a time.
+func (api *FilterAPI) acceptedLogs(ctx context.Context, crit FilterCriteria, begin, end uint64) ([]*types.Log, error) {
+	var (
+		logs      []*types.Log
+		maxBlocks = api.sys.backend.GetMaxBlocksPerRequest()
+	)
+	for begin <= end {
+		chunkEnd := end
+		if maxBlocks > 0 && chunkEnd >= begin+uint64(maxBlocks) {
+			chunkEnd = begin + uint64(maxBlocks) - 1
+		}
+		filter, err := api.sys.NewRangeFilter(int64(begin), int64(chunkEnd), crit.Addresses, crit.Topics)
+		if err != nil {
+			return nil, err
+		}
+		found, err := filter.Logs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, found...)
+		begin = chunkEnd + 1
+	}
+	return logs, nil
+}
+
+// ResumableLogs creates a subscription that fires for accepted logs matching the given
+// criteria. If [cursor] is set to the position of the last log a client received,
+// delivery resumes right after it: the accepted logs since the cursor are replayed
+// before new logs, so that a reconnecting client neither misses nor re-receives logs.
+func (api *FilterAPI) ResumableLogs(ctx context.Context, crit FilterCriteria, cursor *LogCursor) (*rpc.Subscription, error) {
+	return api.subscribeResumableLogs(ctx, crit, cursor, func(log *types.Log, _ LogCursor) interface{} {
+		return log
+	})
+}
+
+// subscribeResumableLogs creates a subscription that notifies the [payload] of each
+// accepted log matching [crit] after [cursor], replaying the accepted logs since the
+// cursor before new logs.
+func (api *FilterAPI) subscribeResumableLogs(ctx context.Context, crit FilterCriteria, cursor *LogCursor, payload func(log *types.Log, cursor LogCursor) interface{}) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if err := checkResumableCriteria(crit); err != nil {
+		return nil, err
+	}
+
+	var (
+		rpcSub      = notifier.CreateSubscription()
+		matchedLogs = make(chan []*types.Log)
+	)
+	// Subscribe before reading the last accepted block, so that every log accepted
+	// after the replayed range is broadcast to the subscription.
+	logsSub, err := api.events.SubscribeAcceptedLogs(interfaces.FilterQuery(crit), matchedLogs)
+	if err != nil {
+		return nil, err
+	}
+	lastAccepted := api.sys.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		logsSub.Unsubscribe()
+		return nil, errors.New("no accepted block")
+	}
+
+	go func() {
+		replayCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer logsSub.Unsubscribe()
+
+		type replayResult struct {
+			logs []*types.Log
+			err  error
+		}
+		var (
+			last        = cursor
+			pending     [][]*types.Log // logs broadcast while replaying
+			pendingLogs int            // number of logs in [pending]
+			replayed    chan replayResult
+		)
+		deliver := func(logs []*types.Log) {
+			for _, log := range logs {
+				if last != nil && !last.precedes(log) {
+					continue // already delivered
+				}
+				last = newLogCursor(log)
+				notifier.Notify(rpcSub.ID, payload(log, *last))
+			}
+		}
+		begin, end := uint64(0), lastAccepted.NumberU64()
+		if cursor != nil {
+			begin = uint64(cursor.BlockNumber)
+		}
+		if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 && crit.FromBlock.Uint64() > begin {
+			begin = crit.FromBlock.Uint64()
+		}
+		if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 && crit.ToBlock.Uint64() < end {
+			end = crit.ToBlock.Uint64()
+		}
+		if cursor != nil && begin <= end {
+			replayed = make(chan replayResult, 1)
+			go func() {
+				logs, err := api.acceptedLogs(replayCtx, crit, begin, end)
+				replayed <- replayResult{logs, err}
+			}()
+		}
+
+		for {
+			select {
+			case result := <-replayed:
+				if result.err != nil {
+					// End the subscription rather than skip logs, so the client resumes again.
+					log.Warn("Failed to replay logs of resumed subscription", "id", rpcSub.ID, "err", result.err)
+					notifier.Error(rpcSub.ID, fmt.Errorf("failed to replay logs: %w", result.err))
+					return
+				}
+				deliver(result.logs)
+				for _, logs := range pending {
+					deliver(logs)
+				}
+				pending, pendingLogs, replayed = nil, 0, nil
+			case logs := <-matchedLogs:
+				if replayed != nil {
+					if pendingLogs += len(logs); pendingLogs > maxResumablePendingLogs {
+						// End the subscription rather than buffer without bound, so the client
+						// resumes again from the last log it received.
+						log.Warn("Too many logs accepted while replaying logs of resumed subscription", "id", rpcSub.ID)
+						notifier.Error(rpcSub.ID, errResumableReplayOverflow)
+						return
+					}
+					pending = append(pending, logs)
+					continue
+				}
+				deliver(logs)
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				return
+			case <-notifier.Closed(): // connection dropped
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,170 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/consensus/dummy"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+// makeLogChain generates a chain of 5 blocks, of which blocks 2, 4 and 5 contain a log
+// of [addr], and commits its genesis to [db].
+func makeLogChain(t *testing.T, db ethdb.Database, addr common.Address) ([]*types.Block, []types.Receipts) {
+	gspec := &core.Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(1),
+	}
+	_, chain, receipts, err := core.GenerateChainWithGenesis(gspec, dummy.NewFaker(), 5, 10, func(i int, gen *core.BlockGen) {
+		if i == 1 || i == 3 || i == 4 {
+			gen.AddUncheckedReceipt(makeReceipt(addr))
+			gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+	})
+	require.NoError(t, err)
+	gspec.MustCommit(db)
+	return chain, receipts
+}
+
+// acceptLogChain writes [blocks] to [db] as accepted blocks.
+func acceptLogChain(db ethdb.Database, blocks []*types.Block, receipts []types.Receipts) {
+	for i, block := range blocks {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+}
+
+func TestResumableFilter(t *testing.T) {
+	var (
+		db              = rawdb.NewMemoryDatabase()
+		addr            = common.BytesToAddress([]byte("jeff"))
+		chain, receipts = makeLogChain(t, db, addr)
+	)
+	changes := func(api *FilterAPI, id rpc.ID) []*types.Log {
+		t.Helper()
+		result, err := api.GetFilterChanges(id)
+		require.NoError(t, err)
+		return result.([]*types.Log)
+	}
+	acceptLogChain(db, chain[:3], receipts[:3])
+
+	_, sys := newTestFilterSystem(t, db, Config{})
+	api := NewFilterAPI(sys)
+	id, err := api.NewResumableFilter(FilterCriteria{FromBlock: big.NewInt(1), Addresses: []common.Address{addr}})
+	require.NoError(t, err)
+
+	logs := changes(api, id)
+	require.Len(t, logs, 1)
+	require.Equal(t, uint64(2), logs[0].BlockNumber)
+	require.Empty(t, changes(api, id))
+
+	// The filter survives a restart and returns the logs accepted since the last poll.
+	acceptLogChain(db, chain[3:], receipts[3:])
+	_, sys = newTestFilterSystem(t, db, Config{})
+	api = NewFilterAPI(sys)
+
+	logs = changes(api, id)
+	require.Len(t, logs, 2)
+	require.Equal(t, uint64(4), logs[0].BlockNumber)
+	require.Equal(t, uint64(5), logs[1].BlockNumber)
+
+	logs, err = api.GetFilterLogs(context.Background(), id)
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+
+	require.True(t, api.UninstallFilter(id))
+	require.False(t, api.UninstallFilter(id))
+	_, err = api.GetFilterChanges(id)
+	require.Error(t, err)
+}
+
+func TestResumableFilterLimits(t *testing.T) {
+	var (
+		db              = rawdb.NewMemoryDatabase()
+		addr            = common.BytesToAddress([]byte("jeff"))
+		chain, receipts = makeLogChain(t, db, addr)
+		_, sys          = newTestFilterSystem(t, db, Config{ResumableFilterTTL: time.Hour, MaxResumableFilters: 2})
+		api             = NewFilterAPI(sys)
+		crit            = FilterCriteria{Addresses: []common.Address{addr}}
+	)
+	acceptLogChain(db, chain, receipts)
+
+	first, err := api.NewResumableFilter(crit)
+	require.NoError(t, err)
+	second, err := api.NewResumableFilter(crit)
+	require.NoError(t, err)
+	_, err = api.NewResumableFilter(crit)
+	require.ErrorIs(t, err, errTooManyResumableFilters)
+
+	// A filter that is not polled within the TTL expires, and no longer counts
+	// towards the cap.
+	f, err := api.readResumableFilter(first)
+	require.NoError(t, err)
+	f.LastUsed = uint64(time.Now().Add(-2 * time.Hour).Unix())
+	require.NoError(t, api.writeResumableFilter(first, f))
+	_, err = api.GetFilterChanges(first)
+	require.Error(t, err)
+
+	_, err = api.NewResumableFilter(crit)
+	require.NoError(t, err)
+	require.Len(t, rawdb.ReadResumableFilterIDs(db), 2)
+	_, err = api.GetFilterChanges(second)
+	require.NoError(t, err)
+}
+
+func TestResumableCriteria(t *testing.T) {
+	for _, crit := range []FilterCriteria{
+		{BlockHash: &common.Hash{}},
+		{FromTime: new(uint64)},
+		{ToBlock: big.NewInt(rpc.PendingBlockNumber.Int64())},
+	} {
+		require.Error(t, checkResumableCriteria(crit))
+	}
+	require.NoError(t, checkResumableCriteria(FilterCriteria{FromBlock: big.NewInt(1)}))
+}
+
+func TestGetLogsSince(t *testing.T) {
+	var (
+		db              = rawdb.NewMemoryDatabase()
+		addr            = common.BytesToAddress([]byte("jeff"))
+		chain, receipts = makeLogChain(t, db, addr)
+		_, sys          = newTestFilterSystem(t, db, Config{})
+		api             = NewFilterAPI(sys)
+		crit            = FilterCriteria{Addresses: []common.Address{addr}}
+		limit           = hexutil.Uint(2)
+	)
+	acceptLogChain(db, chain, receipts)
+
+	result, err := api.GetLogsSince(context.Background(), crit, nil, &limit)
+	require.NoError(t, err)
+	require.Len(t, result.Logs, 2)
+	require.Equal(t, uint64(2), result.Logs[0].Log.BlockNumber)
+	require.Equal(t, uint64(4), result.Logs[1].Log.BlockNumber)
+	require.Equal(t, result.Logs[1].Cursor, result.Cursor)
+
+	// Resuming from the returned cursor continues after the last returned log.
+	result, err = api.GetLogsSince(context.Background(), crit, &result.Cursor, &limit)
+	require.NoError(t, err)
+	require.Len(t, result.Logs, 1)
+	require.Equal(t, uint64(5), result.Logs[0].Log.BlockNumber)
+	require.Equal(t, hexutil.Uint64(5), result.Cursor.BlockNumber)
+
+	result, err = api.GetLogsSince(context.Background(), crit, &result.Cursor, &limit)
+	require.NoError(t, err)
+	require.Empty(t, result.Logs)
+}
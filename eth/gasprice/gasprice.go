@@ -62,6 +62,19 @@ const (
 	// [DefaultMaxBlockHistory] to ensure all block lookups can be cached when
 	// serving a fee history query.
 	DefaultFeeHistoryCacheSize int = 30_000
+	// DefaultCongestionThreshold is the number of pending transactions in the
+	// mempool above which [CongestionMode] begins scaling up the suggested tip.
+	DefaultCongestionThreshold int = 1024
+)
+
+const (
+	// SamplingMode suggests tips using the configured percentile of the tips
+	// paid in recent blocks.
+	SamplingMode = "sampling"
+	// CongestionMode suggests tips using the same sampling as [SamplingMode],
+	// and scales the result up proportionally to the number of pending
+	// transactions in the mempool once it exceeds the congestion threshold.
+	CongestionMode = "congestion"
 )
 
 var (
@@ -70,6 +83,9 @@ var (
 	DefaultMinBaseFee         = big.NewInt(params.TestInitialBaseFee)
 	DefaultMinGasUsed         = big.NewInt(6_000_000) // block gas limit is 8,000,000
 	DefaultMaxLookbackSeconds = uint64(80)
+	// DefaultCongestionBaseTip is the tip that [CongestionMode] scales from
+	// when sampling suggests a zero tip and no minimum price is configured.
+	DefaultCongestionBaseTip = big.NewInt(1 * params.GWei)
 )
 
 type Config struct {
@@ -90,6 +106,12 @@ type Config struct {
 	MaxPrice        *big.Int `toml:",omitempty"`
 	MinPrice        *big.Int `toml:",omitempty"`
 	MinGasUsed      *big.Int `toml:",omitempty"`
+	// Mode selects the algorithm used to suggest tips. Either [SamplingMode]
+	// or [CongestionMode]. Defaults to [SamplingMode] if empty.
+	Mode string
+	// CongestionThreshold is the number of pending transactions in the mempool
+	// above which [CongestionMode] scales up the suggested tip.
+	CongestionThreshold int
 }
 
 // OracleBackend includes all necessary background APIs for oracle.
@@ -103,6 +125,7 @@ type OracleBackend interface {
 	MinRequiredTip(ctx context.Context, header *types.Header) (*big.Int, error)
 	LastAcceptedBlock() *types.Block
 	GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error)
+	Stats() (pending int, queued int)
 }
 
 // Oracle recommends gas prices based on the content of recent
@@ -122,9 +145,11 @@ type Oracle struct {
 	fetchLock sync.Mutex
 
 	// clock to decide what set of rules to use when recommending a gas price
-	clock mockable.Clock
+	clock *mockable.Clock
 
 	checkBlocks, percentile int
+	mode                    string
+	congestionThreshold     int
 	maxLookbackSeconds      uint64
 	maxCallBlockHistory     uint64
 	maxBlockHistory         int
@@ -135,6 +160,12 @@ type Oracle struct {
 // NewOracle returns a new gasprice oracle which can recommend suitable
 // gasprice for newly created transaction.
 func NewOracle(backend OracleBackend, config Config) (*Oracle, error) {
+	return NewOracleWithClock(backend, config, &mockable.Clock{})
+}
+
+// NewOracleWithClock returns a new gasprice oracle that decides which rules
+// apply to the next block according to [clock].
+func NewOracleWithClock(backend OracleBackend, config Config, clock *mockable.Clock) (*Oracle, error) {
 	blocks := config.Blocks
 	if blocks < 1 {
 		blocks = 1
@@ -178,6 +209,19 @@ func NewOracle(backend OracleBackend, config Config) (*Oracle, error) {
 		maxBlockHistory = DefaultMaxBlockHistory
 		log.Warn("Sanitizing invalid gasprice oracle max block history", "provided", config.MaxBlockHistory, "updated", maxBlockHistory)
 	}
+	mode := config.Mode
+	switch mode {
+	case SamplingMode, CongestionMode:
+	case "":
+		mode = SamplingMode
+	default:
+		return nil, fmt.Errorf("invalid gasprice oracle mode %q", config.Mode)
+	}
+	congestionThreshold := config.CongestionThreshold
+	if mode == CongestionMode && congestionThreshold < 1 {
+		congestionThreshold = DefaultCongestionThreshold
+		log.Warn("Sanitizing invalid gasprice oracle congestion threshold", "provided", config.CongestionThreshold, "updated", congestionThreshold)
+	}
 
 	cache := lru.NewCache[uint64, *slimBlock](DefaultFeeHistoryCacheSize)
 	headEvent := make(chan core.ChainHeadEvent, 1)
@@ -211,11 +255,14 @@ func NewOracle(backend OracleBackend, config Config) (*Oracle, error) {
 		maxPrice:            maxPrice,
 		checkBlocks:         blocks,
 		percentile:          percent,
+		mode:                mode,
+		congestionThreshold: congestionThreshold,
 		maxLookbackSeconds:  maxLookbackSeconds,
 		maxCallBlockHistory: maxCallBlockHistory,
 		maxBlockHistory:     maxBlockHistory,
 		historyCache:        cache,
 		feeInfoProvider:     feeInfoProvider,
+		clock:               clock,
 	}, nil
 }
 
@@ -307,8 +354,38 @@ func (oracle *Oracle) SuggestTipCap(ctx context.Context) (*big.Int, error) {
 	return tip, err
 }
 
-// suggestDynamicFees estimates the gas tip and base fee based on a simple sampling method
+// suggestDynamicFees estimates the gas tip and base fee using the configured mode.
 func (oracle *Oracle) suggestDynamicFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tip, baseFee, err := oracle.sampleDynamicFees(ctx)
+	if err != nil || oracle.mode != CongestionMode {
+		return tip, baseFee, err
+	}
+	return oracle.scaleTipForCongestion(tip), baseFee, nil
+}
+
+// scaleTipForCongestion scales [tip] proportionally to the number of pending
+// transactions in the mempool once it exceeds the congestion threshold. The
+// result is capped at the oracle's maximum price.
+func (oracle *Oracle) scaleTipForCongestion(tip *big.Int) *big.Int {
+	pending, _ := oracle.backend.Stats()
+	if pending <= oracle.congestionThreshold {
+		return tip
+	}
+
+	baseTip := tip
+	if baseTip.Sign() == 0 {
+		baseTip = oracle.minPrice
+		if baseTip.Sign() == 0 {
+			baseTip = DefaultCongestionBaseTip
+		}
+	}
+	scaled := new(big.Int).Mul(baseTip, big.NewInt(int64(pending)))
+	scaled.Div(scaled, big.NewInt(int64(oracle.congestionThreshold)))
+	return math.BigMin(scaled, oracle.maxPrice)
+}
+
+// sampleDynamicFees estimates the gas tip and base fee based on a simple sampling method
+func (oracle *Oracle) sampleDynamicFees(ctx context.Context) (*big.Int, *big.Int, error) {
 	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if err != nil {
 		return nil, nil, err
@@ -57,6 +57,7 @@ var (
 type testBackend struct {
 	chain         *core.BlockChain
 	acceptedEvent chan<- core.ChainEvent
+	pendingTxs    int
 }
 
 func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -94,6 +95,10 @@ func (b *testBackend) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig
 	return b.chain.GetFeeConfigAt(parent)
 }
 
+func (b *testBackend) Stats() (int, int) {
+	return b.pendingTxs, 0
+}
+
 func (b *testBackend) teardown() {
 	b.chain.Stop()
 }
@@ -264,6 +269,59 @@ func TestSuggestTipCap(t *testing.T) {
 	}, defaultOracleConfig())
 }
 
+func TestSuggestTipCapCongestionMode(t *testing.T) {
+	config := defaultOracleConfig()
+	config.Mode = CongestionMode
+	config.CongestionThreshold = 100
+
+	tests := []struct {
+		name        string
+		pendingTxs  int
+		expectedTip *big.Int
+	}{
+		{
+			name:        "below threshold",
+			pendingTxs:  100,
+			expectedTip: big.NewInt(643_500_643),
+		},
+		{
+			name:        "above threshold",
+			pendingTxs:  250,
+			expectedTip: big.NewInt(1_608_751_607),
+		},
+		{
+			name:        "capped at max price",
+			pendingTxs:  1_000_000,
+			expectedTip: DefaultMaxPrice,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := newTestBackend(t, params.TestChainConfig, 3, testGenBlock(t, 55, 370))
+			defer backend.teardown()
+			backend.pendingTxs = test.pendingTxs
+
+			oracle, err := NewOracle(backend, config)
+			require.NoError(t, err)
+			oracle.clock.Set(time.Unix(20, 0))
+
+			got, err := oracle.SuggestTipCap(context.Background())
+			require.NoError(t, err)
+			require.Zero(t, got.Cmp(test.expectedTip), "expected tip %d, got %d", test.expectedTip, got)
+		})
+	}
+}
+
+func TestNewOracleInvalidMode(t *testing.T) {
+	backend := newTestBackend(t, params.TestChainConfig, 1, nil)
+	defer backend.teardown()
+
+	config := defaultOracleConfig()
+	config.Mode = "unknown"
+	_, err := NewOracle(backend, config)
+	require.ErrorContains(t, err, "invalid gasprice oracle mode")
+}
+
 func TestSuggestTipCapSimpleFloor(t *testing.T) {
 	applyGasPriceTest(t, suggestTipCapTest{
 		chainConfig: params.TestChainConfig,
@@ -93,6 +93,7 @@ type Backend interface {
 	BadBlocks() ([]*types.Block, []*core.BadBlockReason)
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 	RPCGasCap() uint64
+	RPCTraceTimeout() time.Duration // default timeout of a single transaction trace, or 0 for [defaultTraceTimeout]
 	ChainConfig() *params.ChainConfig
 	Engine() consensus.Engine
 	ChainDb() ethdb.Database
@@ -597,7 +598,7 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 		)
 		statedb.SetTxContext(tx.Hash(), i)
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
-			log.Warn("Tracing intermediate roots did not complete", "txindex", i, "txhash", tx.Hash(), "err", err)
+			log.Warn("Tracing intermediate roots did not complete", "requestID", rpc.RequestIDFromContext(ctx), "txindex", i, "txhash", tx.Hash(), "err", err)
 			// We intentionally don't return the error here: if we do, then the RPC server will not
 			// return the roots. Most likely, the caller already knows that a certain transaction fails to
 			// be included, but still want the intermediate roots that led to that point.
@@ -998,7 +999,7 @@ func (api *baseAPI) traceTx(ctx context.Context, message *core.Message, txctx *C
 	var (
 		tracer    Tracer
 		err       error
-		timeout   = defaultTraceTimeout
+		timeout   = api.traceTimeout()
 		txContext = core.NewEVMTxContext(message)
 	)
 	if config == nil {
@@ -1034,9 +1035,22 @@ func (api *baseAPI) traceTx(ctx context.Context, message *core.Message, txctx *C
 	// Call Prepare to clear out the statedb access list
 	statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
 	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit)); err != nil {
+		log.Debug("Tracing transaction failed", "requestID", rpc.RequestIDFromContext(ctx), "txhash", txctx.TxHash, "err", err)
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
-	return tracer.GetResult()
+	result, err := tracer.GetResult()
+	log.Debug("Traced transaction", "requestID", rpc.RequestIDFromContext(ctx), "txhash", txctx.TxHash, "err", err)
+	return result, err
+}
+
+// traceTimeout returns the amount of time a single transaction can execute before
+// being aborted, unless the trace config sets its own timeout. Aborted traces
+// return the partial result collected by the tracer.
+func (api *baseAPI) traceTimeout() time.Duration {
+	if timeout := api.backend.RPCTraceTimeout(); timeout > 0 {
+		return timeout
+	}
+	return defaultTraceTimeout
 }
 
 // APIs return the collection of RPC services the tracer package offers.
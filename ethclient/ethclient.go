@@ -102,6 +102,8 @@ type Client interface {
 	EstimateGas(context.Context, interfaces.CallMsg) (uint64, error)
 	EstimateBaseFee(context.Context) (*big.Int, error)
 	SendTransaction(context.Context, *types.Transaction) error
+	SendPrivateTransaction(context.Context, *types.Transaction) error
+	SendBundle(context.Context, types.Transactions) (common.Hash, error)
 }
 
 // client defines implementation for typed wrappers for the Ethereum RPC API.
@@ -483,16 +485,29 @@ func toFilterArg(q interfaces.FilterQuery) (interface{}, error) {
 	}
 	if q.BlockHash != nil {
 		arg["blockHash"] = *q.BlockHash
-		if q.FromBlock != nil || q.ToBlock != nil {
+		if q.FromBlock != nil || q.ToBlock != nil || q.FromTime != nil || q.ToTime != nil {
 			return nil, fmt.Errorf("cannot specify both BlockHash and FromBlock/ToBlock")
 		}
 	} else {
-		if q.FromBlock == nil {
+		switch {
+		case q.FromTime != nil:
+			if q.FromBlock != nil {
+				return nil, fmt.Errorf("cannot specify both FromBlock and FromTime")
+			}
+			arg["fromTimestamp"] = hexutil.Uint64(*q.FromTime)
+		case q.FromBlock == nil:
 			arg["fromBlock"] = "0x0"
-		} else {
+		default:
 			arg["fromBlock"] = ToBlockNumArg(q.FromBlock)
 		}
-		arg["toBlock"] = ToBlockNumArg(q.ToBlock)
+		if q.ToTime != nil {
+			if q.ToBlock != nil {
+				return nil, fmt.Errorf("cannot specify both ToBlock and ToTime")
+			}
+			arg["toTimestamp"] = hexutil.Uint64(*q.ToTime)
+		} else {
+			arg["toBlock"] = ToBlockNumArg(q.ToBlock)
+		}
 	}
 	return arg, nil
 }
@@ -631,6 +646,32 @@ func (ec *client) SendTransaction(ctx context.Context, tx *types.Transaction) er
 	return ec.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
 }
 
+// SendPrivateTransaction injects a signed transaction into the pending pool of the
+// node for inclusion in the blocks it builds, without gossiping it to other nodes.
+func (ec *client) SendPrivateTransaction(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return ec.c.CallContext(ctx, nil, "eth_sendPrivateTransaction", hexutil.Encode(data))
+}
+
+// SendBundle submits signed transactions to the node for inclusion in its next block
+// in the given order, all together or not at all. It returns the hash of the bundle.
+func (ec *client) SendBundle(ctx context.Context, txs types.Transactions) (common.Hash, error) {
+	inputs := make([]string, len(txs))
+	for i, tx := range txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		inputs[i] = hexutil.Encode(data)
+	}
+	var hash common.Hash
+	err := ec.c.CallContext(ctx, &hash, "eth_sendBundle", inputs)
+	return hash, err
+}
+
 func ToBlockNumArg(number *big.Int) string {
 	// The Ethereum implementation uses a different mapping from
 	// negative numbers to special strings (latest, pending) then is
@@ -156,6 +156,21 @@ func (ec *Client) CallContract(ctx context.Context, msg interfaces.CallMsg, bloc
 	return hex, err
 }
 
+// EstimateGas estimates the gas needed to execute a message call transaction at the
+// given block, after applying the state overrides as in CallContract.
+//
+// blockNumber selects the block height at which the estimate runs. It can be nil, in
+// which case the latest accepted block is used.
+// Please use ethclient.EstimateGas instead if you don't need the override functionality.
+func (ec *Client) EstimateGas(ctx context.Context, msg interfaces.CallMsg, blockNumber *big.Int, overrides *map[common.Address]OverrideAccount) (uint64, error) {
+	var hex hexutil.Uint64
+	err := ec.c.CallContext(
+		ctx, &hex, "eth_estimateGas", toCallArg(msg),
+		ethclient.ToBlockNumArg(blockNumber), toOverrideMap(overrides),
+	)
+	return uint64(hex), err
+}
+
 // GCStats retrieves the current garbage collection stats from a geth node.
 func (ec *Client) GCStats(ctx context.Context) (*debug.GCStats, error) {
 	var result debug.GCStats
@@ -132,6 +132,8 @@ type FilterQuery struct {
 	BlockHash *common.Hash     // used by eth_getLogs, return logs only from block with this hash
 	FromBlock *big.Int         // beginning of the queried range, nil means genesis block
 	ToBlock   *big.Int         // end of the range, nil means latest block
+	FromTime  *uint64          // beginning of the queried range by block timestamp, exclusive with FromBlock
+	ToTime    *uint64          // end of the queried range by block timestamp, exclusive with ToBlock
 	Addresses []common.Address // restricts matches to events created by specific contracts
 
 	// The Topic list restricts matches to particular event topics. Each event has a list
@@ -0,0 +1,78 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const headerSize = 8
+
+var errReservedNonZero = errors.New("e2store entry has non-zero reserved bytes")
+
+// entry is a single e2store record: a 2 byte type, a 4 byte little endian length,
+// 2 reserved zero bytes and the value.
+type entry struct {
+	Type  uint16
+	Value []byte
+}
+
+// e2storeWriter writes e2store records to an underlying writer and tracks the
+// offset of the next record.
+type e2storeWriter struct {
+	w      io.Writer
+	offset int64
+}
+
+func newE2storeWriter(w io.Writer) *e2storeWriter {
+	return &e2storeWriter{w: w}
+}
+
+// Write writes a single record of [typ] containing [value] and returns the number of
+// bytes written.
+func (w *e2storeWriter) Write(typ uint16, value []byte) (int, error) {
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint16(header[0:2], typ)
+	binary.LittleEndian.PutUint32(header[2:6], uint32(len(value)))
+	n, err := w.w.Write(header[:])
+	w.offset += int64(n)
+	if err != nil {
+		return n, err
+	}
+	m, err := w.w.Write(value)
+	w.offset += int64(m)
+	return n + m, err
+}
+
+// e2storeReader reads e2store records from an underlying reader at arbitrary offsets.
+type e2storeReader struct {
+	r io.ReaderAt
+}
+
+func newE2storeReader(r io.ReaderAt) *e2storeReader {
+	return &e2storeReader{r: r}
+}
+
+// ReadAt reads the record starting at [off] and returns it along with the total
+// number of bytes it occupies.
+func (r *e2storeReader) ReadAt(off int64) (*entry, int, error) {
+	var header [headerSize]byte
+	if _, err := r.r.ReadAt(header[:], off); err != nil {
+		return nil, 0, fmt.Errorf("failed to read e2store header at offset %d: %w", off, err)
+	}
+	if header[6] != 0 || header[7] != 0 {
+		return nil, 0, fmt.Errorf("%w: offset %d", errReservedNonZero, off)
+	}
+	e := &entry{
+		Type:  binary.LittleEndian.Uint16(header[0:2]),
+		Value: make([]byte, binary.LittleEndian.Uint32(header[2:6])),
+	}
+	if _, err := r.r.ReadAt(e.Value, off+headerSize); err != nil {
+		return nil, 0, fmt.Errorf("failed to read e2store value at offset %d: %w", off, err)
+	}
+	return e, headerSize + len(e.Value), nil
+}
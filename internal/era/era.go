@@ -0,0 +1,378 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package era implements an era1-style archive format for accepted blocks and
+// their receipts.
+//
+// An archive is an e2store file with the following layout:
+//
+//	Version | (CompressedHeader | CompressedBody | CompressedReceipts)* | Accumulator | BlockIndex
+//
+// Headers, bodies and receipts are RLP encoded and snappy compressed. The accumulator
+// is the keccak256 hash of the concatenated hashes of the archived blocks, which
+// allows archives to be compared against a trusted value without reading them fully.
+// The block index holds the number of the first block, the file offset of the header
+// record of each block and the number of blocks in the archive.
+package era
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+const (
+	typeVersion            uint16 = 0x3265
+	typeCompressedHeader   uint16 = 0x03
+	typeCompressedBody     uint16 = 0x04
+	typeCompressedReceipts uint16 = 0x05
+	typeAccumulator        uint16 = 0x07
+	typeBlockIndex         uint16 = 0x3266
+
+	// MaxSize is the maximum number of blocks stored in a single archive.
+	MaxSize = 8192
+)
+
+var (
+	errArchiveFull       = errors.New("archive is full")
+	errArchiveFinalized  = errors.New("archive is finalized")
+	errArchiveEmpty      = errors.New("archive is empty")
+	errNonSequential     = errors.New("block is not sequential")
+	errUnexpectedType    = errors.New("unexpected e2store entry type")
+	errOutOfRange        = errors.New("block is not in archive")
+	errInvalidBlockIndex = errors.New("invalid block index")
+)
+
+// Filename returns the canonical file name of the archive of [network] with the
+// given [epoch] and [accumulator].
+func Filename(network string, epoch uint64, accumulator common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%s.era1", network, epoch, accumulator.Hex()[2:10])
+}
+
+// Builder writes blocks and receipts to an archive.
+type Builder struct {
+	w         *e2storeWriter
+	start     *uint64
+	offsets   []int64
+	hashes    []byte
+	finalized bool
+}
+
+// NewBuilder returns a Builder writing an archive to [w].
+func NewBuilder(w io.Writer) *Builder {
+	return &Builder{w: newE2storeWriter(w)}
+}
+
+// Add appends [block] and its [receipts] to the archive. Blocks must be added in
+// ascending order without gaps.
+func (b *Builder) Add(block *types.Block, receipts types.Receipts) error {
+	if b.finalized {
+		return errArchiveFinalized
+	}
+	if len(b.offsets) >= MaxSize {
+		return errArchiveFull
+	}
+	if b.start == nil {
+		if _, err := b.w.Write(typeVersion, nil); err != nil {
+			return err
+		}
+		start := block.NumberU64()
+		b.start = &start
+	} else if expected := *b.start + uint64(len(b.offsets)); block.NumberU64() != expected {
+		return fmt.Errorf("%w: have %d, want %d", errNonSequential, block.NumberU64(), expected)
+	}
+
+	b.offsets = append(b.offsets, b.w.offset)
+	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+	for i, receipt := range receipts {
+		storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+	}
+	for _, record := range []struct {
+		typ uint16
+		val interface{}
+	}{
+		{typeCompressedHeader, block.Header()},
+		{typeCompressedBody, block.Body()},
+		{typeCompressedReceipts, storageReceipts},
+	} {
+		if err := b.writeCompressed(record.typ, record.val); err != nil {
+			return err
+		}
+	}
+	b.hashes = append(b.hashes, block.Hash().Bytes()...)
+	return nil
+}
+
+func (b *Builder) writeCompressed(typ uint16, val interface{}) error {
+	encoded, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	_, err = b.w.Write(typ, snappy.Encode(nil, encoded))
+	return err
+}
+
+// Finalize writes the accumulator and block index of the archive and returns the
+// accumulator. No blocks may be added after the archive is finalized.
+func (b *Builder) Finalize() (common.Hash, error) {
+	if b.finalized {
+		return common.Hash{}, errArchiveFinalized
+	}
+	if b.start == nil {
+		return common.Hash{}, errArchiveEmpty
+	}
+	b.finalized = true
+
+	accumulator := crypto.Keccak256Hash(b.hashes)
+	if _, err := b.w.Write(typeAccumulator, accumulator.Bytes()); err != nil {
+		return common.Hash{}, err
+	}
+	index := make([]byte, 16+8*len(b.offsets))
+	binary.LittleEndian.PutUint64(index, *b.start)
+	for i, offset := range b.offsets {
+		binary.LittleEndian.PutUint64(index[8+8*i:], uint64(offset))
+	}
+	binary.LittleEndian.PutUint64(index[8+8*len(b.offsets):], uint64(len(b.offsets)))
+	if _, err := b.w.Write(typeBlockIndex, index); err != nil {
+		return common.Hash{}, err
+	}
+	return accumulator, nil
+}
+
+// ReadAtCloser is the interface an archive is read from.
+type ReadAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// Era provides random access to the blocks and receipts of an archive.
+type Era struct {
+	f       ReadAtCloser
+	r       *e2storeReader
+	start   uint64
+	offsets []int64
+	// offset of the accumulator record
+	accumulatorOffset int64
+}
+
+// Open opens the archive at [path].
+func Open(path string) (*Era, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	e, err := From(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// From reads the block index of the archive of [size] bytes held by [f].
+func From(f ReadAtCloser, size int64) (*Era, error) {
+	if size < headerSize+16 {
+		return nil, fmt.Errorf("%w: archive too small", errInvalidBlockIndex)
+	}
+	var buf [8]byte
+	if _, err := f.ReadAt(buf[:], size-8); err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(buf[:])
+	if count == 0 || count > MaxSize {
+		return nil, fmt.Errorf("%w: block count %d", errInvalidBlockIndex, count)
+	}
+	indexOffset := size - int64(headerSize+16+8*count)
+	if indexOffset < 0 {
+		return nil, fmt.Errorf("%w: block count %d exceeds archive size", errInvalidBlockIndex, count)
+	}
+
+	r := newE2storeReader(f)
+	index, _, err := r.ReadAt(indexOffset)
+	if err != nil {
+		return nil, err
+	}
+	if index.Type != typeBlockIndex {
+		return nil, fmt.Errorf("%w: have %#x, want block index", errUnexpectedType, index.Type)
+	}
+	e := &Era{
+		f:                 f,
+		r:                 r,
+		start:             binary.LittleEndian.Uint64(index.Value),
+		offsets:           make([]int64, count),
+		accumulatorOffset: indexOffset - (headerSize + common.HashLength),
+	}
+	for i := range e.offsets {
+		e.offsets[i] = int64(binary.LittleEndian.Uint64(index.Value[8+8*i:]))
+		if e.offsets[i] < 0 || e.offsets[i] >= e.accumulatorOffset {
+			return nil, fmt.Errorf("%w: offset %d of block %d", errInvalidBlockIndex, e.offsets[i], e.start+uint64(i))
+		}
+	}
+	return e, nil
+}
+
+// Close closes the underlying archive file.
+func (e *Era) Close() error { return e.f.Close() }
+
+// Start returns the number of the first block in the archive.
+func (e *Era) Start() uint64 { return e.start }
+
+// Count returns the number of blocks in the archive.
+func (e *Era) Count() uint64 { return uint64(len(e.offsets)) }
+
+// Accumulator returns the accumulator stored in the archive.
+func (e *Era) Accumulator() (common.Hash, error) {
+	record, _, err := e.r.ReadAt(e.accumulatorOffset)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if record.Type != typeAccumulator || len(record.Value) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("%w: have %#x, want accumulator", errUnexpectedType, record.Type)
+	}
+	return common.BytesToHash(record.Value), nil
+}
+
+// GetBlockByNumber returns block [number] from the archive.
+func (e *Era) GetBlockByNumber(number uint64) (*types.Block, error) {
+	off, err := e.offset(number)
+	if err != nil {
+		return nil, err
+	}
+	var header types.Header
+	n, err := e.readCompressed(off, typeCompressedHeader, &header)
+	if err != nil {
+		return nil, err
+	}
+	var body types.Body
+	if _, err := e.readCompressed(off+int64(n), typeCompressedBody, &body); err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(&header).WithBody(body.Transactions, body.Uncles), nil
+}
+
+// GetReceiptsByNumber returns the receipts of block [number] from the archive. Only
+// the consensus fields of the receipts are populated.
+func (e *Era) GetReceiptsByNumber(number uint64) (types.Receipts, error) {
+	off, err := e.offset(number)
+	if err != nil {
+		return nil, err
+	}
+	// Skip the header and body records.
+	for i := 0; i < 2; i++ {
+		_, n, err := e.r.ReadAt(off)
+		if err != nil {
+			return nil, err
+		}
+		off += int64(n)
+	}
+	var storageReceipts []*types.ReceiptForStorage
+	if _, err := e.readCompressed(off, typeCompressedReceipts, &storageReceipts); err != nil {
+		return nil, err
+	}
+	receipts := make(types.Receipts, len(storageReceipts))
+	for i, receipt := range storageReceipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	return receipts, nil
+}
+
+func (e *Era) offset(number uint64) (int64, error) {
+	if number < e.start || number-e.start >= e.Count() {
+		return 0, fmt.Errorf("%w: %d not in [%d, %d]", errOutOfRange, number, e.start, e.start+e.Count()-1)
+	}
+	return e.offsets[number-e.start], nil
+}
+
+// readCompressed reads the record at [off], which must be of [typ], and decodes its
+// value into [val]. Returns the number of bytes occupied by the record.
+func (e *Era) readCompressed(off int64, typ uint16, val interface{}) (int, error) {
+	record, n, err := e.r.ReadAt(off)
+	if err != nil {
+		return 0, err
+	}
+	if record.Type != typ {
+		return 0, fmt.Errorf("%w: have %#x, want %#x at offset %d", errUnexpectedType, record.Type, typ, off)
+	}
+	decoded, err := snappy.Decode(nil, record.Value)
+	if err != nil {
+		return 0, err
+	}
+	return n, rlp.DecodeBytes(decoded, val)
+}
+
+// Verify checks the contents of the archive: the blocks must form a chain, the
+// transactions, uncles and receipts of each block must match the roots committed
+// to in its header and the accumulator must match the archived blocks. Returns the
+// accumulator.
+func (e *Era) Verify() (common.Hash, error) {
+	var (
+		hashes     = make([]byte, 0, common.HashLength*e.Count())
+		parentHash common.Hash
+	)
+	for number := e.start; number < e.start+e.Count(); number++ {
+		block, err := e.GetBlockByNumber(number)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if number > e.start && block.ParentHash() != parentHash {
+			return common.Hash{}, fmt.Errorf("block %d parent hash mismatch: have %s, want %s", number, block.ParentHash(), parentHash)
+		}
+		receipts, err := e.GetReceiptsByNumber(number)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if err := VerifyBlock(block, receipts); err != nil {
+			return common.Hash{}, err
+		}
+		parentHash = block.Hash()
+		hashes = append(hashes, parentHash.Bytes()...)
+	}
+	accumulator, err := e.Accumulator()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if computed := crypto.Keccak256Hash(hashes); computed != accumulator {
+		return common.Hash{}, fmt.Errorf("accumulator mismatch: have %s, want %s", computed, accumulator)
+	}
+	return accumulator, nil
+}
+
+// VerifyBlock checks that the transactions and uncles of [block] and [receipts] match
+// the roots committed to in the header of [block].
+func VerifyBlock(block *types.Block, receipts types.Receipts) error {
+	txs := block.Transactions()
+	if hash := types.DeriveSha(txs, trie.NewStackTrie(nil)); hash != block.TxHash() {
+		return fmt.Errorf("block %d transaction root mismatch: have %s, want %s", block.NumberU64(), hash, block.TxHash())
+	}
+	if hash := types.CalcUncleHash(block.Uncles()); hash != block.UncleHash() {
+		return fmt.Errorf("block %d uncle root mismatch: have %s, want %s", block.NumberU64(), hash, block.UncleHash())
+	}
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("block %d receipt count mismatch: have %d, want %d", block.NumberU64(), len(receipts), len(txs))
+	}
+	// Receipts are archived in storage form, which omits the type.
+	for i, receipt := range receipts {
+		receipt.Type = txs[i].Type()
+	}
+	if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != block.ReceiptHash() {
+		return fmt.Errorf("block %d receipt root mismatch: have %s, want %s", block.NumberU64(), hash, block.ReceiptHash())
+	}
+	if bloom := types.CreateBloom(receipts); bloom != block.Bloom() {
+		return fmt.Errorf("block %d bloom mismatch", block.NumberU64())
+	}
+	return nil
+}
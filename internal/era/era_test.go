@@ -0,0 +1,124 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestChain(start uint64, count int) ([]*types.Block, []types.Receipts) {
+	var (
+		blocks     []*types.Block
+		receipts   []types.Receipts
+		parentHash common.Hash
+	)
+	for i := 0; i < count; i++ {
+		number := start + uint64(i)
+		to := common.Address{byte(i)}
+		txs := []*types.Transaction{
+			types.NewTx(&types.LegacyTx{Nonce: number, To: &to, Value: big.NewInt(int64(i)), Gas: 21_000}),
+			types.NewTx(&types.DynamicFeeTx{Nonce: number + 1, To: &to, Gas: 21_000}),
+		}
+		blockReceipts := types.Receipts{
+			{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21_000, Logs: []*types.Log{}},
+			{Type: types.DynamicFeeTxType, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 42_000, Logs: []*types.Log{
+				{Address: to, Topics: []common.Hash{{byte(i)}}, Data: []byte{byte(i)}},
+			}},
+		}
+		header := &types.Header{
+			ParentHash: parentHash,
+			Number:     new(big.Int).SetUint64(number),
+			Time:       number,
+			GasLimit:   8_000_000,
+			Difficulty: common.Big1,
+		}
+		block := types.NewBlock(header, txs, nil, blockReceipts, trie.NewStackTrie(nil))
+		parentHash = block.Hash()
+		blocks = append(blocks, block)
+		receipts = append(receipts, blockReceipts)
+	}
+	return blocks, receipts
+}
+
+func writeTestArchive(t *testing.T, blocks []*types.Block, receipts []types.Receipts) (string, common.Hash) {
+	path := filepath.Join(t.TempDir(), "test.era1")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	builder := NewBuilder(f)
+	for i, block := range blocks {
+		require.NoError(t, builder.Add(block, receipts[i]))
+	}
+	accumulator, err := builder.Finalize()
+	require.NoError(t, err)
+	return path, accumulator
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	blocks, receipts := makeTestChain(10, 5)
+	path, accumulator := writeTestArchive(t, blocks, receipts)
+
+	e, err := Open(path)
+	require.NoError(t, err)
+	defer e.Close()
+
+	require.Equal(t, uint64(10), e.Start())
+	require.Equal(t, uint64(5), e.Count())
+	stored, err := e.Accumulator()
+	require.NoError(t, err)
+	require.Equal(t, accumulator, stored)
+
+	for i, want := range blocks {
+		block, err := e.GetBlockByNumber(want.NumberU64())
+		require.NoError(t, err)
+		require.Equal(t, want.Hash(), block.Hash())
+		require.Len(t, block.Transactions(), len(want.Transactions()))
+
+		blockReceipts, err := e.GetReceiptsByNumber(want.NumberU64())
+		require.NoError(t, err)
+		require.Len(t, blockReceipts, len(receipts[i]))
+		require.NoError(t, VerifyBlock(block, blockReceipts))
+	}
+
+	_, err = e.GetBlockByNumber(9)
+	require.ErrorIs(t, err, errOutOfRange)
+	_, err = e.GetBlockByNumber(15)
+	require.ErrorIs(t, err, errOutOfRange)
+
+	verified, err := e.Verify()
+	require.NoError(t, err)
+	require.Equal(t, accumulator, verified)
+}
+
+func TestBuilderRejectsInvalidSequences(t *testing.T) {
+	blocks, receipts := makeTestChain(0, 3)
+
+	builder := NewBuilder(io.Discard)
+	_, err := builder.Finalize()
+	require.ErrorIs(t, err, errArchiveEmpty)
+
+	require.NoError(t, builder.Add(blocks[0], receipts[0]))
+	require.ErrorIs(t, builder.Add(blocks[2], receipts[2]), errNonSequential)
+	require.NoError(t, builder.Add(blocks[1], receipts[1]))
+
+	_, err = builder.Finalize()
+	require.NoError(t, err)
+	require.ErrorIs(t, builder.Add(blocks[2], receipts[2]), errArchiveFinalized)
+}
+
+func TestVerifyBlockRejectsMismatchedReceipts(t *testing.T) {
+	blocks, receipts := makeTestChain(0, 2)
+	require.Error(t, VerifyBlock(blocks[0], receipts[1]))
+	require.Error(t, VerifyBlock(blocks[0], receipts[0][:1]))
+}
@@ -32,6 +32,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 
@@ -41,12 +42,15 @@ import (
 	"github.com/ava-labs/subnet-evm/accounts/scwallet"
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/core/vm"
 	"github.com/ava-labs/subnet-evm/eth/tracers/logger"
+	"github.com/ava-labs/subnet-evm/ethdb"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/trie"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
@@ -460,7 +464,7 @@ func (s *PersonalAccountAPI) SendTransaction(ctx context.Context, args Transacti
 	}
 	signed, err := s.signTransaction(ctx, &args, passwd)
 	if err != nil {
-		log.Warn("Failed transaction send attempt", "from", args.from(), "to", args.To, "value", args.Value.ToInt(), "err", err)
+		log.Warn("Failed transaction send attempt", "requestID", rpc.RequestIDFromContext(ctx), "from", args.from(), "to", args.To, "value", args.Value.ToInt(), "err", err)
 		return common.Hash{}, err
 	}
 	return SubmitTransaction(ctx, s.b, signed)
@@ -487,12 +491,12 @@ func (s *PersonalAccountAPI) SignTransaction(ctx context.Context, args Transacti
 	}
 	// Before actually signing the transaction, ensure the transaction fee is reasonable.
 	tx := args.toTransaction()
-	if err := checkTxFee(tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
+	if err := checkTxFee(s.b.ChainConfig(), tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
 		return nil, err
 	}
 	signed, err := s.signTransaction(ctx, &args, passwd)
 	if err != nil {
-		log.Warn("Failed transaction sign attempt", "from", args.from(), "to", args.To, "value", args.Value.ToInt(), "err", err)
+		log.Warn("Failed transaction sign attempt", "requestID", rpc.RequestIDFromContext(ctx), "from", args.from(), "to", args.To, "value", args.Value.ToInt(), "err", err)
 		return nil, err
 	}
 	data, err := signed.MarshalBinary()
@@ -690,11 +694,30 @@ type StorageResult struct {
 }
 
 // GetProof returns the Merkle-proof for a given account and optionally some storage keys.
+//
+// Proofs are served at any height for which the node retains the state, which
+// with pruning disabled is every accepted height, subject to the configured
+// historical proof query window. This includes the storage of precompiles,
+// which is part of the state trie like that of any other account.
 func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
-	if state == nil || err != nil {
+	header, err := s.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	if err := checkProofQueryWindow(header.Number.Uint64(), s.b.LastAcceptedBlock().NumberU64(), s.b.HistoricalProofQueryWindow()); err != nil {
+		return nil, err
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithHash(header.Hash(), false))
+	if err != nil {
+		var missingNodeErr *trie.MissingNodeError
+		if errors.As(err, &missingNodeErr) {
+			return nil, fmt.Errorf("%w: block %d (state may have been pruned, disable pruning to serve historical proofs): %v", errStateUnavailable, header.Number.Uint64(), err)
+		}
 		return nil, err
 	}
+	if state == nil {
+		return nil, nil
+	}
 	storageTrie, err := state.StorageTrie(address)
 	if err != nil {
 		return nil, err
@@ -745,6 +768,21 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 	}, state.Error()
 }
 
+var (
+	errStateUnavailable = errors.New("state unavailable")
+	errProofQueryWindow = errors.New("block outside of historical proof query window")
+)
+
+// checkProofQueryWindow returns an error if proofs are not served at [number]
+// because it is more than [window] blocks before [lastAccepted]. A zero [window]
+// serves proofs at any height.
+func checkProofQueryWindow(number, lastAccepted, window uint64) error {
+	if window == 0 || number >= lastAccepted || lastAccepted-number <= window {
+		return nil
+	}
+	return fmt.Errorf("%w: block %d is more than %d blocks before the last accepted block %d", errProofQueryWindow, number, window, lastAccepted)
+}
+
 // decodeHash parses a hex-encoded 32-byte hash. The input may optionally
 // be prefixed by 0x and can have an byte length up to 32.
 func decodeHash(s string) (common.Hash, error) {
@@ -813,6 +851,109 @@ func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNu
 	return nil, err
 }
 
+// GetBlockByTimestamp returns the accepted block with the timestamp nearest to [timestamp],
+// preferring the earlier block if two blocks are equally near. When fullTx is true all
+// transactions in the block are returned in full detail, otherwise only the transaction
+// hash is returned.
+func (s *BlockChainAPI) GetBlockByTimestamp(ctx context.Context, timestamp hexutil.Uint64, fullTx bool) (map[string]interface{}, error) {
+	number, err := s.blockNumberByTimestamp(ctx, uint64(timestamp))
+	if err != nil {
+		return nil, err
+	}
+	return s.GetBlockByNumber(ctx, rpc.BlockNumber(number), fullTx)
+}
+
+// blockNumberByTimestamp returns the number of the accepted block with the timestamp
+// nearest to [timestamp].
+func (s *BlockChainAPI) blockNumberByTimestamp(ctx context.Context, timestamp uint64) (uint64, error) {
+	lastAccepted := s.b.LastAcceptedBlock()
+	if lastAccepted == nil {
+		return 0, errors.New("no accepted block")
+	}
+	head := lastAccepted.NumberU64()
+
+	// Find the first block at or after [timestamp], which is the last accepted block
+	// if there is none.
+	number, err := BlockNumberAtOrAfterTimestamp(ctx, s.b, timestamp, head)
+	if err != nil {
+		return 0, err
+	}
+	if number > head {
+		number = head
+	}
+
+	// Block timestamps are non-decreasing, so the nearest block is either the first
+	// block at or after [timestamp] or the block before it.
+	blockTime, err := headerTime(ctx, s.b, number)
+	if err != nil {
+		return 0, err
+	}
+	if number == 0 || blockTime <= timestamp {
+		return number, nil
+	}
+	prevTime, err := headerTime(ctx, s.b, number-1)
+	if err != nil {
+		return 0, err
+	}
+	if timestamp-prevTime <= blockTime-timestamp {
+		return number - 1, nil
+	}
+	return number, nil
+}
+
+// TimestampBackend is the part of an API backend needed to look up accepted blocks by
+// timestamp.
+type TimestampBackend interface {
+	ChainDb() ethdb.Database
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+}
+
+// BlockNumberAtOrAfterTimestamp returns the number of the first accepted block up to [head]
+// with a timestamp at or after [timestamp], or head+1 if there is none. The block is looked
+// up in the timestamp index, falling back to a search of the headers for blocks accepted
+// before the index was maintained.
+func BlockNumberAtOrAfterTimestamp(ctx context.Context, b TimestampBackend, timestamp uint64, head uint64) (uint64, error) {
+	number, ok := rawdb.ReadBlockNumberAtOrAfterTimestamp(b.ChainDb(), timestamp)
+	if !ok || number > head {
+		number = head + 1
+	}
+	// Blocks accepted before the timestamp index was maintained are not indexed, so
+	// search the headers below the indexed block if the index may have missed one.
+	if number > 0 {
+		prevTime, err := headerTime(ctx, b, number-1)
+		if err != nil {
+			return 0, err
+		}
+		if prevTime >= timestamp {
+			var searchErr error
+			number = uint64(sort.Search(int(number-1), func(i int) bool {
+				blockTime, err := headerTime(ctx, b, uint64(i))
+				if err != nil {
+					searchErr = err
+					return true
+				}
+				return blockTime >= timestamp
+			}))
+			if searchErr != nil {
+				return 0, searchErr
+			}
+		}
+	}
+	return number, nil
+}
+
+// headerTime returns the timestamp of the canonical block [number].
+func headerTime(ctx context.Context, b TimestampBackend, number uint64) (uint64, error) {
+	header, err := b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("header %d not found", number)
+	}
+	return header.Time, nil
+}
+
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
 // detail, otherwise only the transaction hash is returned.
 func (s *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
@@ -989,7 +1130,9 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 }
 
 func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+	defer func(start time.Time) {
+		log.Debug("Executing EVM call finished", "requestID", rpc.RequestIDFromContext(ctx), "runtime", time.Since(start))
+	}(time.Now())
 
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
@@ -1006,7 +1149,7 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 		// Grab the hash of the unmodified header, so that the modified header can point to the
 		// prior block as its parent.
 		parentHash := header.Hash()
-		header.Time = uint64(time.Now().Unix())
+		header.Time = uint64(b.CurrentTime().Unix())
 		header.ParentHash = parentHash
 		header.Number = new(big.Int).Add(header.Number, big.NewInt(1))
 		estimatedBaseFee, err := b.EstimateBaseFee(ctx)
@@ -1133,16 +1276,20 @@ func (s *BlockChainAPI) CallDetailed(ctx context.Context, args TransactionArgs,
 func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
 	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
 	if err != nil {
-		return nil, err
+		return nil, toAPIError(err)
+	}
+	rpc.AnnotateSlowQuery(ctx, "gasUsed", result.UsedGas)
+	if result.Err != nil {
+		log.Debug("EVM call failed", "requestID", rpc.RequestIDFromContext(ctx), "err", result.Err)
 	}
 	// If the result contains a revert reason, try to unpack and return it.
 	if len(result.Revert()) > 0 {
 		return nil, newRevertError(result)
 	}
-	return result.Return(), result.Err
+	return result.Return(), toAPIError(result.Err)
 }
 
-func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -1184,6 +1331,10 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		if err != nil {
 			return 0, err
 		}
+		// Apply the overrides so that an overridden balance is used as the allowance.
+		if err := overrides.Apply(state); err != nil {
+			return 0, err
+		}
 		balance := state.GetBalance(*args.From) // from can't be nil
 		available := new(big.Int).Set(balance)
 		if args.Value != nil {
@@ -1200,14 +1351,14 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 			if transfer == nil {
 				transfer = new(hexutil.Big)
 			}
-			log.Info("Gas estimation capped by limited funds", "original", hi, "balance", balance,
+			log.Info("Gas estimation capped by limited funds", "requestID", rpc.RequestIDFromContext(ctx), "original", hi, "balance", balance,
 				"sent", transfer.ToInt(), "maxFeePerGas", feeCap, "fundable", allowance)
 			hi = allowance.Uint64()
 		}
 	}
 	// Recap the highest gas allowance with specified gascap.
 	if gasCap != 0 && hi > gasCap {
-		log.Info("Caller gas above allowance, capping", "requested", hi, "cap", gasCap)
+		log.Info("Caller gas above allowance, capping", "requestID", rpc.RequestIDFromContext(ctx), "requested", hi, "cap", gasCap)
 		hi = gasCap
 	}
 	cap = hi
@@ -1216,7 +1367,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
 
-		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, 0, gasCap)
+		result, err := DoCall(ctx, b, args, blockNrOrHash, overrides, 0, gasCap)
 		if err != nil {
 			if errors.Is(err, core.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -1259,17 +1410,41 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 			return 0, fmt.Errorf("gas required exceeds allowance (%d)", cap)
 		}
 	}
-	return hexutil.Uint64(hi), nil
+	multiplier, padding := b.RPCGasEstimateBuffer()
+	return hexutil.Uint64(bufferGasEstimate(hi, cap, multiplier, padding)), nil
+}
+
+// bufferGasEstimate multiplies [gas] by [multiplier] and adds [padding], so that
+// the estimate remains sufficient if the state changes before the transaction
+// is included, as it does when fees change quickly. The result never exceeds
+// [cap]. A multiplier below 1 is ignored.
+func bufferGasEstimate(gas, cap uint64, multiplier float64, padding uint64) uint64 {
+	buffered := gas
+	if multiplier > 1 {
+		scaled := float64(gas) * multiplier
+		if scaled >= float64(cap) {
+			return cap
+		}
+		buffered = uint64(scaled)
+	}
+	if padding >= cap || buffered > cap-padding {
+		return cap
+	}
+	return buffered + padding
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block, or the given block if
+// specified. The optional state overrides are applied as in eth_call, so that
+// the estimate can depend on state created by a preceding transaction.
+func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Uint64, error) {
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+	gas, err := DoEstimateGas(ctx, s.b, args, bNrOrHash, overrides, s.b.RPCGasCap())
+	rpc.AnnotateSlowQuery(ctx, "gasEstimate", uint64(gas))
+	return gas, toAPIError(err)
 }
 
 // RPCMarshalHeader converts the given header to the RPC output .
@@ -1724,7 +1899,31 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 	}
 
 	// Transaction unknown, return as such
-	return nil, nil
+	return nil, txNotFoundError(s.b.TxUnindexing(), s.b.ChainDb())
+}
+
+// txIndexOutOfRangeError is returned when a transaction is not found while the lookup
+// indices of transactions below [tail] are being deleted, so the transaction may have
+// been accepted in a block whose index is being deleted.
+type txIndexOutOfRangeError struct {
+	tail uint64
+}
+
+func (e *txIndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("transaction not found, transaction indexing is in progress from block %d", e.tail)
+}
+
+// txNotFoundError returns the error to report for a transaction that was not found,
+// which is nil unless transaction lookup indices are being deleted ([unindexing]).
+// Once the indices are up to date, an unknown transaction is reported as such.
+func txNotFoundError(unindexing bool, db ethdb.KeyValueReader) error {
+	if !unindexing {
+		return nil
+	}
+	if tail := rawdb.ReadTxIndexTail(db); tail != nil && *tail > 0 {
+		return &txIndexOutOfRangeError{tail: *tail}
+	}
+	return nil
 }
 
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
@@ -1737,7 +1936,7 @@ func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash commo
 	if tx == nil {
 		if tx = s.b.GetPoolTransaction(hash); tx == nil {
 			// Transaction not found anywhere, abort
-			return nil, nil
+			return nil, txNotFoundError(s.b.TxUnindexing(), s.b.ChainDb())
 		}
 	}
 	// Serialize to RLP and return
@@ -1818,17 +2017,28 @@ func (s *TransactionAPI) sign(addr common.Address, tx *types.Transaction) (*type
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	return submitTransaction(ctx, b, tx, false)
+}
+
+// submitTransaction is a helper function that submits tx to txPool and logs a message.
+// If [private] is set, the transaction is not gossiped to other nodes.
+func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction, private bool) (common.Hash, error) {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
-	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
+	if err := checkTxFee(b.ChainConfig(), tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
 		return common.Hash{}, err
 	}
 	if !b.UnprotectedAllowed(tx) && !tx.Protected() {
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
 		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 	}
-	if err := b.SendTx(ctx, tx); err != nil {
-		return common.Hash{}, err
+	send := b.SendTx
+	if private {
+		send = b.SendPrivateTx
+	}
+	if err := send(ctx, tx); err != nil {
+		log.Debug("Transaction rejected by tx pool", "requestID", rpc.RequestIDFromContext(ctx), "hash", tx.Hash(), "private", private, "err", err)
+		return common.Hash{}, toAPIError(err)
 	}
 	// Print a log with full tx details for manual investigations and interventions
 	currentBlock := b.CurrentBlock()
@@ -1840,9 +2050,9 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 
 	if tx.To() == nil {
 		addr := crypto.CreateAddress(from, tx.Nonce())
-		log.Info("Submitted contract creation", "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "contract", addr.Hex(), "value", tx.Value(), "type", tx.Type(), "gasFeeCap", tx.GasFeeCap(), "gasTipCap", tx.GasTipCap(), "gasPrice", tx.GasPrice())
+		log.Info("Submitted contract creation", "requestID", rpc.RequestIDFromContext(ctx), "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "contract", addr.Hex(), "value", tx.Value(), "type", tx.Type(), "gasFeeCap", tx.GasFeeCap(), "gasTipCap", tx.GasTipCap(), "gasPrice", tx.GasPrice(), "private", private)
 	} else {
-		log.Info("Submitted transaction", "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "recipient", tx.To(), "value", tx.Value(), "type", tx.Type(), "gasFeeCap", tx.GasFeeCap(), "gasTipCap", tx.GasTipCap(), "gasPrice", tx.GasPrice())
+		log.Info("Submitted transaction", "requestID", rpc.RequestIDFromContext(ctx), "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "recipient", tx.To(), "value", tx.Value(), "type", tx.Type(), "gasFeeCap", tx.GasFeeCap(), "gasTipCap", tx.GasTipCap(), "gasPrice", tx.GasPrice(), "private", private)
 	}
 	return tx.Hash(), nil
 }
@@ -1906,6 +2116,46 @@ func (s *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil.B
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// SendPrivateTransaction will add the signed transaction to the transaction pool for
+// inclusion in the blocks built by this node only. The transaction is not gossiped to
+// other nodes, so it is not exposed to frontrunning, but it is only included once
+// this node builds a block.
+func (s *TransactionAPI) SendPrivateTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	return submitTransaction(ctx, s.b, tx, true)
+}
+
+// SendBundle will add the signed transactions as a bundle for the next block built
+// by this node. The transactions are included in the given order without other
+// transactions between them, or not at all if any of them fails or reverts. Like
+// private transactions, bundles are not gossiped to other nodes. It returns the
+// hash identifying the bundle.
+func (s *TransactionAPI) SendBundle(ctx context.Context, inputs []hexutil.Bytes) (common.Hash, error) {
+	txs := make(types.Transactions, len(inputs))
+	for i, input := range inputs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid bundle transaction %d: %w", i, err)
+		}
+		if err := checkTxFee(s.b.ChainConfig(), tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid bundle transaction %d: %w", i, err)
+		}
+		if !s.b.UnprotectedAllowed(tx) && !tx.Protected() {
+			return common.Hash{}, fmt.Errorf("invalid bundle transaction %d: only replay-protected (EIP-155) transactions allowed over RPC", i)
+		}
+		txs[i] = tx
+	}
+	hash, err := s.b.SendBundle(ctx, txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted transaction bundle", "requestID", rpc.RequestIDFromContext(ctx), "hash", hash, "txs", len(txs))
+	return hash, nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -1955,7 +2205,7 @@ func (s *TransactionAPI) SignTransaction(ctx context.Context, args TransactionAr
 	}
 	// Before actually sign the transaction, ensure the transaction fee is reasonable.
 	tx := args.toTransaction()
-	if err := checkTxFee(tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
+	if err := checkTxFee(s.b.ChainConfig(), tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
 		return nil, err
 	}
 	signed, err := s.sign(args.from(), tx)
@@ -2014,7 +2264,7 @@ func (s *TransactionAPI) Resend(ctx context.Context, sendArgs TransactionArgs, g
 	if gasLimit != nil {
 		gas = uint64(*gasLimit)
 	}
-	if err := checkTxFee(price, gas, s.b.RPCTxFeeCap()); err != nil {
+	if err := checkTxFee(s.b.ChainConfig(), price, gas, s.b.RPCTxFeeCap()); err != nil {
 		return common.Hash{}, err
 	}
 	// Iterate the pending list for replacement
@@ -2057,55 +2307,56 @@ func NewDebugAPI(b Backend) *DebugAPI {
 	return &DebugAPI{b: b}
 }
 
+// blockHash resolves [blockNrOrHash] to the hash of the block it refers to.
+func (api *DebugAPI) blockHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (common.Hash, error) {
+	if h, ok := blockNrOrHash.Hash(); ok {
+		return h, nil
+	}
+	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if block == nil {
+		return common.Hash{}, fmt.Errorf("block %s not found", blockNrOrHash.String())
+	}
+	return block.Hash(), nil
+}
+
 // GetRawHeader retrieves the RLP encoding for a single header.
 func (api *DebugAPI) GetRawHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	var hash common.Hash
-	if h, ok := blockNrOrHash.Hash(); ok {
-		hash = h
-	} else {
-		block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
-		if err != nil {
-			return nil, err
-		}
-		hash = block.Hash()
+	hash, err := api.blockHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	header, _ := api.b.HeaderByHash(ctx, hash)
 	if header == nil {
-		return nil, fmt.Errorf("header #%d not found", hash)
+		return nil, fmt.Errorf("header %#x not found", hash)
 	}
 	return rlp.EncodeToBytes(header)
 }
 
 // GetRawBlock retrieves the RLP encoded for a single block.
 func (api *DebugAPI) GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	var hash common.Hash
-	if h, ok := blockNrOrHash.Hash(); ok {
-		hash = h
-	} else {
-		block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
-		if err != nil {
-			return nil, err
-		}
-		hash = block.Hash()
+	hash, err := api.blockHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	block, _ := api.b.BlockByHash(ctx, hash)
 	if block == nil {
-		return nil, fmt.Errorf("block #%d not found", hash)
+		return nil, fmt.Errorf("block %#x not found", hash)
 	}
 	return rlp.EncodeToBytes(block)
 }
 
 // GetRawReceipts retrieves the binary-encoded receipts of a single block.
 func (api *DebugAPI) GetRawReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]hexutil.Bytes, error) {
-	var hash common.Hash
-	if h, ok := blockNrOrHash.Hash(); ok {
-		hash = h
-	} else {
-		block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
-		if err != nil {
-			return nil, err
-		}
-		hash = block.Hash()
+	hash, err := api.blockHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	// Distinguish between an unknown block and a block without any receipts.
+	if header, _ := api.b.HeaderByHash(ctx, hash); header == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
 	}
 	receipts, err := api.b.GetReceipts(ctx, hash)
 	if err != nil {
@@ -2174,16 +2425,18 @@ func (s *NetAPI) Version() string {
 }
 
 // checkTxFee is an internal function used to check whether the fee of
-// the given transaction is _reasonable_(under the cap).
-func checkTxFee(gasPrice *big.Int, gas uint64, cap float64) error {
+// the given transaction is _reasonable_(under the cap). The cap is in
+// native tokens, as denominated by [config].
+func checkTxFee(config *params.ChainConfig, gasPrice *big.Int, gas uint64, cap float64) error {
 	// Short circuit if there is no cap for transaction fee at all.
 	if cap == 0 {
 		return nil
 	}
-	feeEth := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas))), new(big.Float).SetInt(big.NewInt(params.Ether)))
-	feeFloat, _ := feeEth.Float64()
+	feeTokens := config.ToNativeTokens(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas)))
+	feeFloat, _ := feeTokens.Float64()
 	if feeFloat > cap {
-		return fmt.Errorf("tx fee (%.2f ether) exceeds the configured cap (%.2f ether)", feeFloat, cap)
+		symbol := config.NativeTokenSymbol()
+		return fmt.Errorf("tx fee (%.2f %s) exceeds the configured cap (%.2f %s)", feeFloat, symbol, cap, symbol)
 	}
 	return nil
 }
@@ -28,9 +28,11 @@ package ethapi
 
 import (
 	"encoding/json"
+	"errors"
 	"math/big"
 	"testing"
 
+	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ethereum/go-ethereum/common"
@@ -167,3 +169,88 @@ func allTransactionTypes(addr common.Address, config *params.ChainConfig) []type
 		},
 	}
 }
+
+func TestTxNotFoundError(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	// Transactions of all blocks are indexed until the index tail is moved.
+	if err := txNotFoundError(true, db); err != nil {
+		t.Fatalf("unexpected error without an index tail: %v", err)
+	}
+	rawdb.WriteTxIndexTail(db, 0)
+	if err := txNotFoundError(true, db); err != nil {
+		t.Fatalf("unexpected error with a zero index tail: %v", err)
+	}
+
+	rawdb.WriteTxIndexTail(db, 10)
+	// Once the indices are up to date, unknown transactions are not errors.
+	if err := txNotFoundError(false, db); err != nil {
+		t.Fatalf("unexpected error after unindexing: %v", err)
+	}
+	var outOfRangeErr *txIndexOutOfRangeError
+	if err := txNotFoundError(true, db); !errors.As(err, &outOfRangeErr) || outOfRangeErr.tail != 10 {
+		t.Fatalf("expected out of range error with tail 10, got %v", err)
+	}
+}
+
+func TestCheckProofQueryWindow(t *testing.T) {
+	tests := []struct {
+		number, lastAccepted, window uint64
+		allowed                      bool
+	}{
+		{number: 0, lastAccepted: 1000, window: 0, allowed: true},
+		{number: 900, lastAccepted: 1000, window: 100, allowed: true},
+		{number: 899, lastAccepted: 1000, window: 100, allowed: false},
+		{number: 1000, lastAccepted: 1000, window: 100, allowed: true},
+		{number: 1001, lastAccepted: 1000, window: 100, allowed: true},
+	}
+	for _, test := range tests {
+		err := checkProofQueryWindow(test.number, test.lastAccepted, test.window)
+		if test.allowed && err != nil {
+			t.Errorf("block %d, last accepted %d, window %d: unexpected error: %v", test.number, test.lastAccepted, test.window, err)
+		}
+		if !test.allowed && !errors.Is(err, errProofQueryWindow) {
+			t.Errorf("block %d, last accepted %d, window %d: expected %v, got %v", test.number, test.lastAccepted, test.window, errProofQueryWindow, err)
+		}
+	}
+}
+
+func TestCheckTxFeeNativeTokenDecimals(t *testing.T) {
+	decimals := uint8(6)
+	config := *params.TestChainConfig
+	config.NativeToken = &params.NativeTokenConfig{Symbol: "USDX", Decimals: &decimals}
+
+	// 21000 gas at 100 units per gas is 2.1 tokens with 6 decimals.
+	if err := checkTxFee(&config, big.NewInt(100), 21000, 3); err != nil {
+		t.Fatalf("unexpected error under the cap: %v", err)
+	}
+	if err := checkTxFee(&config, big.NewInt(100), 21000, 2); err == nil {
+		t.Fatal("expected error over the cap")
+	}
+	// The same fee is negligible with the default 18 decimals.
+	if err := checkTxFee(params.TestChainConfig, big.NewInt(100), 21000, 2); err != nil {
+		t.Fatalf("unexpected error with default decimals: %v", err)
+	}
+}
+
+func TestBufferGasEstimate(t *testing.T) {
+	tests := []struct {
+		gas, cap   uint64
+		multiplier float64
+		padding    uint64
+		want       uint64
+	}{
+		{gas: 50_000, cap: 8_000_000, multiplier: 1, padding: 0, want: 50_000},
+		{gas: 50_000, cap: 8_000_000, multiplier: 0, padding: 0, want: 50_000},
+		{gas: 50_000, cap: 8_000_000, multiplier: 1.2, padding: 0, want: 60_000},
+		{gas: 50_000, cap: 8_000_000, multiplier: 1, padding: 10_000, want: 60_000},
+		{gas: 50_000, cap: 8_000_000, multiplier: 1.5, padding: 5_000, want: 80_000},
+		{gas: 50_000, cap: 55_000, multiplier: 1.5, padding: 0, want: 55_000},
+		{gas: 50_000, cap: 55_000, multiplier: 1, padding: 10_000, want: 55_000},
+		{gas: 50_000, cap: 55_000, multiplier: 1, padding: ^uint64(0), want: 55_000},
+	}
+	for _, test := range tests {
+		if have := bufferGasEstimate(test.gas, test.cap, test.multiplier, test.padding); have != test.want {
+			t.Errorf("gas %d, cap %d, multiplier %f, padding %d: have %d, want %d", test.gas, test.cap, test.multiplier, test.padding, have, test.want)
+		}
+	}
+}
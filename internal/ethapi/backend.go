@@ -51,16 +51,20 @@ import (
 // both full and light clients) with access to necessary functions.
 type Backend interface {
 	// General Ethereum API
+	CurrentTime() time.Time // time of the pending block, according to the node's clock
 	EstimateBaseFee(ctx context.Context) (*big.Int, error)
 	SuggestPrice(ctx context.Context) (*big.Int, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	FeeHistory(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error)
 	ChainDb() ethdb.Database
+	TxUnindexing() bool // true while transaction lookup indices are being deleted
 	AccountManager() *accounts.Manager
 	ExtRPCEnabled() bool
 	RPCGasCap() uint64                             // global gas cap for eth_call over rpc: DoS protection
+	RPCGasEstimateBuffer() (float64, uint64)       // multiplier and padding applied to gas estimates
 	RPCEVMTimeout() time.Duration                  // global timeout for eth_call over rpc: DoS protection
 	RPCTxFeeCap() float64                          // global tx fee cap for all transaction related APIs
+	HistoricalProofQueryWindow() uint64            // number of blocks before the last accepted block to serve proofs for, or 0 for any
 	UnprotectedAllowed(tx *types.Transaction) bool // allows only for EIP155 transactions.
 
 	// Blockchain API
@@ -84,6 +88,8 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error
+	SendBundle(ctx context.Context, signedTxs types.Transactions) (common.Hash, error)
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
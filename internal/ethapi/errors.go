@@ -0,0 +1,84 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"errors"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/txpool"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+)
+
+// ErrorData is the structured data attached to the JSON-RPC errors of common failures,
+// so that clients can branch on the reason rather than on the error message. The errors
+// keep the default -32000 code, which existing clients match on. The reasons are part of
+// the API and must not be changed or reused once released.
+type ErrorData struct {
+	// Reason is a stable identifier of the failure
+	Reason string `json:"reason"`
+	// Precompile is the config key of the precompile that returned the error, if any
+	Precompile string `json:"precompile,omitempty"`
+}
+
+type errorReason struct {
+	err  error
+	data ErrorData
+}
+
+// errorReasons lists the failures with a stable reason. Errors are matched in order
+// with errors.Is, so more specific errors must come before the errors they wrap.
+var errorReasons = []errorReason{
+	{core.ErrNonceTooLow, ErrorData{Reason: "nonce-too-low"}},
+	{core.ErrNonceTooHigh, ErrorData{Reason: "nonce-too-high"}},
+	{txpool.ErrUnderpriced, ErrorData{Reason: "underpriced"}},
+	{txpool.ErrReplaceUnderpriced, ErrorData{Reason: "replacement-underpriced"}},
+	{core.ErrInsufficientFunds, ErrorData{Reason: "insufficient-funds"}},
+	{core.ErrInsufficientFundsForTransfer, ErrorData{Reason: "insufficient-funds"}},
+	{core.ErrIntrinsicGas, ErrorData{Reason: "intrinsic-gas-too-low"}},
+	{txpool.ErrGasLimit, ErrorData{Reason: "exceeds-block-gas-limit"}},
+	{txpool.ErrAlreadyKnown, ErrorData{Reason: "already-known"}},
+	{core.ErrFeeCapTooLow, ErrorData{Reason: "fee-cap-too-low"}},
+	{vmerrs.ErrSenderAddressNotAllowListed, ErrorData{Reason: "sender-not-allow-listed", Precompile: txallowlist.ConfigKey}},
+	{core.ErrMissingPredicateContext, ErrorData{Reason: "predicate-verification-failed"}},
+	{nativeminter.ErrCannotMint, ErrorData{Reason: "cannot-mint", Precompile: nativeminter.ConfigKey}},
+	{feemanager.ErrCannotChangeFee, ErrorData{Reason: "cannot-change-fee", Precompile: feemanager.ConfigKey}},
+	{allowlist.ErrCannotModifyAllowList, ErrorData{Reason: "cannot-modify-allow-list"}},
+}
+
+var _ rpc.DataError = (*reasonError)(nil)
+
+// reasonError is an API error with structured data. It is returned with the default
+// JSON error code.
+type reasonError struct {
+	error
+	data ErrorData
+}
+
+func (e *reasonError) ErrorData() interface{} { return e.data }
+
+func (e *reasonError) Unwrap() error { return e.error }
+
+// toAPIError attaches the stable reason of [err] to it as error data, if it has one.
+// Errors that already carry a JSON error code, such as reverts, are returned as is.
+func toAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return err
+	}
+	for _, r := range errorReasons {
+		if errors.Is(err, r.err) {
+			return &reasonError{error: err, data: r.data}
+		}
+	}
+	return err
+}
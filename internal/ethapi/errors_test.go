@@ -0,0 +1,83 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/txpool"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/rpc"
+)
+
+func TestToAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantData *ErrorData
+	}{
+		{
+			name:     "nonce too low",
+			err:      core.ErrNonceTooLow,
+			wantData: &ErrorData{Reason: "nonce-too-low"},
+		},
+		{
+			name:     "wrapped underpriced",
+			err:      fmt.Errorf("%w: tip needed 1, tip permitted 0", txpool.ErrUnderpriced),
+			wantData: &ErrorData{Reason: "underpriced"},
+		},
+		{
+			name:     "precompile error",
+			err:      fmt.Errorf("%w: 0x01", nativeminter.ErrCannotMint),
+			wantData: &ErrorData{Reason: "cannot-mint", Precompile: nativeminter.ConfigKey},
+		},
+		{
+			name:     "revert keeps its code",
+			err:      &revertError{error: errors.New("execution reverted"), reason: "0x"},
+			wantCode: 3,
+		},
+		{
+			name: "unknown error",
+			err:  errors.New("unknown"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := toAPIError(tt.err)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error %v does not wrap %v", err, tt.err)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Fatalf("message mismatch: have %q, want %q", err.Error(), tt.err.Error())
+			}
+
+			// Only errors that carry their own code, such as reverts, change the default code.
+			var rpcErr rpc.Error
+			if errors.As(err, &rpcErr) {
+				if code := rpcErr.ErrorCode(); code != tt.wantCode {
+					t.Fatalf("error code mismatch: have %d, want %d", code, tt.wantCode)
+				}
+			} else if tt.wantCode != 0 {
+				t.Fatalf("expected error code %d, got none", tt.wantCode)
+			}
+			if tt.wantData == nil {
+				return
+			}
+			dataErr, ok := err.(rpc.DataError)
+			if !ok {
+				t.Fatalf("expected error data %v, got none", *tt.wantData)
+			}
+			if data := dataErr.ErrorData(); data != *tt.wantData {
+				t.Fatalf("error data mismatch: have %v, want %v", data, *tt.wantData)
+			}
+		})
+	}
+	if toAPIError(nil) != nil {
+		t.Fatal("expected nil error")
+	}
+}
@@ -0,0 +1,94 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package miner
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	_ OrderedTransactions = (*types.TransactionsByPriceAndNonce)(nil)
+	_ OrderedTransactions = (*types.TransactionsByTimeAndNonce)(nil)
+)
+
+const (
+	// PriceOrdering is the name of the default ordering policy, which orders
+	// transactions by effective tip while respecting account nonces.
+	PriceOrdering = "price"
+	// FCFSOrdering is the name of the first-come-first-served ordering policy,
+	// which orders transactions by the time they were first seen while respecting
+	// account nonces. Since tips do not affect the order, it prevents priority gas
+	// auctions. Local transactions are not prioritized under this policy.
+	FCFSOrdering = "fcfs"
+)
+
+// OrderedTransactions iterates over pending transactions in the order they
+// should be committed to a block. Implementations must return the transactions
+// of each account in nonce order.
+type OrderedTransactions interface {
+	// Peek returns the next transaction to commit, or nil if there are none left.
+	Peek() *types.Transaction
+	// Shift replaces the next transaction with the following transaction of the
+	// same account, after the next transaction was committed.
+	Shift()
+	// Pop removes the next transaction along with all remaining transactions of
+	// the same account, after the next transaction could not be committed.
+	Pop()
+}
+
+// OrderingPolicy decides the order in which the block builder commits pending
+// transactions.
+type OrderingPolicy interface {
+	// Order returns an iterator over [txs], which maps each account to its pending
+	// transactions in nonce order, for a block with [baseFee].
+	Order(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) OrderedTransactions
+}
+
+// OrderingPolicyFunc is an adapter to use a function as an OrderingPolicy.
+type OrderingPolicyFunc func(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) OrderedTransactions
+
+// Order calls f(signer, txs, baseFee).
+func (f OrderingPolicyFunc) Order(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) OrderedTransactions {
+	return f(signer, txs, baseFee)
+}
+
+var orderingPolicies = map[string]OrderingPolicy{
+	PriceOrdering: OrderingPolicyFunc(func(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) OrderedTransactions {
+		return types.NewTransactionsByPriceAndNonce(signer, txs, baseFee)
+	}),
+	FCFSOrdering: OrderingPolicyFunc(func(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) OrderedTransactions {
+		return types.NewTransactionsByTimeAndNonce(signer, txs, baseFee)
+	}),
+}
+
+// RegisterOrderingPolicy makes [policy] available to the block builder under
+// [name], so that it can be selected by config. It is not safe to call
+// concurrently and should be called from an init function.
+func RegisterOrderingPolicy(name string, policy OrderingPolicy) error {
+	if len(name) == 0 {
+		return fmt.Errorf("ordering policy name cannot be empty")
+	}
+	if _, exists := orderingPolicies[name]; exists {
+		return fmt.Errorf("ordering policy %q already registered", name)
+	}
+	orderingPolicies[name] = policy
+	return nil
+}
+
+// GetOrderingPolicy returns the ordering policy registered under [name]. An
+// empty [name] selects PriceOrdering.
+func GetOrderingPolicy(name string) (OrderingPolicy, error) {
+	if len(name) == 0 {
+		name = PriceOrdering
+	}
+	policy, ok := orderingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ordering policy %q", name)
+	}
+	return policy, nil
+}
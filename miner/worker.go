@@ -42,6 +42,7 @@ import (
 	"github.com/ava-labs/subnet-evm/consensus/dummy"
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/txpool"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/core/vm"
 	"github.com/ava-labs/subnet-evm/params"
@@ -99,9 +100,17 @@ type worker struct {
 	mu       sync.RWMutex   // The lock used to protect the coinbase and extra fields
 	coinbase common.Address
 	clock    *mockable.Clock // Allows us mock the clock for testing
+
+	ordering    OrderingPolicy // Orders pending transactions when building blocks
+	strictOrder bool           // Whether local transactions are ordered with remote ones instead of first
 }
 
 func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, clock *mockable.Clock) *worker {
+	ordering, err := GetOrderingPolicy(config.TxOrdering)
+	if err != nil {
+		log.Error("Falling back to default transaction ordering", "ordering", PriceOrdering, "err", err)
+		ordering, _ = GetOrderingPolicy(PriceOrdering)
+	}
 	worker := &worker{
 		config:      config,
 		chainConfig: chainConfig,
@@ -111,6 +120,8 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		mux:         mux,
 		coinbase:    config.Etherbase,
 		clock:       clock,
+		ordering:    ordering,
+		strictOrder: config.TxOrdering == FCFSOrdering,
 	}
 
 	return worker
@@ -202,24 +213,33 @@ func (w *worker) commitNewWork(predicateContext *precompileconfig.PredicateConte
 		return nil, err
 	}
 
+	// Commit the pending bundles first, since they were submitted for this node's
+	// blocks only and are dropped if they are not included.
+	for _, bundle := range w.eth.TxPool().TakeBundles() {
+		w.commitBundle(env, bundle, header.Coinbase)
+	}
+
 	// Get the pending txs from TxPool
 	pending := w.eth.TxPool().Pending(true)
 
-	// Split the pending transactions into locals and remotes
+	// Split the pending transactions into locals and remotes, unless local
+	// transactions must be ordered with remote ones.
 	localTxs := make(map[common.Address]types.Transactions)
 	remoteTxs := pending
-	for _, account := range w.eth.TxPool().Locals() {
-		if txs := remoteTxs[account]; len(txs) > 0 {
-			delete(remoteTxs, account)
-			localTxs[account] = txs
+	if !w.strictOrder {
+		for _, account := range w.eth.TxPool().Locals() {
+			if txs := remoteTxs[account]; len(txs) > 0 {
+				delete(remoteTxs, account)
+				localTxs[account] = txs
+			}
 		}
 	}
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, header.BaseFee)
+		txs := w.ordering.Order(env.signer, localTxs, header.BaseFee)
 		w.commitTransactions(env, txs, header.Coinbase)
 	}
 	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, header.BaseFee)
+		txs := w.ordering.Order(env.signer, remoteTxs, header.BaseFee)
 		w.commitTransactions(env, txs, header.Coinbase)
 	}
 
@@ -279,7 +299,7 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction, coin
 	return receipt.Logs, nil
 }
 
-func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByPriceAndNonce, coinbase common.Address) {
+func (w *worker) commitTransactions(env *environment, txs OrderedTransactions, coinbase common.Address) {
 	for {
 		// If we don't have enough gas for any further transactions then we're done.
 		if env.gasPool.Gas() < params.TxGas {
@@ -349,6 +369,54 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 	}
 }
 
+// commitBundle commits all transactions of [bundle] in order, or none of them if
+// any transaction fails or reverts.
+func (w *worker) commitBundle(env *environment, bundle *txpool.Bundle, coinbase common.Address) {
+	var (
+		snap     = env.state.Snapshot()
+		gp       = env.gasPool.Gas()
+		gasUsed  = env.header.GasUsed
+		tcount   = env.tcount
+		size     = env.size
+		txsCount = len(env.txs)
+	)
+	revert := func(reason error) {
+		env.state.RevertToSnapshot(snap)
+		env.gasPool.SetGas(gp)
+		env.header.GasUsed = gasUsed
+		env.tcount = tcount
+		env.size = size
+		for _, tx := range env.txs[txsCount:] {
+			env.predicateResults.DeleteTxPredicateResults(tx.Hash())
+		}
+		env.txs = env.txs[:txsCount]
+		env.receipts = env.receipts[:txsCount]
+		log.Debug("Dropping transaction bundle", "hash", bundle.Hash, "err", reason)
+	}
+
+	for _, tx := range bundle.Txs {
+		if totalTxsSize := env.size + tx.Size(); totalTxsSize > targetTxsSize {
+			revert(fmt.Errorf("bundle exceeds target size with tx %s", tx.Hash()))
+			return
+		}
+		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+			revert(fmt.Errorf("replay protected tx %s before EIP155", tx.Hash()))
+			return
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if _, err := w.commitTransaction(env, tx, coinbase); err != nil {
+			revert(fmt.Errorf("tx %s failed: %w", tx.Hash(), err))
+			return
+		}
+		env.tcount++
+		if receipt := env.receipts[len(env.receipts)-1]; receipt.Status != types.ReceiptStatusSuccessful {
+			revert(fmt.Errorf("tx %s reverted", tx.Hash()))
+			return
+		}
+	}
+	log.Debug("Committed transaction bundle", "hash", bundle.Hash, "txs", len(bundle.Txs))
+}
+
 // commit runs any post-transaction state modifications, assembles the final block
 // and commits new work if consensus engine is running.
 func (w *worker) commit(env *environment) (*types.Block, error) {
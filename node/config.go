@@ -54,6 +54,10 @@ type Config struct {
 	// ExternalSigner specifies an external URI for a clef-type signer
 	ExternalSigner string `toml:",omitempty"`
 
+	// ExternalSignerPolicy, if non-nil, is applied to every signing request before it is
+	// forwarded to [ExternalSigner].
+	ExternalSignerPolicy *external.Policy `toml:",omitempty"`
+
 	// UseLightweightKDF lowers the memory and CPU requirements of the key store
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
@@ -226,12 +230,20 @@ func makeAccountManager(conf *Config) (*accounts.Manager, error) {
 	// Assemble the account manager and supported backends
 	var backends []accounts.Backend
 	if len(conf.ExternalSigner) > 0 {
-		log.Info("Using external signer", "url", conf.ExternalSigner)
-		if extapi, err := external.NewExternalBackend(conf.ExternalSigner); err == nil {
-			backends = append(backends, extapi)
+		log.Info("Using external signer", "url", conf.ExternalSigner, "policy", conf.ExternalSignerPolicy != nil)
+		var (
+			extapi *external.ExternalBackend
+			err    error
+		)
+		if conf.ExternalSignerPolicy != nil {
+			extapi, err = external.NewExternalBackendWithPolicy(conf.ExternalSigner, conf.ExternalSignerPolicy)
 		} else {
+			extapi, err = external.NewExternalBackend(conf.ExternalSigner)
+		}
+		if err != nil {
 			return nil, fmt.Errorf("error connecting to external signer: %v", err)
 		}
+		backends = append(backends, extapi)
 	}
 	if len(backends) == 0 {
 		// For now, we're using EITHER external signer OR local signers.
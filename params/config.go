@@ -191,6 +191,7 @@ type ChainConfig struct {
 	ChainID            *big.Int             `json:"chainId"`                      // chainId identifies the current chain and is used for replay protection
 	FeeConfig          commontype.FeeConfig `json:"feeConfig"`                    // Set the configuration for the dynamic fee algorithm
 	AllowFeeRecipients bool                 `json:"allowFeeRecipients,omitempty"` // Allows fees to be collected by block builders.
+	NativeToken        *NativeTokenConfig   `json:"nativeToken,omitempty"`        // Denomination of the native token. Defaults to 18 decimals.
 
 	HomesteadBlock *big.Int `json:"homesteadBlock,omitempty"` // Homestead switch block (nil = no fork, 0 = already homestead)
 
@@ -323,6 +324,9 @@ func (c *ChainConfig) Description() string {
 
 	banner += fmt.Sprintf("Allow Fee Recipients: %v", c.AllowFeeRecipients)
 	banner += "\n"
+
+	banner += fmt.Sprintf("Native Token: %s (%d decimals)", c.NativeTokenSymbol(), c.NativeTokenDecimals())
+	banner += "\n"
 	return banner
 }
 
@@ -431,6 +435,12 @@ func (c *ChainConfig) Verify() error {
 		return err
 	}
 
+	if c.NativeToken != nil {
+		if err := c.NativeToken.Verify(); err != nil {
+			return fmt.Errorf("invalid native token config: %w", err)
+		}
+	}
+
 	// Verify the precompile upgrades are internally consistent given the existing chainConfig.
 	if err := c.verifyPrecompileUpgrades(); err != nil {
 		return fmt.Errorf("invalid precompile upgrades: %w", err)
@@ -330,3 +330,24 @@ func TestChainConfigMarshalWithUpgrades(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, config, unmarshalled)
 }
+
+func TestNativeTokenConfig(t *testing.T) {
+	require := require.New(t)
+
+	c := ChainConfig{}
+	require.Equal(DefaultNativeTokenSymbol, c.NativeTokenSymbol())
+	require.Equal(DefaultNativeTokenDecimals, c.NativeTokenDecimals())
+	require.Equal(big.NewInt(Ether), c.NativeTokenUnit())
+
+	require.NoError(json.Unmarshal([]byte(`{"chainId": 43214, "nativeToken": {"symbol": "USDX", "decimals": 6}}`), &c))
+	require.NoError(c.NativeToken.Verify())
+	require.Equal("USDX", c.NativeTokenSymbol())
+	require.Equal(uint8(6), c.NativeTokenDecimals())
+	require.Equal(big.NewInt(1_000_000), c.NativeTokenUnit())
+	fee, _ := c.ToNativeTokens(big.NewInt(2_500_000)).Float64()
+	require.Equal(2.5, fee)
+
+	decimals := MaxNativeTokenDecimals + 1
+	c.NativeToken.Decimals = &decimals
+	require.ErrorContains(c.NativeToken.Verify(), "exceeds max")
+}
@@ -0,0 +1,67 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	// DefaultNativeTokenDecimals is the number of decimals of the native token of
+	// chains that do not configure it, matching the 10^18 wei in an ether.
+	DefaultNativeTokenDecimals uint8 = 18
+	// DefaultNativeTokenSymbol is the symbol of the native token of chains that do
+	// not configure it.
+	DefaultNativeTokenSymbol = "ether"
+	// MaxNativeTokenDecimals is the maximum number of decimals of the native token.
+	MaxNativeTokenDecimals uint8 = 36
+	// MaxNativeTokenSymbolLen is the maximum length of the symbol of the native token.
+	MaxNativeTokenSymbolLen = 16
+)
+
+// NativeTokenConfig declares the denomination of the native token. Balances, gas
+// prices and fees are always denominated in the smallest unit of the native token,
+// of which there are 10^Decimals in a token.
+type NativeTokenConfig struct {
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals *uint8 `json:"decimals,omitempty"`
+}
+
+// Verify returns an error if the native token config is invalid.
+func (n *NativeTokenConfig) Verify() error {
+	if len(n.Symbol) > MaxNativeTokenSymbolLen {
+		return fmt.Errorf("native token symbol %q exceeds max length %d", n.Symbol, MaxNativeTokenSymbolLen)
+	}
+	if n.Decimals != nil && *n.Decimals > MaxNativeTokenDecimals {
+		return fmt.Errorf("native token decimals %d exceeds max %d", *n.Decimals, MaxNativeTokenDecimals)
+	}
+	return nil
+}
+
+// NativeTokenSymbol returns the symbol of the native token.
+func (c *ChainConfig) NativeTokenSymbol() string {
+	if c.NativeToken == nil || len(c.NativeToken.Symbol) == 0 {
+		return DefaultNativeTokenSymbol
+	}
+	return c.NativeToken.Symbol
+}
+
+// NativeTokenDecimals returns the number of decimals of the native token.
+func (c *ChainConfig) NativeTokenDecimals() uint8 {
+	if c.NativeToken == nil || c.NativeToken.Decimals == nil {
+		return DefaultNativeTokenDecimals
+	}
+	return *c.NativeToken.Decimals
+}
+
+// NativeTokenUnit returns the number of base units in one native token.
+func (c *ChainConfig) NativeTokenUnit() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.NativeTokenDecimals())), nil)
+}
+
+// ToNativeTokens converts [amount] base units to native tokens.
+func (c *ChainConfig) ToNativeTokens(amount *big.Int) *big.Float {
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(c.NativeTokenUnit()))
+}
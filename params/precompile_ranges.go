@@ -0,0 +1,68 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PrecompileAddressRange is a range of the precompile address space set aside for
+// precompiles from a particular origin.
+type PrecompileAddressRange struct {
+	Name string
+	utils.AddressRange
+}
+
+// PrecompileAddressRanges partitions the precompile address space reserved for
+// stateful precompiles by modules.ReservedAddress into named ranges, which must
+// not overlap.
+var PrecompileAddressRanges = []PrecompileAddressRange{
+	// Precompiles originating in coreth, reserved so that they can be migrated
+	// into subnet-evm without conflicts. System addresses are also allocated here.
+	{
+		Name: "coreth",
+		AddressRange: utils.AddressRange{
+			Start: common.HexToAddress("0x0100000000000000000000000000000000000000"),
+			End:   common.HexToAddress("0x01000000000000000000000000000000000000ff"),
+		},
+	},
+	// Optional precompiles implemented in subnet-evm.
+	{
+		Name: "in-tree",
+		AddressRange: utils.AddressRange{
+			Start: common.HexToAddress("0x0200000000000000000000000000000000000000"),
+			End:   common.HexToAddress("0x020000000000000000000000000000000000007f"),
+		},
+	},
+	// Experimental precompiles under development in subnet-evm, which move to
+	// the in-tree range once they are stable.
+	{
+		Name: "experimental",
+		AddressRange: utils.AddressRange{
+			Start: common.HexToAddress("0x0200000000000000000000000000000000000080"),
+			End:   common.HexToAddress("0x02000000000000000000000000000000000000ff"),
+		},
+	},
+	// Precompiles of forks of subnet-evm, so that they do not conflict with
+	// precompiles added to subnet-evm in the future.
+	{
+		Name: "downstream-fork",
+		AddressRange: utils.AddressRange{
+			Start: common.HexToAddress("0x0300000000000000000000000000000000000000"),
+			End:   common.HexToAddress("0x03000000000000000000000000000000000000ff"),
+		},
+	},
+}
+
+// GetPrecompileAddressRange returns the precompile address range containing [addr],
+// and false if [addr] is not in the precompile address space.
+func GetPrecompileAddressRange(addr common.Address) (PrecompileAddressRange, bool) {
+	for _, addressRange := range PrecompileAddressRanges {
+		if addressRange.Contains(addr) {
+			return addressRange, true
+		}
+	}
+	return PrecompileAddressRange{}, false
+}
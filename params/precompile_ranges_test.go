@@ -0,0 +1,30 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecompileAddressRanges(t *testing.T) {
+	for i, a := range PrecompileAddressRanges {
+		require.True(t, bytes.Compare(a.Start[:], a.End[:]) <= 0, "range %s is empty", a.Name)
+		require.True(t, modules.ReservedAddress(a.Start), "range %s is not reserved", a.Name)
+		require.True(t, modules.ReservedAddress(a.End), "range %s is not reserved", a.Name)
+		for _, b := range PrecompileAddressRanges[i+1:] {
+			require.False(t, a.Contains(b.Start) || a.Contains(b.End) || b.Contains(a.Start), "ranges %s and %s overlap", a.Name, b.Name)
+		}
+	}
+
+	addressRange, ok := GetPrecompileAddressRange(common.HexToAddress("0x0200000000000000000000000000000000000080"))
+	require.True(t, ok)
+	require.Equal(t, "experimental", addressRange.Name)
+	_, ok = GetPrecompileAddressRange(common.HexToAddress("0x0400000000000000000000000000000000000000"))
+	require.False(t, ok)
+}
@@ -77,6 +77,12 @@ type Network interface {
 	// TrackBandwidth should be called for each valid request with the bandwidth
 	// (length of response divided by request time), and with 0 if the response is invalid.
 	TrackBandwidth(nodeID ids.NodeID, bandwidth float64)
+
+	// Peers returns the peers this node is connected to
+	Peers() []PeerInfo
+
+	// Uptime returns the uptime of [nodeID] observed by this node since it started
+	Uptime(nodeID ids.NodeID) PeerUptime
 }
 
 // network is an implementation of Network that processes message requests for
@@ -539,6 +545,20 @@ func (n *network) TrackBandwidth(nodeID ids.NodeID, bandwidth float64) {
 	n.peers.TrackBandwidth(nodeID, bandwidth)
 }
 
+func (n *network) Peers() []PeerInfo {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.peers.Peers()
+}
+
+func (n *network) Uptime(nodeID ids.NodeID) PeerUptime {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.peers.Uptime(nodeID, time.Now())
+}
+
 // invariant: peer/network must use explicitly even request ids.
 // for this reason, [n.requestID] is initialized as zero and incremented by 2.
 // This is for backwards-compatibility while the SDK router exists with the
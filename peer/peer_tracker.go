@@ -6,6 +6,7 @@ package peer
 import (
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -33,8 +34,33 @@ const (
 
 // information we track on a given peer
 type peerInfo struct {
-	version   *version.Application
-	bandwidth utils_math.Averager
+	version     *version.Application
+	bandwidth   utils_math.Averager
+	connectedAt time.Time
+}
+
+// PeerInfo describes a peer this node is connected to.
+type PeerInfo struct {
+	NodeID      ids.NodeID `json:"nodeID"`
+	Version     string     `json:"version"`
+	ConnectedAt time.Time  `json:"connectedAt"`
+	// Bandwidth is the average bandwidth of the responses of the peer, or 0
+	// if no request was sent to it.
+	Bandwidth float64 `json:"bandwidth"`
+	// Tracked is true if this node sent requests to the peer.
+	Tracked bool `json:"tracked"`
+	// Responsive is true if the peer responded to the last request it was sent.
+	Responsive bool `json:"responsive"`
+}
+
+// PeerUptime is the uptime of a node as observed by this node, that is the time
+// this node was connected to it since this node started.
+type PeerUptime struct {
+	Connected bool
+	// UpDuration is the time this node was connected to the peer.
+	UpDuration time.Duration
+	// ObservedDuration is the time since this node started tracking peers.
+	ObservedDuration time.Duration
 }
 
 // peerTracker tracks the bandwidth of responses coming from peers,
@@ -50,6 +76,8 @@ type peerTracker struct {
 	bandwidthHeap          utils_math.AveragerHeap // tracks bandwidth peers are responding with
 	averageBandwidthMetric metrics.GaugeFloat64
 	averageBandwidth       utils_math.Averager
+	startTime              time.Time                    // time at which uptimes started being observed
+	upDurations            map[ids.NodeID]time.Duration // time connected to peers, excluding their current connection
 }
 
 func NewPeerTracker() *peerTracker {
@@ -62,6 +90,8 @@ func NewPeerTracker() *peerTracker {
 		bandwidthHeap:          utils_math.NewMaxAveragerHeap(),
 		averageBandwidthMetric: metrics.GetOrRegisterGaugeFloat64("net_average_bandwidth", nil),
 		averageBandwidth:       utils_math.NewAverager(0, bandwidthHalflife, time.Now()),
+		startTime:              time.Now(),
+		upDurations:            make(map[ids.NodeID]time.Duration),
 	}
 }
 
@@ -169,8 +199,9 @@ func (p *peerTracker) Connected(nodeID ids.NodeID, nodeVersion *version.Applicat
 		// that we have already marked as Connected.
 		if nodeVersion.Compare(peer.version) != 0 {
 			p.peers[nodeID] = &peerInfo{
-				version:   nodeVersion,
-				bandwidth: peer.bandwidth,
+				version:     nodeVersion,
+				bandwidth:   peer.bandwidth,
+				connectedAt: peer.connectedAt,
 			}
 			log.Warn("updating node version of already connected peer", "nodeID", nodeID, "storedVersion", peer.version, "nodeVersion", nodeVersion)
 		} else {
@@ -180,12 +211,16 @@ func (p *peerTracker) Connected(nodeID ids.NodeID, nodeVersion *version.Applicat
 	}
 
 	p.peers[nodeID] = &peerInfo{
-		version: nodeVersion,
+		version:     nodeVersion,
+		connectedAt: time.Now(),
 	}
 }
 
 // Disconnected should be called when [nodeID] disconnects from this node
 func (p *peerTracker) Disconnected(nodeID ids.NodeID) {
+	if peer := p.peers[nodeID]; peer != nil {
+		p.upDurations[nodeID] += time.Since(peer.connectedAt)
+	}
 	p.bandwidthHeap.Remove(nodeID)
 	p.trackedPeers.Remove(nodeID)
 	p.numTrackedPeers.Update(int64(p.trackedPeers.Len()))
@@ -198,3 +233,38 @@ func (p *peerTracker) Disconnected(nodeID ids.NodeID) {
 func (p *peerTracker) Size() int {
 	return len(p.peers)
 }
+
+// Peers returns the peers the node is connected to, sorted by NodeID.
+func (p *peerTracker) Peers() []PeerInfo {
+	peers := make([]PeerInfo, 0, len(p.peers))
+	for nodeID, peer := range p.peers {
+		info := PeerInfo{
+			NodeID:      nodeID,
+			Version:     peer.version.String(),
+			ConnectedAt: peer.connectedAt,
+			Tracked:     p.trackedPeers.Contains(nodeID),
+			Responsive:  p.responsivePeers.Contains(nodeID),
+		}
+		if peer.bandwidth != nil {
+			info.Bandwidth = peer.bandwidth.Read()
+		}
+		peers = append(peers, info)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].NodeID.Less(peers[j].NodeID)
+	})
+	return peers
+}
+
+// Uptime returns the uptime of [nodeID] observed at [now].
+func (p *peerTracker) Uptime(nodeID ids.NodeID, now time.Time) PeerUptime {
+	uptime := PeerUptime{
+		UpDuration:       p.upDurations[nodeID],
+		ObservedDuration: now.Sub(p.startTime),
+	}
+	if peer := p.peers[nodeID]; peer != nil {
+		uptime.Connected = true
+		uptime.UpDuration += now.Sub(peer.connectedAt)
+	}
+	return uptime
+}
@@ -5,6 +5,7 @@ package peer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/stretchr/testify/require"
@@ -86,3 +87,33 @@ func TestPeerTracker(t *testing.T) {
 	require.True(ok)
 	require.Falsef(responsive, "expected connecting to a non-responsive peer, but got a peer that was responsive: peer %s", peer)
 }
+
+func TestPeerTrackerUptime(t *testing.T) {
+	require := require.New(t)
+	p := NewPeerTracker()
+
+	nodeID := ids.GenerateTestNodeID()
+	uptime := p.Uptime(nodeID, time.Now())
+	require.False(uptime.Connected)
+	require.Zero(uptime.UpDuration)
+
+	p.Connected(nodeID, defaultPeerVersion)
+	peers := p.Peers()
+	require.Len(peers, 1)
+	require.Equal(nodeID, peers[0].NodeID)
+	require.Equal(defaultPeerVersion.String(), peers[0].Version)
+	require.False(peers[0].Tracked)
+
+	connectedAt := peers[0].ConnectedAt
+	uptime = p.Uptime(nodeID, connectedAt.Add(time.Minute))
+	require.True(uptime.Connected)
+	require.Equal(time.Minute, uptime.UpDuration)
+	require.GreaterOrEqual(uptime.ObservedDuration, uptime.UpDuration)
+
+	// The time connected is kept after the peer disconnects
+	p.Disconnected(nodeID)
+	require.Empty(p.Peers())
+	uptime = p.Uptime(nodeID, time.Now().Add(time.Hour))
+	require.False(uptime.Connected)
+	require.Less(uptime.UpDuration, time.Hour)
+}
@@ -4,12 +4,23 @@
 package evm
 
 import (
+	"bufio"
+	stdjson "encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/warp"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // Admin is the API service for admin API calls
@@ -54,17 +65,41 @@ func (p *Admin) LockProfile(_ *http.Request, _ *struct{}, _ *api.EmptyReply) err
 }
 
 type SetLogLevelArgs struct {
+	// Module is the path of a module relative to the root of subnet-evm, such as
+	// "warp" or "sync/statesync", to set the log level of. If empty, the log level
+	// of the VM is set.
+	Module string `json:"module,omitempty"`
+	// Level is the log level to set. An empty level removes the log level of
+	// [Module], so that it logs at the log level of the VM again.
 	Level string `json:"level"`
 }
 
 func (p *Admin) SetLogLevel(_ *http.Request, args *SetLogLevelArgs, reply *api.EmptyReply) error {
-	log.Info("EVM: SetLogLevel called", "logLevel", args.Level)
+	log.Info("EVM: SetLogLevel called", "module", args.Module, "logLevel", args.Level)
+	if args.Module != "" {
+		if err := p.vm.logger.SetModuleLogLevel(args.Module, args.Level); err != nil {
+			return fmt.Errorf("failed to set log level of module %q: %w", args.Module, err)
+		}
+		return nil
+	}
 	if err := p.vm.logger.SetLogLevel(args.Level); err != nil {
 		return fmt.Errorf("failed to parse log level: %w ", err)
 	}
 	return nil
 }
 
+type GetLogLevelsReply struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// GetLogLevels returns the log level of the VM and the log levels of modules that
+// override it.
+func (p *Admin) GetLogLevels(_ *http.Request, _ *struct{}, reply *GetLogLevelsReply) error {
+	reply.Level, reply.Modules = p.vm.logger.LogLevels()
+	return nil
+}
+
 type ConfigReply struct {
 	Config *Config `json:"config"`
 }
@@ -73,3 +108,270 @@ func (p *Admin) GetVMConfig(_ *http.Request, _ *struct{}, reply *ConfigReply) er
 	reply.Config = &p.vm.config
 	return nil
 }
+
+type SetClockArgs struct {
+	// Time is the unix timestamp the VM clock is set to. If zero, the VM clock is
+	// synced with the system time again.
+	Time json.Uint64 `json:"time"`
+}
+
+type SetClockReply struct {
+	Time json.Uint64 `json:"time"`
+}
+
+// SetClock sets the clock used to build blocks, check precompile activations and
+// estimate fees, so that tests can cross upgrade timestamps without waiting for them.
+// It is only available in builds with the "testclock" tag.
+func (p *Admin) SetClock(_ *http.Request, args *SetClockArgs, reply *SetClockReply) error {
+	log.Info("Admin: SetClock called", "time", args.Time)
+	if !testClockEnabled {
+		return errors.New("cannot set the clock in a build without the testclock tag")
+	}
+
+	p.vm.ctx.Lock.Lock()
+	defer p.vm.ctx.Lock.Unlock()
+
+	if args.Time == 0 {
+		p.vm.clock.Sync()
+	} else {
+		p.vm.clock.Set(time.Unix(int64(args.Time), 0))
+	}
+	reply.Time = json.Uint64(p.vm.clock.Unix())
+	return nil
+}
+
+type SetRPCCapsArgs struct {
+	// GasCap is the new rpc-gas-cap, left unchanged if nil. Zero is unlimited.
+	GasCap *json.Uint64 `json:"gasCap,omitempty"`
+	// TxFeeCap is the new rpc-tx-fee-cap in ether, left unchanged if nil. Zero is unlimited.
+	TxFeeCap *float64 `json:"txFeeCap,omitempty"`
+}
+
+type SetRPCCapsReply struct {
+	GasCap   json.Uint64 `json:"gasCap"`
+	TxFeeCap float64     `json:"txFeeCap"`
+}
+
+// SetRPCCaps adjusts the gas cap of eth_call and eth_estimateGas and the fee cap of
+// transactions sent over RPC without restarting the node, and returns the caps in effect.
+// The changes are not persisted and the config returned by GetVMConfig is not updated.
+func (p *Admin) SetRPCCaps(_ *http.Request, args *SetRPCCapsArgs, reply *SetRPCCapsReply) error {
+	log.Info("Admin: SetRPCCaps called", "gasCap", args.GasCap, "txFeeCap", args.TxFeeCap)
+	if args.TxFeeCap != nil && (*args.TxFeeCap < 0 || math.IsNaN(*args.TxFeeCap)) {
+		return fmt.Errorf("invalid tx fee cap: %v", *args.TxFeeCap)
+	}
+
+	backend := p.vm.eth.APIBackend
+	if args.GasCap != nil {
+		backend.SetRPCGasCap(uint64(*args.GasCap))
+	}
+	if args.TxFeeCap != nil {
+		backend.SetRPCTxFeeCap(*args.TxFeeCap)
+	}
+	reply.GasCap = json.Uint64(backend.RPCGasCap())
+	reply.TxFeeCap = backend.RPCTxFeeCap()
+	return nil
+}
+
+type ExportStateArgs struct {
+	// Height of the accepted block whose state is exported. Defaults to the last accepted block.
+	Height *json.Uint64 `json:"height,omitempty"`
+	// File the state is written to.
+	File string `json:"file"`
+}
+
+type ExportStateReply struct {
+	Height   json.Uint64 `json:"height"`
+	Root     common.Hash `json:"root"`
+	Accounts json.Uint64 `json:"accounts"`
+}
+
+// ExportState writes the full state (accounts, storage and code) of an accepted block
+// to a file in the streaming format read by ImportState.
+func (p *Admin) ExportState(_ *http.Request, args *ExportStateArgs, reply *ExportStateReply) error {
+	log.Info("Admin: ExportState called", "height", args.Height, "file", args.File)
+	if args.File == "" {
+		return errors.New("file must be specified")
+	}
+
+	block := p.vm.blockChain.LastAcceptedBlock()
+	if args.Height != nil {
+		if uint64(*args.Height) > block.NumberU64() {
+			return fmt.Errorf("block %d is not accepted", *args.Height)
+		}
+		block = p.vm.blockChain.GetBlockByNumber(uint64(*args.Height))
+		if block == nil {
+			return fmt.Errorf("block %d not found", *args.Height)
+		}
+	}
+	if !p.vm.blockChain.HasState(block.Root()) {
+		return fmt.Errorf("state of block %d (root %s) is not available", block.NumberU64(), block.Root())
+	}
+
+	f, err := os.Create(args.File)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer f.Close()
+	blockRLP, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	accounts, err := state.ExportState(p.vm.blockChain.StateCache(), state.ExportHeader{Root: block.Root(), Block: blockRLP}, w)
+	if err != nil {
+		return fmt.Errorf("failed to export state of block %d: %w", block.NumberU64(), err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	reply.Height = json.Uint64(block.NumberU64())
+	reply.Root = block.Root()
+	reply.Accounts = json.Uint64(accounts)
+	return f.Sync()
+}
+
+type ImportStateArgs struct {
+	// File the state is read from, as written by ExportState.
+	File string `json:"file"`
+	// TrustedHash is the hash of the exported block, obtained from a trusted source.
+	TrustedHash common.Hash `json:"trustedHash"`
+}
+
+type ImportStateReply struct {
+	Height json.Uint64 `json:"height"`
+	Root   common.Hash `json:"root"`
+}
+
+// ImportState reads a state export written by ExportState, writes the verified trie
+// nodes and code it contains to the database of this node and makes the exported block
+// the last accepted block. Only permitted before the chain has bootstrapped.
+func (p *Admin) ImportState(_ *http.Request, args *ImportStateArgs, reply *ImportStateReply) error {
+	log.Info("Admin: ImportState called", "file", args.File, "trustedHash", args.TrustedHash)
+	if args.File == "" {
+		return errors.New("file must be specified")
+	}
+
+	f, err := os.Open(args.File)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+	block, err := p.vm.importState(f, args.TrustedHash)
+	if err != nil {
+		return fmt.Errorf("failed to import state: %w", err)
+	}
+	reply.Height = json.Uint64(block.NumberU64())
+	reply.Root = block.Root()
+	return nil
+}
+
+type ExportChainArgs struct {
+	First json.Uint64 `json:"first"`
+	Last  json.Uint64 `json:"last"`
+	// Dir the archives are written to.
+	Dir string `json:"dir"`
+}
+
+type ExportChainReply struct {
+	Files []string `json:"files"`
+}
+
+// ExportChain writes the accepted blocks in [First, Last] and their receipts to
+// era1-style archives in [Dir].
+func (p *Admin) ExportChain(_ *http.Request, args *ExportChainArgs, reply *ExportChainReply) error {
+	log.Info("Admin: ExportChain called", "first", args.First, "last", args.Last, "dir", args.Dir)
+	if args.Dir == "" {
+		return errors.New("dir must be specified")
+	}
+
+	files, err := p.vm.exportEra(uint64(args.First), uint64(args.Last), args.Dir)
+	reply.Files = files
+	return err
+}
+
+type ImportChainArgs struct {
+	// Dir the archives are read from.
+	Dir string `json:"dir"`
+	// TrustedHash is the hash of the last block to import, obtained from a trusted
+	// source. Blocks are only imported if they form a chain ending at it.
+	TrustedHash common.Hash `json:"trustedHash"`
+}
+
+type ImportChainReply struct {
+	Imported     json.Uint64 `json:"imported"`
+	LastAccepted json.Uint64 `json:"lastAccepted"`
+}
+
+// ImportChain verifies the era1-style archives in [Dir] and accepts the blocks they
+// contain on top of the last accepted block, up to the block with [TrustedHash]. Only
+// permitted before the chain has bootstrapped.
+func (p *Admin) ImportChain(r *http.Request, args *ImportChainArgs, reply *ImportChainReply) error {
+	log.Info("Admin: ImportChain called", "dir", args.Dir, "trustedHash", args.TrustedHash)
+	if args.Dir == "" {
+		return errors.New("dir must be specified")
+	}
+
+	imported, err := p.vm.importEra(r.Context(), args.Dir, args.TrustedHash)
+	reply.Imported = json.Uint64(imported)
+	reply.LastAccepted = json.Uint64(p.vm.blockChain.LastAcceptedBlock().NumberU64())
+	return err
+}
+
+type ExportWarpMessagesArgs struct {
+	// File the snapshot is written to.
+	File string `json:"file"`
+}
+
+type ExportWarpMessagesReply struct {
+	Messages json.Uint64 `json:"messages"`
+}
+
+// ExportWarpMessages writes the unsigned warp messages retained by this node and the
+// blocks that sent them to a snapshot file that relayers can ingest at startup.
+func (p *Admin) ExportWarpMessages(_ *http.Request, args *ExportWarpMessagesArgs, reply *ExportWarpMessagesReply) error {
+	log.Info("Admin: ExportWarpMessages called", "file", args.File)
+	if args.File == "" {
+		return errors.New("file must be specified")
+	}
+
+	f, err := os.Create(args.File)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer f.Close()
+	count, err := warp.ExportSnapshot(p.vm.warpBackend, f)
+	if err != nil {
+		return fmt.Errorf("failed to export warp messages: %w", err)
+	}
+	reply.Messages = json.Uint64(count)
+	return f.Sync()
+}
+
+type SimulateUpgradeArgs struct {
+	// Upgrade is the proposed upgrade.json.
+	Upgrade stdjson.RawMessage `json:"upgrade"`
+	// Timestamp the upgrade is simulated at. Defaults to the current time.
+	Timestamp *json.Uint64 `json:"timestamp,omitempty"`
+}
+
+// SimulateUpgrade verifies a proposed upgrade.json against the accepted chain as
+// if the node restarted with it, and reports the precompiles it enables, disables
+// or reconfigures at [Timestamp]. Nothing is persisted.
+func (p *Admin) SimulateUpgrade(_ *http.Request, args *SimulateUpgradeArgs, reply *UpgradeSimulation) error {
+	log.Info("Admin: SimulateUpgrade called", "timestamp", args.Timestamp)
+	if len(args.Upgrade) == 0 {
+		return errors.New("upgrade must be specified")
+	}
+
+	timestamp := p.vm.clock.Unix()
+	if args.Timestamp != nil {
+		timestamp = uint64(*args.Timestamp)
+	}
+	result, err := p.vm.simulateUpgrade(args.Upgrade, timestamp)
+	if err != nil {
+		return err
+	}
+	*reply = *result
+	return nil
+}
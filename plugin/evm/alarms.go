@@ -0,0 +1,162 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// AlarmReorg is raised when the preferred chain reorganizes deeper than
+	// [Config.ReorgAlarmDepth] blocks.
+	AlarmReorg = "reorg"
+	// AlarmAcceptFailed is raised when a verified block fails to be accepted.
+	AlarmAcceptFailed = "accept-failed"
+	// AlarmTrieCorruption is raised when the trie integrity check finds a trie node
+	// that is missing or does not match its hash.
+	AlarmTrieCorruption = "trie-corruption"
+
+	alarmWebhookTimeout = 10 * time.Second
+	reorgEventsBuffer   = 16
+)
+
+// Alarm is an early warning of a consensus issue or bug, sent to the alarm
+// callbacks and posted as JSON to the alarm webhook.
+type Alarm struct {
+	Kind        string      `json:"kind"`
+	Time        time.Time   `json:"time"`
+	Message     string      `json:"message"`
+	BlockHash   common.Hash `json:"blockHash"`
+	BlockNumber uint64      `json:"blockNumber"`
+	// Depth is the number of preferred blocks dropped by a reorg.
+	Depth int `json:"depth,omitempty"`
+}
+
+// alarms raises alarms by logging them, counting them, calling the registered
+// callbacks and posting them to the webhook, if configured.
+type alarms struct {
+	webhookURL string
+	client     *http.Client
+
+	lock      sync.RWMutex
+	callbacks []func(Alarm)
+
+	reorgCounter        metrics.Counter
+	acceptFailedCounter metrics.Counter
+	trieCorruptCounter  metrics.Counter
+}
+
+func newAlarms(webhookURL string) *alarms {
+	return &alarms{
+		webhookURL:          webhookURL,
+		client:              &http.Client{Timeout: alarmWebhookTimeout},
+		reorgCounter:        metrics.GetOrRegisterCounter("vm/alarms/reorg", nil),
+		acceptFailedCounter: metrics.GetOrRegisterCounter("vm/alarms/accept_failed", nil),
+		trieCorruptCounter:  metrics.GetOrRegisterCounter("vm/alarms/trie_corruption", nil),
+	}
+}
+
+// addCallback registers [fn] to be called with each alarm raised.
+func (a *alarms) addCallback(fn func(Alarm)) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.callbacks = append(a.callbacks, fn)
+}
+
+func (a *alarms) raise(alarm Alarm) {
+	switch alarm.Kind {
+	case AlarmReorg:
+		a.reorgCounter.Inc(1)
+	case AlarmAcceptFailed:
+		a.acceptFailedCounter.Inc(1)
+	case AlarmTrieCorruption:
+		a.trieCorruptCounter.Inc(1)
+	}
+	log.Warn("Alarm raised", "kind", alarm.Kind, "msg", alarm.Message, "blockHash", alarm.BlockHash, "blockNumber", alarm.BlockNumber, "depth", alarm.Depth)
+
+	a.lock.RLock()
+	callbacks := a.callbacks
+	a.lock.RUnlock()
+	for _, fn := range callbacks {
+		fn(alarm)
+	}
+	if a.webhookURL != "" {
+		go a.post(alarm)
+	}
+}
+
+// post sends [alarm] to the webhook. Failures are only logged, since alarms must
+// not interfere with consensus.
+func (a *alarms) post(alarm Alarm) {
+	body, err := json.Marshal(alarm)
+	if err != nil {
+		log.Warn("Failed to marshal alarm", "kind", alarm.Kind, "err", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alarmWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		log.Warn("Failed to create alarm webhook request", "kind", alarm.Kind, "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		log.Warn("Failed to post alarm to webhook", "kind", alarm.Kind, "err", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		log.Warn("Alarm webhook returned an error", "kind", alarm.Kind, "status", resp.Status)
+	}
+}
+
+// AddAlarmCallback registers [fn] to be called with each alarm raised by the VM.
+// [fn] is called synchronously, so it must not block.
+func (vm *VM) AddAlarmCallback(fn func(Alarm)) {
+	vm.alarms.addCallback(fn)
+}
+
+// watchReorgs raises an alarm for each reorg of the preferred chain that drops at
+// least [config.ReorgAlarmDepth] blocks, until the VM shuts down.
+func (vm *VM) watchReorgs() {
+	defer vm.shutdownWg.Done()
+
+	reorgs := make(chan core.ChainReorgEvent, reorgEventsBuffer)
+	sub := vm.blockChain.SubscribeChainReorgEvent(reorgs)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case ev := <-reorgs:
+			if uint64(ev.Dropped) < vm.config.ReorgAlarmDepth {
+				continue
+			}
+			vm.alarms.raise(Alarm{
+				Kind:        AlarmReorg,
+				Time:        time.Now(),
+				Message:     fmt.Sprintf("preferred chain reorganized %d blocks deep above block %d", ev.Dropped, ev.Common.Number),
+				BlockHash:   ev.NewHead.Hash(),
+				BlockNumber: ev.NewHead.Number.Uint64(),
+				Depth:       ev.Dropped,
+			})
+		case <-sub.Err():
+			return
+		case <-vm.shutdownChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlarms(t *testing.T) {
+	require := require.New(t)
+
+	posted := make(chan Alarm, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alarm Alarm
+		require.NoError(json.NewDecoder(r.Body).Decode(&alarm))
+		posted <- alarm
+	}))
+	defer server.Close()
+
+	a := newAlarms(server.URL)
+	var called []Alarm
+	a.addCallback(func(alarm Alarm) { called = append(called, alarm) })
+
+	alarm := Alarm{
+		Kind:        AlarmReorg,
+		Time:        time.Unix(1000, 0).UTC(),
+		Message:     "test",
+		BlockHash:   common.Hash{1},
+		BlockNumber: 5,
+		Depth:       3,
+	}
+	a.raise(alarm)
+	require.Equal([]Alarm{alarm}, called)
+
+	select {
+	case got := <-posted:
+		require.Equal(alarm, got)
+	case <-time.After(5 * time.Second):
+		require.FailNow("alarm was not posted to the webhook")
+	}
+}
@@ -16,10 +16,12 @@ import (
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/metrics"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
 	"github.com/ava-labs/subnet-evm/precompile/results"
 	"github.com/ava-labs/subnet-evm/utils/predicate"
+	warpBackend "github.com/ava-labs/subnet-evm/warp"
 	"github.com/ava-labs/subnet-evm/warp/payload"
 	"github.com/ava-labs/subnet-evm/x/warp"
 
@@ -35,6 +37,14 @@ var (
 	_ block.WithVerifyContext = (*Block)(nil)
 )
 
+// Time (ms) spent in each stage of accepting a block. Accepting on the chain
+// blocks while the acceptor queue is full.
+var (
+	acceptPrecompileTimer = metrics.NewRegisteredCounter("vm/accept/precompile", nil)
+	acceptChainTimer      = metrics.NewRegisteredCounter("vm/accept/chain", nil)
+	acceptCommitTimer     = metrics.NewRegisteredCounter("vm/accept/commit", nil)
+)
+
 // Block implements the snowman.Block interface
 type Block struct {
 	id       ids.ID
@@ -56,7 +66,22 @@ func (vm *VM) newBlock(ethBlock *types.Block) *Block {
 func (b *Block) ID() ids.ID { return b.id }
 
 // Accept implements the snowman.Block interface
-func (b *Block) Accept(context.Context) error {
+func (b *Block) Accept(ctx context.Context) error {
+	defer b.vm.beginConsensusWork()()
+	if err := b.accept(ctx); err != nil {
+		b.vm.alarms.raise(Alarm{
+			Kind:        AlarmAcceptFailed,
+			Time:        time.Now(),
+			Message:     err.Error(),
+			BlockHash:   b.ethBlock.Hash(),
+			BlockNumber: b.ethBlock.NumberU64(),
+		})
+		return err
+	}
+	return nil
+}
+
+func (b *Block) accept(context.Context) error {
 	vm := b.vm
 
 	// Although returning an error from Accept is considered fatal, it is good
@@ -71,14 +96,22 @@ func (b *Block) Accept(context.Context) error {
 	// take place before the accepted log is emitted to subscribers. Use of the
 	// sharedMemoryWriter ensures shared memory requests generated by
 	// precompiles are committed atomically with the vm's lastAcceptedKey.
+	start := time.Now()
 	rules := b.vm.chainConfig.AvalancheRules(b.ethBlock.Number(), b.ethBlock.Timestamp())
 	sharedMemoryWriter := NewSharedMemoryWriter()
 	if err := b.handlePrecompileAccept(&rules, sharedMemoryWriter); err != nil {
 		return err
 	}
+	acceptPrecompileTimer.Inc(time.Since(start).Milliseconds())
+
+	start = time.Now()
 	if err := vm.blockChain.Accept(b.ethBlock); err != nil {
 		return fmt.Errorf("chain could not accept %s: %w", b.ID(), err)
 	}
+	acceptChainTimer.Inc(time.Since(start).Milliseconds())
+	defer func(start time.Time) {
+		acceptCommitTimer.Inc(time.Since(start).Milliseconds())
+	}(time.Now())
 
 	if err := vm.acceptedBlockDB.Put(lastAcceptedKey, b.id[:]); err != nil {
 		return fmt.Errorf("failed to put %s as the last accepted block: %w", b.ID(), err)
@@ -114,10 +147,17 @@ func (b *Block) handlePrecompileAccept(rules *params.Rules, sharedMemoryWriter *
 	if len(receipts) == 0 && b.ethBlock.ReceiptHash() != types.EmptyRootHash {
 		return fmt.Errorf("failed to fetch receipts for accepted block with non-empty root hash (%s) (Block: %s, Height: %d)", b.ethBlock.ReceiptHash(), b.ethBlock.Hash(), b.ethBlock.NumberU64())
 	}
+	warpWriter := &warpMessageWriter{
+		backend: b.vm.warpBackend,
+		provenance: warpBackend.MessageProvenance{
+			BlockNumber: b.ethBlock.NumberU64(),
+			BlockHash:   b.ethBlock.Hash(),
+		},
+	}
 	acceptCtx := &precompileconfig.AcceptContext{
 		SnowCtx:      b.vm.ctx,
 		SharedMemory: sharedMemoryWriter,
-		Warp:         b.vm.warpBackend,
+		Warp:         warpWriter,
 	}
 	for _, receipt := range receipts {
 		for logIdx, log := range receipt.Logs {
@@ -125,6 +165,8 @@ func (b *Block) handlePrecompileAccept(rules *params.Rules, sharedMemoryWriter *
 			if !ok {
 				continue
 			}
+			warpWriter.provenance.TxHash = log.TxHash
+			warpWriter.provenance.LogIndex = log.Index
 			if err := accepter.Accept(acceptCtx, log.TxHash, logIdx, log.Topics, log.Data); err != nil {
 				return err
 			}
@@ -141,7 +183,11 @@ func (b *Block) handlePrecompileAccept(rules *params.Rules, sharedMemoryWriter *
 		if err != nil {
 			return fmt.Errorf("failed to create unsigned message for block hash payload: %w", err)
 		}
-		if err := b.vm.warpBackend.AddMessage(unsignedMessage); err != nil {
+		provenance := &warpBackend.MessageProvenance{
+			BlockNumber: b.ethBlock.NumberU64(),
+			BlockHash:   b.ethBlock.Hash(),
+		}
+		if err := b.vm.warpBackend.AddMessageWithProvenance(unsignedMessage, provenance); err != nil {
 			return fmt.Errorf("failed to add block hash payload unsigned message: %w", err)
 		}
 	}
@@ -149,6 +195,19 @@ func (b *Block) handlePrecompileAccept(rules *params.Rules, sharedMemoryWriter *
 	return nil
 }
 
+// warpMessageWriter adds the warp messages sent by the logs of an accepted block to
+// the warp backend along with their provenance, which is updated before each log is
+// passed to an accepter.
+type warpMessageWriter struct {
+	backend    warpBackend.Backend
+	provenance warpBackend.MessageProvenance
+}
+
+func (w *warpMessageWriter) AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) error {
+	provenance := w.provenance
+	return w.backend.AddMessageWithProvenance(unsignedMessage, &provenance)
+}
+
 // Reject implements the snowman.Block interface
 func (b *Block) Reject(context.Context) error {
 	b.status = choices.Rejected
@@ -193,6 +252,9 @@ func (b *Block) syntacticVerify() error {
 
 // Verify implements the snowman.Block interface
 func (b *Block) Verify(context.Context) error {
+	if b.vm.following {
+		return errFollowerMode
+	}
 	return b.verify(&precompileconfig.PredicateContext{
 		SnowCtx:            b.vm.ctx,
 		ProposerVMBlockCtx: nil,
@@ -224,6 +286,9 @@ func (b *Block) ShouldVerifyWithContext(context.Context) (bool, error) {
 
 // VerifyWithContext implements the block.WithVerifyContext interface
 func (b *Block) VerifyWithContext(ctx context.Context, proposerVMBlockCtx *block.Context) error {
+	if b.vm.following {
+		return errFollowerMode
+	}
 	return b.verify(&precompileconfig.PredicateContext{
 		SnowCtx:            b.vm.ctx,
 		ProposerVMBlockCtx: proposerVMBlockCtx,
@@ -234,6 +299,7 @@ func (b *Block) VerifyWithContext(ctx context.Context, proposerVMBlockCtx *block
 // Enforces that the predicates are valid within [predicateContext].
 // Writes the block details to disk and the state to the trie manager iff writes=true.
 func (b *Block) verify(predicateContext *precompileconfig.PredicateContext, writes bool) error {
+	defer b.vm.beginConsensusWork()()
 	if predicateContext.ProposerVMBlockCtx != nil {
 		log.Debug("Verifying block with context", "block", b.ID(), "height", b.Height())
 	} else {
@@ -246,8 +312,8 @@ func (b *Block) verify(predicateContext *precompileconfig.PredicateContext, writ
 	// Only enforce predicates if the chain has already bootstrapped.
 	// If the chain is still bootstrapping, we can assume that all blocks we are verifying have
 	// been accepted by the network (so the predicate was validated by the network when the
-	// block was originally verified).
-	if b.vm.bootstrapped {
+	// block was originally verified). The same holds for blocks obtained by a follower.
+	if b.vm.bootstrapped && !b.vm.following {
 		if err := b.verifyPredicates(predicateContext); err != nil {
 			return fmt.Errorf("failed to verify predicates: %w", err)
 		}
@@ -44,6 +44,10 @@ type blockBuilder struct {
 	// are still waiting for buildBlock to be called.
 	buildSent bool
 
+	// stopped is true once the VM is shutting down, after which the engine is not
+	// notified to build blocks.
+	stopped bool
+
 	// buildBlockTimer is a timer used to delay retrying block building a minimum amount of time
 	// with the same contents of the mempool.
 	// If the mempool receives a new transaction, the block builder will send a new notification to
@@ -90,6 +94,10 @@ func (b *blockBuilder) handleGenerateBlock() {
 	b.buildBlockLock.Lock()
 	defer b.buildBlockLock.Unlock()
 
+	if b.stopped {
+		return
+	}
+
 	// Reset buildSent now that the engine has called BuildBlock.
 	b.buildSent = false
 
@@ -97,18 +105,27 @@ func (b *blockBuilder) handleGenerateBlock() {
 	b.buildBlockTimer.SetTimeoutIn(minBlockBuildingRetryDelay)
 }
 
-// needToBuild returns true if there are outstanding transactions to be issued
-// into a block.
+// stop stops notifying the engine to build blocks and stops the retry timer.
+func (b *blockBuilder) stop() {
+	b.buildBlockLock.Lock()
+	defer b.buildBlockLock.Unlock()
+
+	b.stopped = true
+	b.buildBlockTimer.Stop()
+}
+
+// needToBuild returns true if there are outstanding transactions or bundles to
+// be issued into a block.
 func (b *blockBuilder) needToBuild() bool {
 	size := b.txPool.PendingSize()
-	return size > 0
+	return size > 0 || b.txPool.PendingBundles() > 0
 }
 
 // markBuilding adds a PendingTxs message to the toEngine channel.
 // markBuilding assumes the [buildBlockLock] is held.
 func (b *blockBuilder) markBuilding() {
 	// If the engine has not called BuildBlock, no need to send another message.
-	if b.buildSent {
+	if b.buildSent || b.stopped {
 		return
 	}
 	b.buildBlockTimer.Cancel() // Cancel any future attempt from the timer to send a PendingTxs message
@@ -145,6 +162,9 @@ func (b *blockBuilder) awaitSubmittedTxs() {
 	// may orphan transactions that were previously in a preferred block.
 	txSubmitChan := make(chan core.NewTxsEvent)
 	b.txPool.SubscribeNewTxsEvent(txSubmitChan)
+	// bundleSubmitChan is invoked when new bundles are submitted, which are never gossiped.
+	bundleSubmitChan := make(chan core.NewTxsEvent)
+	b.txPool.SubscribeNewBundleEvent(bundleSubmitChan)
 
 	b.shutdownWg.Add(1)
 	go b.ctx.Log.RecoverAndPanic(func() {
@@ -166,6 +186,9 @@ func (b *blockBuilder) awaitSubmittedTxs() {
 						)
 					}
 				}
+			case <-bundleSubmitChan:
+				log.Trace("New bundle detected, trying to generate a block")
+				b.signalTxsReady()
 			case <-b.shutdownChan:
 				b.buildBlockTimer.Stop()
 				return
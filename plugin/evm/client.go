@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -22,7 +23,12 @@ type Client interface {
 	MemoryProfile(ctx context.Context) error
 	LockProfile(ctx context.Context) error
 	SetLogLevel(ctx context.Context, level log.Lvl) error
+	SetModuleLogLevel(ctx context.Context, module string, level log.Lvl) error
+	ResetModuleLogLevel(ctx context.Context, module string) error
+	GetLogLevels(ctx context.Context) (*GetLogLevelsReply, error)
 	GetVMConfig(ctx context.Context) (*Config, error)
+	SetClock(ctx context.Context, timestamp uint64) (uint64, error)
+	SetRPCCaps(ctx context.Context, gasCap *uint64, txFeeCap *float64) (uint64, float64, error)
 }
 
 // Client implementation for interacting with EVM [chain]
@@ -66,9 +72,56 @@ func (c *client) SetLogLevel(ctx context.Context, level log.Lvl) error {
 	}, &api.EmptyReply{})
 }
 
+// SetModuleLogLevel dynamically sets the log level of the packages in [module]
+func (c *client) SetModuleLogLevel(ctx context.Context, module string, level log.Lvl) error {
+	return c.requester.SendRequest(ctx, "admin.setLogLevel", &SetLogLevelArgs{
+		Module: module,
+		Level:  level.String(),
+	}, &api.EmptyReply{})
+}
+
+// ResetModuleLogLevel removes the log level of [module], so that it logs at the
+// log level of the VM again
+func (c *client) ResetModuleLogLevel(ctx context.Context, module string) error {
+	return c.requester.SendRequest(ctx, "admin.setLogLevel", &SetLogLevelArgs{
+		Module: module,
+	}, &api.EmptyReply{})
+}
+
+// GetLogLevels returns the log level of the VM and of the modules that override it
+func (c *client) GetLogLevels(ctx context.Context) (*GetLogLevelsReply, error) {
+	res := &GetLogLevelsReply{}
+	err := c.requester.SendRequest(ctx, "admin.getLogLevels", struct{}{}, res)
+	return res, err
+}
+
 // GetVMConfig returns the current config of the VM
 func (c *client) GetVMConfig(ctx context.Context) (*Config, error) {
 	res := &ConfigReply{}
 	err := c.requester.SendRequest(ctx, "admin.getVMConfig", struct{}{}, res)
 	return res.Config, err
 }
+
+// SetClock sets the clock of the VM to [timestamp], or syncs it with the system
+// time if [timestamp] is zero, and returns the resulting time of the VM clock.
+// Only supported by builds with the "testclock" tag.
+func (c *client) SetClock(ctx context.Context, timestamp uint64) (uint64, error) {
+	res := &SetClockReply{}
+	err := c.requester.SendRequest(ctx, "admin.setClock", &SetClockArgs{
+		Time: json.Uint64(timestamp),
+	}, res)
+	return uint64(res.Time), err
+}
+
+// SetRPCCaps sets the rpc-gas-cap and rpc-tx-fee-cap of the node, leaving nil caps
+// unchanged, and returns the caps in effect.
+func (c *client) SetRPCCaps(ctx context.Context, gasCap *uint64, txFeeCap *float64) (uint64, float64, error) {
+	args := &SetRPCCapsArgs{TxFeeCap: txFeeCap}
+	if gasCap != nil {
+		gasCapArg := json.Uint64(*gasCap)
+		args.GasCap = &gasCapArg
+	}
+	res := &SetRPCCapsReply{}
+	err := c.requester.SendRequest(ctx, "admin.setRPCCaps", args, res)
+	return uint64(res.GasCap), res.TxFeeCap, err
+}
@@ -6,10 +6,19 @@ package evm
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/external"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/txpool"
 	"github.com/ava-labs/subnet-evm/eth"
+	"github.com/ava-labs/subnet-evm/eth/ethconfig"
+	"github.com/ava-labs/subnet-evm/eth/gasprice"
+	"github.com/ava-labs/subnet-evm/miner"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/sync/handlers"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cast"
 )
@@ -27,6 +36,7 @@ const (
 	defaultSnapshotWait                               = false
 	defaultRpcGasCap                                  = 50_000_000 // Default to 50M Gas Limit
 	defaultRpcTxFeeCap                                = 100        // 100 AVAX
+	defaultRpcGasEstimateMultiplier                   = 1.0
 	defaultMetricsExpensiveEnabled                    = true
 	defaultApiMaxDuration                             = 0 // Default to no maximum API call duration
 	defaultWsCpuRefillRate                            = 0 // Default to no maximum WS CPU usage
@@ -47,7 +57,16 @@ const (
 	defaultMaxOutboundActiveCrossChainRequests        = 64
 	defaultPopulateMissingTriesParallelism            = 1024
 	defaultStateSyncServerTrieCache                   = 64 // MB
+	defaultStateSyncServerPeerRequestRate             = 50 // requests per second
+	defaultStateSyncServerMaxConcurrent               = 32
+	defaultStateSyncServerMaxConcurrentBusy           = 4
 	defaultAcceptedCacheSize                          = 32 // blocks
+	defaultFollowerPollInterval                       = 1 * time.Second
+	defaultWarpSignatureFallbackDelay                 = 2 * time.Second
+	defaultWarpMaxConcurrentRequests                  = 256
+	defaultMemoryCheckInterval                        = 5 * time.Second
+	defaultShutdownTimeout                            = 5 * time.Second
+	defaultTrieIntegrityCheckSamples                  = 256
 
 	// defaultStateSyncMinBlocks is the minimum number of blocks the blockchain
 	// should be ahead of local last accepted to perform state sync.
@@ -58,6 +77,19 @@ const (
 	// - state sync time: ~6 hrs.
 	defaultStateSyncMinBlocks   = 300_000
 	defaultStateSyncRequestSize = 1024 // the number of key/values to ask peers for per request
+	// defaultStateSyncBlockRequestSize is the number of ancestor blocks to ask
+	// peers for per request when fetching the blocks preceding the state summary.
+	defaultStateSyncBlockRequestSize = 32
+	// defaultStateSyncBlockRequestConcurrency is the number of ancestor block requests
+	// in flight at a time. 1 fetches the ancestors one request after another.
+	defaultStateSyncBlockRequestConcurrency = 1
+
+	// RPCAPIProfile serves the APIs enabled by the rest of the config.
+	RPCAPIProfile = "rpc"
+	// ValidatorAPIProfile disables every public API namespace, local transaction
+	// acceptance and the RPC/WebSocket endpoints, leaving health and metrics reporting
+	// (and the admin API, if explicitly enabled) available.
+	ValidatorAPIProfile = "validator"
 )
 
 var (
@@ -70,6 +102,7 @@ var (
 		"internal-blockchain",
 		"internal-transaction",
 	}
+	defaultAPIAuthNamespaces        = []string{"admin", "debug", "warp"}
 	defaultAllowUnprotectedTxHashes = []common.Hash{
 		common.HexToHash("0xfefb2da535e927b85fe68eb81cb2e4a5827c905f78381a01ef2322aa9b0aee8e"), // EIP-1820: https://eips.ethereum.org/EIPS/eip-1820
 	}
@@ -79,21 +112,151 @@ type Duration struct {
 	time.Duration
 }
 
+// APINamespacePolicy restricts the clients that may call the methods of an API namespace.
+// Empty fields impose no restriction.
+type APINamespacePolicy struct {
+	CORSOrigins  []string `json:"cors-origins"` // Origins browsers may call the namespace from ("*" allows any)
+	VirtualHosts []string `json:"vhosts"`       // Host names the namespace may be called through ("*" allows any)
+	AllowedIPs   []string `json:"allowed-ips"`  // Client IPs or CIDR ranges that may call the namespace
+}
+
+// AccessPolicy returns the rpc.AccessPolicy described by [p].
+func (p APINamespacePolicy) AccessPolicy() (*rpc.AccessPolicy, error) {
+	return rpc.ParseAccessPolicy(p.CORSOrigins, p.VirtualHosts, p.AllowedIPs)
+}
+
+// APIQuota limits the rate at which the clients of an API key may call methods.
+// Zero rates impose no limit.
+type APIQuota struct {
+	RequestsPerSecond     float64 `json:"requests-per-second"`
+	RequestBurst          int     `json:"request-burst"`
+	ComputeUnitsPerSecond float64 `json:"compute-units-per-second"`
+	ComputeUnitBurst      int     `json:"compute-unit-burst"`
+}
+
+func (q APIQuota) quota() rpc.Quota {
+	return rpc.Quota{
+		RequestsPerSecond:     q.RequestsPerSecond,
+		RequestBurst:          q.RequestBurst,
+		ComputeUnitsPerSecond: q.ComputeUnitsPerSecond,
+		ComputeUnitBurst:      q.ComputeUnitBurst,
+	}
+}
+
 // Config ...
 type Config struct {
 	// Airdrop
 	AirdropFile string `json:"airdrop"`
 
+	// DevGenesis replaces the genesis with a local development preset that activates
+	// every admin-managed precompile at genesis. See [NewDevGenesis].
+	DevGenesis bool `json:"dev-genesis"`
+
+	// APIProfile is a preset applied on top of the API settings below.
+	// Must be one of [RPCAPIProfile] or [ValidatorAPIProfile].
+	APIProfile string `json:"api-profile"`
+
 	// Subnet EVM APIs
-	SnowmanAPIEnabled bool   `json:"snowman-api-enabled"`
-	WarpAPIEnabled    bool   `json:"warp-api-enabled"`
-	AdminAPIEnabled   bool   `json:"admin-api-enabled"`
-	AdminAPIDir       string `json:"admin-api-dir"`
+	SnowmanAPIEnabled    bool   `json:"snowman-api-enabled"`
+	WarpAPIEnabled       bool   `json:"warp-api-enabled"`
+	ValidatorsAPIEnabled bool   `json:"validators-api-enabled"` // Reports validator set changes, the observed uptime of validators and peer connection stats
+	IndexAPIEnabled      bool   `json:"index-api-enabled"`      // Serves the accepted blocks by height with their consensus bytes
+	StateSyncAPIEnabled  bool   `json:"state-sync-api-enabled"` // Reports the progress of state sync and the contribution of each peer
+	AdminAPIEnabled      bool   `json:"admin-api-enabled"`
+	AdminAPIDir          string `json:"admin-api-dir"`
+
+	// WarpSignatureEndpoints maps the NodeIDs of validators to the base URIs of their
+	// public warp APIs. The warp API falls back to fetching signatures from these
+	// endpoints for validators that do not respond over p2p within
+	// WarpSignatureFallbackDelay.
+	WarpSignatureEndpoints     map[string]string `json:"warp-signature-endpoints"`
+	WarpSignatureFallbackDelay Duration          `json:"warp-signature-fallback-delay"`
+
+	// Limits on the signature requests sent by the warp API to aggregate signatures.
+	// A max of 0 sends requests to all validators at once, and an interval of 0
+	// disables pacing.
+	WarpAggregationMaxConcurrentRequests int      `json:"warp-aggregation-max-concurrent-requests"`
+	WarpAggregationRequestInterval       Duration `json:"warp-aggregation-request-interval"`
+
+	// WarpSignHTTPEnabled serves the signatures of warp messages sent by this chain
+	// at "/ext/bc/{chain}/warp/sign" to requests carrying a JWT signed with
+	// [APIAuthJWTSecret], so that trusted relayers can bypass the p2p layer.
+	WarpSignHTTPEnabled bool `json:"warp-sign-http-enabled"`
+
+	// ValidatorEventsInterval is the frequency to check the validator set of the subnet for
+	// changes, which are sent to subscribers of validators_subscribe("validatorSetChanges")
+	// when [ValidatorsAPIEnabled] is set. 0 disables validator set events.
+	ValidatorEventsInterval Duration `json:"validator-events-interval"`
+
+	// WarpValidatorSetInterval is the frequency to check the P-Chain height for a new
+	// validator set epoch, at which a warp message summarizing the validator set of the
+	// subnet is signed for light clients. 0 disables validator set messages.
+	// WarpValidatorSetEpoch is the number of P-Chain blocks between validator set
+	// messages, so that all validators sign messages at the same P-Chain heights.
+	WarpValidatorSetInterval Duration `json:"warp-validator-set-interval"`
+	WarpValidatorSetEpoch    uint64   `json:"warp-validator-set-epoch"`
+
+	// WarpNextBLSKeyFile is the path of the BLS secret key this node rotates to, which
+	// signs warp messages once it is registered for this node on the P-Chain, or from
+	// WarpNextBLSKeyActivation (unix timestamp) if the registered key is unknown.
+	WarpNextBLSKeyFile       string `json:"warp-next-bls-key-file"`
+	WarpNextBLSKeyActivation uint64 `json:"warp-next-bls-key-activation"`
+
+	// Alarms warn of consensus issues or bugs. ReorgAlarmDepth is the number of
+	// preferred blocks a reorg must drop to raise an alarm (0 disables reorg alarms),
+	// and alarms are posted as JSON to AlarmWebhookURL if it is set. Blocks that
+	// fail to be accepted always raise an alarm.
+	ReorgAlarmDepth uint64 `json:"reorg-alarm-depth"`
+	AlarmWebhookURL string `json:"alarm-webhook-url"`
+
+	// MemoryCeiling is the resident set size (MB) the node should stay under. When the
+	// process nears it, checked every MemoryCheckInterval, the caches of recent blocks
+	// and dirty trie nodes are shrunk. The clean trie and snapshot caches are not shrunk,
+	// so the ceiling should leave room above TrieCleanCache and SnapshotCache. 0 disables
+	// the check.
+	MemoryCeiling       uint64   `json:"memory-ceiling"`
+	MemoryCheckInterval Duration `json:"memory-check-interval"`
+
+	// ShutdownTimeout bounds how long shutdown waits for in-flight RPC requests to
+	// finish, after new requests are rejected.
+	ShutdownTimeout Duration `json:"shutdown-timeout"`
+
+	// TrieIntegrityCheckInterval is how often TrieIntegrityCheckSamples trie nodes of
+	// the last accepted state are checked against their hashes (0 disables the
+	// check). If TrieRepairEnabled, corrupt nodes are repaired by fetching the
+	// leaves below them from peers.
+	TrieIntegrityCheckInterval Duration `json:"trie-integrity-check-interval"`
+	TrieIntegrityCheckSamples  int      `json:"trie-integrity-check-samples"`
+	TrieRepairEnabled          bool     `json:"trie-repair-enabled"`
+
+	// ContractGasAnalyticsWindow is the number of recent accepted blocks over which
+	// the gas used by the transactions calling each contract is aggregated and
+	// served by the analytics API (0 disables the analytics).
+	ContractGasAnalyticsWindow int `json:"contract-gas-analytics-window"`
+
+	// FaultInjection injects faults into the signature responses and gossip messages
+	// handled by this node, to test how the rest of the network copes with them. It is
+	// only available in builds with the "faultinjection" tag.
+	FaultInjection *FaultInjectionConfig `json:"fault-injection,omitempty"`
 
 	// EnabledEthAPIs is a list of Ethereum services that should be enabled
 	// If none is specified, then we use the default list [defaultEnabledAPIs]
 	EnabledEthAPIs []string `json:"eth-apis"`
 
+	// API Authentication
+	APIAuthJWTSecret  string   `json:"api-auth-jwt-secret"` // Path to a file holding the hex encoded 32 byte secret used to verify JWTs. Authentication is disabled if empty.
+	APIAuthNamespaces []string `json:"api-auth-namespaces"` // Namespaces whose methods require a valid JWT if [APIAuthJWTSecret] is set
+
+	// APINamespacePolicies maps API namespaces (ex. "eth", "debug") to the clients
+	// permitted to call their methods.
+	APINamespacePolicies map[string]APINamespacePolicy `json:"api-namespace-policies"`
+
+	// API Quotas
+	APIKeyHeader          string              `json:"api-key-header"`           // HTTP header holding the API key of a request
+	APIKeyQuotas          map[string]APIQuota `json:"api-key-quotas"`           // Quotas of each API key. Quotas are enforced if non-empty.
+	APIDefaultQuota       *APIQuota           `json:"api-default-quota"`        // Quota shared by requests without a known API key. Such requests are rejected if nil.
+	APIMethodComputeUnits map[string]uint64   `json:"api-method-compute-units"` // Compute units consumed by each method (default 1)
+
 	// Continuous Profiler
 	ContinuousProfilerDir       string   `json:"continuous-profiler-dir"`       // If set to non-empty string creates a continuous profiler
 	ContinuousProfilerFrequency Duration `json:"continuous-profiler-frequency"` // Frequency to run continuous profiler if enabled
@@ -103,6 +266,25 @@ type Config struct {
 	RPCGasCap   uint64  `json:"rpc-gas-cap"`
 	RPCTxFeeCap float64 `json:"rpc-tx-fee-cap"`
 
+	// Gas estimates are multiplied by RPCGasEstimateMultiplier and increased by
+	// RPCGasEstimatePadding, up to the gas allowance of the estimation, so that
+	// they stay sufficient on chains where fees and state change within a block
+	// or two.
+	RPCGasEstimateMultiplier float64 `json:"rpc-gas-estimate-multiplier"`
+	RPCGasEstimatePadding    uint64  `json:"rpc-gas-estimate-padding"`
+
+	// HistoricalProofQueryWindow is the number of blocks before the last accepted block
+	// for which eth_getProof is served. Zero serves proofs at any height for which the
+	// state is retained, which with pruning disabled is every height.
+	HistoricalProofQueryWindow uint64 `json:"historical-proof-query-window"`
+
+	// Gas Price Oracle Settings
+	GasPriceOracleBlocks              int    `json:"gpo-blocks"`               // Number of recent blocks sampled when suggesting a tip
+	GasPriceOraclePercentile          int    `json:"gpo-percentile"`           // Percentile of sampled tips to suggest
+	GasPriceOracleMaxPrice            uint64 `json:"gpo-max-price"`            // Maximum tip (wei) that will be suggested
+	GasPriceOracleMode                string `json:"gpo-mode"`                 // Tip suggestion algorithm ("sampling" or "congestion")
+	GasPriceOracleCongestionThreshold int    `json:"gpo-congestion-threshold"` // Pending mempool size above which "congestion" mode scales up the suggested tip
+
 	// Cache settings
 	TrieCleanCache        int      `json:"trie-clean-cache"`         // Size of the trie clean cache (MB)
 	TrieCleanJournal      string   `json:"trie-clean-journal"`       // Directory to use to save the trie clean cache (must be populated to enable journaling the trie clean cache)
@@ -110,6 +292,7 @@ type Config struct {
 	TrieDirtyCache        int      `json:"trie-dirty-cache"`         // Size of the trie dirty cache (MB)
 	TrieDirtyCommitTarget int      `json:"trie-dirty-commit-target"` // Memory limit to target in the dirty cache before performing a commit (MB)
 	SnapshotCache         int      `json:"snapshot-cache"`           // Size of the snapshot disk layer clean cache (MB)
+	SnapshotCacheJournal  string   `json:"snapshot-cache-journal"`   // Directory to save the snapshot disk layer clean cache to on shutdown and restore it from on startup
 
 	// Eth Settings
 	Preimages      bool `json:"preimages-enabled"`
@@ -119,6 +302,8 @@ type Config struct {
 	// Pruning Settings
 	Pruning                         bool    `json:"pruning-enabled"`                    // If enabled, trie roots are only persisted every 4096 blocks
 	AcceptorQueueLimit              int     `json:"accepted-queue-limit"`               // Maximum blocks to queue before blocking during acceptance
+	AcceptedIndexingParallelism     int     `json:"accepted-indexing-parallelism"`      // Number of queued blocks whose logs are collected concurrently ahead of acceptance (0 disables)
+	AcceptedEventBuffer             int     `json:"accepted-event-buffer"`              // Number of accepted events buffered for RPC subscriptions before the acceptor blocks on them (0 uses the default of 10)
 	CommitInterval                  uint64  `json:"commit-interval"`                    // Specifies the commit interval at which to persist EVM and atomic tries.
 	AllowMissingTries               bool    `json:"allow-missing-tries"`                // If enabled, warnings preventing an incomplete trie index are suppressed
 	PopulateMissingTries            *uint64 `json:"populate-missing-tries,omitempty"`   // Sets the starting point for re-populating missing tries. Disables re-generation if nil.
@@ -139,19 +324,31 @@ type Config struct {
 	TxPoolGlobalSlots  uint64   `json:"tx-pool-global-slots"`
 	TxPoolAccountQueue uint64   `json:"tx-pool-account-queue"`
 	TxPoolGlobalQueue  uint64   `json:"tx-pool-global-queue"`
-
-	APIMaxDuration           Duration      `json:"api-max-duration"`
-	WSCPURefillRate          Duration      `json:"ws-cpu-refill-rate"`
-	WSCPUMaxStored           Duration      `json:"ws-cpu-max-stored"`
-	MaxBlocksPerRequest      int64         `json:"api-max-blocks-per-request"`
-	AllowUnfinalizedQueries  bool          `json:"allow-unfinalized-queries"`
-	AllowUnprotectedTxs      bool          `json:"allow-unprotected-txs"`
-	AllowUnprotectedTxHashes []common.Hash `json:"allow-unprotected-tx-hashes"`
+	TxPoolLifetime     Duration `json:"tx-pool-lifetime"`
+	TxPoolMaxNonceGap  uint64   `json:"tx-pool-max-nonce-gap"`
+
+	APIMaxDuration            Duration      `json:"api-max-duration"`
+	APISlowQueryThreshold     Duration      `json:"api-slow-query-threshold"` // Calls taking longer are logged along with their parameters (0 is disabled)
+	APICallTimeout            Duration      `json:"api-call-timeout"`         // EVM execution timeout of eth_call and eth_estimateGas (0 uses api-max-duration)
+	APITraceTimeout           Duration      `json:"api-trace-timeout"`        // Default timeout of a single transaction trace, after which the partial result is returned (0 uses the tracer default)
+	APILogsTimeout            Duration      `json:"api-logs-timeout"`         // Timeout of eth_getLogs and eth_getFilterLogs, after which an error is returned (0 is unlimited)
+	ResumableFilterTTL        Duration      `json:"resumable-filter-ttl"`     // Time after which a resumable filter that is not polled is removed (0 uses the default of 24h)
+	MaxResumableFilters       int           `json:"max-resumable-filters"`    // Maximum number of resumable filters stored by the node (0 uses the default of 1000)
+	WSCPURefillRate           Duration      `json:"ws-cpu-refill-rate"`
+	WSCPUMaxStored            Duration      `json:"ws-cpu-max-stored"`
+	WSMaxConnections          int           `json:"ws-max-connections"`           // Maximum number of concurrent WebSocket connections (0 is unlimited)
+	WSMaxSubscriptions        int           `json:"ws-max-subscriptions"`         // Maximum number of subscriptions per WebSocket connection (0 is unlimited)
+	WSMaxPendingNotifications int           `json:"ws-max-pending-notifications"` // Maximum number of notifications buffered per WebSocket connection before it is closed (0 is unbuffered)
+	MaxBlocksPerRequest       int64         `json:"api-max-blocks-per-request"`
+	AllowUnfinalizedQueries   bool          `json:"allow-unfinalized-queries"`
+	AllowUnprotectedTxs       bool          `json:"allow-unprotected-txs"`
+	AllowUnprotectedTxHashes  []common.Hash `json:"allow-unprotected-tx-hashes"`
 
 	// Keystore Settings
-	KeystoreDirectory             string `json:"keystore-directory"` // both absolute and relative supported
-	KeystoreExternalSigner        string `json:"keystore-external-signer"`
-	KeystoreInsecureUnlockAllowed bool   `json:"keystore-insecure-unlock-allowed"`
+	KeystoreDirectory             string           `json:"keystore-directory"`                        // both absolute and relative supported
+	KeystoreExternalSigner        string           `json:"keystore-external-signer"`                  // IPC path or URL of a clef-style external signer
+	KeystoreExternalSignerPolicy  *external.Policy `json:"keystore-external-signer-policy,omitempty"` // Approval policy applied before forwarding to the external signer
+	KeystoreInsecureUnlockAllowed bool             `json:"keystore-insecure-unlock-allowed"`
 
 	// Gossip Settings
 	RemoteGossipOnlyEnabled       bool             `json:"remote-gossip-only-enabled"`
@@ -170,6 +367,12 @@ type Config struct {
 	// Address for Tx Fees (must be empty if not supported by blockchain)
 	FeeRecipient string `json:"feeRecipient"`
 
+	// TxOrdering is the name of the policy ordering transactions in built blocks.
+	// One of "price", which orders by tip, or "fcfs", which orders strictly by arrival
+	// time to prevent priority gas auctions. Defaults to "price" if empty. Forks may
+	// add policies with miner.RegisterOrderingPolicy.
+	TxOrdering string `json:"tx-ordering"`
+
 	// Offline Pruning Settings
 	OfflinePruning                bool   `json:"offline-pruning-enabled"`
 	OfflinePruningBloomFilterSize uint64 `json:"offline-pruning-bloom-filter-size"`
@@ -187,6 +390,27 @@ type Config struct {
 	StateSyncCommitInterval  uint64 `json:"state-sync-commit-interval"`
 	StateSyncMinBlocks       uint64 `json:"state-sync-min-blocks"`
 	StateSyncRequestSize     uint16 `json:"state-sync-request-size"`
+	// StateSyncBlockRequestSize is the number of ancestor blocks requested from a
+	// peer at a time when fetching the blocks preceding the state summary. Peers
+	// serve at most 64 blocks per request.
+	StateSyncBlockRequestSize uint16 `json:"state-sync-block-request-size"`
+	// StateSyncBlockRequestConcurrency is the number of ancestor block requests in
+	// flight at a time. Concurrent requests ask peers for ranges of ancestors by
+	// height, which is only served by peers running this version or later.
+	StateSyncBlockRequestConcurrency int `json:"state-sync-block-request-concurrency"`
+
+	// Limits on serving state sync leafs and code requests to peers, so that peers
+	// cannot saturate the disk of the node. Requests over a limit are dropped. While
+	// blocks are verified, built or accepted, at most
+	// StateSyncServerMaxConcurrentRequestsBusy requests are served. 0 disables a limit.
+	StateSyncServerPeerRequestRate           float64 `json:"state-sync-server-peer-request-rate"`
+	StateSyncServerPeerRequestBurst          int     `json:"state-sync-server-peer-request-burst"`
+	StateSyncServerMaxConcurrentRequests     int     `json:"state-sync-server-max-concurrent-requests"`
+	StateSyncServerMaxConcurrentRequestsBusy int     `json:"state-sync-server-max-concurrent-requests-busy"`
+
+	// Follower settings
+	FollowerRPCURL       string   `json:"follower-rpc-url"`       // URL of a node whose accepted chain is followed instead of participating in consensus
+	FollowerPollInterval Duration `json:"follower-poll-interval"` // Frequency to poll the followed node for newly accepted blocks
 
 	// Database Settings
 	InspectDatabase bool `json:"inspect-database"` // Inspects the database on startup if enabled.
@@ -208,7 +432,16 @@ type Config struct {
 	// are reserved:
 	//  * 0:   means no limit
 	//  * N:   means N block limit [HEAD-N+1, HEAD] and delete extra indexes
+	// Looking up a transaction that is not found while indexes are being deleted
+	// returns an error reporting the oldest indexed block.
 	TxLookupLimit uint64 `json:"tx-lookup-limit"`
+
+	// ReceiptCompression is the compression applied to newly stored receipts, one of
+	// "none", "snappy" or "zstd". Receipts are read regardless of their compression.
+	ReceiptCompression string `json:"receipt-compression"`
+	// MigrateReceiptCompression rewrites all stored receipts with ReceiptCompression
+	// on startup.
+	MigrateReceiptCompression bool `json:"migrate-receipt-compression"`
 }
 
 // EthAPIs returns an array of strings representing the Eth APIs that should be enabled
@@ -220,12 +453,67 @@ func (c Config) EthBackendSettings() eth.Settings {
 	return eth.Settings{MaxBlocksPerRequest: c.MaxBlocksPerRequest}
 }
 
+// RPCEnabled returns true if the eth RPC and WebSocket endpoints should be served.
+func (c Config) RPCEnabled() bool {
+	return c.APIProfile != ValidatorAPIProfile
+}
+
+// APIQuotas returns the quotas enforced on the eth RPC and WebSocket endpoints, or nil
+// if no API key quotas are configured.
+func (c Config) APIQuotas() (*rpc.Quotas, error) {
+	if len(c.APIKeyQuotas) == 0 {
+		return nil, nil
+	}
+	config := rpc.QuotaConfig{
+		KeyHeader:    c.APIKeyHeader,
+		Keys:         make(map[string]rpc.Quota, len(c.APIKeyQuotas)),
+		ComputeUnits: c.APIMethodComputeUnits,
+	}
+	for key, quota := range c.APIKeyQuotas {
+		config.Keys[key] = quota.quota()
+	}
+	if c.APIDefaultQuota != nil {
+		defaultQuota := c.APIDefaultQuota.quota()
+		config.Default = &defaultQuota
+	}
+	return rpc.NewQuotas(config)
+}
+
+// FollowerEnabled returns true if the node follows the accepted chain of the node at
+// [c.FollowerRPCURL] instead of participating in consensus.
+func (c Config) FollowerEnabled() bool {
+	return len(c.FollowerRPCURL) > 0
+}
+
+// ApplyAPIProfile overrides the API settings of [c] as required by [c.APIProfile].
+func (c *Config) ApplyAPIProfile() {
+	if c.APIProfile != ValidatorAPIProfile {
+		return
+	}
+	c.EnabledEthAPIs = nil
+	c.SnowmanAPIEnabled = false
+	c.WarpAPIEnabled = false
+	c.ValidatorsAPIEnabled = false
+	c.IndexAPIEnabled = false
+	c.StateSyncAPIEnabled = false
+	c.LocalTxsEnabled = false
+}
+
 func (c *Config) SetDefaults() {
+	c.APIProfile = RPCAPIProfile
 	c.EnabledEthAPIs = defaultEnabledAPIs
+	c.APIAuthNamespaces = defaultAPIAuthNamespaces
 	c.RPCGasCap = defaultRpcGasCap
 	c.RPCTxFeeCap = defaultRpcTxFeeCap
+	c.RPCGasEstimateMultiplier = defaultRpcGasEstimateMultiplier
 	c.MetricsExpensiveEnabled = defaultMetricsExpensiveEnabled
 
+	c.GasPriceOracleBlocks = ethconfig.DefaultFullGPOConfig.Blocks
+	c.GasPriceOraclePercentile = ethconfig.DefaultFullGPOConfig.Percentile
+	c.GasPriceOracleMaxPrice = ethconfig.DefaultFullGPOConfig.MaxPrice.Uint64()
+	c.GasPriceOracleMode = gasprice.SamplingMode
+	c.GasPriceOracleCongestionThreshold = gasprice.DefaultCongestionThreshold
+
 	c.TxPoolJournal = txpool.DefaultConfig.Journal
 	c.TxPoolRejournal = Duration{txpool.DefaultConfig.Rejournal}
 	c.TxPoolPriceLimit = txpool.DefaultConfig.PriceLimit
@@ -234,6 +522,8 @@ func (c *Config) SetDefaults() {
 	c.TxPoolGlobalSlots = txpool.DefaultConfig.GlobalSlots
 	c.TxPoolAccountQueue = txpool.DefaultConfig.AccountQueue
 	c.TxPoolGlobalQueue = txpool.DefaultConfig.GlobalQueue
+	c.TxPoolLifetime = Duration{txpool.DefaultConfig.Lifetime}
+	c.TxPoolMaxNonceGap = txpool.DefaultConfig.MaxNonceGap
 
 	c.APIMaxDuration.Duration = defaultApiMaxDuration
 	c.WSCPURefillRate.Duration = defaultWsCpuRefillRate
@@ -262,11 +552,22 @@ func (c *Config) SetDefaults() {
 	c.MaxOutboundActiveCrossChainRequests = defaultMaxOutboundActiveCrossChainRequests
 	c.PopulateMissingTriesParallelism = defaultPopulateMissingTriesParallelism
 	c.StateSyncServerTrieCache = defaultStateSyncServerTrieCache
+	c.StateSyncServerPeerRequestRate = defaultStateSyncServerPeerRequestRate
+	c.StateSyncServerMaxConcurrentRequests = defaultStateSyncServerMaxConcurrent
+	c.StateSyncServerMaxConcurrentRequestsBusy = defaultStateSyncServerMaxConcurrentBusy
 	c.StateSyncCommitInterval = defaultSyncableCommitInterval
 	c.StateSyncMinBlocks = defaultStateSyncMinBlocks
 	c.StateSyncRequestSize = defaultStateSyncRequestSize
+	c.StateSyncBlockRequestSize = defaultStateSyncBlockRequestSize
+	c.StateSyncBlockRequestConcurrency = defaultStateSyncBlockRequestConcurrency
+	c.FollowerPollInterval.Duration = defaultFollowerPollInterval
+	c.WarpSignatureFallbackDelay.Duration = defaultWarpSignatureFallbackDelay
+	c.WarpAggregationMaxConcurrentRequests = defaultWarpMaxConcurrentRequests
 	c.AllowUnprotectedTxHashes = defaultAllowUnprotectedTxHashes
 	c.AcceptedCacheSize = defaultAcceptedCacheSize
+	c.MemoryCheckInterval.Duration = defaultMemoryCheckInterval
+	c.ShutdownTimeout.Duration = defaultShutdownTimeout
+	c.TrieIntegrityCheckSamples = defaultTrieIntegrityCheckSamples
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) (err error) {
@@ -297,6 +598,19 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("cannot enable populate missing tries without at least one reader (parallelism: %d)", c.PopulateMissingTriesParallelism)
 	}
 
+	if c.RPCGasEstimateMultiplier < 1 {
+		return fmt.Errorf("rpc gas estimate multiplier must be at least 1 (got %f)", c.RPCGasEstimateMultiplier)
+	}
+	if c.AcceptedEventBuffer < 0 {
+		return fmt.Errorf("cannot use negative accepted event buffer (%d)", c.AcceptedEventBuffer)
+	}
+	if c.ResumableFilterTTL.Duration < 0 || c.MaxResumableFilters < 0 {
+		return fmt.Errorf("resumable filter limits must be non-negative (ttl: %s, max filters: %d)", c.ResumableFilterTTL.Duration, c.MaxResumableFilters)
+	}
+	if c.AcceptedIndexingParallelism < 0 {
+		return fmt.Errorf("cannot use negative accepted indexing parallelism (%d)", c.AcceptedIndexingParallelism)
+	}
+
 	if !c.Pruning && c.OfflinePruning {
 		return fmt.Errorf("cannot run offline pruning while pruning is disabled")
 	}
@@ -305,5 +619,139 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("cannot use commit interval of 0 with pruning enabled")
 	}
 
+	if c.KeystoreExternalSignerPolicy != nil && len(c.KeystoreExternalSigner) == 0 {
+		return fmt.Errorf("cannot specify an external signer policy without an external signer")
+	}
+
+	for namespace, policy := range c.APINamespacePolicies {
+		if _, err := policy.AccessPolicy(); err != nil {
+			return fmt.Errorf("invalid api namespace policy for %q: %w", namespace, err)
+		}
+	}
+
+	if _, err := c.APIQuotas(); err != nil {
+		return fmt.Errorf("invalid api quotas: %w", err)
+	}
+
+	if _, err := rawdb.ParseReceiptCompression(c.ReceiptCompression); err != nil {
+		return fmt.Errorf("invalid receipt compression: %w", err)
+	}
+
+	if _, err := miner.GetOrderingPolicy(c.TxOrdering); err != nil {
+		return fmt.Errorf("invalid tx ordering: %w", err)
+	}
+
+	if c.WSMaxConnections < 0 || c.WSMaxSubscriptions < 0 || c.WSMaxPendingNotifications < 0 {
+		return fmt.Errorf("websocket limits must be non-negative (connections: %d, subscriptions: %d, pending notifications: %d)", c.WSMaxConnections, c.WSMaxSubscriptions, c.WSMaxPendingNotifications)
+	}
+
+	if c.APISlowQueryThreshold.Duration < 0 {
+		return fmt.Errorf("api slow query threshold must be non-negative (got %s)", c.APISlowQueryThreshold.Duration)
+	}
+	for name, timeout := range map[string]time.Duration{
+		"api-call-timeout":  c.APICallTimeout.Duration,
+		"api-trace-timeout": c.APITraceTimeout.Duration,
+		"api-logs-timeout":  c.APILogsTimeout.Duration,
+	} {
+		if timeout < 0 {
+			return fmt.Errorf("%s must be non-negative (got %s)", name, timeout)
+		}
+		// The RPC server aborts any request after api-max-duration, so a longer
+		// per-API timeout would never take effect.
+		if c.APIMaxDuration.Duration > 0 && timeout > c.APIMaxDuration.Duration {
+			return fmt.Errorf("%s (%s) must not exceed api-max-duration (%s)", name, timeout, c.APIMaxDuration.Duration)
+		}
+	}
+
+	if c.FollowerEnabled() {
+		if c.FollowerPollInterval.Duration <= 0 {
+			return fmt.Errorf("follower poll interval must be positive (got %s)", c.FollowerPollInterval.Duration)
+		}
+	}
+
+	if c.APIProfile != RPCAPIProfile && c.APIProfile != ValidatorAPIProfile {
+		return fmt.Errorf("invalid api profile %q (must be %q or %q)", c.APIProfile, RPCAPIProfile, ValidatorAPIProfile)
+	}
+
+	if c.GasPriceOracleMode != gasprice.SamplingMode && c.GasPriceOracleMode != gasprice.CongestionMode {
+		return fmt.Errorf("invalid gas price oracle mode %q (must be %q or %q)", c.GasPriceOracleMode, gasprice.SamplingMode, gasprice.CongestionMode)
+	}
+
+	if _, err := c.WarpSignatureEndpointsByNodeID(); err != nil {
+		return err
+	}
+	if c.WarpSignatureFallbackDelay.Duration < 0 {
+		return fmt.Errorf("warp signature fallback delay must be non-negative (got %s)", c.WarpSignatureFallbackDelay.Duration)
+	}
+	if c.WarpSignHTTPEnabled && len(c.APIAuthJWTSecret) == 0 {
+		return fmt.Errorf("cannot enable the warp sign http endpoint without an api auth jwt secret")
+	}
+	if c.WarpAggregationMaxConcurrentRequests < 0 || c.WarpAggregationRequestInterval.Duration < 0 {
+		return fmt.Errorf("warp aggregation limits must be non-negative (max concurrent requests: %d, request interval: %s)", c.WarpAggregationMaxConcurrentRequests, c.WarpAggregationRequestInterval.Duration)
+	}
+	if c.ValidatorEventsInterval.Duration < 0 {
+		return fmt.Errorf("validator events interval must be non-negative (got %s)", c.ValidatorEventsInterval.Duration)
+	}
+	if c.WarpValidatorSetInterval.Duration < 0 {
+		return fmt.Errorf("warp validator set interval must be non-negative (got %s)", c.WarpValidatorSetInterval.Duration)
+	}
+	if c.WarpValidatorSetInterval.Duration > 0 && c.WarpValidatorSetEpoch == 0 {
+		return fmt.Errorf("warp validator set epoch must be positive when validator set messages are enabled")
+	}
+	if c.ShutdownTimeout.Duration < 0 {
+		return fmt.Errorf("shutdown timeout must be non-negative (got %s)", c.ShutdownTimeout.Duration)
+	}
+	if c.StateSyncServerPeerRequestRate < 0 || c.StateSyncServerPeerRequestBurst < 0 ||
+		c.StateSyncServerMaxConcurrentRequests < 0 || c.StateSyncServerMaxConcurrentRequestsBusy < 0 {
+		return fmt.Errorf("state sync server request limits must be non-negative")
+	}
+	if c.StateSyncBlockRequestSize == 0 || c.StateSyncBlockRequestSize > handlers.MaxParentsPerRequest {
+		return fmt.Errorf("state sync block request size must be between 1 and %d (got %d)", handlers.MaxParentsPerRequest, c.StateSyncBlockRequestSize)
+	}
+	if c.StateSyncBlockRequestConcurrency < 1 {
+		return fmt.Errorf("state sync block request concurrency must be positive (got %d)", c.StateSyncBlockRequestConcurrency)
+	}
+	if c.ContractGasAnalyticsWindow < 0 {
+		return fmt.Errorf("contract gas analytics window must be non-negative (got %d)", c.ContractGasAnalyticsWindow)
+	}
+	if c.TrieIntegrityCheckInterval.Duration < 0 {
+		return fmt.Errorf("trie integrity check interval must be non-negative (got %s)", c.TrieIntegrityCheckInterval.Duration)
+	}
+	if c.TrieIntegrityCheckInterval.Duration > 0 && c.TrieIntegrityCheckSamples <= 0 {
+		return fmt.Errorf("trie integrity check samples must be positive when the check is enabled (got %d)", c.TrieIntegrityCheckSamples)
+	}
+	if c.MemoryCeiling > 0 && c.MemoryCheckInterval.Duration <= 0 {
+		return fmt.Errorf("memory check interval must be positive when the memory ceiling is set (got %s)", c.MemoryCheckInterval.Duration)
+	}
+	if c.WarpNextBLSKeyActivation != 0 && c.WarpNextBLSKeyFile == "" {
+		return fmt.Errorf("cannot set warp next BLS key activation without a next BLS key file")
+	}
+	if c.AlarmWebhookURL != "" {
+		if _, err := url.ParseRequestURI(c.AlarmWebhookURL); err != nil {
+			return fmt.Errorf("invalid alarm webhook URL %q: %w", c.AlarmWebhookURL, err)
+		}
+	}
+	if c.FaultInjection != nil {
+		if !faultInjectionEnabled {
+			return fmt.Errorf("cannot enable fault injection in a build without the faultinjection tag")
+		}
+		if err := c.FaultInjection.Verify(); err != nil {
+			return fmt.Errorf("invalid fault injection: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// WarpSignatureEndpointsByNodeID returns [WarpSignatureEndpoints] keyed by parsed NodeID.
+func (c *Config) WarpSignatureEndpointsByNodeID() (map[ids.NodeID]string, error) {
+	endpoints := make(map[ids.NodeID]string, len(c.WarpSignatureEndpoints))
+	for nodeIDStr, uri := range c.WarpSignatureEndpoints {
+		nodeID, err := ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid warp signature endpoint node ID %q: %w", nodeIDStr, err)
+		}
+		endpoints[nodeID] = uri
+	}
+	return endpoints, nil
+}
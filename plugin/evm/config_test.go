@@ -47,7 +47,7 @@ func TestUnmarshalConfig(t *testing.T) {
 
 		{
 			"tx pool configurations",
-			[]byte(`{"tx-pool-journal": "hello", "tx-pool-price-limit": 1, "tx-pool-price-bump": 2, "tx-pool-account-slots": 3, "tx-pool-global-slots": 4, "tx-pool-account-queue": 5, "tx-pool-global-queue": 6}`),
+			[]byte(`{"tx-pool-journal": "hello", "tx-pool-price-limit": 1, "tx-pool-price-bump": 2, "tx-pool-account-slots": 3, "tx-pool-global-slots": 4, "tx-pool-account-queue": 5, "tx-pool-global-queue": 6, "tx-pool-lifetime": "1h", "tx-pool-max-nonce-gap": 7}`),
 			Config{
 				TxPoolJournal:      "hello",
 				TxPoolPriceLimit:   1,
@@ -56,6 +56,21 @@ func TestUnmarshalConfig(t *testing.T) {
 				TxPoolGlobalSlots:  4,
 				TxPoolAccountQueue: 5,
 				TxPoolGlobalQueue:  6,
+				TxPoolLifetime:     Duration{time.Hour},
+				TxPoolMaxNonceGap:  7,
+			},
+			false,
+		},
+
+		{
+			"gas price oracle configurations",
+			[]byte(`{"gpo-blocks": 10, "gpo-percentile": 50, "gpo-max-price": 1000, "gpo-mode": "congestion", "gpo-congestion-threshold": 64}`),
+			Config{
+				GasPriceOracleBlocks:              10,
+				GasPriceOraclePercentile:          50,
+				GasPriceOracleMaxPrice:            1000,
+				GasPriceOracleMode:                "congestion",
+				GasPriceOracleCongestionThreshold: 64,
 			},
 			false,
 		},
@@ -121,3 +136,142 @@ func TestUnmarshalConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyAPIProfile(t *testing.T) {
+	var rpcConfig Config
+	rpcConfig.SetDefaults()
+	rpcConfig.SnowmanAPIEnabled = true
+	rpcConfig.ApplyAPIProfile()
+	assert.NoError(t, rpcConfig.Validate())
+	assert.True(t, rpcConfig.RPCEnabled())
+	assert.Equal(t, defaultEnabledAPIs, rpcConfig.EthAPIs())
+	assert.True(t, rpcConfig.SnowmanAPIEnabled)
+
+	var validatorConfig Config
+	validatorConfig.SetDefaults()
+	assert.NoError(t, json.Unmarshal([]byte(`{"api-profile": "validator", "warp-api-enabled": true, "admin-api-enabled": true, "local-txs-enabled": true}`), &validatorConfig))
+	assert.NoError(t, validatorConfig.Validate())
+	validatorConfig.ApplyAPIProfile()
+	assert.False(t, validatorConfig.RPCEnabled())
+	assert.Empty(t, validatorConfig.EthAPIs())
+	assert.False(t, validatorConfig.WarpAPIEnabled)
+	assert.False(t, validatorConfig.LocalTxsEnabled)
+	assert.True(t, validatorConfig.AdminAPIEnabled)
+
+	var invalidConfig Config
+	invalidConfig.SetDefaults()
+	invalidConfig.APIProfile = "archive"
+	assert.Error(t, invalidConfig.Validate())
+}
+
+func TestFollowerConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	assert.False(t, config.FollowerEnabled())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"follower-rpc-url": "http://127.0.0.1:9650/ext/bc/C/rpc", "follower-poll-interval": "500ms"}`), &config))
+	assert.True(t, config.FollowerEnabled())
+	assert.Equal(t, 500*time.Millisecond, config.FollowerPollInterval.Duration)
+	assert.NoError(t, config.Validate())
+
+	config.FollowerPollInterval.Duration = 0
+	assert.Error(t, config.Validate())
+}
+
+func TestAPIQuotasConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	quotas, err := config.APIQuotas()
+	assert.NoError(t, err)
+	assert.Nil(t, quotas)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"api-key-header": "X-API-Key", "api-key-quotas": {"team-a": {"requests-per-second": 10}}, "api-method-compute-units": {"eth_call": 5}}`), &config))
+	assert.NoError(t, config.Validate())
+	quotas, err = config.APIQuotas()
+	assert.NoError(t, err)
+	assert.NotNil(t, quotas)
+
+	config.APIKeyHeader = ""
+	assert.Error(t, config.Validate())
+}
+
+func TestReceiptCompressionConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	assert.NoError(t, json.Unmarshal([]byte(`{"receipt-compression": "zstd", "migrate-receipt-compression": true}`), &config))
+	assert.NoError(t, config.Validate())
+	assert.True(t, config.MigrateReceiptCompression)
+
+	config.ReceiptCompression = "gzip"
+	assert.Error(t, config.Validate())
+}
+
+func TestTxOrderingConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	assert.NoError(t, config.Validate())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"tx-ordering": "price"}`), &config))
+	assert.NoError(t, config.Validate())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"tx-ordering": "fcfs"}`), &config))
+	assert.NoError(t, config.Validate())
+
+	config.TxOrdering = "unknown"
+	assert.Error(t, config.Validate())
+}
+
+func TestFaultInjectionConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	assert.NoError(t, json.Unmarshal([]byte(`{"fault-injection": {"gossip": {"drop-rate": 0.5, "delay": "1s"}}}`), &config))
+	assert.EqualValues(t, 0.5, config.FaultInjection.Gossip.DropRate)
+	assert.Equal(t, time.Second, config.FaultInjection.Gossip.Delay.Duration)
+	if faultInjectionEnabled {
+		assert.NoError(t, config.Validate())
+	} else {
+		assert.Error(t, config.Validate())
+	}
+
+	config.FaultInjection.SignatureResponses.CorruptRate = 2
+	assert.Error(t, config.Validate())
+}
+
+func TestAPITimeoutsConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	assert.NoError(t, json.Unmarshal([]byte(`{"api-call-timeout": "2s", "api-trace-timeout": "20s", "api-logs-timeout": "10s"}`), &config))
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, 2*time.Second, config.APICallTimeout.Duration)
+	assert.Equal(t, 20*time.Second, config.APITraceTimeout.Duration)
+	assert.Equal(t, 10*time.Second, config.APILogsTimeout.Duration)
+
+	config.APILogsTimeout.Duration = -time.Second
+	assert.Error(t, config.Validate())
+
+	config.APILogsTimeout.Duration = 0
+	config.APIMaxDuration.Duration = 5 * time.Second
+	assert.Error(t, config.Validate())
+}
+
+func TestBootstrapFetchConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, 1, config.StateSyncBlockRequestConcurrency)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"state-sync-block-request-size": 64, "state-sync-block-request-concurrency": 4}`), &config))
+	assert.NoError(t, config.Validate())
+	assert.EqualValues(t, 64, config.StateSyncBlockRequestSize)
+	assert.Equal(t, 4, config.StateSyncBlockRequestConcurrency)
+
+	config.StateSyncBlockRequestConcurrency = 0
+	assert.Error(t, config.Validate())
+	config.StateSyncBlockRequestConcurrency = 4
+
+	config.StateSyncBlockRequestSize = 65
+	assert.Error(t, config.Validate())
+
+	config.StateSyncBlockRequestSize = 0
+	assert.Error(t, config.Validate())
+}
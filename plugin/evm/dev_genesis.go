@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ava-labs/subnet-evm/x/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// DevAdminAddress is the well-known address that administers every precompile in the
+	// dev genesis. Its private key is [DevAdminKey], so it must never be used outside of
+	// local development.
+	DevAdminAddress = common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	// DevAdminKey is the hex encoded private key of [DevAdminAddress].
+	DevAdminKey = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+
+	// DevChainID is the chain ID of the dev genesis.
+	DevChainID = big.NewInt(99999)
+
+	// devAdminBalance is the genesis balance of [DevAdminAddress].
+	devAdminBalance = new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(params.Ether))
+
+	errDevGenesisOnPublicNetwork = errors.New("dev genesis cannot be used on mainnet or fuji")
+)
+
+// NewDevGenesis returns a genesis for local contract development, which activates the warp,
+// native minter, fee manager, tx allow list and contract deployer allow list precompiles at
+// genesis with [DevAdminAddress] as the admin of each and funds [DevAdminAddress].
+func NewDevGenesis() *core.Genesis {
+	var (
+		genesisTimestamp = utils.NewUint64(0)
+		admins           = []common.Address{DevAdminAddress}
+	)
+	config := *params.SubnetEVMDefaultChainConfig
+	config.ChainID = DevChainID
+	config.GenesisPrecompiles = params.Precompiles{
+		warp.ConfigKey:              warp.NewDefaultConfig(genesisTimestamp),
+		nativeminter.ConfigKey:      nativeminter.NewConfig(genesisTimestamp, admins, nil, nil, nil),
+		feemanager.ConfigKey:        feemanager.NewConfig(genesisTimestamp, admins, nil, nil, nil),
+		txallowlist.ConfigKey:       txallowlist.NewConfig(genesisTimestamp, admins, nil, nil),
+		deployerallowlist.ConfigKey: deployerallowlist.NewConfig(genesisTimestamp, admins, nil, nil),
+	}
+
+	return &core.Genesis{
+		Config:     &config,
+		GasLimit:   config.FeeConfig.GasLimit.Uint64(),
+		Difficulty: big.NewInt(0),
+		Alloc: core.GenesisAlloc{
+			DevAdminAddress: {Balance: devAdminBalance},
+		},
+	}
+}
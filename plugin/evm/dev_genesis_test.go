@@ -0,0 +1,45 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/x/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevGenesis(t *testing.T) {
+	require := require.New(t)
+
+	// The genesis passed to the VM is ignored in favor of the dev genesis.
+	_, vm, _, _ := GenesisVM(t, true, "", `{"dev-genesis": true}`, "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+
+	require.Zero(DevChainID.Cmp(vm.chainConfig.ChainID))
+	require.True(vm.chainConfig.IsPrecompileEnabled(warp.ContractAddress, 0))
+
+	genesisState, err := vm.blockChain.StateAt(vm.blockChain.Genesis().Root())
+	require.NoError(err)
+	require.Zero(devAdminBalance.Cmp(genesisState.GetBalance(DevAdminAddress)))
+
+	for _, precompileAddr := range []common.Address{
+		nativeminter.ContractAddress,
+		feemanager.ContractAddress,
+		txallowlist.ContractAddress,
+		deployerallowlist.ContractAddress,
+	} {
+		require.True(vm.chainConfig.IsPrecompileEnabled(precompileAddr, 0), "precompile %s not enabled", precompileAddr)
+		require.Equal(allowlist.AdminRole, allowlist.GetAllowListStatus(genesisState, precompileAddr, DevAdminAddress), "admin role of precompile %s", precompileAddr)
+	}
+}
@@ -0,0 +1,279 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/internal/era"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	errImportAfterBootstrap = errors.New("blocks can only be imported before the chain has bootstrapped")
+	errMissingTrustedHash   = errors.New("trusted hash must be specified")
+	errTrustedHashNotFound  = errors.New("trusted hash not found in archives")
+
+	errExternalBlockPredicates = errors.New("cannot verify the predicates of a block obtained outside of consensus")
+)
+
+// exportEra writes the accepted blocks in [first, last] and their receipts to archives
+// in [dir]. Each archive holds the blocks of a single epoch of era.MaxSize blocks.
+// Returns the paths of the written archives.
+func (vm *VM) exportEra(first, last uint64, dir string) ([]string, error) {
+	if first > last {
+		return nil, fmt.Errorf("first (%d) is greater than last (%d)", first, last)
+	}
+	if lastAccepted := vm.blockChain.LastAcceptedBlock().NumberU64(); last > lastAccepted {
+		return nil, fmt.Errorf("last (%d) is greater than the last accepted block (%d)", last, lastAccepted)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for start := first; start <= last; {
+		epoch := start / era.MaxSize
+		end := (epoch+1)*era.MaxSize - 1
+		if end > last {
+			end = last
+		}
+		path, err := vm.exportEpoch(epoch, start, end, dir)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+		start = end + 1
+	}
+	return paths, nil
+}
+
+// exportEpoch writes the blocks in [start, end] of [epoch] to an archive in [dir].
+func (vm *VM) exportEpoch(epoch, start, end uint64, dir string) (string, error) {
+	f, err := os.CreateTemp(dir, "*.era1.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	builder := era.NewBuilder(f)
+	for number := start; number <= end; number++ {
+		block := vm.blockChain.GetBlockByNumber(number)
+		if block == nil {
+			return "", fmt.Errorf("block %d not found", number)
+		}
+		receipts := vm.blockChain.GetReceiptsByHash(block.Hash())
+		if receipts == nil && len(block.Transactions()) > 0 {
+			return "", fmt.Errorf("receipts of block %d not found", number)
+		}
+		if err := builder.Add(block, receipts); err != nil {
+			return "", fmt.Errorf("failed to add block %d: %w", number, err)
+		}
+	}
+	accumulator, err := builder.Finalize()
+	if err != nil {
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, era.Filename(vm.chainConfig.ChainID.String(), epoch, accumulator))
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+	log.Info("Exported blocks to archive", "start", start, "end", end, "path", path, "accumulator", accumulator)
+	return path, nil
+}
+
+// importEra verifies the archives in [dir] and processes the blocks they contain on
+// top of the last accepted block, as if they were accepted by consensus, up to and
+// including the block with [trustedHash]. Blocks at or below the last accepted block
+// must match the accepted chain. Returns the number of blocks accepted.
+//
+// The accumulator of an archive only shows that the archive is intact, not that the
+// network accepted its blocks, so [trustedHash] must be obtained from a trusted source
+// such as the validators. Since each block commits to its parent, the blocks before it
+// are checked to form a chain ending at [trustedHash] before any of them is accepted.
+//
+// Importing is only permitted before the chain has bootstrapped, so that a new node
+// can be brought up to date from archives instead of fetching every block from the
+// validator set. Each block is accepted while holding the context lock.
+func (vm *VM) importEra(ctx context.Context, dir string, trustedHash common.Hash) (uint64, error) {
+	vm.ctx.Lock.Lock()
+	bootstrapped := vm.bootstrapped
+	vm.ctx.Lock.Unlock()
+	if bootstrapped {
+		return 0, errImportAfterBootstrap
+	}
+	if trustedHash == (common.Hash{}) {
+		return 0, errMissingTrustedHash
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.era1"))
+	if err != nil {
+		return 0, err
+	}
+	// Archive names begin with the zero padded epoch, so sorting orders them by height.
+	sort.Strings(paths)
+
+	trustedNumber, err := verifyEraChain(paths, trustedHash)
+	if err != nil {
+		return 0, err
+	}
+
+	var imported uint64
+	for _, path := range paths {
+		n, done, err := vm.importArchive(ctx, path, trustedNumber)
+		imported += n
+		if err != nil {
+			return imported, fmt.Errorf("failed to import %s: %w", path, err)
+		}
+		if done {
+			break
+		}
+	}
+	return imported, nil
+}
+
+// verifyEraChain checks that the blocks of the archives at [paths] form a chain up to
+// the block with [trustedHash], and returns the number of that block.
+func verifyEraChain(paths []string, trustedHash common.Hash) (uint64, error) {
+	var parent *types.Block
+	for _, path := range paths {
+		number, found, err := verifyArchiveChain(path, trustedHash, &parent)
+		if err != nil {
+			return 0, fmt.Errorf("failed to verify %s: %w", path, err)
+		}
+		if found {
+			return number, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", errTrustedHashNotFound, trustedHash)
+}
+
+// verifyArchiveChain checks that the blocks of the archive at [path] extend [parent],
+// which is updated to the last block checked, until the block with [trustedHash].
+// Returns the number of that block and true if it is in the archive.
+func verifyArchiveChain(path string, trustedHash common.Hash, parent **types.Block) (uint64, bool, error) {
+	archive, err := era.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer archive.Close()
+
+	if _, err := archive.Verify(); err != nil {
+		return 0, false, err
+	}
+	for number := archive.Start(); number < archive.Start()+archive.Count(); number++ {
+		ethBlock, err := archive.GetBlockByNumber(number)
+		if err != nil {
+			return 0, false, err
+		}
+		if *parent != nil && ethBlock.ParentHash() != (*parent).Hash() {
+			return 0, false, fmt.Errorf("block %d (%s) does not extend block %d (%s)", number, ethBlock.Hash(), (*parent).NumberU64(), (*parent).Hash())
+		}
+		if ethBlock.Hash() == trustedHash {
+			return number, true, nil
+		}
+		*parent = ethBlock
+	}
+	return 0, false, nil
+}
+
+// importArchive accepts the blocks of the archive at [path] up to [lastNumber]. Returns
+// the number of blocks accepted and true if [lastNumber] was reached.
+func (vm *VM) importArchive(ctx context.Context, path string, lastNumber uint64) (uint64, bool, error) {
+	archive, err := era.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer archive.Close()
+
+	accumulator, err := archive.Verify()
+	if err != nil {
+		return 0, false, err
+	}
+	log.Info("Importing blocks from archive", "path", path, "start", archive.Start(), "count", archive.Count(), "accumulator", accumulator)
+
+	var imported uint64
+	end := archive.Start() + archive.Count()
+	done := lastNumber < end
+	if done {
+		end = lastNumber + 1
+	}
+	for number := archive.Start(); number < end; number++ {
+		ethBlock, err := archive.GetBlockByNumber(number)
+		if err != nil {
+			return imported, false, err
+		}
+		accepted, err := vm.acceptImportedBlock(ctx, ethBlock)
+		if err != nil {
+			return imported, false, err
+		}
+		if accepted {
+			imported++
+		}
+	}
+	vm.blockChain.DrainAcceptorQueue()
+	return imported, done, nil
+}
+
+// acceptImportedBlock accepts [ethBlock] while holding the context lock, unless the
+// chain has bootstrapped in the meantime.
+func (vm *VM) acceptImportedBlock(ctx context.Context, ethBlock *types.Block) (bool, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if vm.bootstrapped {
+		return false, errImportAfterBootstrap
+	}
+	return vm.acceptExternalBlock(ctx, ethBlock)
+}
+
+// acceptExternalBlock verifies and accepts [ethBlock], which was accepted by the network
+// but obtained outside of consensus, on top of the last accepted block. If [ethBlock]
+// is at or below the last accepted block it must match the accepted chain and is
+// skipped. Returns true if [ethBlock] was accepted.
+//
+// Assumes the context lock is held. As the proposervm block context of [ethBlock] is
+// not known, blocks can only be accepted while predicates are not enforced: before the
+// chain has bootstrapped or in follower mode.
+func (vm *VM) acceptExternalBlock(ctx context.Context, ethBlock *types.Block) (bool, error) {
+	if vm.bootstrapped && !vm.following {
+		return false, errExternalBlockPredicates
+	}
+	number := ethBlock.NumberU64()
+	lastAccepted := vm.blockChain.LastAcceptedBlock()
+	if number <= lastAccepted.NumberU64() {
+		if canonical := vm.blockChain.GetCanonicalHash(number); canonical != ethBlock.Hash() {
+			return false, fmt.Errorf("block %d (%s) conflicts with accepted block %s", number, ethBlock.Hash(), canonical)
+		}
+		return false, nil
+	}
+	if ethBlock.ParentHash() != lastAccepted.Hash() {
+		return false, fmt.Errorf("block %d does not extend the last accepted block %d (%s)", number, lastAccepted.NumberU64(), lastAccepted.Hash())
+	}
+
+	blk := vm.newBlock(ethBlock)
+	if err := blk.verify(&precompileconfig.PredicateContext{SnowCtx: vm.ctx}, true); err != nil {
+		return false, fmt.Errorf("failed to verify block %d: %w", number, err)
+	}
+	if err := blk.Accept(ctx); err != nil {
+		return false, fmt.Errorf("failed to accept block %d: %w", number, err)
+	}
+	if err := vm.State.SetLastAcceptedBlock(blk); err != nil {
+		return false, err
+	}
+	return true, nil
+}
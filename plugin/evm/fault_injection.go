@@ -0,0 +1,212 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	_ message.RequestHandler = (*faultyRequestHandler)(nil)
+	_ message.GossipHandler  = (*faultyGossipHandler)(nil)
+	_ p2p.Handler            = (*faultyP2PHandler)(nil)
+)
+
+// FaultInjectionConfig configures the faults injected into the messages handled by a node.
+type FaultInjectionConfig struct {
+	// SignatureResponses are the faults injected into the responses to warp signature requests
+	SignatureResponses FaultConfig `json:"signature-responses"`
+	// Gossip are the faults injected into the received transaction gossip, and the
+	// responses to transaction pull gossip requests
+	Gossip FaultConfig `json:"gossip"`
+}
+
+// FaultConfig configures the faults injected into a kind of message.
+type FaultConfig struct {
+	// DropRate is the fraction of messages that are dropped
+	DropRate float64 `json:"drop-rate"`
+	// CorruptRate is the fraction of messages that are corrupted, if not dropped
+	CorruptRate float64 `json:"corrupt-rate"`
+	// Delay is added before handling each message that is not dropped
+	Delay Duration `json:"delay"`
+}
+
+// Verify returns an error if the config is invalid
+func (c *FaultInjectionConfig) Verify() error {
+	if err := c.SignatureResponses.Verify(); err != nil {
+		return fmt.Errorf("signature responses: %w", err)
+	}
+	if err := c.Gossip.Verify(); err != nil {
+		return fmt.Errorf("gossip: %w", err)
+	}
+	return nil
+}
+
+// Verify returns an error if the config is invalid
+func (c FaultConfig) Verify() error {
+	if c.DropRate < 0 || c.DropRate > 1 {
+		return fmt.Errorf("drop rate must be between 0 and 1 (got %f)", c.DropRate)
+	}
+	if c.CorruptRate < 0 || c.CorruptRate > 1 {
+		return fmt.Errorf("corrupt rate must be between 0 and 1 (got %f)", c.CorruptRate)
+	}
+	if c.Delay.Duration < 0 {
+		return fmt.Errorf("delay must be non-negative (got %s)", c.Delay.Duration)
+	}
+	return nil
+}
+
+// enabled returns true if the config injects any fault
+func (c FaultConfig) enabled() bool {
+	return c.DropRate > 0 || c.CorruptRate > 0 || c.Delay.Duration > 0
+}
+
+func (c FaultConfig) drop() bool {
+	return c.DropRate > 0 && rand.Float64() < c.DropRate
+}
+
+func (c FaultConfig) corrupt() bool {
+	return c.CorruptRate > 0 && rand.Float64() < c.CorruptRate
+}
+
+// delay blocks for the configured delay, or until [ctx] is done
+func (c FaultConfig) delay(ctx context.Context) {
+	if c.Delay.Duration <= 0 {
+		return
+	}
+	timer := time.NewTimer(c.Delay.Duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// corruptBytes returns a copy of [b] with one byte flipped
+func corruptBytes(b []byte) []byte {
+	corrupted := make([]byte, len(b))
+	copy(corrupted, b)
+	if len(corrupted) > 0 {
+		corrupted[rand.Intn(len(corrupted))] ^= 0xff
+	}
+	return corrupted
+}
+
+// faultyRequestHandler injects faults into the responses to warp signature requests.
+type faultyRequestHandler struct {
+	message.RequestHandler
+	config FaultConfig
+	codec  codec.Manager
+}
+
+func newFaultyRequestHandler(handler message.RequestHandler, config FaultConfig, codec codec.Manager) message.RequestHandler {
+	return &faultyRequestHandler{
+		RequestHandler: handler,
+		config:         config,
+		codec:          codec,
+	}
+}
+
+// HandleSignatureRequest drops the response, or corrupts the signature it holds, so
+// that the requester receives an invalid signature rather than an unparsable response.
+func (f *faultyRequestHandler) HandleSignatureRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, signatureRequest message.SignatureRequest) ([]byte, error) {
+	if f.config.drop() {
+		log.Debug("fault injection: dropping signature request", "nodeID", nodeID, "requestID", requestID, "messageID", signatureRequest.MessageID)
+		return nil, nil
+	}
+	f.config.delay(ctx)
+	responseBytes, err := f.RequestHandler.HandleSignatureRequest(ctx, nodeID, requestID, signatureRequest)
+	if err != nil || responseBytes == nil || !f.config.corrupt() {
+		return responseBytes, err
+	}
+
+	var response message.SignatureResponse
+	if _, err := f.codec.Unmarshal(responseBytes, &response); err != nil {
+		return responseBytes, nil
+	}
+	copy(response.Signature[:], corruptBytes(response.Signature[:]))
+	corruptedBytes, err := f.codec.Marshal(message.Version, &response)
+	if err != nil {
+		return responseBytes, nil
+	}
+	log.Debug("fault injection: corrupting signature response", "nodeID", nodeID, "requestID", requestID, "messageID", signatureRequest.MessageID)
+	return corruptedBytes, nil
+}
+
+// faultyGossipHandler injects faults into received transaction gossip.
+type faultyGossipHandler struct {
+	handler message.GossipHandler
+	config  FaultConfig
+}
+
+func newFaultyGossipHandler(handler message.GossipHandler, config FaultConfig) message.GossipHandler {
+	return &faultyGossipHandler{
+		handler: handler,
+		config:  config,
+	}
+}
+
+func (f *faultyGossipHandler) HandleTxs(nodeID ids.NodeID, msg message.TxsGossip) error {
+	if f.config.drop() {
+		log.Debug("fault injection: dropping txs gossip", "nodeID", nodeID)
+		return nil
+	}
+	f.config.delay(context.Background())
+	if f.config.corrupt() {
+		log.Debug("fault injection: corrupting txs gossip", "nodeID", nodeID)
+		msg.Txs = corruptBytes(msg.Txs)
+	}
+	return f.handler.HandleTxs(nodeID, msg)
+}
+
+// faultyP2PHandler injects faults into the gossip and requests handled by a p2p.Handler.
+type faultyP2PHandler struct {
+	p2p.Handler
+	config FaultConfig
+}
+
+func newFaultyP2PHandler(handler p2p.Handler, config FaultConfig) p2p.Handler {
+	return &faultyP2PHandler{
+		Handler: handler,
+		config:  config,
+	}
+}
+
+func (f *faultyP2PHandler) AppGossip(ctx context.Context, nodeID ids.NodeID, gossipBytes []byte) error {
+	if f.config.drop() {
+		log.Debug("fault injection: dropping app gossip", "nodeID", nodeID)
+		return nil
+	}
+	f.config.delay(ctx)
+	if f.config.corrupt() {
+		log.Debug("fault injection: corrupting app gossip", "nodeID", nodeID)
+		gossipBytes = corruptBytes(gossipBytes)
+	}
+	return f.Handler.AppGossip(ctx, nodeID, gossipBytes)
+}
+
+func (f *faultyP2PHandler) AppRequest(ctx context.Context, nodeID ids.NodeID, deadline time.Time, requestBytes []byte) ([]byte, error) {
+	if f.config.drop() {
+		log.Debug("fault injection: dropping app request", "nodeID", nodeID)
+		return nil, nil
+	}
+	delayCtx, cancel := context.WithDeadline(ctx, deadline)
+	f.config.delay(delayCtx)
+	cancel()
+	responseBytes, err := f.Handler.AppRequest(ctx, nodeID, deadline, requestBytes)
+	if err != nil || !f.config.corrupt() {
+		return responseBytes, err
+	}
+	log.Debug("fault injection: corrupting app response", "nodeID", nodeID)
+	return corruptBytes(responseBytes), nil
+}
@@ -0,0 +1,10 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !faultinjection
+// +build !faultinjection
+
+package evm
+
+// faultInjectionEnabled rejects the fault-injection config in production builds.
+const faultInjectionEnabled = false
@@ -0,0 +1,78 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/stretchr/testify/require"
+)
+
+type testSignatureRequestHandler struct {
+	message.RequestHandler
+	response []byte
+}
+
+func (t *testSignatureRequestHandler) HandleSignatureRequest(context.Context, ids.NodeID, uint32, message.SignatureRequest) ([]byte, error) {
+	return t.response, nil
+}
+
+type testGossipHandler struct {
+	received []message.TxsGossip
+}
+
+func (t *testGossipHandler) HandleTxs(_ ids.NodeID, msg message.TxsGossip) error {
+	t.received = append(t.received, msg)
+	return nil
+}
+
+func TestFaultySignatureResponses(t *testing.T) {
+	require := require.New(t)
+
+	var signature [bls.SignatureLen]byte
+	signature[0] = 1
+	responseBytes, err := message.Codec.Marshal(message.Version, &message.SignatureResponse{Signature: signature})
+	require.NoError(err)
+	handler := &testSignatureRequestHandler{response: responseBytes}
+
+	// Without faults, the response is forwarded unchanged
+	faulty := newFaultyRequestHandler(handler, FaultConfig{}, message.Codec)
+	response, err := faulty.HandleSignatureRequest(context.Background(), ids.GenerateTestNodeID(), 1, message.SignatureRequest{})
+	require.NoError(err)
+	require.Equal(responseBytes, response)
+
+	// Dropped requests are not responded to
+	faulty = newFaultyRequestHandler(handler, FaultConfig{DropRate: 1}, message.Codec)
+	response, err = faulty.HandleSignatureRequest(context.Background(), ids.GenerateTestNodeID(), 1, message.SignatureRequest{})
+	require.NoError(err)
+	require.Nil(response)
+
+	// Corrupted responses hold a different signature
+	faulty = newFaultyRequestHandler(handler, FaultConfig{CorruptRate: 1}, message.Codec)
+	response, err = faulty.HandleSignatureRequest(context.Background(), ids.GenerateTestNodeID(), 1, message.SignatureRequest{})
+	require.NoError(err)
+	var corrupted message.SignatureResponse
+	_, err = message.Codec.Unmarshal(response, &corrupted)
+	require.NoError(err)
+	require.NotEqual(signature, corrupted.Signature)
+}
+
+func TestFaultyGossip(t *testing.T) {
+	require := require.New(t)
+
+	msg := message.TxsGossip{Txs: []byte{1, 2, 3}}
+	handler := &testGossipHandler{}
+
+	require.NoError(newFaultyGossipHandler(handler, FaultConfig{DropRate: 1}).HandleTxs(ids.GenerateTestNodeID(), msg))
+	require.Empty(handler.received)
+
+	require.NoError(newFaultyGossipHandler(handler, FaultConfig{CorruptRate: 1}).HandleTxs(ids.GenerateTestNodeID(), msg))
+	require.Len(handler.received, 1)
+	require.NotEqual(msg.Txs, handler.received[0].Txs)
+	require.Equal([]byte{1, 2, 3}, msg.Txs)
+}
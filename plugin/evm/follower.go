@@ -0,0 +1,96 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var errFollowerMode = errors.New("blocks from consensus are not processed in follower mode")
+
+// startFollower connects to the node at [config.FollowerRPCURL] and starts tracking
+// its accepted chain. Once following, blocks from consensus are rejected and the VM
+// only serves API traffic.
+func (vm *VM) startFollower() error {
+	ctx, cancel := context.WithCancel(context.TODO())
+	c, err := rpc.DialContext(ctx, vm.config.FollowerRPCURL)
+	if err != nil {
+		cancel()
+		return err
+	}
+	vm.cancel = cancel
+	vm.following = true
+
+	log.Info("Following accepted chain", "url", vm.config.FollowerRPCURL, "pollInterval", vm.config.FollowerPollInterval.Duration)
+	// Note: the follower is not tracked by [shutdownWg] as it acquires the context lock,
+	// which is held by the engine while calling Shutdown.
+	go vm.follow(ctx, c)
+	return nil
+}
+
+// follow polls [c] for newly accepted blocks until [ctx] is cancelled.
+func (vm *VM) follow(ctx context.Context, c *rpc.Client) {
+	defer c.Close()
+
+	source := ethclient.NewClient(c)
+	ticker := time.NewTicker(vm.config.FollowerPollInterval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := vm.followAccepted(ctx, c, source); err != nil && ctx.Err() == nil {
+			log.Warn("Failed to follow accepted chain", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// followAccepted accepts the blocks between the local last accepted block and the last
+// accepted block of [source].
+func (vm *VM) followAccepted(ctx context.Context, c *rpc.Client, source ethclient.Client) error {
+	var head *types.Header
+	if err := c.CallContext(ctx, &head, "eth_getBlockByNumber", "accepted", false); err != nil {
+		return fmt.Errorf("failed to fetch accepted head: %w", err)
+	}
+	if head == nil {
+		return errors.New("accepted head not found")
+	}
+
+	target := head.Number.Uint64()
+	for number := vm.blockChain.LastAcceptedBlock().NumberU64() + 1; number <= target; number++ {
+		ethBlock, err := source.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return fmt.Errorf("failed to fetch block %d: %w", number, err)
+		}
+		if err := vm.acceptFollowedBlock(ctx, ethBlock); err != nil {
+			return err
+		}
+		log.Debug("Accepted followed block", "number", number, "hash", ethBlock.Hash())
+	}
+	return nil
+}
+
+// acceptFollowedBlock accepts [ethBlock] while holding the context lock, unless the VM
+// is shutting down.
+func (vm *VM) acceptFollowedBlock(ctx context.Context, ethBlock *types.Block) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err := vm.acceptExternalBlock(ctx, ethBlock)
+	return err
+}
@@ -0,0 +1,220 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// gasAnalyticsEventsBuffer is the number of accepted blocks buffered for the
+	// gas analytics before the acceptor blocks on it.
+	gasAnalyticsEventsBuffer = 32
+	// defaultContractGasUsageLimit is the number of contracts returned by
+	// [AnalyticsAPI.ContractGasUsage] if no limit is given.
+	defaultContractGasUsageLimit = 20
+)
+
+var errGasAnalyticsDisabled = errors.New("contract gas analytics are disabled")
+
+// contractGasUsage is the gas used by the transactions calling a contract.
+type contractGasUsage struct {
+	gas uint64
+	txs uint64
+}
+
+// gasAnalytics aggregates the gas used by the transactions calling each
+// contract over a rolling window of accepted blocks.
+//
+// Gas is attributed to the account a transaction calls, or to the contract it
+// creates. Transactions without calldata are transfers and are not attributed.
+type gasAnalytics struct {
+	lock sync.RWMutex
+
+	window int                                   // number of blocks aggregated
+	blocks []map[common.Address]contractGasUsage // usage of each block in the window, oldest at [next]
+	next   int
+
+	totals    map[common.Address]*contractGasUsage
+	totalGas  uint64
+	size      int    // number of blocks in the window
+	lastBlock uint64 // last block in the window
+}
+
+func newGasAnalytics(window int) *gasAnalytics {
+	return &gasAnalytics{
+		window: window,
+		blocks: make([]map[common.Address]contractGasUsage, window),
+		totals: make(map[common.Address]*contractGasUsage),
+	}
+}
+
+// add aggregates the gas used by the transactions of [block] with [receipts],
+// evicting the oldest block from the window if it is full. Transactions without
+// a receipt are not attributed, but the block still takes its place in the window.
+func (g *gasAnalytics) add(block *types.Block, receipts types.Receipts) {
+	usage := make(map[common.Address]contractGasUsage)
+	for i, tx := range block.Transactions() {
+		if i >= len(receipts) {
+			break
+		}
+		var contract common.Address
+		switch {
+		case tx.To() == nil:
+			contract = receipts[i].ContractAddress
+		case len(tx.Data()) > 0:
+			contract = *tx.To()
+		default:
+			continue
+		}
+		contractUsage := usage[contract]
+		contractUsage.gas += receipts[i].GasUsed
+		contractUsage.txs++
+		usage[contract] = contractUsage
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.evict(g.blocks[g.next])
+	g.blocks[g.next] = usage
+	g.next = (g.next + 1) % g.window
+	for contract, contractUsage := range usage {
+		total, ok := g.totals[contract]
+		if !ok {
+			total = &contractGasUsage{}
+			g.totals[contract] = total
+		}
+		total.gas += contractUsage.gas
+		total.txs += contractUsage.txs
+		g.totalGas += contractUsage.gas
+	}
+
+	g.lastBlock = block.NumberU64()
+	if g.size < g.window {
+		g.size++
+	}
+}
+
+// evict removes [usage] from the totals, assumes the lock is held.
+func (g *gasAnalytics) evict(usage map[common.Address]contractGasUsage) {
+	for contract, contractUsage := range usage {
+		total := g.totals[contract]
+		total.gas -= contractUsage.gas
+		total.txs -= contractUsage.txs
+		g.totalGas -= contractUsage.gas
+		if total.txs == 0 {
+			delete(g.totals, contract)
+		}
+	}
+}
+
+// ContractGasUsage is the gas used by the transactions calling a contract over
+// the blocks of a [ContractGasUsageReply].
+type ContractGasUsage struct {
+	Address common.Address `json:"address"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	TxCount hexutil.Uint64 `json:"txCount"`
+	// Share is the fraction of the gas used by the attributed transactions of
+	// the window that was used calling the contract.
+	Share float64 `json:"share"`
+}
+
+// ContractGasUsageReply describes the contracts that used the most gas over a
+// range of accepted blocks.
+type ContractGasUsageReply struct {
+	FromBlock hexutil.Uint64     `json:"fromBlock"`
+	ToBlock   hexutil.Uint64     `json:"toBlock"`
+	GasUsed   hexutil.Uint64     `json:"gasUsed"`
+	Contracts []ContractGasUsage `json:"contracts"`
+}
+
+// top returns the [limit] contracts that used the most gas in the window.
+func (g *gasAnalytics) top(limit int) *ContractGasUsageReply {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	contracts := make([]ContractGasUsage, 0, len(g.totals))
+	for contract, total := range g.totals {
+		usage := ContractGasUsage{
+			Address: contract,
+			GasUsed: hexutil.Uint64(total.gas),
+			TxCount: hexutil.Uint64(total.txs),
+		}
+		if g.totalGas > 0 {
+			usage.Share = float64(total.gas) / float64(g.totalGas)
+		}
+		contracts = append(contracts, usage)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		if contracts[i].GasUsed != contracts[j].GasUsed {
+			return contracts[i].GasUsed > contracts[j].GasUsed
+		}
+		return bytes.Compare(contracts[i].Address[:], contracts[j].Address[:]) < 0
+	})
+	if len(contracts) > limit {
+		contracts = contracts[:limit]
+	}
+	reply := &ContractGasUsageReply{
+		ToBlock:   hexutil.Uint64(g.lastBlock),
+		GasUsed:   hexutil.Uint64(g.totalGas),
+		Contracts: contracts,
+	}
+	if g.size > 0 {
+		reply.FromBlock = hexutil.Uint64(g.lastBlock + 1 - uint64(g.size))
+	}
+	return reply
+}
+
+// trackContractGas aggregates the gas used by the contracts called in each
+// accepted block until the VM shuts down.
+func (vm *VM) trackContractGas() {
+	defer vm.shutdownWg.Done()
+
+	accepted := make(chan core.ChainEvent, gasAnalyticsEventsBuffer)
+	sub := vm.blockChain.SubscribeChainAcceptedEvent(accepted)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case ev := <-accepted:
+			receipts := vm.blockChain.GetReceiptsByHash(ev.Hash)
+			if receipts == nil && len(ev.Block.Transactions()) > 0 {
+				// The block is still added without its gas, so that the window keeps
+				// covering a contiguous range of blocks.
+				log.Warn("Missing receipts of accepted block for gas analytics", "hash", ev.Hash, "number", ev.Block.NumberU64())
+			}
+			vm.gasAnalytics.add(ev.Block, receipts)
+		case <-sub.Err():
+			return
+		case <-vm.shutdownChan:
+			return
+		}
+	}
+}
+
+// AnalyticsAPI reports how the block space of the chain is used.
+type AnalyticsAPI struct{ vm *VM }
+
+// ContractGasUsage returns the [limit] contracts (20 by default) whose
+// transactions used the most gas over the rolling window of accepted blocks.
+func (api *AnalyticsAPI) ContractGasUsage(_ context.Context, limit *int) (*ContractGasUsageReply, error) {
+	if api.vm.gasAnalytics == nil {
+		return nil, errGasAnalyticsDisabled
+	}
+	n := defaultContractGasUsageLimit
+	if limit != nil && *limit > 0 {
+		n = *limit
+	}
+	return api.vm.gasAnalytics.top(n), nil
+}
@@ -0,0 +1,75 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasAnalytics(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		contractA = common.Address{0xa}
+		contractB = common.Address{0xb}
+		created   = common.Address{0xc}
+		eoa       = common.Address{0xe}
+	)
+	call := func(to common.Address, data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: &to, Data: data})
+	}
+	newBlock := func(number int64, txs []*types.Transaction, receipts types.Receipts) (*types.Block, types.Receipts) {
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number)}).WithBody(txs, nil), receipts
+	}
+
+	g := newGasAnalytics(2)
+	g.add(newBlock(1,
+		[]*types.Transaction{call(contractA, []byte{1}), call(eoa, nil), types.NewTx(&types.LegacyTx{Data: []byte{1}})},
+		types.Receipts{{GasUsed: 100}, {GasUsed: 21_000}, {GasUsed: 300, ContractAddress: created}},
+	))
+	g.add(newBlock(2,
+		[]*types.Transaction{call(contractA, []byte{1}), call(contractB, []byte{1})},
+		types.Receipts{{GasUsed: 250}, {GasUsed: 200}},
+	))
+
+	// Transfers are not attributed, and contracts are sorted by gas used.
+	reply := g.top(10)
+	require.EqualValues(1, reply.FromBlock)
+	require.EqualValues(2, reply.ToBlock)
+	require.EqualValues(850, reply.GasUsed)
+	require.Len(reply.Contracts, 3)
+	require.Equal(contractA, reply.Contracts[0].Address)
+	require.EqualValues(350, reply.Contracts[0].GasUsed)
+	require.EqualValues(2, reply.Contracts[0].TxCount)
+	require.InDelta(350.0/850.0, reply.Contracts[0].Share, 1e-9)
+	require.Equal(created, reply.Contracts[1].Address)
+	require.Equal(contractB, reply.Contracts[2].Address)
+
+	require.Len(g.top(1).Contracts, 1)
+
+	// The first block is evicted once the window is full.
+	g.add(newBlock(3, nil, nil))
+	reply = g.top(10)
+	require.EqualValues(2, reply.FromBlock)
+	require.EqualValues(3, reply.ToBlock)
+	require.EqualValues(450, reply.GasUsed)
+	require.Len(reply.Contracts, 2)
+	require.Equal(contractA, reply.Contracts[0].Address)
+	require.EqualValues(250, reply.Contracts[0].GasUsed)
+	require.EqualValues(1, reply.Contracts[0].TxCount)
+	require.Equal(contractB, reply.Contracts[1].Address)
+
+	// A block without receipts is not attributed but still moves the window.
+	g.add(newBlock(4, []*types.Transaction{call(contractB, []byte{1})}, nil))
+	reply = g.top(10)
+	require.EqualValues(3, reply.FromBlock)
+	require.EqualValues(4, reply.ToBlock)
+	require.Zero(reply.GasUsed)
+	require.Empty(reply.Contracts)
+}
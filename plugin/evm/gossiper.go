@@ -346,6 +346,10 @@ func (n *pushGossiper) gossipTxs(force bool) (int, error) {
 			continue
 		}
 
+		if n.txPool.IsPrivate(txHash) {
+			continue
+		}
+
 		// We check [force] outside of the if statement to avoid an unnecessary
 		// cache lookup.
 		if !force {
@@ -0,0 +1,97 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	errBlockNotAccepted   = errors.New("no accepted block at height")
+	errBlockIDMismatch    = errors.New("block ID does not match block bytes")
+	errBlockFieldMismatch = errors.New("block field does not match block bytes")
+	errTxRootMismatch     = errors.New("transactions do not match the transaction root")
+)
+
+// IndexAPI serves the accepted chain by height in the format used by consensus,
+// so that external systems can verify it independently of the eth namespace.
+type IndexAPI struct{ vm *VM }
+
+// IndexedBlock is an accepted block along with its consensus bytes.
+type IndexedBlock struct {
+	// ID is the ID of the block in consensus, which is the hash of its header.
+	ID       ids.ID `json:"id"`
+	ParentID ids.ID `json:"parentID"`
+	Height   uint64 `json:"height"`
+	// Timestamp is the timestamp in the block header.
+	Timestamp uint64 `json:"timestamp"`
+	// Bytes is the RLP encoding of the block, as gossiped and accepted by consensus.
+	Bytes hexutil.Bytes `json:"bytes"`
+	// AcceptedAt is the unix time at which this node accepted the block, if known.
+	AcceptedAt *uint64 `json:"acceptedAt,omitempty"`
+}
+
+// Verify checks that the bytes of the block encode a block with the ID, parent,
+// height and timestamp of [b], and that its transactions match its header. Together
+// with checking the parent ID of each block against the ID of the previous one,
+// this proves the accepted chain from a trusted block ID without trusting the node.
+func (b *IndexedBlock) Verify() error {
+	ethBlock := new(types.Block)
+	if err := rlp.DecodeBytes(b.Bytes, ethBlock); err != nil {
+		return fmt.Errorf("failed to decode block bytes: %w", err)
+	}
+	if id := ids.ID(ethBlock.Hash()); id != b.ID {
+		return fmt.Errorf("%w: have %s, want %s", errBlockIDMismatch, id, b.ID)
+	}
+	if ids.ID(ethBlock.ParentHash()) != b.ParentID || ethBlock.NumberU64() != b.Height || ethBlock.Time() != b.Timestamp {
+		return fmt.Errorf("%w: block %s", errBlockFieldMismatch, b.ID)
+	}
+	if txRoot := types.DeriveSha(ethBlock.Transactions(), trie.NewStackTrie(nil)); txRoot != ethBlock.TxHash() {
+		return fmt.Errorf("%w: have %s, want %s", errTxRootMismatch, txRoot, ethBlock.TxHash())
+	}
+	return nil
+}
+
+// GetContainerByIndex returns the accepted block at [height].
+func (api *IndexAPI) GetContainerByIndex(ctx context.Context, height uint64) (*IndexedBlock, error) {
+	bc := api.vm.blockChain
+	// Above the last accepted block, the canonical chain may contain blocks that
+	// are only preferred.
+	if height > bc.LastAcceptedBlock().NumberU64() {
+		return nil, fmt.Errorf("%w %d", errBlockNotAccepted, height)
+	}
+	ethBlock := bc.GetBlockByNumber(height)
+	if ethBlock == nil {
+		return nil, fmt.Errorf("%w %d", errBlockNotAccepted, height)
+	}
+	return api.indexedBlock(ethBlock), nil
+}
+
+// GetLastAccepted returns the last accepted block.
+func (api *IndexAPI) GetLastAccepted(ctx context.Context) (*IndexedBlock, error) {
+	return api.indexedBlock(api.vm.blockChain.LastAcceptedBlock()), nil
+}
+
+func (api *IndexAPI) indexedBlock(ethBlock *types.Block) *IndexedBlock {
+	block := api.vm.newBlock(ethBlock)
+	indexed := &IndexedBlock{
+		ID:        block.ID(),
+		ParentID:  block.Parent(),
+		Height:    block.Height(),
+		Timestamp: ethBlock.Time(),
+		Bytes:     block.Bytes(),
+	}
+	if acceptedAt, ok := api.vm.blockChain.GetBlockAcceptedAt(ethBlock.Hash()); ok {
+		indexed.AcceptedAt = &acceptedAt
+	}
+	return indexed
+}
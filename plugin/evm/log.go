@@ -5,9 +5,13 @@ package evm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"path"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -18,8 +22,21 @@ const (
 	timeFormat = "2006-01-02T15:04:05-0700"
 )
 
+// modulePathPrefix prefixes the paths of the source files of subnet-evm packages.
+const modulePathPrefix = "github.com/ava-labs/subnet-evm/"
+
 type SubnetEVMLogger struct {
 	log.Handler
+
+	levels *logLevels
+}
+
+// logLevels holds the log level of the logger and the levels that override it for
+// the packages of modules, such as "warp" or "sync/statesync".
+type logLevels struct {
+	lock    sync.RWMutex
+	root    log.Lvl
+	modules map[string]log.Lvl
 }
 
 // InitLogger initializes logger with alias and sets the log level and format with the original [os.StdErr] interface
@@ -32,11 +49,15 @@ func InitLogger(alias string, level string, jsonFormat bool, writer io.Writer) (
 
 	// Create handler
 	logHandler := log.StreamHandler(writer, logFormat)
-	c := SubnetEVMLogger{Handler: logHandler}
+	c := SubnetEVMLogger{
+		Handler: logHandler,
+		levels:  &logLevels{modules: make(map[string]log.Lvl)},
+	}
 
 	if err := c.SetLogLevel(level); err != nil {
 		return SubnetEVMLogger{}, err
 	}
+	log.Root().SetHandler(log.FuncHandler(c.filter))
 	return c, nil
 }
 
@@ -47,10 +68,82 @@ func (c *SubnetEVMLogger) SetLogLevel(level string) error {
 	if err != nil {
 		return err
 	}
-	log.Root().SetHandler(log.LvlFilterHandler(logLevel, c))
+	c.levels.lock.Lock()
+	defer c.levels.lock.Unlock()
+
+	c.levels.root = logLevel
+	return nil
+}
+
+// SetModuleLogLevel sets the log level of the packages in [module], a path relative to
+// the root of subnet-evm such as "warp" or "sync/statesync". An empty [level] removes
+// the override, so that the module logs at the level of the logger again.
+func (c *SubnetEVMLogger) SetModuleLogLevel(module string, level string) error {
+	module = strings.Trim(module, "/")
+	if module == "" {
+		return errors.New("module must not be empty")
+	}
+	c.levels.lock.Lock()
+	defer c.levels.lock.Unlock()
+
+	if level == "" {
+		delete(c.levels.modules, module)
+		return nil
+	}
+	logLevel, err := log.LvlFromString(level)
+	if err != nil {
+		return err
+	}
+	c.levels.modules[module] = logLevel
 	return nil
 }
 
+// LogLevels returns the log level of the logger and the levels of modules that
+// override it.
+func (c *SubnetEVMLogger) LogLevels() (string, map[string]string) {
+	c.levels.lock.RLock()
+	defer c.levels.lock.RUnlock()
+
+	modules := make(map[string]string, len(c.levels.modules))
+	for module, level := range c.levels.modules {
+		modules[module] = level.String()
+	}
+	return c.levels.root.String(), modules
+}
+
+// filter logs [r] if it is at or above the log level of the module that logged it.
+func (c *SubnetEVMLogger) filter(r *log.Record) error {
+	if r.Lvl <= c.levels.level(r) {
+		return c.Handler.Log(r)
+	}
+	return nil
+}
+
+// level returns the log level of the module of the package that logged [r], which
+// is the root level unless a module containing the package overrides it.
+func (l *logLevels) level(r *log.Record) log.Lvl {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if len(l.modules) == 0 {
+		return l.root
+	}
+	file := fmt.Sprintf("%+v", r.Call)
+	i := strings.Index(file, modulePathPrefix)
+	if i < 0 {
+		return l.root
+	}
+	// Match the longest module containing the package, so that "sync/statesync"
+	// overrides "sync".
+	pkg := path.Dir(file[i+len(modulePathPrefix):])
+	for module := pkg; module != "."; module = path.Dir(module) {
+		if level, ok := l.modules[module]; ok {
+			return level
+		}
+	}
+	return l.root
+}
+
 func SubnetEVMTermFormat(alias string) log.Format {
 	prefix := fmt.Sprintf("<%s Chain>", alias)
 	return log.FormatFunc(func(r *log.Record) []byte {
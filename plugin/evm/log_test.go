@@ -0,0 +1,42 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleLogLevel(t *testing.T) {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	logger, err := InitLogger("test", "info", false, &buf)
+	require.NoError(err)
+	defer log.Root().SetHandler(log.DiscardHandler())
+
+	log.Debug("filtered")
+	require.Empty(buf.String())
+
+	// The test logs from the "plugin/evm" module.
+	require.NoError(logger.SetModuleLogLevel("plugin", "debug"))
+	require.NoError(logger.SetModuleLogLevel("plugin/evm", "trace"))
+	log.Trace("logged")
+	require.Contains(buf.String(), "logged")
+
+	level, modules := logger.LogLevels()
+	require.Equal("info", level)
+	require.Equal(map[string]string{"plugin": "dbug", "plugin/evm": "trce"}, modules)
+
+	buf.Reset()
+	require.NoError(logger.SetModuleLogLevel("plugin/evm", ""))
+	log.Trace("filtered")
+	require.Empty(buf.String())
+
+	require.Error(logger.SetModuleLogLevel("", "debug"))
+	require.Error(logger.SetModuleLogLevel("warp", "verbose"))
+}
@@ -0,0 +1,151 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// memoryPressureRatio is the fraction of the memory ceiling above which the VM
+	// shrinks its caches.
+	memoryPressureRatio = 0.9
+
+	// memoryShrinkCooldown is how long the VM waits before shrinking its caches again
+	// after a shrink that did not bring the resident set size below the pressure
+	// threshold, since shrinking again right away would only repeat the stop-the-world
+	// GC and dirty trie flush without freeing memory.
+	memoryShrinkCooldown = time.Minute
+)
+
+var (
+	rssGauge            = metrics.NewRegisteredGauge("vm/memory/rss", nil)
+	memoryShrinkCounter = metrics.NewRegisteredCounter("vm/memory/shrinks", nil)
+)
+
+// memoryCoordinator shrinks the caches of the VM when the resident set size of the
+// process nears a ceiling, so that traffic spikes slow the node down instead of
+// getting it killed for running out of memory.
+//
+// The clean trie and snapshot caches are not shrunk: they are fastcaches whose
+// memory is allocated outside of the Go heap and kept for reuse when they are reset,
+// so purging them would not lower the resident set size. Their size is bounded by
+// their configured allowance instead, which should leave room below the ceiling.
+type memoryCoordinator struct {
+	ceiling uint64 // bytes
+	shrink  func() error
+	rss     func() (uint64, error)
+	now     func() time.Time
+
+	// cooldownEnd is the time before which the caches are not shrunk again, set when
+	// a shrink did not relieve the memory pressure.
+	cooldownEnd time.Time
+}
+
+func newMemoryCoordinator(ceiling uint64, shrink func() error) *memoryCoordinator {
+	return &memoryCoordinator{
+		ceiling: ceiling,
+		shrink:  shrink,
+		rss:     readRSS,
+		now:     time.Now,
+	}
+}
+
+// underPressure returns whether [rss] is above the pressure threshold.
+func (m *memoryCoordinator) underPressure(rss uint64) bool {
+	return float64(rss) >= memoryPressureRatio*float64(m.ceiling)
+}
+
+// check shrinks the caches if the resident set size is above the pressure
+// threshold, unless a previous shrink did not relieve the pressure less than
+// [memoryShrinkCooldown] ago. Returns whether the caches were shrunk.
+func (m *memoryCoordinator) check() (bool, error) {
+	rss, err := m.rss()
+	if err != nil {
+		return false, fmt.Errorf("failed to read resident set size: %w", err)
+	}
+	rssGauge.Update(int64(rss))
+	if !m.underPressure(rss) {
+		m.cooldownEnd = time.Time{}
+		return false, nil
+	}
+	if m.now().Before(m.cooldownEnd) {
+		return false, nil
+	}
+
+	start := m.now()
+	if err := m.shrink(); err != nil {
+		return false, err
+	}
+	// Return the freed memory to the OS, rather than waiting for the scavenger.
+	debug.FreeOSMemory()
+	memoryShrinkCounter.Inc(1)
+
+	after, err := m.rss()
+	if err == nil && m.underPressure(after) {
+		m.cooldownEnd = m.now().Add(memoryShrinkCooldown)
+		log.Warn("Shrinking caches did not relieve memory pressure", "rss", rss, "rssAfter", after, "ceiling", m.ceiling, "elapsed", m.now().Sub(start), "cooldown", memoryShrinkCooldown)
+		return true, nil
+	}
+	log.Warn("Shrunk caches under memory pressure", "rss", rss, "rssAfter", after, "ceiling", m.ceiling, "elapsed", m.now().Sub(start))
+	return true, nil
+}
+
+// readRSS returns the resident set size of the process. It falls back to the
+// memory obtained by the Go runtime where /proc is not available.
+func readRSS() (uint64, error) {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.Sys, nil
+	}
+	fields := bytes.Fields(statm)
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected /proc/self/statm format: %q", statm)
+	}
+	pages, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse resident pages: %w", err)
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
+
+// monitorMemory checks the memory of the process against [config.MemoryCeiling]
+// every [config.MemoryCheckInterval] until the VM shuts down.
+func (vm *VM) monitorMemory() {
+	defer vm.shutdownWg.Done()
+
+	ceiling := vm.config.MemoryCeiling * units.MiB
+	// Let the garbage collector work harder before the ceiling is reached.
+	debug.SetMemoryLimit(int64(ceiling))
+	coordinator := newMemoryCoordinator(ceiling, vm.blockChain.ShrinkCaches)
+	// The clean trie and snapshot caches are not shrunk under memory pressure.
+	if cleanCaches := uint64(vm.config.TrieCleanCache+vm.config.SnapshotCache) * units.MiB; coordinator.underPressure(cleanCaches) {
+		log.Warn("Clean trie and snapshot caches can exceed the memory pressure threshold", "trieCleanCache", vm.config.TrieCleanCache, "snapshotCache", vm.config.SnapshotCache, "memoryCeiling", vm.config.MemoryCeiling)
+	}
+
+	ticker := time.NewTicker(vm.config.MemoryCheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := coordinator.check(); err != nil {
+				log.Warn("Failed to relieve memory pressure", "err", err)
+			}
+		case <-vm.shutdownChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCoordinator(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		rss     uint64
+		shrinks int
+	)
+	coordinator := newMemoryCoordinator(1000, func() error {
+		shrinks++
+		return nil
+	})
+	coordinator.rss = func() (uint64, error) { return rss, nil }
+
+	rss = 899
+	shrunk, err := coordinator.check()
+	require.NoError(err)
+	require.False(shrunk)
+	require.Zero(shrinks)
+
+	rss = 900
+	shrunk, err = coordinator.check()
+	require.NoError(err)
+	require.True(shrunk)
+	require.Equal(1, shrinks)
+}
+
+func TestMemoryCoordinatorCooldown(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		rss     uint64 = 950
+		freed   uint64
+		shrinks int
+		now     = time.Unix(0, 0)
+	)
+	coordinator := newMemoryCoordinator(1000, func() error {
+		shrinks++
+		rss -= freed
+		return nil
+	})
+	coordinator.rss = func() (uint64, error) { return rss, nil }
+	coordinator.now = func() time.Time { return now }
+
+	// A shrink that does not relieve the pressure is not repeated until the cooldown ends.
+	shrunk, err := coordinator.check()
+	require.NoError(err)
+	require.True(shrunk)
+	now = now.Add(memoryShrinkCooldown - time.Second)
+	shrunk, err = coordinator.check()
+	require.NoError(err)
+	require.False(shrunk)
+	require.Equal(1, shrinks)
+
+	now = now.Add(time.Second)
+	shrunk, err = coordinator.check()
+	require.NoError(err)
+	require.True(shrunk)
+	require.Equal(2, shrinks)
+
+	// Dropping below the threshold ends the cooldown.
+	rss = 500
+	shrunk, err = coordinator.check()
+	require.NoError(err)
+	require.False(shrunk)
+	rss = 950
+	freed = 100
+	shrunk, err = coordinator.check()
+	require.NoError(err)
+	require.True(shrunk)
+	require.Equal(3, shrinks)
+
+	// A shrink that relieves the pressure allows shrinking again right away.
+	rss = 950
+	shrunk, err = coordinator.check()
+	require.NoError(err)
+	require.True(shrunk)
+	require.Equal(4, shrinks)
+}
+
+func TestReadRSS(t *testing.T) {
+	rss, err := readRSS()
+	require.NoError(t, err)
+	require.Positive(t, rss)
+}
@@ -17,6 +17,7 @@ var (
 )
 
 // BlockRequest is a request to retrieve Parents number of blocks starting from Hash from newest-oldest manner
+// If Hash is empty, the blocks start from the canonical block at Height
 type BlockRequest struct {
 	Hash    common.Hash `serialize:"true"`
 	Height  uint64      `serialize:"true"`
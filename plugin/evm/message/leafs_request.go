@@ -11,7 +11,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-const MaxCodeHashesPerRequest = 5
+const (
+	// MaxCodeHashesPerRequest is the number of code hashes per request served by
+	// nodes that predate batched code requests.
+	MaxCodeHashesPerRequest = 5
+	// MaxCodeHashesPerBatchedRequest is the number of code hashes per request served.
+	// Note: nodes that predate batched code requests drop requests for more than
+	// [MaxCodeHashesPerRequest] code hashes.
+	MaxCodeHashesPerBatchedRequest = 32
+)
 
 var _ Request = LeafsRequest{}
 
@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/subnet-evm/warp"
 	warpHandlers "github.com/ava-labs/subnet-evm/warp/handlers"
 	warpStats "github.com/ava-labs/subnet-evm/warp/handlers/stats"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var _ message.RequestHandler = &networkHandler{}
@@ -26,6 +27,7 @@ type networkHandler struct {
 	blockRequestHandler          *syncHandlers.BlockRequestHandler
 	codeRequestHandler           *syncHandlers.CodeRequestHandler
 	signatureRequestHandler      warpHandlers.SignatureRequestHandler
+	syncThrottler                *syncHandlers.RequestThrottler
 }
 
 // newNetworkHandler constructs the handler for serving network requests.
@@ -35,6 +37,7 @@ func newNetworkHandler(
 	evmTrieDB *trie.Database,
 	warpBackend warp.Backend,
 	networkCodec codec.Manager,
+	throttleConfig syncHandlers.ThrottleConfig,
 ) message.RequestHandler {
 	syncStats := syncStats.NewHandlerStats(metrics.Enabled)
 	return &networkHandler{
@@ -42,10 +45,17 @@ func newNetworkHandler(
 		blockRequestHandler:          syncHandlers.NewBlockRequestHandler(provider, networkCodec, syncStats),
 		codeRequestHandler:           syncHandlers.NewCodeRequestHandler(diskDB, networkCodec, syncStats),
 		signatureRequestHandler:      warpHandlers.NewSignatureRequestHandler(warpBackend, networkCodec, warpStats.NewStats()),
+		syncThrottler:                syncHandlers.NewRequestThrottler(throttleConfig),
 	}
 }
 
 func (n networkHandler) HandleTrieLeafsRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, leafsRequest message.LeafsRequest) ([]byte, error) {
+	release, ok := n.syncThrottler.Acquire(nodeID)
+	if !ok {
+		log.Debug("dropping throttled leafs request", "nodeID", nodeID, "requestID", requestID)
+		return nil, nil
+	}
+	defer release()
 	return n.stateTrieLeafsRequestHandler.OnLeafsRequest(ctx, nodeID, requestID, leafsRequest)
 }
 
@@ -54,6 +64,12 @@ func (n networkHandler) HandleBlockRequest(ctx context.Context, nodeID ids.NodeI
 }
 
 func (n networkHandler) HandleCodeRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, codeRequest message.CodeRequest) ([]byte, error) {
+	release, ok := n.syncThrottler.Acquire(nodeID)
+	if !ok {
+		log.Debug("dropping throttled code request", "nodeID", nodeID, "requestID", requestID)
+		return nil, nil
+	}
+	defer release()
 	return n.codeRequestHandler.OnCodeRequest(ctx, nodeID, requestID, codeRequest)
 }
 
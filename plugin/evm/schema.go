@@ -0,0 +1,153 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// migrationLogInterval is the minimum time between progress logs of a migration.
+const migrationLogInterval = 8 * time.Second
+
+var (
+	schemaVersionKey = []byte("schema_version")
+
+	schemaVersionGauge = metrics.NewRegisteredGauge("vm/schema/version", nil)
+)
+
+// schemaMigration migrates the database from schema [version]-1 to [version].
+// Migrations must be idempotent, since a migration interrupted by a shutdown is
+// run again from the start when the node restarts.
+type schemaMigration struct {
+	version uint64
+	name    string
+	migrate func(db ethdb.Database, progress *migrationProgress) error
+}
+
+// schemaMigrations lists the migrations of the database schema in order. Each
+// format change adds a migration with the next version, so that existing
+// databases are migrated in place when the node upgrades.
+var schemaMigrations = []schemaMigration{
+	{
+		// Databases created before schema versions were recorded are already
+		// in the baseline format.
+		version: 1,
+		name:    "baseline",
+		migrate: func(ethdb.Database, *migrationProgress) error { return nil },
+	},
+}
+
+// latestSchemaVersion returns the schema version reached by applying all of
+// [migrations].
+func latestSchemaVersion(migrations []schemaMigration) uint64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].version
+}
+
+// migrationProgress reports the progress of a running migration.
+type migrationProgress struct {
+	name   string
+	start  time.Time
+	logged time.Time
+}
+
+func newMigrationProgress(name string) *migrationProgress {
+	now := time.Now()
+	return &migrationProgress{
+		name:   name,
+		start:  now,
+		logged: now,
+	}
+}
+
+// Report logs that [done] of [total] items have been migrated, at most once per
+// [migrationLogInterval]. [total] may be zero if it is not known up front.
+func (p *migrationProgress) Report(done, total uint64) {
+	if time.Since(p.logged) < migrationLogInterval {
+		return
+	}
+	p.logged = time.Now()
+	ctx := []interface{}{"migration", p.name, "done", done, "elapsed", common.PrettyDuration(time.Since(p.start))}
+	if total > 0 {
+		ctx = append(ctx, "total", total, "progress", fmt.Sprintf("%.2f%%", float64(done)*100/float64(total)))
+	}
+	log.Info("Migrating database", ctx...)
+}
+
+// schemaMigrator applies [migrations] to [chaindb], recording the schema version
+// in [metadataDB] after each migration. [commit] persists the writes made to
+// [metadataDB].
+type schemaMigrator struct {
+	chaindb    ethdb.Database
+	metadataDB database.Database
+	commit     func() error
+	migrations []schemaMigration
+}
+
+// migrate brings the database up to the latest schema version. Databases with
+// no recorded version are assumed to be at version 0, unless [fresh] indicates
+// that they have just been created, in which case they are already in the
+// latest format.
+func (s *schemaMigrator) migrate(fresh bool) error {
+	latest := latestSchemaVersion(s.migrations)
+	version, err := database.GetUInt64(s.metadataDB, schemaVersionKey)
+	switch {
+	case errors.Is(err, database.ErrNotFound) && fresh:
+		if err := s.setVersion(latest); err != nil {
+			return err
+		}
+		log.Info("Initialized database schema", "version", latest)
+		return nil
+	case errors.Is(err, database.ErrNotFound):
+		version = 0
+	case err != nil:
+		return fmt.Errorf("failed to read database schema version: %w", err)
+	}
+	schemaVersionGauge.Update(int64(version))
+
+	if version > latest {
+		return fmt.Errorf("database schema version is v%d, but only up to v%d is supported", version, latest)
+	}
+	if version == latest {
+		return nil
+	}
+
+	log.Info("Migrating database schema", "from", version, "to", latest)
+	for _, migration := range s.migrations {
+		if migration.version <= version {
+			continue
+		}
+		start := time.Now()
+		log.Info("Starting database migration", "version", migration.version, "migration", migration.name)
+		if err := migration.migrate(s.chaindb, newMigrationProgress(migration.name)); err != nil {
+			return fmt.Errorf("failed to migrate database to schema v%d (%s): %w", migration.version, migration.name, err)
+		}
+		if err := s.setVersion(migration.version); err != nil {
+			return err
+		}
+		log.Info("Completed database migration", "version", migration.version, "migration", migration.name, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return nil
+}
+
+func (s *schemaMigrator) setVersion(version uint64) error {
+	if err := database.PutUInt64(s.metadataDB, schemaVersionKey, version); err != nil {
+		return fmt.Errorf("failed to write database schema version: %w", err)
+	}
+	if err := s.commit(); err != nil {
+		return fmt.Errorf("failed to commit database schema version: %w", err)
+	}
+	schemaVersionGauge.Update(int64(version))
+	return nil
+}
@@ -0,0 +1,98 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaMigrator(t *testing.T) {
+	require := require.New(t)
+
+	var applied []uint64
+	newMigration := func(version uint64) schemaMigration {
+		return schemaMigration{
+			version: version,
+			name:    "test",
+			migrate: func(ethdb.Database, *migrationProgress) error {
+				applied = append(applied, version)
+				return nil
+			},
+		}
+	}
+	metadataDB := memdb.New()
+	migrator := &schemaMigrator{
+		chaindb:    Database{memdb.New()},
+		metadataDB: metadataDB,
+		commit:     func() error { return nil },
+		migrations: []schemaMigration{newMigration(1), newMigration(2)},
+	}
+
+	// Databases without a recorded version run every migration.
+	require.NoError(migrator.migrate(false))
+	require.Equal([]uint64{1, 2}, applied)
+	version, err := database.GetUInt64(metadataDB, schemaVersionKey)
+	require.NoError(err)
+	require.Equal(uint64(2), version)
+
+	// Only new migrations run on upgrade.
+	migrator.migrations = append(migrator.migrations, newMigration(3))
+	require.NoError(migrator.migrate(false))
+	require.Equal([]uint64{1, 2, 3}, applied)
+
+	// Downgrades are refused.
+	migrator.migrations = migrator.migrations[:2]
+	require.ErrorContains(migrator.migrate(false), "only up to v2 is supported")
+}
+
+func TestSchemaMigratorFreshDatabase(t *testing.T) {
+	require := require.New(t)
+
+	metadataDB := memdb.New()
+	migrator := &schemaMigrator{
+		chaindb:    Database{memdb.New()},
+		metadataDB: metadataDB,
+		commit:     func() error { return nil },
+		migrations: []schemaMigration{{
+			version: 1,
+			name:    "test",
+			migrate: func(ethdb.Database, *migrationProgress) error {
+				return errors.New("fresh databases must not be migrated")
+			},
+		}},
+	}
+	require.NoError(migrator.migrate(true))
+	version, err := database.GetUInt64(metadataDB, schemaVersionKey)
+	require.NoError(err)
+	require.Equal(uint64(1), version)
+}
+
+func TestSchemaMigratorFailure(t *testing.T) {
+	require := require.New(t)
+
+	errMigration := errors.New("migration failed")
+	metadataDB := memdb.New()
+	migrator := &schemaMigrator{
+		chaindb:    Database{memdb.New()},
+		metadataDB: metadataDB,
+		commit:     func() error { return nil },
+		migrations: []schemaMigration{
+			{version: 1, name: "ok", migrate: func(ethdb.Database, *migrationProgress) error { return nil }},
+			{version: 2, name: "failing", migrate: func(ethdb.Database, *migrationProgress) error { return errMigration }},
+		},
+	}
+	require.ErrorIs(migrator.migrate(false), errMigration)
+
+	// The version of the last completed migration is recorded, so that the
+	// failed migration is retried on restart.
+	version, err := database.GetUInt64(metadataDB, schemaVersionKey)
+	require.NoError(err)
+	require.Equal(uint64(1), version)
+}
@@ -36,6 +36,62 @@ func (api *SnowmanAPI) GetAcceptedFront(ctx context.Context) (*GetAcceptedFrontR
 	}, nil
 }
 
+// AcceptanceStatusReply describes the acceptance of a block, or of the block
+// including a transaction.
+type AcceptanceStatusReply struct {
+	BlockHash   common.Hash `json:"blockHash"`
+	BlockNumber uint64      `json:"blockNumber"`
+	// Accepted is true if the block has been accepted and processed. Accepted
+	// blocks are final and cannot be reverted.
+	Accepted bool `json:"accepted"`
+	// Depth is the number of accepted blocks built on top of the block.
+	Depth uint64 `json:"depth"`
+	// BlockTimestamp is the timestamp in the block header.
+	BlockTimestamp uint64 `json:"blockTimestamp"`
+	// AcceptedAt is the unix time at which this node accepted the block. It is
+	// omitted if unknown, which is the case for blocks accepted before acceptance
+	// times were recorded and for blocks this node did not accept itself, such
+	// as blocks fetched by state sync.
+	AcceptedAt *uint64 `json:"acceptedAt,omitempty"`
+}
+
+// GetAcceptanceStatus returns the acceptance status of the block with [hash],
+// or of the block including the transaction with [hash]. Returns nil if there
+// is no such block, including for transactions that are not yet accepted.
+func (api *SnowmanAPI) GetAcceptanceStatus(ctx context.Context, hash common.Hash) (*AcceptanceStatusReply, error) {
+	bc := api.vm.blockChain
+	// Read the tip before the block, so that blocks accepted in between are
+	// reported as not yet accepted rather than with an inconsistent depth.
+	tip := bc.LastAcceptedBlock()
+
+	header := bc.GetHeaderByHash(hash)
+	if header == nil {
+		lookup := bc.GetTransactionLookup(hash)
+		if lookup == nil {
+			return nil, nil
+		}
+		if header = bc.GetHeaderByHash(lookup.BlockHash); header == nil {
+			return nil, nil
+		}
+	}
+
+	number := header.Number.Uint64()
+	reply := &AcceptanceStatusReply{
+		BlockHash:      header.Hash(),
+		BlockNumber:    number,
+		BlockTimestamp: header.Time,
+	}
+	// Below the accepted tip, the canonical chain only contains accepted blocks.
+	if number <= tip.NumberU64() && bc.GetCanonicalHash(number) == reply.BlockHash {
+		reply.Accepted = true
+		reply.Depth = tip.NumberU64() - number
+		if acceptedAt, ok := bc.GetBlockAcceptedAt(reply.BlockHash); ok {
+			reply.AcceptedAt = &acceptedAt
+		}
+	}
+	return reply, nil
+}
+
 // IssueBlock to the chain
 func (api *SnowmanAPI) IssueBlock(ctx context.Context) error {
 	log.Info("Issuing a new block")
@@ -0,0 +1,93 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	errStateImportAfterBootstrap = errors.New("state can only be imported before the chain has bootstrapped")
+	errStateImportMissingBlock   = errors.New("state export does not include its block")
+)
+
+// importState imports the state export read from [r] and moves the chain to the block of
+// the export, which must have [trustedHash], as if the node had state synced to it: the
+// head, last accepted and snapshot markers are set to the block.
+//
+// Like importEra, importing is only permitted before the chain has bootstrapped, and the
+// exported block must be ahead of the last accepted block. The context lock is held for
+// the whole import, so that it does not run concurrently with the engine.
+func (vm *VM) importState(r io.Reader, trustedHash common.Hash) (*types.Block, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if vm.bootstrapped {
+		return nil, errStateImportAfterBootstrap
+	}
+	if trustedHash == (common.Hash{}) {
+		return nil, errMissingTrustedHash
+	}
+
+	var block *types.Block
+	checkHeader := func(header state.ExportHeader) error {
+		if len(header.Block) == 0 {
+			return errStateImportMissingBlock
+		}
+		block = new(types.Block)
+		if err := rlp.DecodeBytes(header.Block, block); err != nil {
+			return fmt.Errorf("failed to decode block of state export: %w", err)
+		}
+		if block.Hash() != trustedHash {
+			return fmt.Errorf("block of state export %s does not match trusted hash %s", block.Hash(), trustedHash)
+		}
+		if block.Root() != header.Root {
+			return fmt.Errorf("state export root %s does not match the root %s of block %d", header.Root, block.Root(), block.NumberU64())
+		}
+		if lastAccepted := vm.blockChain.LastAcceptedBlock(); block.NumberU64() <= lastAccepted.NumberU64() {
+			return fmt.Errorf("block %d of state export is not ahead of the last accepted block %d", block.NumberU64(), lastAccepted.NumberU64())
+		}
+		return nil
+	}
+	if _, err := state.ImportState(vm.chaindb, r, checkHeader); err != nil {
+		return nil, err
+	}
+
+	batch := vm.chaindb.NewBatch()
+	rawdb.WriteBlock(batch, block)
+	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+
+	// As in state sync, the BloomIndexer cannot index the blocks before the imported block.
+	vm.eth.BloomIndexer().AddCheckpoint((block.NumberU64()-1)/params.BloomBitsBlocks, block.ParentHash())
+	if err := vm.blockChain.ResetToStateSyncedBlock(block); err != nil {
+		return nil, err
+	}
+	if err := vm.acceptedBlockDB.Put(lastAcceptedKey, block.Hash().Bytes()); err != nil {
+		return nil, err
+	}
+	if err := vm.db.Commit(); err != nil {
+		return nil, err
+	}
+	blk := vm.newBlock(block)
+	blk.SetStatus(choices.Accepted)
+	if err := vm.State.SetLastAcceptedBlock(blk); err != nil {
+		return nil, err
+	}
+	log.Info("Imported state", "height", block.NumberU64(), "hash", block.Hash(), "root", block.Root())
+	return block, nil
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -94,3 +95,45 @@ func (ss *StaticService) DecodeGenesis(_ *http.Request, args *DecodeGenesisArgs,
 	reply.Encoding = args.Encoding
 	return nil
 }
+
+// VersionReply is the reply from Version
+type VersionReply struct {
+	VersionInfo
+}
+
+// Version returns the version of the VM and its compatibility with avalanchego.
+func (ss *StaticService) Version(_ *http.Request, _ *struct{}, reply *VersionReply) error {
+	reply.VersionInfo = GetVersionInfo()
+	return nil
+}
+
+// CheckCompatibilityArgs are arguments for CheckCompatibility
+type CheckCompatibilityArgs struct {
+	RPCChainVMProtocol uint     `json:"rpcChainVMProtocol"`
+	Features           []string `json:"features"`
+}
+
+// CheckCompatibilityReply is the reply from CheckCompatibility
+type CheckCompatibilityReply struct {
+	Compatible      bool     `json:"compatible"`
+	Reason          string   `json:"reason,omitempty"`
+	MissingFeatures []string `json:"missingFeatures,omitempty"`
+}
+
+// CheckCompatibility reports whether the VM can run on avalanchego with the requested
+// RPCChainVM protocol version and supports the requested features.
+func (ss *StaticService) CheckCompatibility(_ *http.Request, args *CheckCompatibilityArgs, reply *CheckCompatibilityReply) error {
+	reply.Compatible = true
+	if err := CheckRPCChainVMProtocol(args.RPCChainVMProtocol); err != nil {
+		reply.Compatible = false
+		reply.Reason = err.Error()
+	}
+	supported := set.Of(Features...)
+	for _, feature := range args.Features {
+		if !supported.Contains(feature) {
+			reply.Compatible = false
+			reply.MissingFeatures = append(reply.MissingFeatures, feature)
+		}
+	}
+	return nil
+}
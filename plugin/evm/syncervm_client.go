@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/versiondb"
@@ -17,20 +18,27 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/chain"
 	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/state/snapshot"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/eth"
 	"github.com/ava-labs/subnet-evm/ethdb"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/plugin/evm/message"
 	syncclient "github.com/ava-labs/subnet-evm/sync/client"
+	"github.com/ava-labs/subnet-evm/sync/client/stats"
 	"github.com/ava-labs/subnet-evm/sync/statesync"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	// State sync fetches [parentsToGet] parents of the block it syncs to.
 	// The last 256 block hashes are necessary to support the BLOCKHASH opcode.
 	parentsToGet = 256
+
+	// blockPrefetchTimeout bounds each request for a range of ancestors by height, so
+	// that peers which do not serve blocks by height do not stall state sync.
+	blockPrefetchTimeout = 30 * time.Second
 )
 
 var stateSyncSummaryKey = []byte("stateSyncSummary")
@@ -42,8 +50,11 @@ type stateSyncClientConfig struct {
 	// Specifies the number of blocks behind the latest state summary that the chain must be
 	// in order to prefer performing state sync over falling back to the normal bootstrapping
 	// algorithm.
-	stateSyncMinBlocks   uint64
-	stateSyncRequestSize uint16 // number of key/value pairs to ask peers for per request
+	stateSyncMinBlocks        uint64
+	stateSyncRequestSize      uint16 // number of key/value pairs to ask peers for per request
+	stateSyncBlockRequestSize uint16 // number of ancestor blocks to ask peers for per request
+	// number of ancestor block requests in flight at a time
+	stateSyncBlockRequestConcurrency int
 
 	lastAcceptedHeight uint64
 
@@ -54,7 +65,8 @@ type stateSyncClientConfig struct {
 	acceptedBlockDB database.Database
 	db              *versiondb.Database
 
-	client syncclient.Client
+	client    syncclient.Client
+	peerStats *stats.PeerStats // contribution of each peer to [client]
 
 	toEngine chan<- commonEng.Message
 }
@@ -70,6 +82,12 @@ type stateSyncerClient struct {
 	// State Sync results
 	syncSummary  message.SyncSummary
 	stateSyncErr error
+
+	// track the phase of the sync for the status API
+	statusLock sync.RWMutex
+	phase      stateSyncPhase
+	startTime  time.Time
+	progress   func() statesync.Progress // set while syncing the EVM trie
 }
 
 func NewStateSyncClient(config *stateSyncClientConfig) StateSyncClient {
@@ -88,6 +106,7 @@ type StateSyncClient interface {
 	StateSyncClearOngoingSummary() error
 	Shutdown() error
 	Error() error
+	Status() *StateSyncStatus
 }
 
 // Syncer represents a step in state sync,
@@ -201,6 +220,7 @@ func (client *stateSyncerClient) acceptSyncSummary(proposedSummary message.SyncS
 	}
 
 	log.Info("Starting state sync", "summary", proposedSummary)
+	client.setPhase(stateSyncPhaseBlocks)
 
 	// create a cancellable ctx for the state sync goroutine
 	ctx, cancel := context.WithCancel(context.Background())
@@ -215,6 +235,11 @@ func (client *stateSyncerClient) acceptSyncSummary(proposedSummary message.SyncS
 		} else {
 			client.stateSyncErr = client.finishSync()
 		}
+		if client.stateSyncErr != nil {
+			client.setPhase(stateSyncPhaseFailed)
+		} else {
+			client.setPhase(stateSyncPhaseDone)
+		}
 		// notify engine regardless of whether err == nil,
 		// this error will be propagated to the engine when it calls
 		// vm.SetState(snow.Bootstrapping)
@@ -231,7 +256,7 @@ func (client *stateSyncerClient) acceptSyncSummary(proposedSummary message.SyncS
 func (client *stateSyncerClient) syncBlocks(ctx context.Context, fromHash common.Hash, fromHeight uint64, parentsToGet int) error {
 	nextHash := fromHash
 	nextHeight := fromHeight
-	parentsPerRequest := uint16(32)
+	parentsPerRequest := client.stateSyncBlockRequestSize
 
 	// first, check for blocks already available on disk so we don't
 	// request them from peers.
@@ -249,6 +274,10 @@ func (client *stateSyncerClient) syncBlocks(ctx context.Context, fromHash common
 		break
 	}
 
+	// fetch ranges of the blocks we couldn't find on disk from peers
+	// concurrently. These are only used if they link to [fromHash].
+	prefetched := client.prefetchBlocks(ctx, nextHeight, parentsToGet)
+
 	// get any blocks we couldn't find on disk from peers and write
 	// them to disk.
 	batch := client.chaindb.NewBatch()
@@ -256,6 +285,15 @@ func (client *stateSyncerClient) syncBlocks(ctx context.Context, fromHash common
 		if err := ctx.Err(); err != nil {
 			return err
 		}
+		if block, ok := prefetched[nextHeight]; ok && block.Hash() == nextHash {
+			rawdb.WriteBlock(batch, block)
+			rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
+
+			i--
+			nextHash = block.ParentHash()
+			nextHeight--
+			continue
+		}
 		blocks, err := client.client.GetBlocks(ctx, nextHash, nextHeight, parentsPerRequest)
 		if err != nil {
 			log.Error("could not get blocks from peer", "err", err, "nextHash", nextHash, "remaining", i+1)
@@ -275,6 +313,50 @@ func (client *stateSyncerClient) syncBlocks(ctx context.Context, fromHash common
 	return batch.Write()
 }
 
+// prefetchBlocks requests the [count] blocks ending at [height] from peers by height,
+// in ranges of [stateSyncBlockRequestSize] blocks with up to
+// [stateSyncBlockRequestConcurrency] requests in flight, and returns them by height.
+// The blocks are not verified against a trusted hash, and ranges that could not be
+// fetched are omitted, so the caller must fetch any blocks that do not link by hash.
+func (client *stateSyncerClient) prefetchBlocks(ctx context.Context, height uint64, count int) map[uint64]*types.Block {
+	blocks := make(map[uint64]*types.Block)
+	if client.stateSyncBlockRequestConcurrency <= 1 {
+		return blocks
+	}
+
+	var (
+		lock sync.Mutex
+		eg   errgroup.Group
+		size = int(client.stateSyncBlockRequestSize)
+	)
+	eg.SetLimit(client.stateSyncBlockRequestConcurrency)
+	for offset := 0; offset < count && uint64(offset) <= height; offset += size {
+		start := height - uint64(offset)
+		parents := uint16(size)
+		if remaining := count - offset; remaining < size {
+			parents = uint16(remaining)
+		}
+		eg.Go(func() error {
+			reqCtx, cancel := context.WithTimeout(ctx, blockPrefetchTimeout)
+			defer cancel()
+
+			fetched, err := client.client.GetBlocks(reqCtx, common.Hash{}, start, parents)
+			if err != nil {
+				log.Debug("could not prefetch blocks from peer", "height", start, "parents", parents, "err", err)
+				return nil
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			for _, block := range fetched {
+				blocks[block.NumberU64()] = block
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return blocks
+}
+
 func (client *stateSyncerClient) syncStateTrie(ctx context.Context) error {
 	log.Info("state sync: sync starting", "root", client.syncSummary.BlockRoot)
 	evmSyncer, err := statesync.NewStateSyncer(&statesync.StateSyncerConfig{
@@ -289,6 +371,11 @@ func (client *stateSyncerClient) syncStateTrie(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	client.statusLock.Lock()
+	client.phase = stateSyncPhaseState
+	client.progress = evmSyncer.Progress
+	client.statusLock.Unlock()
+
 	if err := evmSyncer.Start(ctx); err != nil {
 		return err
 	}
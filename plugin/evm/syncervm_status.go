@@ -0,0 +1,132 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type stateSyncPhase string
+
+const (
+	stateSyncPhaseIdle   stateSyncPhase = "idle"   // no sync has started
+	stateSyncPhaseBlocks stateSyncPhase = "blocks" // fetching the blocks preceding the summary
+	stateSyncPhaseState  stateSyncPhase = "state"  // syncing the EVM tries and code
+	stateSyncPhaseDone   stateSyncPhase = "done"
+	stateSyncPhaseFailed stateSyncPhase = "failed"
+)
+
+// StateSyncPeerStatus is the contribution of a peer to the state sync.
+type StateSyncPeerStatus struct {
+	NodeID       ids.NodeID `json:"nodeID"`
+	Requests     uint64     `json:"requests"`
+	Failures     uint64     `json:"failures"`
+	Bytes        uint64     `json:"bytes"`
+	LastResponse *time.Time `json:"lastResponse,omitempty"`
+}
+
+// StateSyncStatus describes the progress of the state sync of this node.
+type StateSyncStatus struct {
+	Phase       string      `json:"phase"`
+	BlockNumber uint64      `json:"blockNumber,omitempty"`
+	BlockRoot   common.Hash `json:"blockRoot,omitempty"`
+	StartedAt   *time.Time  `json:"startedAt,omitempty"`
+
+	// MainTrieDone is true once the account trie is synced. Until then,
+	// TriesRemaining is not known.
+	MainTrieDone    bool    `json:"mainTrieDone"`
+	TriesSynced     int     `json:"triesSynced"`
+	TriesRemaining  int     `json:"triesRemaining"`
+	Leafs           uint64  `json:"leafs"`
+	LeafsPerSecond  float64 `json:"leafsPerSecond"`
+	BytesDownloaded uint64  `json:"bytesDownloaded"`
+	// ETASeconds is zero until the leaf rate is first measured, a minute after
+	// the sync of the tries starts.
+	ETASeconds uint64 `json:"etaSeconds"`
+	// LastLeafsAt is the time leafs were last received. A sync that has not
+	// received leafs recently is stuck rather than slow.
+	LastLeafsAt *time.Time `json:"lastLeafsAt,omitempty"`
+
+	Peers []StateSyncPeerStatus `json:"peers"`
+}
+
+// setPhase records that the sync entered [phase].
+func (client *stateSyncerClient) setPhase(phase stateSyncPhase) {
+	client.statusLock.Lock()
+	defer client.statusLock.Unlock()
+
+	if client.phase == "" {
+		client.startTime = time.Now()
+	}
+	client.phase = phase
+}
+
+// Status returns the progress of the state sync. Safe to call concurrently
+// with the sync.
+func (client *stateSyncerClient) Status() *StateSyncStatus {
+	client.statusLock.RLock()
+	defer client.statusLock.RUnlock()
+
+	status := &StateSyncStatus{
+		Phase: string(stateSyncPhaseIdle),
+		Peers: []StateSyncPeerStatus{},
+	}
+	if client.phase == "" {
+		return status
+	}
+	startTime := client.startTime
+	status.Phase = string(client.phase)
+	status.BlockNumber = client.syncSummary.BlockNumber
+	status.BlockRoot = client.syncSummary.BlockRoot
+	status.StartedAt = &startTime
+
+	if client.progress != nil {
+		progress := client.progress()
+		status.MainTrieDone = progress.MainTrieDone
+		status.TriesSynced = progress.TriesSynced
+		status.TriesRemaining = progress.TriesRemaining
+		status.Leafs = progress.Leafs
+		status.LeafsPerSecond = progress.LeafsPerSecond
+		status.ETASeconds = uint64(progress.ETA.Seconds())
+		if !progress.LastLeafs.IsZero() {
+			lastLeafs := progress.LastLeafs
+			status.LastLeafsAt = &lastLeafs
+		}
+	}
+	if client.peerStats != nil {
+		status.BytesDownloaded = client.peerStats.BytesDownloaded()
+		for nodeID, peer := range client.peerStats.Peers() {
+			peerStatus := StateSyncPeerStatus{
+				NodeID:   nodeID,
+				Requests: peer.Requests,
+				Failures: peer.Failures,
+				Bytes:    peer.Bytes,
+			}
+			if !peer.LastResponse.IsZero() {
+				lastResponse := peer.LastResponse
+				peerStatus.LastResponse = &lastResponse
+			}
+			status.Peers = append(status.Peers, peerStatus)
+		}
+		// List the peers that contributed the most first.
+		sort.Slice(status.Peers, func(i, j int) bool {
+			return status.Peers[i].Bytes > status.Peers[j].Bytes
+		})
+	}
+	return status
+}
+
+// StateSyncAPI reports the progress of the state sync of this node, so that
+// operators can tell a slow sync from a stuck one.
+type StateSyncAPI struct{ vm *VM }
+
+// Status returns the progress of the state sync of this node.
+func (api *StateSyncAPI) Status(ctx context.Context) (*StateSyncStatus, error) {
+	return api.vm.StateSyncClient.Status(), nil
+}
@@ -439,6 +439,7 @@ func testSyncerVM(t *testing.T, vmSetup *syncVMSetup, test syncTest) {
 		t.Fatal("unexpected value returned from accept", "expected", test.syncMode, "got", syncMode)
 	}
 	if syncMode == block.StateSyncSkipped {
+		assert.Equal(t, string(stateSyncPhaseIdle), syncerVM.StateSyncClient.Status().Phase)
 		return
 	}
 	msg := <-syncerEngineChan
@@ -455,6 +456,18 @@ func testSyncerVM(t *testing.T, vmSetup *syncVMSetup, test syncTest) {
 		t.Fatal("state sync failed", err)
 	}
 
+	// the status reports a completed sync and the contribution of the server.
+	status := syncerVM.StateSyncClient.Status()
+	assert.Equal(t, string(stateSyncPhaseDone), status.Phase)
+	assert.Equal(t, retrievedSummary.Height(), status.BlockNumber)
+	assert.True(t, status.MainTrieDone)
+	assert.NotEmpty(t, status.Peers)
+	peerBytes := uint64(0)
+	for _, peer := range status.Peers {
+		peerBytes += peer.Bytes
+	}
+	assert.Equal(t, status.BytesDownloaded, peerBytes)
+
 	// set [syncerVM] to bootstrapping and verify the last accepted block has been updated correctly
 	// and that we can bootstrap and process some blocks.
 	if err := syncerVM.SetState(context.Background(), snow.Bootstrapping); err != nil {
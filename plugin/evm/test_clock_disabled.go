@@ -0,0 +1,10 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !testclock
+// +build !testclock
+
+package evm
+
+// testClockEnabled prevents the admin API from setting the VM clock in production builds.
+const testClockEnabled = false
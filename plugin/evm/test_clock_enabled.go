@@ -0,0 +1,10 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build testclock
+// +build testclock
+
+package evm
+
+// testClockEnabled allows the admin API to set the VM clock in test builds.
+const testClockEnabled = true
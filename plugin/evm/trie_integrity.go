@@ -0,0 +1,224 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/metrics"
+	statesyncclient "github.com/ava-labs/subnet-evm/sync/client"
+	"github.com/ava-labs/subnet-evm/sync/client/stats"
+	"github.com/ava-labs/subnet-evm/sync/statesync"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// trieRepairTimeout bounds how long a repair waits for peers to serve the
+// leaves below a corrupt node.
+const trieRepairTimeout = 10 * time.Minute
+
+var (
+	trieIntegrityChecksCounter  = metrics.NewRegisteredCounter("vm/trie_integrity/checks", nil)
+	trieIntegrityRepairsCounter = metrics.NewRegisteredCounter("vm/trie_integrity/repairs", nil)
+	trieIntegrityFailedCounter  = metrics.NewRegisteredCounter("vm/trie_integrity/failed_repairs", nil)
+)
+
+// trieCorruption describes a trie node that is missing or does not match its hash.
+type trieCorruption struct {
+	root    common.Hash // root of the trie containing the node
+	account common.Hash // account hash of a storage trie, empty for the account trie
+	path    []byte      // hex-encoded path of the node
+	hash    common.Hash // hash of the node
+}
+
+func (c *trieCorruption) String() string {
+	return fmt.Sprintf("trie node %s at path %x of trie %s (account %s)", c.hash, c.path, c.root, c.account)
+}
+
+// trieIntegrityChecker samples trie nodes of a state and checks them against their
+// hashes.
+type trieIntegrityChecker struct {
+	triedb  *trie.Database
+	samples int
+	rand    *rand.Rand
+}
+
+func newTrieIntegrityChecker(triedb *trie.Database, samples int) *trieIntegrityChecker {
+	return &trieIntegrityChecker{
+		triedb:  triedb,
+		samples: samples,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+	}
+}
+
+// check samples up to [samples] nodes of the account trie at [root], starting at
+// a random key, and up to [samples] nodes of the storage trie of the first sampled
+// account with storage. Returns the first corruption found, or nil.
+func (c *trieIntegrityChecker) check(root common.Hash) (*trieCorruption, error) {
+	var storage *trie.ID
+	corruption, err := c.sample(trie.StateTrieID(root), func(key, blob []byte) error {
+		if storage != nil {
+			return nil
+		}
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			return fmt.Errorf("failed to decode account %x: %w", key, err)
+		}
+		if account.Root != types.EmptyRootHash {
+			storage = trie.StorageTrieID(root, common.BytesToHash(key), account.Root)
+		}
+		return nil
+	})
+	if corruption != nil || err != nil || storage == nil {
+		return corruption, err
+	}
+	return c.sample(storage, nil)
+}
+
+// sample iterates up to [samples] nodes of the trie [id] from a random key,
+// calling [onLeaf] with the key and value of each leaf.
+func (c *trieIntegrityChecker) sample(id *trie.ID, onLeaf func(key, blob []byte) error) (*trieCorruption, error) {
+	tr, err := trie.New(id, c.triedb)
+	if err != nil {
+		return c.missing(id, err)
+	}
+	start := make([]byte, common.HashLength)
+	c.rand.Read(start)
+
+	it := tr.NodeIterator(start)
+	for i := 0; i < c.samples && it.Next(true); i++ {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			blob := it.NodeBlob()
+			if blob == nil {
+				break // the error is returned by the iterator below
+			}
+			if crypto.Keccak256Hash(blob) != hash {
+				return &trieCorruption{
+					root:    id.Root,
+					account: id.Owner,
+					path:    common.CopyBytes(it.Path()),
+					hash:    hash,
+				}, nil
+			}
+		}
+		if it.Leaf() && onLeaf != nil {
+			if err := onLeaf(it.LeafKey(), it.LeafBlob()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return c.missing(id, err)
+	}
+	return nil, nil
+}
+
+// missing returns the corruption described by [err] if it is a missing node
+// error, or [err] otherwise.
+func (c *trieIntegrityChecker) missing(id *trie.ID, err error) (*trieCorruption, error) {
+	var missing *trie.MissingNodeError
+	if !errors.As(err, &missing) {
+		return nil, err
+	}
+	return &trieCorruption{
+		root:    id.Root,
+		account: id.Owner,
+		path:    missing.Path,
+		hash:    missing.NodeHash,
+	}, nil
+}
+
+// checkTrieIntegrity checks the trie nodes of the last accepted state every
+// [config.TrieIntegrityCheckInterval] until the VM shuts down, raising an alarm
+// for each corruption found and repairing it from peers if
+// [config.TrieRepairEnabled].
+//
+// Note: the trie of the last accepted state is kept in memory for a number of
+// blocks after it is accepted, so its nodes are not dereferenced while sampled.
+func (vm *VM) checkTrieIntegrity() {
+	defer vm.shutdownWg.Done()
+
+	checker := newTrieIntegrityChecker(vm.blockChain.StateCache().TrieDB(), vm.config.TrieIntegrityCheckSamples)
+	ticker := time.NewTicker(vm.config.TrieIntegrityCheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			block := vm.blockChain.LastAcceptedBlock()
+			corruption, err := checker.check(block.Root())
+			trieIntegrityChecksCounter.Inc(1)
+			if err != nil {
+				log.Warn("Failed to check trie integrity", "root", block.Root(), "err", err)
+				continue
+			}
+			if corruption == nil {
+				continue
+			}
+			vm.alarms.raise(Alarm{
+				Kind:        AlarmTrieCorruption,
+				Time:        time.Now(),
+				Message:     fmt.Sprintf("corrupt %s", corruption),
+				BlockHash:   block.Hash(),
+				BlockNumber: block.NumberU64(),
+			})
+			if !vm.config.TrieRepairEnabled {
+				continue
+			}
+			if err := vm.repairTrie(corruption); err != nil {
+				trieIntegrityFailedCounter.Inc(1)
+				log.Error("Failed to repair trie", "corruption", corruption, "err", err)
+				continue
+			}
+			trieIntegrityRepairsCounter.Inc(1)
+		case <-vm.shutdownChan:
+			return
+		}
+	}
+}
+
+// repairTrie fetches the leaves below the corrupt node from peers using the state
+// sync leaf protocol, and rewrites the trie nodes below it.
+func (vm *VM) repairTrie(corruption *trieCorruption) error {
+	ctx, cancel := context.WithTimeout(context.Background(), trieRepairTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-vm.shutdownChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	client := statesyncclient.NewClient(&statesyncclient.ClientConfig{
+		NetworkClient: vm.client,
+		Codec:         vm.networkCodec,
+		Stats:         stats.NewNoOpStats(),
+		BlockParser:   vm,
+	})
+	start := time.Now()
+	log.Warn("Repairing trie from peers", "corruption", corruption)
+	leafs, err := statesync.RepairTrie(ctx, client, vm.chaindb, corruption.root, corruption.account, corruption.path, vm.config.StateSyncRequestSize)
+	if err != nil {
+		return err
+	}
+
+	// Drop the corrupt node from the clean cache, so that the repaired node is
+	// read from disk.
+	triedb := vm.blockChain.StateCache().TrieDB()
+	triedb.EvictClean(corruption.hash)
+	if blob := rawdb.ReadLegacyTrieNode(vm.chaindb, corruption.hash); crypto.Keccak256Hash(blob) != corruption.hash {
+		return fmt.Errorf("%s is still corrupt after fetching %d leafs", corruption, leafs)
+	}
+	log.Info("Repaired trie", "corruption", corruption, "leafs", leafs, "elapsed", time.Since(start))
+	return nil
+}
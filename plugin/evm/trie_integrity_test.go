@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrieIntegrityChecker(t *testing.T) {
+	require := require.New(t)
+
+	diskdb := rawdb.NewMemoryDatabase()
+	root, _ := trie.FillAccounts(t, trie.NewDatabase(diskdb), types.EmptyRootHash, 1000, nil)
+
+	checker := newTrieIntegrityChecker(trie.NewDatabase(diskdb), 10000)
+	corruption, err := checker.check(root)
+	require.NoError(err)
+	require.Nil(corruption)
+
+	// Swap the contents of every node below the root, so that the first node
+	// sampled after the root does not match its hash.
+	tr, err := trie.New(trie.StateTrieID(root), trie.NewDatabase(diskdb))
+	require.NoError(err)
+	var (
+		hashes []common.Hash
+		blobs  [][]byte
+	)
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if it.Hash() != (common.Hash{}) && it.Hash() != root {
+			hashes = append(hashes, it.Hash())
+			blobs = append(blobs, it.NodeBlob())
+		}
+	}
+	require.NoError(it.Error())
+	require.Greater(len(hashes), 1)
+	for i, hash := range hashes {
+		rawdb.WriteLegacyTrieNode(diskdb, hash, blobs[(i+1)%len(blobs)])
+	}
+
+	checker = newTrieIntegrityChecker(trie.NewDatabase(diskdb), 10000)
+	corruption, err = checker.check(root)
+	require.NoError(err)
+	require.NotNil(corruption)
+	require.Equal(root, corruption.root)
+	require.Equal(common.Hash{}, corruption.account)
+	require.Contains(hashes, corruption.hash)
+
+	// A missing root is reported as a corruption of the root.
+	rawdb.DeleteLegacyTrieNode(diskdb, root)
+	checker = newTrieIntegrityChecker(trie.NewDatabase(diskdb), 10000)
+	corruption, err = checker.check(root)
+	require.NoError(err)
+	require.NotNil(corruption)
+	require.Equal(root, corruption.hash)
+	require.Empty(corruption.path)
+}
@@ -0,0 +1,155 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+)
+
+const (
+	precompileEnabled      = "enabled"
+	precompileDisabled     = "disabled"
+	precompileReconfigured = "reconfigured"
+)
+
+// PrecompileChange describes how a proposed upgrade changes a precompile that
+// is enabled at the simulated timestamp.
+type PrecompileChange struct {
+	Key string `json:"key"`
+	// Change is one of "enabled", "disabled" or "reconfigured".
+	Change string `json:"change"`
+	// Before is the config in effect without the proposed upgrade, omitted if
+	// the precompile would not be enabled.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the config in effect with the proposed upgrade, omitted if the
+	// precompile would not be enabled.
+	After json.RawMessage `json:"after,omitempty"`
+}
+
+// UpgradeSimulation is the result of simulating a proposed upgrade.
+type UpgradeSimulation struct {
+	// Valid is true if the upgrade passes verification, is compatible with
+	// the accepted chain and its activations can be applied to the current state.
+	Valid bool `json:"valid"`
+	// Error is the reason the upgrade is not valid.
+	Error string `json:"error,omitempty"`
+	// Timestamp is the timestamp the upgrade was simulated at.
+	Timestamp uint64 `json:"timestamp"`
+	// Precompiles are the precompiles enabled at [Timestamp] with the upgrade.
+	Precompiles params.Precompiles `json:"precompiles,omitempty"`
+	// Changes are the precompiles whose config at [Timestamp] differs with the upgrade.
+	Changes []PrecompileChange `json:"changes,omitempty"`
+	// StateUpgrades is the number of state upgrades activated between the last
+	// accepted block and [Timestamp] with the upgrade.
+	StateUpgrades int `json:"stateUpgrades"`
+}
+
+// simulateUpgrade applies [upgradeBytes], formatted as upgrade.json, to the
+// chain config as if the node restarted with them, and reports the precompiles
+// that would be enabled at [timestamp]. The activations between the last
+// accepted block and [timestamp] are applied to a copy of the last accepted
+// state, which is discarded.
+//
+// Returns an error only if [upgradeBytes] cannot be parsed or the simulation
+// cannot run, a failed verification is reported in the result.
+func (vm *VM) simulateUpgrade(upgradeBytes []byte, timestamp uint64) (*UpgradeSimulation, error) {
+	var upgradeConfig params.UpgradeConfig
+	if err := json.Unmarshal(upgradeBytes, &upgradeConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse upgrade bytes: %w", err)
+	}
+
+	lastAccepted := vm.blockChain.LastAcceptedBlock()
+	if timestamp < lastAccepted.Time() {
+		return nil, fmt.Errorf("timestamp %d is before the last accepted block (%d)", timestamp, lastAccepted.Time())
+	}
+	result := &UpgradeSimulation{Timestamp: timestamp}
+
+	config := *vm.chainConfig
+	config.UpgradeConfig = upgradeConfig
+	if err := config.Verify(); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	if err := vm.chainConfig.CheckCompatible(&config, lastAccepted.NumberU64(), lastAccepted.Time()); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	statedb, err := vm.blockChain.StateAt(lastAccepted.Root())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state of last accepted block: %w", err)
+	}
+	parentTimestamp := lastAccepted.Time()
+	header := &types.Header{
+		Number: new(big.Int).Add(lastAccepted.Number(), big.NewInt(1)),
+		Time:   timestamp,
+	}
+	if err := core.ApplyUpgrades(&config, &parentTimestamp, types.NewBlockWithHeader(header), statedb); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	result.Valid = true
+	result.Precompiles = config.EnabledStatefulPrecompiles(timestamp)
+	result.StateUpgrades = len(config.GetActivatingStateUpgrades(&parentTimestamp, timestamp, config.StateUpgrades))
+	result.Changes, err = diffPrecompiles(vm.chainConfig.EnabledStatefulPrecompiles(timestamp), result.Precompiles)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// diffPrecompiles returns the changes from [before] to [after], ordered by
+// precompile address.
+func diffPrecompiles(before, after params.Precompiles) ([]PrecompileChange, error) {
+	var changes []PrecompileChange
+	for _, module := range modules.RegisteredModules() {
+		key := module.ConfigKey
+		beforeConfig, wasEnabled := before[key]
+		afterConfig, isEnabled := after[key]
+
+		change := PrecompileChange{Key: key}
+		switch {
+		case !wasEnabled && !isEnabled:
+			continue
+		case !wasEnabled:
+			change.Change = precompileEnabled
+		case !isEnabled:
+			change.Change = precompileDisabled
+		case !beforeConfig.Equal(afterConfig):
+			change.Change = precompileReconfigured
+		default:
+			continue
+		}
+
+		var err error
+		if change.Before, err = marshalPrecompileConfig(beforeConfig, wasEnabled); err != nil {
+			return nil, err
+		}
+		if change.After, err = marshalPrecompileConfig(afterConfig, isEnabled); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func marshalPrecompileConfig(config precompileconfig.Config, enabled bool) (json.RawMessage, error) {
+	if !enabled {
+		return nil, nil
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s config: %w", config.Key(), err)
+	}
+	return b, nil
+}
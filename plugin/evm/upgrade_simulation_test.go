@@ -0,0 +1,62 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateUpgrade(t *testing.T) {
+	_, vm, _, _ := GenesisVM(t, true, genesisJSONSubnetEVM, "", "")
+	defer func() {
+		require.NoError(t, vm.Shutdown(context.Background()))
+	}()
+
+	simulate := func(timestamp uint64, upgrades ...params.PrecompileUpgrade) *UpgradeSimulation {
+		upgradeBytes, err := json.Marshal(&params.UpgradeConfig{PrecompileUpgrades: upgrades})
+		require.NoError(t, err)
+		result, err := vm.simulateUpgrade(upgradeBytes, timestamp)
+		require.NoError(t, err)
+		return result
+	}
+	now := vm.blockChain.LastAcceptedBlock().Time() + 10
+
+	// Enabling the tx allow list after the last accepted block is valid, and
+	// only shows up once the activation timestamp is reached.
+	enable := params.PrecompileUpgrade{Config: txallowlist.NewConfig(utils.NewUint64(now), testEthAddrs[0:1], nil, nil)}
+	result := simulate(now-1, enable)
+	require.True(t, result.Valid, result.Error)
+	require.Empty(t, result.Changes)
+
+	result = simulate(now, enable)
+	require.True(t, result.Valid, result.Error)
+	require.Contains(t, result.Precompiles, txallowlist.ConfigKey)
+	require.Len(t, result.Changes, 1)
+	require.Equal(t, txallowlist.ConfigKey, result.Changes[0].Key)
+	require.Equal(t, precompileEnabled, result.Changes[0].Change)
+	require.Nil(t, result.Changes[0].Before)
+	require.NotNil(t, result.Changes[0].After)
+
+	// Configs failing verification are reported.
+	invalid := params.PrecompileUpgrade{Config: txallowlist.NewConfig(utils.NewUint64(now), testEthAddrs[0:1], testEthAddrs[0:1], nil)}
+	result = simulate(now, invalid)
+	require.False(t, result.Valid)
+	require.NotEmpty(t, result.Error)
+
+	// Activating before the last accepted block is incompatible.
+	retroactive := params.PrecompileUpgrade{Config: txallowlist.NewConfig(utils.NewUint64(0), testEthAddrs[0:1], nil, nil)}
+	result = simulate(now, retroactive)
+	require.False(t, result.Valid)
+	require.Contains(t, result.Error, "retroactively")
+
+	_, err := vm.simulateUpgrade([]byte("{"), now)
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ValidatorSetChange describes a change to the weight of a validator of the subnet.
+// PreviousWeight is 0 for an added validator, and Weight is 0 for a removed validator.
+type ValidatorSetChange struct {
+	NodeID         ids.NodeID `json:"nodeID"`
+	PreviousWeight uint64     `json:"previousWeight"`
+	Weight         uint64     `json:"weight"`
+	PChainHeight   uint64     `json:"pChainHeight"`
+}
+
+// validatorEvents tracks the validator set of a subnet and sends the changes to it to
+// subscribers of the validators API.
+//
+// Note: the changes are derived from the P-Chain height observed by this node, so they are
+// not part of any block and are not served as logs.
+type validatorEvents struct {
+	state    validators.State
+	subnetID ids.ID
+	feed     event.Feed
+
+	lock       sync.Mutex
+	height     uint64
+	validators map[ids.NodeID]uint64 // nil until the first validator set is fetched
+}
+
+func newValidatorEvents(state validators.State, subnetID ids.ID) *validatorEvents {
+	return &validatorEvents{
+		state:    state,
+		subnetID: subnetID,
+	}
+}
+
+// Subscribe registers [ch] to receive the changes found by each update.
+func (v *validatorEvents) Subscribe(ch chan<- []ValidatorSetChange) event.Subscription {
+	return v.feed.Subscribe(ch)
+}
+
+// update fetches the validator set at the current P-Chain height and returns the changes
+// since the last update, which are also sent to subscribers. The first update only records
+// the validator set.
+func (v *validatorEvents) update(ctx context.Context) ([]ValidatorSetChange, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	height, err := v.state.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current P-Chain height: %w", err)
+	}
+	if v.validators != nil && height == v.height {
+		return nil, nil
+	}
+	validatorSet, err := v.state.GetValidatorSet(ctx, height, v.subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator set at P-Chain height %d: %w", height, err)
+	}
+	weights := make(map[ids.NodeID]uint64, len(validatorSet))
+	for nodeID, validator := range validatorSet {
+		weights[nodeID] = validator.Weight
+	}
+
+	var changes []ValidatorSetChange
+	if v.validators != nil {
+		changes = validatorSetChanges(v.validators, weights, height)
+	}
+	v.height = height
+	v.validators = weights
+	if len(changes) > 0 {
+		v.feed.Send(changes)
+	}
+	return changes, nil
+}
+
+// validatorSetChanges returns the changes from [previous] to [current] at P-Chain [height],
+// ordered by NodeID.
+func validatorSetChanges(previous, current map[ids.NodeID]uint64, height uint64) []ValidatorSetChange {
+	var changes []ValidatorSetChange
+	for nodeID, previousWeight := range previous {
+		if weight := current[nodeID]; weight != previousWeight {
+			changes = append(changes, ValidatorSetChange{
+				NodeID:         nodeID,
+				PreviousWeight: previousWeight,
+				Weight:         weight,
+				PChainHeight:   height,
+			})
+		}
+	}
+	for nodeID, weight := range current {
+		if _, ok := previous[nodeID]; !ok {
+			changes = append(changes, ValidatorSetChange{
+				NodeID:       nodeID,
+				Weight:       weight,
+				PChainHeight: height,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].NodeID.Less(changes[j].NodeID) })
+	return changes
+}
+
+// emitValidatorEvents sends the changes to the validator set of the subnet to subscribers
+// every [config.ValidatorEventsInterval] until the VM shuts down.
+func (vm *VM) emitValidatorEvents() {
+	defer vm.shutdownWg.Done()
+
+	ticker := time.NewTicker(vm.config.ValidatorEventsInterval.Duration)
+	defer ticker.Stop()
+	for {
+		changes, err := vm.validatorEvents.update(context.TODO())
+		if err != nil {
+			log.Warn("Failed to update validator set events", "err", err)
+		} else if len(changes) > 0 {
+			log.Debug("Sent validator set changes", "pChainHeight", changes[0].PChainHeight, "changes", len(changes))
+		}
+		select {
+		case <-vm.shutdownChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// signWarpValidatorSets periodically signs the validator set message of the latest
+// P-Chain epoch until shutdown.
+func (vm *VM) signWarpValidatorSets() {
+	defer vm.shutdownWg.Done()
+
+	ticker := time.NewTicker(vm.config.WarpValidatorSetInterval.Duration)
+	defer ticker.Stop()
+	for {
+		height, err := vm.ctx.ValidatorState.GetCurrentHeight(context.TODO())
+		if err != nil {
+			log.Warn("Failed to get current P-Chain height", "err", err)
+		} else if err := vm.warpValidatorSets.Update(context.TODO(), height); err != nil {
+			log.Warn("Failed to sign warp validator set message", "pChainHeight", height, "err", err)
+		}
+		select {
+		case <-vm.shutdownChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorEvents(t *testing.T) {
+	require := require.New(t)
+
+	subnetID := ids.GenerateTestID()
+	removedNodeID := ids.GenerateTestNodeID()
+	changedNodeID := ids.GenerateTestNodeID()
+	unchangedNodeID := ids.GenerateTestNodeID()
+	addedNodeID := ids.GenerateTestNodeID()
+	validatorSets := map[uint64]map[ids.NodeID]uint64{
+		1: {removedNodeID: 10, changedNodeID: 20, unchangedNodeID: 30},
+		2: {changedNodeID: 25, unchangedNodeID: 30, addedNodeID: 40},
+	}
+	height := uint64(1)
+	state := &validators.TestState{
+		GetCurrentHeightF: func(context.Context) (uint64, error) {
+			return height, nil
+		},
+		GetValidatorSetF: func(_ context.Context, height uint64, requestedSubnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			require.Equal(subnetID, requestedSubnetID)
+			output := make(map[ids.NodeID]*validators.GetValidatorOutput)
+			for nodeID, weight := range validatorSets[height] {
+				output[nodeID] = &validators.GetValidatorOutput{NodeID: nodeID, Weight: weight}
+			}
+			return output, nil
+		},
+	}
+	events := newValidatorEvents(state, subnetID)
+	changesCh := make(chan []ValidatorSetChange, 1)
+	sub := events.Subscribe(changesCh)
+	defer sub.Unsubscribe()
+
+	// The first update only records the validator set.
+	changes, err := events.update(context.Background())
+	require.NoError(err)
+	require.Empty(changes)
+
+	// Updates at the same P-Chain height do not report changes.
+	changes, err = events.update(context.Background())
+	require.NoError(err)
+	require.Empty(changes)
+	require.Empty(changesCh)
+
+	height = 2
+	changes, err = events.update(context.Background())
+	require.NoError(err)
+	require.Equal(changes, <-changesCh)
+
+	expected := []ValidatorSetChange{
+		{NodeID: removedNodeID, PreviousWeight: 10, PChainHeight: 2},
+		{NodeID: changedNodeID, PreviousWeight: 20, Weight: 25, PChainHeight: 2},
+		{NodeID: addedNodeID, Weight: 40, PChainHeight: 2},
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i].NodeID.Less(expected[j].NodeID) })
+	require.Equal(expected, changes)
+}
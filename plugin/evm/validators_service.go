@@ -0,0 +1,141 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/peer"
+	"github.com/ava-labs/subnet-evm/rpc"
+)
+
+var errValidatorEventsDisabled = errors.New("validator set events are disabled, set validator-events-interval to enable them")
+
+// ValidatorsAPI reports the validators of the subnet as observed by this node, to
+// diagnose validators that do not return warp signatures or gossip.
+type ValidatorsAPI struct{ vm *VM }
+
+// ValidatorUptime is the uptime of a validator observed by this node since it started.
+type ValidatorUptime struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	Weight uint64     `json:"weight"`
+	// HasBLSKey is false for validators without a registered BLS key, which
+	// cannot sign warp messages.
+	HasBLSKey bool `json:"hasBLSKey"`
+	// Self is true for the validator run by this node.
+	Self      bool `json:"self"`
+	Connected bool `json:"connected"`
+	// UptimePercentage is the percentage of the observed duration this node was
+	// connected to the validator.
+	UptimePercentage float64 `json:"uptimePercentage"`
+	// UpDuration is the number of seconds this node was connected to the validator.
+	UpDuration uint64 `json:"upDuration"`
+	// ObservedDuration is the number of seconds since this node started.
+	ObservedDuration uint64 `json:"observedDuration"`
+}
+
+// GetValidatorUptimesReply is the reply of GetValidatorUptimes.
+type GetValidatorUptimesReply struct {
+	PChainHeight uint64            `json:"pChainHeight"`
+	Validators   []ValidatorUptime `json:"validators"`
+}
+
+// GetValidatorUptimes returns the uptime of each validator of the subnet at the
+// current P-Chain height, sorted by NodeID.
+func (api *ValidatorsAPI) GetValidatorUptimes(ctx context.Context) (*GetValidatorUptimesReply, error) {
+	state := api.vm.ctx.ValidatorState
+	height, err := state.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current P-Chain height: %w", err)
+	}
+	validatorSet, err := state.GetValidatorSet(ctx, height, api.vm.ctx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator set at P-Chain height %d: %w", height, err)
+	}
+
+	reply := &GetValidatorUptimesReply{
+		PChainHeight: height,
+		Validators:   make([]ValidatorUptime, 0, len(validatorSet)),
+	}
+	for nodeID, validator := range validatorSet {
+		uptime := api.vm.Network.Uptime(nodeID)
+		if nodeID == api.vm.ctx.NodeID {
+			// This node is always up from its own point of view.
+			uptime.Connected = true
+			uptime.UpDuration = uptime.ObservedDuration
+		}
+		validatorUptime := ValidatorUptime{
+			NodeID:           nodeID,
+			Weight:           validator.Weight,
+			HasBLSKey:        validator.PublicKey != nil,
+			Self:             nodeID == api.vm.ctx.NodeID,
+			Connected:        uptime.Connected,
+			UpDuration:       uint64(uptime.UpDuration.Seconds()),
+			ObservedDuration: uint64(uptime.ObservedDuration.Seconds()),
+		}
+		if uptime.ObservedDuration > 0 {
+			validatorUptime.UptimePercentage = 100 * float64(uptime.UpDuration) / float64(uptime.ObservedDuration)
+		}
+		reply.Validators = append(reply.Validators, validatorUptime)
+	}
+	sort.Slice(reply.Validators, func(i, j int) bool {
+		return reply.Validators[i].NodeID.Less(reply.Validators[j].NodeID)
+	})
+	return reply, nil
+}
+
+// GetPeersReply is the reply of GetPeers.
+type GetPeersReply struct {
+	NumPeers uint32          `json:"numPeers"`
+	Peers    []peer.PeerInfo `json:"peers"`
+}
+
+// GetPeers returns the peers this node is connected to, sorted by NodeID.
+func (api *ValidatorsAPI) GetPeers(ctx context.Context) (*GetPeersReply, error) {
+	peers := api.vm.Network.Peers()
+	return &GetPeersReply{
+		NumPeers: uint32(len(peers)),
+		Peers:    peers,
+	}, nil
+}
+
+// ValidatorSetChanges creates a subscription that is sent the changes to the validator set
+// of the subnet each time this node observes a new P-Chain height.
+func (api *ValidatorsAPI) ValidatorSetChanges(ctx context.Context) (*rpc.Subscription, error) {
+	events := api.vm.validatorEvents
+	if events == nil {
+		return &rpc.Subscription{}, errValidatorEventsDisabled
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		changesCh := make(chan []ValidatorSetChange, 16)
+		changesSub := events.Subscribe(changesCh)
+		defer changesSub.Unsubscribe()
+
+		for {
+			select {
+			case changes := <-changesCh:
+				for _, change := range changes {
+					notifier.Notify(rpcSub.ID, change)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
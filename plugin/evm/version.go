@@ -5,6 +5,8 @@ package evm
 
 import (
 	"fmt"
+
+	"github.com/ava-labs/avalanchego/version"
 )
 
 var (
@@ -12,6 +14,16 @@ var (
 	GitCommit string
 	// Version is the version of Subnet EVM
 	Version string = "v0.5.6"
+
+	// Features lists the optional capabilities of this VM, so that orchestration
+	// tooling can check for the ones it depends on.
+	Features = []string{
+		"build-block-with-context",
+		"cross-chain-app-requests",
+		"height-index",
+		"state-sync",
+		"warp",
+	}
 )
 
 func init() {
@@ -19,3 +31,44 @@ func init() {
 		Version = fmt.Sprintf("%s@%s", Version, GitCommit)
 	}
 }
+
+// VersionInfo describes the compatibility of this VM with avalanchego.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit,omitempty"`
+	// RPCChainVMProtocol is the version of the protocol between avalanchego and
+	// this plugin. avalanchego refuses to run plugins with a different version.
+	RPCChainVMProtocol uint `json:"rpcChainVMProtocol"`
+	// AvalancheGoVersion is the version of avalanchego this VM is built against,
+	// and CompatibleAvalancheGoVersions are the versions of avalanchego that use
+	// [RPCChainVMProtocol].
+	AvalancheGoVersion            string   `json:"avalanchegoVersion"`
+	CompatibleAvalancheGoVersions []string `json:"compatibleAvalanchegoVersions"`
+	Features                      []string `json:"features"`
+}
+
+// GetVersionInfo returns the VersionInfo of this VM.
+func GetVersionInfo() VersionInfo {
+	compatible := version.RPCChainVMProtocolCompatibility[version.RPCChainVMProtocol]
+	compatibleVersions := make([]string, len(compatible))
+	for i, v := range compatible {
+		compatibleVersions[i] = v.String()
+	}
+	return VersionInfo{
+		Version:                       Version,
+		GitCommit:                     GitCommit,
+		RPCChainVMProtocol:            version.RPCChainVMProtocol,
+		AvalancheGoVersion:            version.Current.String(),
+		CompatibleAvalancheGoVersions: compatibleVersions,
+		Features:                      Features,
+	}
+}
+
+// CheckRPCChainVMProtocol returns an error if avalanchego with RPCChainVM
+// protocol version [protocol] cannot run this VM.
+func CheckRPCChainVMProtocol(protocol uint) error {
+	if protocol != version.RPCChainVMProtocol {
+		return fmt.Errorf("RPCChainVM protocol mismatch: avalanchego uses %d, subnet-evm %s uses %d", protocol, Version, version.RPCChainVMProtocol)
+	}
+	return nil
+}
@@ -27,3 +27,22 @@ func TestCompatibility(t *testing.T) {
 	assert.True(t, valueInJSON)
 	assert.Equal(t, rpcChainVMVersion, version.RPCChainVMProtocol)
 }
+
+func TestVersionInfo(t *testing.T) {
+	info := GetVersionInfo()
+	assert.Equal(t, Version, info.Version)
+	assert.Equal(t, version.RPCChainVMProtocol, info.RPCChainVMProtocol)
+	assert.Contains(t, info.CompatibleAvalancheGoVersions, version.Current.String())
+
+	assert.NoError(t, CheckRPCChainVMProtocol(version.RPCChainVMProtocol))
+	assert.Error(t, CheckRPCChainVMProtocol(version.RPCChainVMProtocol+1))
+
+	reply := &CheckCompatibilityReply{}
+	err := CreateStaticService().CheckCompatibility(nil, &CheckCompatibilityArgs{
+		RPCChainVMProtocol: version.RPCChainVMProtocol,
+		Features:           []string{"warp", "unknown-feature"},
+	}, reply)
+	assert.NoError(t, err)
+	assert.False(t, reply.Compatible)
+	assert.Equal(t, []string{"unknown-feature"}, reply.MissingFeatures)
+}
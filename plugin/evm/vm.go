@@ -5,14 +5,17 @@ package evm
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	avalanchegoMetrics "github.com/ava-labs/avalanchego/api/metrics"
@@ -39,6 +42,7 @@ import (
 	"github.com/ava-labs/subnet-evm/rpc"
 	statesyncclient "github.com/ava-labs/subnet-evm/sync/client"
 	"github.com/ava-labs/subnet-evm/sync/client/stats"
+	syncHandlers "github.com/ava-labs/subnet-evm/sync/handlers"
 	"github.com/ava-labs/subnet-evm/trie"
 	"github.com/ava-labs/subnet-evm/warp"
 	"github.com/ava-labs/subnet-evm/warp/aggregator"
@@ -72,12 +76,14 @@ import (
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	cjson "github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/components/chain"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
 
 	commonEng "github.com/ava-labs/avalanchego/snow/engine/common"
 
@@ -154,6 +160,7 @@ var (
 	errNilBaseFeeSubnetEVM           = errors.New("nil base fee is invalid after subnetEVM")
 	errNilBlockGasCostSubnetEVM      = errors.New("nil blockGasCost is invalid after subnetEVM")
 	errInvalidHeaderPredicateResults = errors.New("invalid header predicate results")
+	errShuttingDown                  = errors.New("VM is shutting down")
 )
 
 // legacyApiNames maps pre geth v1.10.20 api names to their updated counterparts.
@@ -227,6 +234,10 @@ type VM struct {
 	shutdownChan chan struct{}
 	shutdownWg   sync.WaitGroup
 
+	// following is true once the VM tracks the accepted chain of [config.FollowerRPCURL]
+	// instead of blocks from consensus
+	following bool
+
 	// Continuous Profiler
 	profiler profiler.ContinuousProfiler
 
@@ -251,6 +262,27 @@ type VM struct {
 	// Avalanche Warp Messaging backend
 	// Used to serve BLS signatures of warp messages over RPC
 	warpBackend warp.Backend
+	// Signs the validator set of the subnet for light clients, nil if disabled
+	warpValidatorSets *warp.ValidatorSetSigner
+	// Sends changes to the validator set of the subnet to API subscribers, nil if disabled
+	validatorEvents *validatorEvents
+
+	// Raises alarms on deep reorgs and accept failures
+	alarms *alarms
+
+	// Aggregates the gas used by contracts over recent blocks, nil if disabled
+	gasAnalytics *gasAnalytics
+
+	// rpcHandler serves the eth APIs, and is drained on shutdown
+	rpcHandler *rpc.Server
+	// buildLock is held while building a block, so that shutdown waits for the
+	// block being built. Blocks are not built once [shuttingDown] is set.
+	buildLock    sync.Mutex
+	shuttingDown atomic.Bool
+
+	// consensusWork counts the blocks being verified, built or accepted, so that
+	// serving state sync requests yields to them.
+	consensusWork atomic.Int32
 }
 
 // Initialize implements the snowman.ChainVM interface
@@ -274,6 +306,7 @@ func (vm *VM) Initialize(
 	if err := vm.config.Validate(); err != nil {
 		return err
 	}
+	vm.config.ApplyAPIProfile()
 
 	vm.ctx = chainCtx
 
@@ -313,6 +346,22 @@ func (vm *VM) Initialize(
 	// the last accepted block.
 	vm.warpDB = prefixdb.New(warpPrefix, baseDB)
 
+	// A database without a last accepted block has just been created, so it is
+	// already in the latest schema.
+	hasLastAccepted, err := vm.acceptedBlockDB.Has(lastAcceptedKey)
+	if err != nil {
+		return fmt.Errorf("failed to read last accepted block: %w", err)
+	}
+	migrator := &schemaMigrator{
+		chaindb:    vm.chaindb,
+		metadataDB: vm.metadataDB,
+		commit:     vm.db.Commit,
+		migrations: schemaMigrations,
+	}
+	if err := migrator.migrate(!hasLastAccepted); err != nil {
+		return err
+	}
+
 	if vm.config.InspectDatabase {
 		start := time.Now()
 		log.Info("Starting database inspection")
@@ -322,8 +371,24 @@ func (vm *VM) Initialize(
 		log.Info("Completed database inspection", "elapsed", time.Since(start))
 	}
 
+	receiptCompression, err := rawdb.ParseReceiptCompression(vm.config.ReceiptCompression)
+	if err != nil {
+		return err
+	}
+	if vm.config.MigrateReceiptCompression {
+		if _, err := rawdb.RecompressReceipts(vm.chaindb, receiptCompression, nil); err != nil {
+			return fmt.Errorf("failed to migrate receipt compression: %w", err)
+		}
+	}
+
 	g := new(core.Genesis)
-	if err := json.Unmarshal(genesisBytes, g); err != nil {
+	if vm.config.DevGenesis {
+		if _, enforce := getMandatoryNetworkUpgrades(chainCtx.NetworkID); enforce {
+			return errDevGenesisOnPublicNetwork
+		}
+		log.Warn("Using dev genesis, which must not be used outside of local development", "chainID", DevChainID, "admin", DevAdminAddress)
+		g = NewDevGenesis()
+	} else if err := json.Unmarshal(genesisBytes, g); err != nil {
 		return err
 	}
 
@@ -390,7 +455,23 @@ func (vm *VM) Initialize(
 	// gas price to prevent so transactions and blocks all use the correct fees
 	vm.ethConfig.RPCGasCap = vm.config.RPCGasCap
 	vm.ethConfig.RPCEVMTimeout = vm.config.APIMaxDuration.Duration
+	if vm.config.APICallTimeout.Duration > 0 {
+		vm.ethConfig.RPCEVMTimeout = vm.config.APICallTimeout.Duration
+	}
+	vm.ethConfig.RPCTraceTimeout = vm.config.APITraceTimeout.Duration
+	vm.ethConfig.RPCLogsTimeout = vm.config.APILogsTimeout.Duration
+	vm.ethConfig.RPCResumableFilterTTL = vm.config.ResumableFilterTTL.Duration
+	vm.ethConfig.RPCMaxResumableFilters = vm.config.MaxResumableFilters
 	vm.ethConfig.RPCTxFeeCap = vm.config.RPCTxFeeCap
+	vm.ethConfig.RPCGasEstimateMultiplier = vm.config.RPCGasEstimateMultiplier
+	vm.ethConfig.RPCGasEstimatePadding = vm.config.RPCGasEstimatePadding
+	vm.ethConfig.HistoricalProofQueryWindow = vm.config.HistoricalProofQueryWindow
+
+	vm.ethConfig.GPO.Blocks = vm.config.GasPriceOracleBlocks
+	vm.ethConfig.GPO.Percentile = vm.config.GasPriceOraclePercentile
+	vm.ethConfig.GPO.MaxPrice = new(big.Int).SetUint64(vm.config.GasPriceOracleMaxPrice)
+	vm.ethConfig.GPO.Mode = vm.config.GasPriceOracleMode
+	vm.ethConfig.GPO.CongestionThreshold = vm.config.GasPriceOracleCongestionThreshold
 
 	vm.ethConfig.TxPool.Locals = vm.config.PriorityRegossipAddresses
 	vm.ethConfig.TxPool.NoLocals = !vm.config.LocalTxsEnabled
@@ -402,6 +483,8 @@ func (vm *VM) Initialize(
 	vm.ethConfig.TxPool.GlobalSlots = vm.config.TxPoolGlobalSlots
 	vm.ethConfig.TxPool.AccountQueue = vm.config.TxPoolAccountQueue
 	vm.ethConfig.TxPool.GlobalQueue = vm.config.TxPoolGlobalQueue
+	vm.ethConfig.TxPool.Lifetime = vm.config.TxPoolLifetime.Duration
+	vm.ethConfig.TxPool.MaxNonceGap = vm.config.TxPoolMaxNonceGap
 
 	vm.ethConfig.AllowUnfinalizedQueries = vm.config.AllowUnfinalizedQueries
 	vm.ethConfig.AllowUnprotectedTxs = vm.config.AllowUnprotectedTxs
@@ -414,7 +497,10 @@ func (vm *VM) Initialize(
 	vm.ethConfig.TrieDirtyCache = vm.config.TrieDirtyCache
 	vm.ethConfig.TrieDirtyCommitTarget = vm.config.TrieDirtyCommitTarget
 	vm.ethConfig.SnapshotCache = vm.config.SnapshotCache
+	vm.ethConfig.SnapshotCacheJournal = vm.config.SnapshotCacheJournal
 	vm.ethConfig.AcceptorQueueLimit = vm.config.AcceptorQueueLimit
+	vm.ethConfig.AcceptorIndexingParallelism = vm.config.AcceptedIndexingParallelism
+	vm.ethConfig.AcceptedEventBuffer = vm.config.AcceptedEventBuffer
 	vm.ethConfig.PopulateMissingTries = vm.config.PopulateMissingTries
 	vm.ethConfig.PopulateMissingTriesParallelism = vm.config.PopulateMissingTriesParallelism
 	vm.ethConfig.AllowMissingTries = vm.config.AllowMissingTries
@@ -428,6 +514,7 @@ func (vm *VM) Initialize(
 	vm.ethConfig.SkipUpgradeCheck = vm.config.SkipUpgradeCheck
 	vm.ethConfig.AcceptedCacheSize = vm.config.AcceptedCacheSize
 	vm.ethConfig.TxLookupLimit = vm.config.TxLookupLimit
+	vm.ethConfig.ReceiptCompression = receiptCompression
 
 	// Create directory for offline pruning
 	if len(vm.ethConfig.OfflinePruningDataDirectory) != 0 {
@@ -446,6 +533,7 @@ func (vm *VM) Initialize(
 		log.Info("Config has not specified any coinbase address. Defaulting to the blackhole address.")
 		vm.ethConfig.Miner.Etherbase = constants.BlackholeAddr
 	}
+	vm.ethConfig.Miner.TxOrdering = vm.config.TxOrdering
 
 	vm.chainConfig = g.Config
 	vm.networkID = vm.ethConfig.NetworkId
@@ -462,6 +550,10 @@ func (vm *VM) Initialize(
 	if err := vm.initializeMetrics(); err != nil {
 		return err
 	}
+	vm.alarms = newAlarms(vm.config.AlarmWebhookURL)
+	if vm.config.ContractGasAnalyticsWindow > 0 {
+		vm.gasAnalytics = newGasAnalytics(vm.config.ContractGasAnalyticsWindow)
+	}
 
 	// initialize peer network
 	vm.validators = p2p.NewValidators(vm.ctx.Log, vm.ctx.SubnetID, vm.ctx.ValidatorState, maxValidatorSetStaleness)
@@ -471,7 +563,20 @@ func (vm *VM) Initialize(
 	vm.client = peer.NewNetworkClient(vm.Network)
 
 	// initialize warp backend
-	vm.warpBackend = warp.NewBackend(vm.ctx.WarpSigner, vm.warpDB, warpSignatureCacheSize)
+	warpSigner := vm.ctx.WarpSigner
+	if vm.config.WarpNextBLSKeyFile != "" {
+		warpSigner, err = vm.newRotatingWarpSigner()
+		if err != nil {
+			return err
+		}
+	}
+	vm.warpBackend = warp.NewBackend(warpSigner, vm.warpDB, warpSignatureCacheSize)
+	if vm.config.WarpValidatorSetInterval.Duration > 0 {
+		vm.warpValidatorSets = warp.NewValidatorSetSigner(vm.warpBackend, vm.ctx.ValidatorState, vm.ctx.NetworkID, vm.ctx.ChainID, vm.ctx.SubnetID, vm.config.WarpValidatorSetEpoch)
+	}
+	if vm.config.ValidatorEventsInterval.Duration > 0 {
+		vm.validatorEvents = newValidatorEvents(vm.ctx.ValidatorState, vm.ctx.SubnetID)
+	}
 
 	// clear warpdb on initialization if config enabled
 	if vm.config.PruneWarpDB {
@@ -510,11 +615,37 @@ func (vm *VM) initializeMetrics() error {
 	return nil
 }
 
+// newRotatingWarpSigner returns a warp signer that rotates from the staking BLS key
+// of this node to the key in [WarpNextBLSKeyFile].
+func (vm *VM) newRotatingWarpSigner() (avalancheWarp.Signer, error) {
+	skBytes, err := os.ReadFile(vm.config.WarpNextBLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read warp next BLS key file: %w", err)
+	}
+	nextSK, err := bls.SecretKeyFromBytes(skBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse warp next BLS key: %w", err)
+	}
+	activation := time.Unix(int64(vm.config.WarpNextBLSKeyActivation), 0)
+	log.Info("Configured warp BLS key rotation", "activation", activation)
+	return warp.NewRotatingSigner(
+		vm.ctx.WarpSigner,
+		vm.ctx.PublicKey,
+		avalancheWarp.NewSigner(nextSK, vm.ctx.NetworkID, vm.ctx.ChainID),
+		bls.PublicFromSecretKey(nextSK),
+		activation,
+		vm.ctx.ValidatorState,
+		vm.ctx.NodeID,
+		vm.ctx.SubnetID,
+	), nil
+}
+
 func (vm *VM) initializeChain(lastAcceptedHash common.Hash, ethConfig ethconfig.Config) error {
 	nodecfg := &node.Config{
 		SubnetEVMVersion:      Version,
 		KeyStoreDir:           vm.config.KeystoreDirectory,
 		ExternalSigner:        vm.config.KeystoreExternalSigner,
+		ExternalSignerPolicy:  vm.config.KeystoreExternalSignerPolicy,
 		InsecureUnlockAllowed: vm.config.KeystoreInsecureUnlockAllowed,
 	}
 	node, err := node.New(nodecfg)
@@ -561,6 +692,7 @@ func (vm *VM) initializeStateSyncClient(lastAcceptedHeight uint64) error {
 		}
 	}
 
+	peerStats := stats.NewPeerStats()
 	vm.StateSyncClient = NewStateSyncClient(&stateSyncClientConfig{
 		chain: vm.eth,
 		state: vm.State,
@@ -571,18 +703,22 @@ func (vm *VM) initializeStateSyncClient(lastAcceptedHeight uint64) error {
 				Stats:            stats.NewClientSyncerStats(),
 				StateSyncNodeIDs: stateSyncIDs,
 				BlockParser:      vm,
+				PeerStats:        peerStats,
 			},
 		),
-		enabled:              vm.config.StateSyncEnabled,
-		skipResume:           vm.config.StateSyncSkipResume,
-		stateSyncMinBlocks:   vm.config.StateSyncMinBlocks,
-		stateSyncRequestSize: vm.config.StateSyncRequestSize,
-		lastAcceptedHeight:   lastAcceptedHeight, // TODO clean up how this is passed around
-		chaindb:              vm.chaindb,
-		metadataDB:           vm.metadataDB,
-		acceptedBlockDB:      vm.acceptedBlockDB,
-		db:                   vm.db,
-		toEngine:             vm.toEngine,
+		peerStats:                        peerStats,
+		enabled:                          vm.config.StateSyncEnabled,
+		skipResume:                       vm.config.StateSyncSkipResume,
+		stateSyncMinBlocks:               vm.config.StateSyncMinBlocks,
+		stateSyncRequestSize:             vm.config.StateSyncRequestSize,
+		stateSyncBlockRequestSize:        vm.config.StateSyncBlockRequestSize,
+		stateSyncBlockRequestConcurrency: vm.config.StateSyncBlockRequestConcurrency,
+		lastAcceptedHeight:               lastAcceptedHeight, // TODO clean up how this is passed around
+		chaindb:                          vm.chaindb,
+		metadataDB:                       vm.metadataDB,
+		acceptedBlockDB:                  vm.acceptedBlockDB,
+		db:                               vm.db,
+		toEngine:                         vm.toEngine,
 	})
 
 	// If StateSync is disabled, clear any ongoing summary so that we will not attempt to resume
@@ -645,6 +781,38 @@ func (vm *VM) SetState(_ context.Context, state snow.State) error {
 		}
 		return nil
 	case snow.NormalOp:
+		if vm.validatorEvents != nil {
+			vm.shutdownWg.Add(1)
+			go vm.emitValidatorEvents()
+		}
+		if vm.warpValidatorSets != nil {
+			vm.shutdownWg.Add(1)
+			go vm.signWarpValidatorSets()
+		}
+		if vm.config.ReorgAlarmDepth > 0 {
+			vm.shutdownWg.Add(1)
+			go vm.watchReorgs()
+		}
+		if vm.config.MemoryCeiling > 0 {
+			vm.shutdownWg.Add(1)
+			go vm.monitorMemory()
+		}
+		if vm.config.TrieIntegrityCheckInterval.Duration > 0 {
+			vm.shutdownWg.Add(1)
+			go vm.checkTrieIntegrity()
+		}
+		if vm.gasAnalytics != nil {
+			vm.shutdownWg.Add(1)
+			go vm.trackContractGas()
+		}
+		if vm.config.FollowerEnabled() {
+			// Followers do not build blocks or handle gossip, so block building is not initialized.
+			if err := vm.startFollower(); err != nil {
+				return fmt.Errorf("failed to start follower: %w", err)
+			}
+			vm.bootstrapped = true
+			return nil
+		}
 		// Initialize goroutines related to block building once we enter normal operation as there is no need to handle mempool gossip before this point.
 		if err := vm.initBlockBuilding(); err != nil {
 			return fmt.Errorf("failed to initialize block building: %w", err)
@@ -666,7 +834,11 @@ func (vm *VM) initBlockBuilding() error {
 	vm.gossiper = vm.createGossiper(gossipStats)
 	vm.builder = vm.NewBlockBuilder(vm.toEngine)
 	vm.builder.awaitSubmittedTxs()
-	vm.Network.SetGossipHandler(NewGossipHandler(vm, gossipStats))
+	var gossipHandler message.GossipHandler = NewGossipHandler(vm, gossipStats)
+	if faults := vm.config.FaultInjection; faults != nil && faults.Gossip.enabled() {
+		gossipHandler = newFaultyGossipHandler(gossipHandler, faults.Gossip)
+	}
+	vm.Network.SetGossipHandler(gossipHandler)
 
 	txPool, err := NewGossipTxPool(vm.txPool)
 	if err != nil {
@@ -686,6 +858,9 @@ func (vm *VM) initBlockBuilding() error {
 	if err != nil {
 		return err
 	}
+	if faults := vm.config.FaultInjection; faults != nil && faults.Gossip.enabled() {
+		txGossipHandler = newFaultyP2PHandler(txGossipHandler, faults.Gossip)
+	}
 	txGossipHandler = &p2p.ValidatorHandler{
 		ValidatorSet: vm.validators,
 		Handler: &p2p.ThrottlerHandler{
@@ -736,7 +911,17 @@ func (vm *VM) setAppRequestHandlers() {
 		},
 	)
 
-	networkHandler := newNetworkHandler(vm.blockChain, vm.chaindb, evmTrieDB, vm.warpBackend, vm.networkCodec)
+	throttleConfig := syncHandlers.ThrottleConfig{
+		PeerRequestsPerSecond:          vm.config.StateSyncServerPeerRequestRate,
+		PeerRequestBurst:               vm.config.StateSyncServerPeerRequestBurst,
+		MaxConcurrentRequests:          vm.config.StateSyncServerMaxConcurrentRequests,
+		MaxConcurrentRequestsWhileBusy: vm.config.StateSyncServerMaxConcurrentRequestsBusy,
+		Busy:                           vm.consensusBusy,
+	}
+	networkHandler := newNetworkHandler(vm.blockChain, vm.chaindb, evmTrieDB, vm.warpBackend, vm.networkCodec, throttleConfig)
+	if faults := vm.config.FaultInjection; faults != nil && faults.SignatureResponses.enabled() {
+		networkHandler = newFaultyRequestHandler(networkHandler, faults.SignatureResponses, vm.networkCodec)
+	}
 	vm.Network.SetRequestHandler(networkHandler)
 }
 
@@ -752,6 +937,23 @@ func (vm *VM) Shutdown(context.Context) error {
 	if vm.ctx == nil {
 		return nil
 	}
+	// Stop serving new RPC requests first, and give the ones in flight time to
+	// finish, so that clients are not disconnected mid-request.
+	if vm.rpcHandler != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), vm.config.ShutdownTimeout.Duration)
+		if err := vm.rpcHandler.Drain(ctx); err != nil {
+			log.Warn("Abandoned in-flight RPC requests on shutdown", "timeout", vm.config.ShutdownTimeout.Duration, "err", err)
+		}
+		cancel()
+	}
+	// Let the block being built complete, and abandon any further builds.
+	vm.shuttingDown.Store(true)
+	vm.buildLock.Lock()
+	if vm.builder != nil {
+		vm.builder.stop()
+	}
+	vm.buildLock.Unlock()
+
 	if vm.cancel != nil {
 		vm.cancel()
 	}
@@ -760,6 +962,13 @@ func (vm *VM) Shutdown(context.Context) error {
 		log.Error("error stopping state syncer", "err", err)
 	}
 	close(vm.shutdownChan)
+	// Process the accepted blocks still queued, so that their side effects are
+	// written before the blockchain stops.
+	if vm.blockChain != nil {
+		start := time.Now()
+		vm.blockChain.DrainAcceptorQueue()
+		log.Info("Acceptor queue flushed", "elapsed", time.Since(start))
+	}
 	vm.eth.Stop()
 	log.Info("Ethereum backend stop completed")
 	vm.shutdownWg.Wait()
@@ -767,11 +976,30 @@ func (vm *VM) Shutdown(context.Context) error {
 	return nil
 }
 
+// beginConsensusWork marks consensus work in progress until the returned function
+// is called.
+func (vm *VM) beginConsensusWork() func() {
+	vm.consensusWork.Add(1)
+	return func() { vm.consensusWork.Add(-1) }
+}
+
+// consensusBusy returns whether consensus work is in progress.
+func (vm *VM) consensusBusy() bool {
+	return vm.consensusWork.Load() > 0
+}
+
 func (vm *VM) buildBlock(ctx context.Context) (snowman.Block, error) {
 	return vm.buildBlockWithContext(ctx, nil)
 }
 
 func (vm *VM) buildBlockWithContext(ctx context.Context, proposerVMBlockCtx *block.Context) (snowman.Block, error) {
+	defer vm.beginConsensusWork()()
+	vm.buildLock.Lock()
+	defer vm.buildLock.Unlock()
+
+	if vm.shuttingDown.Load() {
+		return nil, errShuttingDown
+	}
 	if proposerVMBlockCtx != nil {
 		log.Debug("Building block with context", "pChainBlockHeight", proposerVMBlockCtx.PChainHeight)
 	} else {
@@ -913,9 +1141,56 @@ func newHandler(name string, service interface{}, lockOption ...commonEng.LockOp
 	return &commonEng.HTTPHandler{LockOptions: lock, Handler: server}, nil
 }
 
+// newJWTAuthenticator returns an authenticator verifying JWTs signed with the hex
+// encoded secret stored in the file at [secretPath].
+func newJWTAuthenticator(secretPath string) (func(http.Header) error, error) {
+	secretBytes, err := os.ReadFile(secretPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt secret: %w", err)
+	}
+	secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(secretBytes)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode jwt secret: %w", err)
+	}
+	return rpc.NewJWTAuthenticator(secret)
+}
+
 // CreateHandlers makes new http handlers that can handle API calls
 func (vm *VM) CreateHandlers(context.Context) (map[string]*commonEng.HTTPHandler, error) {
 	handler := rpc.NewServer(vm.config.APIMaxDuration.Duration)
+	vm.rpcHandler = handler
+	handler.SetWebsocketLimits(rpc.WebsocketLimits{
+		MaxConnections:                vm.config.WSMaxConnections,
+		MaxSubscriptionsPerConnection: vm.config.WSMaxSubscriptions,
+		MaxPendingNotifications:       vm.config.WSMaxPendingNotifications,
+	})
+	if len(vm.config.APIAuthJWTSecret) > 0 {
+		authenticate, err := newJWTAuthenticator(vm.config.APIAuthJWTSecret)
+		if err != nil {
+			return nil, err
+		}
+		handler.SetAuthentication(authenticate, vm.config.APIAuthNamespaces)
+		log.Info("Enabled JWT authentication", "namespaces", vm.config.APIAuthNamespaces)
+	}
+	for namespace, policy := range vm.config.APINamespacePolicies {
+		accessPolicy, err := policy.AccessPolicy()
+		if err != nil {
+			return nil, err
+		}
+		handler.SetAccessPolicy(namespace, accessPolicy)
+	}
+	quotas, err := vm.config.APIQuotas()
+	if err != nil {
+		return nil, err
+	}
+	if quotas != nil {
+		handler.SetQuotas(quotas)
+		log.Info("Enabled API key quotas", "keys", len(vm.config.APIKeyQuotas))
+	}
+	if vm.config.APISlowQueryThreshold.Duration > 0 {
+		handler.SetSlowQueryThreshold(vm.config.APISlowQueryThreshold.Duration)
+		log.Info("Enabled slow RPC call logging", "threshold", vm.config.APISlowQueryThreshold.Duration)
+	}
 	enabledAPIs := vm.config.EthAPIs()
 	if err := attachEthService(handler, vm.eth.APIs(), enabledAPIs); err != nil {
 		return nil, err
@@ -942,14 +1217,69 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]*commonEng.HTTPHandler
 		enabledAPIs = append(enabledAPIs, "snowman")
 	}
 
+	if vm.config.IndexAPIEnabled {
+		if err := handler.RegisterName("index", &IndexAPI{vm}); err != nil {
+			return nil, err
+		}
+		enabledAPIs = append(enabledAPIs, "index")
+	}
+
+	if vm.gasAnalytics != nil {
+		if err := handler.RegisterName("analytics", &AnalyticsAPI{vm}); err != nil {
+			return nil, err
+		}
+		enabledAPIs = append(enabledAPIs, "analytics")
+	}
+
+	if vm.config.StateSyncAPIEnabled {
+		if err := handler.RegisterName("statesync", &StateSyncAPI{vm}); err != nil {
+			return nil, err
+		}
+		enabledAPIs = append(enabledAPIs, "statesync")
+	}
+
+	if vm.config.ValidatorsAPIEnabled {
+		if err := handler.RegisterName("validators", &ValidatorsAPI{vm}); err != nil {
+			return nil, err
+		}
+		enabledAPIs = append(enabledAPIs, "validators")
+	}
+
 	if vm.config.WarpAPIEnabled {
-		warpAggregator := aggregator.New(vm.ctx.SubnetID, warpValidators.NewState(vm.ctx), &aggregator.NetworkSigner{Client: vm.client})
-		if err := handler.RegisterName("warp", warp.NewAPI(vm.warpBackend, warpAggregator)); err != nil {
+		signatureGetter, err := vm.warpSignatureGetter()
+		if err != nil {
+			return nil, err
+		}
+		warpValidatorState := warpValidators.NewState(vm.ctx)
+		warpAggregator := aggregator.NewWithConfig(vm.ctx.SubnetID, warpValidatorState, signatureGetter, aggregator.Config{
+			MaxConcurrentRequests: vm.config.WarpAggregationMaxConcurrentRequests,
+			RequestInterval:       vm.config.WarpAggregationRequestInterval.Duration,
+		})
+		if err := handler.RegisterName("warp", warp.NewAPI(vm.ctx.NetworkID, vm.ctx.ChainID, vm.ctx.SubnetID, vm.warpBackend, warpValidatorState, warpAggregator, vm.warpValidatorSets)); err != nil {
 			return nil, err
 		}
 		enabledAPIs = append(enabledAPIs, "warp")
 	}
 
+	if vm.config.WarpSignHTTPEnabled {
+		// Serve warp signatures over plain HTTP to trusted relayers holding a token
+		// signed with the API auth secret.
+		authenticate, err := newJWTAuthenticator(vm.config.APIAuthJWTSecret)
+		if err != nil {
+			return nil, err
+		}
+		apis[warp.SignHTTPPath] = &commonEng.HTTPHandler{
+			LockOptions: commonEng.NoLock,
+			Handler:     warp.NewSignHandler(vm.warpBackend, authenticate),
+		}
+		log.Info("Enabled warp sign HTTP endpoint", "path", warp.SignHTTPPath)
+	}
+
+	if !vm.config.RPCEnabled() {
+		log.Info("RPC and WebSocket endpoints disabled", "apiProfile", vm.config.APIProfile)
+		return apis, nil
+	}
+
 	log.Info(fmt.Sprintf("Enabled APIs: %s", strings.Join(enabledAPIs, ", ")))
 	apis[ethRPCEndpoint] = &commonEng.HTTPHandler{
 		LockOptions: commonEng.NoLock,
@@ -968,6 +1298,29 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]*commonEng.HTTPHandler
 	return apis, nil
 }
 
+// warpSignatureGetter returns the SignatureGetter used to aggregate warp signatures.
+// Signatures are fetched over p2p, falling back to the public warp APIs of any
+// validators in [WarpSignatureEndpoints].
+func (vm *VM) warpSignatureGetter() (aggregator.SignatureGetter, error) {
+	networkSigner := aggregator.NewNetworkSigner(vm.client)
+	endpoints, err := vm.config.WarpSignatureEndpointsByNodeID()
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return networkSigner, nil
+	}
+	clients := make(map[ids.NodeID]warp.Client, len(endpoints))
+	for nodeID, uri := range endpoints {
+		client, err := warp.NewClient(uri, vm.ctx.ChainID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create warp client for %s: %w", nodeID, err)
+		}
+		clients[nodeID] = client
+	}
+	return aggregator.NewFallbackSigner(networkSigner, warp.NewAPIFetcher(clients), vm.config.WarpSignatureFallbackDelay.Duration), nil
+}
+
 // CreateStaticHandlers makes new http handlers that can handle API calls
 func (vm *VM) CreateStaticHandlers(context.Context) (map[string]*commonEng.HTTPHandler, error) {
 	server := avalancheRPC.NewServer()
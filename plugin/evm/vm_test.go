@@ -515,6 +515,104 @@ func TestBuildEthTxBlock(t *testing.T) {
 	}
 }
 
+func TestGetAcceptanceStatus(t *testing.T) {
+	require := require.New(t)
+	issuer, vm, _, _ := GenesisVM(t, true, genesisJSONSubnetEVM, "", "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+	api := &SnowmanAPI{vm}
+
+	tx := types.NewTransaction(uint64(0), testEthAddrs[1], firstTxAmount, 21000, big.NewInt(testMinGasPrice), nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(vm.chainConfig.ChainID), testKeys[0])
+	require.NoError(err)
+	status, err := api.GetAcceptanceStatus(context.Background(), signedTx.Hash())
+	require.NoError(err)
+	require.Nil(status, "pending transaction should not have a status")
+
+	for _, err := range vm.txPool.AddRemotesSync([]*types.Transaction{signedTx}) {
+		require.NoError(err)
+	}
+	blk1 := issueAndAccept(t, issuer, vm)
+	vm.blockChain.DrainAcceptorQueue()
+
+	status, err = api.GetAcceptanceStatus(context.Background(), signedTx.Hash())
+	require.NoError(err)
+	require.NotNil(status)
+	require.Equal(common.Hash(blk1.ID()), status.BlockHash)
+	require.Equal(uint64(1), status.BlockNumber)
+	require.True(status.Accepted)
+	require.Zero(status.Depth)
+	require.NotNil(status.AcceptedAt)
+
+	// Build a second block, which increases the depth of the first.
+	tx = types.NewTransaction(uint64(1), testEthAddrs[1], firstTxAmount, 21000, big.NewInt(testMinGasPrice), nil)
+	signedTx, err = types.SignTx(tx, types.NewEIP155Signer(vm.chainConfig.ChainID), testKeys[0])
+	require.NoError(err)
+	for _, err := range vm.txPool.AddRemotesSync([]*types.Transaction{signedTx}) {
+		require.NoError(err)
+	}
+	vm.clock.Set(vm.clock.Time().Add(2 * time.Second))
+	<-issuer
+	blk2, err := vm.BuildBlock(context.Background())
+	require.NoError(err)
+	require.NoError(blk2.Verify(context.Background()))
+
+	status, err = api.GetAcceptanceStatus(context.Background(), common.Hash(blk2.ID()))
+	require.NoError(err)
+	require.NotNil(status)
+	require.False(status.Accepted, "verified block should not be accepted")
+
+	require.NoError(blk2.Accept(context.Background()))
+	vm.blockChain.DrainAcceptorQueue()
+	status, err = api.GetAcceptanceStatus(context.Background(), common.Hash(blk1.ID()))
+	require.NoError(err)
+	require.True(status.Accepted)
+	require.Equal(uint64(1), status.Depth)
+}
+
+func TestIndexAPI(t *testing.T) {
+	require := require.New(t)
+	issuer, vm, _, _ := GenesisVM(t, true, genesisJSONSubnetEVM, "", "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+	api := &IndexAPI{vm}
+
+	tx := types.NewTransaction(uint64(0), testEthAddrs[1], firstTxAmount, 21000, big.NewInt(testMinGasPrice), nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(vm.chainConfig.ChainID), testKeys[0])
+	require.NoError(err)
+	for _, err := range vm.txPool.AddRemotesSync([]*types.Transaction{signedTx}) {
+		require.NoError(err)
+	}
+	blk1 := issueAndAccept(t, issuer, vm)
+	vm.blockChain.DrainAcceptorQueue()
+
+	genesis, err := api.GetContainerByIndex(context.Background(), 0)
+	require.NoError(err)
+	require.NoError(genesis.Verify())
+
+	indexed, err := api.GetContainerByIndex(context.Background(), 1)
+	require.NoError(err)
+	require.Equal(blk1.ID(), indexed.ID)
+	require.Equal(genesis.ID, indexed.ParentID)
+	require.Equal(blk1.Bytes(), []byte(indexed.Bytes))
+	require.NotNil(indexed.AcceptedAt)
+	require.NoError(indexed.Verify())
+
+	lastAccepted, err := api.GetLastAccepted(context.Background())
+	require.NoError(err)
+	require.Equal(indexed, lastAccepted)
+
+	_, err = api.GetContainerByIndex(context.Background(), 2)
+	require.ErrorIs(err, errBlockNotAccepted)
+
+	indexed.Height++
+	require.ErrorIs(indexed.Verify(), errBlockFieldMismatch)
+	indexed.ID = genesis.ID
+	require.ErrorIs(indexed.Verify(), errBlockIDMismatch)
+}
+
 // Regression test to ensure that after accepting block A
 // then calling SetPreference on block B (when it becomes preferred)
 // and the head of a longer chain (block D) does not corrupt the
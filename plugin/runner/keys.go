@@ -4,5 +4,7 @@
 package runner
 
 const (
-	versionKey = "version"
+	versionKey         = "version"
+	versionJSONKey     = "version-json"
+	checkRPCChainVMKey = "check-rpcchainvm-protocol"
 )
@@ -14,6 +14,8 @@ func subnetEVMFlagSet() *flag.FlagSet {
 	fs := flag.NewFlagSet("subnet-evm", flag.ContinueOnError)
 
 	fs.Bool(versionKey, false, "If true, print version and quit")
+	fs.Bool(versionJSONKey, false, "If true, print the version, RPCChainVM protocol and features as JSON and quit")
+	fs.Uint(checkRPCChainVMKey, 0, "If non-zero, exit with an error unless avalanchego with this RPCChainVM protocol version can run the plugin")
 
 	return fs
 }
@@ -32,14 +34,31 @@ func getViper() (*viper.Viper, error) {
 	return v, nil
 }
 
-func PrintVersion() (bool, error) {
+// Options are the command line options of the plugin binary.
+type Options struct {
+	PrintVersion     bool
+	PrintVersionJSON bool
+	// CheckRPCChainVMProtocol is the RPCChainVM protocol version to check
+	// compatibility with, or 0 to skip the check.
+	CheckRPCChainVMProtocol uint
+}
+
+func ParseOptions() (Options, error) {
 	v, err := getViper()
 	if err != nil {
-		return false, err
+		return Options{}, err
 	}
+	return Options{
+		PrintVersion:            v.GetBool(versionKey),
+		PrintVersionJSON:        v.GetBool(versionJSONKey),
+		CheckRPCChainVMProtocol: v.GetUint(checkRPCChainVMKey),
+	}, nil
+}
 
-	if v.GetBool(versionKey) {
-		return true, nil
+func PrintVersion() (bool, error) {
+	options, err := ParseOptions()
+	if err != nil {
+		return false, err
 	}
-	return false, nil
+	return options.PrintVersion, nil
 }
@@ -5,6 +5,7 @@ package runner
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -16,15 +17,31 @@ import (
 )
 
 func Run(versionStr string) {
-	printVersion, err := PrintVersion()
+	options, err := ParseOptions()
 	if err != nil {
 		fmt.Printf("couldn't get config: %s", err)
 		os.Exit(1)
 	}
-	if printVersion && versionStr != "" {
+	if options.PrintVersion && versionStr != "" {
 		fmt.Printf(versionStr)
 		os.Exit(0)
 	}
+	if options.PrintVersionJSON {
+		versionJSON, err := json.Marshal(evm.GetVersionInfo())
+		if err != nil {
+			fmt.Printf("couldn't marshal version: %s", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(versionJSON))
+		os.Exit(0)
+	}
+	if options.CheckRPCChainVMProtocol != 0 {
+		if err := evm.CheckRPCChainVMProtocol(options.CheckRPCChainVMProtocol); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if err := ulimit.Set(ulimit.DefaultFDLimit, logging.NoLog{}); err != nil {
 		fmt.Printf("failed to set fd limit correctly due to: %s", err)
 		os.Exit(1)
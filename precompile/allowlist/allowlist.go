@@ -46,6 +46,8 @@ var (
 	readAllowListSignature = contract.CalculateFunctionSelector("readAllowList(address)")
 	// Error returned when an invalid write is attempted
 	ErrCannotModifyAllowList = errors.New("cannot modify allow list")
+	// Error returned when temporary permissions would lapse immediately
+	ErrInvalidExpiry = errors.New("invalid expiry")
 )
 
 // GetAllowListStatus returns the allow list role of [address] for the precompile
@@ -130,6 +132,10 @@ func createAllowListRoleSetter(precompileAddr common.Address, role Role) contrac
 			return nil, remainingGas, fmt.Errorf("%w: modify address: %s, from role: %s, to role: %s", ErrCannotModifyAllowList, callerAddr, modifyStatus, role)
 		}
 		SetAllowListRole(stateDB, precompileAddr, modifyAddress, role)
+		// Assigning a role replaces any temporary permissions granted by setEnabledUntil.
+		if GetAllowListExpiry(stateDB, precompileAddr, modifyAddress) != 0 {
+			SetAllowListExpiry(stateDB, precompileAddr, modifyAddress, 0)
+		}
 		// Return an empty output and the remaining gas
 		return []byte{}, remainingGas, nil
 	}
@@ -149,7 +155,7 @@ func createReadAllowList(precompileAddr common.Address) contract.RunStatefulPrec
 		}
 
 		readAddress := common.BytesToAddress(input)
-		role := GetAllowListStatus(evm.GetStateDB(), precompileAddr, readAddress)
+		role := GetAllowListStatusAt(evm.GetStateDB(), precompileAddr, readAddress, evm.GetBlockContext().Timestamp())
 		roleBytes := common.Hash(role).Bytes()
 		return roleBytes, remainingGas, nil
 	}
@@ -175,7 +181,9 @@ func CreateAllowListFunctions(precompileAddr common.Address) []*contract.Statefu
 	setNone := contract.NewStatefulPrecompileFunction(setNoneSignature, createAllowListRoleSetter(precompileAddr, NoRole))
 	read := contract.NewStatefulPrecompileFunction(readAllowListSignature, createReadAllowList(precompileAddr))
 
-	return []*contract.StatefulPrecompileFunction{setAdmin, setManager, setEnabled, setNone, read}
+	functions := []*contract.StatefulPrecompileFunction{setAdmin, setManager, setEnabled, setNone, read}
+	functions = append(functions, CreateRoleSignatureFunctions(precompileAddr)...)
+	return append(functions, CreateThresholdFunctions(precompileAddr)...)
 }
 
 func isManagerRoleActivated(evm contract.AccessibleState) bool {
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/subnet-evm/precompile/contract"
 	"github.com/ava-labs/subnet-evm/precompile/modules"
 	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -17,9 +18,17 @@ var (
 	_ precompileconfig.Config = &dummyConfig{}
 	_ contract.Configurator   = &dummyConfigurator{}
 
-	dummyAddr = common.Address{1}
+	// dummyAddr is in a reserved range, so that the dummy module can be registered to
+	// execute threshold calls.
+	dummyAddr = common.HexToAddress("0x03000000000000000000000000000000000000ff")
 )
 
+func init() {
+	if err := modules.RegisterModule(testModule); err != nil {
+		panic(err)
+	}
+}
+
 type dummyConfig struct {
 	precompileconfig.Upgrade
 	AllowListConfig
@@ -65,6 +74,20 @@ func TestAllowListRun(t *testing.T) {
 	RunPrecompileWithAllowListTests(t, dummyModule, state.NewTestStateDB, nil)
 }
 
+func TestAllowListReadOnly(t *testing.T) {
+	dummyModule := modules.Module{
+		Address:      dummyAddr,
+		Contract:     CreateAllowListPrecompile(dummyAddr),
+		Configurator: &dummyConfigurator{},
+		ConfigKey:    "dummy",
+	}
+	testutils.RunReadOnlyTests(t, dummyModule, state.NewTestStateDB, testutils.ReadOnlyTest{
+		Caller:     TestAdminAddr,
+		BeforeHook: SetDefaultRoles(dummyAddr),
+		Functions:  ReadOnlyFunctions(t, nil),
+	})
+}
+
 func BenchmarkAllowList(b *testing.B) {
 	dummyModule := modules.Module{
 		Address:      dummyAddr,
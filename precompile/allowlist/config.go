@@ -11,13 +11,47 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-var ErrCannotAddManagersBeforeDUpgrade = fmt.Errorf("cannot add managers before DUpgrade")
+var (
+	ErrCannotAddManagersBeforeDUpgrade  = fmt.Errorf("cannot add managers before DUpgrade")
+	ErrCannotAddThresholdBeforeDUpgrade = fmt.Errorf("cannot add threshold admin before DUpgrade")
+)
+
+// ThresholdConfig specifies a threshold of signers that administer the precompile through
+// its threshold admin address.
+type ThresholdConfig struct {
+	Threshold uint64           `json:"threshold"` // number of signers required to execute a call
+	Signers   []common.Address `json:"signers"`   // addresses that can propose and confirm calls
+}
+
+// Equal returns true iff [other] has the same threshold and signers in the same order.
+func (c *ThresholdConfig) Equal(other *ThresholdConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.Threshold == other.Threshold && areEqualAddressLists(c.Signers, other.Signers)
+}
+
+// Verify returns an error if the threshold cannot be met by the signers.
+func (c *ThresholdConfig) Verify() error {
+	if c.Threshold == 0 || c.Threshold > uint64(len(c.Signers)) {
+		return fmt.Errorf("invalid threshold %d for %d signers", c.Threshold, len(c.Signers))
+	}
+	signers := make(map[common.Address]struct{}, len(c.Signers))
+	for _, signer := range c.Signers {
+		if _, ok := signers[signer]; ok {
+			return fmt.Errorf("duplicate address in threshold signers: %s", signer)
+		}
+		signers[signer] = struct{}{}
+	}
+	return nil
+}
 
 // AllowListConfig specifies the initial set of addresses with Admin or Enabled roles.
 type AllowListConfig struct {
 	AdminAddresses   []common.Address `json:"adminAddresses,omitempty"`   // initial admin addresses
 	ManagerAddresses []common.Address `json:"managerAddresses,omitempty"` // initial manager addresses
 	EnabledAddresses []common.Address `json:"enabledAddresses,omitempty"` // initial enabled addresses
+	Threshold        *ThresholdConfig `json:"threshold,omitempty"`        // initial threshold admin
 }
 
 // Configure initializes the address space of [precompileAddr] by initializing the role of each of
@@ -34,6 +68,11 @@ func (c *AllowListConfig) Configure(chainConfig precompileconfig.ChainConfig, pr
 	for _, managerAddr := range c.ManagerAddresses {
 		SetAllowListRole(state, precompileAddr, managerAddr, ManagerRole)
 	}
+	// Verify() should have been called before Configure()
+	// so we know threshold functions are activated
+	if c.Threshold != nil {
+		configureThreshold(state, precompileAddr, c.Threshold.Threshold, c.Threshold.Signers)
+	}
 	return nil
 }
 
@@ -45,7 +84,8 @@ func (c *AllowListConfig) Equal(other *AllowListConfig) bool {
 
 	return areEqualAddressLists(c.AdminAddresses, other.AdminAddresses) &&
 		areEqualAddressLists(c.ManagerAddresses, other.ManagerAddresses) &&
-		areEqualAddressLists(c.EnabledAddresses, other.EnabledAddresses)
+		areEqualAddressLists(c.EnabledAddresses, other.EnabledAddresses) &&
+		c.Threshold.Equal(other.Threshold)
 }
 
 // areEqualAddressLists returns true iff [a] and [b] have the same addresses in the same order.
@@ -108,5 +148,15 @@ func (c *AllowListConfig) Verify(chainConfig precompileconfig.ChainConfig, upgra
 		addressMap[managerAddr] = ManagerRole
 	}
 
+	if c.Threshold != nil {
+		if err := c.Threshold.Verify(); err != nil {
+			return err
+		}
+		// If the config attempts to activate a threshold admin before the DUpgrade, fail verification
+		if upgrade.Timestamp() != nil && !chainConfig.IsDUpgrade(*upgrade.Timestamp()) {
+			return ErrCannotAddThresholdBeforeDUpgrade
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,122 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Temporary permissions are granted with setEnabledUntil, which assigns the Enabled role
+// to an address until a timestamp. The expiry is stored in a separate slot from the role
+// so that the role of every address keeps the same storage layout. Once the expiry has
+// passed, the address is treated as having no role.
+
+const (
+	SetEnabledUntilFuncKey = "setEnabledUntil"
+
+	SetEnabledUntilGasCost = 2 * contract.WriteGasCostPerSlot
+
+	setEnabledUntilInputLen = common.HashLength + common.HashLength
+)
+
+var (
+	setEnabledUntilSignature = contract.CalculateFunctionSelector("setEnabledUntil(address,uint256)")
+
+	// enabledUntilKeyPrefix is the prefix of the storage keys holding the expiry of
+	// temporary permissions.
+	enabledUntilKeyPrefix = []byte("enabledUntil")
+)
+
+// enabledUntilKey returns the storage key holding the expiry of the permissions of [address].
+func enabledUntilKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(enabledUntilKeyPrefix, address.Bytes())
+}
+
+// GetAllowListExpiry returns the timestamp at which the Enabled role of [address] lapses
+// for the precompile at [precompileAddr], or 0 if it does not lapse.
+func GetAllowListExpiry(state contract.StateDB, precompileAddr common.Address, address common.Address) uint64 {
+	expiry := state.GetState(precompileAddr, enabledUntilKey(address)).Big()
+	if !expiry.IsUint64() {
+		return 0
+	}
+	return expiry.Uint64()
+}
+
+// SetAllowListExpiry sets the timestamp at which the Enabled role of [address] lapses
+// for the precompile at [precompileAddr]. An [expiry] of 0 removes the expiry.
+func SetAllowListExpiry(state contract.StateDB, precompileAddr common.Address, address common.Address, expiry uint64) {
+	state.SetState(precompileAddr, enabledUntilKey(address), common.BigToHash(new(big.Int).SetUint64(expiry)))
+}
+
+// GetAllowListStatusAt returns the allow list role of [address] for the precompile at
+// [precompileAddr] at [timestamp], taking into account the expiry of temporary permissions.
+func GetAllowListStatusAt(state contract.StateDB, precompileAddr common.Address, address common.Address, timestamp uint64) Role {
+	role := GetAllowListStatus(state, precompileAddr, address)
+	if role != EnabledRole {
+		return role
+	}
+	if expiry := GetAllowListExpiry(state, precompileAddr, address); expiry != 0 && timestamp >= expiry {
+		return NoRole
+	}
+	return role
+}
+
+// PackSetEnabledUntil packs [address] and [timestamp] into the input data to the
+// setEnabledUntil function.
+func PackSetEnabledUntil(address common.Address, timestamp uint64) ([]byte, error) {
+	input := make([]byte, contract.SelectorLen+setEnabledUntilInputLen)
+	err := contract.PackOrderedHashesWithSelector(input, setEnabledUntilSignature, []common.Hash{
+		address.Hash(),
+		common.BigToHash(new(big.Int).SetUint64(timestamp)),
+	})
+	return input, err
+}
+
+// CreateSetEnabledUntilFunction returns the setEnabledUntil function of the allow list at
+// [precompileAddr]. The function is activated by the DUpgrade.
+func CreateSetEnabledUntilFunction(precompileAddr common.Address) *contract.StatefulPrecompileFunction {
+	return contract.NewStatefulPrecompileFunctionWithActivator(setEnabledUntilSignature, createSetEnabledUntil(precompileAddr), isManagerRoleActivated)
+}
+
+// createSetEnabledUntil returns an execution function that assigns the Enabled role to the
+// input address until the input timestamp. Callers require the same permissions as setEnabled.
+func createSetEnabledUntil(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, SetEnabledUntilGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != setEnabledUntilInputLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for setting enabled until: %d", len(input))
+		}
+		modifyAddress := common.BytesToAddress(contract.PackedHash(input, 0))
+		expiry := new(big.Int).SetBytes(contract.PackedHash(input, 1))
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		timestamp := evm.GetBlockContext().Timestamp()
+		if !expiry.IsUint64() || expiry.Uint64() <= timestamp {
+			return nil, remainingGas, fmt.Errorf("%w: expiry %s is not after the block timestamp %d", ErrInvalidExpiry, expiry, timestamp)
+		}
+
+		stateDB := evm.GetStateDB()
+		callerStatus := GetAllowListStatus(stateDB, precompileAddr, callerAddr)
+		modifyStatus := GetAllowListStatusAt(stateDB, precompileAddr, modifyAddress, timestamp)
+		if !callerStatus.CanModify(modifyStatus, EnabledRole) {
+			return nil, remainingGas, fmt.Errorf("%w: modify address: %s, from role: %s, to role: %s", ErrCannotModifyAllowList, callerAddr, modifyStatus, EnabledRole)
+		}
+		SetAllowListRole(stateDB, precompileAddr, modifyAddress, EnabledRole)
+		SetAllowListExpiry(stateDB, precompileAddr, modifyAddress, expiry.Uint64())
+		// Return an empty output and the remaining gas
+		return []byte{}, remainingGas, nil
+	}
+}
@@ -0,0 +1,217 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Roles can be assigned with setRoleWithSignature, which takes an EIP-712 signed
+// approval in place of a call from the approving address. This lets an admin sign an
+// approval offline and have anyone submit it on-chain. Each signer has a nonce that
+// is consumed by every approval, so that approvals cannot be replayed.
+//
+// Approvals are signed over the domain
+//
+//	EIP712Domain(string name,string version,address verifyingContract,bytes32 salt)
+//
+// with name "AllowList", version "1", the address of the precompile as verifyingContract
+// and the ID of the chain as salt, and the message
+//
+//	SetRole(address addr,uint256 role,uint256 nonce,uint256 deadline)
+
+const (
+	SetRoleWithSignatureFuncKey = "setRoleWithSignature"
+	GetRoleNonceFuncKey         = "getRoleNonce"
+
+	// ecrecoverGasCost matches the cost of the ecrecover precompile.
+	ecrecoverGasCost uint64 = 3_000
+
+	SetRoleWithSignatureGasCost = ModifyAllowListGasCost + 2*contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot + ecrecoverGasCost
+	GetRoleNonceGasCost         = contract.ReadGasCostPerSlot
+
+	setRoleWithSignatureInputLen = 7 * common.HashLength
+
+	roleApprovalDomainName    = "AllowList"
+	roleApprovalDomainVersion = "1"
+)
+
+var (
+	setRoleWithSignatureSignature = contract.CalculateFunctionSelector("setRoleWithSignature(address,uint256,uint256,uint256,uint8,bytes32,bytes32)")
+	getRoleNonceSignature         = contract.CalculateFunctionSelector("getRoleNonce(address)")
+
+	roleApprovalDomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,address verifyingContract,bytes32 salt)"))
+	setRoleTypeHash            = crypto.Keccak256Hash([]byte("SetRole(address addr,uint256 role,uint256 nonce,uint256 deadline)"))
+
+	// roleNonceKeyPrefix is the prefix of the storage keys holding the nonces of signers.
+	roleNonceKeyPrefix = []byte("roleNonce")
+
+	ErrInvalidRoleSignature = errors.New("invalid role approval signature")
+	ErrExpiredRoleApproval  = errors.New("role approval expired")
+	ErrInvalidRoleNonce     = errors.New("invalid role approval nonce")
+)
+
+// roleNonceKey returns the storage key holding the nonce of [signer].
+func roleNonceKey(signer common.Address) common.Hash {
+	return crypto.Keccak256Hash(roleNonceKeyPrefix, signer.Bytes())
+}
+
+// GetRoleNonce returns the nonce of the next role approval signed by [signer] for the
+// precompile at [precompileAddr].
+func GetRoleNonce(state contract.StateDB, precompileAddr common.Address, signer common.Address) *big.Int {
+	return state.GetState(precompileAddr, roleNonceKey(signer)).Big()
+}
+
+// RoleApprovalHash returns the EIP-712 digest signed to approve assigning [role] to [addr]
+// for the precompile at [precompileAddr] on the chain [chainID].
+func RoleApprovalHash(chainID ids.ID, precompileAddr common.Address, addr common.Address, role Role, nonce *big.Int, deadline uint64) common.Hash {
+	domainSeparator := crypto.Keccak256Hash(
+		roleApprovalDomainTypeHash[:],
+		crypto.Keccak256([]byte(roleApprovalDomainName)),
+		crypto.Keccak256([]byte(roleApprovalDomainVersion)),
+		precompileAddr.Hash().Bytes(),
+		chainID[:],
+	)
+	structHash := crypto.Keccak256Hash(
+		setRoleTypeHash[:],
+		addr.Hash().Bytes(),
+		common.Hash(role).Bytes(),
+		common.BigToHash(nonce).Bytes(),
+		common.BigToHash(new(big.Int).SetUint64(deadline)).Bytes(),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator[:], structHash[:])
+}
+
+// PackSetRoleWithSignature packs the input data to the setRoleWithSignature function.
+// [signature] is a 65 byte [R || S || V] signature as produced by crypto.Sign.
+func PackSetRoleWithSignature(addr common.Address, role Role, nonce *big.Int, deadline uint64, signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrInvalidRoleSignature, len(signature))
+	}
+	input := make([]byte, contract.SelectorLen+setRoleWithSignatureInputLen)
+	err := contract.PackOrderedHashesWithSelector(input, setRoleWithSignatureSignature, []common.Hash{
+		addr.Hash(),
+		common.Hash(role),
+		common.BigToHash(nonce),
+		common.BigToHash(new(big.Int).SetUint64(deadline)),
+		common.BigToHash(big.NewInt(int64(signature[crypto.RecoveryIDOffset]) + 27)),
+		common.BytesToHash(signature[:32]),
+		common.BytesToHash(signature[32:64]),
+	})
+	return input, err
+}
+
+// PackGetRoleNonce packs [signer] into the input data to the getRoleNonce function.
+func PackGetRoleNonce(signer common.Address) []byte {
+	input := make([]byte, 0, contract.SelectorLen+common.HashLength)
+	input = append(input, getRoleNonceSignature...)
+	input = append(input, signer.Hash().Bytes()...)
+	return input
+}
+
+// CreateRoleSignatureFunctions returns the setRoleWithSignature and getRoleNonce functions
+// of the allow list at [precompileAddr]. The functions are activated by the DUpgrade.
+func CreateRoleSignatureFunctions(precompileAddr common.Address) []*contract.StatefulPrecompileFunction {
+	return []*contract.StatefulPrecompileFunction{
+		contract.NewStatefulPrecompileFunctionWithActivator(setRoleWithSignatureSignature, createSetRoleWithSignature(precompileAddr), isManagerRoleActivated),
+		contract.NewStatefulPrecompileFunctionWithActivator(getRoleNonceSignature, createGetRoleNonce(precompileAddr), isManagerRoleActivated),
+	}
+}
+
+// createSetRoleWithSignature returns an execution function that assigns a role approved by
+// a signed message. The signer requires the same permissions as the caller of the
+// corresponding role setter, while the caller requires none.
+func createSetRoleWithSignature(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, SetRoleWithSignatureGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != setRoleWithSignatureInputLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for setting role with signature: %d", len(input))
+		}
+		modifyAddress := common.BytesToAddress(contract.PackedHash(input, 0))
+		role := Role(common.BytesToHash(contract.PackedHash(input, 1)))
+		nonce := new(big.Int).SetBytes(contract.PackedHash(input, 2))
+		deadline := new(big.Int).SetBytes(contract.PackedHash(input, 3))
+		v := new(big.Int).SetBytes(contract.PackedHash(input, 4))
+		r := new(big.Int).SetBytes(contract.PackedHash(input, 5))
+		s := new(big.Int).SetBytes(contract.PackedHash(input, 6))
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		switch role {
+		case NoRole, EnabledRole, ManagerRole, AdminRole:
+		default:
+			return nil, remainingGas, fmt.Errorf("cannot set invalid role: %s", common.Hash(role))
+		}
+		timestamp := evm.GetBlockContext().Timestamp()
+		if !deadline.IsUint64() || deadline.Uint64() < timestamp {
+			return nil, remainingGas, fmt.Errorf("%w: deadline %s is before the block timestamp %d", ErrExpiredRoleApproval, deadline, timestamp)
+		}
+
+		// Recover the signer of the approval.
+		if !v.IsUint64() || (v.Uint64() != 27 && v.Uint64() != 28) || !crypto.ValidateSignatureValues(byte(v.Uint64()-27), r, s, true) {
+			return nil, remainingGas, ErrInvalidRoleSignature
+		}
+		signature := make([]byte, crypto.SignatureLength)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:64])
+		signature[crypto.RecoveryIDOffset] = byte(v.Uint64() - 27)
+		digest := RoleApprovalHash(evm.GetSnowContext().ChainID, precompileAddr, modifyAddress, role, nonce, deadline.Uint64())
+		pubKey, err := crypto.SigToPub(digest[:], signature)
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidRoleSignature, err)
+		}
+		signer := crypto.PubkeyToAddress(*pubKey)
+
+		stateDB := evm.GetStateDB()
+		if expected := GetRoleNonce(stateDB, precompileAddr, signer); expected.Cmp(nonce) != 0 {
+			return nil, remainingGas, fmt.Errorf("%w: signer: %s, expected: %s, got: %s", ErrInvalidRoleNonce, signer, expected, nonce)
+		}
+
+		// Verify that the signer is permitted to modify the allow list
+		signerStatus := GetAllowListStatus(stateDB, precompileAddr, signer)
+		modifyStatus := GetAllowListStatus(stateDB, precompileAddr, modifyAddress)
+		if !signerStatus.CanModify(modifyStatus, role) {
+			return nil, remainingGas, fmt.Errorf("%w: modify address: %s, from role: %s, to role: %s", ErrCannotModifyAllowList, signer, modifyStatus, role)
+		}
+		stateDB.SetState(precompileAddr, roleNonceKey(signer), common.BigToHash(new(big.Int).Add(nonce, common.Big1)))
+		SetAllowListRole(stateDB, precompileAddr, modifyAddress, role)
+		// Assigning a role replaces any temporary permissions granted by setEnabledUntil.
+		if GetAllowListExpiry(stateDB, precompileAddr, modifyAddress) != 0 {
+			SetAllowListExpiry(stateDB, precompileAddr, modifyAddress, 0)
+		}
+		// Return an empty output and the remaining gas
+		return []byte{}, remainingGas, nil
+	}
+}
+
+// createGetRoleNonce returns an execution function that returns the nonce of the next
+// role approval signed by the input address.
+func createGetRoleNonce(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, GetRoleNonceGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != allowListInputLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for get role nonce: %d", len(input))
+		}
+
+		signer := common.BytesToAddress(input)
+		nonce := GetRoleNonce(evm.GetStateDB(), precompileAddr, signer)
+		return common.BigToHash(nonce).Bytes(), remainingGas, nil
+	}
+}
@@ -0,0 +1,140 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRoleWithSignature(t *testing.T) {
+	dummyModule := modules.Module{
+		Address:      dummyAddr,
+		Contract:     CreateAllowListPrecompile(dummyAddr),
+		Configurator: &dummyConfigurator{},
+		ConfigKey:    "dummy",
+	}
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signerAddr := crypto.PubkeyToAddress(signerKey.PublicKey)
+	chainID := snow.DefaultContextTest().ChainID
+	deadline := uint64(1 << 40)
+
+	setSignerRole := func(role Role) func(t testing.TB, state contract.StateDB) {
+		return func(t testing.TB, state contract.StateDB) {
+			SetDefaultRoles(dummyAddr)(t, state)
+			SetAllowListRole(state, dummyAddr, signerAddr, role)
+		}
+	}
+	signedInput := func(addr common.Address, role Role, nonce int64, deadline uint64) func(t testing.TB) []byte {
+		return func(t testing.TB) []byte {
+			digest := RoleApprovalHash(chainID, dummyAddr, addr, role, big.NewInt(nonce), deadline)
+			signature, err := crypto.Sign(digest[:], signerKey)
+			require.NoError(t, err)
+			input, err := PackSetRoleWithSignature(addr, role, big.NewInt(nonce), deadline, signature)
+			require.NoError(t, err)
+			return input
+		}
+	}
+
+	tests := map[string]testutils.PrecompileTest{
+		"admin approval submitted by no role succeeds": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setSignerRole(AdminRole),
+			InputFn:     signedInput(TestNoRoleAddr, EnabledRole, 0, deadline),
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, EnabledRole, GetAllowListStatus(state, dummyAddr, TestNoRoleAddr))
+				require.Equal(t, big.NewInt(1), GetRoleNonce(state, dummyAddr, signerAddr))
+			},
+		},
+		"manager approval of admin role fails": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setSignerRole(ManagerRole),
+			InputFn:     signedInput(TestNoRoleAddr, AdminRole, 0, deadline),
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotModifyAllowList.Error(),
+		},
+		"no role approval fails": {
+			Caller:      TestAdminAddr,
+			BeforeHook:  setSignerRole(NoRole),
+			InputFn:     signedInput(TestNoRoleAddr, EnabledRole, 0, deadline),
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotModifyAllowList.Error(),
+		},
+		"replayed approval fails": {
+			Caller: TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				setSignerRole(AdminRole)(t, state)
+				state.SetState(dummyAddr, roleNonceKey(signerAddr), common.BigToHash(common.Big1))
+			},
+			InputFn:     signedInput(TestNoRoleAddr, EnabledRole, 0, deadline),
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInvalidRoleNonce.Error(),
+		},
+		"expired approval fails": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setSignerRole(AdminRole),
+			InputFn:     signedInput(TestNoRoleAddr, EnabledRole, 0, 1),
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrExpiredRoleApproval.Error(),
+		},
+		"tampered approval fails": {
+			Caller:     TestNoRoleAddr,
+			BeforeHook: setSignerRole(AdminRole),
+			InputFn: func(t testing.TB) []byte {
+				// The signature approves the enabled role, but the admin role is submitted.
+				input := signedInput(TestNoRoleAddr, EnabledRole, 0, deadline)(t)
+				copy(input[contract.SelectorLen+common.HashLength:], common.Hash(AdminRole).Bytes())
+				return input
+			},
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotModifyAllowList.Error(),
+		},
+		"readOnly approval fails": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setSignerRole(AdminRole),
+			InputFn:     signedInput(TestNoRoleAddr, EnabledRole, 0, deadline),
+			SuppliedGas: SetRoleWithSignatureGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"insufficient gas approval fails": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setSignerRole(AdminRole),
+			InputFn:     signedInput(TestNoRoleAddr, EnabledRole, 0, deadline),
+			SuppliedGas: SetRoleWithSignatureGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"get role nonce": {
+			Caller: TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetState(dummyAddr, roleNonceKey(signerAddr), common.BigToHash(big.NewInt(3)))
+			},
+			Input:       PackGetRoleNonce(signerAddr),
+			SuppliedGas: GetRoleNonceGasCost,
+			ReadOnly:    true,
+			ExpectedRes: common.BigToHash(big.NewInt(3)).Bytes(),
+		},
+	}
+	testutils.RunPrecompileTests(t, dummyModule, state.NewTestStateDB, tests)
+}
@@ -4,6 +4,7 @@
 package allowlist
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ava-labs/subnet-evm/precompile/contract"
@@ -12,6 +13,7 @@ import (
 	"github.com/ava-labs/subnet-evm/precompile/testutils"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -25,6 +27,9 @@ var (
 
 func AllowListTests(t testing.TB, module modules.Module) map[string]testutils.PrecompileTest {
 	contractAddress := module.Address
+	// thresholdCall assigns the Enabled role to TestNoRoleAddr.
+	thresholdCall, err := PackModifyAllowList(TestNoRoleAddr, EnabledRole)
+	require.NoError(t, err)
 	return map[string]testutils.PrecompileTest{
 		"admin set admin": {
 			Caller:     TestAdminAddr,
@@ -525,6 +530,126 @@ func AllowListTests(t testing.TB, module modules.Module) map[string]testutils.Pr
 				require.Equal(t, ManagerRole, GetAllowListStatus(state, contractAddress, TestEnabledAddr))
 			},
 		},
+		"initial config sets threshold": {
+			Config: mkConfigWithAllowList(
+				module,
+				&AllowListConfig{
+					Threshold: &ThresholdConfig{Threshold: 2, Signers: []common.Address{TestAdminAddr, TestManagerAddr}},
+				},
+			),
+			SuppliedGas: 0,
+			ReadOnly:    false,
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, uint64(2), GetThreshold(state, contractAddress))
+				require.True(t, IsThresholdSigner(state, contractAddress, TestAdminAddr))
+				require.True(t, IsThresholdSigner(state, contractAddress, TestManagerAddr))
+				require.False(t, IsThresholdSigner(state, contractAddress, TestEnabledAddr))
+				require.Equal(t, AdminRole, GetAllowListStatus(state, contractAddress, ThresholdAdminAddress(contractAddress)))
+			},
+		},
+		"threshold propose call from non-signer fails": {
+			Caller:      TestEnabledAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, nil, 0),
+			Input:       PackProposeCall(thresholdCall),
+			SuppliedGas: ProposeCallGasCost + keccak256GasCostOf(thresholdCall),
+			ReadOnly:    false,
+			ExpectedErr: ErrNotThresholdSigner.Error(),
+		},
+		"threshold propose call from signer": {
+			Caller:      TestAdminAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, nil, 0),
+			Input:       PackProposeCall(thresholdCall),
+			SuppliedGas: ProposeCallGasCost + keccak256GasCostOf(thresholdCall),
+			ReadOnly:    false,
+			ExpectedRes: common.BigToHash(common.Big1).Bytes(),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				proposal, ok := GetCallProposal(state, contractAddress, common.Big1)
+				require.True(t, ok)
+				require.Equal(t, CallProposal{CallHash: crypto.Keccak256Hash(thresholdCall), Confirmations: 1}, proposal)
+			},
+		},
+		"threshold confirm call from signer": {
+			Caller:      TestManagerAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 1),
+			Input:       PackConfirmCall(common.Big1),
+			SuppliedGas: ConfirmCallGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				proposal, ok := GetCallProposal(state, contractAddress, common.Big1)
+				require.True(t, ok)
+				require.Equal(t, uint64(2), proposal.Confirmations)
+			},
+		},
+		"threshold confirm call twice fails": {
+			Caller:      TestAdminAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 1),
+			Input:       PackConfirmCall(common.Big1),
+			SuppliedGas: ConfirmCallGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCallAlreadyConfirmed.Error(),
+		},
+		"threshold confirm unknown call fails": {
+			Caller:      TestManagerAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 1),
+			Input:       PackConfirmCall(common.Big2),
+			SuppliedGas: ConfirmCallGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrUnknownCallProposal.Error(),
+		},
+		"threshold execute confirmed call": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 2),
+			Input:       PackExecuteCall(common.Big1, thresholdCall),
+			SuppliedGas: ExecuteCallGasCost + keccak256GasCostOf(thresholdCall) + ModifyAllowListGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, EnabledRole, GetAllowListStatus(state, contractAddress, TestNoRoleAddr))
+				proposal, ok := GetCallProposal(state, contractAddress, common.Big1)
+				require.True(t, ok)
+				require.True(t, proposal.Executed)
+			},
+		},
+		"threshold execute call twice fails": {
+			Caller: TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				setThresholdProposal(contractAddress, thresholdCall, 2)(t, state)
+				state.SetState(contractAddress, callProposalKey(common.Big1, callExecutedField), thresholdTrueHash)
+			},
+			Input:       PackExecuteCall(common.Big1, thresholdCall),
+			SuppliedGas: ExecuteCallGasCost + keccak256GasCostOf(thresholdCall),
+			ReadOnly:    false,
+			ExpectedErr: ErrCallAlreadyExecuted.Error(),
+		},
+		"threshold execute call with insufficient confirmations fails": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 1),
+			Input:       PackExecuteCall(common.Big1, thresholdCall),
+			SuppliedGas: ExecuteCallGasCost + keccak256GasCostOf(thresholdCall),
+			ReadOnly:    false,
+			ExpectedErr: ErrInsufficientConfirmations.Error(),
+		},
+		"threshold execute mismatched call fails": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 2),
+			Input:       PackExecuteCall(common.Big1, PackReadAllowList(TestNoRoleAddr)),
+			SuppliedGas: ExecuteCallGasCost + keccak256GasCostOf(PackReadAllowList(TestNoRoleAddr)),
+			ReadOnly:    false,
+			ExpectedErr: ErrCallMismatch.Error(),
+		},
+		"threshold get call proposal": {
+			Caller:      TestNoRoleAddr,
+			BeforeHook:  setThresholdProposal(contractAddress, thresholdCall, 1),
+			Input:       PackGetCallProposal(common.Big1),
+			SuppliedGas: GetCallProposalGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackCallProposalOutput(CallProposal{CallHash: crypto.Keccak256Hash(thresholdCall), Confirmations: 1})
+				require.NoError(t, err)
+				return res
+			}(),
+		},
 		"initial config sets enabled": {
 			Config: mkConfigWithAllowList(
 				module,
@@ -542,6 +667,23 @@ func AllowListTests(t testing.TB, module modules.Module) map[string]testutils.Pr
 	}
 }
 
+// setThresholdProposal returns a BeforeHook that sets the default roles and a threshold of
+// 2 with TestAdminAddr and TestManagerAddr as signers. If [call] is not nil, it also stores
+// a proposal of [call] with [confirmations], the first of which is by TestAdminAddr.
+func setThresholdProposal(contractAddress common.Address, call []byte, confirmations uint64) func(t testing.TB, state contract.StateDB) {
+	return func(t testing.TB, state contract.StateDB) {
+		SetDefaultRoles(contractAddress)(t, state)
+		configureThreshold(state, contractAddress, 2, []common.Address{TestAdminAddr, TestManagerAddr})
+		if call == nil {
+			return
+		}
+		state.SetState(contractAddress, callProposalCountKey, common.BigToHash(common.Big1))
+		state.SetState(contractAddress, callProposalKey(common.Big1, callHashField), crypto.Keccak256Hash(call))
+		state.SetState(contractAddress, callProposalKey(common.Big1, callConfirmationsField), common.BigToHash(new(big.Int).SetUint64(confirmations)))
+		state.SetState(contractAddress, callConfirmationKey(common.Big1, TestAdminAddr), thresholdTrueHash)
+	}
+}
+
 // SetDefaultRoles returns a BeforeHook that sets roles TestAdminAddr and TestEnabledAddr
 // to have the AdminRole and EnabledRole respectively.
 func SetDefaultRoles(contractAddress common.Address) func(t testing.TB, state contract.StateDB) {
@@ -588,3 +730,29 @@ func BenchPrecompileWithAllowList(b *testing.B, module modules.Module, newStateD
 		})
 	}
 }
+
+// ReadOnlyFunctions returns the allow list functions for testutils.RunReadOnlyTests,
+// merged with the contract specific [contractFunctions].
+func ReadOnlyFunctions(t testing.TB, contractFunctions map[string]testutils.ReadOnlyFunction) map[string]testutils.ReadOnlyFunction {
+	functions := make(map[string]testutils.ReadOnlyFunction)
+	for _, role := range []Role{AdminRole, ManagerRole, EnabledRole, NoRole} {
+		input, err := PackModifyAllowList(TestNoRoleAddr, role)
+		require.NoError(t, err)
+		functions[contract.FunctionName(input[:contract.SelectorLen])] = testutils.ReadOnlyFunction{Input: input, Writes: true}
+	}
+	functions[ReadAllowListFuncKey] = testutils.ReadOnlyFunction{Input: PackReadAllowList(TestNoRoleAddr)}
+	functions[SetRoleWithSignatureFuncKey] = testutils.ReadOnlyFunction{
+		Input:  append(common.CopyBytes(setRoleWithSignatureSignature), make([]byte, setRoleWithSignatureInputLen)...),
+		Writes: true,
+	}
+	functions[GetRoleNonceFuncKey] = testutils.ReadOnlyFunction{Input: PackGetRoleNonce(TestNoRoleAddr)}
+	functions[ProposeCallFuncKey] = testutils.ReadOnlyFunction{Input: PackProposeCall(nil), Writes: true}
+	functions[ConfirmCallFuncKey] = testutils.ReadOnlyFunction{Input: PackConfirmCall(common.Big1), Writes: true}
+	functions[ExecuteCallFuncKey] = testutils.ReadOnlyFunction{Input: PackExecuteCall(common.Big1, nil), Writes: true}
+	functions[GetCallProposalFuncKey] = testutils.ReadOnlyFunction{Input: PackGetCallProposal(common.Big1)}
+
+	for name, function := range contractFunctions {
+		functions[name] = function
+	}
+	return functions
+}
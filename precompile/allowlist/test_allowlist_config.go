@@ -127,6 +127,44 @@ func AllowListConfigVerifyTests(t testing.TB, module modules.Module) map[string]
 			}),
 			ExpectedError: "",
 		},
+		"invalid allow list config with zero threshold": {
+			Config: mkConfigWithAllowList(module, &AllowListConfig{
+				Threshold: &ThresholdConfig{Threshold: 0, Signers: []common.Address{TestAdminAddr}},
+			}),
+			ExpectedError: "invalid threshold 0 for 1 signers",
+		},
+		"invalid allow list config with threshold above signers": {
+			Config: mkConfigWithAllowList(module, &AllowListConfig{
+				Threshold: &ThresholdConfig{Threshold: 2, Signers: []common.Address{TestAdminAddr}},
+			}),
+			ExpectedError: "invalid threshold 2 for 1 signers",
+		},
+		"invalid allow list config with duplicate threshold signers": {
+			Config: mkConfigWithAllowList(module, &AllowListConfig{
+				Threshold: &ThresholdConfig{Threshold: 1, Signers: []common.Address{TestAdminAddr, TestAdminAddr}},
+			}),
+			ExpectedError: "duplicate address in threshold signers",
+		},
+		"invalid allow list config with threshold before activation": {
+			Config: mkConfigWithUpgradeAndAllowList(module, &AllowListConfig{
+				Threshold: &ThresholdConfig{Threshold: 1, Signers: []common.Address{TestAdminAddr}},
+			}, precompileconfig.Upgrade{
+				BlockTimestamp: utils.NewUint64(1),
+			}),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: ErrCannotAddThresholdBeforeDUpgrade.Error(),
+		},
+		"valid allow list config with threshold in allowlist": {
+			Config: mkConfigWithAllowList(module, &AllowListConfig{
+				AdminAddresses: []common.Address{TestAdminAddr},
+				Threshold:      &ThresholdConfig{Threshold: 2, Signers: []common.Address{TestAdminAddr, TestManagerAddr}},
+			}),
+			ExpectedError: "",
+		},
 		"valid allow list config in allowlist": {
 			Config: mkConfigWithAllowList(module, &AllowListConfig{
 				AdminAddresses:   []common.Address{TestAdminAddr},
@@ -188,6 +226,27 @@ func AllowListConfigEqualTests(t testing.TB, module modules.Module) map[string]t
 			}),
 			Expected: false,
 		},
+		"allowlist different threshold": {
+			Config: mkConfigWithAllowList(module, &AllowListConfig{
+				AdminAddresses: []common.Address{TestAdminAddr},
+				Threshold:      &ThresholdConfig{Threshold: 1, Signers: []common.Address{TestAdminAddr, TestManagerAddr}},
+			}),
+			Other: mkConfigWithAllowList(module, &AllowListConfig{
+				AdminAddresses: []common.Address{TestAdminAddr},
+				Threshold:      &ThresholdConfig{Threshold: 2, Signers: []common.Address{TestAdminAddr, TestManagerAddr}},
+			}),
+			Expected: false,
+		},
+		"allowlist different threshold signers": {
+			Config: mkConfigWithAllowList(module, &AllowListConfig{
+				AdminAddresses: []common.Address{TestAdminAddr},
+				Threshold:      &ThresholdConfig{Threshold: 1, Signers: []common.Address{TestAdminAddr}},
+			}),
+			Other: mkConfigWithAllowList(module, &AllowListConfig{
+				AdminAddresses: []common.Address{TestAdminAddr},
+			}),
+			Expected: false,
+		},
 		"allowlist same config": {
 			Config: mkConfigWithAllowList(module, &AllowListConfig{
 				AdminAddresses:   []common.Address{TestAdminAddr},
@@ -0,0 +1,357 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// A precompile can be administered by a threshold of signers instead of a single key.
+// The config grants the Admin role to the threshold admin address of the precompile,
+// which has no private key. Instead, any of the configured signers can propose a call to
+// the precompile, which the other signers confirm. Once the proposal has the threshold of
+// confirmations, anyone can execute it, which calls the precompile with the proposed
+// input as the threshold admin. This allows minting, fee config changes and allow list
+// changes to require the approval of several keys.
+
+const (
+	ProposeCallFuncKey     = "proposeCall"
+	ConfirmCallFuncKey     = "confirmCall"
+	ExecuteCallFuncKey     = "executeCall"
+	GetCallProposalFuncKey = "getCallProposal"
+
+	// keccak256GasCost and keccak256WordGasCost match the cost of the KECCAK256 opcode.
+	keccak256GasCost     uint64 = 30
+	keccak256WordGasCost uint64 = 6
+
+	ProposeCallGasCost     = 2*contract.ReadGasCostPerSlot + 4*contract.WriteGasCostPerSlot + keccak256GasCost // signer and count, then call hash, confirmation, confirmations and count
+	ConfirmCallGasCost     = 4*contract.ReadGasCostPerSlot + 2*contract.WriteGasCostPerSlot                    // signer, count, confirmation and confirmations, then confirmation and confirmations
+	ExecuteCallGasCost     = 5*contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot + keccak256GasCost   // count, call hash, confirmations, threshold and executed, then executed
+	GetCallProposalGasCost = 4 * contract.ReadGasCostPerSlot
+
+	callProposalIDLen = common.HashLength
+)
+
+// fields of a call proposal
+const (
+	callHashField = iota
+	callConfirmationsField
+	callExecutedField
+)
+
+var (
+	proposeCallSignature     = contract.CalculateFunctionSelector("proposeCall(bytes)")
+	confirmCallSignature     = contract.CalculateFunctionSelector("confirmCall(uint256)")
+	executeCallSignature     = contract.CalculateFunctionSelector("executeCall(uint256,bytes)")
+	getCallProposalSignature = contract.CalculateFunctionSelector("getCallProposal(uint256)")
+
+	thresholdAdminPrefix      = []byte("thresholdAdmin")
+	thresholdKey              = crypto.Keccak256Hash([]byte("threshold"))
+	thresholdSignerKeyPrefix  = []byte("thresholdSigner")
+	callProposalCountKey      = crypto.Keccak256Hash([]byte("callProposalCount"))
+	callProposalKeyPrefix     = []byte("callProposal")
+	callConfirmationKeyPrefix = []byte("callConfirmation")
+	thresholdTrueHash         = common.BigToHash(common.Big1)
+
+	ErrNotThresholdSigner        = errors.New("caller is not a threshold signer")
+	ErrUnknownCallProposal       = errors.New("unknown call proposal")
+	ErrCallAlreadyConfirmed      = errors.New("call proposal already confirmed by signer")
+	ErrCallAlreadyExecuted       = errors.New("call proposal already executed")
+	ErrInsufficientConfirmations = errors.New("insufficient call proposal confirmations")
+	ErrCallMismatch              = errors.New("call does not match proposal")
+	errInvalidBytesInput         = errors.New("invalid bytes input")
+)
+
+// CallProposal is a proposed call to a precompile administered by a threshold of signers.
+type CallProposal struct {
+	// CallHash is the keccak256 hash of the input of the proposed call.
+	CallHash      common.Hash
+	Confirmations uint64
+	Executed      bool
+}
+
+// ThresholdAdminAddress returns the address that calls the precompile at [precompileAddr]
+// when executing a confirmed call proposal.
+func ThresholdAdminAddress(precompileAddr common.Address) common.Address {
+	return common.BytesToAddress(crypto.Keccak256(thresholdAdminPrefix, precompileAddr.Bytes()))
+}
+
+func thresholdSignerKey(signer common.Address) common.Hash {
+	return crypto.Keccak256Hash(thresholdSignerKeyPrefix, signer.Bytes())
+}
+
+func callProposalKey(proposalID *big.Int, field int) common.Hash {
+	return crypto.Keccak256Hash(callProposalKeyPrefix, common.BigToHash(proposalID).Bytes(), []byte{byte(field)})
+}
+
+func callConfirmationKey(proposalID *big.Int, signer common.Address) common.Hash {
+	return crypto.Keccak256Hash(callConfirmationKeyPrefix, common.BigToHash(proposalID).Bytes(), signer.Bytes())
+}
+
+// GetThreshold returns the number of confirmations required to execute a call proposal of
+// the precompile at [precompileAddr], or 0 if it is not administered by a threshold.
+func GetThreshold(state contract.StateDB, precompileAddr common.Address) uint64 {
+	return state.GetState(precompileAddr, thresholdKey).Big().Uint64()
+}
+
+// IsThresholdSigner returns true if [signer] can propose and confirm call proposals of the
+// precompile at [precompileAddr].
+func IsThresholdSigner(state contract.StateDB, precompileAddr common.Address, signer common.Address) bool {
+	return state.GetState(precompileAddr, thresholdSignerKey(signer)) == thresholdTrueHash
+}
+
+// GetCallProposal returns the call proposal with [proposalID] of the precompile at
+// [precompileAddr] and true, or false if the proposal does not exist.
+func GetCallProposal(state contract.StateDB, precompileAddr common.Address, proposalID *big.Int) (CallProposal, bool) {
+	count := state.GetState(precompileAddr, callProposalCountKey).Big()
+	if proposalID.Sign() <= 0 || proposalID.Cmp(count) > 0 {
+		return CallProposal{}, false
+	}
+	return CallProposal{
+		CallHash:      state.GetState(precompileAddr, callProposalKey(proposalID, callHashField)),
+		Confirmations: state.GetState(precompileAddr, callProposalKey(proposalID, callConfirmationsField)).Big().Uint64(),
+		Executed:      state.GetState(precompileAddr, callProposalKey(proposalID, callExecutedField)) == thresholdTrueHash,
+	}, true
+}
+
+// configureThreshold stores the [threshold] and [signers] administering the precompile at
+// [precompileAddr] and grants the Admin role to its threshold admin address.
+func configureThreshold(state contract.StateDB, precompileAddr common.Address, threshold uint64, signers []common.Address) {
+	state.SetState(precompileAddr, thresholdKey, common.BigToHash(new(big.Int).SetUint64(threshold)))
+	for _, signer := range signers {
+		state.SetState(precompileAddr, thresholdSignerKey(signer), thresholdTrueHash)
+	}
+	SetAllowListRole(state, precompileAddr, ThresholdAdminAddress(precompileAddr), AdminRole)
+}
+
+// keccak256GasCostOf returns the cost of hashing [data].
+func keccak256GasCostOf(data []byte) uint64 {
+	return keccak256WordGasCost * uint64((len(data)+common.HashLength-1)/common.HashLength)
+}
+
+// packBytes returns the ABI encoding of [data] following [offset] bytes of static arguments.
+func packBytes(offset int, data []byte) []byte {
+	words := (len(data) + common.HashLength - 1) / common.HashLength
+	packed := make([]byte, 2*common.HashLength+words*common.HashLength)
+	copy(packed, common.BigToHash(big.NewInt(int64(offset+common.HashLength))).Bytes())
+	copy(packed[common.HashLength:], common.BigToHash(big.NewInt(int64(len(data)))).Bytes())
+	copy(packed[2*common.HashLength:], data)
+	return packed
+}
+
+// unpackBytes returns the bytes argument of [input], whose head is at [index].
+func unpackBytes(input []byte, index int) ([]byte, error) {
+	if len(input) < (index+1)*common.HashLength {
+		return nil, fmt.Errorf("%w: length %d", errInvalidBytesInput, len(input))
+	}
+	offset := new(big.Int).SetBytes(contract.PackedHash(input, index))
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(input)-common.HashLength) {
+		return nil, fmt.Errorf("%w: offset %s", errInvalidBytesInput, offset)
+	}
+	start := offset.Uint64() + common.HashLength
+	length := new(big.Int).SetBytes(input[offset.Uint64():start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(input))-start {
+		return nil, fmt.Errorf("%w: length %s", errInvalidBytesInput, length)
+	}
+	return input[start : start+length.Uint64()], nil
+}
+
+// PackProposeCall packs [input] into the input data to the proposeCall function.
+func PackProposeCall(input []byte) []byte {
+	return append(common.CopyBytes(proposeCallSignature), packBytes(0, input)...)
+}
+
+// PackConfirmCall packs [proposalID] into the input data to the confirmCall function.
+func PackConfirmCall(proposalID *big.Int) []byte {
+	return append(common.CopyBytes(confirmCallSignature), common.BigToHash(proposalID).Bytes()...)
+}
+
+// PackExecuteCall packs [proposalID] and [input] into the input data to the executeCall function.
+func PackExecuteCall(proposalID *big.Int, input []byte) []byte {
+	packed := append(common.CopyBytes(executeCallSignature), common.BigToHash(proposalID).Bytes()...)
+	return append(packed, packBytes(common.HashLength, input)...)
+}
+
+// PackGetCallProposal packs [proposalID] into the input data to the getCallProposal function.
+func PackGetCallProposal(proposalID *big.Int) []byte {
+	return append(common.CopyBytes(getCallProposalSignature), common.BigToHash(proposalID).Bytes()...)
+}
+
+// PackCallProposalOutput packs [proposal] into the output of the getCallProposal function.
+func PackCallProposalOutput(proposal CallProposal) ([]byte, error) {
+	executed := common.Hash{}
+	if proposal.Executed {
+		executed = thresholdTrueHash
+	}
+	output := make([]byte, 3*common.HashLength)
+	err := contract.PackOrderedHashes(output, []common.Hash{
+		proposal.CallHash,
+		common.BigToHash(new(big.Int).SetUint64(proposal.Confirmations)),
+		executed,
+	})
+	return output, err
+}
+
+// CreateThresholdFunctions returns the functions of the allow list at [precompileAddr]
+// that administer the precompile by a threshold of signers. The functions are activated
+// by the DUpgrade.
+func CreateThresholdFunctions(precompileAddr common.Address) []*contract.StatefulPrecompileFunction {
+	return []*contract.StatefulPrecompileFunction{
+		contract.NewStatefulPrecompileFunctionWithActivator(proposeCallSignature, createProposeCall(precompileAddr), isManagerRoleActivated),
+		contract.NewStatefulPrecompileFunctionWithActivator(confirmCallSignature, createConfirmCall(precompileAddr), isManagerRoleActivated),
+		contract.NewStatefulPrecompileFunctionWithActivator(executeCallSignature, createExecuteCall(precompileAddr), isManagerRoleActivated),
+		contract.NewStatefulPrecompileFunctionWithActivator(getCallProposalSignature, createGetCallProposal(precompileAddr), isManagerRoleActivated),
+	}
+}
+
+// createProposeCall returns an execution function that stores the input call as a new
+// proposal confirmed by the caller, and returns its ID. The caller must be a signer.
+func createProposeCall(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, ProposeCallGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		call, err := unpackBytes(input, 0)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if remainingGas, err = contract.DeductGas(remainingGas, keccak256GasCostOf(call)); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		stateDB := evm.GetStateDB()
+		if !IsThresholdSigner(stateDB, precompileAddr, callerAddr) {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrNotThresholdSigner, callerAddr)
+		}
+		proposalID := new(big.Int).Add(stateDB.GetState(precompileAddr, callProposalCountKey).Big(), common.Big1)
+		stateDB.SetState(precompileAddr, callProposalCountKey, common.BigToHash(proposalID))
+		stateDB.SetState(precompileAddr, callProposalKey(proposalID, callHashField), crypto.Keccak256Hash(call))
+		stateDB.SetState(precompileAddr, callProposalKey(proposalID, callConfirmationsField), common.BigToHash(common.Big1))
+		stateDB.SetState(precompileAddr, callConfirmationKey(proposalID, callerAddr), thresholdTrueHash)
+		return common.BigToHash(proposalID).Bytes(), remainingGas, nil
+	}
+}
+
+// createConfirmCall returns an execution function that confirms the input proposal on
+// behalf of the caller. The caller must be a signer.
+func createConfirmCall(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, ConfirmCallGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != callProposalIDLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for confirming call: %d", len(input))
+		}
+		proposalID := new(big.Int).SetBytes(input)
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		stateDB := evm.GetStateDB()
+		if !IsThresholdSigner(stateDB, precompileAddr, callerAddr) {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrNotThresholdSigner, callerAddr)
+		}
+		proposal, ok := GetCallProposal(stateDB, precompileAddr, proposalID)
+		if !ok {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrUnknownCallProposal, proposalID)
+		}
+		if proposal.Executed {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrCallAlreadyExecuted, proposalID)
+		}
+		confirmationKey := callConfirmationKey(proposalID, callerAddr)
+		if stateDB.GetState(precompileAddr, confirmationKey) == thresholdTrueHash {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrCallAlreadyConfirmed, callerAddr)
+		}
+		stateDB.SetState(precompileAddr, confirmationKey, thresholdTrueHash)
+		stateDB.SetState(precompileAddr, callProposalKey(proposalID, callConfirmationsField), common.BigToHash(new(big.Int).SetUint64(proposal.Confirmations+1)))
+		return []byte{}, remainingGas, nil
+	}
+}
+
+// createExecuteCall returns an execution function that calls the precompile with the
+// input call as the threshold admin, once the input proposal of the call has the threshold
+// of confirmations. Can be called by anyone. The remaining gas is supplied to the call.
+func createExecuteCall(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, ExecuteCallGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) < callProposalIDLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for executing call: %d", len(input))
+		}
+		proposalID := new(big.Int).SetBytes(contract.PackedHash(input, 0))
+		call, err := unpackBytes(input, 1)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if remainingGas, err = contract.DeductGas(remainingGas, keccak256GasCostOf(call)); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		stateDB := evm.GetStateDB()
+		proposal, ok := GetCallProposal(stateDB, precompileAddr, proposalID)
+		if !ok {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrUnknownCallProposal, proposalID)
+		}
+		if proposal.Executed {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrCallAlreadyExecuted, proposalID)
+		}
+		if threshold := GetThreshold(stateDB, precompileAddr); threshold == 0 || proposal.Confirmations < threshold {
+			return nil, remainingGas, fmt.Errorf("%w: %d of %d", ErrInsufficientConfirmations, proposal.Confirmations, threshold)
+		}
+		if crypto.Keccak256Hash(call) != proposal.CallHash {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrCallMismatch, proposalID)
+		}
+		module, ok := modules.GetPrecompileModuleByAddress(precompileAddr)
+		if !ok {
+			return nil, remainingGas, fmt.Errorf("no precompile registered at %s", precompileAddr)
+		}
+
+		// Mark the proposal as executed before the call, so that it cannot be executed again
+		// by the call itself.
+		stateDB.SetState(precompileAddr, callProposalKey(proposalID, callExecutedField), thresholdTrueHash)
+		return module.Contract.Run(evm, ThresholdAdminAddress(precompileAddr), precompileAddr, call, remainingGas, false)
+	}
+}
+
+// createGetCallProposal returns an execution function that returns the call hash, number
+// of confirmations and executed flag of the input proposal. Unknown proposals are returned
+// as the zero value.
+func createGetCallProposal(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, GetCallProposalGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != callProposalIDLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for get call proposal: %d", len(input))
+		}
+		proposal, _ := GetCallProposal(evm.GetStateDB(), precompileAddr, new(big.Int).SetBytes(input))
+		output, err := PackCallProposalOutput(proposal)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		return output, remainingGas, nil
+	}
+}
@@ -4,8 +4,10 @@
 package contract
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/ava-labs/subnet-evm/metrics"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -13,6 +15,9 @@ const (
 	SelectorLen = 4
 )
 
+// ErrNonPayableFunction is returned when value is sent to a function that is not payable.
+var ErrNonPayableFunction = errors.New("cannot send value to non-payable function")
+
 type RunStatefulPrecompileFunc func(accessibleState AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error)
 
 // ActivationFunc defines a function that is used to determine if a function is active
@@ -28,6 +33,8 @@ type StatefulPrecompileFunction struct {
 	execute RunStatefulPrecompileFunc
 	// activation is checked before this function is executed
 	activation ActivationFunc
+	// payable indicates this function accepts value sent by the caller
+	payable bool
 }
 
 func (f *StatefulPrecompileFunction) IsActivated(accessibleState AccessibleState) bool {
@@ -53,6 +60,37 @@ func NewStatefulPrecompileFunctionWithActivator(selector []byte, execute RunStat
 	}
 }
 
+// NewPayableStatefulPrecompileFunction creates a stateful precompile function that accepts
+// value sent by the caller. The value is credited to the precompile before [execute] is
+// called and can be read with CallValue.
+//
+// Payable functions are only supported from DUpgrade onwards, as value sent to a precompile
+// before then is credited to it without reaching any function. Configs of precompiles
+// with payable functions should reject activation timestamps before DUpgrade.
+func NewPayableStatefulPrecompileFunction(selector []byte, execute RunStatefulPrecompileFunc) *StatefulPrecompileFunction {
+	return &StatefulPrecompileFunction{
+		selector: selector,
+		execute:  execute,
+		payable:  true,
+	}
+}
+
+// NewPayableStatefulPrecompileFunctionWithActivator creates a payable stateful precompile
+// function that is only executed once activated by [activation].
+func NewPayableStatefulPrecompileFunctionWithActivator(selector []byte, execute RunStatefulPrecompileFunc, activation ActivationFunc) *StatefulPrecompileFunction {
+	return &StatefulPrecompileFunction{
+		selector:   selector,
+		execute:    execute,
+		activation: activation,
+		payable:    true,
+	}
+}
+
+// IsPayable returns true if this function accepts value sent by the caller.
+func (f *StatefulPrecompileFunction) IsPayable() bool {
+	return f.payable
+}
+
 // statefulPrecompileWithFunctionSelectors implements StatefulPrecompiledContract by using 4 byte function selectors to pass
 // off responsibilities to internal execution functions.
 // Note: because we only ever read from [functions] there no lock is required to make it thread-safe.
@@ -80,6 +118,20 @@ func NewStatefulPrecompileContract(fallback RunStatefulPrecompileFunc, functions
 	return contract, nil
 }
 
+// FunctionSelectors returns the selectors of the functions registered with [precompile],
+// or nil if [precompile] was not created by NewStatefulPrecompileContract.
+func FunctionSelectors(precompile StatefulPrecompiledContract) [][]byte {
+	s, ok := precompile.(*statefulPrecompileWithFunctionSelectors)
+	if !ok {
+		return nil
+	}
+	selectors := make([][]byte, 0, len(s.functions))
+	for _, function := range s.functions {
+		selectors = append(selectors, function.selector)
+	}
+	return selectors
+}
+
 // Run selects the function using the 4 byte function selector at the start of the input and executes the underlying function on the
 // given arguments.
 func (s *statefulPrecompileWithFunctionSelectors) Run(accessibleState AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
@@ -106,5 +158,15 @@ func (s *statefulPrecompileWithFunctionSelectors) Run(accessibleState Accessible
 		return nil, suppliedGas, fmt.Errorf("invalid non-activated function selector %#x", selector)
 	}
 
-	return function.execute(accessibleState, caller, addr, functionInput, suppliedGas, readOnly)
+	// From DUpgrade onwards, value can only be sent to payable functions. Previously, the
+	// value was credited to the precompile regardless of the function.
+	if !function.IsPayable() && CallValue(accessibleState).Sign() != 0 && accessibleState.GetChainConfig().IsDUpgrade(accessibleState.GetBlockContext().Timestamp()) {
+		return nil, suppliedGas, fmt.Errorf("%w: %#x", ErrNonPayableFunction, selector)
+	}
+
+	ret, remainingGas, err = function.execute(accessibleState, caller, addr, functionInput, suppliedGas, readOnly)
+	if metrics.Enabled {
+		getFunctionMetrics(addr, selector).update(suppliedGas-remainingGas, err)
+	}
+	return ret, remainingGas, err
 }
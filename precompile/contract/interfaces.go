@@ -28,6 +28,7 @@ type StateDB interface {
 
 	GetBalance(common.Address) *big.Int
 	AddBalance(common.Address, *big.Int)
+	SubBalance(common.Address, *big.Int)
 
 	CreateAccount(common.Address)
 	Exist(common.Address) bool
@@ -54,6 +55,33 @@ type AccessibleState interface {
 	GetChainConfig() precompileconfig.ChainConfig
 }
 
+// PayableAccessibleState is implemented by the AccessibleState of calls that transfer
+// value to the precompile.
+type PayableAccessibleState interface {
+	AccessibleState
+	// GetCallValue returns the value transferred to the precompile by the call.
+	GetCallValue() *big.Int
+}
+
+// ContractDeployerAccessibleState is implemented by the AccessibleState of the EVM, so
+// that precompiles can deploy contracts.
+type ContractDeployerAccessibleState interface {
+	AccessibleState
+	// DeployContract2 deploys a contract from [deployer] with [initCode] at the address
+	// derived from [salt] as the CREATE2 opcode does, forwarding [gas] to the init code.
+	// Returns the output of the init code, the address of the contract and the gas left.
+	DeployContract2(deployer common.Address, initCode []byte, salt common.Hash, gas uint64) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error)
+}
+
+// ContractCallerAccessibleState is implemented by the AccessibleState of the EVM, so that
+// precompiles can read from contracts.
+type ContractCallerAccessibleState interface {
+	AccessibleState
+	// StaticCallContract calls [addr] with [input] from [caller] as the STATICCALL opcode
+	// does, forwarding [gas]. Returns the output of the call and the gas left.
+	StaticCallContract(caller common.Address, addr common.Address, input []byte, gas uint64) (ret []byte, leftOverGas uint64, err error)
+}
+
 // ConfigurationBlockContext defines the interface required to configure a precompile.
 type ConfigurationBlockContext interface {
 	Number() *big.Int
@@ -76,3 +104,9 @@ type Configurator interface {
 		blockContext ConfigurationBlockContext,
 	) error
 }
+
+// Deconfigurator is optionally implemented by a Configurator whose precompile must update
+// the state before it is disabled and its account, including its balance, is deleted.
+type Deconfigurator interface {
+	Deconfigure(state StateDB, blockContext ConfigurationBlockContext) error
+}
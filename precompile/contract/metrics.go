@@ -0,0 +1,85 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// functionNames maps a 4 byte function selector to the human readable name of the function.
+	// It is populated by CalculateFunctionSelector and ParseABI so that metrics can be reported
+	// per function name rather than per selector.
+	functionNames sync.Map // string(selector) -> string
+
+	// functionMetricsCache caches the registered metrics for each (precompile address, selector) pair
+	// to avoid constructing metric names on the hot path.
+	functionMetricsCache sync.Map // functionMetricsKey -> *functionMetrics
+)
+
+type functionMetricsKey struct {
+	addr     common.Address
+	selector string
+}
+
+// functionMetrics tracks the number of invocations, the number of failed invocations, and
+// the gas consumed by a single stateful precompile function.
+type functionMetrics struct {
+	calls  metrics.Counter
+	errors metrics.Counter
+	gas    metrics.Histogram
+}
+
+// registerFunctionName records [name] as the human readable name of [selector].
+func registerFunctionName(selector []byte, name string) {
+	functionNames.LoadOrStore(string(selector), name)
+}
+
+// registerFunctionSignature records the name of the function described by [functionSignature]
+// for [selector]. Ex. "mintNativeCoin(address,uint256)" is recorded as "mintNativeCoin".
+func registerFunctionSignature(selector []byte, functionSignature string) {
+	name, _, _ := strings.Cut(functionSignature, "(")
+	registerFunctionName(selector, name)
+}
+
+// FunctionName returns the human readable name of the function with [selector] if known
+// and the hex encoded selector otherwise.
+func FunctionName(selector []byte) string {
+	if name, ok := functionNames.Load(string(selector)); ok {
+		return name.(string)
+	}
+	return fmt.Sprintf("%x", selector)
+}
+
+// getFunctionMetrics returns the metrics for the function with [selector] of the precompile at [addr],
+// registering them with the default registry on first use.
+func getFunctionMetrics(addr common.Address, selector []byte) *functionMetrics {
+	key := functionMetricsKey{addr: addr, selector: string(selector)}
+	if m, ok := functionMetricsCache.Load(key); ok {
+		return m.(*functionMetrics)
+	}
+
+	prefix := fmt.Sprintf("precompile/%s/%s", addr.Hex(), FunctionName(selector))
+	m := &functionMetrics{
+		calls:  metrics.GetOrRegisterCounter(prefix+"/calls", nil),
+		errors: metrics.GetOrRegisterCounter(prefix+"/errors", nil),
+		gas:    metrics.GetOrRegisterHistogram(prefix+"/gas", nil, metrics.NewExpDecaySample(1028, 0.015)),
+	}
+	actual, _ := functionMetricsCache.LoadOrStore(key, m)
+	return actual.(*functionMetrics)
+}
+
+// update records a single invocation of the function that consumed [gasUsed] and returned [err].
+func (m *functionMetrics) update(gasUsed uint64, err error) {
+	m.calls.Inc(1)
+	if err != nil {
+		m.errors.Inc(1)
+	}
+	m.gas.Update(int64(gasUsed))
+}
@@ -0,0 +1,36 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInsufficientPrecompileBalance = errors.New("insufficient balance for transfer")
+
+// CallValue returns the value transferred to the precompile by the current call, or
+// zero if no value was transferred.
+func CallValue(accessibleState AccessibleState) *big.Int {
+	if payable, ok := accessibleState.(PayableAccessibleState); ok {
+		if value := payable.GetCallValue(); value != nil {
+			return value
+		}
+	}
+	return new(big.Int)
+}
+
+// TransferBalance moves [amount] of native balance from [from] to [to]. Payable functions
+// use this to forward value they received, which is credited to the precompile address.
+func TransferBalance(stateDB StateDB, from common.Address, to common.Address, amount *big.Int) error {
+	if balance := stateDB.GetBalance(from); balance.Cmp(amount) < 0 {
+		return fmt.Errorf("%w: %s has %s, need %s", ErrInsufficientPrecompileBalance, from, balance, amount)
+	}
+	stateDB.SubBalance(from, amount)
+	stateDB.AddBalance(to, amount)
+	return nil
+}
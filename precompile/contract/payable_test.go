@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testPayableAccessibleState struct {
+	AccessibleState
+	value *big.Int
+}
+
+func (s *testPayableAccessibleState) GetCallValue() *big.Int { return s.value }
+
+func TestPayableFunctions(t *testing.T) {
+	var (
+		payableSelector    = CalculateFunctionSelector("deposit()")
+		nonPayableSelector = CalculateFunctionSelector("withdraw()")
+		execute            = func(accessibleState AccessibleState, _ common.Address, _ common.Address, _ []byte, suppliedGas uint64, _ bool) ([]byte, uint64, error) {
+			return CallValue(accessibleState).Bytes(), suppliedGas, nil
+		}
+	)
+	precompile, err := NewStatefulPrecompileContract(nil, []*StatefulPrecompileFunction{
+		NewPayableStatefulPrecompileFunction(payableSelector, execute),
+		NewStatefulPrecompileFunction(nonPayableSelector, execute),
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		selector    []byte
+		value       *big.Int
+		isDUpgrade  bool
+		expectedRes []byte
+		expectedErr error
+	}{
+		"payable with value": {
+			selector:    payableSelector,
+			value:       big.NewInt(10),
+			isDUpgrade:  true,
+			expectedRes: []byte{10},
+		},
+		"non-payable without value": {
+			selector:    nonPayableSelector,
+			isDUpgrade:  true,
+			expectedRes: []byte{},
+		},
+		"non-payable with value": {
+			selector:    nonPayableSelector,
+			value:       big.NewInt(10),
+			isDUpgrade:  true,
+			expectedErr: ErrNonPayableFunction,
+		},
+		"non-payable with value before DUpgrade": {
+			selector:    nonPayableSelector,
+			value:       big.NewInt(10),
+			isDUpgrade:  false,
+			expectedRes: []byte{10},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+			chainConfig.EXPECT().IsDUpgrade(gomock.Any()).Return(test.isDUpgrade).AnyTimes()
+			blockContext := NewMockBlockContext(ctrl)
+			blockContext.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
+			mockState := NewMockAccessibleState(ctrl)
+			mockState.EXPECT().GetChainConfig().Return(chainConfig).AnyTimes()
+			mockState.EXPECT().GetBlockContext().Return(blockContext).AnyTimes()
+
+			var accessibleState AccessibleState = mockState
+			if test.value != nil {
+				accessibleState = &testPayableAccessibleState{AccessibleState: mockState, value: test.value}
+			}
+			ret, _, err := precompile.Run(accessibleState, common.Address{}, common.Address{}, test.selector, 100, false)
+			require.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				require.Equal(t, test.expectedRes, ret)
+			}
+		})
+	}
+}
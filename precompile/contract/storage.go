@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Gas costs of metered storage accesses, matching the SLOAD and SSTORE opcodes
+// under EIP-2200 and EIP-2929.
+const (
+	ColdSloadGasCost       = 2_100  // Access of a slot not yet in the access list
+	WarmStorageReadGasCost = 100    // Access of a slot already in the access list
+	SstoreSetGasCost       = 20_000 // Write of a clean zero slot to non-zero
+	SstoreResetGasCost     = 2_900  // Write of a clean non-zero slot, excluding the cold access
+)
+
+// AccessListStateDB is a StateDB that tracks the storage slots accessed by the current
+// transaction, as required to meter storage accesses.
+type AccessListStateDB interface {
+	StateDB
+
+	GetCommittedState(common.Address, common.Hash) common.Hash
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+	AddSlotToAccessList(addr common.Address, slot common.Hash)
+}
+
+// GetStateMetered reads [key] of [addr] from [stateDB], charging the cost of an SLOAD
+// against [suppliedGas] and adding the slot to the access list. If [stateDB] does not
+// track accessed slots, the read is charged ReadGasCostPerSlot.
+func GetStateMetered(stateDB StateDB, addr common.Address, key common.Hash, suppliedGas uint64) (common.Hash, uint64, error) {
+	cost := uint64(ReadGasCostPerSlot)
+	if accessListStateDB, ok := stateDB.(AccessListStateDB); ok {
+		cost = accessSlot(accessListStateDB, addr, key)
+	}
+	remainingGas, err := DeductGas(suppliedGas, cost)
+	if err != nil {
+		return common.Hash{}, 0, err
+	}
+	return stateDB.GetState(addr, key), remainingGas, nil
+}
+
+// SetStateMetered writes [value] to [key] of [addr] in [stateDB], charging the cost of an
+// SSTORE against [suppliedGas] and adding the slot to the access list. If [stateDB] does
+// not track accessed slots, the write is charged WriteGasCostPerSlot.
+// Refunds are not issued, as they are disabled from SubnetEVM onwards.
+func SetStateMetered(stateDB StateDB, addr common.Address, key common.Hash, value common.Hash, suppliedGas uint64) (uint64, error) {
+	cost := uint64(WriteGasCostPerSlot)
+	if accessListStateDB, ok := stateDB.(AccessListStateDB); ok {
+		cost = sstoreCost(accessListStateDB, addr, key, value)
+	}
+	remainingGas, err := DeductGas(suppliedGas, cost)
+	if err != nil {
+		return 0, err
+	}
+	stateDB.SetState(addr, key, value)
+	return remainingGas, nil
+}
+
+// accessSlot adds [key] of [addr] to the access list and returns the cost of reading it.
+func accessSlot(stateDB AccessListStateDB, addr common.Address, key common.Hash) uint64 {
+	if _, slotOk := stateDB.SlotInAccessList(addr, key); slotOk {
+		return WarmStorageReadGasCost
+	}
+	stateDB.AddSlotToAccessList(addr, key)
+	return ColdSloadGasCost
+}
+
+// sstoreCost adds [key] of [addr] to the access list and returns the cost of writing
+// [value] to it, as in gasSStoreEIP2929.
+func sstoreCost(stateDB AccessListStateDB, addr common.Address, key common.Hash, value common.Hash) uint64 {
+	var cost uint64
+	if _, slotOk := stateDB.SlotInAccessList(addr, key); !slotOk {
+		stateDB.AddSlotToAccessList(addr, key)
+		cost = ColdSloadGasCost
+	}
+	current := stateDB.GetState(addr, key)
+	if current == value { // noop
+		return cost + WarmStorageReadGasCost
+	}
+	original := stateDB.GetCommittedState(addr, key)
+	if original == current {
+		if original == (common.Hash{}) { // create slot
+			return cost + SstoreSetGasCost
+		}
+		return cost + SstoreResetGasCost // write existing slot
+	}
+	return cost + WarmStorageReadGasCost // dirty update
+}
@@ -0,0 +1,135 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// accessListStateDB is a minimal AccessListStateDB for testing metered storage accesses.
+type accessListStateDB struct {
+	StateDB
+
+	committed map[common.Hash]common.Hash
+	dirty     map[common.Hash]common.Hash
+	accessed  map[common.Hash]bool
+}
+
+func newAccessListStateDB() *accessListStateDB {
+	return &accessListStateDB{
+		committed: make(map[common.Hash]common.Hash),
+		dirty:     make(map[common.Hash]common.Hash),
+		accessed:  make(map[common.Hash]bool),
+	}
+}
+
+func (s *accessListStateDB) GetState(_ common.Address, key common.Hash) common.Hash {
+	if value, ok := s.dirty[key]; ok {
+		return value
+	}
+	return s.committed[key]
+}
+
+func (s *accessListStateDB) SetState(_ common.Address, key common.Hash, value common.Hash) {
+	s.dirty[key] = value
+}
+
+func (s *accessListStateDB) GetCommittedState(_ common.Address, key common.Hash) common.Hash {
+	return s.committed[key]
+}
+
+func (s *accessListStateDB) SlotInAccessList(_ common.Address, key common.Hash) (bool, bool) {
+	return len(s.accessed) > 0, s.accessed[key]
+}
+
+func (s *accessListStateDB) AddSlotToAccessList(_ common.Address, key common.Hash) {
+	s.accessed[key] = true
+}
+
+func TestGetStateMetered(t *testing.T) {
+	require := require.New(t)
+
+	stateDB := newAccessListStateDB()
+	key := common.Hash{1}
+	stateDB.committed[key] = common.Hash{2}
+
+	value, remainingGas, err := GetStateMetered(stateDB, common.Address{}, key, ColdSloadGasCost+WarmStorageReadGasCost)
+	require.NoError(err)
+	require.Equal(common.Hash{2}, value)
+	require.Equal(uint64(WarmStorageReadGasCost), remainingGas)
+
+	// The slot is warm once accessed.
+	_, remainingGas, err = GetStateMetered(stateDB, common.Address{}, key, remainingGas)
+	require.NoError(err)
+	require.Zero(remainingGas)
+
+	_, _, err = GetStateMetered(stateDB, common.Address{}, common.Hash{3}, ColdSloadGasCost-1)
+	require.ErrorIs(err, vmerrs.ErrOutOfGas)
+}
+
+func TestSetStateMetered(t *testing.T) {
+	tests := map[string]struct {
+		committed    common.Hash
+		dirty        *common.Hash
+		warm         bool
+		value        common.Hash
+		expectedCost uint64
+	}{
+		"cold create slot": {
+			value:        common.Hash{1},
+			expectedCost: ColdSloadGasCost + SstoreSetGasCost,
+		},
+		"warm create slot": {
+			warm:         true,
+			value:        common.Hash{1},
+			expectedCost: SstoreSetGasCost,
+		},
+		"cold write existing slot": {
+			committed:    common.Hash{1},
+			value:        common.Hash{2},
+			expectedCost: ColdSloadGasCost + SstoreResetGasCost,
+		},
+		"cold noop": {
+			committed:    common.Hash{1},
+			value:        common.Hash{1},
+			expectedCost: ColdSloadGasCost + WarmStorageReadGasCost,
+		},
+		"warm dirty update": {
+			committed:    common.Hash{1},
+			dirty:        &common.Hash{2},
+			warm:         true,
+			value:        common.Hash{3},
+			expectedCost: WarmStorageReadGasCost,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			stateDB := newAccessListStateDB()
+			key := common.Hash{1}
+			stateDB.committed[key] = test.committed
+			if test.dirty != nil {
+				stateDB.dirty[key] = *test.dirty
+			}
+			if test.warm {
+				stateDB.accessed[key] = true
+			}
+
+			_, err := SetStateMetered(stateDB, common.Address{}, key, test.value, test.expectedCost-1)
+			require.ErrorIs(err, vmerrs.ErrOutOfGas)
+
+			// Reset the access list, which is updated even if the write is not applied.
+			stateDB.accessed = map[common.Hash]bool{key: test.warm}
+			remainingGas, err := SetStateMetered(stateDB, common.Address{}, key, test.value, test.expectedCost)
+			require.NoError(err)
+			require.Zero(remainingGas)
+			require.Equal(test.value, stateDB.GetState(common.Address{}, key))
+		})
+	}
+}
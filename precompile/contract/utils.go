@@ -18,6 +18,11 @@ import (
 const (
 	WriteGasCostPerSlot = 20_000
 	ReadGasCostPerSlot  = 5_000
+
+	// Costs of emitting a log, matching the LOG* opcodes
+	LogGas            = 375 // Per log
+	LogTopicGas       = 375 // Per topic
+	LogDataGasPerByte = 8   // Per byte of data
 )
 
 var functionSignatureRegex = regexp.MustCompile(`\w+\((\w*|(\w+,)+\w+)\)`)
@@ -31,7 +36,9 @@ func CalculateFunctionSelector(functionSignature string) []byte {
 		panic(fmt.Errorf("invalid function signature: %q", functionSignature))
 	}
 	hash := crypto.Keccak256([]byte(functionSignature))
-	return hash[:4]
+	selector := hash[:4]
+	registerFunctionSignature(selector, functionSignature)
+	return selector
 }
 
 // DeductGas checks if [suppliedGas] is sufficient against [requiredGas] and deducts [requiredGas] from [suppliedGas].
@@ -85,6 +92,9 @@ func ParseABI(rawABI string) abi.ABI {
 	if err != nil {
 		panic(err)
 	}
+	for _, method := range parsed.Methods {
+		registerFunctionName(method.ID, method.Name)
+	}
 
 	return parsed
 }
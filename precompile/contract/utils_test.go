@@ -64,3 +64,13 @@ func TestFunctionSignatureRegex(t *testing.T) {
 		assert.Equal(t, test.pass, functionSignatureRegex.MatchString(test.str), "unexpected result for %q", test.str)
 	}
 }
+
+func TestFunctionName(t *testing.T) {
+	selector := CalculateFunctionSelector("testFunctionName(address,uint256)")
+	assert.Equal(t, "testFunctionName", FunctionName(selector))
+
+	parsed := ParseABI(`[{"inputs":[],"name":"testABIFunctionName","outputs":[],"stateMutability":"view","type":"function"}]`)
+	assert.Equal(t, "testABIFunctionName", FunctionName(parsed.Methods["testABIFunctionName"].ID))
+
+	assert.Equal(t, "deadbeef", FunctionName([]byte{0xde, 0xad, 0xbe, 0xef}))
+}
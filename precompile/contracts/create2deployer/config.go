@@ -0,0 +1,62 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package create2deployer
+
+import (
+	"errors"
+
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+)
+
+var _ precompileconfig.Config = &Config{}
+
+var errCreate2DeployerCannotBeActivated = errors.New("create2 deployer cannot be activated before DUpgrade")
+
+// Config implements the precompileconfig.Config interface for the CREATE2 deployer,
+// which has no parameters.
+type Config struct {
+	precompileconfig.Upgrade
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the CREATE2 deployer.
+func NewConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the CREATE2 deployer.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the CREATE2 deployer precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	// Init code is metered as by EIP-3860, which only applies from DUpgrade onwards.
+	if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errCreate2DeployerCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade)
+}
@@ -0,0 +1,149 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package create2deployer
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The CREATE2 deployer deploys contracts as the CREATE2 opcode does, with the precompile as
+// the deployer. Since the precompile is at the same address on every chain, the address of a
+// contract only depends on the salt and init code, without funding a deployer account or
+// replaying its transactions first. Anyone can deploy, subject to the deployer allow list.
+
+const (
+	// DeployGasCost is charged as by the CREATE2 opcode, plus DeployGasCostPerWord for each
+	// word of init code. The gas left after that is forwarded to the init code, except for
+	// the 1/64th retained as by EIP-150.
+	DeployGasCost        uint64 = params.Create2Gas
+	DeployGasCostPerWord uint64 = params.InitCodeWordGas + params.Keccak256WordGas
+
+	// ComputeAddressGasCost hashes the 85 bytes the address is derived from.
+	ComputeAddressGasCost uint64 = params.Keccak256Gas + 3*params.Keccak256WordGas
+)
+
+var (
+	ErrDeployUnsupported = errors.New("contract deployment is not supported by the accessible state")
+
+	// Create2DeployerRawABI contains the raw ABI of Create2Deployer contract.
+	//go:embed contract.abi
+	Create2DeployerRawABI string
+
+	Create2DeployerABI        = contract.ParseABI(Create2DeployerRawABI)
+	Create2DeployerPrecompile = createCreate2DeployerPrecompile()
+)
+
+// ComputeAddress returns the address a contract with init code hashing to [initCodeHash]
+// is deployed at with [salt].
+func ComputeAddress(salt common.Hash, initCodeHash common.Hash) common.Address {
+	return crypto.CreateAddress2(ContractAddress, salt, initCodeHash.Bytes())
+}
+
+// PackDeploy packs [salt] and [initCode] into the input for deploy, including the selector.
+func PackDeploy(salt common.Hash, initCode []byte) ([]byte, error) {
+	return Create2DeployerABI.Pack("deploy", salt, initCode)
+}
+
+// PackAddressOutput packs [contractAddr] to conform the ABI outputs of [method].
+func PackAddressOutput(method string, contractAddr common.Address) ([]byte, error) {
+	return Create2DeployerABI.PackOutput(method, contractAddr)
+}
+
+// deploy deploys the input init code at the address derived from the input salt.
+func deploy(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DeployGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := Create2DeployerABI.UnpackInput("deploy", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	salt := common.Hash(*abi.ConvertType(res[0], new([32]byte)).(*[32]byte))
+	initCode := *abi.ConvertType(res[1], new([]byte)).(*[]byte)
+
+	if len(initCode) > params.MaxInitCodeSize {
+		return nil, remainingGas, fmt.Errorf("%w: size %d", vmerrs.ErrMaxInitCodeSizeExceeded, len(initCode))
+	}
+	words := uint64(len(initCode)+31) / 32
+	if remainingGas, err = contract.DeductGas(remainingGas, DeployGasCostPerWord*words); err != nil {
+		return nil, 0, err
+	}
+
+	deployer, ok := accessibleState.(contract.ContractDeployerAccessibleState)
+	if !ok {
+		return nil, remainingGas, ErrDeployUnsupported
+	}
+	gas := remainingGas - remainingGas/64
+	remainingGas -= gas
+	ret, contractAddr, leftOverGas, err := deployer.DeployContract2(ContractAddress, initCode, salt, gas)
+	remainingGas += leftOverGas
+	if err != nil {
+		// Return the revert reason of the init code, if any.
+		return ret, remainingGas, err
+	}
+	packedOutput, err := PackAddressOutput("deploy", contractAddr)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackComputeAddress packs [salt] and [initCodeHash] into the input for computeAddress,
+// including the selector.
+func PackComputeAddress(salt common.Hash, initCodeHash common.Hash) ([]byte, error) {
+	return Create2DeployerABI.Pack("computeAddress", salt, initCodeHash)
+}
+
+func computeAddress(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ComputeAddressGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := Create2DeployerABI.UnpackInput("computeAddress", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	salt := common.Hash(*abi.ConvertType(res[0], new([32]byte)).(*[32]byte))
+	initCodeHash := common.Hash(*abi.ConvertType(res[1], new([32]byte)).(*[32]byte))
+	packedOutput, err := PackAddressOutput("computeAddress", ComputeAddress(salt, initCodeHash))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// createCreate2DeployerPrecompile returns a StatefulPrecompiledContract implementing the
+// CREATE2 deployer.
+func createCreate2DeployerPrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"computeAddress": computeAddress,
+		"deploy":         deploy,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := Create2DeployerABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
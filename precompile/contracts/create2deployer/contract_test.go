@@ -0,0 +1,120 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package create2deployer
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testCaller   = common.Address{'c'}
+	testSalt     = common.Hash{'s'}
+	testInitCode = []byte{0x60, 0x00, 0x60, 0x00, 0xf3} // return an empty runtime code
+)
+
+func TestCreate2DeployerRun(t *testing.T) {
+	tests := map[string]testutils.PrecompileTest{
+		"compute address": {
+			Caller: testCaller,
+			Input: func() []byte {
+				input, _ := PackComputeAddress(testSalt, crypto.Keccak256Hash(testInitCode))
+				return input
+			}(),
+			SuppliedGas: ComputeAddressGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, _ := PackAddressOutput("computeAddress", crypto.CreateAddress2(ContractAddress, testSalt, crypto.Keccak256(testInitCode)))
+				return res
+			}(),
+		},
+		"compute address insufficient gas": {
+			Caller:      testCaller,
+			Input:       func() []byte { input, _ := PackComputeAddress(testSalt, common.Hash{}); return input }(),
+			SuppliedGas: ComputeAddressGasCost - 1,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"deploy readOnly": {
+			Caller:      testCaller,
+			Input:       func() []byte { input, _ := PackDeploy(testSalt, testInitCode); return input }(),
+			SuppliedGas: DeployGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"deploy init code too large": {
+			Caller:      testCaller,
+			Input:       func() []byte { input, _ := PackDeploy(testSalt, make([]byte, params.MaxInitCodeSize+1)); return input }(),
+			SuppliedGas: DeployGasCost,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrMaxInitCodeSizeExceeded.Error(),
+		},
+		"deploy insufficient gas for init code": {
+			Caller:      testCaller,
+			Input:       func() []byte { input, _ := PackDeploy(testSalt, testInitCode); return input }(),
+			SuppliedGas: DeployGasCost + DeployGasCostPerWord - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.Run(t, Module, state.NewTestStateDB(t))
+		})
+	}
+}
+
+// testDeployerAccessibleState records the contract deployed through it.
+type testDeployerAccessibleState struct {
+	contract.AccessibleState
+	deployer common.Address
+	initCode []byte
+	salt     common.Hash
+	gas      uint64
+}
+
+func (s *testDeployerAccessibleState) DeployContract2(deployer common.Address, initCode []byte, salt common.Hash, gas uint64) ([]byte, common.Address, uint64, error) {
+	s.deployer, s.initCode, s.salt, s.gas = deployer, initCode, salt, gas
+	return nil, crypto.CreateAddress2(deployer, salt, crypto.Keccak256(initCode)), gas / 2, nil
+}
+
+func TestCreate2DeployerDeploy(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	accessibleState := contract.NewMockAccessibleState(ctrl)
+	deployerState := &testDeployerAccessibleState{AccessibleState: accessibleState}
+
+	input, err := PackDeploy(testSalt, testInitCode)
+	require.NoError(err)
+	const initCodeGas = 64_000
+	suppliedGas := DeployGasCost + DeployGasCostPerWord + initCodeGas
+	ret, remainingGas, err := Create2DeployerPrecompile.Run(deployerState, testCaller, ContractAddress, input, suppliedGas, false)
+	require.NoError(err)
+
+	// The contract is deployed by the precompile regardless of the caller.
+	require.Equal(ContractAddress, deployerState.deployer)
+	require.Equal(testInitCode, deployerState.initCode)
+	require.Equal(testSalt, deployerState.salt)
+	// All but 1/64th of the gas is forwarded, and the gas left over is returned.
+	require.Equal(uint64(initCodeGas-initCodeGas/64), deployerState.gas)
+	require.Equal(uint64(initCodeGas/64+deployerState.gas/2), remainingGas)
+
+	expected, err := PackAddressOutput("deploy", ComputeAddress(testSalt, crypto.Keccak256Hash(testInitCode)))
+	require.NoError(err)
+	require.Equal(expected, ret)
+
+	// Deploying requires an accessible state that supports it.
+	_, _, err = Create2DeployerPrecompile.Run(accessibleState, testCaller, ContractAddress, input, suppliedGas, false)
+	require.ErrorIs(err, ErrDeployUnsupported)
+}
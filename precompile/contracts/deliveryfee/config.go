@@ -0,0 +1,78 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package deliveryfee
+
+import (
+	"errors"
+
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+)
+
+// DefaultReclaimDelay is the number of seconds after which an unclaimed delivery fee can be
+// reclaimed by its depositor, unless the config specifies otherwise.
+const DefaultReclaimDelay uint64 = 7 * 24 * 60 * 60
+
+var _ precompileconfig.Config = &Config{}
+
+var errDeliveryFeeCannotBeActivated = errors.New("delivery fee escrow cannot be activated before DUpgrade")
+
+// Config implements the precompileconfig.Config interface while adding in the
+// delivery fee escrow specific precompile config.
+type Config struct {
+	precompileconfig.Upgrade
+	// ReclaimDelay is the number of seconds after which the depositor of an unclaimed
+	// delivery fee can reclaim it (0 denotes using DefaultReclaimDelay).
+	ReclaimDelay uint64 `json:"reclaimDelay,omitempty"`
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the delivery fee escrow with [reclaimDelay].
+func NewConfig(blockTimestamp *uint64, reclaimDelay uint64) *Config {
+	return &Config{
+		Upgrade:      precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		ReclaimDelay: reclaimDelay,
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the delivery fee escrow.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the delivery fee escrow precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	// Native fees are paid to a payable function, see [contract.NewPayableStatefulPrecompileFunction].
+	if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errDeliveryFeeCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) && c.ReclaimDelay == other.ReclaimDelay
+}
+
+// reclaimDelay returns the reclaim delay of [c], using the default if unspecified.
+func (c *Config) reclaimDelay() uint64 {
+	if c.ReclaimDelay == 0 {
+		return DefaultReclaimDelay
+	}
+	return c.ReclaimDelay
+}
@@ -0,0 +1,65 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package deliveryfee
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerify(t *testing.T) {
+	tests := map[string]testutils.ConfigVerifyTest{
+		"valid config": {
+			Config: NewConfig(utils.NewUint64(3), 60),
+		},
+		"default reclaim delay": {
+			Config: NewConfig(utils.NewUint64(3), 0),
+		},
+		"cannot be activated before DUpgrade": {
+			Config: NewConfig(utils.NewUint64(3), 0),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errDeliveryFeeCannotBeActivated.Error(),
+		},
+	}
+	testutils.RunVerifyTests(t, tests)
+}
+
+func TestEqual(t *testing.T) {
+	tests := map[string]testutils.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), 0),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(utils.NewUint64(3), 0),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different timestamp": {
+			Config:   NewConfig(utils.NewUint64(3), 0),
+			Other:    NewConfig(utils.NewUint64(4), 0),
+			Expected: false,
+		},
+		"different reclaim delay": {
+			Config:   NewConfig(utils.NewUint64(3), 60),
+			Other:    NewConfig(utils.NewUint64(3), 0),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), 60),
+			Other:    NewConfig(utils.NewUint64(3), 60),
+			Expected: true,
+		},
+	}
+	testutils.RunEqualTests(t, tests)
+}
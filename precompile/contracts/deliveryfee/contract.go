@@ -0,0 +1,627 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package deliveryfee
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
+	"github.com/ava-labs/subnet-evm/x/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The delivery fee escrow pays relayers for delivering warp messages:
+//
+//  1. On the source chain, anyone escrows a fee for a message ID with addDeliveryFee. The fee
+//     is paid in native tokens, or in an ERC-20 token implemented by a precompile (such as
+//     the wrapped native token), which the escrow pulls with transferFrom. Fees are escrowed
+//     per message ID and depositor, so any number of depositors can add a fee for a message.
+//  2. On the destination chain, the destination contract of the message calls recordDelivery
+//     when it receives the message, naming the relayer. The escrow then sends a receipt warp
+//     message, containing the message ID and the relayer, back to the escrow on the source chain.
+//  3. On the source chain, anyone calls claimDeliveryFee with the verified receipt and the
+//     depositors of the fees to claim, paying the escrowed fees to the relayer.
+//
+// Fees that are not claimed can be reclaimed by their depositor after the reclaim delay.
+// The escrow relies on the warp precompile to send and verify messages.
+
+const (
+	GetDeliveryFeeGasCost uint64 = contract.ReadGasCostPerSlot * numFeeField
+	IsDeliveredGasCost    uint64 = contract.ReadGasCostPerSlot
+
+	AddDeliveryFeeGasCost uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot*numFeeField + // check and write the fee
+		contract.LogGas + 3*contract.LogTopicGas + 2*common.HashLength*contract.LogDataGasPerByte // DeliveryFeeAdded log
+	RecordDeliveryGasCost uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot + // check and mark delivery
+		contract.LogGas + 3*contract.LogTopicGas // DeliveryRecorded log
+	// ClaimDeliveryFeeGasCost is charged for each fee claimed.
+	ClaimDeliveryFeeGasCost uint64 = contract.ReadGasCostPerSlot*numFeeField + contract.WriteGasCostPerSlot*(numFeeField+2) + // read and clear the fee, transfer native balance
+		contract.LogGas + 3*contract.LogTopicGas + 2*common.HashLength*contract.LogDataGasPerByte // DeliveryFeeClaimed log
+	ReclaimDeliveryFeeGasCost uint64 = contract.ReadGasCostPerSlot*numFeeField + contract.WriteGasCostPerSlot*(numFeeField+2) + // read and clear the fee, transfer native balance
+		contract.LogGas + 3*contract.LogTopicGas + common.HashLength*contract.LogDataGasPerByte // DeliveryFeeReclaimed log
+
+	// receiptLen is the length of a receipt payload: abi.encode(bytes32 messageID, address relayer).
+	receiptLen = 2 * common.HashLength
+)
+
+// Fields of an escrowed delivery fee
+const (
+	depositorField = iota
+	destinationChainIDField
+	feeTokenField
+	amountField
+	reclaimableAtField
+	numFeeField
+)
+
+var (
+	ErrZeroDeliveryFee       = errors.New("delivery fee must be non-zero")
+	ErrDeliveryFeeExists     = errors.New("delivery fee already escrowed for message by depositor")
+	ErrUnknownDeliveryFee    = errors.New("no delivery fee escrowed for message by depositor")
+	ErrNoDepositors          = errors.New("no depositors to claim delivery fees of")
+	ErrInvalidFeeValue       = errors.New("value sent does not match delivery fee")
+	ErrUnsupportedFeeToken   = errors.New("fee token is not a precompile")
+	ErrFeeTokenCallFailed    = errors.New("fee token transfer failed")
+	ErrInvalidWarpMessage    = errors.New("invalid warp message")
+	ErrNotMessageDestination = errors.New("caller is not the destination of the message")
+	ErrAlreadyDelivered      = errors.New("message delivery already recorded")
+	ErrInvalidReceipt        = errors.New("invalid delivery receipt")
+	ErrReclaimTooEarly       = errors.New("delivery fee cannot be reclaimed yet")
+
+	// DeliveryFeeRawABI contains the raw ABI of DeliveryFee contract.
+	//go:embed contract.abi
+	DeliveryFeeRawABI string
+
+	DeliveryFeeABI        = contract.ParseABI(DeliveryFeeRawABI)
+	DeliveryFeePrecompile = createDeliveryFeePrecompile()
+
+	// erc20ABI contains the functions the escrow calls on fee tokens.
+	erc20ABI = contract.ParseABI(`[{"inputs":[{"name":"dst","type":"address"},{"name":"wad","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"src","type":"address"},{"name":"dst","type":"address"},{"name":"wad","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`)
+
+	reclaimDelayKey = common.Hash{'d', 'f', 'r', 'd'}
+
+	feeKeyPrefix       = []byte("deliveryFee")
+	deliveredKeyPrefix = []byte("delivered")
+
+	deliveredHash = common.BigToHash(common.Big1)
+)
+
+// DeliveryFee is a fee escrowed for the delivery of a warp message.
+type DeliveryFee struct {
+	Depositor          common.Address
+	DestinationChainID common.Hash
+	// FeeToken is the ERC-20 token of the fee, or the zero address for native tokens.
+	FeeToken      common.Address
+	Amount        *big.Int
+	ReclaimableAt uint64
+}
+
+func feeKey(messageID common.Hash, depositor common.Address, field int) common.Hash {
+	return crypto.Keccak256Hash(feeKeyPrefix, messageID.Bytes(), depositor.Bytes(), []byte{byte(field)})
+}
+
+func deliveredKey(messageID common.Hash) common.Hash {
+	return crypto.Keccak256Hash(deliveredKeyPrefix, messageID.Bytes())
+}
+
+func storeReclaimDelay(stateDB contract.StateDB, reclaimDelay uint64) {
+	stateDB.SetState(ContractAddress, reclaimDelayKey, common.BigToHash(new(big.Int).SetUint64(reclaimDelay)))
+}
+
+// GetReclaimDelay returns the number of seconds after which unclaimed fees can be reclaimed.
+func GetReclaimDelay(stateDB contract.StateDB) uint64 {
+	return stateDB.GetState(ContractAddress, reclaimDelayKey).Big().Uint64()
+}
+
+// GetDeliveryFee returns the fee escrowed for [messageID] by [depositor] and whether it exists.
+func GetDeliveryFee(stateDB contract.StateDB, messageID common.Hash, depositor common.Address) (DeliveryFee, bool) {
+	fee := DeliveryFee{
+		Depositor:          common.BytesToAddress(stateDB.GetState(ContractAddress, feeKey(messageID, depositor, depositorField)).Bytes()),
+		DestinationChainID: stateDB.GetState(ContractAddress, feeKey(messageID, depositor, destinationChainIDField)),
+		FeeToken:           common.BytesToAddress(stateDB.GetState(ContractAddress, feeKey(messageID, depositor, feeTokenField)).Bytes()),
+		Amount:             stateDB.GetState(ContractAddress, feeKey(messageID, depositor, amountField)).Big(),
+		ReclaimableAt:      stateDB.GetState(ContractAddress, feeKey(messageID, depositor, reclaimableAtField)).Big().Uint64(),
+	}
+	// Fees are non-zero, so a zero amount denotes a missing fee.
+	return fee, fee.Amount.Sign() != 0
+}
+
+func setDeliveryFee(stateDB contract.StateDB, messageID common.Hash, fee DeliveryFee) {
+	stateDB.SetState(ContractAddress, feeKey(messageID, fee.Depositor, depositorField), fee.Depositor.Hash())
+	stateDB.SetState(ContractAddress, feeKey(messageID, fee.Depositor, destinationChainIDField), fee.DestinationChainID)
+	stateDB.SetState(ContractAddress, feeKey(messageID, fee.Depositor, feeTokenField), fee.FeeToken.Hash())
+	stateDB.SetState(ContractAddress, feeKey(messageID, fee.Depositor, amountField), common.BigToHash(fee.Amount))
+	stateDB.SetState(ContractAddress, feeKey(messageID, fee.Depositor, reclaimableAtField), common.BigToHash(new(big.Int).SetUint64(fee.ReclaimableAt)))
+}
+
+func deleteDeliveryFee(stateDB contract.StateDB, messageID common.Hash, depositor common.Address) {
+	for field := 0; field < numFeeField; field++ {
+		stateDB.SetState(ContractAddress, feeKey(messageID, depositor, field), common.Hash{})
+	}
+}
+
+// IsDelivered returns whether the delivery of [messageID] to this chain has been recorded.
+func IsDelivered(stateDB contract.StateDB, messageID common.Hash) bool {
+	return stateDB.GetState(ContractAddress, deliveredKey(messageID)) == deliveredHash
+}
+
+// MessageID returns the ID of the warp message sent on [sourceChainID] of [networkID] by
+// [originSender] to [destinationAddress] on [destinationChainID] with [payload]. This is the
+// ID of the unsigned warp message signed by the validators of the source chain.
+func MessageID(networkID uint32, sourceChainID common.Hash, originSender common.Address, destinationChainID common.Hash, destinationAddress common.Address, payload []byte) (common.Hash, error) {
+	addressedPayload, err := warpPayload.NewAddressedPayload(originSender, destinationChainID, destinationAddress, payload)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(networkID, ids.ID(sourceChainID), addressedPayload.Bytes())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(unsignedMessage.ID()), nil
+}
+
+// messageIDGasCost returns the gas cost of hashing a warp message with [payloadLen] bytes of payload.
+func messageIDGasCost(payloadLen int) (uint64, error) {
+	wordGas, overflow := math.SafeMul(params.Sha256PerWordGas, (uint64(payloadLen)+31)/32+warpMessageOverheadWords)
+	if overflow {
+		return 0, vmerrs.ErrOutOfGas
+	}
+	return params.Sha256BaseGas + wordGas, nil
+}
+
+// warpMessageOverheadWords bounds the number of words of an unsigned warp message that are
+// not part of its payload.
+const warpMessageOverheadWords = 6
+
+// PackReceipt packs the payload of the receipt of the delivery of [messageID] by [relayer].
+func PackReceipt(messageID common.Hash, relayer common.Address) []byte {
+	return append(messageID.Bytes(), relayer.Hash().Bytes()...)
+}
+
+// UnpackReceipt unpacks a receipt payload packed by PackReceipt.
+func UnpackReceipt(payload []byte) (common.Hash, common.Address, error) {
+	if len(payload) != receiptLen {
+		return common.Hash{}, common.Address{}, fmt.Errorf("%w: payload length %d", ErrInvalidReceipt, len(payload))
+	}
+	return common.BytesToHash(payload[:common.HashLength]), common.BytesToAddress(payload[common.HashLength:]), nil
+}
+
+// emitEvent adds a log of the event [name] with [args] to the state.
+func emitEvent(accessibleState contract.AccessibleState, name string, args ...interface{}) error {
+	topics, data, err := DeliveryFeeABI.PackEvent(name, args...)
+	if err != nil {
+		return err
+	}
+	accessibleState.GetStateDB().AddLog(ContractAddress, topics, data, accessibleState.GetBlockContext().Number().Uint64())
+	return nil
+}
+
+// callFeeToken calls [method] of the precompile [feeToken] with [args] as the escrow, and
+// requires it to succeed and return true.
+func callFeeToken(accessibleState contract.AccessibleState, feeToken common.Address, suppliedGas uint64, method string, args ...interface{}) (uint64, error) {
+	module, ok := modules.GetPrecompileModuleByAddress(feeToken)
+	if !ok {
+		return suppliedGas, fmt.Errorf("%w: %s", ErrUnsupportedFeeToken, feeToken)
+	}
+	input, err := erc20ABI.Pack(method, args...)
+	if err != nil {
+		return suppliedGas, err
+	}
+	ret, remainingGas, err := module.Contract.Run(accessibleState, ContractAddress, feeToken, input, suppliedGas, false)
+	if err != nil {
+		return remainingGas, fmt.Errorf("%w: %s", ErrFeeTokenCallFailed, err)
+	}
+	if len(ret) != common.HashLength || common.BytesToHash(ret) != common.BigToHash(common.Big1) {
+		return remainingGas, fmt.Errorf("%w: %s returned %#x", ErrFeeTokenCallFailed, method, ret)
+	}
+	return remainingGas, nil
+}
+
+// payFee transfers [fee] from the escrow to [recipient].
+func payFee(accessibleState contract.AccessibleState, fee DeliveryFee, recipient common.Address, suppliedGas uint64) (uint64, error) {
+	if fee.FeeToken == (common.Address{}) {
+		return suppliedGas, contract.TransferBalance(accessibleState.GetStateDB(), ContractAddress, recipient, fee.Amount)
+	}
+	return callFeeToken(accessibleState, fee.FeeToken, suppliedGas, "transfer", recipient, fee.Amount)
+}
+
+// getVerifiedWarpMessage returns the warp message verified at [index] in the predicates of
+// the current transaction, charging the gas of the warp precompile.
+func getVerifiedWarpMessage(accessibleState contract.AccessibleState, index uint32, suppliedGas uint64) (warp.WarpMessage, uint64, error) {
+	input, err := warp.PackGetVerifiedWarpMessage(index)
+	if err != nil {
+		return warp.WarpMessage{}, suppliedGas, err
+	}
+	ret, remainingGas, err := warp.WarpPrecompile.Run(accessibleState, ContractAddress, warp.ContractAddress, input, suppliedGas, true)
+	if err != nil {
+		return warp.WarpMessage{}, remainingGas, err
+	}
+	output, err := warp.UnpackGetVerifiedWarpMessageOutput(ret)
+	if err != nil {
+		return warp.WarpMessage{}, remainingGas, err
+	}
+	if !output.Valid {
+		return warp.WarpMessage{}, remainingGas, fmt.Errorf("%w at index %d", ErrInvalidWarpMessage, index)
+	}
+	return output.Message, remainingGas, nil
+}
+
+// PackAddDeliveryFee packs the inputs of addDeliveryFee, including the selector.
+func PackAddDeliveryFee(messageID common.Hash, destinationChainID common.Hash, feeToken common.Address, amount *big.Int) ([]byte, error) {
+	return DeliveryFeeABI.Pack("addDeliveryFee", messageID, destinationChainID, feeToken, amount)
+}
+
+// addDeliveryFee escrows a fee from the caller for the delivery of the input message ID to
+// the input destination chain. Native fees must be sent as the value of the call, and ERC-20
+// fees must be approved for the escrow in advance. Each depositor can escrow one fee per
+// message, independently of the fees of other depositors.
+func addDeliveryFee(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, AddDeliveryFeeGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := DeliveryFeeABI.UnpackInput("addDeliveryFee", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	messageID := common.Hash(*abi.ConvertType(res[0], new([32]byte)).(*[32]byte))
+	destinationChainID := common.Hash(*abi.ConvertType(res[1], new([32]byte)).(*[32]byte))
+	feeToken := *abi.ConvertType(res[2], new(common.Address)).(*common.Address)
+	amount := *abi.ConvertType(res[3], new(*big.Int)).(**big.Int)
+
+	if amount.Sign() == 0 {
+		return nil, remainingGas, ErrZeroDeliveryFee
+	}
+	stateDB := accessibleState.GetStateDB()
+	if _, exists := GetDeliveryFee(stateDB, messageID, caller); exists {
+		return nil, remainingGas, fmt.Errorf("%w: %s by %s", ErrDeliveryFeeExists, messageID, caller)
+	}
+
+	value := contract.CallValue(accessibleState)
+	if feeToken == (common.Address{}) {
+		// The value has already been credited to the escrow.
+		if value.Cmp(amount) != 0 {
+			return nil, remainingGas, fmt.Errorf("%w: sent %s, fee %s", ErrInvalidFeeValue, value, amount)
+		}
+	} else {
+		if value.Sign() != 0 {
+			return nil, remainingGas, fmt.Errorf("%w: sent %s with token fee", ErrInvalidFeeValue, value)
+		}
+		if remainingGas, err = callFeeToken(accessibleState, feeToken, remainingGas, "transferFrom", caller, ContractAddress, amount); err != nil {
+			return nil, remainingGas, err
+		}
+	}
+
+	reclaimableAt, overflow := math.SafeAdd(accessibleState.GetBlockContext().Timestamp(), GetReclaimDelay(stateDB))
+	if overflow {
+		reclaimableAt = math.MaxUint64
+	}
+	setDeliveryFee(stateDB, messageID, DeliveryFee{
+		Depositor:          caller,
+		DestinationChainID: destinationChainID,
+		FeeToken:           feeToken,
+		Amount:             amount,
+		ReclaimableAt:      reclaimableAt,
+	})
+	if err := emitEvent(accessibleState, "DeliveryFeeAdded", messageID, caller, feeToken, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackRecordDelivery packs the inputs of recordDelivery, including the selector.
+func PackRecordDelivery(index uint32, relayer common.Address) ([]byte, error) {
+	return DeliveryFeeABI.Pack("recordDelivery", index, relayer)
+}
+
+// recordDelivery records the delivery of the warp message verified at the input index to its
+// destination, which must be the caller, and sends a receipt crediting the input relayer to
+// the escrow on the source chain of the message. Each message can be recorded once.
+func recordDelivery(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RecordDeliveryGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := DeliveryFeeABI.UnpackInput("recordDelivery", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	index := *abi.ConvertType(res[0], new(uint32)).(*uint32)
+	relayer := *abi.ConvertType(res[1], new(common.Address)).(*common.Address)
+
+	message, remainingGas, err := getVerifiedWarpMessage(accessibleState, index, remainingGas)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if message.DestinationAddress != caller {
+		return nil, remainingGas, fmt.Errorf("%w: destination %s, caller %s", ErrNotMessageDestination, message.DestinationAddress, caller)
+	}
+	hashGas, err := messageIDGasCost(len(message.Payload))
+	if err != nil {
+		return nil, 0, err
+	}
+	if remainingGas, err = contract.DeductGas(remainingGas, hashGas); err != nil {
+		return nil, 0, err
+	}
+	messageID, err := MessageID(accessibleState.GetSnowContext().NetworkID, message.SourceChainID, message.OriginSenderAddress, message.DestinationChainID, message.DestinationAddress, message.Payload)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	if IsDelivered(stateDB, messageID) {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrAlreadyDelivered, messageID)
+	}
+	stateDB.SetState(ContractAddress, deliveredKey(messageID), deliveredHash)
+
+	sendInput, err := warp.PackSendWarpMessage(warp.SendWarpMessageInput{
+		DestinationChainID: message.SourceChainID,
+		DestinationAddress: ContractAddress,
+		Payload:            PackReceipt(messageID, relayer),
+	})
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if _, remainingGas, err = warp.WarpPrecompile.Run(accessibleState, ContractAddress, warp.ContractAddress, sendInput, remainingGas, false); err != nil {
+		return nil, remainingGas, err
+	}
+	if err := emitEvent(accessibleState, "DeliveryRecorded", messageID, relayer); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackClaimDeliveryFee packs the inputs of claimDeliveryFee, including the selector.
+func PackClaimDeliveryFee(index uint32, depositors []common.Address) ([]byte, error) {
+	return DeliveryFeeABI.Pack("claimDeliveryFee", index, depositors)
+}
+
+// claimDeliveryFee pays the fees escrowed by the input depositors for a delivered message to
+// its relayer, given the receipt sent by the escrow on the destination chain of the message,
+// verified at the input index.
+func claimDeliveryFee(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ClaimDeliveryFeeGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := DeliveryFeeABI.UnpackInput("claimDeliveryFee", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	index := *abi.ConvertType(res[0], new(uint32)).(*uint32)
+	depositors := *abi.ConvertType(res[1], new([]common.Address)).(*[]common.Address)
+	if len(depositors) == 0 {
+		return nil, remainingGas, ErrNoDepositors
+	}
+	// The cost of the first fee was charged before unpacking the input.
+	claimGas, overflow := math.SafeMul(ClaimDeliveryFeeGasCost, uint64(len(depositors)-1))
+	if overflow {
+		return nil, 0, vmerrs.ErrOutOfGas
+	}
+	if remainingGas, err = contract.DeductGas(remainingGas, claimGas); err != nil {
+		return nil, 0, err
+	}
+
+	receipt, remainingGas, err := getVerifiedWarpMessage(accessibleState, index, remainingGas)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if receipt.OriginSenderAddress != ContractAddress || receipt.DestinationAddress != ContractAddress ||
+		receipt.DestinationChainID != common.Hash(accessibleState.GetSnowContext().ChainID) {
+		return nil, remainingGas, fmt.Errorf("%w: not sent by the escrow to this chain", ErrInvalidReceipt)
+	}
+	messageID, relayer, err := UnpackReceipt(receipt.Payload)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	for _, depositor := range depositors {
+		fee, exists := GetDeliveryFee(stateDB, messageID, depositor)
+		if !exists {
+			return nil, remainingGas, fmt.Errorf("%w: %s by %s", ErrUnknownDeliveryFee, messageID, depositor)
+		}
+		if fee.DestinationChainID != receipt.SourceChainID {
+			return nil, remainingGas, fmt.Errorf("%w: sent by %s, fee of %s is for delivery to %s", ErrInvalidReceipt, receipt.SourceChainID, depositor, fee.DestinationChainID)
+		}
+		// Delete the fee before paying it, so that it cannot be claimed again.
+		deleteDeliveryFee(stateDB, messageID, depositor)
+		if remainingGas, err = payFee(accessibleState, fee, relayer, remainingGas); err != nil {
+			return nil, remainingGas, err
+		}
+		if err := emitEvent(accessibleState, "DeliveryFeeClaimed", messageID, relayer, depositor, fee.Amount); err != nil {
+			return nil, remainingGas, err
+		}
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackReclaimDeliveryFee packs the inputs of reclaimDeliveryFee, including the selector.
+func PackReclaimDeliveryFee(messageID common.Hash) ([]byte, error) {
+	return DeliveryFeeABI.Pack("reclaimDeliveryFee", messageID)
+}
+
+// reclaimDeliveryFee returns the unclaimed fee escrowed by the caller for the input message
+// ID, once the reclaim delay has passed.
+func reclaimDeliveryFee(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ReclaimDeliveryFeeGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := DeliveryFeeABI.UnpackInput("reclaimDeliveryFee", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	messageID := common.Hash(*abi.ConvertType(res[0], new([32]byte)).(*[32]byte))
+
+	stateDB := accessibleState.GetStateDB()
+	fee, exists := GetDeliveryFee(stateDB, messageID, caller)
+	if !exists {
+		return nil, remainingGas, fmt.Errorf("%w: %s by %s", ErrUnknownDeliveryFee, messageID, caller)
+	}
+	if timestamp := accessibleState.GetBlockContext().Timestamp(); timestamp < fee.ReclaimableAt {
+		return nil, remainingGas, fmt.Errorf("%w: reclaimable at %d, current timestamp %d", ErrReclaimTooEarly, fee.ReclaimableAt, timestamp)
+	}
+	deleteDeliveryFee(stateDB, messageID, caller)
+	if remainingGas, err = payFee(accessibleState, fee, caller, remainingGas); err != nil {
+		return nil, remainingGas, err
+	}
+	if err := emitEvent(accessibleState, "DeliveryFeeReclaimed", messageID, caller, fee.Amount); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackGetDeliveryFee packs the inputs of getDeliveryFee, including the selector.
+func PackGetDeliveryFee(messageID common.Hash, depositor common.Address) ([]byte, error) {
+	return DeliveryFeeABI.Pack("getDeliveryFee", messageID, depositor)
+}
+
+// PackGetDeliveryFeeOutput packs [fee] to conform the ABI outputs.
+func PackGetDeliveryFeeOutput(fee DeliveryFee) ([]byte, error) {
+	return DeliveryFeeABI.PackOutput("getDeliveryFee", fee.Depositor, fee.DestinationChainID, fee.FeeToken, fee.Amount, fee.ReclaimableAt)
+}
+
+// getDeliveryFee returns the fee escrowed for the input message ID by the input depositor, or
+// the zero value if there is none.
+func getDeliveryFee(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetDeliveryFeeGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := DeliveryFeeABI.UnpackInput("getDeliveryFee", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	messageID := common.Hash(*abi.ConvertType(res[0], new([32]byte)).(*[32]byte))
+	depositor := *abi.ConvertType(res[1], new(common.Address)).(*common.Address)
+
+	fee, _ := GetDeliveryFee(accessibleState.GetStateDB(), messageID, depositor)
+	packedOutput, err := PackGetDeliveryFeeOutput(fee)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackIsDelivered packs the inputs of isDelivered, including the selector.
+func PackIsDelivered(messageID common.Hash) ([]byte, error) {
+	return DeliveryFeeABI.Pack("isDelivered", messageID)
+}
+
+// PackIsDeliveredOutput packs [delivered] to conform the ABI outputs.
+func PackIsDeliveredOutput(delivered bool) ([]byte, error) {
+	return DeliveryFeeABI.PackOutput("isDelivered", delivered)
+}
+
+// isDelivered returns whether the delivery of the input message ID to this chain has been recorded.
+func isDelivered(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, IsDeliveredGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := DeliveryFeeABI.UnpackInput("isDelivered", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	messageID := common.Hash(*abi.ConvertType(res[0], new([32]byte)).(*[32]byte))
+
+	packedOutput, err := PackIsDeliveredOutput(IsDelivered(accessibleState.GetStateDB(), messageID))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackGetMessageID packs the inputs of getMessageID, including the selector.
+func PackGetMessageID(originSender common.Address, destinationChainID common.Hash, destinationAddress common.Address, payload []byte) ([]byte, error) {
+	return DeliveryFeeABI.Pack("getMessageID", originSender, destinationChainID, destinationAddress, payload)
+}
+
+// PackGetMessageIDOutput packs [messageID] to conform the ABI outputs.
+func PackGetMessageIDOutput(messageID common.Hash) ([]byte, error) {
+	return DeliveryFeeABI.PackOutput("getMessageID", messageID)
+}
+
+// getMessageID returns the ID of the warp message sent from this chain with the input
+// fields, so that contracts can escrow fees for the messages they send.
+func getMessageID(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	// Charge for hashing before unpacking the variable sized input.
+	hashGas, err := messageIDGasCost(len(input))
+	if err != nil {
+		return nil, 0, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, hashGas); err != nil {
+		return nil, 0, err
+	}
+	res, err := DeliveryFeeABI.UnpackInput("getMessageID", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	originSender := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	destinationChainID := common.Hash(*abi.ConvertType(res[1], new([32]byte)).(*[32]byte))
+	destinationAddress := *abi.ConvertType(res[2], new(common.Address)).(*common.Address)
+	payload := *abi.ConvertType(res[3], new([]byte)).(*[]byte)
+
+	snowCtx := accessibleState.GetSnowContext()
+	messageID, err := MessageID(snowCtx.NetworkID, common.Hash(snowCtx.ChainID), originSender, destinationChainID, destinationAddress, payload)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := PackGetMessageIDOutput(messageID)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// createDeliveryFeePrecompile returns a StatefulPrecompiledContract implementing the
+// delivery fee escrow.
+func createDeliveryFeePrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"addDeliveryFee":     addDeliveryFee,
+		"claimDeliveryFee":   claimDeliveryFee,
+		"getDeliveryFee":     getDeliveryFee,
+		"getMessageID":       getMessageID,
+		"isDelivered":        isDelivered,
+		"reclaimDeliveryFee": reclaimDeliveryFee,
+		"recordDelivery":     recordDelivery,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := DeliveryFeeABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		if method.IsPayable() {
+			functions = append(functions, contract.NewPayableStatefulPrecompileFunction(method.ID, function))
+		} else {
+			functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+		}
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
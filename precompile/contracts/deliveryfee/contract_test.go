@@ -0,0 +1,376 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package deliveryfee
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/wrappednative"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
+	"github.com/ava-labs/subnet-evm/x/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testDepositor          = common.Address{'d'}
+	testRelayer            = common.Address{'r'}
+	testDestinationAddress = common.Address{'a'}
+	testMessageID          = common.Hash{'m'}
+	testDestinationChainID = common.Hash{'c'}
+	testFee                = big.NewInt(100)
+	testTimestamp          = uint64(1000)
+)
+
+// storeFee escrows [testFee] in native tokens for [testMessageID].
+func storeFee(t testing.TB, stateDB contract.StateDB) {
+	setDeliveryFee(stateDB, testMessageID, DeliveryFee{
+		Depositor:          testDepositor,
+		DestinationChainID: testDestinationChainID,
+		Amount:             testFee,
+		ReclaimableAt:      testTimestamp,
+	})
+	stateDB.AddBalance(ContractAddress, testFee)
+}
+
+func setupBlockContextAt(timestamp uint64) func(*contract.MockBlockContext) {
+	return func(mbc *contract.MockBlockContext) {
+		mbc.EXPECT().Number().Return(common.Big0).AnyTimes()
+		mbc.EXPECT().Timestamp().Return(timestamp).AnyTimes()
+	}
+}
+
+func TestDeliveryFeeRun(t *testing.T) {
+	reclaimInput := func(t testing.TB) []byte {
+		input, err := PackReclaimDeliveryFee(testMessageID)
+		require.NoError(t, err)
+		return input
+	}
+
+	tests := map[string]testutils.PrecompileTest{
+		"add zero fee fails": {
+			Caller: testDepositor,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackAddDeliveryFee(testMessageID, testDestinationChainID, common.Address{}, common.Big0)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: AddDeliveryFeeGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrZeroDeliveryFee.Error(),
+		},
+		"add existing fee fails": {
+			Caller:     testDepositor,
+			BeforeHook: storeFee,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackAddDeliveryFee(testMessageID, testDestinationChainID, common.Address{}, testFee)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: AddDeliveryFeeGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrDeliveryFeeExists.Error(),
+		},
+		"add native fee without value fails": {
+			Caller: testDepositor,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackAddDeliveryFee(testMessageID, testDestinationChainID, common.Address{}, testFee)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: AddDeliveryFeeGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInvalidFeeValue.Error(),
+		},
+		"add fee in unsupported token fails": {
+			Caller: testDepositor,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackAddDeliveryFee(testMessageID, testDestinationChainID, common.Address{'t'}, testFee)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: AddDeliveryFeeGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrUnsupportedFeeToken.Error(),
+		},
+		"reclaim fee": {
+			Caller:            testDepositor,
+			BeforeHook:        storeFee,
+			SetupBlockContext: setupBlockContextAt(testTimestamp),
+			InputFn:           reclaimInput,
+			SuppliedGas:       ReclaimDeliveryFeeGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				_, exists := GetDeliveryFee(stateDB, testMessageID, testDepositor)
+				require.False(t, exists)
+				require.Equal(t, testFee, stateDB.GetBalance(testDepositor))
+				require.Zero(t, stateDB.GetBalance(ContractAddress).Sign())
+			},
+		},
+		"reclaim fee too early fails": {
+			Caller:            testDepositor,
+			BeforeHook:        storeFee,
+			SetupBlockContext: setupBlockContextAt(testTimestamp - 1),
+			InputFn:           reclaimInput,
+			SuppliedGas:       ReclaimDeliveryFeeGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrReclaimTooEarly.Error(),
+		},
+		"reclaim fee of other depositor fails": {
+			Caller:            testRelayer,
+			BeforeHook:        storeFee,
+			SetupBlockContext: setupBlockContextAt(testTimestamp),
+			InputFn:           reclaimInput,
+			SuppliedGas:       ReclaimDeliveryFeeGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrUnknownDeliveryFee.Error(),
+		},
+		"reclaim unknown fee fails": {
+			Caller:      testDepositor,
+			InputFn:     reclaimInput,
+			SuppliedGas: ReclaimDeliveryFeeGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrUnknownDeliveryFee.Error(),
+		},
+		"reclaim fee readOnly": {
+			Caller:      testDepositor,
+			BeforeHook:  storeFee,
+			InputFn:     reclaimInput,
+			SuppliedGas: ReclaimDeliveryFeeGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"get delivery fee": {
+			Caller:     testRelayer,
+			BeforeHook: storeFee,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetDeliveryFee(testMessageID, testDepositor)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: GetDeliveryFeeGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetDeliveryFeeOutput(DeliveryFee{
+					Depositor:          testDepositor,
+					DestinationChainID: testDestinationChainID,
+					Amount:             testFee,
+					ReclaimableAt:      testTimestamp,
+				})
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+		"claim without depositors fails": {
+			Caller: testRelayer,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackClaimDeliveryFee(0, nil)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ClaimDeliveryFeeGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrNoDepositors.Error(),
+		},
+		"is delivered": {
+			Caller: testRelayer,
+			BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+				stateDB.SetState(ContractAddress, deliveredKey(testMessageID), deliveredHash)
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackIsDelivered(testMessageID)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: IsDeliveredGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackIsDeliveredOutput(true)
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+	}
+
+	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
+}
+
+func TestDeliveryFeeReadOnly(t *testing.T) {
+	testutils.RunReadOnlyTests(t, Module, state.NewTestStateDB, testutils.ReadOnlyTest{
+		Caller:    testDepositor,
+		Functions: testutils.ABIReadOnlyFunctions(t, DeliveryFeeABI),
+	})
+}
+
+// testChain is a chain with the delivery fee escrow, where the warp message at index 0 of the
+// predicates of the current transaction is verified.
+type testChain struct {
+	stateDB         contract.StateDB
+	snowCtx         *snow.Context
+	accessibleState contract.AccessibleState
+}
+
+func newTestChain(t *testing.T, ctrl *gomock.Controller, timestamp uint64) *testChain {
+	stateDB := state.NewTestStateDB(t)
+	snowCtx := snow.DefaultContextTest()
+	snowCtx.ChainID = ids.GenerateTestID()
+
+	chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+	chainConfig.EXPECT().IsDUpgrade(gomock.Any()).Return(true).AnyTimes()
+	blockContext := contract.NewMockBlockContext(ctrl)
+	blockContext.EXPECT().Number().Return(common.Big1).AnyTimes()
+	blockContext.EXPECT().Timestamp().Return(timestamp).AnyTimes()
+	blockContext.EXPECT().GetPredicateResults(gomock.Any(), warp.ContractAddress).Return(set.NewBits(0).Bytes()).AnyTimes()
+	accessibleState := contract.NewMockAccessibleState(ctrl)
+	accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+	accessibleState.EXPECT().GetBlockContext().Return(blockContext).AnyTimes()
+	accessibleState.EXPECT().GetSnowContext().Return(snowCtx).AnyTimes()
+	accessibleState.EXPECT().GetChainConfig().Return(chainConfig).AnyTimes()
+
+	require.NoError(t, Module.Configure(chainConfig, NewConfig(nil, 0), stateDB, blockContext))
+	return &testChain{stateDB: stateDB, snowCtx: snowCtx, accessibleState: accessibleState}
+}
+
+// call calls [precompile] as [caller] with [value], crediting the value to the precompile as
+// the EVM does before running a payable function.
+func (c *testChain) call(precompile common.Address, caller common.Address, value *big.Int, input []byte) error {
+	c.stateDB.SubBalance(caller, value)
+	c.stateDB.AddBalance(precompile, value)
+	module := Module
+	if precompile == wrappednative.ContractAddress {
+		module = wrappednative.Module
+	}
+	_, _, err := module.Contract.Run(&testutils.PayableAccessibleState{AccessibleState: c.accessibleState, Value: value}, caller, precompile, input, 1_000_000, false)
+	return err
+}
+
+// verifyMessage makes [unsignedMessage] the verified warp message at index 0 of the current transaction.
+func (c *testChain) verifyMessage(t *testing.T, unsignedMessage *avalancheWarp.UnsignedMessage) {
+	message, err := avalancheWarp.NewMessage(unsignedMessage, &avalancheWarp.BitSetSignature{})
+	require.NoError(t, err)
+	c.stateDB.SetPredicateStorageSlots(warp.ContractAddress, [][]byte{predicateutils.PackPredicate(message.Bytes())})
+}
+
+// sentMessage returns the last warp message sent by [c].
+func (c *testChain) sentMessage(t *testing.T) *avalancheWarp.UnsignedMessage {
+	logs := c.stateDB.(*state.StateDB).Logs()
+	for i := len(logs) - 1; i >= 0; i-- {
+		if logs[i].Address == warp.ContractAddress {
+			unsignedMessage, err := avalancheWarp.ParseUnsignedMessage(logs[i].Data)
+			require.NoError(t, err)
+			return unsignedMessage
+		}
+	}
+	require.FailNow(t, "no warp message sent")
+	return nil
+}
+
+func TestDeliveryFeeRoundTrip(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	source := newTestChain(t, ctrl, testTimestamp)
+	destination := newTestChain(t, ctrl, testTimestamp)
+
+	// Compute the ID of the message sent by the depositor on the source chain.
+	payload := []byte("hello")
+	getMessageIDInput, err := PackGetMessageID(testDepositor, common.Hash(destination.snowCtx.ChainID), testDestinationAddress, payload)
+	require.NoError(err)
+	ret, _, err := DeliveryFeePrecompile.Run(source.accessibleState, testDepositor, ContractAddress, getMessageIDInput, 1_000_000, true)
+	require.NoError(err)
+	addressedPayload, err := warpPayload.NewAddressedPayload(testDepositor, common.Hash(destination.snowCtx.ChainID), testDestinationAddress, payload)
+	require.NoError(err)
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(source.snowCtx.NetworkID, source.snowCtx.ChainID, addressedPayload.Bytes())
+	require.NoError(err)
+	messageID := common.Hash(unsignedMessage.ID())
+	require.Equal(messageID.Bytes(), ret)
+
+	// Another depositor escrowing a fee first does not prevent the depositor from escrowing its fee.
+	otherDepositor := common.Address{'o'}
+	source.stateDB.AddBalance(otherDepositor, common.Big1)
+	otherAddInput, err := PackAddDeliveryFee(messageID, common.Hash(destination.snowCtx.ChainID), common.Address{}, common.Big1)
+	require.NoError(err)
+	require.NoError(source.call(ContractAddress, otherDepositor, common.Big1, otherAddInput))
+
+	// Escrow the fee on the source chain.
+	source.stateDB.AddBalance(testDepositor, testFee)
+	addInput, err := PackAddDeliveryFee(messageID, common.Hash(destination.snowCtx.ChainID), common.Address{}, testFee)
+	require.NoError(err)
+	require.ErrorIs(source.call(ContractAddress, testDepositor, common.Big1, addInput), ErrInvalidFeeValue)
+	require.NoError(source.call(ContractAddress, testDepositor, testFee, addInput))
+	fee, exists := GetDeliveryFee(source.stateDB, messageID, testDepositor)
+	require.True(exists)
+	require.Equal(testDepositor, fee.Depositor)
+	require.Equal(testFee, fee.Amount)
+	require.Equal(testTimestamp+DefaultReclaimDelay, fee.ReclaimableAt)
+
+	// Record the delivery on the destination chain, which sends a receipt.
+	destination.verifyMessage(t, unsignedMessage)
+	recordInput, err := PackRecordDelivery(0, testRelayer)
+	require.NoError(err)
+	require.ErrorIs(destination.call(ContractAddress, testRelayer, common.Big0, recordInput), ErrNotMessageDestination)
+	require.NoError(destination.call(ContractAddress, testDestinationAddress, common.Big0, recordInput))
+	require.True(IsDelivered(destination.stateDB, messageID))
+	require.ErrorIs(destination.call(ContractAddress, testDestinationAddress, common.Big0, recordInput), ErrAlreadyDelivered)
+	receipt := destination.sentMessage(t)
+
+	// A receipt verified on another chain cannot be claimed.
+	claimInput, err := PackClaimDeliveryFee(0, []common.Address{testDepositor, otherDepositor})
+	require.NoError(err)
+	destination.verifyMessage(t, receipt)
+	require.ErrorIs(destination.call(ContractAddress, testRelayer, common.Big0, claimInput), ErrInvalidReceipt)
+
+	// Claim the sum of the fees on the source chain with the receipt.
+	source.verifyMessage(t, receipt)
+	require.NoError(source.call(ContractAddress, testRelayer, common.Big0, claimInput))
+	require.Equal(new(big.Int).Add(testFee, common.Big1), source.stateDB.GetBalance(testRelayer))
+	require.Zero(source.stateDB.GetBalance(ContractAddress).Sign())
+	require.ErrorIs(source.call(ContractAddress, testRelayer, common.Big0, claimInput), ErrUnknownDeliveryFee)
+}
+
+func TestDeliveryFeeInToken(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	source := newTestChain(t, ctrl, testTimestamp)
+	source.stateDB.AddBalance(testDepositor, testFee)
+
+	depositInput, err := wrappednative.PackDeposit()
+	require.NoError(err)
+	require.NoError(source.call(wrappednative.ContractAddress, testDepositor, testFee, depositInput))
+
+	addInput, err := PackAddDeliveryFee(testMessageID, testDestinationChainID, wrappednative.ContractAddress, testFee)
+	require.NoError(err)
+	require.ErrorIs(source.call(ContractAddress, testDepositor, common.Big0, addInput), ErrFeeTokenCallFailed)
+
+	approveInput, err := wrappednative.PackApprove(ContractAddress, testFee)
+	require.NoError(err)
+	require.NoError(source.call(wrappednative.ContractAddress, testDepositor, common.Big0, approveInput))
+	require.NoError(source.call(ContractAddress, testDepositor, common.Big0, addInput))
+	require.Zero(wrappednative.GetBalance(source.stateDB, testDepositor).Sign())
+	require.Equal(testFee, wrappednative.GetBalance(source.stateDB, ContractAddress))
+
+	// Receipts from the destination chain of the fee pay the relayer in the token.
+	receiptPayload, err := warpPayload.NewAddressedPayload(ContractAddress, common.Hash(source.snowCtx.ChainID), ContractAddress, PackReceipt(testMessageID, testRelayer))
+	require.NoError(err)
+	receipt, err := avalancheWarp.NewUnsignedMessage(source.snowCtx.NetworkID, ids.ID(testDestinationChainID), receiptPayload.Bytes())
+	require.NoError(err)
+	source.verifyMessage(t, receipt)
+	claimInput, err := PackClaimDeliveryFee(0, []common.Address{testDepositor})
+	require.NoError(err)
+	require.NoError(source.call(ContractAddress, testRelayer, common.Big0, claimInput))
+	require.Equal(testFee, wrappednative.GetBalance(source.stateDB, testRelayer))
+	require.Zero(wrappednative.GetBalance(source.stateDB, ContractAddress).Sign())
+}
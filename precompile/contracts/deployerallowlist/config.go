@@ -4,6 +4,9 @@
 package deployerallowlist
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
 	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
 	"github.com/ethereum/go-ethereum/common"
@@ -11,11 +14,28 @@ import (
 
 var _ precompileconfig.Config = &Config{}
 
+// MaxFactoryDepth is the maximum allowed value of [Config.FactoryDepth].
+const MaxFactoryDepth = 16
+
+var errFactoryDepthCannotBeActivated = errors.New("factory depth cannot be activated before DUpgrade")
+
 // Config contains the configuration for the ContractDeployerAllowList precompile,
 // consisting of the initial allowlist and the timestamp for the network upgrade.
 type Config struct {
 	allowlist.AllowListConfig
 	precompileconfig.Upgrade
+
+	// FactoryDepth is the number of generations of contracts created by enabled deployers
+	// that inherit the right to deploy contracts. For example, with a depth of 1 a factory
+	// deployed by an enabled address may deploy contracts, but those contracts may not.
+	// Creations that grant the right are charged [RecordDeploymentGasCost] to store it.
+	// Defaults to 0, which only permits enabled addresses to deploy contracts.
+	//
+	// A factory may deploy contracts in transactions from any origin, so every contract
+	// deployed by an enabled address opens deployment through it to all accounts, with
+	// whatever code the factory accepts. Admins and managers can take the right away from
+	// a factory with revokeFactory. Only supported from DUpgrade onwards.
+	FactoryDepth uint64 `json:"factoryDepth,omitempty"`
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -51,9 +71,15 @@ func (c *Config) Equal(cfg precompileconfig.Config) bool {
 	if !ok {
 		return false
 	}
-	return c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig)
+	return c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig) && c.FactoryDepth == other.FactoryDepth
 }
 
 func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	if c.FactoryDepth > MaxFactoryDepth {
+		return fmt.Errorf("factory depth %d exceeds the maximum of %d", c.FactoryDepth, MaxFactoryDepth)
+	}
+	if c.FactoryDepth > 0 && c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errFactoryDepthCannotBeActivated
+	}
 	return c.AllowListConfig.Verify(chainConfig, c.Upgrade)
 }
@@ -15,7 +15,28 @@ import (
 )
 
 func TestVerify(t *testing.T) {
-	allowlist.VerifyPrecompileWithAllowListTests(t, Module, nil)
+	tests := map[string]testutils.ConfigVerifyTest{
+		"factory depth too large": {
+			Config: &Config{
+				Upgrade:      precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)},
+				FactoryDepth: MaxFactoryDepth + 1,
+			},
+			ExpectedError: "factory depth",
+		},
+		"factory depth before DUpgrade": {
+			Config: &Config{
+				Upgrade:      precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)},
+				FactoryDepth: 1,
+			},
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false).AnyTimes()
+				return config
+			}(),
+			ExpectedError: errFactoryDepthCannotBeActivated.Error(),
+		},
+	}
+	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
 }
 
 func TestEqual(t *testing.T) {
@@ -38,6 +59,11 @@ func TestEqual(t *testing.T) {
 			Other:    NewConfig(utils.NewUint64(4), admins, enableds, managers),
 			Expected: false,
 		},
+		"different factory depth": {
+			Config:   &Config{Upgrade: precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)}, FactoryDepth: 1},
+			Other:    &Config{Upgrade: precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)}, FactoryDepth: 2},
+			Expected: false,
+		},
 		"same config": {
 			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers),
 			Other:    NewConfig(utils.NewUint64(3), admins, enableds, managers),
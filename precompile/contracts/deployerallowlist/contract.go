@@ -4,13 +4,49 @@
 package deployerallowlist
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
 	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// RecordDeploymentGasCost is the gas charged to a contract creation that records the
+	// generation of the created contract.
+	RecordDeploymentGasCost = contract.WriteGasCostPerSlot
+
+	RevokeFactoryGasCost = allowlist.ReadAllowListGasCost + contract.WriteGasCostPerSlot // read allow list + write 1 slot
+)
+
+var (
+	// factoryDepthKeyPrefix is the prefix of the storage keys holding the generation of
+	// contracts that inherited the right to deploy contracts. See [Config.FactoryDepth].
+	factoryDepthKeyPrefix = []byte("factoryDepth")
+
+	revokeFactorySignature = contract.CalculateFunctionSelector("revokeFactory(address)")
+
+	ErrCannotRevokeFactory = errors.New("non-admin or manager cannot revoke factory")
 )
 
 // Singleton StatefulPrecompiledContract for W/R access to the contract deployer allow list.
-var ContractDeployerAllowListPrecompile contract.StatefulPrecompiledContract = allowlist.CreateAllowListPrecompile(ContractAddress)
+var ContractDeployerAllowListPrecompile contract.StatefulPrecompiledContract = createContractDeployerAllowListPrecompile()
+
+// createContractDeployerAllowListPrecompile returns the allow list precompile extended
+// with revokeFactory, which is activated by the DUpgrade.
+func createContractDeployerAllowListPrecompile() contract.StatefulPrecompiledContract {
+	functions := allowlist.CreateAllowListFunctions(ContractAddress)
+	functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(revokeFactorySignature, revokeFactory, isRevokeFactoryActivated))
+	precompile, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return precompile
+}
 
 // GetContractDeployerAllowListStatus returns the role of [address] for the contract deployer
 // allow list.
@@ -24,3 +60,89 @@ func GetContractDeployerAllowListStatus(stateDB contract.StateDB, address common
 func SetContractDeployerAllowListStatus(stateDB contract.StateDB, address common.Address, role allowlist.Role) {
 	allowlist.SetAllowListRole(stateDB, ContractAddress, address, role)
 }
+
+func factoryDepthKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(factoryDepthKeyPrefix, address.Bytes())
+}
+
+// GetFactoryDepth returns the generation of [address] if it is a contract that inherited
+// the right to deploy contracts from an enabled deployer, or 0 otherwise.
+func GetFactoryDepth(stateDB contract.StateDB, address common.Address) uint64 {
+	depth := stateDB.GetState(ContractAddress, factoryDepthKey(address)).Big()
+	if !depth.IsUint64() {
+		return 0
+	}
+	return depth.Uint64()
+}
+
+// CanDeploy returns true if a contract may be deployed by [caller] in a transaction
+// originated by [origin] under [config].
+func CanDeploy(stateDB contract.StateDB, config *Config, origin common.Address, caller common.Address) bool {
+	if GetContractDeployerAllowListStatus(stateDB, origin).IsEnabled() {
+		return true
+	}
+	if config == nil || config.FactoryDepth == 0 {
+		return false
+	}
+	depth := GetFactoryDepth(stateDB, caller)
+	return depth > 0 && depth <= config.FactoryDepth
+}
+
+// RecordDeployment grants [created] the right to deploy contracts if it was deployed by
+// an enabled deployer, or a contract that inherited the right, within the depth limit
+// of [config]. Returns true if the right was granted, in which case the creation must be
+// charged [RecordDeploymentGasCost].
+func RecordDeployment(stateDB contract.StateDB, config *Config, caller common.Address, created common.Address) bool {
+	if config == nil || config.FactoryDepth == 0 {
+		return false
+	}
+	var depth uint64
+	if GetContractDeployerAllowListStatus(stateDB, caller).IsEnabled() {
+		depth = 1
+	} else if callerDepth := GetFactoryDepth(stateDB, caller); callerDepth > 0 {
+		depth = callerDepth + 1
+	}
+	if depth == 0 || depth > config.FactoryDepth {
+		return false
+	}
+	stateDB.SetState(ContractAddress, factoryDepthKey(created), common.BigToHash(new(big.Int).SetUint64(depth)))
+	return true
+}
+
+// RevokeFactory removes the right of [factory] to deploy contracts it inherited from an
+// enabled deployer. Contracts it already deployed keep their own generation.
+func RevokeFactory(stateDB contract.StateDB, factory common.Address) {
+	stateDB.SetState(ContractAddress, factoryDepthKey(factory), common.Hash{})
+}
+
+// PackRevokeFactory packs [factory] into the input of revokeFactory, including the selector.
+func PackRevokeFactory(factory common.Address) []byte {
+	input := make([]byte, 0, contract.SelectorLen+common.HashLength)
+	input = append(input, revokeFactorySignature...)
+	return append(input, factory.Hash().Bytes()...)
+}
+
+// revokeFactory revokes the inherited right to deploy contracts of the address in
+// [input]. Only callable by admins and managers, who may also revoke enabled deployers.
+func revokeFactory(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RevokeFactoryGasCost); err != nil {
+		return nil, 0, err
+	}
+	if len(input) != common.HashLength {
+		return nil, remainingGas, fmt.Errorf("invalid input length for revokeFactory: %d", len(input))
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	if !GetContractDeployerAllowListStatus(stateDB, caller).CanModify(allowlist.EnabledRole, allowlist.NoRole) {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotRevokeFactory, caller)
+	}
+	RevokeFactory(stateDB, common.BytesToAddress(input))
+	return []byte{}, remainingGas, nil
+}
+
+func isRevokeFactoryActivated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetChainConfig().IsDUpgrade(accessibleState.GetBlockContext().Timestamp())
+}
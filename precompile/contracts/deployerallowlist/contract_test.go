@@ -8,12 +8,101 @@ import (
 
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testFactory = common.HexToAddress("0x0100000000000000000000000000000000000001")
+
+	storeTestFactory = func(t testing.TB, stateDB contract.StateDB) {
+		allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+		require.True(t, RecordDeployment(stateDB, &Config{FactoryDepth: 1}, allowlist.TestEnabledAddr, testFactory))
+	}
+
+	tests = map[string]testutils.PrecompileTest{
+		"manager revokes factory": {
+			Caller:      allowlist.TestManagerAddr,
+			BeforeHook:  storeTestFactory,
+			Input:       PackRevokeFactory(testFactory),
+			SuppliedGas: RevokeFactoryGasCost,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				require.Zero(t, GetFactoryDepth(stateDB, testFactory))
+			},
+		},
+		"enabled cannot revoke factory": {
+			Caller:      allowlist.TestEnabledAddr,
+			BeforeHook:  storeTestFactory,
+			Input:       PackRevokeFactory(testFactory),
+			SuppliedGas: RevokeFactoryGasCost,
+			ExpectedErr: ErrCannotRevokeFactory.Error(),
+		},
+		"revoke factory in read only mode fails": {
+			Caller:      allowlist.TestAdminAddr,
+			BeforeHook:  storeTestFactory,
+			Input:       PackRevokeFactory(testFactory),
+			SuppliedGas: RevokeFactoryGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"insufficient gas revoke factory": {
+			Caller:      allowlist.TestAdminAddr,
+			BeforeHook:  storeTestFactory,
+			Input:       PackRevokeFactory(testFactory),
+			SuppliedGas: RevokeFactoryGasCost - 1,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
 )
 
 func TestContractDeployerAllowListRun(t *testing.T) {
-	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, nil)
+	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
 }
 
 func BenchmarkContractDeployerAllowList(b *testing.B) {
-	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, nil)
+	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
+}
+
+func TestFactoryDepth(t *testing.T) {
+	var (
+		factory = common.HexToAddress("0x0100000000000000000000000000000000000001")
+		child   = common.HexToAddress("0x0100000000000000000000000000000000000002")
+		nested  = common.HexToAddress("0x0100000000000000000000000000000000000003")
+	)
+	stateDB := state.NewTestStateDB(t)
+	allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+	config := &Config{FactoryDepth: 1}
+
+	// Enabled deployers may deploy in any transaction they originate.
+	require.True(t, CanDeploy(stateDB, config, allowlist.TestEnabledAddr, allowlist.TestEnabledAddr))
+	require.False(t, CanDeploy(stateDB, config, allowlist.TestNoRoleAddr, allowlist.TestNoRoleAddr))
+
+	// A factory deployed by an enabled deployer may deploy for any origin.
+	require.True(t, RecordDeployment(stateDB, config, allowlist.TestEnabledAddr, factory))
+	require.Equal(t, uint64(1), GetFactoryDepth(stateDB, factory))
+	require.True(t, CanDeploy(stateDB, config, allowlist.TestNoRoleAddr, factory))
+
+	// Contracts deployed by the factory exceed the depth limit.
+	require.False(t, RecordDeployment(stateDB, config, factory, child))
+	require.Zero(t, GetFactoryDepth(stateDB, child))
+	require.False(t, CanDeploy(stateDB, config, allowlist.TestNoRoleAddr, child))
+
+	// Deeper generations inherit the right up to the depth limit.
+	config.FactoryDepth = 2
+	require.True(t, RecordDeployment(stateDB, config, factory, child))
+	require.Equal(t, uint64(2), GetFactoryDepth(stateDB, child))
+	require.False(t, RecordDeployment(stateDB, config, child, nested))
+	require.Zero(t, GetFactoryDepth(stateDB, nested))
+
+	// A revoked factory may no longer deploy.
+	RevokeFactory(stateDB, factory)
+	require.False(t, CanDeploy(stateDB, config, allowlist.TestNoRoleAddr, factory))
+
+	// Without a factory depth, only enabled deployers may deploy.
+	require.False(t, CanDeploy(stateDB, &Config{}, allowlist.TestNoRoleAddr, factory))
+	require.False(t, CanDeploy(stateDB, nil, allowlist.TestNoRoleAddr, factory))
 }
@@ -4,20 +4,61 @@
 package feemanager
 
 import (
+	"errors"
+	"math/big"
+
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
 	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 )
 
 var _ precompileconfig.Config = &Config{}
 
+var (
+	errInvalidFeeConfigVoteQuorum     = errors.New("fee config vote quorum must be positive")
+	errFeeConfigVoteCannotBeActivated = errors.New("fee config voting cannot be activated before DUpgrade")
+)
+
+// VoteConfig specifies that fee config changes must pass a vote of native token holders,
+// who vote by locking native tokens in the precompile. Disabling the precompile refunds
+// the tokens still locked in it to their voters before its state is wiped.
+type VoteConfig struct {
+	// Quorum is the amount of native tokens that must be locked in favor of a fee config
+	// proposal for it to pass. Each vote must lock at least Quorum / FeeConfigVoteQuorumFraction.
+	Quorum *math.HexOrDecimal256 `json:"quorum"`
+	// VotingPeriod is the number of seconds a proposal is open for votes after it is
+	// proposed (0 denotes using the default).
+	VotingPeriod uint64 `json:"votingPeriod,omitempty"`
+}
+
+// Equal returns true if [c] and [other] specify the same vote.
+func (c *VoteConfig) Equal(other *VoteConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	if c.Quorum == nil || other.Quorum == nil {
+		return c.Quorum == other.Quorum && c.VotingPeriod == other.VotingPeriod
+	}
+	return (*big.Int)(c.Quorum).Cmp((*big.Int)(other.Quorum)) == 0 && c.VotingPeriod == other.VotingPeriod
+}
+
+// votingPeriod returns the voting period of [c], using the default if unspecified.
+func (c *VoteConfig) votingPeriod() uint64 {
+	if c.VotingPeriod == 0 {
+		return DefaultFeeConfigVotingPeriod
+	}
+	return c.VotingPeriod
+}
+
 // Config implements the StatefulPrecompileConfig interface while adding in the
 // FeeManager specific precompile config.
 type Config struct {
 	allowlist.AllowListConfig // Config for the fee config manager allow list
 	precompileconfig.Upgrade
 	InitialFeeConfig *commontype.FeeConfig `json:"initialFeeConfig,omitempty"` // initial fee config to be immediately activated
+	VoteConfig       *VoteConfig           `json:"voteConfig,omitempty"`       // if specified, fee config changes must pass a vote
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -55,7 +96,7 @@ func (c *Config) Equal(cfg precompileconfig.Config) bool {
 	if !ok {
 		return false
 	}
-	eq := c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig)
+	eq := c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig) && c.VoteConfig.Equal(other.VoteConfig)
 	if !eq {
 		return false
 	}
@@ -71,6 +112,15 @@ func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
 	if err := c.AllowListConfig.Verify(chainConfig, c.Upgrade); err != nil {
 		return err
 	}
+	if c.VoteConfig != nil {
+		if c.VoteConfig.Quorum == nil || (*big.Int)(c.VoteConfig.Quorum).Sign() <= 0 {
+			return errInvalidFeeConfigVoteQuorum
+		}
+		// Votes lock value paid to a payable function, see [contract.NewPayableStatefulPrecompileFunction].
+		if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+			return errFeeConfigVoteCannotBeActivated
+		}
+	}
 	if c.InitialFeeConfig == nil {
 		return nil
 	}
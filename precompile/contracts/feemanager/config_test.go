@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/subnet-evm/precompile/testutils"
 	"github.com/ava-labs/subnet-evm/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"go.uber.org/mock/gomock"
 )
 
@@ -29,6 +30,11 @@ var validFeeConfig = commontype.FeeConfig{
 	BlockGasCostStep: big.NewInt(200_000),
 }
 
+func withVoteConfig(config *Config, voteConfig *VoteConfig) *Config {
+	config.VoteConfig = voteConfig
+	return config
+}
+
 func TestVerify(t *testing.T) {
 	admins := []common.Address{allowlist.TestAdminAddr}
 	invalidFeeConfig := validFeeConfig
@@ -42,6 +48,26 @@ func TestVerify(t *testing.T) {
 			Config:        NewConfig(utils.NewUint64(3), admins, nil, nil, &commontype.FeeConfig{}),
 			ExpectedError: "gasLimit cannot be nil",
 		},
+		"vote config without quorum": {
+			Config:        withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{}),
+			ExpectedError: errInvalidFeeConfigVoteQuorum.Error(),
+		},
+		"vote config with negative quorum": {
+			Config:        withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(-1)}),
+			ExpectedError: errInvalidFeeConfigVoteQuorum.Error(),
+		},
+		"valid vote config": {
+			Config: withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(1)}),
+		},
+		"vote config before DUpgrade": {
+			Config: withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(1)}),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errFeeConfigVoteCannotBeActivated.Error(),
+		},
 	}
 	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
 }
@@ -80,6 +106,21 @@ func TestEqual(t *testing.T) {
 				}()),
 			Expected: false,
 		},
+		"different vote config": {
+			Config:   withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(1)}),
+			Other:    withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(2)}),
+			Expected: false,
+		},
+		"non-nil vote config and nil vote config": {
+			Config:   withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(1)}),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, nil),
+			Expected: false,
+		},
+		"same vote config": {
+			Config:   withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(1), VotingPeriod: 10}),
+			Other:    withVoteConfig(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), &VoteConfig{Quorum: math.NewHexOrDecimal256(1), VotingPeriod: 10}),
+			Expected: true,
+		},
 		"same config": {
 			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, &validFeeConfig),
 			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, &validFeeConfig),
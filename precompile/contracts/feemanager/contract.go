@@ -145,9 +145,15 @@ func UnpackFeeConfigInput(input []byte) (commontype.FeeConfig, error) {
 
 // GetStoredFeeConfig returns fee config from contract storage in given state
 func GetStoredFeeConfig(stateDB contract.StateDB) commontype.FeeConfig {
+	return readFeeConfig(stateDB, func(field int) common.Hash { return common.Hash{byte(field)} })
+}
+
+// readFeeConfig returns the fee config whose fields are stored in the storage slots
+// returned by [key].
+func readFeeConfig(stateDB contract.StateDB, key func(field int) common.Hash) commontype.FeeConfig {
 	feeConfig := commontype.FeeConfig{}
 	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
-		val := stateDB.GetState(ContractAddress, common.Hash{byte(i)})
+		val := stateDB.GetState(ContractAddress, key(i))
 		switch i {
 		case gasLimitKey:
 			feeConfig.GasLimit = new(big.Int).Set(val.Big())
@@ -242,9 +248,37 @@ func setFeeConfig(accessibleState contract.AccessibleState, caller common.Addres
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
 	}
 
-	if err := StoreFeeConfig(stateDB, feeConfig, accessibleState.GetBlockContext()); err != nil {
+	recordHistory := isFeeConfigHistoryActivated(accessibleState)
+	// Fee config voting can only be configured from DUpgrade onwards.
+	if recordHistory && GetFeeConfigVoteQuorum(stateDB).Sign() != 0 {
+		return nil, remainingGas, ErrFeeConfigVoteRequired
+	}
+	var seed *feeConfigHistorySeed
+	if recordHistory {
+		if remainingGas, err = contract.DeductGas(remainingGas, FeeConfigHistoryGasCost); err != nil {
+			return nil, 0, err
+		}
+		if seed = getFeeConfigHistorySeed(stateDB); seed != nil {
+			if remainingGas, err = contract.DeductGas(remainingGas, FeeConfigHistoryGasCost); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	if seed != nil {
+		if err := appendFeeConfigHistory(stateDB, seed.feeConfig, seed.blockNumber); err != nil {
+			return nil, remainingGas, err
+		}
+	}
+	blockContext := accessibleState.GetBlockContext()
+	if err := StoreFeeConfig(stateDB, feeConfig, blockContext); err != nil {
 		return nil, remainingGas, err
 	}
+	if recordHistory {
+		if err := appendFeeConfigHistory(stateDB, feeConfig, blockContext.Number()); err != nil {
+			return nil, remainingGas, err
+		}
+	}
 
 	// Return an empty output and the remaining gas
 	return []byte{}, remainingGas, nil
@@ -290,8 +324,16 @@ func createFeeManagerPrecompile() contract.StatefulPrecompiledContract {
 	setFeeConfigFunc := contract.NewStatefulPrecompileFunction(setFeeConfigSignature, setFeeConfig)
 	getFeeConfigFunc := contract.NewStatefulPrecompileFunction(getFeeConfigSignature, getFeeConfig)
 	getFeeConfigLastChangedAtFunc := contract.NewStatefulPrecompileFunction(getFeeConfigLastChangedAtSignature, getFeeConfigLastChangedAt)
+	getFeeConfigAtFunc := contract.NewStatefulPrecompileFunctionWithActivator(getFeeConfigAtSignature, getFeeConfigAt, isFeeConfigHistoryActivated)
+
+	proposeFeeConfigFunc := contract.NewStatefulPrecompileFunctionWithActivator(proposeFeeConfigSignature, proposeFeeConfig, isFeeConfigHistoryActivated)
+	voteFeeConfigFunc := contract.NewPayableStatefulPrecompileFunctionWithActivator(voteFeeConfigSignature, voteFeeConfig, isFeeConfigHistoryActivated)
+	executeFeeConfigFunc := contract.NewStatefulPrecompileFunctionWithActivator(executeFeeConfigSignature, executeFeeConfig, isFeeConfigHistoryActivated)
+	withdrawFeeConfigVoteFunc := contract.NewStatefulPrecompileFunctionWithActivator(withdrawFeeConfigVoteSignature, withdrawFeeConfigVote, isFeeConfigHistoryActivated)
+	getFeeConfigProposalFunc := contract.NewStatefulPrecompileFunctionWithActivator(getFeeConfigProposalSignature, getFeeConfigProposal, isFeeConfigHistoryActivated)
 
-	feeManagerFunctions = append(feeManagerFunctions, setFeeConfigFunc, getFeeConfigFunc, getFeeConfigLastChangedAtFunc)
+	feeManagerFunctions = append(feeManagerFunctions, setFeeConfigFunc, getFeeConfigFunc, getFeeConfigLastChangedAtFunc, getFeeConfigAtFunc,
+		proposeFeeConfigFunc, voteFeeConfigFunc, executeFeeConfigFunc, withdrawFeeConfigVoteFunc, getFeeConfigProposalFunc)
 	// Construct the contract with no fallback function.
 	contract, err := contract.NewStatefulPrecompileContract(nil, feeManagerFunctions)
 	// TODO Change this to be returned as an error after refactoring this precompile
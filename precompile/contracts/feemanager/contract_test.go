@@ -55,7 +55,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: SetFeeConfigGasCost,
+			SuppliedGas: SetFeeConfigGasCost + FeeConfigHistoryGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			AfterHook: func(t testing.TB, state contract.StateDB) {
@@ -72,7 +72,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: SetFeeConfigGasCost,
+			SuppliedGas: SetFeeConfigGasCost + FeeConfigHistoryGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			AfterHook: func(t testing.TB, state contract.StateDB) {
@@ -91,7 +91,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: SetFeeConfigGasCost,
+			SuppliedGas: SetFeeConfigGasCost + FeeConfigHistoryGasCost,
 			ReadOnly:    false,
 			Config: &Config{
 				InitialFeeConfig: &testFeeConfig,
@@ -111,11 +111,12 @@ var (
 
 				return input
 			},
-			SuppliedGas: SetFeeConfigGasCost,
+			SuppliedGas: SetFeeConfigGasCost + FeeConfigHistoryGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			SetupBlockContext: func(mbc *contract.MockBlockContext) {
 				mbc.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+				mbc.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
 			},
 			AfterHook: func(t testing.TB, state contract.StateDB) {
 				feeConfig := GetStoredFeeConfig(state)
@@ -167,7 +168,8 @@ var (
 				return res
 			}(),
 			SetupBlockContext: func(mbc *contract.MockBlockContext) {
-				mbc.EXPECT().Number().Return(testBlockNumber)
+				mbc.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+				mbc.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
 			},
 			AfterHook: func(t testing.TB, state contract.StateDB) {
 				feeConfig := GetStoredFeeConfig(state)
@@ -196,6 +198,157 @@ var (
 				require.Equal(t, testBlockNumber, lastChangedAt)
 			},
 		},
+		"set config appends to history": {
+			Caller: allowlist.TestAdminAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, appendFeeConfigHistory(state, commontype.ValidTestFeeConfig, big.NewInt(1)))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeConfig(testFeeConfig)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetFeeConfigGasCost + FeeConfigHistoryGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+				mbc.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
+			},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				// The fee config stored in a block takes effect from the next block.
+				feeConfig, err := GetFeeConfigAt(state, testBlockNumber)
+				require.NoError(t, err)
+				require.Equal(t, commontype.ValidTestFeeConfig, feeConfig)
+				feeConfig, err = GetFeeConfigAt(state, new(big.Int).Add(testBlockNumber, common.Big1))
+				require.NoError(t, err)
+				require.Equal(t, testFeeConfig, feeConfig)
+				_, err = GetFeeConfigAt(state, common.Big1)
+				require.ErrorIs(t, err, ErrFeeConfigHistoryUnavailable)
+			},
+		},
+		"set config seeds history with the stored config": {
+			Caller: allowlist.TestAdminAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				blockContext := contract.NewMockBlockContext(gomock.NewController(t))
+				blockContext.EXPECT().Number().Return(big.NewInt(3)).Times(1)
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, StoreFeeConfig(state, commontype.ValidTestFeeConfig, blockContext))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeConfig(testFeeConfig)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetFeeConfigGasCost + 2*FeeConfigHistoryGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+				mbc.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
+			},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				_, err := GetFeeConfigAt(state, big.NewInt(3))
+				require.ErrorIs(t, err, ErrFeeConfigHistoryUnavailable)
+				feeConfig, err := GetFeeConfigAt(state, big.NewInt(4))
+				require.NoError(t, err)
+				require.Equal(t, commontype.ValidTestFeeConfig, feeConfig)
+				feeConfig, err = GetFeeConfigAt(state, testBlockNumber)
+				require.NoError(t, err)
+				require.Equal(t, commontype.ValidTestFeeConfig, feeConfig)
+				feeConfig, err = GetFeeConfigAt(state, new(big.Int).Add(testBlockNumber, common.Big1))
+				require.NoError(t, err)
+				require.Equal(t, testFeeConfig, feeConfig)
+			},
+		},
+		"get fee config at block": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, appendFeeConfigHistory(state, testFeeConfig, big.NewInt(2)))
+				require.NoError(t, appendFeeConfigHistory(state, commontype.ValidTestFeeConfig, big.NewInt(5)))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetFeeConfigAtInput(big.NewInt(4))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetFeeConfigAtGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get fee config at block of change": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, appendFeeConfigHistory(state, testFeeConfig, big.NewInt(2)))
+				require.NoError(t, appendFeeConfigHistory(state, commontype.ValidTestFeeConfig, big.NewInt(5)))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetFeeConfigAtInput(big.NewInt(5))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetFeeConfigAtGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get fee config at block after change": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, appendFeeConfigHistory(state, testFeeConfig, big.NewInt(2)))
+				require.NoError(t, appendFeeConfigHistory(state, commontype.ValidTestFeeConfig, big.NewInt(5)))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetFeeConfigAtInput(big.NewInt(6))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetFeeConfigAtGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackFeeConfig(commontype.ValidTestFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get fee config at block before history fails": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, appendFeeConfigHistory(state, testFeeConfig, big.NewInt(2)))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetFeeConfigAtInput(big.NewInt(2))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetFeeConfigAtGasCost,
+			ReadOnly:    true,
+			ExpectedErr: ErrFeeConfigHistoryUnavailable.Error(),
+		},
 		"readOnly setFeeConfig with noRole fails": {
 			Caller:     allowlist.TestNoRoleAddr,
 			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
@@ -255,6 +408,61 @@ func TestFeeManager(t *testing.T) {
 	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
 }
 
+func TestFeeManagerReadOnly(t *testing.T) {
+	setFeeConfigInput, err := PackSetFeeConfig(testFeeConfig)
+	require.NoError(t, err)
+	getFeeConfigAtInput, err := PackGetFeeConfigAtInput(big.NewInt(0))
+	require.NoError(t, err)
+	proposeFeeConfigInput, err := PackProposeFeeConfig(testFeeConfig)
+	require.NoError(t, err)
+	voteFeeConfigInput, err := PackVoteFeeConfig(testProposalID, true)
+	require.NoError(t, err)
+	executeFeeConfigInput, err := PackExecuteFeeConfig(testProposalID)
+	require.NoError(t, err)
+	withdrawFeeConfigVoteInput, err := PackWithdrawFeeConfigVote(testProposalID)
+	require.NoError(t, err)
+	getFeeConfigProposalInput, err := PackGetFeeConfigProposal(testProposalID)
+	require.NoError(t, err)
+
+	testutils.RunReadOnlyTests(t, Module, state.NewTestStateDB, testutils.ReadOnlyTest{
+		Caller: allowlist.TestAdminAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, state)
+			storeProposal(testQuorum, common.Big0)(t, state)
+			require.NoError(t, appendFeeConfigHistory(state, testFeeConfig, big.NewInt(0)))
+		},
+		Functions: allowlist.ReadOnlyFunctions(t, map[string]testutils.ReadOnlyFunction{
+			"setFeeConfig":              {Input: setFeeConfigInput, Writes: true},
+			"getFeeConfig":              {Input: PackGetFeeConfigInput()},
+			"getFeeConfigLastChangedAt": {Input: PackGetLastChangedAtInput()},
+			"getFeeConfigAt":            {Input: getFeeConfigAtInput},
+			"proposeFeeConfig":          {Input: proposeFeeConfigInput, Writes: true},
+			"voteFeeConfig":             {Input: voteFeeConfigInput, Writes: true},
+			"executeFeeConfig":          {Input: executeFeeConfigInput, Writes: true},
+			"withdrawFeeConfigVote":     {Input: withdrawFeeConfigVoteInput, Writes: true},
+			"getFeeConfigProposal":      {Input: getFeeConfigProposalInput},
+		}),
+	})
+}
+
 func BenchmarkFeeManager(b *testing.B) {
 	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
 }
+
+func TestFeeConfigHistoryBounded(t *testing.T) {
+	stateDB := state.NewTestStateDB(t)
+	for i := int64(0); i < MaxFeeConfigHistory+2; i++ {
+		feeConfig := testFeeConfig
+		feeConfig.GasLimit = big.NewInt(8_000_000 + i)
+		require.NoError(t, appendFeeConfigHistory(stateDB, feeConfig, big.NewInt(10*i)))
+	}
+
+	// The oldest entries were replaced.
+	_, err := GetFeeConfigAt(stateDB, big.NewInt(15))
+	require.ErrorIs(t, err, ErrFeeConfigHistoryUnavailable)
+	for i := int64(2); i < MaxFeeConfigHistory+2; i++ {
+		feeConfig, err := GetFeeConfigAt(stateDB, big.NewInt(10*i+5))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(8_000_000+i), feeConfig.GasLimit)
+	}
+}
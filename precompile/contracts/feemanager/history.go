@@ -0,0 +1,163 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feemanager
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// From DUpgrade onwards, every fee config stored by the precompile is also appended to
+// a bounded history, so that the fee config in effect at a past block can be looked up
+// with getFeeConfigAt. The history is a ring buffer of [MaxFeeConfigHistory] entries,
+// each holding the block number the fee config was stored in followed by its fields.
+// A fee config stored in block N takes effect from block N+1, since the fees of a block
+// are set by the state of its parent.
+//
+// The first fee config stored after DUpgrade seeds the history with the fee config it
+// replaces, which was in effect when the history was activated.
+
+const (
+	// MaxFeeConfigHistory is the number of fee configs retained in the history.
+	MaxFeeConfigHistory = 32
+
+	// feeConfigHistoryEntryLen is the number of storage slots of a history entry.
+	feeConfigHistoryEntryLen = numFeeConfigField + 1
+	// feeConfigHistorySearchReads is the maximum number of entries read to search the history.
+	feeConfigHistorySearchReads = 6 // ceil(log2(MaxFeeConfigHistory)) + 1
+
+	// FeeConfigHistoryGasCost is the additional cost of setFeeConfig to append to the history,
+	// charged from DUpgrade onwards.
+	FeeConfigHistoryGasCost = contract.WriteGasCostPerSlot * (feeConfigHistoryEntryLen + 1) // plus one for the history length
+	GetFeeConfigAtGasCost   = contract.ReadGasCostPerSlot * (1 + feeConfigHistorySearchReads + numFeeConfigField)
+
+	getFeeConfigAtInputLen = common.HashLength
+)
+
+var (
+	getFeeConfigAtSignature = contract.CalculateFunctionSelector("getFeeConfigAt(uint256)")
+
+	feeConfigHistoryLenKey = common.Hash{'f', 'c', 'h', 'l'}
+
+	ErrFeeConfigHistoryUnavailable = errors.New("fee config history unavailable")
+)
+
+// feeConfigHistoryKey returns the storage key of [field] of the history entry at [index].
+// Field 0 holds the block number of the entry, followed by the fee config fields in the
+// order of their keys.
+func feeConfigHistoryKey(index uint64, field int) common.Hash {
+	return common.Hash{'f', 'c', 'h', 'e', byte(index % MaxFeeConfigHistory), byte(field)}
+}
+
+// getFeeConfigHistoryLen returns the number of fee configs ever appended to the history.
+func getFeeConfigHistoryLen(stateDB contract.StateDB) uint64 {
+	return stateDB.GetState(ContractAddress, feeConfigHistoryLenKey).Big().Uint64()
+}
+
+// getFeeConfigHistoryBlockNumber returns the block number of the history entry at [index].
+func getFeeConfigHistoryBlockNumber(stateDB contract.StateDB, index uint64) *big.Int {
+	return stateDB.GetState(ContractAddress, feeConfigHistoryKey(index, 0)).Big()
+}
+
+// appendFeeConfigHistory appends [feeConfig], stored in [blockNumber], to the history,
+// replacing the oldest entry once the history is full.
+func appendFeeConfigHistory(stateDB contract.StateDB, feeConfig commontype.FeeConfig, blockNumber *big.Int) error {
+	packed, err := PackFeeConfig(feeConfig)
+	if err != nil {
+		return err
+	}
+	index := getFeeConfigHistoryLen(stateDB)
+	stateDB.SetState(ContractAddress, feeConfigHistoryKey(index, 0), common.BigToHash(blockNumber))
+	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
+		stateDB.SetState(ContractAddress, feeConfigHistoryKey(index, i), common.BytesToHash(contract.PackedHash(packed, i-1)))
+	}
+	stateDB.SetState(ContractAddress, feeConfigHistoryLenKey, common.BigToHash(new(big.Int).SetUint64(index+1)))
+	return nil
+}
+
+// feeConfigHistorySeed is the fee config an empty history is seeded with.
+type feeConfigHistorySeed struct {
+	feeConfig   commontype.FeeConfig
+	blockNumber *big.Int
+}
+
+// getFeeConfigHistorySeed returns the currently stored fee config and the block it was
+// stored in if the history is empty, and nil if the history needs no seed. Must be
+// called before a new fee config is stored, and the seed appended before it.
+func getFeeConfigHistorySeed(stateDB contract.StateDB) *feeConfigHistorySeed {
+	if getFeeConfigHistoryLen(stateDB) != 0 {
+		return nil
+	}
+	feeConfig := GetStoredFeeConfig(stateDB)
+	if err := feeConfig.Verify(); err != nil {
+		// No fee config has been stored yet.
+		return nil
+	}
+	return &feeConfigHistorySeed{feeConfig: feeConfig, blockNumber: GetFeeConfigLastChangedAt(stateDB)}
+}
+
+// GetFeeConfigAt returns the fee config stored by the precompile that was in effect at
+// [blockNumber], which is the last fee config stored before [blockNumber]. Returns
+// ErrFeeConfigHistoryUnavailable if the history does not reach back to [blockNumber].
+func GetFeeConfigAt(stateDB contract.StateDB, blockNumber *big.Int) (commontype.FeeConfig, error) {
+	length := getFeeConfigHistoryLen(stateDB)
+	var oldest uint64
+	if length > MaxFeeConfigHistory {
+		oldest = length - MaxFeeConfigHistory
+	}
+	if length == 0 || getFeeConfigHistoryBlockNumber(stateDB, oldest).Cmp(blockNumber) >= 0 {
+		return commontype.FeeConfig{}, fmt.Errorf("%w at block %s", ErrFeeConfigHistoryUnavailable, blockNumber)
+	}
+
+	// Find the last entry stored before [blockNumber]. Entries are appended in block
+	// order, so the history is sorted by block number.
+	lo, hi := oldest, length-1
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		if getFeeConfigHistoryBlockNumber(stateDB, mid).Cmp(blockNumber) < 0 {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	return readFeeConfig(stateDB, func(field int) common.Hash { return feeConfigHistoryKey(lo, field) }), nil
+}
+
+// PackGetFeeConfigAtInput packs [blockNumber] with the getFeeConfigAt selector.
+func PackGetFeeConfigAtInput(blockNumber *big.Int) ([]byte, error) {
+	input := make([]byte, contract.SelectorLen+getFeeConfigAtInputLen)
+	err := contract.PackOrderedHashesWithSelector(input, getFeeConfigAtSignature, []common.Hash{common.BigToHash(blockNumber)})
+	return input, err
+}
+
+// getFeeConfigAt returns the fee config that was in effect at the input block number.
+func getFeeConfigAt(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetFeeConfigAtGasCost); err != nil {
+		return nil, 0, err
+	}
+	if len(input) != getFeeConfigAtInputLen {
+		return nil, remainingGas, fmt.Errorf("invalid input length for getFeeConfigAt: %d", len(input))
+	}
+
+	feeConfig, err := GetFeeConfigAt(accessibleState.GetStateDB(), new(big.Int).SetBytes(input))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	output, err := PackFeeConfig(feeConfig)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
+
+// isFeeConfigHistoryActivated returns true if fee configs are appended to the history.
+func isFeeConfigHistoryActivated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetChainConfig().IsDUpgrade(accessibleState.GetBlockContext().Timestamp())
+}
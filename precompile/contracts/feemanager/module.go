@@ -5,6 +5,7 @@ package feemanager
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/ava-labs/subnet-evm/precompile/contract"
 	"github.com/ava-labs/subnet-evm/precompile/modules"
@@ -12,7 +13,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-var _ contract.Configurator = &configurator{}
+var (
+	_ contract.Configurator   = &configurator{}
+	_ contract.Deconfigurator = &configurator{}
+)
 
 // ConfigKey is the key used in json config files to specify this precompile config.
 // must be unique across all precompiles.
@@ -46,16 +50,32 @@ func (*configurator) Configure(chainConfig precompileconfig.ChainConfig, cfg pre
 		return fmt.Errorf("expected config type %T, got %T: %v", &Config{}, cfg, cfg)
 	}
 	// Store the initial fee config into the state when the fee manager activates.
+	feeConfig := chainConfig.GetFeeConfig()
 	if config.InitialFeeConfig != nil {
-		if err := StoreFeeConfig(state, *config.InitialFeeConfig, blockContext); err != nil {
+		feeConfig = *config.InitialFeeConfig
+		if err := StoreFeeConfig(state, feeConfig, blockContext); err != nil {
 			// This should not happen since we already checked this config with Verify()
 			return fmt.Errorf("cannot configure given initial fee config: %w", err)
 		}
 	} else {
-		if err := StoreFeeConfig(state, chainConfig.GetFeeConfig(), blockContext); err != nil {
+		if err := StoreFeeConfig(state, feeConfig, blockContext); err != nil {
 			// This should not happen since we already checked the chain config in the genesis creation.
 			return fmt.Errorf("cannot configure fee config in chain config: %w", err)
 		}
 	}
+	if chainConfig.IsDUpgrade(blockContext.Timestamp()) {
+		if err := appendFeeConfigHistory(state, feeConfig, blockContext.Number()); err != nil {
+			return fmt.Errorf("cannot append initial fee config to history: %w", err)
+		}
+	}
+	if config.VoteConfig != nil {
+		storeFeeConfigVoteParams(state, (*big.Int)(config.VoteConfig.Quorum), config.VoteConfig.votingPeriod())
+	}
 	return config.AllowListConfig.Configure(chainConfig, ContractAddress, state, blockContext)
 }
+
+// Deconfigure refunds the native tokens locked in fee config votes, since disabling the
+// precompile wipes its balance.
+func (*configurator) Deconfigure(state contract.StateDB, _ contract.ConfigurationBlockContext) error {
+	return refundFeeConfigLockedVotes(state)
+}
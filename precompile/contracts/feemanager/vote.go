@@ -0,0 +1,489 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feemanager
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// When the config specifies a VoteConfig, the fee config can no longer be set directly by
+// enabled addresses. Instead, anyone can propose a fee config, which is voted on by locking
+// native tokens in the precompile for the duration of the vote. Once the voting period is
+// over, a proposal with at least the quorum of locked tokens in favor and more tokens in
+// favor than against can be executed by anyone, and voters can withdraw their tokens.
+// Tokens still locked when the precompile is disabled are refunded to their voters.
+
+// DefaultFeeConfigVotingPeriod is the number of seconds a fee config proposal is open
+// for votes, unless the config specifies otherwise.
+const DefaultFeeConfigVotingPeriod uint64 = 7 * 24 * 60 * 60
+
+// FeeConfigVoteQuorumFraction bounds the number of locked votes that must be refunded
+// when the precompile is disabled: a vote must lock at least the quorum divided by
+// FeeConfigVoteQuorumFraction.
+const FeeConfigVoteQuorumFraction = 100
+
+const (
+	// fields of a fee config proposal, stored after the fee config fields
+	proposalDeadlineField = numFeeConfigField + 1 + iota
+	proposalYesWeightField
+	proposalNoWeightField
+	proposalExecutedField
+	numFeeConfigProposalField = proposalExecutedField
+)
+
+const (
+	// proposalReads is the number of storage slots read to look up a proposal, including
+	// the proposal count.
+	proposalReads = numFeeConfigProposalField + 1
+
+	ProposeFeeConfigGasCost      = contract.ReadGasCostPerSlot*3 + contract.WriteGasCostPerSlot*(numFeeConfigField+2) // vote params and proposal count, then fee config fields, deadline and proposal count
+	VoteFeeConfigGasCost         = contract.ReadGasCostPerSlot*(proposalReads+3) + contract.WriteGasCostPerSlot*6     // proposal, quorum, locked amount and locked vote count, then locked amount, weight, locked vote entry, index and count
+	ExecuteFeeConfigGasCost      = contract.ReadGasCostPerSlot*(proposalReads+1) + contract.WriteGasCostPerSlot + SetFeeConfigGasCost + FeeConfigHistoryGasCost
+	WithdrawFeeConfigVoteGasCost = contract.ReadGasCostPerSlot*(proposalReads+5) + contract.WriteGasCostPerSlot*10 // proposal, locked amount, locked vote index, count and last entry, then locked amount, balances, moved entry and index, cleared entry and index and count
+	GetFeeConfigProposalGasCost  = contract.ReadGasCostPerSlot * proposalReads
+
+	proposalIDInputLen = common.HashLength
+	voteInputLen       = common.HashLength * 2
+)
+
+var (
+	proposeFeeConfigSignature      = contract.CalculateFunctionSelector("proposeFeeConfig(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)")
+	voteFeeConfigSignature         = contract.CalculateFunctionSelector("voteFeeConfig(uint256,bool)")
+	executeFeeConfigSignature      = contract.CalculateFunctionSelector("executeFeeConfig(uint256)")
+	withdrawFeeConfigVoteSignature = contract.CalculateFunctionSelector("withdrawFeeConfigVote(uint256)")
+	getFeeConfigProposalSignature  = contract.CalculateFunctionSelector("getFeeConfigProposal(uint256)")
+
+	feeConfigVoteQuorumKey    = common.Hash{'f', 'c', 'v', 'q'}
+	feeConfigVotingPeriodKey  = common.Hash{'f', 'c', 'v', 'p'}
+	feeConfigProposalCountKey = common.Hash{'f', 'c', 'v', 'c'}
+	feeConfigProposalPrefix   = []byte("feeConfigProposal")
+	feeConfigLockedVotePrefix = []byte("feeConfigLockedVote")
+	// Every outstanding locked vote is recorded in a list, so that the tokens still locked
+	// can be refunded when the precompile is disabled. Withdrawn votes are removed from the
+	// list by moving its last entry in their place, using the index stored per vote.
+	feeConfigLockedVoteCountKey    = common.Hash{'f', 'c', 'v', 'l'}
+	feeConfigLockedVoteEntryPrefix = []byte("feeConfigLockedVoteEntry")
+	feeConfigLockedVoteIndexPrefix = []byte("feeConfigLockedVoteIndex")
+	trueHash                       = common.BigToHash(common.Big1)
+
+	ErrFeeConfigVoteRequired         = errors.New("fee config can only be changed by vote")
+	ErrFeeConfigVotingDisabled       = errors.New("fee config voting is disabled")
+	ErrUnknownFeeConfigProposal      = errors.New("unknown fee config proposal")
+	ErrFeeConfigVotingClosed         = errors.New("fee config proposal voting is closed")
+	ErrFeeConfigVotingOpen           = errors.New("fee config proposal voting is still open")
+	ErrFeeConfigVoteWithoutValue     = errors.New("fee config vote must lock value")
+	ErrFeeConfigVoteBelowMinimum     = errors.New("fee config vote locks less than the minimum")
+	ErrAlreadyVotedOnFeeConfig       = errors.New("already voted on fee config proposal")
+	ErrFeeConfigProposalNotPassed    = errors.New("fee config proposal did not pass")
+	ErrFeeConfigProposalExecuted     = errors.New("fee config proposal already executed")
+	ErrNoLockedFeeConfigVote         = errors.New("no locked fee config vote")
+	errInvalidFeeConfigProposalInput = errors.New("invalid input length for fee config proposal")
+)
+
+// FeeConfigProposal is a proposed fee config and the tally of the votes on it.
+type FeeConfigProposal struct {
+	FeeConfig commontype.FeeConfig
+	// Deadline is the timestamp at which voting on the proposal closes.
+	Deadline uint64
+	// YesWeight and NoWeight are the amounts of native tokens locked in favor of and
+	// against the proposal.
+	YesWeight *big.Int
+	NoWeight  *big.Int
+	Executed  bool
+}
+
+// Passed returns true if the proposal has at least [quorum] locked in favor and more locked
+// in favor than against.
+func (p FeeConfigProposal) Passed(quorum *big.Int) bool {
+	return p.YesWeight.Cmp(quorum) >= 0 && p.YesWeight.Cmp(p.NoWeight) > 0
+}
+
+// feeConfigProposalKey returns the storage key of [field] of the proposal with [proposalID].
+func feeConfigProposalKey(proposalID *big.Int, field int) common.Hash {
+	return crypto.Keccak256Hash(feeConfigProposalPrefix, common.BigToHash(proposalID).Bytes(), []byte{byte(field)})
+}
+
+// feeConfigLockedVoteKey returns the storage key of the amount locked by [voter] on the
+// proposal with [proposalID].
+func feeConfigLockedVoteKey(proposalID *big.Int, voter common.Address) common.Hash {
+	return crypto.Keccak256Hash(feeConfigLockedVotePrefix, common.BigToHash(proposalID).Bytes(), voter.Bytes())
+}
+
+// feeConfigLockedVoteEntryKeys returns the storage keys of the proposal ID and the voter of
+// the locked vote at [index] of the list of locked votes.
+func feeConfigLockedVoteEntryKeys(index *big.Int) (common.Hash, common.Hash) {
+	indexBytes := common.BigToHash(index).Bytes()
+	return crypto.Keccak256Hash(feeConfigLockedVoteEntryPrefix, indexBytes, []byte{0}),
+		crypto.Keccak256Hash(feeConfigLockedVoteEntryPrefix, indexBytes, []byte{1})
+}
+
+// feeConfigLockedVoteIndexKey returns the storage key of the position in the list of
+// locked votes of the vote of [voter] on the proposal with [proposalID], plus one.
+func feeConfigLockedVoteIndexKey(proposalID *big.Int, voter common.Address) common.Hash {
+	return crypto.Keccak256Hash(feeConfigLockedVoteIndexPrefix, common.BigToHash(proposalID).Bytes(), voter.Bytes())
+}
+
+// setFeeConfigLockedVoteEntry stores the vote of [voter] on [proposalID] at [index] of the
+// list of locked votes.
+func setFeeConfigLockedVoteEntry(stateDB contract.StateDB, index *big.Int, proposalID *big.Int, voter common.Address) {
+	proposalIDKey, voterKey := feeConfigLockedVoteEntryKeys(index)
+	stateDB.SetState(ContractAddress, proposalIDKey, common.BigToHash(proposalID))
+	stateDB.SetState(ContractAddress, voterKey, common.BytesToHash(voter.Bytes()))
+	stateDB.SetState(ContractAddress, feeConfigLockedVoteIndexKey(proposalID, voter), common.BigToHash(new(big.Int).Add(index, common.Big1)))
+}
+
+// getFeeConfigLockedVoteEntry returns the proposal ID and voter of the vote at [index] of
+// the list of locked votes.
+func getFeeConfigLockedVoteEntry(stateDB contract.StateDB, index *big.Int) (*big.Int, common.Address) {
+	proposalIDKey, voterKey := feeConfigLockedVoteEntryKeys(index)
+	return stateDB.GetState(ContractAddress, proposalIDKey).Big(), common.BytesToAddress(stateDB.GetState(ContractAddress, voterKey).Bytes())
+}
+
+// recordFeeConfigLockedVote appends the vote of [voter] on [proposalID] to the list of
+// locked votes.
+func recordFeeConfigLockedVote(stateDB contract.StateDB, proposalID *big.Int, voter common.Address) {
+	count := stateDB.GetState(ContractAddress, feeConfigLockedVoteCountKey).Big()
+	setFeeConfigLockedVoteEntry(stateDB, count, proposalID, voter)
+	stateDB.SetState(ContractAddress, feeConfigLockedVoteCountKey, common.BigToHash(count.Add(count, common.Big1)))
+}
+
+// removeFeeConfigLockedVote removes the vote of [voter] on [proposalID] from the list of
+// locked votes by moving the last entry of the list in its place.
+func removeFeeConfigLockedVote(stateDB contract.StateDB, proposalID *big.Int, voter common.Address) {
+	indexKey := feeConfigLockedVoteIndexKey(proposalID, voter)
+	index := stateDB.GetState(ContractAddress, indexKey).Big()
+	if index.Sign() == 0 {
+		return
+	}
+	index.Sub(index, common.Big1)
+	last := stateDB.GetState(ContractAddress, feeConfigLockedVoteCountKey).Big()
+	last.Sub(last, common.Big1)
+	if index.Cmp(last) != 0 {
+		lastProposalID, lastVoter := getFeeConfigLockedVoteEntry(stateDB, last)
+		setFeeConfigLockedVoteEntry(stateDB, index, lastProposalID, lastVoter)
+	}
+	proposalIDKey, voterKey := feeConfigLockedVoteEntryKeys(last)
+	stateDB.SetState(ContractAddress, proposalIDKey, common.Hash{})
+	stateDB.SetState(ContractAddress, voterKey, common.Hash{})
+	stateDB.SetState(ContractAddress, indexKey, common.Hash{})
+	stateDB.SetState(ContractAddress, feeConfigLockedVoteCountKey, common.BigToHash(last))
+}
+
+// refundFeeConfigLockedVotes returns the tokens of every vote that has not been withdrawn
+// to its voter. Called before the precompile is disabled, which wipes its balance.
+func refundFeeConfigLockedVotes(stateDB contract.StateDB) error {
+	count := stateDB.GetState(ContractAddress, feeConfigLockedVoteCountKey).Big()
+	for i := new(big.Int); i.Cmp(count) < 0; i.Add(i, common.Big1) {
+		proposalID, voter := getFeeConfigLockedVoteEntry(stateDB, i)
+		lockedKey := feeConfigLockedVoteKey(proposalID, voter)
+		locked := stateDB.GetState(ContractAddress, lockedKey).Big()
+		stateDB.SetState(ContractAddress, lockedKey, common.Hash{})
+		if err := contract.TransferBalance(stateDB, ContractAddress, voter, locked); err != nil {
+			return fmt.Errorf("cannot refund fee config vote of %s on proposal %s: %w", voter, proposalID, err)
+		}
+	}
+	return nil
+}
+
+// storeFeeConfigVoteParams stores the quorum and voting period of fee config votes. A zero
+// [quorum] disables voting and allows enabled addresses to set the fee config directly.
+func storeFeeConfigVoteParams(stateDB contract.StateDB, quorum *big.Int, votingPeriod uint64) {
+	stateDB.SetState(ContractAddress, feeConfigVoteQuorumKey, common.BigToHash(quorum))
+	stateDB.SetState(ContractAddress, feeConfigVotingPeriodKey, common.BigToHash(new(big.Int).SetUint64(votingPeriod)))
+}
+
+// GetFeeConfigVoteQuorum returns the amount of native tokens that must be locked in favor
+// of a fee config proposal for it to pass, or zero if fee config voting is disabled.
+func GetFeeConfigVoteQuorum(stateDB contract.StateDB) *big.Int {
+	return stateDB.GetState(ContractAddress, feeConfigVoteQuorumKey).Big()
+}
+
+// GetFeeConfigProposal returns the fee config proposal with [proposalID] and true, or false
+// if the proposal does not exist.
+func GetFeeConfigProposal(stateDB contract.StateDB, proposalID *big.Int) (FeeConfigProposal, bool) {
+	count := stateDB.GetState(ContractAddress, feeConfigProposalCountKey).Big()
+	if proposalID.Sign() <= 0 || proposalID.Cmp(count) > 0 {
+		return FeeConfigProposal{}, false
+	}
+	return FeeConfigProposal{
+		FeeConfig: readFeeConfig(stateDB, func(field int) common.Hash { return feeConfigProposalKey(proposalID, field) }),
+		Deadline:  stateDB.GetState(ContractAddress, feeConfigProposalKey(proposalID, proposalDeadlineField)).Big().Uint64(),
+		YesWeight: stateDB.GetState(ContractAddress, feeConfigProposalKey(proposalID, proposalYesWeightField)).Big(),
+		NoWeight:  stateDB.GetState(ContractAddress, feeConfigProposalKey(proposalID, proposalNoWeightField)).Big(),
+		Executed:  stateDB.GetState(ContractAddress, feeConfigProposalKey(proposalID, proposalExecutedField)) == trueHash,
+	}, true
+}
+
+// PackProposeFeeConfig packs [feeConfig] with the proposeFeeConfig selector.
+func PackProposeFeeConfig(feeConfig commontype.FeeConfig) ([]byte, error) {
+	packed, err := PackFeeConfig(feeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, proposeFeeConfigSignature...), packed...), nil
+}
+
+// PackVoteFeeConfig packs [proposalID] and [support] with the voteFeeConfig selector.
+func PackVoteFeeConfig(proposalID *big.Int, support bool) ([]byte, error) {
+	supportHash := common.Hash{}
+	if support {
+		supportHash = trueHash
+	}
+	input := make([]byte, contract.SelectorLen+voteInputLen)
+	err := contract.PackOrderedHashesWithSelector(input, voteFeeConfigSignature, []common.Hash{common.BigToHash(proposalID), supportHash})
+	return input, err
+}
+
+// PackExecuteFeeConfig packs [proposalID] with the executeFeeConfig selector.
+func PackExecuteFeeConfig(proposalID *big.Int) ([]byte, error) {
+	return packProposalIDInput(executeFeeConfigSignature, proposalID)
+}
+
+// PackWithdrawFeeConfigVote packs [proposalID] with the withdrawFeeConfigVote selector.
+func PackWithdrawFeeConfigVote(proposalID *big.Int) ([]byte, error) {
+	return packProposalIDInput(withdrawFeeConfigVoteSignature, proposalID)
+}
+
+// PackGetFeeConfigProposal packs [proposalID] with the getFeeConfigProposal selector.
+func PackGetFeeConfigProposal(proposalID *big.Int) ([]byte, error) {
+	return packProposalIDInput(getFeeConfigProposalSignature, proposalID)
+}
+
+func packProposalIDInput(selector []byte, proposalID *big.Int) ([]byte, error) {
+	input := make([]byte, contract.SelectorLen+proposalIDInputLen)
+	err := contract.PackOrderedHashesWithSelector(input, selector, []common.Hash{common.BigToHash(proposalID)})
+	return input, err
+}
+
+// PackFeeConfigProposalOutput packs [proposal] into the output of getFeeConfigProposal:
+// the fee config fields followed by the deadline, yes weight, no weight and executed flag.
+func PackFeeConfigProposalOutput(proposal FeeConfigProposal) ([]byte, error) {
+	packed, err := PackFeeConfig(proposal.FeeConfig)
+	if err != nil {
+		return nil, err
+	}
+	executed := common.Hash{}
+	if proposal.Executed {
+		executed = trueHash
+	}
+	tally := make([]byte, (numFeeConfigProposalField-numFeeConfigField)*common.HashLength)
+	err = contract.PackOrderedHashes(tally, []common.Hash{
+		common.BigToHash(new(big.Int).SetUint64(proposal.Deadline)),
+		common.BigToHash(proposal.YesWeight),
+		common.BigToHash(proposal.NoWeight),
+		executed,
+	})
+	return append(packed, tally...), err
+}
+
+// unpackProposalID returns the proposal ID of a function taking a single proposal ID.
+func unpackProposalID(input []byte) (*big.Int, error) {
+	if len(input) != proposalIDInputLen {
+		return nil, fmt.Errorf("%w: %d", errInvalidFeeConfigProposalInput, len(input))
+	}
+	return new(big.Int).SetBytes(input), nil
+}
+
+// proposeFeeConfig stores the fee config in [input] as a new proposal and returns its ID.
+func proposeFeeConfig(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ProposeFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	feeConfig, err := UnpackFeeConfigInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if err := feeConfig.Verify(); err != nil {
+		return nil, remainingGas, fmt.Errorf("cannot verify fee config: %w", err)
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	if GetFeeConfigVoteQuorum(stateDB).Sign() == 0 {
+		return nil, remainingGas, ErrFeeConfigVotingDisabled
+	}
+
+	proposalID := new(big.Int).Add(stateDB.GetState(ContractAddress, feeConfigProposalCountKey).Big(), common.Big1)
+	packed, err := PackFeeConfig(feeConfig)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
+		stateDB.SetState(ContractAddress, feeConfigProposalKey(proposalID, i), common.BytesToHash(contract.PackedHash(packed, i-1)))
+	}
+	votingPeriod := stateDB.GetState(ContractAddress, feeConfigVotingPeriodKey).Big().Uint64()
+	deadline := accessibleState.GetBlockContext().Timestamp() + votingPeriod
+	stateDB.SetState(ContractAddress, feeConfigProposalKey(proposalID, proposalDeadlineField), common.BigToHash(new(big.Int).SetUint64(deadline)))
+	stateDB.SetState(ContractAddress, feeConfigProposalCountKey, common.BigToHash(proposalID))
+
+	return common.BigToHash(proposalID).Bytes(), remainingGas, nil
+}
+
+// voteFeeConfig locks the value sent by the caller as a vote on the proposal in [input].
+func voteFeeConfig(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, VoteFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	if len(input) != voteInputLen {
+		return nil, remainingGas, fmt.Errorf("invalid input length for voteFeeConfig: %d", len(input))
+	}
+	proposalID := new(big.Int).SetBytes(contract.PackedHash(input, 0))
+	support := new(big.Int).SetBytes(contract.PackedHash(input, 1)).Sign() != 0
+
+	value := contract.CallValue(accessibleState)
+	if value.Sign() == 0 {
+		return nil, remainingGas, ErrFeeConfigVoteWithoutValue
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	minimum := new(big.Int).Div(GetFeeConfigVoteQuorum(stateDB), big.NewInt(FeeConfigVoteQuorumFraction))
+	if value.Cmp(minimum) < 0 {
+		return nil, remainingGas, fmt.Errorf("%w: %s < %s", ErrFeeConfigVoteBelowMinimum, value, minimum)
+	}
+	proposal, ok := GetFeeConfigProposal(stateDB, proposalID)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrUnknownFeeConfigProposal, proposalID)
+	}
+	if accessibleState.GetBlockContext().Timestamp() >= proposal.Deadline {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrFeeConfigVotingClosed, proposalID)
+	}
+	lockedKey := feeConfigLockedVoteKey(proposalID, caller)
+	if stateDB.GetState(ContractAddress, lockedKey) != (common.Hash{}) {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrAlreadyVotedOnFeeConfig, proposalID)
+	}
+
+	stateDB.SetState(ContractAddress, lockedKey, common.BigToHash(value))
+	recordFeeConfigLockedVote(stateDB, proposalID, caller)
+	if support {
+		stateDB.SetState(ContractAddress, feeConfigProposalKey(proposalID, proposalYesWeightField), common.BigToHash(new(big.Int).Add(proposal.YesWeight, value)))
+	} else {
+		stateDB.SetState(ContractAddress, feeConfigProposalKey(proposalID, proposalNoWeightField), common.BigToHash(new(big.Int).Add(proposal.NoWeight, value)))
+	}
+
+	return []byte{}, remainingGas, nil
+}
+
+// executeFeeConfig stores the fee config of the proposal in [input] once it has passed.
+func executeFeeConfig(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ExecuteFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	proposalID, err := unpackProposalID(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	quorum := GetFeeConfigVoteQuorum(stateDB)
+	if quorum.Sign() == 0 {
+		return nil, remainingGas, ErrFeeConfigVotingDisabled
+	}
+	proposal, ok := GetFeeConfigProposal(stateDB, proposalID)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrUnknownFeeConfigProposal, proposalID)
+	}
+	blockContext := accessibleState.GetBlockContext()
+	if blockContext.Timestamp() < proposal.Deadline {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrFeeConfigVotingOpen, proposalID)
+	}
+	if proposal.Executed {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrFeeConfigProposalExecuted, proposalID)
+	}
+	if !proposal.Passed(quorum) {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrFeeConfigProposalNotPassed, proposalID)
+	}
+
+	if seed := getFeeConfigHistorySeed(stateDB); seed != nil {
+		if remainingGas, err = contract.DeductGas(remainingGas, FeeConfigHistoryGasCost); err != nil {
+			return nil, 0, err
+		}
+		if err := appendFeeConfigHistory(stateDB, seed.feeConfig, seed.blockNumber); err != nil {
+			return nil, remainingGas, err
+		}
+	}
+	stateDB.SetState(ContractAddress, feeConfigProposalKey(proposalID, proposalExecutedField), trueHash)
+	if err := StoreFeeConfig(stateDB, proposal.FeeConfig, blockContext); err != nil {
+		return nil, remainingGas, err
+	}
+	if err := appendFeeConfigHistory(stateDB, proposal.FeeConfig, blockContext.Number()); err != nil {
+		return nil, remainingGas, err
+	}
+
+	return []byte{}, remainingGas, nil
+}
+
+// withdrawFeeConfigVote returns the value locked by the caller on the proposal in [input]
+// once voting on it is closed.
+func withdrawFeeConfigVote(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, WithdrawFeeConfigVoteGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	proposalID, err := unpackProposalID(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	proposal, ok := GetFeeConfigProposal(stateDB, proposalID)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrUnknownFeeConfigProposal, proposalID)
+	}
+	if accessibleState.GetBlockContext().Timestamp() < proposal.Deadline {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrFeeConfigVotingOpen, proposalID)
+	}
+	lockedKey := feeConfigLockedVoteKey(proposalID, caller)
+	locked := stateDB.GetState(ContractAddress, lockedKey).Big()
+	if locked.Sign() == 0 {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrNoLockedFeeConfigVote, proposalID)
+	}
+
+	stateDB.SetState(ContractAddress, lockedKey, common.Hash{})
+	removeFeeConfigLockedVote(stateDB, proposalID, caller)
+	if err := contract.TransferBalance(stateDB, ContractAddress, caller, locked); err != nil {
+		return nil, remainingGas, err
+	}
+
+	return []byte{}, remainingGas, nil
+}
+
+// getFeeConfigProposal returns the fee config proposal with the ID in [input].
+func getFeeConfigProposal(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetFeeConfigProposalGasCost); err != nil {
+		return nil, 0, err
+	}
+	proposalID, err := unpackProposalID(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	proposal, ok := GetFeeConfigProposal(accessibleState.GetStateDB(), proposalID)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrUnknownFeeConfigProposal, proposalID)
+	}
+	output, err := PackFeeConfigProposalOutput(proposal)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
@@ -0,0 +1,329 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feemanager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testQuorum        = big.NewInt(1000)
+	testTimestamp     = uint64(1000)
+	testDeadline      = testTimestamp + 100
+	testVoteConfig    = &VoteConfig{Quorum: math.NewHexOrDecimal256(testQuorum.Int64())}
+	testProposalID    = common.Big1
+	testProposalInput = func(pack func(*big.Int) ([]byte, error)) func(t testing.TB) []byte {
+		return func(t testing.TB) []byte {
+			input, err := pack(testProposalID)
+			require.NoError(t, err)
+			return input
+		}
+	}
+)
+
+// storeProposal stores an open proposal of [testFeeConfig] with ID 1 and the given tally.
+func storeProposal(yesWeight *big.Int, noWeight *big.Int) func(t testing.TB, stateDB contract.StateDB) {
+	return func(t testing.TB, stateDB contract.StateDB) {
+		storeFeeConfigVoteParams(stateDB, testQuorum, testDeadline-testTimestamp)
+		packed, err := PackFeeConfig(testFeeConfig)
+		require.NoError(t, err)
+		for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
+			stateDB.SetState(ContractAddress, feeConfigProposalKey(testProposalID, i), common.BytesToHash(contract.PackedHash(packed, i-1)))
+		}
+		stateDB.SetState(ContractAddress, feeConfigProposalKey(testProposalID, proposalDeadlineField), common.BigToHash(new(big.Int).SetUint64(testDeadline)))
+		stateDB.SetState(ContractAddress, feeConfigProposalKey(testProposalID, proposalYesWeightField), common.BigToHash(yesWeight))
+		stateDB.SetState(ContractAddress, feeConfigProposalKey(testProposalID, proposalNoWeightField), common.BigToHash(noWeight))
+		stateDB.SetState(ContractAddress, feeConfigProposalCountKey, common.BigToHash(testProposalID))
+	}
+}
+
+func setupBlockContextAt(timestamp uint64) func(*contract.MockBlockContext) {
+	return func(mbc *contract.MockBlockContext) {
+		mbc.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+		mbc.EXPECT().Timestamp().Return(timestamp).AnyTimes()
+	}
+}
+
+func TestFeeConfigVote(t *testing.T) {
+	voteConfig := NewConfig(nil, []common.Address{allowlist.TestAdminAddr}, []common.Address{allowlist.TestEnabledAddr}, nil, nil)
+	voteConfig.VoteConfig = testVoteConfig
+
+	tests := map[string]testutils.PrecompileTest{
+		"set config from enabled requires vote": {
+			Caller:            allowlist.TestEnabledAddr,
+			Config:            voteConfig,
+			SetupBlockContext: setupBlockContextAt(testTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeConfig(testFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: SetFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrFeeConfigVoteRequired.Error(),
+		},
+		"propose fee config": {
+			Caller:            allowlist.TestNoRoleAddr,
+			Config:            voteConfig,
+			SetupBlockContext: setupBlockContextAt(testTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackProposeFeeConfig(testFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ProposeFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedRes: common.BigToHash(common.Big1).Bytes(),
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				proposal, ok := GetFeeConfigProposal(stateDB, common.Big1)
+				require.True(t, ok)
+				require.Equal(t, testFeeConfig, proposal.FeeConfig)
+				require.Equal(t, testTimestamp+DefaultFeeConfigVotingPeriod, proposal.Deadline)
+				require.Zero(t, proposal.YesWeight.Sign())
+			},
+		},
+		"propose invalid fee config fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			Config:            voteConfig,
+			SetupBlockContext: setupBlockContextAt(testTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				feeConfig := testFeeConfig
+				feeConfig.GasLimit = common.Big0
+				input, err := PackProposeFeeConfig(feeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ProposeFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedErr: "cannot verify fee config",
+		},
+		"propose without vote config fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackProposeFeeConfig(testFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ProposeFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrFeeConfigVotingDisabled.Error(),
+		},
+		"execute passed proposal": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(testQuorum, common.Big1),
+			SetupBlockContext: setupBlockContextAt(testDeadline),
+			InputFn:           testProposalInput(PackExecuteFeeConfig),
+			SuppliedGas:       ExecuteFeeConfigGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				require.Equal(t, testFeeConfig, GetStoredFeeConfig(stateDB))
+				require.Equal(t, testBlockNumber, GetFeeConfigLastChangedAt(stateDB))
+				feeConfig, err := GetFeeConfigAt(stateDB, new(big.Int).Add(testBlockNumber, common.Big1))
+				require.NoError(t, err)
+				require.Equal(t, testFeeConfig, feeConfig)
+				proposal, ok := GetFeeConfigProposal(stateDB, testProposalID)
+				require.True(t, ok)
+				require.True(t, proposal.Executed)
+			},
+		},
+		"execute proposal twice fails": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+				storeProposal(testQuorum, common.Big0)(t, stateDB)
+				stateDB.SetState(ContractAddress, feeConfigProposalKey(testProposalID, proposalExecutedField), trueHash)
+			},
+			SetupBlockContext: setupBlockContextAt(testDeadline),
+			InputFn:           testProposalInput(PackExecuteFeeConfig),
+			SuppliedGas:       ExecuteFeeConfigGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrFeeConfigProposalExecuted.Error(),
+		},
+		"execute proposal below quorum fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(new(big.Int).Sub(testQuorum, common.Big1), common.Big0),
+			SetupBlockContext: setupBlockContextAt(testDeadline),
+			InputFn:           testProposalInput(PackExecuteFeeConfig),
+			SuppliedGas:       ExecuteFeeConfigGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrFeeConfigProposalNotPassed.Error(),
+		},
+		"execute rejected proposal fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(testQuorum, testQuorum),
+			SetupBlockContext: setupBlockContextAt(testDeadline),
+			InputFn:           testProposalInput(PackExecuteFeeConfig),
+			SuppliedGas:       ExecuteFeeConfigGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrFeeConfigProposalNotPassed.Error(),
+		},
+		"execute open proposal fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(testQuorum, common.Big0),
+			SetupBlockContext: setupBlockContextAt(testDeadline - 1),
+			InputFn:           testProposalInput(PackExecuteFeeConfig),
+			SuppliedGas:       ExecuteFeeConfigGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrFeeConfigVotingOpen.Error(),
+		},
+		"execute unknown proposal fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(testQuorum, common.Big0),
+			SetupBlockContext: setupBlockContextAt(testDeadline),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackExecuteFeeConfig(common.Big2)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ExecuteFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrUnknownFeeConfigProposal.Error(),
+		},
+		"vote without value fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(common.Big0, common.Big0),
+			SetupBlockContext: setupBlockContextAt(testTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackVoteFeeConfig(testProposalID, true)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: VoteFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrFeeConfigVoteWithoutValue.Error(),
+		},
+		"withdraw without locked vote fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        storeProposal(common.Big0, common.Big0),
+			SetupBlockContext: setupBlockContextAt(testDeadline),
+			InputFn:           testProposalInput(PackWithdrawFeeConfigVote),
+			SuppliedGas:       WithdrawFeeConfigVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrNoLockedFeeConfigVote.Error(),
+		},
+		"get fee config proposal": {
+			Caller:      allowlist.TestNoRoleAddr,
+			BeforeHook:  storeProposal(testQuorum, common.Big1),
+			InputFn:     testProposalInput(PackGetFeeConfigProposal),
+			SuppliedGas: GetFeeConfigProposalGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackFeeConfigProposalOutput(FeeConfigProposal{
+					FeeConfig: testFeeConfig,
+					Deadline:  testDeadline,
+					YesWeight: testQuorum,
+					NoWeight:  common.Big1,
+				})
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+		"insufficient gas propose fee config": {
+			Caller: allowlist.TestNoRoleAddr,
+			Config: voteConfig,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackProposeFeeConfig(testFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ProposeFeeConfigGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
+
+	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
+}
+
+func TestFeeConfigVoteLocksValue(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	stateDB := state.NewTestStateDB(t)
+	storeProposal(common.Big0, common.Big0)(t, stateDB)
+
+	chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+	chainConfig.EXPECT().IsDUpgrade(gomock.Any()).Return(true).AnyTimes()
+	chainConfig.EXPECT().GetFeeConfig().Return(commontype.ValidTestFeeConfig).AnyTimes()
+	timestamp := testTimestamp
+	blockContext := contract.NewMockBlockContext(ctrl)
+	blockContext.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+	blockContext.EXPECT().Timestamp().DoAndReturn(func() uint64 { return timestamp }).AnyTimes()
+	accessibleState := contract.NewMockAccessibleState(ctrl)
+	accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+	accessibleState.EXPECT().GetBlockContext().Return(blockContext).AnyTimes()
+	accessibleState.EXPECT().GetChainConfig().Return(chainConfig).AnyTimes()
+
+	// vote credits [value] to the precompile, as the EVM does before running a payable function.
+	vote := func(voter common.Address, value *big.Int, support bool) error {
+		stateDB.SubBalance(voter, value)
+		stateDB.AddBalance(ContractAddress, value)
+		input, err := PackVoteFeeConfig(testProposalID, support)
+		require.NoError(err)
+		_, _, err = FeeManagerPrecompile.Run(&testutils.PayableAccessibleState{AccessibleState: accessibleState, Value: value}, voter, ContractAddress, input, VoteFeeConfigGasCost, false)
+		return err
+	}
+	withdraw := func(voter common.Address) error {
+		input, err := PackWithdrawFeeConfigVote(testProposalID)
+		require.NoError(err)
+		_, _, err = FeeManagerPrecompile.Run(accessibleState, voter, ContractAddress, input, WithdrawFeeConfigVoteGasCost, false)
+		return err
+	}
+
+	yesVoter, noVoter, smallVoter := common.Address{'y'}, common.Address{'n'}, common.Address{'s'}
+	minimum := new(big.Int).Div(testQuorum, big.NewInt(FeeConfigVoteQuorumFraction))
+	stateDB.AddBalance(yesVoter, testQuorum)
+	stateDB.AddBalance(noVoter, testQuorum)
+	belowMinimum := new(big.Int).Sub(minimum, common.Big1)
+	stateDB.AddBalance(smallVoter, belowMinimum)
+	require.ErrorIs(vote(smallVoter, belowMinimum, false), ErrFeeConfigVoteBelowMinimum)
+	// Revert the value transfer of the failed vote.
+	stateDB.SubBalance(ContractAddress, belowMinimum)
+	stateDB.AddBalance(smallVoter, belowMinimum)
+	require.NoError(vote(yesVoter, testQuorum, true))
+	require.NoError(vote(noVoter, minimum, false))
+	require.ErrorIs(vote(noVoter, minimum, false), ErrAlreadyVotedOnFeeConfig)
+	require.ErrorIs(withdraw(yesVoter), ErrFeeConfigVotingOpen)
+
+	proposal, ok := GetFeeConfigProposal(stateDB, testProposalID)
+	require.True(ok)
+	require.Equal(testQuorum, proposal.YesWeight)
+	require.Equal(minimum, proposal.NoWeight)
+	require.True(proposal.Passed(testQuorum))
+
+	timestamp = testDeadline
+	require.ErrorIs(vote(noVoter, minimum, false), ErrFeeConfigVotingClosed)
+	require.NoError(withdraw(yesVoter))
+	require.Equal(testQuorum, stateDB.GetBalance(yesVoter))
+	require.ErrorIs(withdraw(yesVoter), ErrNoLockedFeeConfigVote)
+
+	// Withdrawn votes are removed from the list of locked votes.
+	require.Equal(common.Big1, stateDB.GetState(ContractAddress, feeConfigLockedVoteCountKey).Big())
+	proposalID, voter := getFeeConfigLockedVoteEntry(stateDB, common.Big0)
+	require.Equal(testProposalID, proposalID)
+	require.Equal(noVoter, voter)
+
+	// Disabling the precompile refunds the votes that were not withdrawn.
+	deconfigurator, ok := Module.Configurator.(contract.Deconfigurator)
+	require.True(ok)
+	require.NoError(deconfigurator.Deconfigure(stateDB, blockContext))
+	require.Equal(testQuorum, stateDB.GetBalance(yesVoter))
+	require.Equal(testQuorum, stateDB.GetBalance(noVoter))
+	require.Zero(stateDB.GetBalance(ContractAddress).Sign())
+	require.ErrorIs(withdraw(noVoter), ErrNoLockedFeeConfigVote)
+}
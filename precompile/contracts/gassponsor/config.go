@@ -0,0 +1,106 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gassponsor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var _ precompileconfig.Config = &Config{}
+
+var errGasSponsorCannotBeActivated = errors.New("gas sponsor cannot be activated before DUpgrade")
+
+// Config implements the precompileconfig.Config interface while adding in the
+// gas sponsor specific precompile config.
+type Config struct {
+	allowlist.AllowListConfig
+	precompileconfig.Upgrade
+	// SponsoredContracts are sponsored when the precompile is activated.
+	SponsoredContracts []common.Address `json:"sponsoredContracts,omitempty"`
+	// SenderGasQuota is the gas that can be sponsored for each sender per window of
+	// QuotaWindow seconds. If zero, no transaction is sponsored.
+	SenderGasQuota uint64 `json:"senderGasQuota,omitempty"`
+	QuotaWindow    uint64 `json:"quotaWindow,omitempty"`
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the gas sponsor with the given [admins], [enableds] and [managers] as members of the
+// allowlist, sponsoring [sponsoredContracts] up to [senderGasQuota] gas per sender every
+// [quotaWindow] seconds.
+func NewConfig(blockTimestamp *uint64, admins []common.Address, enableds []common.Address, managers []common.Address, sponsoredContracts []common.Address, senderGasQuota uint64, quotaWindow uint64) *Config {
+	return &Config{
+		AllowListConfig: allowlist.AllowListConfig{
+			AdminAddresses:   admins,
+			EnabledAddresses: enableds,
+			ManagerAddresses: managers,
+		},
+		Upgrade:            precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		SponsoredContracts: sponsoredContracts,
+		SenderGasQuota:     senderGasQuota,
+		QuotaWindow:        quotaWindow,
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the gas sponsor.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the gas sponsor precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	seen := make(map[common.Address]struct{}, len(c.SponsoredContracts))
+	for _, addr := range c.SponsoredContracts {
+		if _, ok := seen[addr]; ok {
+			return fmt.Errorf("duplicate sponsored contract: %s", addr)
+		}
+		seen[addr] = struct{}{}
+	}
+	if c.SenderGasQuota > 0 && c.QuotaWindow == 0 {
+		return errors.New("quota window must be positive if sender gas quota is set")
+	}
+	if err := c.AllowListConfig.Verify(chainConfig, c.Upgrade); err != nil {
+		return err
+	}
+	// Deposits are paid to a payable function, see [contract.NewPayableStatefulPrecompileFunction].
+	if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errGasSponsorCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	if len(c.SponsoredContracts) != len(other.SponsoredContracts) {
+		return false
+	}
+	for i, addr := range c.SponsoredContracts {
+		if addr != other.SponsoredContracts[i] {
+			return false
+		}
+	}
+	if c.SenderGasQuota != other.SenderGasQuota || c.QuotaWindow != other.QuotaWindow {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig)
+}
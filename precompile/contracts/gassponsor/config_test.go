@@ -0,0 +1,83 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gassponsor
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/mock/gomock"
+)
+
+var testSponsored = common.Address{'s'}
+
+func TestVerify(t *testing.T) {
+	tests := map[string]testutils.ConfigVerifyTest{
+		"valid config with sponsored contracts": {
+			Config: NewConfig(utils.NewUint64(3), nil, nil, nil, []common.Address{testSponsored}, 0, 0),
+		},
+		"sender gas quota without window": {
+			Config:        NewConfig(utils.NewUint64(3), nil, nil, nil, []common.Address{testSponsored}, 100_000, 0),
+			ExpectedError: "quota window must be positive",
+		},
+		"duplicate sponsored contracts": {
+			Config:        NewConfig(utils.NewUint64(3), nil, nil, nil, []common.Address{testSponsored, testSponsored}, 0, 0),
+			ExpectedError: "duplicate sponsored contract",
+		},
+		"cannot be activated before DUpgrade": {
+			Config: NewConfig(utils.NewUint64(3), nil, nil, nil, nil, 0, 0),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errGasSponsorCannotBeActivated.Error(),
+		},
+	}
+	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
+}
+
+func TestEqual(t *testing.T) {
+	admins := []common.Address{allowlist.TestAdminAddr}
+	enableds := []common.Address{allowlist.TestEnabledAddr}
+	managers := []common.Address{allowlist.TestManagerAddr}
+	sponsored := []common.Address{testSponsored}
+	tests := map[string]testutils.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 100_000, 60),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(nil, nil, nil, nil, nil, 0, 0),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different timestamp": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 100_000, 60),
+			Other:    NewConfig(utils.NewUint64(4), admins, enableds, managers, sponsored, 100_000, 60),
+			Expected: false,
+		},
+		"different sponsored contracts": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 100_000, 60),
+			Other:    NewConfig(utils.NewUint64(3), admins, enableds, managers, nil, 100_000, 60),
+			Expected: false,
+		},
+		"different sender gas quota": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 100_000, 60),
+			Other:    NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 200_000, 60),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 100_000, 60),
+			Other:    NewConfig(utils.NewUint64(3), admins, enableds, managers, sponsored, 100_000, 60),
+			Expected: true,
+		},
+	}
+	allowlist.EqualPrecompileWithAllowListTests(t, Module, tests)
+}
@@ -0,0 +1,341 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gassponsor
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The gas sponsor pays for the gas of transactions sent to sponsored contracts, so that
+// users can interact with them without holding native tokens. The gas is paid from the
+// native balance of the precompile, which anyone can fund with deposit. Enabled addresses
+// on the allow list choose the sponsored contracts and can withdraw the balance.
+//
+// A transaction is only sponsored if it pays no tip above the base fee, the balance covers
+// its gas limit at its fee cap, and its gas limit fits in the remaining quota of its sender
+// for the current window. Otherwise, the sender pays for its gas as usual. The sender
+// always pays the value of the transaction.
+
+const (
+	IsSponsoredGasCost    uint64 = contract.ReadGasCostPerSlot
+	SponsorBalanceGasCost uint64 = contract.ReadGasCostPerSlot // read the balance of the precompile
+
+	SetSponsoredGasCost uint64 = contract.WriteGasCostPerSlot + twoTopicEventGasCost                                 // write sponsorship + SponsoredSet log
+	DepositGasCost      uint64 = twoTopicEventGasCost                                                                // Deposit log
+	WithdrawGasCost     uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot*2 + twoTopicEventGasCost // transfer native balance + Withdrawal log
+
+	twoTopicEventGasCost = contract.LogGas + 2*contract.LogTopicGas + common.HashLength*contract.LogDataGasPerByte
+)
+
+var (
+	ErrCannotSetSponsored = errors.New("non-enabled cannot set sponsored contracts")
+	ErrCannotWithdraw     = errors.New("non-enabled cannot withdraw sponsor balance")
+
+	// GasSponsorRawABI contains the raw ABI of GasSponsor contract.
+	//go:embed contract.abi
+	GasSponsorRawABI string
+
+	GasSponsorABI        = contract.ParseABI(GasSponsorRawABI)
+	GasSponsorPrecompile = createGasSponsorPrecompile()
+
+	sponsoredKeyPrefix   = []byte("sponsored")
+	senderUsageKeyPrefix = []byte("senderUsage")
+
+	senderGasQuotaKey = common.Hash{'s', 'q'}
+	quotaWindowKey    = common.Hash{'q', 'w'}
+
+	sponsoredHash = common.BigToHash(common.Big1)
+)
+
+func sponsoredKey(addr common.Address) common.Hash {
+	return crypto.Keccak256Hash(sponsoredKeyPrefix, addr.Bytes())
+}
+
+func senderUsageKey(sender common.Address) common.Hash {
+	return crypto.Keccak256Hash(senderUsageKeyPrefix, sender.Bytes())
+}
+
+// GetGasSponsorStatus returns the role of [address] for the gas sponsor allow list.
+func GetGasSponsorStatus(stateDB contract.StateDB, address common.Address) allowlist.Role {
+	return allowlist.GetAllowListStatus(stateDB, ContractAddress, address)
+}
+
+// SetGasSponsorStatus sets the permissions of [address] to [role] for the gas sponsor
+// allow list. Assumes [role] has already been verified as valid.
+func SetGasSponsorStatus(stateDB contract.StateDB, address common.Address, role allowlist.Role) {
+	allowlist.SetAllowListRole(stateDB, ContractAddress, address, role)
+}
+
+// IsSponsored returns true if the gas of transactions sent to [addr] is sponsored.
+func IsSponsored(stateDB contract.StateDB, addr common.Address) bool {
+	return stateDB.GetState(ContractAddress, sponsoredKey(addr)) == sponsoredHash
+}
+
+// SetSponsored sets whether the gas of transactions sent to [addr] is sponsored.
+func SetSponsored(stateDB contract.StateDB, addr common.Address, sponsored bool) {
+	value := common.Hash{}
+	if sponsored {
+		value = sponsoredHash
+	}
+	stateDB.SetState(ContractAddress, sponsoredKey(addr), value)
+}
+
+// GetSponsorBalance returns the native balance available to sponsor gas.
+func GetSponsorBalance(stateDB contract.StateDB) *big.Int {
+	return stateDB.GetBalance(ContractAddress)
+}
+
+// GetSenderQuota returns the gas that can be sponsored for each sender per window of
+// [window] seconds.
+func GetSenderQuota(stateDB contract.StateDB) (gas uint64, window uint64) {
+	gas = stateDB.GetState(ContractAddress, senderGasQuotaKey).Big().Uint64()
+	window = stateDB.GetState(ContractAddress, quotaWindowKey).Big().Uint64()
+	return gas, window
+}
+
+// SetSenderQuota sets the gas that can be sponsored for each sender per window of
+// [window] seconds. Assumes [window] is positive if [gas] is.
+func SetSenderQuota(stateDB contract.StateDB, gas uint64, window uint64) {
+	stateDB.SetState(ContractAddress, senderGasQuotaKey, common.BigToHash(new(big.Int).SetUint64(gas)))
+	stateDB.SetState(ContractAddress, quotaWindowKey, common.BigToHash(new(big.Int).SetUint64(window)))
+}
+
+// senderUsage returns the window index stored for [sender] and the gas sponsored for
+// it in that window.
+func senderUsage(stateDB contract.StateDB, sender common.Address) (window uint64, used uint64) {
+	value := stateDB.GetState(ContractAddress, senderUsageKey(sender))
+	return binary.BigEndian.Uint64(value[16:24]), binary.BigEndian.Uint64(value[24:])
+}
+
+// AvailableSponsoredGas returns the gas that can still be sponsored for [sender] in the
+// window containing [timestamp].
+func AvailableSponsoredGas(stateDB contract.StateDB, sender common.Address, timestamp uint64) uint64 {
+	quota, window := GetSenderQuota(stateDB)
+	if quota == 0 {
+		return 0
+	}
+	usageWindow, used := senderUsage(stateDB, sender)
+	if usageWindow != timestamp/window {
+		return quota
+	}
+	if used >= quota {
+		return 0
+	}
+	return quota - used
+}
+
+// UseSponsoredGas records [gas] as sponsored for [sender] in the window containing
+// [timestamp]. Assumes [gas] does not exceed AvailableSponsoredGas.
+func UseSponsoredGas(stateDB contract.StateDB, sender common.Address, timestamp uint64, gas uint64) {
+	_, window := GetSenderQuota(stateDB)
+	current := timestamp / window
+	usageWindow, used := senderUsage(stateDB, sender)
+	if usageWindow != current {
+		used = 0
+	}
+	var value common.Hash
+	binary.BigEndian.PutUint64(value[16:24], current)
+	binary.BigEndian.PutUint64(value[24:], used+gas)
+	stateDB.SetState(ContractAddress, senderUsageKey(sender), value)
+}
+
+// CanSponsor returns true if the gas of a transaction from [sender] to [to] with
+// [gasLimit] can be sponsored at [timestamp], given that [pendingGas] was already
+// sponsored for [sender] without being recorded yet and that the sponsor balance
+// must cover [gasCost].
+// Callers must check separately that the transaction pays no tip.
+func CanSponsor(stateDB contract.StateDB, sender common.Address, to common.Address, timestamp uint64, gasLimit uint64, pendingGas uint64, gasCost *big.Int) bool {
+	if !IsSponsored(stateDB, to) || GetSponsorBalance(stateDB).Cmp(gasCost) < 0 {
+		return false
+	}
+	available := AvailableSponsoredGas(stateDB, sender, timestamp)
+	return pendingGas <= available && gasLimit <= available-pendingGas
+}
+
+func emitEvent(accessibleState contract.AccessibleState, name string, args ...interface{}) error {
+	topics, data, err := GasSponsorABI.PackEvent(name, args...)
+	if err != nil {
+		return err
+	}
+	accessibleState.GetStateDB().AddLog(ContractAddress, topics, data, accessibleState.GetBlockContext().Number().Uint64())
+	return nil
+}
+
+// PackIsSponsored packs [addr] into the input for isSponsored, including the selector.
+func PackIsSponsored(addr common.Address) ([]byte, error) {
+	return GasSponsorABI.Pack("isSponsored", addr)
+}
+
+// PackIsSponsoredOutput packs [sponsored] into the output of isSponsored.
+func PackIsSponsoredOutput(sponsored bool) ([]byte, error) {
+	return GasSponsorABI.PackOutput("isSponsored", sponsored)
+}
+
+func isSponsored(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, IsSponsoredGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := GasSponsorABI.UnpackInput("isSponsored", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	contractAddr := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+
+	output, err := PackIsSponsoredOutput(IsSponsored(accessibleState.GetStateDB(), contractAddr))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
+
+// PackSetSponsored packs [addr] and [sponsored] into the input for setSponsored, including the selector.
+func PackSetSponsored(addr common.Address, sponsored bool) ([]byte, error) {
+	return GasSponsorABI.Pack("setSponsored", addr, sponsored)
+}
+
+// setSponsored sets whether the gas of transactions sent to the input address is sponsored.
+// The caller must be enabled on the allow list.
+func setSponsored(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SetSponsoredGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := GasSponsorABI.UnpackInput("setSponsored", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	contractAddr := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	sponsored := *abi.ConvertType(res[1], new(bool)).(*bool)
+
+	stateDB := accessibleState.GetStateDB()
+	if !GetGasSponsorStatus(stateDB, caller).IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotSetSponsored, caller)
+	}
+	SetSponsored(stateDB, contractAddr, sponsored)
+	if err := emitEvent(accessibleState, "SponsoredSet", contractAddr, sponsored); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackSponsorBalance packs the input for sponsorBalance, including the selector.
+func PackSponsorBalance() ([]byte, error) {
+	return GasSponsorABI.Pack("sponsorBalance")
+}
+
+// PackSponsorBalanceOutput packs [balance] into the output of sponsorBalance.
+func PackSponsorBalanceOutput(balance *big.Int) ([]byte, error) {
+	return GasSponsorABI.PackOutput("sponsorBalance", balance)
+}
+
+func sponsorBalance(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SponsorBalanceGasCost); err != nil {
+		return nil, 0, err
+	}
+	output, err := PackSponsorBalanceOutput(GetSponsorBalance(accessibleState.GetStateDB()))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
+
+// PackDeposit packs the input for deposit, including the selector.
+func PackDeposit() ([]byte, error) {
+	return GasSponsorABI.Pack("deposit")
+}
+
+// deposit adds the value sent to the sponsor balance. The value is credited to the
+// precompile address before the precompile runs, so only the log remains to be emitted.
+func deposit(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DepositGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	if err := emitEvent(accessibleState, "Deposit", caller, contract.CallValue(accessibleState)); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackWithdraw packs [to] and [amount] into the input for withdraw, including the selector.
+func PackWithdraw(to common.Address, amount *big.Int) ([]byte, error) {
+	return GasSponsorABI.Pack("withdraw", to, amount)
+}
+
+// withdraw sends the input amount of the sponsor balance to the input address.
+// The caller must be enabled on the allow list.
+func withdraw(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, WithdrawGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := GasSponsorABI.UnpackInput("withdraw", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	to := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	amount := *abi.ConvertType(res[1], new(*big.Int)).(**big.Int)
+
+	stateDB := accessibleState.GetStateDB()
+	if !GetGasSponsorStatus(stateDB, caller).IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotWithdraw, caller)
+	}
+	if err := contract.TransferBalance(stateDB, ContractAddress, to, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	if err := emitEvent(accessibleState, "Withdrawal", to, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// createGasSponsorPrecompile returns a StatefulPrecompiledContract with getters and setters
+// for the sponsored contracts and the sponsor balance. Access to the setters is controlled
+// by an allow list for ContractAddress.
+func createGasSponsorPrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	functions = append(functions, allowlist.CreateAllowListFunctions(ContractAddress)...)
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"deposit":        deposit,
+		"isSponsored":    isSponsored,
+		"setSponsored":   setSponsored,
+		"sponsorBalance": sponsorBalance,
+		"withdraw":       withdraw,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := GasSponsorABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		if method.IsPayable() {
+			functions = append(functions, contract.NewPayableStatefulPrecompileFunction(method.ID, function))
+		} else {
+			functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+		}
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
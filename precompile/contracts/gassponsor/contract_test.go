@@ -0,0 +1,247 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gassponsor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testRecipient = common.Address{'r'}
+	testAmount    = big.NewInt(100)
+)
+
+func fundSponsor(t testing.TB, state contract.StateDB) {
+	allowlist.SetDefaultRoles(Module.Address)(t, state)
+	state.AddBalance(ContractAddress, testAmount)
+}
+
+var tests = map[string]testutils.PrecompileTest{
+	"enabled set sponsored": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackSetSponsored(testSponsored, true)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: SetSponsoredGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.True(t, IsSponsored(state, testSponsored))
+		},
+	},
+	"enabled unset sponsored": {
+		Caller: allowlist.TestEnabledAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, state)
+			SetSponsored(state, testSponsored, true)
+		},
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackSetSponsored(testSponsored, false)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: SetSponsoredGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.False(t, IsSponsored(state, testSponsored))
+		},
+	},
+	"no role cannot set sponsored": {
+		Caller:     allowlist.TestNoRoleAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackSetSponsored(testSponsored, true)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: SetSponsoredGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrCannotSetSponsored.Error(),
+	},
+	"readOnly set sponsored fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackSetSponsored(testSponsored, true)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: SetSponsoredGasCost,
+		ReadOnly:    true,
+		ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+	},
+	"set sponsored insufficient gas": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackSetSponsored(testSponsored, true)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: SetSponsoredGasCost - 1,
+		ReadOnly:    false,
+		ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+	},
+	"read sponsored": {
+		Caller: allowlist.TestNoRoleAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			SetSponsored(state, testSponsored, true)
+		},
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackIsSponsored(testSponsored)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: IsSponsoredGasCost,
+		ReadOnly:    true,
+		ExpectedRes: func() []byte {
+			res, err := PackIsSponsoredOutput(true)
+			if err != nil {
+				panic(err)
+			}
+			return res
+		}(),
+	},
+	"read sponsor balance": {
+		Caller:     allowlist.TestNoRoleAddr,
+		BeforeHook: fundSponsor,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackSponsorBalance()
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: SponsorBalanceGasCost,
+		ReadOnly:    true,
+		ExpectedRes: func() []byte {
+			res, err := PackSponsorBalanceOutput(testAmount)
+			if err != nil {
+				panic(err)
+			}
+			return res
+		}(),
+	},
+	"enabled withdraw": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundSponsor,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackWithdraw(testRecipient, big.NewInt(40))
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: WithdrawGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, big.NewInt(60), GetSponsorBalance(state))
+			require.Equal(t, big.NewInt(40), state.GetBalance(testRecipient))
+		},
+	},
+	"withdraw more than balance fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundSponsor,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackWithdraw(testRecipient, big.NewInt(101))
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: WithdrawGasCost,
+		ReadOnly:    false,
+		ExpectedErr: contract.ErrInsufficientPrecompileBalance.Error(),
+	},
+	"no role cannot withdraw": {
+		Caller:     allowlist.TestNoRoleAddr,
+		BeforeHook: fundSponsor,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackWithdraw(testRecipient, big.NewInt(40))
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: WithdrawGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrCannotWithdraw.Error(),
+	},
+	"readOnly withdraw fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundSponsor,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackWithdraw(testRecipient, big.NewInt(40))
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: WithdrawGasCost,
+		ReadOnly:    true,
+		ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+	},
+	"configure sponsored contracts": {
+		Caller:      allowlist.TestNoRoleAddr,
+		Config:      NewConfig(utils.NewUint64(0), nil, nil, nil, []common.Address{testSponsored}, 100_000, 60),
+		Input:       allowlist.PackReadAllowList(allowlist.TestNoRoleAddr),
+		SuppliedGas: allowlist.ReadAllowListGasCost,
+		ReadOnly:    true,
+		ExpectedRes: common.Hash(allowlist.NoRole).Bytes(),
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.True(t, IsSponsored(state, testSponsored))
+			require.False(t, IsSponsored(state, testRecipient))
+			gas, window := GetSenderQuota(state)
+			require.Equal(t, uint64(100_000), gas)
+			require.Equal(t, uint64(60), window)
+		},
+	},
+}
+
+func TestGasSponsorRun(t *testing.T) {
+	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
+}
+
+func BenchmarkGasSponsor(b *testing.B) {
+	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
+}
+
+func TestGasSponsorDeposit(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	stateDB := state.NewTestStateDB(t)
+	chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+	chainConfig.EXPECT().IsDUpgrade(gomock.Any()).Return(true).AnyTimes()
+	blockContext := contract.NewMockBlockContext(ctrl)
+	blockContext.EXPECT().Number().Return(common.Big1).AnyTimes()
+	blockContext.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
+	accessibleState := contract.NewMockAccessibleState(ctrl)
+	accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+	accessibleState.EXPECT().GetBlockContext().Return(blockContext).AnyTimes()
+	accessibleState.EXPECT().GetChainConfig().Return(chainConfig).AnyTimes()
+
+	// The EVM credits the value to the precompile before running a payable function.
+	caller := allowlist.TestNoRoleAddr
+	stateDB.AddBalance(ContractAddress, testAmount)
+
+	input, err := PackDeposit()
+	require.NoError(err)
+	_, remainingGas, err := GasSponsorPrecompile.Run(&testutils.PayableAccessibleState{AccessibleState: accessibleState, Value: testAmount}, caller, ContractAddress, input, DepositGasCost, false)
+	require.NoError(err)
+	require.Zero(remainingGas)
+	require.Equal(testAmount, GetSponsorBalance(stateDB))
+
+	logs := stateDB.(*state.StateDB).Logs()
+	require.Len(logs, 1)
+	require.Equal(GasSponsorABI.Events["Deposit"].ID, logs[0].Topics[0])
+	require.Equal(common.BytesToHash(caller.Bytes()), logs[0].Topics[1])
+}
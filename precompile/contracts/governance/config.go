@@ -0,0 +1,119 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package governance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultQuorumPercentage is the percentage of the validator weight that must vote in
+	// favor of a proposal for it to pass, unless the config specifies otherwise.
+	DefaultQuorumPercentage uint64 = 67
+	// MaxQuorumPercentage is the quorum percentage requiring every validator to vote in favor.
+	MaxQuorumPercentage uint64 = 100
+	// DefaultVotingPeriod is the number of seconds a proposal is open for votes, unless the
+	// config specifies otherwise.
+	DefaultVotingPeriod uint64 = 7 * 24 * 60 * 60
+)
+
+var (
+	_ precompileconfig.Config     = &Config{}
+	_ precompileconfig.Predicater = &Config{}
+)
+
+var errGovernanceCannotBeActivated = errors.New("governance cannot be activated before DUpgrade")
+
+// Config implements the precompileconfig.Config interface while adding in the
+// governance specific precompile config. Addresses in the allow list can create
+// proposals, which are voted on by the validators of the subnet.
+type Config struct {
+	allowlist.AllowListConfig
+	precompileconfig.Upgrade
+	// QuorumPercentage is the percentage of the validator weight that must vote in favor
+	// of a proposal for it to pass (0 denotes using the default).
+	QuorumPercentage uint64 `json:"quorumPercentage,omitempty"`
+	// VotingPeriod is the number of seconds a proposal is open for votes after it is
+	// created (0 denotes using the default).
+	VotingPeriod uint64 `json:"votingPeriod,omitempty"`
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// governance with the given [admins], [enableds] and [managers] as members of the allowlist,
+// requiring [quorumPercentage] of the validator weight to pass proposals open for
+// [votingPeriod] seconds.
+func NewConfig(blockTimestamp *uint64, admins []common.Address, enableds []common.Address, managers []common.Address, quorumPercentage uint64, votingPeriod uint64) *Config {
+	return &Config{
+		AllowListConfig: allowlist.AllowListConfig{
+			AdminAddresses:   admins,
+			EnabledAddresses: enableds,
+			ManagerAddresses: managers,
+		},
+		Upgrade:          precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		QuorumPercentage: quorumPercentage,
+		VotingPeriod:     votingPeriod,
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables governance.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the governance precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	if err := c.AllowListConfig.Verify(chainConfig, c.Upgrade); err != nil {
+		return err
+	}
+	if c.QuorumPercentage > MaxQuorumPercentage {
+		return fmt.Errorf("cannot specify quorum percentage (%d) > %d", c.QuorumPercentage, MaxQuorumPercentage)
+	}
+	// Votes are verified as predicates, which are only supported from DUpgrade onwards.
+	if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errGovernanceCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig) &&
+		c.QuorumPercentage == other.QuorumPercentage && c.VotingPeriod == other.VotingPeriod
+}
+
+// quorumPercentage returns the quorum percentage of [c], using the default if unspecified.
+func (c *Config) quorumPercentage() uint64 {
+	if c.QuorumPercentage == 0 {
+		return DefaultQuorumPercentage
+	}
+	return c.QuorumPercentage
+}
+
+// votingPeriod returns the voting period of [c], using the default if unspecified.
+func (c *Config) votingPeriod() uint64 {
+	if c.VotingPeriod == 0 {
+		return DefaultVotingPeriod
+	}
+	return c.VotingPeriod
+}
@@ -0,0 +1,71 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package governance
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerify(t *testing.T) {
+	admins := []common.Address{allowlist.TestAdminAddr}
+	enableds := []common.Address{allowlist.TestEnabledAddr}
+	tests := map[string]testutils.ConfigVerifyTest{
+		"quorum percentage above maximum": {
+			Config:        NewConfig(utils.NewUint64(3), admins, enableds, nil, MaxQuorumPercentage+1, 0),
+			ExpectedError: "cannot specify quorum percentage",
+		},
+		"default voting params": {
+			Config: NewConfig(utils.NewUint64(3), admins, enableds, nil, 0, 0),
+		},
+		"cannot be activated before DUpgrade": {
+			Config: NewConfig(utils.NewUint64(3), admins, enableds, nil, 0, 0),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errGovernanceCannotBeActivated.Error(),
+		},
+	}
+	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
+}
+
+func TestEqual(t *testing.T) {
+	admins := []common.Address{allowlist.TestAdminAddr}
+	tests := map[string]testutils.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, 0, 0),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, 0, 0),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different quorum percentage": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, 60, 0),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, 70, 0),
+			Expected: false,
+		},
+		"different voting period": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, 0, 10),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, 0, 20),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, 60, 10),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, 60, 10),
+			Expected: true,
+		},
+	}
+	allowlist.EqualPrecompileWithAllowListTests(t, Module, tests)
+}
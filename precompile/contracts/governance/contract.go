@@ -0,0 +1,414 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package governance
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	CreateProposalGasCost uint64 = contract.ReadGasCostPerSlot*2 + contract.WriteGasCostPerSlot*5 + allowlist.ReadAllowListGasCost // read voting params, write count, payload hash, deadline and validator set snapshot + read allow list
+	CastVoteGasCost       uint64 = contract.ReadGasCostPerSlot*4 + contract.WriteGasCostPerSlot*2                                  // read deadline, snapshot height, voted and tally, write voted and tally
+	GetProposalGasCost    uint64 = contract.ReadGasCostPerSlot * proposalFields
+	ProposalPassedGasCost uint64 = contract.ReadGasCostPerSlot * (proposalFields + 1) // plus the quorum percentage
+)
+
+const (
+	// Fields of a proposal in the storage of the precompile.
+	payloadHashField byte = iota
+	deadlineField
+	yesWeightField
+	noWeightField
+	totalWeightField
+	pChainHeightField
+	proposalFields
+)
+
+var (
+	ErrCannotCreateProposal = errors.New("non-enabled cannot call createProposal")
+	ErrInvalidVote          = errors.New("invalid vote")
+	ErrUnknownProposal      = errors.New("unknown proposal")
+	ErrVotingClosed         = errors.New("voting closed")
+	ErrAlreadyVoted         = errors.New("already voted")
+	ErrMissingSnapshot      = errors.New("missing validator set snapshot")
+
+	// GovernanceRawABI contains the raw ABI of Governance contract.
+	//go:embed contract.abi
+	GovernanceRawABI string
+
+	GovernanceABI        = contract.ParseABI(GovernanceRawABI)
+	GovernancePrecompile = createGovernancePrecompile()
+
+	proposalCountKey    = common.Hash{'g', 'p', 'c'}
+	quorumPercentageKey = common.Hash{'g', 'q', 'p'}
+	votingPeriodKey     = common.Hash{'g', 'v', 'p'}
+)
+
+// Proposal is a proposal and its vote tally. [TotalWeight] is the total weight of the
+// validator set snapshotted at [PChainHeight] when the proposal was created.
+type Proposal struct {
+	PayloadHash  common.Hash
+	Deadline     uint64
+	YesWeight    uint64
+	NoWeight     uint64
+	TotalWeight  uint64
+	PChainHeight uint64
+}
+
+// proposalKey returns the storage key of [field] of the proposal [proposalID].
+func proposalKey(proposalID uint64, field byte) common.Hash {
+	key := common.Hash{'g', 'p', field}
+	binary.BigEndian.PutUint64(key[common.HashLength-8:], proposalID)
+	return key
+}
+
+// votedKey returns the storage key recording whether the validator of [vote] voted on its proposal.
+func votedKey(vote *Vote) common.Hash {
+	return crypto.Keccak256Hash([]byte{'g', 'v'}, binary.BigEndian.AppendUint64(nil, vote.ProposalID), vote.NodeID[:])
+}
+
+func getUint64(stateDB contract.StateDB, key common.Hash) uint64 {
+	return stateDB.GetState(ContractAddress, key).Big().Uint64()
+}
+
+func setUint64(stateDB contract.StateDB, key common.Hash, value uint64) {
+	stateDB.SetState(ContractAddress, key, common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+// storeVotingParams stores the quorum percentage and voting period of new proposals.
+func storeVotingParams(stateDB contract.StateDB, quorumPercentage uint64, votingPeriod uint64) {
+	setUint64(stateDB, quorumPercentageKey, quorumPercentage)
+	setUint64(stateDB, votingPeriodKey, votingPeriod)
+}
+
+// GetProposal returns the proposal [proposalID] and true, or false if it does not exist.
+func GetProposal(stateDB contract.StateDB, proposalID uint64) (Proposal, bool) {
+	proposal := Proposal{
+		PayloadHash:  stateDB.GetState(ContractAddress, proposalKey(proposalID, payloadHashField)),
+		Deadline:     getUint64(stateDB, proposalKey(proposalID, deadlineField)),
+		YesWeight:    getUint64(stateDB, proposalKey(proposalID, yesWeightField)),
+		NoWeight:     getUint64(stateDB, proposalKey(proposalID, noWeightField)),
+		TotalWeight:  getUint64(stateDB, proposalKey(proposalID, totalWeightField)),
+		PChainHeight: getUint64(stateDB, proposalKey(proposalID, pChainHeightField)),
+	}
+	// Proposals are created with a deadline after the timestamp of their block.
+	return proposal, proposal.Deadline != 0
+}
+
+// ProposalPassed returns true if the validators in favor of the proposal [proposalID] hold at
+// least the quorum percentage of the total weight of its validator set snapshot.
+func ProposalPassed(stateDB contract.StateDB, proposalID uint64) bool {
+	proposal, ok := GetProposal(stateDB, proposalID)
+	if !ok || proposal.TotalWeight == 0 {
+		return false
+	}
+	quorumPercentage := getUint64(stateDB, quorumPercentageKey)
+	yes := new(big.Int).Mul(new(big.Int).SetUint64(proposal.YesWeight), new(big.Int).SetUint64(MaxQuorumPercentage))
+	required := new(big.Int).Mul(new(big.Int).SetUint64(proposal.TotalWeight), new(big.Int).SetUint64(quorumPercentage))
+	return yes.Cmp(required) >= 0
+}
+
+// unpackProposalID unpacks the proposalID argument of [method] from [input].
+func unpackProposalID(method string, input []byte) (uint64, error) {
+	res, err := GovernanceABI.UnpackInput(method, input)
+	if err != nil {
+		return 0, err
+	}
+	proposalID := *abi.ConvertType(res[0], new(*big.Int)).(**big.Int)
+	if !proposalID.IsUint64() {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownProposal, proposalID)
+	}
+	return proposalID.Uint64(), nil
+}
+
+// PackCreateProposal packs [payloadHash] of type common.Hash into the appropriate arguments for createProposal.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackCreateProposal(payloadHash common.Hash) ([]byte, error) {
+	return GovernanceABI.Pack("createProposal", payloadHash)
+}
+
+// PackCreateProposalOutput attempts to pack given proposalID of type *big.Int
+// to conform the ABI outputs.
+func PackCreateProposalOutput(proposalID *big.Int) ([]byte, error) {
+	return GovernanceABI.PackOutput("createProposal", proposalID)
+}
+
+// UnpackCreateProposalInput attempts to unpack [input] into the common.Hash type argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackCreateProposalInput(input []byte) (common.Hash, error) {
+	res, err := GovernanceABI.UnpackInput("createProposal", input)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	unpacked := *abi.ConvertType(res[0], new([32]byte)).(*[32]byte)
+	return common.Hash(unpacked), nil
+}
+
+// createProposal creates a proposal for the payload hash in [input], open for votes until the
+// voting period has elapsed. The validator set the votes are weighed against is snapshotted
+// by the empty governance predicate at index 0 of the transaction. Only callable by enabled
+// addresses in the allow list.
+func createProposal(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, CreateProposalGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	payloadHash, err := UnpackCreateProposalInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to call this function.
+	callerStatus := allowlist.GetAllowListStatus(stateDB, ContractAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotCreateProposal, caller)
+	}
+	totalWeight, pChainHeight, err := getValidatorSetSnapshot(accessibleState)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	proposalID := getUint64(stateDB, proposalCountKey) + 1
+	deadline := accessibleState.GetBlockContext().Timestamp() + getUint64(stateDB, votingPeriodKey)
+	if deadline < accessibleState.GetBlockContext().Timestamp() {
+		deadline = math.MaxUint64
+	}
+	setUint64(stateDB, proposalCountKey, proposalID)
+	stateDB.SetState(ContractAddress, proposalKey(proposalID, payloadHashField), payloadHash)
+	setUint64(stateDB, proposalKey(proposalID, deadlineField), deadline)
+	setUint64(stateDB, proposalKey(proposalID, totalWeightField), totalWeight)
+	setUint64(stateDB, proposalKey(proposalID, pChainHeightField), pChainHeight)
+
+	packedOutput, err := PackCreateProposalOutput(new(big.Int).SetUint64(proposalID))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+// getValidatorSetSnapshot returns the total weight and P-Chain height of the validator set
+// snapshotted by the empty governance predicate at index 0 of the current transaction.
+func getValidatorSetSnapshot(accessibleState contract.AccessibleState) (uint64, uint64, error) {
+	stateDB := accessibleState.GetStateDB()
+	predicateBytes, exists := stateDB.GetPredicateStorageSlots(ContractAddress, 0)
+	if !exists {
+		return 0, 0, ErrMissingSnapshot
+	}
+	unpackedPredicateBytes, err := predicateutils.UnpackPredicate(predicateBytes)
+	if err != nil || len(unpackedPredicateBytes) != 0 {
+		return 0, 0, fmt.Errorf("%w: predicate at index 0 is not a snapshot request", ErrMissingSnapshot)
+	}
+	results := accessibleState.GetBlockContext().GetPredicateResults(stateDB.GetTxHash(), ContractAddress)
+	_, totalWeight, pChainHeight := voteResult(results, 0)
+	if totalWeight == 0 {
+		return 0, 0, fmt.Errorf("%w: validator set could not be retrieved", ErrMissingSnapshot)
+	}
+	return totalWeight, pChainHeight, nil
+}
+
+// PackCastVote packs [index] of type uint32 into the appropriate arguments for castVote.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackCastVote(index uint32) ([]byte, error) {
+	return GovernanceABI.Pack("castVote", index)
+}
+
+// UnpackCastVoteInput attempts to unpack [input] into the uint32 type argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackCastVoteInput(input []byte) (uint32, error) {
+	res, err := GovernanceABI.UnpackInput("castVote", input)
+	if err != nil {
+		return 0, err
+	}
+	unpacked := *abi.ConvertType(res[0], new(uint32)).(*uint32)
+	return unpacked, nil
+}
+
+// castVote tallies the vote at the index in [input] of the governance predicates of the
+// transaction, weighted by the stake of the voter in the validator set snapshot of the
+// proposal. Reverts if the vote failed verification, was not cast on the snapshot of the
+// proposal, the proposal is not open for votes or the voter already voted on it.
+func castVote(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, CastVoteGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	index, err := UnpackCastVoteInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if index > math.MaxInt32 {
+		return nil, remainingGas, fmt.Errorf("%w: index %d larger than MaxInt32", ErrInvalidVote, index)
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	predicateBytes, exists := stateDB.GetPredicateStorageSlots(ContractAddress, int(index))
+	results := accessibleState.GetBlockContext().GetPredicateResults(stateDB.GetTxHash(), ContractAddress)
+	weight, _, _ := voteResult(results, int(index))
+	if !exists || weight == 0 {
+		return nil, remainingGas, fmt.Errorf("%w at index %d", ErrInvalidVote, index)
+	}
+	// Note: since the predicate is verified in advance of execution, the vote can be parsed
+	// without error.
+	unpackedPredicateBytes, err := predicateutils.UnpackPredicate(predicateBytes)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidVote, err)
+	}
+	vote, err := ParseVote(unpackedPredicateBytes)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidVote, err)
+	}
+
+	proposal, ok := GetProposal(stateDB, vote.ProposalID)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %d", ErrUnknownProposal, vote.ProposalID)
+	}
+	// The vote was verified against the validator set at the P-Chain height it was signed over.
+	if vote.PChainHeight != proposal.PChainHeight {
+		return nil, remainingGas, fmt.Errorf("%w: cast on validator set at P-Chain height %d, proposal %d snapshotted at %d", ErrInvalidVote, vote.PChainHeight, vote.ProposalID, proposal.PChainHeight)
+	}
+	if accessibleState.GetBlockContext().Timestamp() > proposal.Deadline {
+		return nil, remainingGas, fmt.Errorf("%w: proposal %d", ErrVotingClosed, vote.ProposalID)
+	}
+	voted := votedKey(vote)
+	if stateDB.GetState(ContractAddress, voted) != (common.Hash{}) {
+		return nil, remainingGas, fmt.Errorf("%w: %s on proposal %d", ErrAlreadyVoted, vote.NodeID, vote.ProposalID)
+	}
+
+	stateDB.SetState(ContractAddress, voted, common.BigToHash(common.Big1))
+	if vote.Support {
+		setUint64(stateDB, proposalKey(vote.ProposalID, yesWeightField), proposal.YesWeight+weight)
+	} else {
+		setUint64(stateDB, proposalKey(vote.ProposalID, noWeightField), proposal.NoWeight+weight)
+	}
+
+	// this function does not return an output, leave this one as is
+	packedOutput := []byte{}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+// PackGetProposal packs [proposalID] of type *big.Int into the appropriate arguments for getProposal.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetProposal(proposalID *big.Int) ([]byte, error) {
+	return GovernanceABI.Pack("getProposal", proposalID)
+}
+
+// PackGetProposalOutput attempts to pack given [proposal] of type Proposal
+// to conform the ABI outputs.
+func PackGetProposalOutput(proposal Proposal) ([]byte, error) {
+	return GovernanceABI.PackOutput("getProposal",
+		proposal.PayloadHash,
+		proposal.Deadline,
+		proposal.YesWeight,
+		proposal.NoWeight,
+		proposal.TotalWeight,
+		proposal.PChainHeight,
+	)
+}
+
+// getProposal returns the payload hash, deadline, vote tally and validator set snapshot of the
+// proposal in [input].
+func getProposal(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetProposalGasCost); err != nil {
+		return nil, 0, err
+	}
+	proposalID, err := unpackProposalID("getProposal", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	proposal, ok := GetProposal(accessibleState.GetStateDB(), proposalID)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %d", ErrUnknownProposal, proposalID)
+	}
+	packedOutput, err := PackGetProposalOutput(proposal)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+// PackProposalPassed packs [proposalID] of type *big.Int into the appropriate arguments for proposalPassed.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackProposalPassed(proposalID *big.Int) ([]byte, error) {
+	return GovernanceABI.Pack("proposalPassed", proposalID)
+}
+
+// PackProposalPassedOutput attempts to pack given passed of type bool
+// to conform the ABI outputs.
+func PackProposalPassedOutput(passed bool) ([]byte, error) {
+	return GovernanceABI.PackOutput("proposalPassed", passed)
+}
+
+// proposalPassed returns true if the proposal in [input] reached the quorum of validator weight
+// in favor of it, so that contracts can gate actions on the consent of the validators.
+func proposalPassed(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ProposalPassedGasCost); err != nil {
+		return nil, 0, err
+	}
+	proposalID, err := unpackProposalID("proposalPassed", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := PackProposalPassedOutput(ProposalPassed(accessibleState.GetStateDB(), proposalID))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+// createGovernancePrecompile returns a StatefulPrecompiledContract with getters and setters for the precompile.
+// Access to createProposal is controlled by an allow list for [ContractAddress].
+func createGovernancePrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	functions = append(functions, allowlist.CreateAllowListFunctions(ContractAddress)...)
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"castVote":       castVote,
+		"createProposal": createProposal,
+		"getProposal":    getProposal,
+		"proposalPassed": proposalPassed,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := GovernanceABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
@@ -0,0 +1,337 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package governance
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPayloadHash  = common.Hash{'p', 'a', 'y', 'l', 'o', 'a', 'd'}
+	testNodeID       = ids.GenerateTestNodeID()
+	testTimestamp    = uint64(1000)
+	testDeadline     = testTimestamp + 100
+	testPChainHeight = uint64(10)
+)
+
+// setProposal stores an open proposal with ID 1, snapshotted at [testPChainHeight] with a
+// total weight of 100, and the default voting params.
+func setProposal(t testing.TB, stateDB contract.StateDB) {
+	allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+	storeVotingParams(stateDB, DefaultQuorumPercentage, DefaultVotingPeriod)
+	setUint64(stateDB, proposalCountKey, 1)
+	stateDB.SetState(ContractAddress, proposalKey(1, payloadHashField), testPayloadHash)
+	setUint64(stateDB, proposalKey(1, deadlineField), testDeadline)
+	setUint64(stateDB, proposalKey(1, totalWeightField), 100)
+	setUint64(stateDB, proposalKey(1, pChainHeightField), testPChainHeight)
+}
+
+// setSnapshot stores an open proposal and a validator set snapshot request as the only
+// governance predicate of the transaction.
+func setSnapshot(t testing.TB, stateDB contract.StateDB) {
+	setProposal(t, stateDB)
+	stateDB.SetPredicateStorageSlots(ContractAddress, [][]byte{predicateutils.PackPredicate(nil)})
+}
+
+// setVote stores [vote] as the only governance predicate of the transaction.
+func setVote(vote *Vote) func(t testing.TB, stateDB contract.StateDB) {
+	return func(t testing.TB, stateDB contract.StateDB) {
+		setProposal(t, stateDB)
+		stateDB.SetPredicateStorageSlots(ContractAddress, [][]byte{predicateutils.PackPredicate(vote.Bytes())})
+	}
+}
+
+// voteResults returns the predicate results of a single vote or snapshot request.
+func voteResults(weight uint64, totalWeight uint64, pChainHeight uint64) []byte {
+	results := binary.BigEndian.AppendUint64(nil, weight)
+	results = binary.BigEndian.AppendUint64(results, totalWeight)
+	return binary.BigEndian.AppendUint64(results, pChainHeight)
+}
+
+func setupBlockContext(results []byte) func(*contract.MockBlockContext) {
+	return func(mbc *contract.MockBlockContext) {
+		mbc.EXPECT().Timestamp().Return(testTimestamp).AnyTimes()
+		mbc.EXPECT().GetPredicateResults(common.Hash{}, ContractAddress).Return(results).AnyTimes()
+	}
+}
+
+func TestGovernanceRun(t *testing.T) {
+	castVoteInput := func(t testing.TB) []byte {
+		input, err := PackCastVote(0)
+		require.NoError(t, err)
+		return input
+	}
+	yesVote := &Vote{NodeID: testNodeID, ProposalID: 1, PChainHeight: testPChainHeight, Support: true}
+	noVote := &Vote{NodeID: testNodeID, ProposalID: 1, PChainHeight: testPChainHeight, Support: false}
+	unknownProposalVote := &Vote{NodeID: testNodeID, ProposalID: 2, PChainHeight: testPChainHeight, Support: true}
+	otherHeightVote := &Vote{NodeID: testNodeID, ProposalID: 1, PChainHeight: testPChainHeight + 1, Support: true}
+
+	tests := map[string]testutils.PrecompileTest{
+		"create proposal from no role fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotCreateProposal.Error(),
+		},
+		"create proposal from enabled succeeds": {
+			Caller:            allowlist.TestEnabledAddr,
+			BeforeHook:        setSnapshot,
+			SetupBlockContext: setupBlockContext(voteResults(0, 60, 12)),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost,
+			ReadOnly:    false,
+			ExpectedRes: func() []byte {
+				res, err := PackCreateProposalOutput(big.NewInt(2))
+				require.NoError(t, err)
+				return res
+			}(),
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				proposal, ok := GetProposal(stateDB, 2)
+				require.True(t, ok)
+				require.Equal(t, Proposal{
+					PayloadHash:  testPayloadHash,
+					Deadline:     testTimestamp + DefaultVotingPeriod,
+					TotalWeight:  60,
+					PChainHeight: 12,
+				}, proposal)
+			},
+		},
+		"create proposal without snapshot fails": {
+			Caller:            allowlist.TestEnabledAddr,
+			BeforeHook:        setProposal,
+			SetupBlockContext: setupBlockContext(nil),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMissingSnapshot.Error(),
+		},
+		"create proposal with unverified snapshot fails": {
+			Caller:            allowlist.TestEnabledAddr,
+			BeforeHook:        setSnapshot,
+			SetupBlockContext: setupBlockContext(voteResults(0, 0, 0)),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMissingSnapshot.Error(),
+		},
+		"create proposal with vote instead of snapshot fails": {
+			Caller:            allowlist.TestEnabledAddr,
+			BeforeHook:        setVote(yesVote),
+			SetupBlockContext: setupBlockContext(voteResults(70, 100, testPChainHeight)),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMissingSnapshot.Error(),
+		},
+		"create proposal readOnly": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"create proposal insufficient gas": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCreateProposal(testPayloadHash)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CreateProposalGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"cast vote in favor": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        setVote(yesVote),
+			SetupBlockContext: setupBlockContext(voteResults(70, 200, testPChainHeight)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				proposal, ok := GetProposal(stateDB, 1)
+				require.True(t, ok)
+				require.Equal(t, uint64(70), proposal.YesWeight)
+				// The total weight is that of the snapshot, not of the set the vote was verified against.
+				require.Equal(t, uint64(100), proposal.TotalWeight)
+				require.True(t, ProposalPassed(stateDB, 1))
+			},
+		},
+		"cast vote against": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        setVote(noVote),
+			SetupBlockContext: setupBlockContext(voteResults(70, 100, testPChainHeight)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				proposal, ok := GetProposal(stateDB, 1)
+				require.True(t, ok)
+				require.Equal(t, uint64(70), proposal.NoWeight)
+				require.False(t, ProposalPassed(stateDB, 1))
+			},
+		},
+		"cast vote twice fails": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+				setVote(yesVote)(t, stateDB)
+				stateDB.SetState(ContractAddress, votedKey(yesVote), common.BigToHash(common.Big1))
+			},
+			SetupBlockContext: setupBlockContext(voteResults(70, 100, testPChainHeight)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrAlreadyVoted.Error(),
+		},
+		"cast unverified vote fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        setVote(yesVote),
+			SetupBlockContext: setupBlockContext(voteResults(0, 0, 0)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrInvalidVote.Error(),
+		},
+		"cast missing vote fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        setProposal,
+			SetupBlockContext: setupBlockContext(nil),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrInvalidVote.Error(),
+		},
+		"cast vote on unknown proposal fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        setVote(unknownProposalVote),
+			SetupBlockContext: setupBlockContext(voteResults(70, 100, testPChainHeight)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrUnknownProposal.Error(),
+		},
+		"cast vote on other validator set fails": {
+			Caller:            allowlist.TestNoRoleAddr,
+			BeforeHook:        setVote(otherHeightVote),
+			SetupBlockContext: setupBlockContext(voteResults(70, 100, testPChainHeight+1)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrInvalidVote.Error(),
+		},
+		"cast vote after deadline fails": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+				setVote(yesVote)(t, stateDB)
+				setUint64(stateDB, proposalKey(1, deadlineField), testTimestamp-1)
+			},
+			SetupBlockContext: setupBlockContext(voteResults(70, 100, testPChainHeight)),
+			InputFn:           castVoteInput,
+			SuppliedGas:       CastVoteGasCost,
+			ReadOnly:          false,
+			ExpectedErr:       ErrVotingClosed.Error(),
+		},
+		"cast vote readOnly": {
+			Caller:      allowlist.TestNoRoleAddr,
+			BeforeHook:  setVote(yesVote),
+			InputFn:     castVoteInput,
+			SuppliedGas: CastVoteGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"get proposal": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: setProposal,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetProposal(common.Big1)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: GetProposalGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetProposalOutput(Proposal{
+					PayloadHash:  testPayloadHash,
+					Deadline:     testDeadline,
+					TotalWeight:  100,
+					PChainHeight: testPChainHeight,
+				})
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+		"get unknown proposal fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: setProposal,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetProposal(common.Big2)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: GetProposalGasCost,
+			ReadOnly:    true,
+			ExpectedErr: ErrUnknownProposal.Error(),
+		},
+		"proposal without votes has not passed": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: setProposal,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackProposalPassed(common.Big1)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ProposalPassedGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackProposalPassedOutput(false)
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+	}
+
+	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
+}
@@ -0,0 +1,261 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package governance
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Validators vote on proposals by signing a Vote with their BLS key. Votes are included in
+// the access list of the transaction calling castVote as predicates of the governance
+// precompile, so that the signature and the stake of the voter are verified during block
+// verification. The weight of each vote is recorded in the predicate results of the
+// transaction, where castVote reads it.
+//
+// Each proposal snapshots the validator set when it is created: the transaction calling
+// createProposal includes an empty governance predicate, whose result records the ProposerVM
+// P-Chain height and the total weight of the validator set at that height. Votes are signed
+// over the P-Chain height of the snapshot of their proposal and verified against the
+// validator set at that height, so that every vote is weighed against the same validator set.
+
+const (
+	GasCostPerVoteVerification uint64 = 200_000
+	GasCostPerVoteBytes        uint64 = 100
+
+	voteLen         = len(ids.NodeID{}) + 2*wrappers.LongLen + wrappers.BoolLen + bls.SignatureLen
+	voteResultLen   = 3 * wrappers.LongLen // weight of the voter, total weight and P-Chain height
+	unsignedVoteLen = len(ids.ID{}) + 2*wrappers.LongLen + wrappers.BoolLen
+)
+
+var (
+	errInvalidPredicateBytes = errors.New("cannot unpack predicate bytes")
+	errInvalidVote           = errors.New("cannot unpack vote")
+)
+
+// Vote is a vote of the validator [NodeID] in favor of, or against, the proposal [ProposalID],
+// whose validator set was snapshotted at [PChainHeight].
+type Vote struct {
+	NodeID       ids.NodeID
+	ProposalID   uint64
+	PChainHeight uint64
+	Support      bool
+	Signature    [bls.SignatureLen]byte
+}
+
+// NewVote returns a vote of [nodeID] on [proposalID] of the chain [chainID] with the validator
+// set snapshotted at [pChainHeight], signed with [sk].
+func NewVote(sk *bls.SecretKey, chainID ids.ID, nodeID ids.NodeID, proposalID uint64, pChainHeight uint64, support bool) *Vote {
+	vote := &Vote{
+		NodeID:       nodeID,
+		ProposalID:   proposalID,
+		PChainHeight: pChainHeight,
+		Support:      support,
+	}
+	copy(vote.Signature[:], bls.SignatureToBytes(bls.Sign(sk, vote.UnsignedBytes(chainID))))
+	return vote
+}
+
+// ParseVote parses [b] as a Vote.
+func ParseVote(b []byte) (*Vote, error) {
+	if len(b) != voteLen {
+		return nil, fmt.Errorf("%w: expected length %d, got %d", errInvalidVote, voteLen, len(b))
+	}
+	vote := &Vote{}
+	offset := copy(vote.NodeID[:], b)
+	vote.ProposalID = binary.BigEndian.Uint64(b[offset:])
+	offset += wrappers.LongLen
+	vote.PChainHeight = binary.BigEndian.Uint64(b[offset:])
+	offset += wrappers.LongLen
+	switch b[offset] {
+	case 0:
+	case 1:
+		vote.Support = true
+	default:
+		return nil, fmt.Errorf("%w: invalid support byte %d", errInvalidVote, b[offset])
+	}
+	offset += wrappers.BoolLen
+	copy(vote.Signature[:], b[offset:])
+	return vote, nil
+}
+
+// Bytes returns the encoding of [v].
+func (v *Vote) Bytes() []byte {
+	b := make([]byte, 0, voteLen)
+	b = append(b, v.NodeID[:]...)
+	b = binary.BigEndian.AppendUint64(b, v.ProposalID)
+	b = binary.BigEndian.AppendUint64(b, v.PChainHeight)
+	b = append(b, boolByte(v.Support))
+	return append(b, v.Signature[:]...)
+}
+
+// UnsignedBytes returns the bytes signed by the voter for a vote on the chain [chainID].
+func (v *Vote) UnsignedBytes(chainID ids.ID) []byte {
+	b := make([]byte, 0, unsignedVoteLen)
+	b = append(b, chainID[:]...)
+	b = binary.BigEndian.AppendUint64(b, v.ProposalID)
+	b = binary.BigEndian.AppendUint64(b, v.PChainHeight)
+	return append(b, boolByte(v.Support))
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// PredicateGas returns the amount of gas necessary to verify the vote, or the validator set
+// snapshot, encoded in [predicateBytes].
+func (c *Config) PredicateGas(predicateBytes []byte) (uint64, error) {
+	bytesGasCost, overflow := math.SafeMul(GasCostPerVoteBytes, uint64(len(predicateBytes)))
+	if overflow {
+		return 0, fmt.Errorf("overflow calculating gas cost for vote bytes of size %d", len(predicateBytes))
+	}
+	totalGas, overflow := math.SafeAdd(GasCostPerVoteVerification, bytesGasCost)
+	if overflow {
+		return 0, fmt.Errorf("overflow adding bytes gas cost of size %d", len(predicateBytes))
+	}
+	unpackedPredicateBytes, err := predicateutils.UnpackPredicate(predicateBytes)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", errInvalidPredicateBytes, err)
+	}
+	if len(unpackedPredicateBytes) == 0 {
+		// An empty predicate requests a snapshot of the validator set.
+		return totalGas, nil
+	}
+	if _, err := ParseVote(unpackedPredicateBytes); err != nil {
+		return 0, err
+	}
+	return totalGas, nil
+}
+
+// VerifyPredicate verifies the votes in [predicates] against the validator set of the subnet
+// at the P-Chain height of their proposal snapshot, which must not be above the ProposerVM
+// P-Chain height of [predicateContext]. For each vote, the result holds the weight of the
+// voter, the total weight of the validator set and the P-Chain height, with a weight of zero
+// if the vote is invalid. Empty predicates request a snapshot of the validator set at the
+// ProposerVM P-Chain height, and their result holds a weight of zero.
+func (c *Config) VerifyPredicate(predicateContext *precompileconfig.PredicateContext, predicates [][]byte) []byte {
+	results := make([]byte, len(predicates)*voteResultLen)
+	if len(predicates) == 0 {
+		return results
+	}
+
+	snowCtx := predicateContext.SnowCtx
+	pChainHeight := predicateContext.ProposerVMBlockCtx.PChainHeight
+	validatorSets := make(map[uint64]*weightedValidatorSet)
+	getValidatorSet := func(height uint64) *weightedValidatorSet {
+		if validatorSet, ok := validatorSets[height]; ok {
+			return validatorSet
+		}
+		validatorSet, err := getWeightedValidatorSet(snowCtx, height)
+		if err != nil {
+			log.Debug("failed to get validator set to verify votes", "pChainHeight", height, "err", err)
+		}
+		validatorSets[height] = validatorSet
+		return validatorSet
+	}
+
+	for i, predicateBytes := range predicates {
+		unpackedPredicateBytes, err := predicateutils.UnpackPredicate(predicateBytes)
+		if err != nil {
+			continue
+		}
+		var (
+			vote   *Vote
+			height = pChainHeight
+		)
+		if len(unpackedPredicateBytes) != 0 {
+			if vote, err = ParseVote(unpackedPredicateBytes); err != nil {
+				continue
+			}
+			// Validator sets above the ProposerVM P-Chain height may not be known by every
+			// validator, so votes on them cannot be verified.
+			if vote.PChainHeight > pChainHeight {
+				log.Debug("vote on validator set above P-Chain height", "nodeID", vote.NodeID, "proposalID", vote.ProposalID, "votePChainHeight", vote.PChainHeight, "pChainHeight", pChainHeight)
+				continue
+			}
+			height = vote.PChainHeight
+		}
+		validatorSet := getValidatorSet(height)
+		if validatorSet == nil {
+			continue
+		}
+		var weight uint64
+		if vote != nil {
+			if weight = verifyVote(snowCtx.ChainID, validatorSet.validators, vote); weight == 0 {
+				continue
+			}
+		}
+		offset := i * voteResultLen
+		binary.BigEndian.PutUint64(results[offset:], weight)
+		binary.BigEndian.PutUint64(results[offset+wrappers.LongLen:], validatorSet.totalWeight)
+		binary.BigEndian.PutUint64(results[offset+2*wrappers.LongLen:], height)
+	}
+	return results
+}
+
+// weightedValidatorSet is a validator set of the subnet and its total weight.
+type weightedValidatorSet struct {
+	validators  map[ids.NodeID]*validators.GetValidatorOutput
+	totalWeight uint64
+}
+
+// getWeightedValidatorSet returns the validator set of the subnet of [snowCtx] at [height].
+func getWeightedValidatorSet(snowCtx *snow.Context, height uint64) (*weightedValidatorSet, error) {
+	validatorSet, err := snowCtx.ValidatorState.GetValidatorSet(context.Background(), height, snowCtx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	var totalWeight uint64
+	for _, validator := range validatorSet {
+		var overflow bool
+		totalWeight, overflow = math.SafeAdd(totalWeight, validator.Weight)
+		if overflow {
+			return nil, errors.New("overflow summing validator weight")
+		}
+	}
+	return &weightedValidatorSet{validators: validatorSet, totalWeight: totalWeight}, nil
+}
+
+// verifyVote returns the weight of the voter of [vote] if it is a validator in [validatorSet]
+// and signed the vote, and zero otherwise.
+func verifyVote(chainID ids.ID, validatorSet map[ids.NodeID]*validators.GetValidatorOutput, vote *Vote) uint64 {
+	validator, ok := validatorSet[vote.NodeID]
+	if !ok || validator.PublicKey == nil {
+		log.Debug("vote from non-validator", "nodeID", vote.NodeID, "proposalID", vote.ProposalID)
+		return 0
+	}
+	sig, err := bls.SignatureFromBytes(vote.Signature[:])
+	if err != nil || !bls.Verify(validator.PublicKey, sig, vote.UnsignedBytes(chainID)) {
+		log.Debug("invalid vote signature", "nodeID", vote.NodeID, "proposalID", vote.ProposalID)
+		return 0
+	}
+	return validator.Weight
+}
+
+// voteResult returns the weight of the voter, the total weight of the validator set and the
+// P-Chain height of the validator set recorded for the predicate at [index] in [results].
+// Returns a total weight of zero if the predicate is invalid, and a weight of zero if it is
+// not a valid vote.
+func voteResult(results []byte, index int) (weight uint64, totalWeight uint64, pChainHeight uint64) {
+	offset := index * voteResultLen
+	if offset < 0 || len(results) < offset+voteResultLen {
+		return 0, 0, 0
+	}
+	return binary.BigEndian.Uint64(results[offset:]), binary.BigEndian.Uint64(results[offset+wrappers.LongLen:]), binary.BigEndian.Uint64(results[offset+2*wrappers.LongLen:])
+}
@@ -0,0 +1,109 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package governance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteBytes(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	vote := NewVote(sk, ids.GenerateTestID(), ids.GenerateTestNodeID(), 5, 10, true)
+
+	parsed, err := ParseVote(vote.Bytes())
+	require.NoError(err)
+	require.Equal(vote, parsed)
+
+	_, err = ParseVote(vote.Bytes()[1:])
+	require.ErrorIs(err, errInvalidVote)
+}
+
+func TestVerifyPredicate(t *testing.T) {
+	require := require.New(t)
+
+	snowCtx := snow.DefaultContextTest()
+	validatorSet := make(map[ids.NodeID]*validators.GetValidatorOutput)
+	secretKeys := make(map[ids.NodeID]*bls.SecretKey)
+	for _, weight := range []uint64{10, 20, 30} {
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+		nodeID := ids.GenerateTestNodeID()
+		validatorSet[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: bls.PublicFromSecretKey(sk),
+			Weight:    weight,
+		}
+		secretKeys[nodeID] = sk
+	}
+	snowCtx.ValidatorState = &validators.TestState{
+		GetValidatorSetF: func(_ context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			require.LessOrEqual(height, uint64(10))
+			require.Equal(snowCtx.SubnetID, subnetID)
+			return validatorSet, nil
+		},
+	}
+	predicateContext := &precompileconfig.PredicateContext{
+		SnowCtx:            snowCtx,
+		ProposerVMBlockCtx: &block.Context{PChainHeight: 10},
+	}
+
+	var (
+		validNodeID ids.NodeID
+		validVote   *Vote
+	)
+	for nodeID, sk := range secretKeys {
+		validNodeID = nodeID
+		validVote = NewVote(sk, snowCtx.ChainID, nodeID, 1, 10, true)
+		break
+	}
+	nonValidatorSK, err := bls.NewSecretKey()
+	require.NoError(err)
+	nonValidatorVote := NewVote(nonValidatorSK, snowCtx.ChainID, ids.GenerateTestNodeID(), 1, 10, true)
+	otherChainVote := NewVote(secretKeys[validNodeID], ids.GenerateTestID(), validNodeID, 1, 10, true)
+	futureVote := NewVote(secretKeys[validNodeID], snowCtx.ChainID, validNodeID, 1, 11, true)
+
+	config := NewConfig(nil, nil, nil, nil, 0, 0)
+	predicates := [][]byte{
+		predicateutils.PackPredicate(nil),
+		predicateutils.PackPredicate(validVote.Bytes()),
+		predicateutils.PackPredicate(nonValidatorVote.Bytes()),
+		predicateutils.PackPredicate(otherChainVote.Bytes()),
+		predicateutils.PackPredicate(futureVote.Bytes()),
+	}
+	for _, predicate := range predicates {
+		_, err := config.PredicateGas(predicate)
+		require.NoError(err)
+	}
+
+	results := config.VerifyPredicate(predicateContext, predicates)
+	// The snapshot request holds the validator set at the ProposerVM P-Chain height.
+	weight, totalWeight, pChainHeight := voteResult(results, 0)
+	require.Zero(weight)
+	require.Equal(uint64(60), totalWeight)
+	require.Equal(uint64(10), pChainHeight)
+
+	weight, totalWeight, pChainHeight = voteResult(results, 1)
+	require.Equal(validatorSet[validNodeID].Weight, weight)
+	require.Equal(uint64(60), totalWeight)
+	require.Equal(uint64(10), pChainHeight)
+	for i := 2; i < len(predicates); i++ {
+		weight, _, _ := voteResult(results, i)
+		require.Zero(weight)
+	}
+	weight, _, _ = voteResult(results, len(predicates))
+	require.Zero(weight)
+}
@@ -0,0 +1,88 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nativedistributor
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var _ precompileconfig.Config = &Config{}
+
+var errNativeDistributorCannotBeActivated = errors.New("native distributor cannot be activated before DUpgrade")
+
+// Config implements the precompileconfig.Config interface while adding in the
+// native distributor specific precompile config.
+type Config struct {
+	allowlist.AllowListConfig
+	precompileconfig.Upgrade
+	// MaxDistributionPerCall is the maximum total amount a single call of distribute sends
+	// (nil denotes zero, so that nothing can be distributed).
+	MaxDistributionPerCall *math.HexOrDecimal256 `json:"maxDistributionPerCall,omitempty"`
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the native distributor with the given [admins], [enableds] and [managers] as members
+// of the allowlist, distributing at most [maxDistributionPerCall] per call.
+func NewConfig(blockTimestamp *uint64, admins []common.Address, enableds []common.Address, managers []common.Address, maxDistributionPerCall *big.Int) *Config {
+	return &Config{
+		AllowListConfig: allowlist.AllowListConfig{
+			AdminAddresses:   admins,
+			EnabledAddresses: enableds,
+			ManagerAddresses: managers,
+		},
+		Upgrade:                precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		MaxDistributionPerCall: (*math.HexOrDecimal256)(maxDistributionPerCall),
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the native distributor.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the native distributor precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	if c.MaxDistributionPerCall != nil && (*big.Int)(c.MaxDistributionPerCall).Sign() < 0 {
+		return fmt.Errorf("max distribution per call cannot be negative (got %v)", (*big.Int)(c.MaxDistributionPerCall))
+	}
+	if err := c.AllowListConfig.Verify(chainConfig, c.Upgrade); err != nil {
+		return err
+	}
+	// Deposits are paid to a payable function, see [contract.NewPayableStatefulPrecompileFunction].
+	if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errNativeDistributorCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	if !utils.BigNumEqual((*big.Int)(c.MaxDistributionPerCall), (*big.Int)(other.MaxDistributionPerCall)) {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) && c.AllowListConfig.Equal(&other.AllowListConfig)
+}
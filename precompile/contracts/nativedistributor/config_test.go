@@ -0,0 +1,75 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nativedistributor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerify(t *testing.T) {
+	tests := map[string]testutils.ConfigVerifyTest{
+		"valid config with max distribution": {
+			Config: NewConfig(utils.NewUint64(3), nil, nil, nil, big.NewInt(1000)),
+		},
+		"valid config without max distribution": {
+			Config: NewConfig(utils.NewUint64(3), nil, nil, nil, nil),
+		},
+		"negative max distribution": {
+			Config:        NewConfig(utils.NewUint64(3), nil, nil, nil, big.NewInt(-1)),
+			ExpectedError: "max distribution per call cannot be negative",
+		},
+		"cannot be activated before DUpgrade": {
+			Config: NewConfig(utils.NewUint64(3), nil, nil, nil, big.NewInt(1000)),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errNativeDistributorCannotBeActivated.Error(),
+		},
+	}
+	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
+}
+
+func TestEqual(t *testing.T) {
+	admins := []common.Address{allowlist.TestAdminAddr}
+	enableds := []common.Address{allowlist.TestEnabledAddr}
+	managers := []common.Address{allowlist.TestManagerAddr}
+	tests := map[string]testutils.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, big.NewInt(1000)),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(nil, nil, nil, nil, nil),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different timestamp": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, big.NewInt(1000)),
+			Other:    NewConfig(utils.NewUint64(4), admins, enableds, managers, big.NewInt(1000)),
+			Expected: false,
+		},
+		"different max distribution": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, big.NewInt(1000)),
+			Other:    NewConfig(utils.NewUint64(3), admins, enableds, managers, big.NewInt(2000)),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), admins, enableds, managers, big.NewInt(1000)),
+			Other:    NewConfig(utils.NewUint64(3), admins, enableds, managers, big.NewInt(1000)),
+			Expected: true,
+		},
+	}
+	allowlist.EqualPrecompileWithAllowListTests(t, Module, tests)
+}
@@ -0,0 +1,224 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nativedistributor
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The native distributor sends native tokens from its balance to many addresses in a single
+// call, so that distributions after launch do not require a transfer per recipient signed with
+// the treasury key. Anyone can fund the balance with deposit, or it can be allocated to the
+// precompile address in the genesis. Enabled addresses on the allow list can distribute, and
+// each call is bounded to MaxRecipients recipients and the configured maximum total amount.
+//
+// Disabling the precompile deletes its account, burning any balance left.
+
+const (
+	// MaxRecipients is the maximum number of recipients of a single call of distribute.
+	MaxRecipients = 256
+
+	MaxDistributionPerCallGasCost uint64 = contract.ReadGasCostPerSlot
+
+	DepositGasCost    uint64 = contract.LogGas + 2*contract.LogTopicGas + common.HashLength*contract.LogDataGasPerByte // Deposit log
+	DistributeGasCost uint64 = contract.ReadGasCostPerSlot + contract.ReadGasCostPerSlot + distributedEventGasCost     // read role and maximum distribution + Distributed log
+	// DistributeGasCostPerRecipient is charged for each recipient as the CALL opcode charges
+	// to transfer value, plus params.CallNewAccountGas if the recipient does not exist.
+	DistributeGasCostPerRecipient uint64 = params.CallValueTransferGas
+
+	distributedEventGasCost = contract.LogGas + 2*contract.LogTopicGas + 2*common.HashLength*contract.LogDataGasPerByte
+)
+
+var (
+	ErrCannotDistribute       = errors.New("non-enabled cannot distribute")
+	ErrLengthMismatch         = errors.New("recipients and amounts have different lengths")
+	ErrTooManyRecipients      = errors.New("too many recipients")
+	ErrExceedsMaxDistribution = errors.New("distribution exceeds maximum per call")
+	ErrInsufficientBalance    = errors.New("insufficient distributor balance")
+
+	// NativeDistributorRawABI contains the raw ABI of NativeDistributor contract.
+	//go:embed contract.abi
+	NativeDistributorRawABI string
+
+	NativeDistributorABI        = contract.ParseABI(NativeDistributorRawABI)
+	NativeDistributorPrecompile = createNativeDistributorPrecompile()
+
+	maxDistributionPerCallKey = common.Hash{'n', 'd', 'm'}
+)
+
+// GetNativeDistributorStatus returns the role of [address] for the native distributor
+// allow list.
+func GetNativeDistributorStatus(stateDB contract.StateDB, address common.Address) allowlist.Role {
+	return allowlist.GetAllowListStatus(stateDB, ContractAddress, address)
+}
+
+// SetNativeDistributorStatus sets the permissions of [address] to [role] for the native
+// distributor allow list. Assumes [role] has already been verified as valid.
+func SetNativeDistributorStatus(stateDB contract.StateDB, address common.Address, role allowlist.Role) {
+	allowlist.SetAllowListRole(stateDB, ContractAddress, address, role)
+}
+
+// GetMaxDistributionPerCall returns the maximum total amount a single call of distribute sends.
+func GetMaxDistributionPerCall(stateDB contract.StateDB) *big.Int {
+	return stateDB.GetState(ContractAddress, maxDistributionPerCallKey).Big()
+}
+
+// SetMaxDistributionPerCall sets the maximum total amount a single call of distribute sends.
+func SetMaxDistributionPerCall(stateDB contract.StateDB, amount *big.Int) {
+	stateDB.SetState(ContractAddress, maxDistributionPerCallKey, common.BigToHash(amount))
+}
+
+func emitEvent(accessibleState contract.AccessibleState, name string, args ...interface{}) error {
+	topics, data, err := NativeDistributorABI.PackEvent(name, args...)
+	if err != nil {
+		return err
+	}
+	accessibleState.GetStateDB().AddLog(ContractAddress, topics, data, accessibleState.GetBlockContext().Number().Uint64())
+	return nil
+}
+
+// PackMaxDistributionPerCall packs the input for maxDistributionPerCall, including the selector.
+func PackMaxDistributionPerCall() ([]byte, error) {
+	return NativeDistributorABI.Pack("maxDistributionPerCall")
+}
+
+// PackMaxDistributionPerCallOutput packs [amount] into the output of maxDistributionPerCall.
+func PackMaxDistributionPerCallOutput(amount *big.Int) ([]byte, error) {
+	return NativeDistributorABI.PackOutput("maxDistributionPerCall", amount)
+}
+
+func maxDistributionPerCall(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, MaxDistributionPerCallGasCost); err != nil {
+		return nil, 0, err
+	}
+	output, err := PackMaxDistributionPerCallOutput(GetMaxDistributionPerCall(accessibleState.GetStateDB()))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
+
+// PackDeposit packs the input for deposit, including the selector.
+func PackDeposit() ([]byte, error) {
+	return NativeDistributorABI.Pack("deposit")
+}
+
+// deposit adds the value sent to the balance of the distributor. The value is credited to
+// the precompile address before the precompile runs, so only the log remains to be emitted.
+func deposit(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DepositGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	if err := emitEvent(accessibleState, "Deposit", caller, contract.CallValue(accessibleState)); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackDistribute packs [recipients] and [amounts] into the input for distribute, including
+// the selector.
+func PackDistribute(recipients []common.Address, amounts []*big.Int) ([]byte, error) {
+	return NativeDistributorABI.Pack("distribute", recipients, amounts)
+}
+
+// distribute sends each input amount from the balance of the distributor to the input
+// recipient at the same index. The caller must be enabled on the allow list, and the total
+// must not exceed the maximum distribution per call.
+func distribute(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DistributeGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := NativeDistributorABI.UnpackInput("distribute", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	recipients := *abi.ConvertType(res[0], new([]common.Address)).(*[]common.Address)
+	amounts := *abi.ConvertType(res[1], new([]*big.Int)).(*[]*big.Int)
+
+	stateDB := accessibleState.GetStateDB()
+	if !GetNativeDistributorStatus(stateDB, caller).IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotDistribute, caller)
+	}
+	if len(recipients) != len(amounts) {
+		return nil, remainingGas, fmt.Errorf("%w: %d recipients, %d amounts", ErrLengthMismatch, len(recipients), len(amounts))
+	}
+	if len(recipients) > MaxRecipients {
+		return nil, remainingGas, fmt.Errorf("%w: %d exceeds %d", ErrTooManyRecipients, len(recipients), MaxRecipients)
+	}
+
+	total := new(big.Int)
+	for _, amount := range amounts {
+		total.Add(total, amount)
+	}
+	if maxDistribution := GetMaxDistributionPerCall(stateDB); total.Cmp(maxDistribution) > 0 {
+		return nil, remainingGas, fmt.Errorf("%w: %s exceeds %s", ErrExceedsMaxDistribution, total, maxDistribution)
+	}
+	if balance := stateDB.GetBalance(ContractAddress); total.Cmp(balance) > 0 {
+		return nil, remainingGas, fmt.Errorf("%w: has %s, need %s", ErrInsufficientBalance, balance, total)
+	}
+
+	for i, recipient := range recipients {
+		gasCost := DistributeGasCostPerRecipient
+		if amounts[i].Sign() != 0 && !stateDB.Exist(recipient) {
+			gasCost += params.CallNewAccountGas
+		}
+		if remainingGas, err = contract.DeductGas(remainingGas, gasCost); err != nil {
+			return nil, 0, err
+		}
+		if err := contract.TransferBalance(stateDB, ContractAddress, recipient, amounts[i]); err != nil {
+			return nil, remainingGas, err
+		}
+	}
+	if err := emitEvent(accessibleState, "Distributed", caller, big.NewInt(int64(len(recipients))), total); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// createNativeDistributorPrecompile returns a StatefulPrecompiledContract with deposit and
+// distribute functions for the balance of the distributor. Access to distribute is controlled
+// by an allow list for ContractAddress.
+func createNativeDistributorPrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	functions = append(functions, allowlist.CreateAllowListFunctions(ContractAddress)...)
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"deposit":                deposit,
+		"distribute":             distribute,
+		"maxDistributionPerCall": maxDistributionPerCall,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := NativeDistributorABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		if method.IsPayable() {
+			functions = append(functions, contract.NewPayableStatefulPrecompileFunction(method.ID, function))
+		} else {
+			functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+		}
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
@@ -0,0 +1,229 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nativedistributor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testRecipients      = []common.Address{{'r', '1'}, {'r', '2'}}
+	testAmounts         = []*big.Int{big.NewInt(100), big.NewInt(200)}
+	testBalance         = big.NewInt(1000)
+	testMaxDistribution = big.NewInt(500)
+
+	// distributeGasCost is the gas of distributing [testAmounts] to [testRecipients],
+	// neither of which exists.
+	distributeGasCost = DistributeGasCost + 2*(DistributeGasCostPerRecipient+params.CallNewAccountGas)
+)
+
+func fundDistributor(t testing.TB, state contract.StateDB) {
+	allowlist.SetDefaultRoles(Module.Address)(t, state)
+	state.AddBalance(ContractAddress, testBalance)
+	SetMaxDistributionPerCall(state, testMaxDistribution)
+}
+
+func mustPackDistribute(t testing.TB, recipients []common.Address, amounts []*big.Int) []byte {
+	input, err := PackDistribute(recipients, amounts)
+	require.NoError(t, err)
+	return input
+}
+
+var tests = map[string]testutils.PrecompileTest{
+	"enabled distribute": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts)
+		},
+		SuppliedGas: distributeGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, big.NewInt(700), state.GetBalance(ContractAddress))
+			require.Equal(t, testAmounts[0], state.GetBalance(testRecipients[0]))
+			require.Equal(t, testAmounts[1], state.GetBalance(testRecipients[1]))
+		},
+	},
+	"enabled distribute to existing recipients": {
+		Caller: allowlist.TestEnabledAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			fundDistributor(t, state)
+			for _, recipient := range testRecipients {
+				state.CreateAccount(recipient)
+			}
+		},
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts)
+		},
+		SuppliedGas: DistributeGasCost + 2*DistributeGasCostPerRecipient,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, testAmounts[0], state.GetBalance(testRecipients[0]))
+			require.Equal(t, testAmounts[1], state.GetBalance(testRecipients[1]))
+		},
+	},
+	"no role cannot distribute": {
+		Caller:     allowlist.TestNoRoleAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts)
+		},
+		SuppliedGas: DistributeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrCannotDistribute.Error(),
+	},
+	"readOnly distribute fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts)
+		},
+		SuppliedGas: DistributeGasCost,
+		ReadOnly:    true,
+		ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+	},
+	"insufficient gas distribute fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts)
+		},
+		SuppliedGas: distributeGasCost - 1,
+		ReadOnly:    false,
+		ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+	},
+	"distribute length mismatch fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts[:1])
+		},
+		SuppliedGas: DistributeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrLengthMismatch.Error(),
+	},
+	"distribute too many recipients fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			recipients := make([]common.Address, MaxRecipients+1)
+			amounts := make([]*big.Int, MaxRecipients+1)
+			for i := range amounts {
+				amounts[i] = new(big.Int)
+			}
+			return mustPackDistribute(t, recipients, amounts)
+		},
+		SuppliedGas: DistributeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrTooManyRecipients.Error(),
+	},
+	"distribute exceeding max distribution fails": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, []*big.Int{big.NewInt(300), big.NewInt(300)})
+		},
+		SuppliedGas: DistributeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrExceedsMaxDistribution.Error(),
+	},
+	"distribute exceeding balance fails": {
+		Caller: allowlist.TestEnabledAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, state)
+			state.AddBalance(ContractAddress, big.NewInt(250))
+			SetMaxDistributionPerCall(state, testMaxDistribution)
+		},
+		InputFn: func(t testing.TB) []byte {
+			return mustPackDistribute(t, testRecipients, testAmounts)
+		},
+		SuppliedGas: DistributeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrInsufficientBalance.Error(),
+	},
+	"read max distribution per call": {
+		Caller:     allowlist.TestNoRoleAddr,
+		BeforeHook: fundDistributor,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackMaxDistributionPerCall()
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: MaxDistributionPerCallGasCost,
+		ReadOnly:    true,
+		ExpectedRes: func() []byte {
+			output, err := PackMaxDistributionPerCallOutput(testMaxDistribution)
+			if err != nil {
+				panic(err)
+			}
+			return output
+		}(),
+	},
+	"configure max distribution per call": {
+		Caller:      allowlist.TestNoRoleAddr,
+		Config:      NewConfig(utils.NewUint64(0), nil, nil, nil, testMaxDistribution),
+		Input:       allowlist.PackReadAllowList(allowlist.TestNoRoleAddr),
+		SuppliedGas: allowlist.ReadAllowListGasCost,
+		ReadOnly:    true,
+		ExpectedRes: common.Hash(allowlist.NoRole).Bytes(),
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, testMaxDistribution, GetMaxDistributionPerCall(state))
+		},
+	},
+}
+
+func TestNativeDistributorRun(t *testing.T) {
+	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
+}
+
+func BenchmarkNativeDistributor(b *testing.B) {
+	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
+}
+
+func TestNativeDistributorDeposit(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	stateDB := state.NewTestStateDB(t)
+	chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+	chainConfig.EXPECT().IsDUpgrade(gomock.Any()).Return(true).AnyTimes()
+	blockContext := contract.NewMockBlockContext(ctrl)
+	blockContext.EXPECT().Number().Return(common.Big1).AnyTimes()
+	blockContext.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
+	accessibleState := contract.NewMockAccessibleState(ctrl)
+	accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+	accessibleState.EXPECT().GetBlockContext().Return(blockContext).AnyTimes()
+	accessibleState.EXPECT().GetChainConfig().Return(chainConfig).AnyTimes()
+
+	// The EVM credits the value to the precompile before running a payable function.
+	caller := allowlist.TestNoRoleAddr
+	stateDB.AddBalance(ContractAddress, testBalance)
+
+	input, err := PackDeposit()
+	require.NoError(err)
+	_, remainingGas, err := NativeDistributorPrecompile.Run(&testutils.PayableAccessibleState{AccessibleState: accessibleState, Value: testBalance}, caller, ContractAddress, input, DepositGasCost, false)
+	require.NoError(err)
+	require.Zero(remainingGas)
+	require.Equal(testBalance, stateDB.GetBalance(ContractAddress))
+
+	logs := stateDB.(*state.StateDB).Logs()
+	require.Len(logs, 1)
+	require.Equal(NativeDistributorABI.Events["Deposit"].ID, logs[0].Topics[0])
+	require.Equal(common.BytesToHash(caller.Bytes()), logs[0].Topics[1])
+}
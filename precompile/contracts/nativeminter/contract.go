@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/subnet-evm/precompile/contract"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -21,6 +22,9 @@ const (
 	mintInputLen = common.HashLength + common.HashLength
 
 	MintGasCost = 30_000
+	// NativeCoinMintedEventGasCost is the additional cost of emitting the NativeCoinMinted
+	// log, charged from DUpgrade onwards.
+	NativeCoinMintedEventGasCost = contract.LogGas + 3*contract.LogTopicGas + common.HashLength*contract.LogDataGasPerByte
 )
 
 var (
@@ -29,6 +33,10 @@ var (
 
 	mintSignature = contract.CalculateFunctionSelector("mintNativeCoin(address,uint256)") // address, amount
 	ErrCannotMint = errors.New("non-enabled cannot mint")
+
+	// NativeCoinMintedEventID is the topic of logs emitted by mintNativeCoin from DUpgrade onwards:
+	// NativeCoinMinted(address indexed recipient, uint256 amount, address indexed minter)
+	NativeCoinMintedEventID = crypto.Keccak256Hash([]byte("NativeCoinMinted(address,uint256,address)"))
 )
 
 // GetContractNativeMinterStatus returns the role of [address] for the minter list.
@@ -66,6 +74,29 @@ func UnpackMintInput(input []byte) (common.Address, *big.Int, error) {
 	return to, assetAmount, nil
 }
 
+// PackNativeCoinMintedEvent packs the topics and data of the NativeCoinMinted log emitted
+// when [minter] mints [amount] to [recipient].
+func PackNativeCoinMintedEvent(recipient common.Address, amount *big.Int, minter common.Address) ([]common.Hash, []byte) {
+	topics := []common.Hash{
+		NativeCoinMintedEventID,
+		recipient.Hash(),
+		minter.Hash(),
+	}
+	return topics, common.BigToHash(amount).Bytes()
+}
+
+// UnpackNativeCoinMintedEvent attempts to unpack the recipient, amount and minter from
+// the [topics] and [data] of a NativeCoinMinted log.
+func UnpackNativeCoinMintedEvent(topics []common.Hash, data []byte) (common.Address, *big.Int, common.Address, error) {
+	if len(topics) != 3 || topics[0] != NativeCoinMintedEventID {
+		return common.Address{}, nil, common.Address{}, fmt.Errorf("invalid topics for NativeCoinMinted event: %v", topics)
+	}
+	if len(data) != common.HashLength {
+		return common.Address{}, nil, common.Address{}, fmt.Errorf("invalid data length for NativeCoinMinted event: %d", len(data))
+	}
+	return common.BytesToAddress(topics[1].Bytes()), new(big.Int).SetBytes(data), common.BytesToAddress(topics[2].Bytes()), nil
+}
+
 // mintNativeCoin checks if the caller is permissioned for minting operation.
 // The execution function parses the [input] into native coin amount and receiver address.
 func mintNativeCoin(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
@@ -89,12 +120,30 @@ func mintNativeCoin(accessibleState contract.AccessibleState, caller common.Addr
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotMint, caller)
 	}
 
+	// Emit a log so issuance can be tracked without tracing. Logs are part of the receipts
+	// root, so the log is only emitted from DUpgrade onwards.
+	emitEvent := accessibleState.GetChainConfig().IsDUpgrade(accessibleState.GetBlockContext().Timestamp())
+	if emitEvent {
+		if remainingGas, err = contract.DeductGas(remainingGas, NativeCoinMintedEventGasCost); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	// if there is no address in the state, create one.
 	if !stateDB.Exist(to) {
 		stateDB.CreateAccount(to)
 	}
 
 	stateDB.AddBalance(to, amount)
+	if emitEvent {
+		topics, data := PackNativeCoinMintedEvent(to, amount, caller)
+		stateDB.AddLog(
+			ContractAddress,
+			topics,
+			data,
+			accessibleState.GetBlockContext().Number().Uint64(),
+		)
+	}
 	// Return an empty output and the remaining gas
 	return []byte{}, remainingGas, nil
 }
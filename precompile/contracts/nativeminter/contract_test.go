@@ -4,16 +4,20 @@
 package nativeminter
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
 	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
 	"github.com/ava-labs/subnet-evm/precompile/testutils"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 var tests = map[string]testutils.PrecompileTest{
@@ -39,12 +43,34 @@ var tests = map[string]testutils.PrecompileTest{
 
 			return input
 		},
-		SuppliedGas: MintGasCost,
+		SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
 		ReadOnly:    false,
 		ExpectedRes: []byte{},
 		AfterHook: func(t testing.TB, state contract.StateDB) {
 			require.Equal(t, common.Big1, state.GetBalance(allowlist.TestEnabledAddr), "expected minted funds")
+
+			logs := state.(interface{ Logs() []*types.Log }).Logs()
+			require.Len(t, logs, 1)
+			require.Equal(t, Module.Address, logs[0].Address)
+			recipient, amount, minter, err := UnpackNativeCoinMintedEvent(logs[0].Topics, logs[0].Data)
+			require.NoError(t, err)
+			require.Equal(t, allowlist.TestEnabledAddr, recipient)
+			require.Equal(t, common.Big1, amount)
+			require.Equal(t, allowlist.TestEnabledAddr, minter)
+		},
+	},
+	"insufficient gas for mint event": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackMintInput(allowlist.TestEnabledAddr, common.Big1)
+			require.NoError(t, err)
+
+			return input
 		},
+		SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost - 1,
+		ReadOnly:    false,
+		ExpectedErr: vmerrs.ErrOutOfGas.Error(),
 	},
 	"initial mint funds": {
 		Caller:     allowlist.TestEnabledAddr,
@@ -67,7 +93,7 @@ var tests = map[string]testutils.PrecompileTest{
 
 			return input
 		},
-		SuppliedGas: MintGasCost,
+		SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
 		ReadOnly:    false,
 		ExpectedRes: []byte{},
 		AfterHook: func(t testing.TB, state contract.StateDB) {
@@ -83,7 +109,7 @@ var tests = map[string]testutils.PrecompileTest{
 
 			return input
 		},
-		SuppliedGas: MintGasCost,
+		SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
 		ReadOnly:    false,
 		ExpectedRes: []byte{},
 		AfterHook: func(t testing.TB, state contract.StateDB) {
@@ -99,7 +125,7 @@ var tests = map[string]testutils.PrecompileTest{
 
 			return input
 		},
-		SuppliedGas: MintGasCost,
+		SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
 		ReadOnly:    false,
 		ExpectedRes: []byte{},
 		AfterHook: func(t testing.TB, state contract.StateDB) {
@@ -164,6 +190,70 @@ func TestContractNativeMinterRun(t *testing.T) {
 	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
 }
 
+func TestContractNativeMinterBeforeDUpgrade(t *testing.T) {
+	test := testutils.PrecompileTest{
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		ChainConfig: func() precompileconfig.ChainConfig {
+			config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+			config.EXPECT().IsDUpgrade(gomock.Any()).Return(false).AnyTimes()
+			return config
+		}(),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackMintInput(allowlist.TestEnabledAddr, common.Big1)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: MintGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, common.Big1, state.GetBalance(allowlist.TestEnabledAddr), "expected minted funds")
+			require.Empty(t, state.GetLogData())
+		},
+	}
+	test.Run(t, Module, state.NewTestStateDB(t))
+}
+
+func TestContractNativeMinterState(t *testing.T) {
+	mintInput := func(addr common.Address, amount *big.Int) []byte {
+		input, err := PackMintInput(addr, amount)
+		require.NoError(t, err)
+		return input
+	}
+	enabledState := func(balance *big.Int) *testutils.TestState {
+		return &testutils.TestState{
+			Balances: map[common.Address]*big.Int{allowlist.TestNoRoleAddr: balance},
+			Storage: map[common.Address]map[common.Hash]common.Hash{
+				Module.Address: {allowlist.TestEnabledAddr.Hash(): common.Hash(allowlist.EnabledRole)},
+			},
+		}
+	}
+	topics, data := PackNativeCoinMintedEvent(allowlist.TestNoRoleAddr, big.NewInt(5), allowlist.TestEnabledAddr)
+
+	testutils.RunStateTests(t, Module, state.NewTestStateDB, map[string]testutils.StateTest{
+		"mint adds to balance and emits log": {
+			Caller:        allowlist.TestEnabledAddr,
+			Input:         mintInput(allowlist.TestNoRoleAddr, big.NewInt(5)),
+			SuppliedGas:   MintGasCost + NativeCoinMintedEventGasCost,
+			PreState:      enabledState(big.NewInt(10)),
+			ExpectedState: enabledState(big.NewInt(15)),
+			ExpectedLogs:  []testutils.TestLog{{Address: Module.Address, Topics: topics, Data: data}},
+			ExpectedRes:   []byte{},
+		},
+		"mint from no role leaves balance unchanged": {
+			Caller:        allowlist.TestNoRoleAddr,
+			Input:         mintInput(allowlist.TestNoRoleAddr, big.NewInt(5)),
+			SuppliedGas:   MintGasCost,
+			PreState:      enabledState(big.NewInt(10)),
+			ExpectedState: enabledState(big.NewInt(10)),
+			ExpectedLogs:  []testutils.TestLog{},
+			ExpectedErr:   ErrCannotMint.Error(),
+		},
+	})
+}
+
 func BenchmarkContractNativeMinter(b *testing.B) {
 	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
 }
@@ -7,6 +7,8 @@
 package rewardmanager
 
 import (
+	"fmt"
+
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
 	"github.com/ava-labs/subnet-evm/precompile/contract"
 	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
@@ -18,6 +20,9 @@ var _ precompileconfig.Config = &Config{}
 type InitialRewardConfig struct {
 	AllowFeeRecipients bool           `json:"allowFeeRecipients"`
 	RewardAddress      common.Address `json:"rewardAddress,omitempty"`
+	// FeeBurnPercentage is the percentage of fees burned from DUpgrade onwards, with the
+	// remainder paid to the reward address or fee recipient.
+	FeeBurnPercentage uint64 `json:"feeBurnPercentage,omitempty"`
 }
 
 func (i *InitialRewardConfig) Equal(other *InitialRewardConfig) bool {
@@ -25,19 +30,24 @@ func (i *InitialRewardConfig) Equal(other *InitialRewardConfig) bool {
 		return false
 	}
 
-	return i.AllowFeeRecipients == other.AllowFeeRecipients && i.RewardAddress == other.RewardAddress
+	return i.AllowFeeRecipients == other.AllowFeeRecipients && i.RewardAddress == other.RewardAddress && i.FeeBurnPercentage == other.FeeBurnPercentage
 }
 
 func (i *InitialRewardConfig) Verify() error {
 	switch {
 	case i.AllowFeeRecipients && i.RewardAddress != (common.Address{}):
 		return ErrCannotEnableBothRewards
+	case i.FeeBurnPercentage > MaxFeeBurnPercentage:
+		return fmt.Errorf("%w: %d exceeds %d", ErrInvalidFeeBurnPercentage, i.FeeBurnPercentage, MaxFeeBurnPercentage)
 	default:
 		return nil
 	}
 }
 
 func (i *InitialRewardConfig) Configure(state contract.StateDB) error {
+	if err := StoreFeeBurnPercentage(state, i.FeeBurnPercentage); err != nil {
+		return err
+	}
 	// enable allow fee recipients
 	if i.AllowFeeRecipients {
 		EnableAllowFeeRecipients(state)
@@ -92,6 +102,10 @@ func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
 		if err := c.InitialRewardConfig.Verify(); err != nil {
 			return err
 		}
+		// Fees are only burned from DUpgrade onwards, see [isFeeBurnActivated].
+		if c.InitialRewardConfig.FeeBurnPercentage != 0 && c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+			return errFeeBurnCannotBeActivated
+		}
 	}
 	return c.AllowListConfig.Verify(chainConfig, c.Upgrade)
 }
@@ -26,6 +26,25 @@ func TestVerify(t *testing.T) {
 			}),
 			ExpectedError: ErrCannotEnableBothRewards.Error(),
 		},
+		"fee burn percentage above maximum": {
+			Config: NewConfig(utils.NewUint64(3), admins, enableds, managers, &InitialRewardConfig{
+				AllowFeeRecipients: true,
+				FeeBurnPercentage:  MaxFeeBurnPercentage + 1,
+			}),
+			ExpectedError: ErrInvalidFeeBurnPercentage.Error(),
+		},
+		"fee burn percentage before DUpgrade": {
+			Config: NewConfig(utils.NewUint64(3), admins, enableds, managers, &InitialRewardConfig{
+				AllowFeeRecipients: true,
+				FeeBurnPercentage:  10,
+			}),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errFeeBurnCannotBeActivated.Error(),
+		},
 	}
 	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
 }
@@ -67,6 +86,17 @@ func TestEqual(t *testing.T) {
 				}),
 			Expected: false,
 		},
+		"different fee burn percentage": {
+			Config: NewConfig(utils.NewUint64(3), admins, nil, nil, &InitialRewardConfig{
+				RewardAddress:     common.HexToAddress("0x01"),
+				FeeBurnPercentage: 10,
+			}),
+			Other: NewConfig(utils.NewUint64(3), admins, nil, nil, &InitialRewardConfig{
+				RewardAddress:     common.HexToAddress("0x01"),
+				FeeBurnPercentage: 20,
+			}),
+			Expected: false,
+		},
 		"same config": {
 			Config: NewConfig(utils.NewUint64(3), admins, nil, nil, &InitialRewardConfig{
 				RewardAddress: common.HexToAddress("0x01"),
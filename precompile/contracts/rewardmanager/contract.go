@@ -290,6 +290,19 @@ func createRewardManagerPrecompile() contract.StatefulPrecompiledContract {
 		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
 	}
 
+	// The fee burn percentage functions are activated by the DUpgrade.
+	feeBurnFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"feeBurnPercentage":    feeBurnPercentage,
+		"setFeeBurnPercentage": setFeeBurnPercentage,
+	}
+	for name, function := range feeBurnFunctionMap {
+		method, ok := FeeBurnABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(method.ID, function, isFeeBurnActivated))
+	}
+
 	// Construct the contract with no fallback function.
 	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
 	if err != nil {
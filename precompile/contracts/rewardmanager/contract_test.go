@@ -4,6 +4,7 @@
 package rewardmanager
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ava-labs/subnet-evm/constants"
@@ -318,6 +319,106 @@ var (
 			ReadOnly:    false,
 			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
 		},
+		"set fee burn percentage from no role fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeBurnPercentage(big.NewInt(50))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetFeeBurnPercentageGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotSetFeeBurnPercentage.Error(),
+		},
+		"set fee burn percentage from enabled succeeds": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeBurnPercentage(big.NewInt(50))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetFeeBurnPercentageGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.EqualValues(t, 50, GetStoredFeeBurnPercentage(state))
+			},
+		},
+		"set fee burn percentage above maximum fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeBurnPercentage(big.NewInt(MaxFeeBurnPercentage + 1))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetFeeBurnPercentageGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInvalidFeeBurnPercentage.Error(),
+		},
+		"readOnly set fee burn percentage with allowed role fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetFeeBurnPercentage(big.NewInt(50))
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetFeeBurnPercentageGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"get fee burn percentage from no role succeeds": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				allowlist.SetDefaultRoles(Module.Address)(t, state)
+				require.NoError(t, StoreFeeBurnPercentage(state, 25))
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackFeeBurnPercentage()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: FeeBurnPercentageGasCost,
+			ReadOnly:    false,
+			ExpectedRes: func() []byte {
+				res, err := PackFeeBurnPercentageOutput(big.NewInt(25))
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get initial config with fee burn percentage": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackFeeBurnPercentage()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: FeeBurnPercentageGasCost,
+			Config: &Config{
+				InitialRewardConfig: &InitialRewardConfig{
+					RewardAddress:     testAddr,
+					FeeBurnPercentage: 40,
+				},
+			},
+			ReadOnly: false,
+			ExpectedRes: func() []byte {
+				res, err := PackFeeBurnPercentageOutput(big.NewInt(40))
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
 		"insufficient gas are fee recipients allowed from allowed role": {
 			Caller:     allowlist.TestEnabledAddr,
 			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
@@ -338,6 +439,41 @@ func TestRewardManagerRun(t *testing.T) {
 	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
 }
 
+func TestRewardManagerReadOnly(t *testing.T) {
+	functions := testutils.ABIReadOnlyFunctions(t, RewardManagerABI)
+	for name, function := range testutils.ABIReadOnlyFunctions(t, FeeBurnABI) {
+		functions[name] = function
+	}
+	testutils.RunReadOnlyTests(t, Module, state.NewTestStateDB, testutils.ReadOnlyTest{
+		Caller:     allowlist.TestAdminAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		Functions:  allowlist.ReadOnlyFunctions(t, functions),
+	})
+}
+
 func BenchmarkRewardManager(b *testing.B) {
 	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
 }
+
+func TestSplitFee(t *testing.T) {
+	tests := map[string]struct {
+		percentage     uint64
+		fee            int64
+		expectedReward int64
+		expectedBurned int64
+	}{
+		"no burn":          {percentage: 0, fee: 1000, expectedReward: 1000, expectedBurned: 0},
+		"partial burn":     {percentage: 30, fee: 1000, expectedReward: 700, expectedBurned: 300},
+		"rounds burn down": {percentage: 50, fee: 3, expectedReward: 2, expectedBurned: 1},
+		"full burn":        {percentage: MaxFeeBurnPercentage, fee: 1000, expectedReward: 0, expectedBurned: 1000},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			stateDB := state.NewTestStateDB(t)
+			require.NoError(t, StoreFeeBurnPercentage(stateDB, test.percentage))
+			reward, burned := SplitFee(stateDB, big.NewInt(test.fee))
+			require.EqualValues(t, test.expectedReward, reward.Int64())
+			require.EqualValues(t, test.expectedBurned, burned.Int64())
+		})
+	}
+}
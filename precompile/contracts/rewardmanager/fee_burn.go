@@ -0,0 +1,153 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rewardmanager
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// From DUpgrade onwards, a percentage of the fees of every transaction can be burned,
+// with the remainder paid to the coinbase of the block as configured by the other
+// functions of the RewardManager (either the reward address or the fee recipient).
+// The fee burn functions are declared in their own ABI, as the RewardManager ABI was
+// shipped before the DUpgrade.
+
+const (
+	// MaxFeeBurnPercentage is the percentage at which all fees are burned.
+	MaxFeeBurnPercentage = 100
+
+	FeeBurnPercentageGasCost    uint64 = contract.ReadGasCostPerSlot
+	SetFeeBurnPercentageGasCost uint64 = (contract.WriteGasCostPerSlot) + allowlist.ReadAllowListGasCost // write 1 slot + read allow list
+)
+
+var (
+	ErrCannotSetFeeBurnPercentage = errors.New("non-enabled cannot call setFeeBurnPercentage")
+	ErrInvalidFeeBurnPercentage   = errors.New("invalid fee burn percentage")
+
+	errFeeBurnCannotBeActivated = errors.New("fee burn percentage cannot be set before DUpgrade")
+
+	// FeeBurnRawABI contains the raw ABI of the fee burn functions of the RewardManager.
+	//go:embed fee_burn.abi
+	FeeBurnRawABI string
+
+	FeeBurnABI = contract.ParseABI(FeeBurnRawABI)
+
+	feeBurnPercentageStorageKey = common.Hash{'f', 'b', 'p', 's', 'k'}
+)
+
+// GetStoredFeeBurnPercentage returns the percentage of fees burned.
+func GetStoredFeeBurnPercentage(stateDB contract.StateDB) uint64 {
+	return stateDB.GetState(ContractAddress, feeBurnPercentageStorageKey).Big().Uint64()
+}
+
+// StoreFeeBurnPercentage stores the percentage of fees burned.
+func StoreFeeBurnPercentage(stateDB contract.StateDB, percentage uint64) error {
+	if percentage > MaxFeeBurnPercentage {
+		return fmt.Errorf("%w: %d exceeds %d", ErrInvalidFeeBurnPercentage, percentage, MaxFeeBurnPercentage)
+	}
+	stateDB.SetState(ContractAddress, feeBurnPercentageStorageKey, common.BigToHash(new(big.Int).SetUint64(percentage)))
+	return nil
+}
+
+// SplitFee splits [fee] into the amount paid to the coinbase and the amount burned,
+// according to the fee burn percentage stored in [stateDB].
+func SplitFee(stateDB contract.StateDB, fee *big.Int) (reward *big.Int, burned *big.Int) {
+	percentage := GetStoredFeeBurnPercentage(stateDB)
+	burned = new(big.Int).Mul(fee, new(big.Int).SetUint64(percentage))
+	burned.Div(burned, big.NewInt(MaxFeeBurnPercentage))
+	return new(big.Int).Sub(fee, burned), burned
+}
+
+// PackFeeBurnPercentage packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackFeeBurnPercentage() ([]byte, error) {
+	return FeeBurnABI.Pack("feeBurnPercentage")
+}
+
+// PackFeeBurnPercentageOutput attempts to pack given percentage of type *big.Int
+// to conform the ABI outputs.
+func PackFeeBurnPercentageOutput(percentage *big.Int) ([]byte, error) {
+	return FeeBurnABI.PackOutput("feeBurnPercentage", percentage)
+}
+
+func feeBurnPercentage(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, FeeBurnPercentageGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	// no input provided for this function
+	percentage := GetStoredFeeBurnPercentage(accessibleState.GetStateDB())
+	packedOutput, err := PackFeeBurnPercentageOutput(new(big.Int).SetUint64(percentage))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+// PackSetFeeBurnPercentage packs [percentage] of type *big.Int into the appropriate arguments for setFeeBurnPercentage.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackSetFeeBurnPercentage(percentage *big.Int) ([]byte, error) {
+	return FeeBurnABI.Pack("setFeeBurnPercentage", percentage)
+}
+
+// UnpackSetFeeBurnPercentageInput attempts to unpack [input] into the *big.Int type argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackSetFeeBurnPercentageInput(input []byte) (*big.Int, error) {
+	res, err := FeeBurnABI.UnpackInput("setFeeBurnPercentage", input)
+	if err != nil {
+		return nil, err
+	}
+	unpacked := *abi.ConvertType(res[0], new(*big.Int)).(**big.Int)
+	return unpacked, nil
+}
+
+func setFeeBurnPercentage(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SetFeeBurnPercentageGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	percentage, err := UnpackSetFeeBurnPercentageInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to call this function.
+	callerStatus := allowlist.GetAllowListStatus(stateDB, ContractAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotSetFeeBurnPercentage, caller)
+	}
+
+	if !percentage.IsUint64() {
+		return nil, remainingGas, fmt.Errorf("%w: %s exceeds %d", ErrInvalidFeeBurnPercentage, percentage, MaxFeeBurnPercentage)
+	}
+	if err := StoreFeeBurnPercentage(stateDB, percentage.Uint64()); err != nil {
+		return nil, remainingGas, err
+	}
+	// this function does not return an output, leave this one as is
+	packedOutput := []byte{}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+// isFeeBurnActivated returns true if the fee burn percentage is in effect.
+func isFeeBurnActivated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetChainConfig().IsDUpgrade(accessibleState.GetBlockContext().Timestamp())
+}
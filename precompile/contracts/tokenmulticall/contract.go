@@ -0,0 +1,192 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tokenmulticall
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The token multicall reads the ERC-20 balances and allowances of many (token, account)
+// pairs in a single call, so that wallets do not need a call per pair. Each token is called
+// with STATICCALL semantics, and a token whose call fails or returns malformed data is
+// reported as unsuccessful instead of failing the whole call. The zero address denotes the
+// native token, whose balance is read directly.
+
+const (
+	// MaxQueries is the maximum number of pairs read in a single call.
+	MaxQueries = 512
+
+	// QueryGasCost is charged for each pair, as the STATICCALL opcode charges to access a
+	// cold account. The gas used by the call of the token is charged on top of that.
+	QueryGasCost uint64 = params.ColdAccountAccessCostEIP2929
+	// MaxTokenCallGas is the maximum gas forwarded to the call of a token, so that a
+	// misbehaving token cannot consume the gas of the other pairs.
+	MaxTokenCallGas uint64 = 50_000
+)
+
+var (
+	ErrLengthMismatch  = errors.New("input arrays have different lengths")
+	ErrTooManyQueries  = errors.New("too many queries")
+	ErrCallUnsupported = errors.New("contract calls are not supported by the accessible state")
+
+	// TokenMulticallRawABI contains the raw ABI of TokenMulticall contract.
+	//go:embed contract.abi
+	TokenMulticallRawABI string
+
+	TokenMulticallABI        = contract.ParseABI(TokenMulticallRawABI)
+	TokenMulticallPrecompile = createTokenMulticallPrecompile()
+
+	// erc20ABI contains the functions the multicall calls on tokens.
+	erc20ABI = contract.ParseABI(`[{"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`)
+)
+
+// deductQueryGas checks the number of pairs [n] and deducts their gas from [suppliedGas].
+func deductQueryGas(suppliedGas uint64, n int) (uint64, error) {
+	if n > MaxQueries {
+		return suppliedGas, fmt.Errorf("%w: %d exceeds %d", ErrTooManyQueries, n, MaxQueries)
+	}
+	return contract.DeductGas(suppliedGas, QueryGasCost*uint64(n))
+}
+
+// callToken calls [method] of [token] with [args], forwarding at most MaxTokenCallGas of
+// [suppliedGas]. Returns false if the call fails or does not return a uint256.
+func callToken(accessibleState contract.AccessibleState, token common.Address, suppliedGas uint64, method string, args ...interface{}) (bool, *big.Int, uint64, error) {
+	caller, ok := accessibleState.(contract.ContractCallerAccessibleState)
+	if !ok {
+		return false, nil, suppliedGas, ErrCallUnsupported
+	}
+	input, err := erc20ABI.Pack(method, args...)
+	if err != nil {
+		return false, nil, suppliedGas, err
+	}
+	// Retain 1/64th of the gas as by EIP-150.
+	gas := suppliedGas - suppliedGas/64
+	if gas > MaxTokenCallGas {
+		gas = MaxTokenCallGas
+	}
+	ret, leftOverGas, err := caller.StaticCallContract(ContractAddress, token, input, gas)
+	remainingGas := suppliedGas - gas + leftOverGas
+	if err != nil || len(ret) < common.HashLength {
+		return false, new(big.Int), remainingGas, nil
+	}
+	return true, new(big.Int).SetBytes(ret[:common.HashLength]), remainingGas, nil
+}
+
+// PackBalances packs [tokens] and [holders] into the input for balances, including the selector.
+func PackBalances(tokens []common.Address, holders []common.Address) ([]byte, error) {
+	return TokenMulticallABI.Pack("balances", tokens, holders)
+}
+
+// PackQueryOutput packs [success] and [amounts] to conform the ABI outputs of [method].
+func PackQueryOutput(method string, success []bool, amounts []*big.Int) ([]byte, error) {
+	return TokenMulticallABI.PackOutput(method, success, amounts)
+}
+
+// balances returns the balance of each input holder of the input token at the same index.
+func balances(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	res, err := TokenMulticallABI.UnpackInput("balances", input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	tokens := *abi.ConvertType(res[0], new([]common.Address)).(*[]common.Address)
+	holders := *abi.ConvertType(res[1], new([]common.Address)).(*[]common.Address)
+	if len(tokens) != len(holders) {
+		return nil, suppliedGas, fmt.Errorf("%w: %d tokens, %d holders", ErrLengthMismatch, len(tokens), len(holders))
+	}
+	if remainingGas, err = deductQueryGas(suppliedGas, len(tokens)); err != nil {
+		return nil, 0, err
+	}
+
+	success := make([]bool, len(tokens))
+	amounts := make([]*big.Int, len(tokens))
+	for i, token := range tokens {
+		if token == (common.Address{}) {
+			success[i], amounts[i] = true, accessibleState.GetStateDB().GetBalance(holders[i])
+			continue
+		}
+		success[i], amounts[i], remainingGas, err = callToken(accessibleState, token, remainingGas, "balanceOf", holders[i])
+		if err != nil {
+			return nil, remainingGas, err
+		}
+	}
+	packedOutput, err := PackQueryOutput("balances", success, amounts)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackAllowances packs [tokens], [owners] and [spenders] into the input for allowances,
+// including the selector.
+func PackAllowances(tokens []common.Address, owners []common.Address, spenders []common.Address) ([]byte, error) {
+	return TokenMulticallABI.Pack("allowances", tokens, owners, spenders)
+}
+
+// allowances returns the amount of the input token each input spender can transfer from
+// the input owner at the same index. The native token has no allowances.
+func allowances(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	res, err := TokenMulticallABI.UnpackInput("allowances", input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	tokens := *abi.ConvertType(res[0], new([]common.Address)).(*[]common.Address)
+	owners := *abi.ConvertType(res[1], new([]common.Address)).(*[]common.Address)
+	spenders := *abi.ConvertType(res[2], new([]common.Address)).(*[]common.Address)
+	if len(tokens) != len(owners) || len(tokens) != len(spenders) {
+		return nil, suppliedGas, fmt.Errorf("%w: %d tokens, %d owners, %d spenders", ErrLengthMismatch, len(tokens), len(owners), len(spenders))
+	}
+	if remainingGas, err = deductQueryGas(suppliedGas, len(tokens)); err != nil {
+		return nil, 0, err
+	}
+
+	success := make([]bool, len(tokens))
+	amounts := make([]*big.Int, len(tokens))
+	for i, token := range tokens {
+		if token == (common.Address{}) {
+			amounts[i] = new(big.Int)
+			continue
+		}
+		success[i], amounts[i], remainingGas, err = callToken(accessibleState, token, remainingGas, "allowance", owners[i], spenders[i])
+		if err != nil {
+			return nil, remainingGas, err
+		}
+	}
+	packedOutput, err := PackQueryOutput("allowances", success, amounts)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// createTokenMulticallPrecompile returns a StatefulPrecompiledContract implementing the
+// token multicall.
+func createTokenMulticallPrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"allowances": allowances,
+		"balances":   balances,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := TokenMulticallABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
@@ -0,0 +1,161 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tokenmulticall
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testHolder  = common.Address{'h'}
+	testSpender = common.Address{'s'}
+	testToken   = common.Address{'t'}
+	testFailing = common.Address{'f'}
+	testAmount  = big.NewInt(100)
+)
+
+// testCallerAccessibleState serves the calls of the multicall from [tokens], using
+// [callGas] for each call.
+type testCallerAccessibleState struct {
+	contract.AccessibleState
+	tokens  map[common.Address][]byte
+	callGas uint64
+	calls   int
+}
+
+func (s *testCallerAccessibleState) StaticCallContract(caller common.Address, addr common.Address, input []byte, gas uint64) ([]byte, uint64, error) {
+	s.calls++
+	if gas < s.callGas {
+		return nil, 0, vmerrs.ErrOutOfGas
+	}
+	ret, ok := s.tokens[addr]
+	if !ok {
+		return nil, gas - s.callGas, errors.New("reverted")
+	}
+	return ret, gas - s.callGas, nil
+}
+
+func newTestCallerAccessibleState(t *testing.T) *testCallerAccessibleState {
+	stateDB := state.NewTestStateDB(t)
+	stateDB.AddBalance(testHolder, testAmount)
+	accessibleState := contract.NewMockAccessibleState(gomock.NewController(t))
+	accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+	return &testCallerAccessibleState{
+		AccessibleState: accessibleState,
+		tokens: map[common.Address][]byte{
+			testToken: common.BigToHash(testAmount).Bytes(),
+		},
+		callGas: 1_000,
+	}
+}
+
+func TestTokenMulticallBalances(t *testing.T) {
+	require := require.New(t)
+	accessibleState := newTestCallerAccessibleState(t)
+
+	input, err := PackBalances([]common.Address{testToken, {}, testFailing}, []common.Address{testHolder, testHolder, testHolder})
+	require.NoError(err)
+	suppliedGas := 3*QueryGasCost + 100_000
+	ret, remainingGas, err := TokenMulticallPrecompile.Run(accessibleState, testHolder, ContractAddress, input, suppliedGas, true)
+	require.NoError(err)
+	// The native balance is read without calling a token.
+	require.Equal(2, accessibleState.calls)
+	require.Equal(suppliedGas-3*QueryGasCost-2*accessibleState.callGas, remainingGas)
+
+	expected, err := PackQueryOutput("balances", []bool{true, true, false}, []*big.Int{testAmount, testAmount, common.Big0})
+	require.NoError(err)
+	require.Equal(expected, ret)
+}
+
+func TestTokenMulticallAllowances(t *testing.T) {
+	require := require.New(t)
+	accessibleState := newTestCallerAccessibleState(t)
+
+	input, err := PackAllowances([]common.Address{testToken, {}}, []common.Address{testHolder, testHolder}, []common.Address{testSpender, testSpender})
+	require.NoError(err)
+	ret, _, err := TokenMulticallPrecompile.Run(accessibleState, testHolder, ContractAddress, input, 2*QueryGasCost+100_000, true)
+	require.NoError(err)
+	expected, err := PackQueryOutput("allowances", []bool{true, false}, []*big.Int{testAmount, common.Big0})
+	require.NoError(err)
+	require.Equal(expected, ret)
+
+	// The gas forwarded to a token is capped.
+	accessibleState.callGas = MaxTokenCallGas + 1
+	ret, _, err = TokenMulticallPrecompile.Run(accessibleState, testHolder, ContractAddress, input, 2*QueryGasCost+10*MaxTokenCallGas, true)
+	require.NoError(err)
+	expected, err = PackQueryOutput("allowances", []bool{false, false}, []*big.Int{common.Big0, common.Big0})
+	require.NoError(err)
+	require.Equal(expected, ret)
+}
+
+func TestTokenMulticallRun(t *testing.T) {
+	tests := map[string]testutils.PrecompileTest{
+		"length mismatch": {
+			Caller:      testHolder,
+			Input:       func() []byte { input, _ := PackBalances([]common.Address{testToken}, nil); return input }(),
+			SuppliedGas: 0,
+			ReadOnly:    true,
+			ExpectedErr: ErrLengthMismatch.Error(),
+		},
+		"too many queries": {
+			Caller: testHolder,
+			Input: func() []byte {
+				addrs := make([]common.Address, MaxQueries+1)
+				input, _ := PackBalances(addrs, addrs)
+				return input
+			}(),
+			SuppliedGas: 0,
+			ReadOnly:    true,
+			ExpectedErr: ErrTooManyQueries.Error(),
+		},
+		"insufficient gas": {
+			Caller: testHolder,
+			Input: func() []byte {
+				input, _ := PackBalances([]common.Address{{}, {}}, []common.Address{testHolder, testHolder})
+				return input
+			}(),
+			SuppliedGas: 2*QueryGasCost - 1,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"native balances": {
+			Caller: testHolder,
+			BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+				stateDB.AddBalance(testHolder, testAmount)
+			},
+			Input: func() []byte {
+				input, _ := PackBalances([]common.Address{{}}, []common.Address{testHolder})
+				return input
+			}(),
+			SuppliedGas: QueryGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte { res, _ := PackQueryOutput("balances", []bool{true}, []*big.Int{testAmount}); return res }(),
+		},
+		"token calls unsupported": {
+			Caller: testHolder,
+			Input: func() []byte {
+				input, _ := PackBalances([]common.Address{testToken}, []common.Address{testHolder})
+				return input
+			}(),
+			SuppliedGas: QueryGasCost,
+			ReadOnly:    true,
+			ExpectedErr: ErrCallUnsupported.Error(),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.Run(t, Module, state.NewTestStateDB(t))
+		})
+	}
+}
@@ -10,16 +10,35 @@ import (
 )
 
 // Singleton StatefulPrecompiledContract for W/R access to the tx allow list.
-var TxAllowListPrecompile contract.StatefulPrecompiledContract = allowlist.CreateAllowListPrecompile(ContractAddress)
+var TxAllowListPrecompile contract.StatefulPrecompiledContract = createTxAllowListPrecompile()
 
 // GetTxAllowListStatus returns the role of [address] for the tx allow list.
 func GetTxAllowListStatus(stateDB contract.StateDB, address common.Address) allowlist.Role {
 	return allowlist.GetAllowListStatus(stateDB, ContractAddress, address)
 }
 
+// GetTxAllowListStatusAt returns the role of [address] for the tx allow list at
+// [timestamp]. Addresses whose temporary permissions have lapsed have no role.
+func GetTxAllowListStatusAt(stateDB contract.StateDB, address common.Address, timestamp uint64) allowlist.Role {
+	return allowlist.GetAllowListStatusAt(stateDB, ContractAddress, address, timestamp)
+}
+
 // SetTxAllowListStatus sets the permissions of [address] to [role] for the
 // tx allow list.
 // assumes [role] has already been verified as valid.
 func SetTxAllowListStatus(stateDB contract.StateDB, address common.Address, role allowlist.Role) {
 	allowlist.SetAllowListRole(stateDB, ContractAddress, address, role)
 }
+
+// createTxAllowListPrecompile returns a StatefulPrecompiledContract with R/W control of the
+// tx allow list, including granting temporary permissions with setEnabledUntil.
+func createTxAllowListPrecompile() contract.StatefulPrecompiledContract {
+	functions := allowlist.CreateAllowListFunctions(ContractAddress)
+	functions = append(functions, allowlist.CreateSetEnabledUntilFunction(ContractAddress))
+	// Construct the contract with no fallback function.
+	contract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return contract
+}
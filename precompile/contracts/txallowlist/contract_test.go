@@ -5,15 +5,123 @@ package txallowlist
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
 )
 
+var expiry = uint64(time.Now().Add(time.Hour).Unix())
+
+var tests = map[string]testutils.PrecompileTest{
+	"admin set enabled until": {
+		Caller:     allowlist.TestAdminAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := allowlist.PackSetEnabledUntil(allowlist.TestNoRoleAddr, expiry)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: allowlist.SetEnabledUntilGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, allowlist.EnabledRole, GetTxAllowListStatusAt(state, allowlist.TestNoRoleAddr, expiry-1))
+			require.Equal(t, allowlist.NoRole, GetTxAllowListStatusAt(state, allowlist.TestNoRoleAddr, expiry))
+		},
+	},
+	"manager set enabled until": {
+		Caller:     allowlist.TestManagerAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := allowlist.PackSetEnabledUntil(allowlist.TestEnabledAddr, expiry)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: allowlist.SetEnabledUntilGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Equal(t, expiry, allowlist.GetAllowListExpiry(state, Module.Address, allowlist.TestEnabledAddr))
+		},
+	},
+	"enabled cannot set enabled until": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := allowlist.PackSetEnabledUntil(allowlist.TestNoRoleAddr, expiry)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: allowlist.SetEnabledUntilGasCost,
+		ReadOnly:    false,
+		ExpectedErr: allowlist.ErrCannotModifyAllowList.Error(),
+	},
+	"set enabled until in the past fails": {
+		Caller:     allowlist.TestAdminAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := allowlist.PackSetEnabledUntil(allowlist.TestNoRoleAddr, 1)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: allowlist.SetEnabledUntilGasCost,
+		ReadOnly:    false,
+		ExpectedErr: allowlist.ErrInvalidExpiry.Error(),
+	},
+	"readOnly set enabled until fails": {
+		Caller:     allowlist.TestAdminAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := allowlist.PackSetEnabledUntil(allowlist.TestNoRoleAddr, expiry)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: allowlist.SetEnabledUntilGasCost,
+		ReadOnly:    true,
+		ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+	},
+	"set enabled replaces expiry": {
+		Caller: allowlist.TestAdminAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, state)
+			allowlist.SetAllowListExpiry(state, Module.Address, allowlist.TestEnabledAddr, expiry)
+		},
+		InputFn: func(t testing.TB) []byte {
+			input, err := allowlist.PackModifyAllowList(allowlist.TestEnabledAddr, allowlist.EnabledRole)
+			require.NoError(t, err)
+			return input
+		},
+		SuppliedGas: allowlist.ModifyAllowListGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			require.Zero(t, allowlist.GetAllowListExpiry(state, Module.Address, allowlist.TestEnabledAddr))
+			require.Equal(t, allowlist.EnabledRole, GetTxAllowListStatusAt(state, allowlist.TestEnabledAddr, expiry))
+		},
+	},
+	"read expired role": {
+		Caller: allowlist.TestNoRoleAddr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, state)
+			allowlist.SetAllowListExpiry(state, Module.Address, allowlist.TestEnabledAddr, 1)
+		},
+		Input:       allowlist.PackReadAllowList(allowlist.TestEnabledAddr),
+		SuppliedGas: allowlist.ReadAllowListGasCost,
+		ReadOnly:    true,
+		ExpectedRes: common.Hash(allowlist.NoRole).Bytes(),
+	},
+}
+
 func TestTxAllowListRun(t *testing.T) {
-	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, nil)
+	allowlist.RunPrecompileWithAllowListTests(t, Module, state.NewTestStateDB, tests)
 }
 
 func BenchmarkTxAllowList(b *testing.B) {
-	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, nil)
+	allowlist.BenchPrecompileWithAllowList(b, Module, state.NewTestStateDB, tests)
 }
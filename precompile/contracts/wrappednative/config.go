@@ -0,0 +1,101 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappednative
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+)
+
+const (
+	// DefaultName and DefaultSymbol are the name and symbol of the wrapped native token,
+	// unless the config specifies otherwise.
+	DefaultName   = "Wrapped Native Token"
+	DefaultSymbol = "WNATIVE"
+
+	// maxShortStringLen is the maximum length of a string stored in a single storage slot.
+	maxShortStringLen = 31
+)
+
+var _ precompileconfig.Config = &Config{}
+
+var errWrappedNativeCannotBeActivated = errors.New("wrapped native token cannot be activated before DUpgrade")
+
+// Config implements the precompileconfig.Config interface while adding in the
+// wrapped native token specific precompile config.
+type Config struct {
+	precompileconfig.Upgrade
+	// Name and Symbol are returned by the name and symbol functions of the token
+	// (empty denotes using the default).
+	Name   string `json:"name,omitempty"`
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the wrapped native token with [name] and [symbol].
+func NewConfig(blockTimestamp *uint64, name string, symbol string) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		Name:    name,
+		Symbol:  symbol,
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the wrapped native token.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the wrapped native token precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	if len(c.Name) > maxShortStringLen {
+		return fmt.Errorf("cannot specify name longer than %d bytes: %q", maxShortStringLen, c.Name)
+	}
+	if len(c.Symbol) > maxShortStringLen {
+		return fmt.Errorf("cannot specify symbol longer than %d bytes: %q", maxShortStringLen, c.Symbol)
+	}
+	// Deposits are paid to a payable function, see [contract.NewPayableStatefulPrecompileFunction].
+	if c.Timestamp() != nil && !chainConfig.IsDUpgrade(*c.Timestamp()) {
+		return errWrappedNativeCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) && c.Name == other.Name && c.Symbol == other.Symbol
+}
+
+// name returns the name of [c], using the default if unspecified.
+func (c *Config) name() string {
+	if c.Name == "" {
+		return DefaultName
+	}
+	return c.Name
+}
+
+// symbol returns the symbol of [c], using the default if unspecified.
+func (c *Config) symbol() string {
+	if c.Symbol == "" {
+		return DefaultSymbol
+	}
+	return c.Symbol
+}
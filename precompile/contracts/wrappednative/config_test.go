@@ -0,0 +1,80 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappednative
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/utils"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerify(t *testing.T) {
+	longString := strings.Repeat("a", maxShortStringLen+1)
+	tests := map[string]testutils.ConfigVerifyTest{
+		"valid config": {
+			Config: NewConfig(utils.NewUint64(3), "Wrapped AVAX", "WAVAX"),
+		},
+		"default name and symbol": {
+			Config: NewConfig(utils.NewUint64(3), "", ""),
+		},
+		"name too long": {
+			Config:        NewConfig(utils.NewUint64(3), longString, ""),
+			ExpectedError: "cannot specify name longer than",
+		},
+		"symbol too long": {
+			Config:        NewConfig(utils.NewUint64(3), "", longString),
+			ExpectedError: "cannot specify symbol longer than",
+		},
+		"cannot be activated before DUpgrade": {
+			Config: NewConfig(utils.NewUint64(3), "", ""),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: errWrappedNativeCannotBeActivated.Error(),
+		},
+	}
+	testutils.RunVerifyTests(t, tests)
+}
+
+func TestEqual(t *testing.T) {
+	tests := map[string]testutils.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), "", ""),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(utils.NewUint64(3), "", ""),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different timestamp": {
+			Config:   NewConfig(utils.NewUint64(3), "", ""),
+			Other:    NewConfig(utils.NewUint64(4), "", ""),
+			Expected: false,
+		},
+		"different name": {
+			Config:   NewConfig(utils.NewUint64(3), "Wrapped AVAX", ""),
+			Other:    NewConfig(utils.NewUint64(3), "", ""),
+			Expected: false,
+		},
+		"different symbol": {
+			Config:   NewConfig(utils.NewUint64(3), "", "WAVAX"),
+			Other:    NewConfig(utils.NewUint64(3), "", "WETH"),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), "Wrapped AVAX", "WAVAX"),
+			Other:    NewConfig(utils.NewUint64(3), "Wrapped AVAX", "WAVAX"),
+			Expected: true,
+		},
+	}
+	testutils.RunEqualTests(t, tests)
+}
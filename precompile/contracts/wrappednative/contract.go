@@ -0,0 +1,475 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappednative
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The wrapped native token is an ERC-20 token backed 1:1 by the native token of the chain,
+// compatible with WETH9. Native tokens sent to deposit, or to the precompile without input,
+// are credited to the balance of the sender, and withdraw returns them. As in WETH9, the
+// total supply is the native balance of the precompile, and an allowance of the maximum
+// uint256 is never decreased by transferFrom.
+
+const (
+	// Decimals is the number of decimals of the wrapped native token, matching the native token.
+	Decimals uint8 = 18
+
+	DecimalsGasCost    uint64 = 2 // the decimals are constant
+	NameGasCost        uint64 = contract.ReadGasCostPerSlot
+	SymbolGasCost      uint64 = contract.ReadGasCostPerSlot
+	TotalSupplyGasCost uint64 = contract.ReadGasCostPerSlot // read the balance of the precompile
+	BalanceOfGasCost   uint64 = contract.ReadGasCostPerSlot
+	AllowanceGasCost   uint64 = contract.ReadGasCostPerSlot
+
+	DepositGasCost      uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot + twoTopicEventGasCost       // read and write balance + Deposit log
+	WithdrawGasCost     uint64 = contract.ReadGasCostPerSlot*2 + contract.WriteGasCostPerSlot*3 + twoTopicEventGasCost   // read balance and precompile balance, write balance and native balances + Withdrawal log
+	ApproveGasCost      uint64 = contract.WriteGasCostPerSlot + threeTopicEventGasCost                                   // write allowance + Approval log
+	TransferGasCost     uint64 = contract.ReadGasCostPerSlot*2 + contract.WriteGasCostPerSlot*2 + threeTopicEventGasCost // read and write both balances + Transfer log
+	TransferFromGasCost uint64 = TransferGasCost + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot            // plus read and write allowance
+
+	twoTopicEventGasCost   = contract.LogGas + 2*contract.LogTopicGas + common.HashLength*contract.LogDataGasPerByte
+	threeTopicEventGasCost = contract.LogGas + 3*contract.LogTopicGas + common.HashLength*contract.LogDataGasPerByte
+)
+
+var (
+	ErrInsufficientBalance   = errors.New("insufficient wrapped native token balance")
+	ErrInsufficientAllowance = errors.New("insufficient wrapped native token allowance")
+
+	// WrappedNativeRawABI contains the raw ABI of WrappedNative contract.
+	//go:embed contract.abi
+	WrappedNativeRawABI string
+
+	WrappedNativeABI        = contract.ParseABI(WrappedNativeRawABI)
+	WrappedNativePrecompile = createWrappedNativePrecompile()
+
+	nameKey   = common.Hash{'w', 'n', 'n'}
+	symbolKey = common.Hash{'w', 'n', 's'}
+
+	balanceKeyPrefix   = []byte("wnb")
+	allowanceKeyPrefix = []byte("wna")
+)
+
+// balanceKey returns the storage key of the balance of [account].
+func balanceKey(account common.Address) common.Hash {
+	return crypto.Keccak256Hash(balanceKeyPrefix, account.Bytes())
+}
+
+// allowanceKey returns the storage key of the amount [spender] can transfer from [owner].
+func allowanceKey(owner common.Address, spender common.Address) common.Hash {
+	return crypto.Keccak256Hash(allowanceKeyPrefix, owner.Bytes(), spender.Bytes())
+}
+
+// storeShortString stores [s] of at most 31 bytes in the slot [key], encoded as Solidity
+// encodes short strings: left aligned, with twice the length in the last byte.
+func storeShortString(stateDB contract.StateDB, key common.Hash, s string) {
+	var value common.Hash
+	copy(value[:], s)
+	value[common.HashLength-1] = byte(2 * len(s))
+	stateDB.SetState(ContractAddress, key, value)
+}
+
+// readShortString returns the string stored in the slot [key] by storeShortString.
+func readShortString(stateDB contract.StateDB, key common.Hash) string {
+	value := stateDB.GetState(ContractAddress, key)
+	length := int(value[common.HashLength-1] / 2)
+	if length > maxShortStringLen {
+		length = maxShortStringLen
+	}
+	return string(value[:length])
+}
+
+// GetBalance returns the wrapped native token balance of [account].
+func GetBalance(stateDB contract.StateDB, account common.Address) *big.Int {
+	return stateDB.GetState(ContractAddress, balanceKey(account)).Big()
+}
+
+func setBalance(stateDB contract.StateDB, account common.Address, balance *big.Int) {
+	stateDB.SetState(ContractAddress, balanceKey(account), common.BigToHash(balance))
+}
+
+// GetAllowance returns the amount of wrapped native tokens [spender] can transfer from [owner].
+func GetAllowance(stateDB contract.StateDB, owner common.Address, spender common.Address) *big.Int {
+	return stateDB.GetState(ContractAddress, allowanceKey(owner, spender)).Big()
+}
+
+// emitEvent adds a log of the event [name] with [args] to the state.
+func emitEvent(accessibleState contract.AccessibleState, name string, args ...interface{}) error {
+	topics, data, err := WrappedNativeABI.PackEvent(name, args...)
+	if err != nil {
+		return err
+	}
+	accessibleState.GetStateDB().AddLog(ContractAddress, topics, data, accessibleState.GetBlockContext().Number().Uint64())
+	return nil
+}
+
+// transferBalance moves [amount] wrapped native tokens from [src] to [dst] and emits a
+// Transfer log.
+func transferBalance(accessibleState contract.AccessibleState, src common.Address, dst common.Address, amount *big.Int) error {
+	stateDB := accessibleState.GetStateDB()
+	srcBalance := GetBalance(stateDB, src)
+	if srcBalance.Cmp(amount) < 0 {
+		return fmt.Errorf("%w: %s has %s, need %s", ErrInsufficientBalance, src, srcBalance, amount)
+	}
+	setBalance(stateDB, src, new(big.Int).Sub(srcBalance, amount))
+	setBalance(stateDB, dst, new(big.Int).Add(GetBalance(stateDB, dst), amount))
+	return emitEvent(accessibleState, "Transfer", src, dst, amount)
+}
+
+// PackName packs the input for name, including the selector.
+func PackName() ([]byte, error) {
+	return WrappedNativeABI.Pack("name")
+}
+
+// PackNameOutput packs [name] to conform the ABI outputs.
+func PackNameOutput(name string) ([]byte, error) {
+	return WrappedNativeABI.PackOutput("name", name)
+}
+
+func name(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, NameGasCost); err != nil {
+		return nil, 0, err
+	}
+	packedOutput, err := PackNameOutput(readShortString(accessibleState.GetStateDB(), nameKey))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackSymbol packs the input for symbol, including the selector.
+func PackSymbol() ([]byte, error) {
+	return WrappedNativeABI.Pack("symbol")
+}
+
+// PackSymbolOutput packs [symbol] to conform the ABI outputs.
+func PackSymbolOutput(symbol string) ([]byte, error) {
+	return WrappedNativeABI.PackOutput("symbol", symbol)
+}
+
+func symbol(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SymbolGasCost); err != nil {
+		return nil, 0, err
+	}
+	packedOutput, err := PackSymbolOutput(readShortString(accessibleState.GetStateDB(), symbolKey))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackDecimals packs the input for decimals, including the selector.
+func PackDecimals() ([]byte, error) {
+	return WrappedNativeABI.Pack("decimals")
+}
+
+// PackDecimalsOutput packs [decimals] to conform the ABI outputs.
+func PackDecimalsOutput(decimals uint8) ([]byte, error) {
+	return WrappedNativeABI.PackOutput("decimals", decimals)
+}
+
+func decimals(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DecimalsGasCost); err != nil {
+		return nil, 0, err
+	}
+	packedOutput, err := PackDecimalsOutput(Decimals)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackTotalSupply packs the input for totalSupply, including the selector.
+func PackTotalSupply() ([]byte, error) {
+	return WrappedNativeABI.Pack("totalSupply")
+}
+
+// PackTotalSupplyOutput packs [totalSupply] to conform the ABI outputs.
+func PackTotalSupplyOutput(totalSupply *big.Int) ([]byte, error) {
+	return WrappedNativeABI.PackOutput("totalSupply", totalSupply)
+}
+
+// totalSupply returns the native balance of the precompile, which backs the wrapped tokens.
+func totalSupply(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, TotalSupplyGasCost); err != nil {
+		return nil, 0, err
+	}
+	packedOutput, err := PackTotalSupplyOutput(accessibleState.GetStateDB().GetBalance(ContractAddress))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackBalanceOf packs [account] into the input for balanceOf, including the selector.
+func PackBalanceOf(account common.Address) ([]byte, error) {
+	return WrappedNativeABI.Pack("balanceOf", account)
+}
+
+// PackBalanceOfOutput packs [balance] to conform the ABI outputs.
+func PackBalanceOfOutput(balance *big.Int) ([]byte, error) {
+	return WrappedNativeABI.PackOutput("balanceOf", balance)
+}
+
+func balanceOf(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, BalanceOfGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := WrappedNativeABI.UnpackInput("balanceOf", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	account := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	packedOutput, err := PackBalanceOfOutput(GetBalance(accessibleState.GetStateDB(), account))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackAllowance packs [owner] and [spender] into the input for allowance, including the selector.
+func PackAllowance(owner common.Address, spender common.Address) ([]byte, error) {
+	return WrappedNativeABI.Pack("allowance", owner, spender)
+}
+
+// PackAllowanceOutput packs [allowance] to conform the ABI outputs.
+func PackAllowanceOutput(allowance *big.Int) ([]byte, error) {
+	return WrappedNativeABI.PackOutput("allowance", allowance)
+}
+
+func allowance(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, AllowanceGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := WrappedNativeABI.UnpackInput("allowance", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	owner := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	spender := *abi.ConvertType(res[1], new(common.Address)).(*common.Address)
+	packedOutput, err := PackAllowanceOutput(GetAllowance(accessibleState.GetStateDB(), owner, spender))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackDeposit packs the input for deposit, including the selector.
+func PackDeposit() ([]byte, error) {
+	return WrappedNativeABI.Pack("deposit")
+}
+
+// deposit credits the value sent by the caller, which is held by the precompile, to the
+// wrapped native token balance of the caller. It is also the fallback of the precompile.
+func deposit(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DepositGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	value := contract.CallValue(accessibleState)
+	stateDB := accessibleState.GetStateDB()
+	setBalance(stateDB, caller, new(big.Int).Add(GetBalance(stateDB, caller), value))
+	if err := emitEvent(accessibleState, "Deposit", caller, value); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackWithdraw packs [amount] into the input for withdraw, including the selector.
+func PackWithdraw(amount *big.Int) ([]byte, error) {
+	return WrappedNativeABI.Pack("withdraw", amount)
+}
+
+// withdraw burns the input amount of wrapped native tokens of the caller and returns the
+// native tokens backing them to the caller.
+func withdraw(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, WithdrawGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := WrappedNativeABI.UnpackInput("withdraw", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	amount := *abi.ConvertType(res[0], new(*big.Int)).(**big.Int)
+
+	stateDB := accessibleState.GetStateDB()
+	balance := GetBalance(stateDB, caller)
+	if balance.Cmp(amount) < 0 {
+		return nil, remainingGas, fmt.Errorf("%w: %s has %s, need %s", ErrInsufficientBalance, caller, balance, amount)
+	}
+	setBalance(stateDB, caller, new(big.Int).Sub(balance, amount))
+	if err := contract.TransferBalance(stateDB, ContractAddress, caller, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	if err := emitEvent(accessibleState, "Withdrawal", caller, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	return []byte{}, remainingGas, nil
+}
+
+// PackApprove packs [spender] and [amount] into the input for approve, including the selector.
+func PackApprove(spender common.Address, amount *big.Int) ([]byte, error) {
+	return WrappedNativeABI.Pack("approve", spender, amount)
+}
+
+// PackBoolOutput packs the boolean output of approve, transfer and transferFrom.
+func PackBoolOutput(method string, success bool) ([]byte, error) {
+	return WrappedNativeABI.PackOutput(method, success)
+}
+
+// approve sets the amount of wrapped native tokens the input spender can transfer from
+// the caller.
+func approve(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ApproveGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := WrappedNativeABI.UnpackInput("approve", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	spender := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	amount := *abi.ConvertType(res[1], new(*big.Int)).(**big.Int)
+
+	accessibleState.GetStateDB().SetState(ContractAddress, allowanceKey(caller, spender), common.BigToHash(amount))
+	if err := emitEvent(accessibleState, "Approval", caller, spender, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := PackBoolOutput("approve", true)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackTransfer packs [dst] and [amount] into the input for transfer, including the selector.
+func PackTransfer(dst common.Address, amount *big.Int) ([]byte, error) {
+	return WrappedNativeABI.Pack("transfer", dst, amount)
+}
+
+// transfer moves the input amount of wrapped native tokens from the caller to the input
+// destination.
+func transfer(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, TransferGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := WrappedNativeABI.UnpackInput("transfer", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	dst := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	amount := *abi.ConvertType(res[1], new(*big.Int)).(**big.Int)
+
+	if err := transferBalance(accessibleState, caller, dst, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := PackBoolOutput("transfer", true)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackTransferFrom packs [src], [dst] and [amount] into the input for transferFrom,
+// including the selector.
+func PackTransferFrom(src common.Address, dst common.Address, amount *big.Int) ([]byte, error) {
+	return WrappedNativeABI.Pack("transferFrom", src, dst, amount)
+}
+
+// transferFrom moves the input amount of wrapped native tokens from the input source to the
+// input destination. Unless the caller is the source, the amount is deducted from the
+// allowance of the caller, unless the allowance is the maximum uint256.
+func transferFrom(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, TransferFromGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	res, err := WrappedNativeABI.UnpackInput("transferFrom", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	src := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+	dst := *abi.ConvertType(res[1], new(common.Address)).(*common.Address)
+	amount := *abi.ConvertType(res[2], new(*big.Int)).(**big.Int)
+
+	if src != caller {
+		stateDB := accessibleState.GetStateDB()
+		allowance := GetAllowance(stateDB, src, caller)
+		if allowance.Cmp(math.MaxBig256) != 0 {
+			if allowance.Cmp(amount) < 0 {
+				return nil, remainingGas, fmt.Errorf("%w: %s can transfer %s from %s, need %s", ErrInsufficientAllowance, caller, allowance, src, amount)
+			}
+			stateDB.SetState(ContractAddress, allowanceKey(src, caller), common.BigToHash(new(big.Int).Sub(allowance, amount)))
+		}
+	}
+	if err := transferBalance(accessibleState, src, dst, amount); err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := PackBoolOutput("transferFrom", true)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// createWrappedNativePrecompile returns a StatefulPrecompiledContract implementing the
+// wrapped native token, with deposit as its fallback.
+func createWrappedNativePrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"allowance":    allowance,
+		"approve":      approve,
+		"balanceOf":    balanceOf,
+		"decimals":     decimals,
+		"deposit":      deposit,
+		"name":         name,
+		"symbol":       symbol,
+		"totalSupply":  totalSupply,
+		"transfer":     transfer,
+		"transferFrom": transferFrom,
+		"withdraw":     withdraw,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := WrappedNativeABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		if method.IsPayable() {
+			functions = append(functions, contract.NewPayableStatefulPrecompileFunction(method.ID, function))
+		} else {
+			functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+		}
+	}
+	// Native tokens sent without input are deposited.
+	statefulContract, err := contract.NewStatefulPrecompileContract(deposit, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
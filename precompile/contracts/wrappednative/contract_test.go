@@ -0,0 +1,242 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappednative
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/precompile/testutils"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	testOwner   = common.Address{'o'}
+	testSpender = common.Address{'s'}
+	testDst     = common.Address{'d'}
+	testAmount  = big.NewInt(100)
+)
+
+func setBalanceOf(account common.Address, balance *big.Int) func(t testing.TB, stateDB contract.StateDB) {
+	return func(t testing.TB, stateDB contract.StateDB) {
+		setBalance(stateDB, account, balance)
+	}
+}
+
+func setAllowanceOf(owner common.Address, spender common.Address, allowance *big.Int) func(t testing.TB, stateDB contract.StateDB) {
+	return func(t testing.TB, stateDB contract.StateDB) {
+		setBalance(stateDB, owner, testAmount)
+		stateDB.SetState(ContractAddress, allowanceKey(owner, spender), common.BigToHash(allowance))
+	}
+}
+
+func packedBoolOutput(method string) []byte {
+	res, err := PackBoolOutput(method, true)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func TestWrappedNativeRun(t *testing.T) {
+	tests := map[string]testutils.PrecompileTest{
+		"name defaults": {
+			Caller:      testOwner,
+			Config:      NewConfig(nil, "", ""),
+			Input:       func() []byte { input, _ := PackName(); return input }(),
+			SuppliedGas: NameGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte { res, _ := PackNameOutput(DefaultName); return res }(),
+		},
+		"configured symbol": {
+			Caller:      testOwner,
+			Config:      NewConfig(nil, "", "WAVAX"),
+			Input:       func() []byte { input, _ := PackSymbol(); return input }(),
+			SuppliedGas: SymbolGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte { res, _ := PackSymbolOutput("WAVAX"); return res }(),
+		},
+		"decimals": {
+			Caller:      testOwner,
+			Input:       func() []byte { input, _ := PackDecimals(); return input }(),
+			SuppliedGas: DecimalsGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte { res, _ := PackDecimalsOutput(18); return res }(),
+		},
+		"total supply is precompile balance": {
+			Caller: testOwner,
+			BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+				stateDB.AddBalance(ContractAddress, testAmount)
+			},
+			Input:       func() []byte { input, _ := PackTotalSupply(); return input }(),
+			SuppliedGas: TotalSupplyGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte { res, _ := PackTotalSupplyOutput(testAmount); return res }(),
+		},
+		"balance of": {
+			Caller:      testDst,
+			BeforeHook:  setBalanceOf(testOwner, testAmount),
+			Input:       func() []byte { input, _ := PackBalanceOf(testOwner); return input }(),
+			SuppliedGas: BalanceOfGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte { res, _ := PackBalanceOfOutput(testAmount); return res }(),
+		},
+		"approve": {
+			Caller:      testOwner,
+			Input:       func() []byte { input, _ := PackApprove(testSpender, testAmount); return input }(),
+			SuppliedGas: ApproveGasCost,
+			ReadOnly:    false,
+			ExpectedRes: packedBoolOutput("approve"),
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				require.Equal(t, testAmount, GetAllowance(stateDB, testOwner, testSpender))
+			},
+		},
+		"approve readOnly": {
+			Caller:      testOwner,
+			Input:       func() []byte { input, _ := PackApprove(testSpender, testAmount); return input }(),
+			SuppliedGas: ApproveGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"transfer": {
+			Caller:      testOwner,
+			BeforeHook:  setBalanceOf(testOwner, testAmount),
+			Input:       func() []byte { input, _ := PackTransfer(testDst, common.Big1); return input }(),
+			SuppliedGas: TransferGasCost,
+			ReadOnly:    false,
+			ExpectedRes: packedBoolOutput("transfer"),
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				require.Equal(t, new(big.Int).Sub(testAmount, common.Big1), GetBalance(stateDB, testOwner))
+				require.Equal(t, common.Big1, GetBalance(stateDB, testDst))
+			},
+		},
+		"transfer insufficient balance": {
+			Caller:      testOwner,
+			BeforeHook:  setBalanceOf(testOwner, common.Big1),
+			Input:       func() []byte { input, _ := PackTransfer(testDst, testAmount); return input }(),
+			SuppliedGas: TransferGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInsufficientBalance.Error(),
+		},
+		"transfer from with allowance": {
+			Caller:      testSpender,
+			BeforeHook:  setAllowanceOf(testOwner, testSpender, testAmount),
+			Input:       func() []byte { input, _ := PackTransferFrom(testOwner, testDst, common.Big1); return input }(),
+			SuppliedGas: TransferFromGasCost,
+			ReadOnly:    false,
+			ExpectedRes: packedBoolOutput("transferFrom"),
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				require.Equal(t, new(big.Int).Sub(testAmount, common.Big1), GetAllowance(stateDB, testOwner, testSpender))
+				require.Equal(t, common.Big1, GetBalance(stateDB, testDst))
+			},
+		},
+		"transfer from with max allowance": {
+			Caller:      testSpender,
+			BeforeHook:  setAllowanceOf(testOwner, testSpender, math.MaxBig256),
+			Input:       func() []byte { input, _ := PackTransferFrom(testOwner, testDst, testAmount); return input }(),
+			SuppliedGas: TransferFromGasCost,
+			ReadOnly:    false,
+			ExpectedRes: packedBoolOutput("transferFrom"),
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				require.Equal(t, math.MaxBig256, GetAllowance(stateDB, testOwner, testSpender))
+				require.Equal(t, testAmount, GetBalance(stateDB, testDst))
+			},
+		},
+		"transfer from own balance without allowance": {
+			Caller:      testOwner,
+			BeforeHook:  setBalanceOf(testOwner, testAmount),
+			Input:       func() []byte { input, _ := PackTransferFrom(testOwner, testDst, testAmount); return input }(),
+			SuppliedGas: TransferFromGasCost,
+			ReadOnly:    false,
+			ExpectedRes: packedBoolOutput("transferFrom"),
+		},
+		"transfer from insufficient allowance": {
+			Caller:      testSpender,
+			BeforeHook:  setAllowanceOf(testOwner, testSpender, common.Big1),
+			Input:       func() []byte { input, _ := PackTransferFrom(testOwner, testDst, testAmount); return input }(),
+			SuppliedGas: TransferFromGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInsufficientAllowance.Error(),
+		},
+		"withdraw insufficient balance": {
+			Caller:      testOwner,
+			BeforeHook:  setBalanceOf(testOwner, common.Big1),
+			Input:       func() []byte { input, _ := PackWithdraw(testAmount); return input }(),
+			SuppliedGas: WithdrawGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInsufficientBalance.Error(),
+		},
+		"insufficient gas": {
+			Caller:      testOwner,
+			BeforeHook:  setBalanceOf(testOwner, testAmount),
+			Input:       func() []byte { input, _ := PackTransfer(testDst, testAmount); return input }(),
+			SuppliedGas: TransferGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
+
+	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
+}
+
+func TestWrappedNativeReadOnly(t *testing.T) {
+	testutils.RunReadOnlyTests(t, Module, state.NewTestStateDB, testutils.ReadOnlyTest{
+		Caller:    testOwner,
+		Functions: testutils.ABIReadOnlyFunctions(t, WrappedNativeABI),
+	})
+}
+
+func TestWrappedNativeDepositWithdraw(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	stateDB := state.NewTestStateDB(t)
+	chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+	chainConfig.EXPECT().IsDUpgrade(gomock.Any()).Return(true).AnyTimes()
+	blockContext := contract.NewMockBlockContext(ctrl)
+	blockContext.EXPECT().Number().Return(common.Big1).AnyTimes()
+	blockContext.EXPECT().Timestamp().Return(uint64(0)).AnyTimes()
+	accessibleState := contract.NewMockAccessibleState(ctrl)
+	accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+	accessibleState.EXPECT().GetBlockContext().Return(blockContext).AnyTimes()
+	accessibleState.EXPECT().GetChainConfig().Return(chainConfig).AnyTimes()
+
+	// send credits [value] to the precompile, as the EVM does before running a payable function.
+	send := func(value *big.Int, input []byte, gas uint64) error {
+		stateDB.SubBalance(testOwner, value)
+		stateDB.AddBalance(ContractAddress, value)
+		_, remainingGas, err := WrappedNativePrecompile.Run(&testutils.PayableAccessibleState{AccessibleState: accessibleState, Value: value}, testOwner, ContractAddress, input, gas, false)
+		require.Zero(remainingGas)
+		return err
+	}
+
+	stateDB.AddBalance(testOwner, testAmount)
+	depositInput, err := PackDeposit()
+	require.NoError(err)
+	require.NoError(send(common.Big2, depositInput, DepositGasCost))
+	// Value sent without input is deposited by the fallback.
+	require.NoError(send(common.Big3, nil, DepositGasCost))
+	require.Equal(big.NewInt(5), GetBalance(stateDB, testOwner))
+	require.Equal(big.NewInt(5), stateDB.GetBalance(ContractAddress))
+	require.Equal(big.NewInt(95), stateDB.GetBalance(testOwner))
+
+	withdrawInput, err := PackWithdraw(big.NewInt(6))
+	require.NoError(err)
+	require.ErrorIs(send(common.Big0, withdrawInput, WithdrawGasCost), ErrInsufficientBalance)
+
+	withdrawInput, err = PackWithdraw(big.NewInt(4))
+	require.NoError(err)
+	require.NoError(send(common.Big0, withdrawInput, WithdrawGasCost))
+	require.Equal(common.Big1, GetBalance(stateDB, testOwner))
+	require.Equal(common.Big1, stateDB.GetBalance(ContractAddress))
+	require.Equal(big.NewInt(99), stateDB.GetBalance(testOwner))
+	require.Len(stateDB.(*state.StateDB).Logs(), 3)
+}
@@ -52,6 +52,19 @@ type Predicater interface {
 	VerifyPredicate(predicateContext *PredicateContext, predicates [][]byte) []byte
 }
 
+// PChainHeightResultsKey is the key under which the P-Chain height a transaction's predicates were
+// verified against is recorded in the predicate results of the transaction. The zero address is never
+// a precompile address, so it cannot collide with the results of a Predicater.
+var PChainHeightResultsKey = common.Address{}
+
+// PChainHeightRecorder is an optional interface for Predicaters to implement.
+// If RecordsPChainHeight returns true, the ProposerVM P-Chain height that the transaction's predicates
+// were verified against is recorded in the predicate results of the transaction under
+// [PChainHeightResultsKey], so that it can be read during EVM execution.
+type PChainHeightRecorder interface {
+	RecordsPChainHeight() bool
+}
+
 // SharedMemoryWriter defines an interface to allow a precompile's Accepter to write operations
 // into shared memory to be committed atomically on block accept.
 type SharedMemoryWriter interface {
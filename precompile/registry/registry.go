@@ -18,6 +18,20 @@ import (
 	_ "github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
 
 	_ "github.com/ava-labs/subnet-evm/x/warp"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/governance"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/wrappednative"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/deliveryfee"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/gassponsor"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/create2deployer"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/tokenmulticall"
+
+	_ "github.com/ava-labs/subnet-evm/precompile/contracts/nativedistributor"
 	// ADD YOUR PRECOMPILE HERE
 	// _ "github.com/ava-labs/subnet-evm/precompile/contracts/yourprecompile"
 )
@@ -30,7 +44,7 @@ import (
 // subnet-evm without issue.
 // These start at the address: 0x0100000000000000000000000000000000000000 and will increment by 1.
 // Optional precompiles implemented in subnet-evm start at 0x0200000000000000000000000000000000000000 and will increment by 1
-// from here to reduce the risk of conflicts.
+// from here to reduce the risk of conflicts. Experimental precompiles start at 0x0200000000000000000000000000000000000080.
 // For forks of subnet-evm, users should start at 0x0300000000000000000000000000000000000000 to ensure
 // that their own modifications do not conflict with stateful precompiles that may be added to subnet-evm
 // in the future.
@@ -40,5 +54,12 @@ import (
 // FeeManagerAddress                = common.HexToAddress("0x0200000000000000000000000000000000000003")
 // RewardManagerAddress             = common.HexToAddress("0x0200000000000000000000000000000000000004")
 // WarpAddress                      = common.HexToAddress("0x0200000000000000000000000000000000000005")
+// GovernanceAddress                = common.HexToAddress("0x0200000000000000000000000000000000000006")
+// WrappedNativeAddress             = common.HexToAddress("0x0200000000000000000000000000000000000007")
+// DeliveryFeeAddress               = common.HexToAddress("0x0200000000000000000000000000000000000008")
+// GasSponsorAddress                = common.HexToAddress("0x0200000000000000000000000000000000000009")
+// Create2DeployerAddress           = common.HexToAddress("0x020000000000000000000000000000000000000a")
+// TokenMulticallAddress            = common.HexToAddress("0x020000000000000000000000000000000000000b")
+// NativeDistributorAddress         = common.HexToAddress("0x020000000000000000000000000000000000000c")
 // ADD YOUR PRECOMPILE HERE
 // {YourPrecompile}Address          = common.HexToAddress("0x03000000000000000000000000000000000000??")
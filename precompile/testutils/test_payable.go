@@ -0,0 +1,22 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testutils
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+)
+
+var _ contract.PayableAccessibleState = &PayableAccessibleState{}
+
+// PayableAccessibleState is the AccessibleState of a call transferring [Value] to the
+// precompile, for testing payable functions outside of the EVM.
+type PayableAccessibleState struct {
+	contract.AccessibleState
+	Value *big.Int
+}
+
+// GetCallValue returns the value transferred to the precompile by the call.
+func (s *PayableAccessibleState) GetCallValue() *big.Int { return s.Value }
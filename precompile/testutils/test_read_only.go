@@ -0,0 +1,109 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testutils
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// readOnlyTestGas is the gas supplied to each function called by RunReadOnlyTests.
+const readOnlyTestGas = 10_000_000
+
+// ReadOnlyFunction is a precompile function called by RunReadOnlyTests.
+type ReadOnlyFunction struct {
+	// Input is the raw input to the function, including its selector
+	Input []byte
+	// Writes is whether the function modifies state, in which case it must fail with
+	// vmerrs.ErrWriteProtection in readOnly mode. Otherwise it must succeed.
+	Writes bool
+}
+
+// ReadOnlyTest checks that every function of a precompile respects readOnly mode.
+type ReadOnlyTest struct {
+	// Caller is the address calling each function. It should be permitted to call every
+	// function so that calls only fail due to readOnly mode.
+	Caller common.Address
+	// BeforeHook is called before each function is called.
+	BeforeHook func(t testing.TB, state contract.StateDB)
+	// SetupBlockContext sets the expected calls on MockBlockContext for each call.
+	SetupBlockContext func(*contract.MockBlockContext)
+	// Functions maps the name of every function registered by the precompile to the
+	// input it is called with. See ABIReadOnlyFunctions.
+	Functions map[string]ReadOnlyFunction
+}
+
+// ABIReadOnlyFunctions returns a ReadOnlyFunction for every method of [contractABI],
+// called with zero values for each argument. Methods that are not view or pure are
+// expected to write.
+func ABIReadOnlyFunctions(t testing.TB, contractABI abi.ABI) map[string]ReadOnlyFunction {
+	functions := make(map[string]ReadOnlyFunction, len(contractABI.Methods))
+	for name, method := range contractABI.Methods {
+		args := make([]interface{}, len(method.Inputs))
+		for i, input := range method.Inputs {
+			args[i] = zeroValue(input.Type.GetType())
+		}
+		input, err := contractABI.Pack(name, args...)
+		require.NoError(t, err, "failed to pack zero input for %s", name)
+		functions[name] = ReadOnlyFunction{
+			Input:  input,
+			Writes: !method.IsConstant(),
+		}
+	}
+	return functions
+}
+
+// zeroValue returns the zero value of [typ], allocating the value pointed to by pointer
+// types so that they can be packed.
+func zeroValue(typ reflect.Type) interface{} {
+	if typ.Kind() == reflect.Ptr {
+		return reflect.New(typ.Elem()).Interface()
+	}
+	return reflect.Zero(typ).Interface()
+}
+
+// RunReadOnlyTests calls every function registered by [module] in readOnly mode and checks
+// that functions that write fail with vmerrs.ErrWriteProtection and all other functions
+// succeed. Fails if [test] does not include every registered function.
+func RunReadOnlyTests(t *testing.T, module modules.Module, newStateDB func(t testing.TB) contract.StateDB, test ReadOnlyTest) {
+	t.Helper()
+
+	selectors := contract.FunctionSelectors(module.Contract)
+	require.NotEmpty(t, selectors, "precompile does not register functions by selector")
+	for _, selector := range selectors {
+		name := contract.FunctionName(selector)
+		function, ok := test.Functions[name]
+		require.True(t, ok, "missing read only test for function %s", name)
+		require.True(t, bytes.HasPrefix(function.Input, selector), "input for function %s does not have its selector", name)
+	}
+
+	for name, function := range test.Functions {
+		function := function
+		t.Run(name, func(t *testing.T) {
+			runParams := PrecompileTest{
+				Caller:            test.Caller,
+				Input:             function.Input,
+				SuppliedGas:       readOnlyTestGas,
+				ReadOnly:          true,
+				BeforeHook:        test.BeforeHook,
+				SetupBlockContext: test.SetupBlockContext,
+			}.setup(t, module, newStateDB(t))
+
+			_, _, err := module.Contract.Run(runParams.AccessibleState, runParams.Caller, runParams.ContractAddress, runParams.Input, runParams.SuppliedGas, runParams.ReadOnly)
+			if function.Writes {
+				require.ErrorIs(t, err, vmerrs.ErrWriteProtection)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
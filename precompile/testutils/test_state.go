@@ -0,0 +1,141 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testutils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// TestState describes the balances and storage of a set of accounts.
+type TestState struct {
+	// Balances maps addresses to their balance
+	Balances map[common.Address]*big.Int
+	// Storage maps addresses to the values of their storage slots
+	Storage map[common.Address]map[common.Hash]common.Hash
+}
+
+// apply writes [s] to [state].
+func (s *TestState) apply(state contract.StateDB) {
+	for addr, balance := range s.Balances {
+		state.SubBalance(addr, state.GetBalance(addr))
+		state.AddBalance(addr, balance)
+	}
+	for addr, storage := range s.Storage {
+		for key, value := range storage {
+			state.SetState(addr, key, value)
+		}
+	}
+}
+
+// check fails [t] if [state] does not match [s]. Accounts and slots that are not
+// included in [s] are not checked.
+func (s *TestState) check(t testing.TB, state contract.StateDB) {
+	for addr, balance := range s.Balances {
+		require.Zero(t, balance.Cmp(state.GetBalance(addr)), "balance of %s: expected %s, got %s", addr, balance, state.GetBalance(addr))
+	}
+	for addr, storage := range s.Storage {
+		for key, value := range storage {
+			require.Equal(t, value, state.GetState(addr, key), "storage slot %s of %s", key, addr)
+		}
+	}
+}
+
+// TestLog is a log expected to be emitted by a precompile.
+type TestLog struct {
+	Address common.Address
+	Topics  []common.Hash
+	Data    []byte
+}
+
+// StateTest is a precompile test case described by the state before and after the call,
+// so that it can be used to test any module without precompile specific hooks.
+type StateTest struct {
+	// Caller is the address of the precompile caller
+	Caller common.Address
+	// Input the raw input bytes to the precompile
+	Input []byte
+	// SuppliedGas is the amount of gas supplied to the precompile
+	SuppliedGas uint64
+	// ReadOnly is whether the precompile should be called in read only mode
+	ReadOnly bool
+	// Config is the config to use for the precompile. If nil, Configure will not be called.
+	Config precompileconfig.Config
+	// ChainConfig is the chain config to use for the precompile's block context
+	// If nil, the default chain config will be used.
+	ChainConfig precompileconfig.ChainConfig
+	// SetupBlockContext sets the expected calls on MockBlockContext for the test execution.
+	SetupBlockContext func(*contract.MockBlockContext)
+	// PreState is written to the state before the precompile is called
+	PreState *TestState
+	// ExpectedState is checked against the state after the precompile is called
+	ExpectedState *TestState
+	// ExpectedLogs are the logs expected to be emitted by the precompile, in order.
+	// If nil, logs are not checked.
+	ExpectedLogs []TestLog
+	// ExpectedRes is the expected raw byte result returned by the precompile
+	ExpectedRes []byte
+	// ExpectedErr is the expected error returned by the precompile
+	ExpectedErr string
+}
+
+// PrecompileTest returns the PrecompileTest performing [test].
+func (test StateTest) PrecompileTest() PrecompileTest {
+	return PrecompileTest{
+		Caller:            test.Caller,
+		Input:             test.Input,
+		SuppliedGas:       test.SuppliedGas,
+		ReadOnly:          test.ReadOnly,
+		Config:            test.Config,
+		ChainConfig:       test.ChainConfig,
+		SetupBlockContext: test.SetupBlockContext,
+		ExpectedRes:       test.ExpectedRes,
+		ExpectedErr:       test.ExpectedErr,
+		BeforeHook: func(t testing.TB, state contract.StateDB) {
+			if test.PreState != nil {
+				test.PreState.apply(state)
+			}
+		},
+		AfterHook: func(t testing.TB, state contract.StateDB) {
+			if test.ExpectedState != nil {
+				test.ExpectedState.check(t, state)
+			}
+			if test.ExpectedLogs != nil {
+				requireLogs(t, test.ExpectedLogs, state)
+			}
+		},
+	}
+}
+
+// requireLogs fails [t] if the logs emitted to [state] do not match [expected].
+func requireLogs(t testing.TB, expected []TestLog, state contract.StateDB) {
+	logState, ok := state.(interface{ Logs() []*types.Log })
+	require.True(t, ok, "state %T does not record logs", state)
+
+	logs := logState.Logs()
+	require.Len(t, logs, len(expected))
+	for i, log := range logs {
+		require.Equal(t, expected[i].Address, log.Address, "address of log %d", i)
+		require.Equal(t, expected[i].Topics, log.Topics, "topics of log %d", i)
+		require.Equal(t, expected[i].Data, log.Data, "data of log %d", i)
+	}
+}
+
+// RunStateTests runs [stateTests] against [module], each with a new state from [newStateDB].
+func RunStateTests(t *testing.T, module modules.Module, newStateDB func(t testing.TB) contract.StateDB, stateTests map[string]StateTest) {
+	t.Helper()
+
+	tests := make(map[string]PrecompileTest, len(stateTests))
+	for name, test := range stateTests {
+		tests[name] = test.PrecompileTest()
+	}
+	RunPrecompileTests(t, module, newStateDB, tests)
+}
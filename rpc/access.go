@@ -0,0 +1,102 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// AccessPolicy restricts which clients may call the methods of a namespace. Empty
+// fields impose no restriction.
+type AccessPolicy struct {
+	// AllowedOrigins is the list of origins browsers may call the namespace from.
+	// Requests without an Origin header (i.e. non-browser clients) are not affected.
+	// "*" allows any origin.
+	AllowedOrigins []string
+	// VirtualHosts is the list of host names the namespace may be called through.
+	// Requests addressed to an IP are not affected. "*" allows any host.
+	VirtualHosts []string
+	// AllowedSubnets is the list of client IP ranges that may call the namespace.
+	AllowedSubnets []netip.Prefix
+}
+
+// ParseAccessPolicy returns an AccessPolicy allowing [origins], [vhosts] and clients
+// within the CIDR ranges or addresses in [allowedIPs].
+func ParseAccessPolicy(origins, vhosts, allowedIPs []string) (*AccessPolicy, error) {
+	policy := &AccessPolicy{
+		AllowedOrigins: origins,
+		VirtualHosts:   vhosts,
+	}
+	for _, ip := range allowedIPs {
+		if !strings.Contains(ip, "/") {
+			addr, err := netip.ParseAddr(ip)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed ip %q: %w", ip, err)
+			}
+			policy.AllowedSubnets = append(policy.AllowedSubnets, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(ip)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed subnet %q: %w", ip, err)
+		}
+		policy.AllowedSubnets = append(policy.AllowedSubnets, prefix.Masked())
+	}
+	return policy, nil
+}
+
+// check returns an error if the client described by [info] is not permitted by [p].
+func (p *AccessPolicy) check(info PeerInfo) error {
+	if origin := info.HTTP.Origin; origin != "" && len(p.AllowedOrigins) > 0 && !matchesAny(p.AllowedOrigins, origin) {
+		return fmt.Errorf("origin %s not allowed", origin)
+	}
+	if len(p.VirtualHosts) > 0 && info.HTTP.Host != "" {
+		host := info.HTTP.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if _, err := netip.ParseAddr(host); err != nil && !matchesAny(p.VirtualHosts, host) {
+			return fmt.Errorf("host %s not allowed", host)
+		}
+	}
+	if len(p.AllowedSubnets) > 0 {
+		addr, err := remoteAddr(info.RemoteAddr)
+		if err != nil {
+			return err
+		}
+		for _, subnet := range p.AllowedSubnets {
+			if subnet.Contains(addr) {
+				return nil
+			}
+		}
+		return fmt.Errorf("client ip %s not allowed", addr)
+	}
+	return nil
+}
+
+// matchesAny returns true if [value] case insensitively equals an entry of [allowed]
+// or [allowed] contains "*".
+func matchesAny(allowed []string, value string) bool {
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(a, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr parses the IP of a client from [addr], which may include a port.
+func remoteAddr(addr string) (netip.Addr, error) {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("could not determine client ip from %q", addr)
+	}
+	return ip.Unmap(), nil
+}
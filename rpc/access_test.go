@@ -0,0 +1,92 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"testing"
+)
+
+func TestAccessPolicyCheck(t *testing.T) {
+	policy, err := ParseAccessPolicy(
+		[]string{"https://app.example.com"},
+		[]string{"rpc.example.com"},
+		[]string{"10.0.0.0/8", "192.168.1.1"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newPeer := func(remoteAddr, origin, host string) PeerInfo {
+		info := PeerInfo{Transport: "http", RemoteAddr: remoteAddr}
+		info.HTTP.Origin = origin
+		info.HTTP.Host = host
+		return info
+	}
+	tests := []struct {
+		name    string
+		info    PeerInfo
+		allowed bool
+	}{
+		{"allowed subnet", newPeer("10.1.2.3:1234", "", "rpc.example.com"), true},
+		{"allowed ip", newPeer("192.168.1.1:1234", "", ""), true},
+		{"ipv4 mapped ipv6", newPeer("[::ffff:10.1.2.3]:1234", "", ""), true},
+		{"ip not allowed", newPeer("192.168.1.2:1234", "", ""), false},
+		{"allowed origin", newPeer("10.1.2.3:1234", "https://app.example.com", ""), true},
+		{"origin not allowed", newPeer("10.1.2.3:1234", "https://evil.example.com", ""), false},
+		{"host with port", newPeer("10.1.2.3:1234", "", "RPC.example.com:9650"), true},
+		{"host not allowed", newPeer("10.1.2.3:1234", "", "other.example.com"), false},
+		{"ip host", newPeer("10.1.2.3:1234", "", "10.0.0.1:9650"), true},
+		{"unknown remote", newPeer("", "", ""), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := policy.check(test.info)
+			if test.allowed && err != nil {
+				t.Fatalf("expected access to be allowed, got %v", err)
+			}
+			if !test.allowed && err == nil {
+				t.Fatal("expected access to be denied")
+			}
+		})
+	}
+}
+
+func TestParseAccessPolicyInvalid(t *testing.T) {
+	if _, err := ParseAccessPolicy(nil, nil, []string{"10.0.0.0/33"}); err == nil {
+		t.Fatal("expected invalid subnet error")
+	}
+	if _, err := ParseAccessPolicy(nil, nil, []string{"not-an-ip"}); err == nil {
+		t.Fatal("expected invalid ip error")
+	}
+}
+
+func TestServerAccessPolicy(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	policy, err := ParseAccessPolicy(nil, nil, []string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.SetAccessPolicy("test", policy)
+
+	// In-process clients have no remote IP, so calls to the restricted
+	// namespace are denied while other namespaces remain reachable.
+	client := DialInProc(server)
+	defer client.Close()
+	var result echoResult
+	err = client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"})
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != errcodeUnauthorized {
+		t.Fatalf("expected access denied error, got %v", err)
+	}
+	var modules map[string]string
+	if err := client.Call(&modules, "rpc_modules"); err != nil {
+		t.Fatalf("unexpected error calling unrestricted method: %v", err)
+	}
+
+	server.SetAccessPolicy("test", nil)
+	if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatalf("unexpected error after removing policy: %v", err)
+	}
+}
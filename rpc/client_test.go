@@ -29,6 +29,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
@@ -127,6 +128,28 @@ func TestClientErrorData(t *testing.T) {
 	}
 }
 
+func TestClientErrorRequestID(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var resp interface{}
+	if err := client.Call(&resp, "test_echo", "hello", 10); err != nil {
+		t.Fatal(err)
+	}
+	err := client.Call(&resp, "no_such_method")
+	if err == nil {
+		t.Fatal("no error returned")
+	}
+	// The successful call is assigned the first request ID.
+	if e, ok := err.(RequestIDError); !ok {
+		t.Fatalf("client did not return rpc.RequestIDError, got %#v", err)
+	} else if id := e.ErrorRequestID(); id != "0x2" {
+		t.Fatalf("wrong request ID %q, want %q", id, "0x2")
+	}
+}
+
 func TestClientBatchRequest(t *testing.T) {
 	server := newTestServer()
 	defer server.Stop()
@@ -168,7 +191,7 @@ func TestClientBatchRequest(t *testing.T) {
 			Method: "no_such_method",
 			Args:   []interface{}{1, 2, 3},
 			Result: new(int),
-			Error:  &jsonError{Code: -32601, Message: "the method no_such_method does not exist/is not available"},
+			Error:  &jsonError{Code: -32601, Message: "the method no_such_method does not exist/is not available", Data: map[string]interface{}{"requestId": "0x3"}},
 		},
 	}
 	if !reflect.DeepEqual(batch, wantResult) {
@@ -358,6 +381,32 @@ func TestClientSubscribe(t *testing.T) {
 	}
 }
 
+// This test checks that an error sent by the server ends the subscription of the client.
+func TestClientSubscribeError(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	nc := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", nc, "errorSubscription", 7)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	if val := <-nc; val != 7 {
+		t.Fatalf("value mismatch: got %d, want %d", val, 7)
+	}
+	select {
+	case err := <-sub.Err():
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != (testError{}).ErrorCode() {
+			t.Fatalf("wrong error: got %v, want %v", err, testError{})
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("subscription not ended within 1s after the server error")
+	}
+}
+
 // In this test, the connection drops while Subscribe is waiting for a response.
 func TestClientSubscribeClose(t *testing.T) {
 	server := newTestServer()
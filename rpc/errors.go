@@ -55,6 +55,14 @@ type DataError interface {
 	ErrorData() interface{} // returns the error data
 }
 
+// A RequestIDError is returned by client operations when the server assigned an ID
+// to the failed call, which the server includes in its logs of the call. The ID is
+// only returned for errors without data of their own.
+type RequestIDError interface {
+	Error() string          // returns the message
+	ErrorRequestID() string // returns the request ID
+}
+
 // Error types defined below are the built-in JSON-RPC errors.
 
 var (
@@ -71,6 +79,8 @@ const (
 	errcodeDefault                  = -32000
 	errcodeNotificationsUnsupported = -32001
 	errcodeTimeout                  = -32002
+	errcodeUnauthorized             = -32003
+	errcodeLimitExceeded            = -32005
 	errcodePanic                    = -32603
 	errcodeMarshalError             = -32603
 )
@@ -123,6 +133,47 @@ func (e *invalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *invalidParamsError) Error() string { return e.message }
 
+// unauthorizedError is returned when a method of a restricted namespace is called
+// without valid credentials.
+type unauthorizedError struct{ method string }
+
+func (e *unauthorizedError) ErrorCode() int { return errcodeUnauthorized }
+
+func (e *unauthorizedError) Error() string {
+	return fmt.Sprintf("the method %s requires authentication", e.method)
+}
+
+// accessDeniedError is returned when a client is not permitted to call a method by
+// the access policy of its namespace.
+type accessDeniedError struct {
+	method string
+	reason error
+}
+
+func (e *accessDeniedError) ErrorCode() int { return errcodeUnauthorized }
+
+func (e *accessDeniedError) Error() string {
+	return fmt.Sprintf("access to the method %s denied: %v", e.method, e.reason)
+}
+
+// quotaError is returned when a client has exhausted the quota of its API key, or
+// does not present a known API key when one is required.
+type quotaError struct {
+	method string
+	reason error
+}
+
+func (e *quotaError) ErrorCode() int {
+	if e.reason == errUnknownAPIKey {
+		return errcodeUnauthorized
+	}
+	return errcodeLimitExceeded
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("call to the method %s rejected: %v", e.method, e.reason)
+}
+
 // internalServerError is used for server errors during request processing.
 type internalServerError struct {
 	code    int
@@ -464,9 +464,17 @@ func (h *handler) handleSubscriptionResult(msg *jsonrpcMessage) {
 		h.log.Debug("Dropping invalid subscription message")
 		return
 	}
-	if h.clientSubs[result.ID] != nil {
-		h.clientSubs[result.ID].deliver(result.Result)
+	sub := h.clientSubs[result.ID]
+	if sub == nil {
+		return
+	}
+	if result.Error != nil {
+		// The server ended the subscription.
+		delete(h.clientSubs, result.ID)
+		sub.close(result.Error)
+		return
 	}
+	sub.deliver(result.Result)
 }
 
 // handleResponse processes method call responses.
@@ -511,17 +519,39 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 	switch {
 	case msg.isNotification():
-		h.handleCall(ctx, msg)
+		h.handleCall(ctx.ctx, ctx, msg)
 		h.log.Debug("Served "+msg.Method, "execTime", time.Since(execStart), "procTime", time.Since(procStart), "totalTime", time.Since(callStart))
 		return nil
 	case msg.isCall():
-		resp := h.handleCall(ctx, msg)
+		// The ID assigned to the call is available to the method through its context,
+		// and is returned to the client as the data of the error if the call fails
+		// without error data of its own.
+		requestID := h.reg.newRequestID()
+		callCtx := withRequestID(ctx.ctx, requestID)
+		slowQueryThreshold := h.reg.getSlowQueryThreshold()
+		var slowQuery *slowQueryInfo
+		if slowQueryThreshold > 0 {
+			callCtx, slowQuery = withSlowQueryInfo(callCtx)
+		}
+		resp := h.handleCall(callCtx, ctx, msg)
+		if execTime := time.Since(execStart); slowQuery != nil && execTime > slowQueryThreshold {
+			slowQueryCounter.Inc(1)
+			h.log.Warn("Slow RPC call", slowQuery.logCtx(
+				"method", msg.Method,
+				"requestID", requestID,
+				"params", summarizeParams(msg.Params),
+				"execTime", execTime,
+				"failed", resp.Error != nil,
+			)...)
+		}
 		var ctx []interface{}
-		ctx = append(ctx, "reqid", idForLog{msg.ID}, "execTime", time.Since(execStart), "procTime", time.Since(procStart), "totalTime", time.Since(callStart))
+		ctx = append(ctx, "reqid", idForLog{msg.ID}, "requestID", requestID, "execTime", time.Since(execStart), "procTime", time.Since(procStart), "totalTime", time.Since(callStart))
 		if resp.Error != nil {
 			ctx = append(ctx, "err", resp.Error.Message)
 			if resp.Error.Data != nil {
 				ctx = append(ctx, "errdata", resp.Error.Data)
+			} else {
+				resp.Error.Data = &requestIDData{RequestID: requestID}
 			}
 			h.log.Info("Served "+msg.Method, ctx...)
 		} else {
@@ -535,10 +565,22 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	}
 }
 
-// handleCall processes method calls.
-func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+// handleCall processes method calls. The method is run with [ctx], which is derived
+// from the context of [cp].
+func (h *handler) handleCall(ctx context.Context, cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	peerInfo := PeerInfoFromContext(ctx)
+	if h.reg.isRestricted(msg.Method) && !peerInfo.Authenticated {
+		return msg.errorResponse(&unauthorizedError{method: msg.Method})
+	}
+	if err := h.reg.checkAccess(msg.Method, peerInfo); err != nil {
+		return msg.errorResponse(&accessDeniedError{method: msg.Method, reason: err})
+	}
+	if err := h.reg.checkQuota(msg.Method, peerInfo); err != nil {
+		quotaExceededCounter.Inc(1)
+		return msg.errorResponse(&quotaError{method: msg.Method, reason: err})
+	}
 	if msg.isSubscribe() {
-		return h.handleSubscribe(cp, msg)
+		return h.handleSubscribe(ctx, cp, msg)
 	}
 	var callb *callback
 	if msg.isUnsubscribe() {
@@ -554,7 +596,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args)
+	answer := h.runMethod(ctx, msg, callb, args)
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
 	if callb != h.unsubscribeCb {
@@ -573,7 +615,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 }
 
 // handleSubscribe processes *_subscribe method calls.
-func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+func (h *handler) handleSubscribe(ctx context.Context, cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	if !h.allowSubscribe {
 		return msg.errorResponse(&internalServerError{
 			code:    errcodeNotificationsUnsupported,
@@ -581,6 +623,20 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 		})
 	}
 
+	if limiter, ok := h.conn.(subscriptionLimiter); ok {
+		if max := limiter.maxSubscriptions(); max > 0 {
+			h.subLock.Lock()
+			active := len(h.serverSubs)
+			h.subLock.Unlock()
+			if active >= max {
+				return msg.errorResponse(&internalServerError{
+					code:    errcodeLimitExceeded,
+					message: ErrSubscriptionLimitExceeded.Error(),
+				})
+			}
+		}
+	}
+
 	// Subscription method name is first argument.
 	name, err := parseSubscriptionName(msg.Params)
 	if err != nil {
@@ -603,7 +659,7 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	// Install notifier in context so the subscription handler can find it.
 	n := &Notifier{h: h, namespace: namespace}
 	cp.notifiers = append(cp.notifiers, n)
-	ctx := context.WithValue(cp.ctx, notifierKey{}, n)
+	ctx = context.WithValue(ctx, notifierKey{}, n)
 
 	return h.runMethod(ctx, msg, callb, args)
 }
@@ -54,6 +54,8 @@ var null = json.RawMessage("null")
 type subscriptionResult struct {
 	ID     string          `json:"subscription"`
 	Result json.RawMessage `json:"result,omitempty"`
+	// Error ends the subscription, see Notifier.Error.
+	Error *jsonError `json:"error,omitempty"`
 }
 
 // A value of this type can a JSON-RPC request, notification, successful response or
@@ -141,6 +143,12 @@ type jsonError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// requestIDData is the data of an error without data of its own, holding the ID assigned
+// by the server to the failed call, which is included in the server logs of the call.
+type requestIDData struct {
+	RequestID string `json:"requestId"`
+}
+
 func (err *jsonError) Error() string {
 	if err.Message == "" {
 		return fmt.Sprintf("json-rpc error %d", err.Code)
@@ -156,6 +164,19 @@ func (err *jsonError) ErrorData() interface{} {
 	return err.Data
 }
 
+// ErrorRequestID returns the ID assigned by the server to the failed call, if any.
+func (err *jsonError) ErrorRequestID() string {
+	switch data := err.Data.(type) {
+	case *requestIDData:
+		return data.RequestID
+	case map[string]interface{}:
+		id, _ := data["requestId"].(string)
+		return id
+	default:
+		return ""
+	}
+}
+
 // Conn is a subset of the methods of net.Conn which are sufficient for ServerCodec.
 type Conn interface {
 	io.ReadWriteCloser
@@ -0,0 +1,83 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWTSecretLength is the required length of the shared secret used to sign tokens.
+const JWTSecretLength = 32
+
+// jwtIssuedAtWindow is the maximum difference allowed between the issued-at claim of a
+// token and the local time.
+const jwtIssuedAtWindow = 60 * time.Second
+
+var (
+	errMissingToken      = errors.New("missing token")
+	errInvalidSecretSize = fmt.Errorf("jwt secret must be %d bytes", JWTSecretLength)
+	errMissingIssuedAt   = errors.New("missing issued-at")
+	errStaleToken        = errors.New("stale token")
+	errFutureToken       = errors.New("future token")
+)
+
+// NewJWTAuthenticator returns an authenticator for [Server.SetAuthentication] that
+// requires requests to carry an "Authorization: Bearer <token>" header, where the token
+// is a JWT signed with HS256 using [secret] whose issued-at claim is within 60 seconds
+// of the local time.
+func NewJWTAuthenticator(secret []byte) (func(http.Header) error, error) {
+	if len(secret) != JWTSecretLength {
+		return nil, errInvalidSecretSize
+	}
+	keyFunc := func(token *jwt.Token) (interface{}, error) { return secret, nil }
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	return func(header http.Header) error {
+		auth := header.Get("Authorization")
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || len(token) == 0 {
+			return errMissingToken
+		}
+		var claims jwt.RegisteredClaims
+		if _, err := parser.ParseWithClaims(token, &claims, keyFunc); err != nil {
+			return err
+		}
+		if claims.IssuedAt == nil {
+			return errMissingIssuedAt
+		}
+		now := time.Now()
+		if claims.IssuedAt.Before(now.Add(-jwtIssuedAtWindow)) {
+			return errStaleToken
+		}
+		if claims.IssuedAt.After(now.Add(jwtIssuedAtWindow)) {
+			return errFutureToken
+		}
+		return nil
+	}, nil
+}
+
+// NewJWTAuth returns an HTTPAuth that authenticates requests to servers using
+// [NewJWTAuthenticator] with [secret], by signing a fresh token for each request.
+func NewJWTAuth(secret []byte) (HTTPAuth, error) {
+	if len(secret) != JWTSecretLength {
+		return nil, errInvalidSecretSize
+	}
+	return func(header http.Header) error {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		})
+		signed, err := token.SignedString(secret)
+		if err != nil {
+			return fmt.Errorf("failed to sign jwt: %w", err)
+		}
+		header.Set("Authorization", "Bearer "+signed)
+		return nil
+	}, nil
+}
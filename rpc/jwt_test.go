@@ -0,0 +1,128 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func issueTestToken(t *testing.T, secret []byte, issuedAt time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)})
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	secret := make([]byte, JWTSecretLength)
+	secret[0] = 1
+	otherSecret := make([]byte, JWTSecretLength)
+
+	if _, err := NewJWTAuthenticator(secret[:16]); err != errInvalidSecretSize {
+		t.Fatalf("expected %v, got %v", errInvalidSecretSize, err)
+	}
+	authenticate, err := NewJWTAuthenticator(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name    string
+		header  string
+		success bool
+	}{
+		{"valid", "Bearer " + issueTestToken(t, secret, now), true},
+		{"missing header", "", false},
+		{"missing bearer prefix", issueTestToken(t, secret, now), false},
+		{"wrong secret", "Bearer " + issueTestToken(t, otherSecret, now), false},
+		{"stale", "Bearer " + issueTestToken(t, secret, now.Add(-2*jwtIssuedAtWindow)), false},
+		{"future", "Bearer " + issueTestToken(t, secret, now.Add(2*jwtIssuedAtWindow)), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://url.com", nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+			err := authenticate(req.Header)
+			if test.success && err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+			if !test.success && err == nil {
+				t.Fatal("expected failure")
+			}
+		})
+	}
+}
+
+func TestServerAuthentication(t *testing.T) {
+	secret := make([]byte, JWTSecretLength)
+	authenticate, err := NewJWTAuthenticator(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer()
+	defer server.Stop()
+	server.SetAuthentication(authenticate, []string{"test"})
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	// Methods of restricted namespaces fail without a token.
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var result echoResult
+	err = client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"})
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != errcodeUnauthorized {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+	// Methods of other namespaces remain public.
+	var modules map[string]string
+	if err := client.Call(&modules, "rpc_modules"); err != nil {
+		t.Fatalf("unexpected error calling public method: %v", err)
+	}
+
+	// Restricted methods succeed with a valid token.
+	client.SetHeader("Authorization", "Bearer "+issueTestToken(t, secret, time.Now()))
+	if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatalf("unexpected error with valid token: %v", err)
+	}
+	if result.String != "hello" {
+		t.Fatalf("wrong result: %v", result)
+	}
+}
+
+func TestJWTAuth(t *testing.T) {
+	secret := make([]byte, JWTSecretLength)
+	secret[0] = 1
+	if _, err := NewJWTAuth(secret[:16]); err != errInvalidSecretSize {
+		t.Fatalf("expected %v, got %v", errInvalidSecretSize, err)
+	}
+	auth, err := NewJWTAuth(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticate, err := NewJWTAuthenticator(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "http://url.com", nil)
+	if err := auth(req.Header); err != nil {
+		t.Fatal(err)
+	}
+	if err := authenticate(req.Header); err != nil {
+		t.Fatalf("expected token to be accepted, got %v", err)
+	}
+}
@@ -42,6 +42,13 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	wsConnectionsRejectedCounter = metrics.NewRegisteredCounter("rpc/ws/connections/rejected", nil)
+	wsSlowConsumerCounter        = metrics.NewRegisteredCounter("rpc/ws/slowconsumer", nil)
+
+	quotaExceededCounter = metrics.NewRegisteredCounter("rpc/quota/rejected", nil)
+
+	slowQueryCounter = metrics.NewRegisteredCounter("rpc/slow", nil)
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
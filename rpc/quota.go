@@ -0,0 +1,206 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	errUnknownAPIKey        = errors.New("missing or unknown api key")
+	errRequestQuotaExceeded = errors.New("request quota exceeded")
+	errComputeQuotaExceeded = errors.New("compute unit quota exceeded")
+	errMissingAPIKeyHeader  = errors.New("api keys must be read from a header")
+	errInvalidQuotaRate     = errors.New("quota rates must be non-negative")
+	errInvalidQuotaBurst    = errors.New("quota bursts must be non-negative")
+	errComputeBurstTooLow   = errors.New("compute unit burst is lower than the cost of a method")
+)
+
+// defaultMethodComputeCost is the number of compute units consumed by methods without
+// a configured cost.
+const defaultMethodComputeCost = 1
+
+// Quota limits the rate at which the clients of a single API key may call methods.
+// A zero rate imposes no limit.
+type Quota struct {
+	// RequestsPerSecond is the number of calls permitted per second.
+	RequestsPerSecond float64
+	// RequestBurst is the number of calls permitted at once. Defaults to
+	// RequestsPerSecond rounded up.
+	RequestBurst int
+	// ComputeUnitsPerSecond is the number of compute units that may be consumed per
+	// second. Each call consumes the cost of its method. See [QuotaConfig.ComputeUnits].
+	ComputeUnitsPerSecond float64
+	// ComputeUnitBurst is the number of compute units that may be consumed at once.
+	// Defaults to ComputeUnitsPerSecond rounded up, or the highest method cost if larger.
+	ComputeUnitBurst int
+}
+
+// QuotaConfig configures how API keys are extracted from requests and the quota
+// enforced for each key.
+type QuotaConfig struct {
+	// KeyHeader is the HTTP header holding the API key of a request.
+	KeyHeader string
+	// Keys maps API keys to their quota.
+	Keys map[string]Quota
+	// Default is the quota shared by all requests without a known API key. If nil,
+	// such requests are rejected.
+	Default *Quota
+	// ComputeUnits maps methods (ex. "eth_call") to the compute units they consume.
+	// Methods that are not listed consume a single unit.
+	ComputeUnits map[string]uint64
+}
+
+// Quotas enforces per API key request and compute unit quotas.
+type Quotas struct {
+	config QuotaConfig
+
+	lock     sync.Mutex
+	limiters map[string]*quotaLimiter // keyed by API key, "" for the default quota
+}
+
+// quotaLimiter holds the rate limiters of a single API key. Nil limiters impose no limit.
+type quotaLimiter struct {
+	requests *rate.Limiter
+	compute  *rate.Limiter
+}
+
+// NewQuotas returns Quotas enforcing [config].
+func NewQuotas(config QuotaConfig) (*Quotas, error) {
+	if len(config.KeyHeader) == 0 {
+		return nil, errMissingAPIKeyHeader
+	}
+	var maxCost uint64
+	for _, cost := range config.ComputeUnits {
+		if cost > maxCost {
+			maxCost = cost
+		}
+	}
+	for key, quota := range config.Keys {
+		if err := quota.verify(maxCost); err != nil {
+			return nil, fmt.Errorf("invalid quota for api key %q: %w", key, err)
+		}
+	}
+	if config.Default != nil {
+		if err := config.Default.verify(maxCost); err != nil {
+			return nil, fmt.Errorf("invalid default quota: %w", err)
+		}
+	}
+	return &Quotas{
+		config:   config,
+		limiters: make(map[string]*quotaLimiter),
+	}, nil
+}
+
+func (q Quota) verify(maxCost uint64) error {
+	if q.RequestsPerSecond < 0 || q.ComputeUnitsPerSecond < 0 {
+		return errInvalidQuotaRate
+	}
+	if q.RequestBurst < 0 || q.ComputeUnitBurst < 0 {
+		return errInvalidQuotaBurst
+	}
+	if q.ComputeUnitsPerSecond > 0 && q.ComputeUnitBurst > 0 && uint64(q.ComputeUnitBurst) < maxCost {
+		return fmt.Errorf("%w (burst: %d, cost: %d)", errComputeBurstTooLow, q.ComputeUnitBurst, maxCost)
+	}
+	return nil
+}
+
+// apiKey returns the API key of [r], or the empty string if it has none.
+func (q *Quotas) apiKey(r *http.Request) string {
+	return r.Header.Get(q.config.KeyHeader)
+}
+
+// cost returns the compute units consumed by a call to [method].
+func (q *Quotas) cost(method string) uint64 {
+	if cost, ok := q.config.ComputeUnits[method]; ok {
+		return cost
+	}
+	return defaultMethodComputeCost
+}
+
+// allow returns an error if a call to [method] by a client of [key] is not
+// permitted by its quota. If permitted, the call is charged to the quota. A call
+// rejected by either limit is not charged to the other.
+func (q *Quotas) allow(key string, method string) error {
+	limiter, err := q.limiter(key)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var request *rate.Reservation
+	if limiter.requests != nil {
+		if request = reserveNow(limiter.requests, now, 1); request == nil {
+			return errRequestQuotaExceeded
+		}
+	}
+	if cost := q.cost(method); limiter.compute != nil && cost > 0 && reserveNow(limiter.compute, now, int(cost)) == nil {
+		if request != nil {
+			request.CancelAt(now)
+		}
+		return errComputeQuotaExceeded
+	}
+	return nil
+}
+
+// reserveNow reserves [n] tokens of [limiter] if they are available at [now], or
+// returns nil without reserving them.
+func reserveNow(limiter *rate.Limiter, now time.Time, n int) *rate.Reservation {
+	reservation := limiter.ReserveN(now, n)
+	if !reservation.OK() {
+		return nil
+	}
+	if reservation.DelayFrom(now) > 0 {
+		reservation.CancelAt(now)
+		return nil
+	}
+	return reservation
+}
+
+// limiter returns the limiter of [key], creating it if needed. Unknown keys share
+// the limiter of the default quota.
+func (q *Quotas) limiter(key string) (*quotaLimiter, error) {
+	quota, ok := q.config.Keys[key]
+	if !ok {
+		if q.config.Default == nil {
+			return nil, errUnknownAPIKey
+		}
+		key, quota = "", *q.config.Default
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if limiter, ok := q.limiters[key]; ok {
+		return limiter, nil
+	}
+	limiter := &quotaLimiter{}
+	if quota.RequestsPerSecond > 0 {
+		burst := quota.RequestBurst
+		if burst == 0 {
+			burst = int(math.Ceil(quota.RequestsPerSecond))
+		}
+		limiter.requests = rate.NewLimiter(rate.Limit(quota.RequestsPerSecond), burst)
+	}
+	if quota.ComputeUnitsPerSecond > 0 {
+		burst := quota.ComputeUnitBurst
+		if burst == 0 {
+			burst = int(math.Ceil(quota.ComputeUnitsPerSecond))
+			for _, cost := range q.config.ComputeUnits {
+				if int(cost) > burst {
+					burst = int(cost)
+				}
+			}
+		}
+		limiter.compute = rate.NewLimiter(rate.Limit(quota.ComputeUnitsPerSecond), burst)
+	}
+	q.limiters[key] = limiter
+	return limiter, nil
+}
@@ -0,0 +1,129 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQuotasAllow(t *testing.T) {
+	quotas, err := NewQuotas(QuotaConfig{
+		KeyHeader: "X-API-Key",
+		Keys: map[string]Quota{
+			"team-a": {RequestsPerSecond: 0.001, RequestBurst: 2},
+			"team-b": {ComputeUnitsPerSecond: 0.001, ComputeUnitBurst: 10},
+			"team-d": {RequestsPerSecond: 0.001, RequestBurst: 2, ComputeUnitsPerSecond: 0.001, ComputeUnitBurst: 10},
+		},
+		ComputeUnits: map[string]uint64{"eth_call": 10, "eth_chainId": 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Request quotas are tracked separately for each key.
+	for i := 0; i < 2; i++ {
+		if err := quotas.allow("team-a", "eth_call"); err != nil {
+			t.Fatalf("call %d: unexpected error %v", i, err)
+		}
+	}
+	if err := quotas.allow("team-a", "eth_call"); !errors.Is(err, errRequestQuotaExceeded) {
+		t.Fatalf("expected %v, got %v", errRequestQuotaExceeded, err)
+	}
+
+	// Compute unit quotas are charged the cost of the method.
+	if err := quotas.allow("team-b", "eth_call"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := quotas.allow("team-b", "eth_blockNumber"); !errors.Is(err, errComputeQuotaExceeded) {
+		t.Fatalf("expected %v, got %v", errComputeQuotaExceeded, err)
+	}
+
+	// Calls rejected by the compute unit quota do not use up the request quota.
+	if err := quotas.allow("team-d", "eth_call"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := quotas.allow("team-d", "eth_call"); !errors.Is(err, errComputeQuotaExceeded) {
+		t.Fatalf("expected %v, got %v", errComputeQuotaExceeded, err)
+	}
+	if err := quotas.allow("team-d", "eth_chainId"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := quotas.allow("team-d", "eth_chainId"); !errors.Is(err, errRequestQuotaExceeded) {
+		t.Fatalf("expected %v, got %v", errRequestQuotaExceeded, err)
+	}
+
+	// Unknown keys are rejected without a default quota.
+	if err := quotas.allow("team-c", "eth_call"); !errors.Is(err, errUnknownAPIKey) {
+		t.Fatalf("expected %v, got %v", errUnknownAPIKey, err)
+	}
+}
+
+func TestNewQuotasInvalid(t *testing.T) {
+	if _, err := NewQuotas(QuotaConfig{}); !errors.Is(err, errMissingAPIKeyHeader) {
+		t.Fatalf("expected %v, got %v", errMissingAPIKeyHeader, err)
+	}
+	_, err := NewQuotas(QuotaConfig{
+		KeyHeader:    "X-API-Key",
+		Keys:         map[string]Quota{"team-a": {ComputeUnitsPerSecond: 1, ComputeUnitBurst: 5}},
+		ComputeUnits: map[string]uint64{"eth_call": 10},
+	})
+	if !errors.Is(err, errComputeBurstTooLow) {
+		t.Fatalf("expected %v, got %v", errComputeBurstTooLow, err)
+	}
+}
+
+func TestQuotasAPIKey(t *testing.T) {
+	quotas, err := NewQuotas(QuotaConfig{KeyHeader: "X-API-Key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "http://url.com/rpc", nil)
+	req.Header.Set("X-API-Key", "header-key")
+	if key := quotas.apiKey(req); key != "header-key" {
+		t.Fatalf("wrong key from header: %q", key)
+	}
+}
+
+func TestServerQuotas(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	quotas, err := NewQuotas(QuotaConfig{
+		KeyHeader: "X-API-Key",
+		Keys:      map[string]Quota{"team-a": {RequestsPerSecond: 0.001, RequestBurst: 1}},
+		Default:   &Quota{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.SetQuotas(quotas)
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetHeader("X-API-Key", "team-a")
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatalf("unexpected error within quota: %v", err)
+	}
+	err = client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"})
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("expected quota exceeded error, got %v", err)
+	}
+
+	// Clients without a known key share the unlimited default quota.
+	client.SetHeader("X-API-Key", "")
+	for i := 0; i < 3; i++ {
+		if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+			t.Fatalf("unexpected error with default quota: %v", err)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import "context"
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext returns the ID assigned by the server to the RPC call being
+// handled. Method handlers should include it in their log lines, so that a failure
+// reported by a client can be correlated with the server logs.
+//
+// The empty string is returned if no request ID is present in ctx.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
@@ -29,6 +29,7 @@ package rpc
 import (
 	"context"
 	"io"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -60,6 +61,15 @@ type Server struct {
 	mutex  sync.Mutex
 	codecs map[ServerCodec]struct{}
 	run    int32
+	// inflight tracks the HTTP requests being served, so that they can be drained
+	// on shutdown.
+	inflight sync.WaitGroup
+
+	authenticate func(http.Header) error
+	quotas       *Quotas
+
+	wsLimits WebsocketLimits
+	wsConns  int64 // number of open WebSocket connections, accessed atomically
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -89,6 +99,54 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetAuthentication requires the HTTP and WebSocket clients of [namespaces] to be
+// authenticated by [authenticate], which is passed the headers of the request (or of
+// the WebSocket upgrade request). Methods of other namespaces remain public.
+func (s *Server) SetAuthentication(authenticate func(http.Header) error, namespaces []string) {
+	s.authenticate = authenticate
+	s.services.setRestricted(namespaces)
+}
+
+// SetAccessPolicy restricts the clients that may call the methods of [namespace] to
+// those permitted by [policy]. A nil [policy] removes any restriction.
+func (s *Server) SetAccessPolicy(namespace string, policy *AccessPolicy) {
+	s.services.setAccessPolicy(namespace, policy)
+}
+
+// SetQuotas enforces [quotas] on the calls of HTTP and WebSocket clients, identified
+// by their API key. A nil [quotas] removes any quota.
+func (s *Server) SetQuotas(quotas *Quotas) {
+	s.quotas = quotas
+	s.services.setQuotas(quotas)
+}
+
+// SetSlowQueryThreshold logs the method, parameters and duration of the calls that
+// take longer than [threshold], along with the details added by the method with
+// [AnnotateSlowQuery]. A zero [threshold] disables slow query logging.
+func (s *Server) SetSlowQueryThreshold(threshold time.Duration) {
+	s.services.setSlowQueryThreshold(threshold)
+}
+
+// apiKey returns the API key of [r], or the empty string if quotas are not enforced.
+func (s *Server) apiKey(r *http.Request) string {
+	if s.quotas == nil {
+		return ""
+	}
+	return s.quotas.apiKey(r)
+}
+
+// authenticated returns true if [header] carries valid credentials.
+func (s *Server) authenticated(header http.Header) bool {
+	if s.authenticate == nil {
+		return false
+	}
+	if err := s.authenticate(header); err != nil {
+		log.Debug("RPC client authentication failed", "err", err)
+		return false
+	}
+	return true
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -125,6 +183,19 @@ func (s *Server) untrackCodec(codec ServerCodec) {
 	delete(s.codecs, codec)
 }
 
+// trackRequest adds an HTTP request to [inflight] if the server is running. The
+// caller must call [inflight.Done] once the request is served.
+func (s *Server) trackRequest() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if atomic.LoadInt32(&s.run) == 0 {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
 // serveSingleRequest reads and processes a single RPC request from the given codec. This
 // is used to serve HTTP connections. Subscriptions and reverse calls are not allowed in
 // this mode.
@@ -169,6 +240,41 @@ func (s *Server) Stop() {
 	}
 }
 
+// Drain stops accepting new requests and waits for the HTTP requests being served
+// to finish, until [ctx] is done. It then closes all codecs like Stop, which cancels
+// the remaining requests and subscriptions. Returns [ctx.Err()] if requests were
+// still in flight.
+func (s *Server) Drain(ctx context.Context) error {
+	s.mutex.Lock()
+	// Requests are only added to [inflight] while the server is running, so no
+	// requests are added once it is stopped here.
+	wasRunning := atomic.CompareAndSwapInt32(&s.run, 1, 0)
+	s.mutex.Unlock()
+	if !wasRunning {
+		return nil
+	}
+	log.Debug("RPC server draining")
+
+	drained := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for codec := range s.codecs {
+		codec.close()
+	}
+	return err
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
@@ -209,6 +315,13 @@ type PeerInfo struct {
 		Origin    string
 		Host      string
 	}
+
+	// Authenticated is true if the client presented valid credentials to the
+	// authenticator of the server. See [Server.SetAuthentication].
+	Authenticated bool
+
+	// APIKey identifies the client for quota enforcement. See [Server.SetQuotas].
+	APIKey string
 }
 
 type peerInfoContextKey struct{}
@@ -28,8 +28,12 @@ package rpc
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -160,3 +164,58 @@ func runTestScript(t *testing.T, file string) {
 // 		}
 // 	}
 // }
+
+func TestServerDrain(t *testing.T) {
+	server := newTestServer()
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Start a call that is in flight when the server drains.
+	callErr := make(chan error, 1)
+	go func() {
+		callErr <- client.Call(nil, "test_sleep", 200*time.Millisecond)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := server.Drain(context.Background()); err != nil {
+		t.Fatalf("drain failed: %v", err)
+	}
+	if err := <-callErr; err != nil {
+		t.Fatalf("in-flight call failed: %v", err)
+	}
+
+	// New requests are rejected once the server is drained.
+	resp, err := http.Get(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("wrong status code after drain: %d", resp.StatusCode)
+	}
+}
+
+func TestServerDrainTimeout(t *testing.T) {
+	server := newTestServer()
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	go client.Call(nil, "test_sleep", time.Second)
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := server.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -46,8 +47,14 @@ var (
 )
 
 type serviceRegistry struct {
-	mu       sync.Mutex
-	services map[string]service
+	mu         sync.Mutex
+	services   map[string]service
+	restricted map[string]struct{} // namespaces that require an authenticated client
+	policies   map[string]*AccessPolicy
+	quotas     *Quotas
+	requestIDs func() ID // generates the IDs assigned to calls, defaults to NewID
+
+	slowQueryThreshold time.Duration // calls taking longer are logged, disabled if 0
 }
 
 // service represents a registered object.
@@ -101,6 +108,113 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 	return nil
 }
 
+// setRestricted marks [namespaces] as requiring an authenticated client.
+func (r *serviceRegistry) setRestricted(namespaces []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restricted = make(map[string]struct{}, len(namespaces))
+	for _, namespace := range namespaces {
+		r.restricted[namespace] = struct{}{}
+	}
+}
+
+// setAccessPolicy restricts the clients that may call methods of [namespace] to
+// those permitted by [policy]. A nil [policy] removes any restriction.
+func (r *serviceRegistry) setAccessPolicy(namespace string, policy *AccessPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.policies == nil {
+		r.policies = make(map[string]*AccessPolicy)
+	}
+	if policy == nil {
+		delete(r.policies, namespace)
+		return
+	}
+	r.policies[namespace] = policy
+}
+
+// setQuotas enforces [quotas] on calls to every method. A nil [quotas] removes any quota.
+func (r *serviceRegistry) setQuotas(quotas *Quotas) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.quotas = quotas
+}
+
+// setSlowQueryThreshold logs the calls taking longer than [threshold]. A zero
+// [threshold] disables slow query logging.
+func (r *serviceRegistry) setSlowQueryThreshold(threshold time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.slowQueryThreshold = threshold
+}
+
+// getSlowQueryThreshold returns the duration above which calls are logged, or 0 if
+// slow query logging is disabled.
+func (r *serviceRegistry) getSlowQueryThreshold() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.slowQueryThreshold
+}
+
+// newRequestID returns the ID assigned to a call handled with the registry.
+func (r *serviceRegistry) newRequestID() string {
+	r.mu.Lock()
+	gen := r.requestIDs
+	r.mu.Unlock()
+
+	if gen == nil {
+		return string(NewID())
+	}
+	return string(gen())
+}
+
+// isRestricted returns true if [method] belongs to a namespace that requires an
+// authenticated client.
+func (r *serviceRegistry) isRestricted(method string) bool {
+	namespace, _, ok := strings.Cut(method, serviceMethodSeparator)
+	if !ok {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok = r.restricted[namespace]
+	return ok
+}
+
+// checkAccess returns an error if the client described by [info] is not permitted to
+// call [method] by the access policy of its namespace.
+func (r *serviceRegistry) checkAccess(method string, info PeerInfo) error {
+	namespace, _, ok := strings.Cut(method, serviceMethodSeparator)
+	if !ok {
+		return nil
+	}
+	r.mu.Lock()
+	policy := r.policies[namespace]
+	r.mu.Unlock()
+	if policy == nil {
+		return nil
+	}
+	return policy.check(info)
+}
+
+// checkQuota returns an error if the client described by [info] has exhausted its
+// quota. Otherwise, the call to [method] is charged to the quota.
+func (r *serviceRegistry) checkQuota(method string, info PeerInfo) error {
+	r.mu.Lock()
+	quotas := r.quotas
+	r.mu.Unlock()
+	// Quotas only apply to remote clients.
+	if quotas == nil || (info.Transport != "http" && info.Transport != "ws") {
+		return nil
+	}
+	return quotas.allow(info.APIKey, method)
+}
+
 // callback returns the callback corresponding to the given RPC method name.
 func (r *serviceRegistry) callback(method string) *callback {
 	elem := strings.SplitN(method, serviceMethodSeparator, 2)
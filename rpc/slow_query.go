@@ -0,0 +1,60 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// maxSlowQueryParamsLength is the maximum number of bytes of the parameters of a
+// call included in the slow query log.
+const maxSlowQueryParamsLength = 256
+
+type slowQueryContextKey struct{}
+
+// slowQueryInfo collects the key/value pairs added to the slow query log of a call.
+type slowQueryInfo struct {
+	lock sync.Mutex
+	ctx  []interface{}
+}
+
+func withSlowQueryInfo(ctx context.Context) (context.Context, *slowQueryInfo) {
+	info := &slowQueryInfo{}
+	return context.WithValue(ctx, slowQueryContextKey{}, info), info
+}
+
+// AnnotateSlowQuery adds the key/value pairs [keyvals], such as the gas consumed by
+// the call, to the log emitted if the call being handled with [ctx] takes longer
+// than the slow query threshold of the server. It is a no-op if slow query logging
+// is disabled.
+func AnnotateSlowQuery(ctx context.Context, keyvals ...interface{}) {
+	info, ok := ctx.Value(slowQueryContextKey{}).(*slowQueryInfo)
+	if !ok {
+		return
+	}
+	info.lock.Lock()
+	defer info.lock.Unlock()
+
+	info.ctx = append(info.ctx, keyvals...)
+}
+
+// logCtx returns the key/value pairs added to the slow query log, after [ctx].
+func (i *slowQueryInfo) logCtx(ctx ...interface{}) []interface{} {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return append(ctx, i.ctx...)
+}
+
+// summarizeParams returns the parameters of a call, truncated to
+// [maxSlowQueryParamsLength] bytes.
+func summarizeParams(params json.RawMessage) string {
+	if len(params) <= maxSlowQueryParamsLength {
+		return string(params)
+	}
+	return fmt.Sprintf("%s... (%d bytes)", params[:maxSlowQueryParamsLength], len(params))
+}
@@ -0,0 +1,53 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotateSlowQuery(t *testing.T) {
+	// Annotations are dropped if slow query logging is disabled.
+	AnnotateSlowQuery(context.Background(), "gasUsed", 1)
+
+	ctx, info := withSlowQueryInfo(context.Background())
+	AnnotateSlowQuery(ctx, "gasUsed", 1)
+	AnnotateSlowQuery(ctx, "block", "latest")
+
+	want := []interface{}{"method", "test_echo", "gasUsed", 1, "block", "latest"}
+	if got := info.logCtx("method", "test_echo"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong log context %v, want %v", got, want)
+	}
+}
+
+func TestSummarizeParams(t *testing.T) {
+	short := json.RawMessage(`["x",3]`)
+	if got := summarizeParams(short); got != string(short) {
+		t.Fatalf("wrong summary %q, want %q", got, short)
+	}
+
+	long := json.RawMessage(`["` + strings.Repeat("a", 2*maxSlowQueryParamsLength) + `"]`)
+	got := summarizeParams(long)
+	if !strings.HasPrefix(got, string(long[:maxSlowQueryParamsLength])) || !strings.HasSuffix(got, "... (516 bytes)") {
+		t.Fatalf("wrong summary %q", got)
+	}
+}
+
+func TestSlowQueryThreshold(t *testing.T) {
+	server := newTestServer()
+	server.SetSlowQueryThreshold(time.Nanosecond)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Slow calls are logged and answered as usual.
+	if err := client.Call(nil, "test_sleep", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+}
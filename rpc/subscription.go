@@ -46,6 +46,10 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrSubscriptionNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionLimitExceeded is returned when a connection exceeds its maximum number of subscriptions
+	ErrSubscriptionLimitExceeded = errors.New("too many subscriptions on this connection")
+	// errSlowConsumer is returned when a connection does not drain its pending notifications in time
+	errSlowConsumer = errors.New("too many pending notifications")
 )
 
 var globalGen = randomIDGenerator()
@@ -106,7 +110,7 @@ type Notifier struct {
 
 	mu           sync.Mutex
 	sub          *Subscription
-	buffer       []json.RawMessage
+	buffer       []*subscriptionResult
 	callReturned bool
 	activated    bool
 }
@@ -144,10 +148,31 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	} else if n.sub.ID != id {
 		panic("Notify with wrong ID")
 	}
+	return n.notify(&subscriptionResult{ID: string(id), Result: enc})
+}
+
+// Error sends [err] to the client as the last notification of the subscription, which
+// ends the subscription of the client with [err]. The server callback must not send
+// further notifications for the subscription.
+func (n *Notifier) Error(id ID, err error) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.sub == nil {
+		panic("can't send Error before subscription is created")
+	} else if n.sub.ID != id {
+		panic("Error with wrong ID")
+	}
+	return n.notify(&subscriptionResult{ID: string(id), Error: errorMessage(err).Error})
+}
+
+// notify sends [result] if the subscription is active, and buffers it otherwise.
+// Must be called with [n.mu] held.
+func (n *Notifier) notify(result *subscriptionResult) error {
 	if n.activated {
-		return n.send(n.sub, enc)
+		return n.send(result)
 	}
-	n.buffer = append(n.buffer, enc)
+	n.buffer = append(n.buffer, result)
 	return nil
 }
 
@@ -173,8 +198,8 @@ func (n *Notifier) activate() error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	for _, data := range n.buffer {
-		if err := n.send(n.sub, data); err != nil {
+	for _, result := range n.buffer {
+		if err := n.send(result); err != nil {
 			return err
 		}
 	}
@@ -182,8 +207,8 @@ func (n *Notifier) activate() error {
 	return nil
 }
 
-func (n *Notifier) send(sub *Subscription, data json.RawMessage) error {
-	params, _ := json.Marshal(&subscriptionResult{ID: string(sub.ID), Result: data})
+func (n *Notifier) send(result *subscriptionResult) error {
+	params, _ := json.Marshal(result)
 	ctx := context.Background()
 
 	msg := &jsonrpcMessage{
@@ -1,7 +1,7 @@
 // These tests trigger various 'internal error' conditions.
 
 --> {"jsonrpc":"2.0","id":1,"method":"test_marshalError","params": []}
-<-- {"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"json: error calling MarshalText for type *rpc.MarshalErrObj: marshal error"}}
+<-- {"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"json: error calling MarshalText for type *rpc.MarshalErrObj: marshal error","data":{"requestId":"0x1"}}}
 
 --> {"jsonrpc":"2.0","id":2,"method":"test_panic","params": []}
-<-- {"jsonrpc":"2.0","id":2,"error":{"code":-32603,"message":"method handler crashed"}}
+<-- {"jsonrpc":"2.0","id":2,"error":{"code":-32603,"message":"method handler crashed","data":{"requestId":"0x2"}}}
@@ -2,4 +2,4 @@
 // with named parameters. 
 
 --> {"jsonrpc":"2.0","method":"test_echo","params":{"int":23},"id":3}
-<-- {"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"non-array args"}}
+<-- {"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"non-array args","data":{"requestId":"0x1"}}}
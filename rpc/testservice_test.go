@@ -38,6 +38,7 @@ import (
 func newTestServer() *Server {
 	server := NewServer(0)
 	server.idgen = sequentialIDGenerator()
+	server.services.requestIDs = sequentialIDGenerator()
 	if err := server.RegisterName("test", new(testService)); err != nil {
 		panic(err)
 	}
@@ -212,6 +213,22 @@ func (s *notificationTestService) SomeSubscription(ctx context.Context, n, val i
 	return subscription, nil
 }
 
+// ErrorSubscription sends [val] and then ends the subscription with an error.
+func (s *notificationTestService) ErrorSubscription(ctx context.Context, val int) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+	go func() {
+		if err := notifier.Notify(subscription.ID, val); err != nil {
+			return
+		}
+		notifier.Error(subscription.ID, testError{})
+	}()
+	return subscription, nil
+}
+
 // HangSubscription blocks on s.unblockHangSubscription before sending anything.
 func (s *notificationTestService) HangSubscription(ctx context.Context, val int) (*Subscription, error) {
 	notifier, supported := NotifierFromContext(ctx)
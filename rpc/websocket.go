@@ -35,6 +35,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -61,6 +62,25 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 	return s.WebsocketHandlerWithDuration(allowedOrigins, 0, 0, 0)
 }
 
+// WebsocketLimits bounds the resources consumed by WebSocket clients. Zero values impose
+// no limit.
+type WebsocketLimits struct {
+	// MaxConnections is the maximum number of concurrent WebSocket connections.
+	MaxConnections int
+	// MaxSubscriptionsPerConnection is the maximum number of active subscriptions of a
+	// single connection.
+	MaxSubscriptionsPerConnection int
+	// MaxPendingNotifications is the maximum number of notifications buffered for a
+	// single connection. Connections that fall further behind are closed.
+	MaxPendingNotifications int
+}
+
+// SetWebsocketLimits applies [limits] to the WebSocket connections served by s. It must
+// be called before s starts serving WebSocket connections.
+func (s *Server) SetWebsocketLimits(limits WebsocketLimits) {
+	s.wsLimits = limits
+}
+
 func (s *Server) WebsocketHandlerWithDuration(allowedOrigins []string, apiMaxDuration, refillRate, maxStored time.Duration) http.Handler {
 	var upgrader = websocket.Upgrader{
 		ReadBufferSize:  wsReadBuffer,
@@ -69,12 +89,24 @@ func (s *Server) WebsocketHandlerWithDuration(allowedOrigins []string, apiMaxDur
 		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if max := s.wsLimits.MaxConnections; max > 0 {
+			if atomic.AddInt64(&s.wsConns, 1) > int64(max) {
+				atomic.AddInt64(&s.wsConns, -1)
+				wsConnectionsRejectedCounter.Inc(1)
+				http.Error(w, "too many websocket connections", http.StatusServiceUnavailable)
+				return
+			}
+			defer atomic.AddInt64(&s.wsConns, -1)
+		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := newWebsocketCodec(conn, r.Host, r.Header)
+		codec := newWebsocketCodec(conn, r.Host, r.Header).(*websocketCodec)
+		codec.info.Authenticated = s.authenticated(r.Header)
+		codec.info.APIKey = s.apiKey(r)
+		codec.setLimits(s.wsLimits)
 		s.ServeCodec(codec, 0, apiMaxDuration, refillRate, maxStored)
 	})
 }
@@ -293,6 +325,32 @@ type websocketCodec struct {
 
 	wg        sync.WaitGroup
 	pingReset chan struct{}
+
+	// Set by setLimits on server connections.
+	subscriptionLimit int
+	notifications     chan *jsonrpcMessage // pending notifications, nil if unbuffered
+}
+
+// subscriptionLimiter is implemented by connections that limit the number of active
+// subscriptions.
+type subscriptionLimiter interface {
+	maxSubscriptions() int
+}
+
+// setLimits applies the per connection [limits] to wc. If [limits] bounds the pending
+// notifications, notifications are written asynchronously from a buffer and wc is
+// closed if the buffer overflows.
+func (wc *websocketCodec) setLimits(limits WebsocketLimits) {
+	wc.subscriptionLimit = limits.MaxSubscriptionsPerConnection
+	if limits.MaxPendingNotifications > 0 {
+		wc.notifications = make(chan *jsonrpcMessage, limits.MaxPendingNotifications)
+		wc.wg.Add(1)
+		go wc.notificationLoop()
+	}
+}
+
+func (wc *websocketCodec) maxSubscriptions() int {
+	return wc.subscriptionLimit
 }
 
 func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header) ServerCodec {
@@ -338,6 +396,41 @@ func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError
 }
 
 func (wc *websocketCodec) writeJSONSkipDeadline(ctx context.Context, v interface{}, isError bool, skip bool) error {
+	if wc.notifications != nil {
+		if msg, ok := v.(*jsonrpcMessage); ok && msg.isNotification() {
+			select {
+			case wc.notifications <- msg:
+				return nil
+			default:
+				log.Debug("Closing slow WebSocket consumer", "conn", wc.remoteAddr(), "pending", len(wc.notifications))
+				wsSlowConsumerCounter.Inc(1)
+				wc.jsonCodec.close()
+				return errSlowConsumer
+			}
+		}
+	}
+	return wc.write(ctx, v, isError, skip)
+}
+
+// notificationLoop writes buffered notifications to the connection.
+func (wc *websocketCodec) notificationLoop() {
+	defer wc.wg.Done()
+
+	for {
+		select {
+		case <-wc.closed():
+			return
+		case msg := <-wc.notifications:
+			if err := wc.write(context.Background(), msg, false, false); err != nil {
+				log.Debug("Failed to write WebSocket notification", "conn", wc.remoteAddr(), "err", err)
+				wc.jsonCodec.close()
+				return
+			}
+		}
+	}
+}
+
+func (wc *websocketCodec) write(ctx context.Context, v interface{}, isError bool, skip bool) error {
 	err := wc.jsonCodec.writeJSONSkipDeadline(ctx, v, isError, skip)
 	if err == nil {
 		// Notify pingLoop to delay the next idle ping.
@@ -337,3 +337,105 @@ func wsPingTestHandler(t *testing.T, conn *websocket.Conn, shutdown, sendPing <-
 		}
 	}
 }
+
+func TestWebsocketConnectionLimit(t *testing.T) {
+	var (
+		srv     = newTestServer()
+		httpsrv = httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+	srv.SetWebsocketLimits(WebsocketLimits{MaxConnections: 1})
+
+	c, err := DialWebsocket(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DialWebsocket(context.Background(), wsURL, ""); err == nil {
+		t.Fatal("expected second connection to be rejected")
+	}
+
+	// Closing the first connection frees up its slot.
+	c.Close()
+	var c2 *Client
+	for i := 0; i < 50; i++ {
+		if c2, err = DialWebsocket(context.Background(), wsURL, ""); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal("connection rejected after closing previous connection:", err)
+	}
+	c2.Close()
+}
+
+func TestWebsocketSubscriptionLimit(t *testing.T) {
+	var (
+		srv     = newTestServer()
+		httpsrv = httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+	srv.SetWebsocketLimits(WebsocketLimits{MaxSubscriptionsPerConnection: 1})
+
+	c, err := DialWebsocket(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 0)
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("expected subscription limit error, got %v", err)
+	}
+
+	// Unsubscribing frees up the slot.
+	sub.Unsubscribe()
+	sub2, err := c.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 0)
+	if err != nil {
+		t.Fatal("subscription rejected after unsubscribing:", err)
+	}
+	sub2.Unsubscribe()
+}
+
+func TestWebsocketSlowConsumer(t *testing.T) {
+	var (
+		srv     = newTestServer()
+		httpsrv = httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+	srv.SetWebsocketLimits(WebsocketLimits{MaxPendingNotifications: 1})
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Subscribe without ever reading notifications, so they pile up on the server.
+	req := `{"jsonrpc":"2.0","id":1,"method":"nftest_subscribe","params":["someSubscription",1000000,0]}`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	// The server closes the connection once its notification buffer overflows.
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if netErr, ok := err.(interface{ Timeout() bool }); ok && netErr.Timeout() {
+				t.Fatal("connection was not closed")
+			}
+			return
+		}
+	}
+}
@@ -21,19 +21,33 @@ go install -v github.com/onsi/ginkgo/v2/ginkgo@${GINKGO_VERSION}
 
 TEST_SOURCE_ROOT=$(pwd)
 
-ACK_GINKGO_RC=true ginkgo build ./tests/load ./tests/warp
+ACK_GINKGO_RC=true ginkgo build ./tests/load ./tests/load/multichain ./tests/warp
 
 # By default, it runs all e2e test cases!
 # Use "--ginkgo.skip" to skip tests.
 # Use "--ginkgo.focus" to select tests.
+# Set E2E_ORCHESTRATOR=external and E2E_NETWORK_CONFIG to the JSON description of an already running
+# network to run the tests against it instead of a network started by the tests.
 TEST_SOURCE_ROOT="$TEST_SOURCE_ROOT" ginkgo run -procs=5 tests/precompile \
   --ginkgo.vv \
-  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""}
+  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""} \
+  --orchestrator=${E2E_ORCHESTRATOR:-anr} \
+  --network-config=${E2E_NETWORK_CONFIG:-""}
 
 ./tests/load/load.test \
   --ginkgo.vv \
-  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""}
+  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""} \
+  --orchestrator=${E2E_ORCHESTRATOR:-anr} \
+  --network-config=${E2E_NETWORK_CONFIG:-""}
+
+./tests/load/multichain/multichain.test \
+  --ginkgo.vv \
+  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""} \
+  --orchestrator=${E2E_ORCHESTRATOR:-anr} \
+  --network-config=${E2E_NETWORK_CONFIG:-""}
 
 ./tests/warp/warp.test \
   --ginkgo.vv \
-  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""}
+  --ginkgo.label-filter=${GINKGO_LABEL_FILTER:-""} \
+  --orchestrator=${E2E_ORCHESTRATOR:-anr} \
+  --network-config=${E2E_NETWORK_CONFIG:-""}
@@ -1,6 +1,8 @@
 #!/usr/bin/env bash
 # This script runs a 30s load simulation using RPC_ENDPOINTS environment variable to specify
 # which RPC endpoints to hit.
+# METRICS_PORT sets the port of the simulator metrics server (default 8082), so that several
+# simulators can run concurrently. SKIP_SIMULATOR_BUILD=true reuses an already built simulator.
 
 set -e
 
@@ -21,14 +23,18 @@ source "$SUBNET_EVM_PATH"/scripts/versions.sh
 # Load the constants
 source "$SUBNET_EVM_PATH"/scripts/constants.sh
 
+METRICS_PORT=${METRICS_PORT:-8082}
+
 run_simulator() {
     #################################
-    echo "building simulator"
-    pushd ./cmd/simulator
-    go build -o ./simulator main/*.go
-    echo 
-
-    popd
+    if [[ ${SKIP_SIMULATOR_BUILD:-false} != true ]]; then
+        echo "building simulator"
+        pushd ./cmd/simulator
+        go build -o ./simulator main/*.go
+        echo 
+
+        popd
+    fi
     echo "running simulator from $PWD"
     ./cmd/simulator/simulator \
         --endpoints=$RPC_ENDPOINTS \
@@ -36,7 +42,8 @@ run_simulator() {
         --timeout=30s \
         --workers=1 \
         --max-fee-cap=300 \
-        --max-tip-cap=100
+        --max-tip-cap=100 \
+        --metrics-port=$METRICS_PORT
 }
 
 run_simulator
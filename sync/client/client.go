@@ -46,6 +46,7 @@ var (
 	errEmptyResponse          = errors.New("empty response")
 	errTooManyBlocks          = errors.New("response contains more blocks than requested")
 	errHashMismatch           = errors.New("hash does not match expected value")
+	errHeightMismatch         = errors.New("height does not match expected value")
 	errInvalidRangeProof      = errors.New("failed to verify range proof")
 	errTooManyLeaves          = errors.New("response contains more than requested leaves")
 	errUnmarshalResponse      = errors.New("failed to unmarshal response")
@@ -63,6 +64,8 @@ type Client interface {
 
 	// GetBlocks synchronously retrieves blocks starting with specified common.Hash and height up to specified parents
 	// specified range from height to height-parents is inclusive
+	// If blockHash is empty, the blocks start from the canonical block at height of the peer, and the
+	// caller must verify that they link to a trusted hash
 	GetBlocks(ctx context.Context, blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error)
 
 	// GetCode synchronously retrieves code associated with the given hashes
@@ -81,6 +84,7 @@ type client struct {
 	stateSyncNodes   []ids.NodeID
 	stateSyncNodeIdx uint32
 	stats            stats.ClientSyncerStats
+	peerStats        *stats.PeerStats
 	blockParser      EthBlockParser
 }
 
@@ -90,6 +94,7 @@ type ClientConfig struct {
 	Stats            stats.ClientSyncerStats
 	StateSyncNodeIDs []ids.NodeID
 	BlockParser      EthBlockParser
+	PeerStats        *stats.PeerStats // Optional, tracks the contribution of each peer
 }
 
 type EthBlockParser interface {
@@ -102,6 +107,7 @@ func NewClient(config *ClientConfig) *client {
 		codec:          config.Codec,
 		stats:          config.Stats,
 		stateSyncNodes: config.StateSyncNodeIDs,
+		peerStats:      config.PeerStats,
 		blockParser:    config.BlockParser,
 	}
 }
@@ -232,7 +238,13 @@ func (c *client) parseBlocks(codec codec.Manager, req message.Request, data []by
 			return nil, 0, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
 		}
 
-		if block.Hash() != hash {
+		if (i == 0 && hash == common.Hash{}) {
+			// The first block of a request by height cannot be checked against a hash
+			// here, so the caller must verify the blocks link to a trusted hash.
+			if block.NumberU64() != blockRequest.Height {
+				return nil, 0, fmt.Errorf("%w for block: (got %d) (expected %d)", errHeightMismatch, block.NumberU64(), blockRequest.Height)
+			}
+		} else if block.Hash() != hash {
 			return nil, 0, fmt.Errorf("%w for block: (got %v) (expected %v)", errHashMismatch, block.Hash(), hash)
 		}
 
@@ -344,6 +356,7 @@ func (c *client) get(ctx context.Context, request message.Request, parseFn parse
 			ctx = append(ctx, "attempt", attempt, "request", request, "err", err)
 			log.Debug("request failed, retrying", ctx...)
 			metric.IncFailed()
+			c.recordFailure(nodeID)
 			c.networkClient.TrackBandwidth(nodeID, 0)
 			time.Sleep(failedRequestSleepInterval)
 			continue
@@ -353,6 +366,7 @@ func (c *client) get(ctx context.Context, request message.Request, parseFn parse
 				lastErr = err
 				log.Info("could not validate response, retrying", "nodeID", nodeID, "attempt", attempt, "request", request, "err", err)
 				c.networkClient.TrackBandwidth(nodeID, 0)
+				c.recordFailure(nodeID)
 				metric.IncFailed()
 				metric.IncInvalidResponse()
 				continue
@@ -360,9 +374,20 @@ func (c *client) get(ctx context.Context, request message.Request, parseFn parse
 
 			bandwidth := float64(len(response)) / (time.Since(start).Seconds() + epsilon)
 			c.networkClient.TrackBandwidth(nodeID, bandwidth)
+			if c.peerStats != nil {
+				c.peerStats.RecordResponse(nodeID, len(response))
+			}
 			metric.IncSucceeded()
 			metric.IncReceived(int64(numElements))
 			return responseIntf, nil
 		}
 	}
 }
+
+// recordFailure records a failed request to [nodeID] in the peer stats if
+// they are tracked. Requests that could not be sent to any peer are ignored.
+func (c *client) recordFailure(nodeID ids.NodeID) {
+	if c.peerStats != nil && nodeID != ids.EmptyNodeID {
+		c.peerStats.RecordFailure(nodeID)
+	}
+}
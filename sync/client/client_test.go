@@ -229,6 +229,46 @@ func TestGetBlocks(t *testing.T) {
 				assert.Equal(t, 11, len(response))
 			},
 		},
+		"response by height": {
+			request: message.BlockRequest{
+				Height:  100,
+				Parents: 16,
+			},
+			getResponse: func(t *testing.T, request message.BlockRequest) []byte {
+				response, err := blocksRequestHandler.OnBlockRequest(context.Background(), ids.GenerateTestNodeID(), 1, request)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(response) == 0 {
+					t.Fatal("Failed to generate valid response")
+				}
+
+				return response
+			},
+			assertResponse: func(t *testing.T, response []*types.Block) {
+				assert.Equal(t, 16, len(response))
+				assert.Equal(t, blocks[100].Hash(), response[0].Hash())
+			},
+		},
+		"wrong height response by height": {
+			request: message.BlockRequest{
+				Height:  100,
+				Parents: 16,
+			},
+			getResponse: func(t *testing.T, request message.BlockRequest) []byte {
+				blockResponse := message.BlockResponse{
+					Blocks: encodeBlockSlice(blocks[80:90]),
+				}
+				responseBytes, err := message.Codec.Marshal(message.Version, blockResponse)
+				if err != nil {
+					t.Fatalf("failed to marshal block response: %s", err)
+				}
+
+				return responseBytes
+			},
+			expectedErr: errHeightMismatch.Error(),
+		},
 		"gibberish response": {
 			request: message.BlockRequest{
 				Hash:    blocks[100].Hash(),
@@ -401,6 +441,12 @@ func buildGetter(blocks []*types.Block) handlers.BlockProvider {
 			}
 			return requestedBlock
 		},
+		GetCanonicalHashFn: func(blockHeight uint64) common.Hash {
+			if blockHeight >= uint64(len(blocks)) {
+				return common.Hash{}
+			}
+			return blocks[blockHeight].Hash()
+		},
 	}
 }
 
@@ -0,0 +1,92 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/metrics"
+)
+
+// PeerContribution is the work a single peer has served to the client.
+type PeerContribution struct {
+	Requests     uint64    `json:"requests"`
+	Failures     uint64    `json:"failures"`
+	Bytes        uint64    `json:"bytes"`
+	LastResponse time.Time `json:"lastResponse"`
+}
+
+// PeerStats tracks the bytes downloaded by the client and the contribution of
+// each peer to them. It is safe for concurrent use.
+type PeerStats struct {
+	lock  sync.Mutex
+	peers map[ids.NodeID]*PeerContribution
+	bytes uint64
+
+	bytesDownloaded metrics.Counter
+}
+
+func NewPeerStats() *PeerStats {
+	return &PeerStats{
+		peers:           make(map[ids.NodeID]*PeerContribution),
+		bytesDownloaded: metrics.GetOrRegisterCounter("sync_bytes_downloaded", nil),
+	}
+}
+
+// RecordResponse records a valid response of [bytes] from [nodeID].
+func (p *PeerStats) RecordResponse(nodeID ids.NodeID, bytes int) {
+	p.bytesDownloaded.Inc(int64(bytes))
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	peer := p.peer(nodeID)
+	peer.Requests++
+	peer.Bytes += uint64(bytes)
+	peer.LastResponse = time.Now()
+	p.bytes += uint64(bytes)
+}
+
+// RecordFailure records a failed request to [nodeID], including requests
+// answered with an invalid response.
+func (p *PeerStats) RecordFailure(nodeID ids.NodeID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	peer := p.peer(nodeID)
+	peer.Requests++
+	peer.Failures++
+}
+
+// BytesDownloaded returns the total bytes of valid responses.
+func (p *PeerStats) BytesDownloaded() uint64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.bytes
+}
+
+// Peers returns a copy of the contribution of each peer requests were sent to.
+func (p *PeerStats) Peers() map[ids.NodeID]PeerContribution {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	peers := make(map[ids.NodeID]PeerContribution, len(p.peers))
+	for nodeID, peer := range p.peers {
+		peers[nodeID] = *peer
+	}
+	return peers
+}
+
+// peer returns the contribution of [nodeID], assumes the lock is held.
+func (p *PeerStats) peer(nodeID ids.NodeID) *PeerContribution {
+	peer, ok := p.peers[nodeID]
+	if !ok {
+		peer = &PeerContribution{}
+		p.peers[nodeID] = peer
+	}
+	return peer
+}
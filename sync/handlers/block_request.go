@@ -17,9 +17,9 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
-// parentLimit specifies how many parents to retrieve and send given a starting hash
+// MaxParentsPerRequest specifies how many parents to retrieve and send given a starting hash
 // This value overrides any specified limit in blockRequest.Parents if it is greater than this value
-const parentLimit = uint16(64)
+const MaxParentsPerRequest = uint16(64)
 
 // BlockRequestHandler is a peer.RequestHandler for message.BlockRequest
 // serving requested blocks starting at specified hash
@@ -46,10 +46,10 @@ func (b *BlockRequestHandler) OnBlockRequest(ctx context.Context, nodeID ids.Nod
 	startTime := time.Now()
 	b.stats.IncBlockRequest()
 
-	// override given Parents limit if it is greater than parentLimit
+	// override given Parents limit if it is greater than MaxParentsPerRequest
 	parents := blockRequest.Parents
-	if parents > parentLimit {
-		parents = parentLimit
+	if parents > MaxParentsPerRequest {
+		parents = MaxParentsPerRequest
 	}
 	blocks := make([][]byte, 0, parents)
 
@@ -61,6 +61,11 @@ func (b *BlockRequestHandler) OnBlockRequest(ctx context.Context, nodeID ids.Nod
 
 	hash := blockRequest.Hash
 	height := blockRequest.Height
+	if (hash == common.Hash{}) {
+		// A request without a hash is for the canonical block at [height] and its
+		// parents, so that clients can request ranges of ancestors concurrently.
+		hash = b.blockProvider.GetCanonicalHash(height)
+	}
 	for i := 0; i < int(parents); i++ {
 		// we return whatever we have until ctx errors, limit is exceeded, or we reach the genesis block
 		// this will happen either when the ctx is cancelled or we hit the ctx deadline
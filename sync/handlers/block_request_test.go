@@ -49,6 +49,12 @@ func TestBlockRequestHandler(t *testing.T) {
 			}
 			return blk
 		},
+		GetCanonicalHashFn: func(height uint64) common.Hash {
+			if height == 0 || height > uint64(len(blocks)) {
+				return common.Hash{}
+			}
+			return blocks[height-1].Hash()
+		},
 	}
 	blockRequestHandler := NewBlockRequestHandler(blockProvider, message.Codec, mockHandlerStats)
 
@@ -59,6 +65,8 @@ func TestBlockRequestHandler(t *testing.T) {
 		startBlockIndex  int
 		startBlockHash   common.Hash
 		startBlockHeight uint64
+		// request the starting block by height only
+		byHeight bool
 
 		requestedParents  uint16
 		expectedBlocks    int
@@ -71,6 +79,13 @@ func TestBlockRequestHandler(t *testing.T) {
 			requestedParents: 32,
 			expectedBlocks:   32,
 		},
+		{
+			name:             "handler_returns_blocks_by_height",
+			startBlockIndex:  64,
+			byHeight:         true,
+			requestedParents: 32,
+			expectedBlocks:   32,
+		},
 		{
 			name:             "handler_caps_blocks_parent_limit",
 			startBlockIndex:  95,
@@ -102,7 +117,9 @@ func TestBlockRequestHandler(t *testing.T) {
 				blockRequest.Height = test.startBlockHeight
 			} else {
 				startingBlock := blocks[test.startBlockIndex]
-				blockRequest.Hash = startingBlock.Hash()
+				if !test.byHeight {
+					blockRequest.Hash = startingBlock.Hash()
+				}
 				blockRequest.Height = startingBlock.NumberU64()
 			}
 			blockRequest.Parents = test.requestedParents
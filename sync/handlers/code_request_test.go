@@ -64,8 +64,12 @@ func TestCodeRequestHandler(t *testing.T) {
 		},
 		"too many hashes": {
 			setup: func() (request message.CodeRequest, expectedCodeResponse [][]byte) {
+				hashes := make([]common.Hash, message.MaxCodeHashesPerBatchedRequest+1)
+				for i := range hashes {
+					hashes[i] = common.Hash{byte(i + 1)}
+				}
 				return message.CodeRequest{
-					Hashes: []common.Hash{{1}, {2}, {3}, {4}, {5}, {6}},
+					Hashes: hashes,
 				}, nil
 			},
 			verifyStats: func(t *testing.T, stats *stats.MockHandlerStats) {
@@ -11,6 +11,7 @@ import (
 
 type BlockProvider interface {
 	GetBlock(common.Hash, uint64) *types.Block
+	GetCanonicalHash(uint64) common.Hash
 }
 
 type SnapshotProvider interface {
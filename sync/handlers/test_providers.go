@@ -15,13 +15,21 @@ var (
 )
 
 type TestBlockProvider struct {
-	GetBlockFn func(common.Hash, uint64) *types.Block
+	GetBlockFn         func(common.Hash, uint64) *types.Block
+	GetCanonicalHashFn func(uint64) common.Hash
 }
 
 func (t *TestBlockProvider) GetBlock(hash common.Hash, number uint64) *types.Block {
 	return t.GetBlockFn(hash, number)
 }
 
+func (t *TestBlockProvider) GetCanonicalHash(number uint64) common.Hash {
+	if t.GetCanonicalHashFn == nil {
+		return common.Hash{}
+	}
+	return t.GetCanonicalHashFn(number)
+}
+
 type TestSnapshotProvider struct {
 	Snapshot *snapshot.Tree
 }
@@ -0,0 +1,118 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handlers
+
+import (
+	"math"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/metrics"
+	"golang.org/x/time/rate"
+)
+
+// throttledPeersCacheSize is the number of peers whose rate limits are tracked.
+// Peers evicted from the cache start over with a full burst.
+const throttledPeersCacheSize = 4096
+
+// ThrottleConfig limits the sync requests served to peers, so that peers cannot
+// saturate the disk of the node. Zero values impose no limit.
+type ThrottleConfig struct {
+	// PeerRequestsPerSecond is the number of requests served per second to each peer.
+	PeerRequestsPerSecond float64
+	// PeerRequestBurst is the number of requests served at once to each peer.
+	// Defaults to PeerRequestsPerSecond rounded up.
+	PeerRequestBurst int
+	// MaxConcurrentRequests is the number of requests served concurrently to all peers.
+	MaxConcurrentRequests int
+	// MaxConcurrentRequestsWhileBusy is the number of requests served concurrently to
+	// all peers while Busy reports consensus work in progress, so that serving sync
+	// yields to consensus.
+	MaxConcurrentRequestsWhileBusy int
+	// Busy reports whether consensus work is in progress. If nil, the node is never
+	// considered busy.
+	Busy func() bool
+}
+
+// RequestThrottler decides whether sync requests can be served, dropping the
+// requests over a limit. Dropped requests time out on the requesting peer, which
+// retries them with another peer.
+type RequestThrottler struct {
+	config ThrottleConfig
+	peers  *cache.LRU[ids.NodeID, *rate.Limiter]
+
+	lock     sync.Mutex
+	inflight int
+
+	inflightGauge      metrics.Gauge
+	rateLimited        metrics.Counter
+	concurrencyLimited metrics.Counter
+	busyLimited        metrics.Counter
+}
+
+func NewRequestThrottler(config ThrottleConfig) *RequestThrottler {
+	if config.PeerRequestBurst == 0 {
+		config.PeerRequestBurst = int(math.Ceil(config.PeerRequestsPerSecond))
+	}
+	return &RequestThrottler{
+		config:             config,
+		peers:              &cache.LRU[ids.NodeID, *rate.Limiter]{Size: throttledPeersCacheSize},
+		inflightGauge:      metrics.GetOrRegisterGauge("sync_requests_inflight", nil),
+		rateLimited:        metrics.GetOrRegisterCounter("sync_requests_throttled_rate", nil),
+		concurrencyLimited: metrics.GetOrRegisterCounter("sync_requests_throttled_concurrency", nil),
+		busyLimited:        metrics.GetOrRegisterCounter("sync_requests_throttled_busy", nil),
+	}
+}
+
+// Acquire returns whether a request from [nodeID] can be served now. If so, the
+// returned function must be called once the request has been served.
+func (t *RequestThrottler) Acquire(nodeID ids.NodeID) (func(), bool) {
+	if t.config.PeerRequestsPerSecond > 0 && !t.peerLimiter(nodeID).Allow() {
+		t.rateLimited.Inc(1)
+		return nil, false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.config.MaxConcurrentRequests > 0 && t.inflight >= t.config.MaxConcurrentRequests {
+		t.concurrencyLimited.Inc(1)
+		return nil, false
+	}
+	if t.config.MaxConcurrentRequestsWhileBusy > 0 && t.config.Busy != nil &&
+		t.inflight >= t.config.MaxConcurrentRequestsWhileBusy && t.config.Busy() {
+		t.busyLimited.Inc(1)
+		return nil, false
+	}
+	t.inflight++
+	t.inflightGauge.Update(int64(t.inflight))
+
+	var once sync.Once
+	return func() {
+		once.Do(t.release)
+	}, true
+}
+
+func (t *RequestThrottler) release() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.inflight--
+	t.inflightGauge.Update(int64(t.inflight))
+}
+
+func (t *RequestThrottler) peerLimiter(nodeID ids.NodeID) *rate.Limiter {
+	// Limiters are created under the lock of the throttler so that concurrent
+	// requests from a new peer share a limiter.
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	limiter, ok := t.peers.Get(nodeID)
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(t.config.PeerRequestsPerSecond), t.config.PeerRequestBurst)
+		t.peers.Put(nodeID, limiter)
+	}
+	return limiter
+}
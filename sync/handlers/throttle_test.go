@@ -0,0 +1,70 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestThrottlerPeerRate(t *testing.T) {
+	throttler := NewRequestThrottler(ThrottleConfig{
+		PeerRequestsPerSecond: 0.001,
+		PeerRequestBurst:      2,
+	})
+	nodeID, otherNodeID := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+
+	for i := 0; i < 2; i++ {
+		release, ok := throttler.Acquire(nodeID)
+		assert.True(t, ok)
+		release()
+	}
+	_, ok := throttler.Acquire(nodeID)
+	assert.False(t, ok, "requests over the burst of a peer must be dropped")
+
+	// Other peers have their own limit.
+	release, ok := throttler.Acquire(otherNodeID)
+	assert.True(t, ok)
+	release()
+}
+
+func TestRequestThrottlerConcurrency(t *testing.T) {
+	busy := false
+	throttler := NewRequestThrottler(ThrottleConfig{
+		MaxConcurrentRequests:          3,
+		MaxConcurrentRequestsWhileBusy: 1,
+		Busy:                           func() bool { return busy },
+	})
+	nodeID := ids.GenerateTestNodeID()
+
+	var releases []func()
+	for i := 0; i < 3; i++ {
+		release, ok := throttler.Acquire(nodeID)
+		assert.True(t, ok)
+		releases = append(releases, release)
+	}
+	_, ok := throttler.Acquire(nodeID)
+	assert.False(t, ok, "requests over the concurrency limit must be dropped")
+
+	// Releasing twice must only free one slot.
+	releases[0]()
+	releases[0]()
+	release, ok := throttler.Acquire(nodeID)
+	assert.True(t, ok)
+	_, ok = throttler.Acquire(nodeID)
+	assert.False(t, ok)
+	release()
+	releases[1]()
+
+	// While busy, requests are only served under the busy limit.
+	busy = true
+	_, ok = throttler.Acquire(nodeID)
+	assert.False(t, ok, "requests over the busy limit must be dropped")
+	releases[2]()
+	release, ok = throttler.Acquire(nodeID)
+	assert.True(t, ok)
+	release()
+}
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/set"
@@ -17,11 +19,14 @@ import (
 	statesyncclient "github.com/ava-labs/subnet-evm/sync/client"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 const (
-	DefaultMaxOutstandingCodeHashes = 5000
-	DefaultNumCodeFetchingWorkers   = 5
+	DefaultMaxOutstandingCodeHashes  = 5000
+	DefaultNumCodeFetchingWorkers    = 16
+	DefaultCodeRequestSize           = message.MaxCodeHashesPerBatchedRequest
+	DefaultBatchedCodeRequestTimeout = 30 * time.Second
 )
 
 var errFailedToAddCodeHashesToQueue = errors.New("failed to add code hashes to queue")
@@ -30,8 +35,15 @@ var errFailedToAddCodeHashesToQueue = errors.New("failed to add code hashes to q
 type CodeSyncerConfig struct {
 	// Maximum number of outstanding code hashes in the queue before the code syncer should block.
 	MaxOutstandingCodeHashes int
-	// Number of worker threads to fetch code from the network
+	// Number of worker threads to fetch code from the network, each of which keeps
+	// one request outstanding.
 	NumCodeFetchingWorkers int
+	// Maximum number of code hashes to fetch per request.
+	CodeRequestSize int
+	// Time to wait for peers to serve a request for more than
+	// [message.MaxCodeHashesPerRequest] code hashes, before falling back to smaller
+	// requests served by all peers.
+	BatchedCodeRequestTimeout time.Duration
 
 	// Client for fetching code from the network
 	Client statesyncclient.Client
@@ -50,6 +62,10 @@ type codeSyncer struct {
 	outstandingCodeHashes set.Set[ids.ID]  // Set of code hashes that we need to fetch from the network.
 	codeHashes            chan common.Hash // Channel of incoming code hash requests
 
+	// Set once a batched code request is not served in time, after which code
+	// hashes are requested [message.MaxCodeHashesPerRequest] at a time.
+	unbatched atomic.Bool
+
 	// Used to set terminal error or pass nil to [errChan] if successful.
 	errOnce sync.Once
 	errChan chan error
@@ -61,6 +77,12 @@ type codeSyncer struct {
 
 // newCodeSyncer returns a a code syncer that will sync code bytes from the network in a separate thread.
 func newCodeSyncer(config CodeSyncerConfig) *codeSyncer {
+	if config.CodeRequestSize == 0 {
+		config.CodeRequestSize = DefaultCodeRequestSize
+	}
+	if config.BatchedCodeRequestTimeout == 0 {
+		config.BatchedCodeRequestTimeout = DefaultBatchedCodeRequestTimeout
+	}
 	return &codeSyncer{
 		CodeSyncerConfig:      config,
 		codeHashes:            make(chan common.Hash, config.MaxOutstandingCodeHashes),
@@ -141,7 +163,7 @@ func (c *codeSyncer) addCodeToFetchFromDBToQueue() error {
 // work fulfills any incoming requests from the producer channel by fetching code bytes from the network
 // and fulfilling them by updating the database.
 func (c *codeSyncer) work(ctx context.Context) error {
-	codeHashes := make([]common.Hash, 0, message.MaxCodeHashesPerRequest)
+	codeHashes := make([]common.Hash, 0, c.CodeRequestSize)
 
 	for {
 		select {
@@ -158,9 +180,10 @@ func (c *codeSyncer) work(ctx context.Context) error {
 			}
 
 			codeHashes = append(codeHashes, codeHash)
-			// Try to wait for at least [MaxCodeHashesPerRequest] code hashes to batch into a single request
-			// if there's more work remaining.
-			if len(codeHashes) < message.MaxCodeHashesPerRequest {
+			// Batch up to [CodeRequestSize] code hashes into a single request, but do not
+			// wait for code hashes that are not queued yet, so that the other workers
+			// keep requests outstanding.
+			if len(codeHashes) < c.CodeRequestSize && len(c.codeHashes) > 0 {
 				continue
 			}
 			if err := c.fulfillCodeRequest(ctx, codeHashes); err != nil {
@@ -178,6 +201,36 @@ func (c *codeSyncer) work(ctx context.Context) error {
 // codeHashes should not be empty or contain duplicate hashes.
 // Returns an error if one is encountered, signaling the worker thread to terminate.
 func (c *codeSyncer) fulfillCodeRequest(ctx context.Context, codeHashes []common.Hash) error {
+	if len(codeHashes) <= message.MaxCodeHashesPerRequest || c.unbatched.Load() {
+		return c.fetchCode(ctx, codeHashes)
+	}
+
+	// Older peers drop requests for more than [MaxCodeHashesPerRequest] code hashes,
+	// so fall back to smaller requests if no peer serves the batched request in time.
+	batchCtx, cancel := context.WithTimeout(ctx, c.BatchedCodeRequestTimeout)
+	err := c.fetchCode(batchCtx, codeHashes)
+	cancel()
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	if c.unbatched.CompareAndSwap(false, true) {
+		log.Warn("Batched code requests are not served by peers, falling back to smaller requests", "err", err)
+	}
+	for start := 0; start < len(codeHashes); start += message.MaxCodeHashesPerRequest {
+		end := start + message.MaxCodeHashesPerRequest
+		if end > len(codeHashes) {
+			end = len(codeHashes)
+		}
+		if err := c.fetchCode(ctx, codeHashes[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchCode fetches [codeHashes] from the network in a single request and writes them to the
+// database.
+func (c *codeSyncer) fetchCode(ctx context.Context, codeHashes []common.Hash) error {
 	codeByteSlices, err := c.Client.GetCode(ctx, codeHashes)
 	if err != nil {
 		return err
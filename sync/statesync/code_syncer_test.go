@@ -129,6 +129,37 @@ func TestCodeSyncerRequestErrors(t *testing.T) {
 	})
 }
 
+func TestCodeSyncerFallsBackToUnbatchedRequests(t *testing.T) {
+	numCodeSlices := 200
+	codeHashes := make([]common.Hash, 0, numCodeSlices)
+	codeByteSlices := make([][]byte, 0, numCodeSlices)
+	for i := 0; i < numCodeSlices; i++ {
+		codeBytes := utils.RandomBytes(100)
+		codeHash := crypto.Keccak256Hash(codeBytes)
+		codeHashes = append(codeHashes, codeHash)
+		codeByteSlices = append(codeByteSlices, codeBytes)
+	}
+
+	var syncer *codeSyncer
+	testCodeSyncer(t, codeSyncerTest{
+		setupCodeSyncer: func(c *codeSyncer) {
+			syncer = c
+			c.NumCodeFetchingWorkers = 1
+			// Queue the code hashes before the worker starts, so that it batches them.
+			assert.NoError(t, c.addCode(codeHashes))
+		},
+		codeByteSlices: codeByteSlices,
+		// Simulate peers that predate batched code requests.
+		getCodeIntercept: func(hashes []common.Hash, codeBytes [][]byte) ([][]byte, error) {
+			if len(hashes) > message.MaxCodeHashesPerRequest {
+				return nil, errors.New("request dropped")
+			}
+			return codeBytes, nil
+		},
+	})
+	assert.True(t, syncer.unbatched.Load())
+}
+
 func TestCodeSyncerAddsInProgressCodeHashes(t *testing.T) {
 	codeBytes := utils.RandomBytes(100)
 	codeHash := crypto.Keccak256Hash(codeBytes)
@@ -30,6 +30,7 @@ type StateSyncerConfig struct {
 	BatchSize                int
 	MaxOutstandingCodeHashes int    // Maximum number of code hashes in the code syncer queue
 	NumCodeFetchingWorkers   int    // Number of code syncing threads
+	CodeRequestSize          int    // Number of code hashes to request from a peer at a time
 	RequestSize              uint16 // Number of leafs to request from a peer at a time
 }
 
@@ -89,6 +90,7 @@ func NewStateSyncer(config *StateSyncerConfig) (*stateSync, error) {
 		Client:                   config.Client,
 		MaxOutstandingCodeHashes: config.MaxOutstandingCodeHashes,
 		NumCodeFetchingWorkers:   config.NumCodeFetchingWorkers,
+		CodeRequestSize:          config.CodeRequestSize,
 	})
 
 	ss.trieQueue = NewTrieQueue(config.DB)
@@ -225,6 +227,18 @@ func (t *stateSync) Start(ctx context.Context) error {
 
 func (t *stateSync) Done() <-chan error { return t.done }
 
+// Progress returns a snapshot of the progress of the sync. Safe to call
+// concurrently with the sync.
+func (t *stateSync) Progress() Progress {
+	progress := t.stats.progress()
+	select {
+	case <-t.mainTrieDone:
+		progress.MainTrieDone = true
+	default:
+	}
+	return progress
+}
+
 // addTrieInProgress tracks the root as being currently synced.
 func (t *stateSync) addTrieInProgress(root common.Hash, trie *trieToSync) {
 	t.lock.Lock()
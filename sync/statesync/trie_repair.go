@@ -0,0 +1,112 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	syncclient "github.com/ava-labs/subnet-evm/sync/client"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var _ syncclient.LeafSyncTask = &trieRepairTask{}
+
+// trieRepairTask fetches the leaves of a trie below a key prefix and rebuilds the
+// trie nodes below the prefix from them.
+//
+// Since a trie node only depends on the leaves below it, hashing the leaves with
+// the prefix stripped reproduces every node of the trie below the prefix. The
+// nodes above the prefix are written under hashes which nothing refers to, which
+// wastes some space but cannot overwrite other nodes.
+type trieRepairTask struct {
+	root      common.Hash
+	account   common.Hash
+	prefix    []byte
+	batch     ethdb.Batch
+	stackTrie *trie.StackTrie
+	leafs     int
+}
+
+func (t *trieRepairTask) Root() common.Hash      { return t.root }
+func (t *trieRepairTask) Account() common.Hash   { return t.account }
+func (t *trieRepairTask) OnStart() (bool, error) { return false, nil }
+
+func (t *trieRepairTask) Start() []byte {
+	return append(common.CopyBytes(t.prefix), bytes.Repeat([]byte{0x00}, common.HashLength-len(t.prefix))...)
+}
+
+func (t *trieRepairTask) End() []byte {
+	return append(common.CopyBytes(t.prefix), bytes.Repeat([]byte{0xff}, common.HashLength-len(t.prefix))...)
+}
+
+func (t *trieRepairTask) OnLeafs(keys, vals [][]byte) error {
+	for i, key := range keys {
+		if err := t.stackTrie.TryUpdate(key[len(t.prefix):], vals[i]); err != nil {
+			return err
+		}
+	}
+	t.leafs += len(keys)
+	if t.batch.ValueSize() > ethdb.IdealBatchSize {
+		if err := t.batch.Write(); err != nil {
+			return err
+		}
+		t.batch.Reset()
+	}
+	return nil
+}
+
+func (t *trieRepairTask) OnFinish(context.Context) error {
+	if _, err := t.stackTrie.Commit(); err != nil {
+		return err
+	}
+	return t.batch.Write()
+}
+
+// RepairTrie fetches the leaves below the hex-encoded [path] of the trie at [root]
+// from peers and rewrites the trie nodes below [path] to [db]. [account] is the
+// account hash of a storage trie, or empty for the account trie. The leaves are
+// verified against [root] with range proofs. Returns the number of leaves fetched.
+//
+// Note: the leaves are fetched for the longest whole-byte prefix of [path], so
+// the repaired range may include siblings of the node at [path].
+func RepairTrie(ctx context.Context, client syncclient.LeafClient, db ethdb.Database, root, account common.Hash, path []byte, requestSize uint16) (int, error) {
+	batch := db.NewBatch()
+	task := &trieRepairTask{
+		root:    root,
+		account: account,
+		prefix:  pathPrefix(path),
+		batch:   batch,
+		stackTrie: trie.NewStackTrie(func(owner common.Hash, path []byte, hash common.Hash, blob []byte) {
+			rawdb.WriteTrieNode(batch, owner, path, hash, blob, rawdb.HashScheme)
+		}),
+	}
+	tasks := make(chan syncclient.LeafSyncTask, 1)
+	tasks <- task
+	close(tasks)
+
+	syncer := syncclient.NewCallbackLeafSyncer(client, tasks, requestSize)
+	syncer.Start(ctx, 1, func(error) error { return nil })
+	if err := <-syncer.Done(); err != nil {
+		return task.leafs, fmt.Errorf("failed to repair trie %s at path %x: %w", root, path, err)
+	}
+	return task.leafs, nil
+}
+
+// pathPrefix returns the key bytes of the longest whole-byte prefix of the
+// hex-encoded trie [path].
+func pathPrefix(path []byte) []byte {
+	if len(path) > 0 && path[len(path)-1] == 16 {
+		path = path[:len(path)-1] // strip the terminator of leaf paths
+	}
+	prefix := make([]byte, len(path)/2)
+	for i := range prefix {
+		prefix[i] = path[2*i]<<4 | path[2*i+1]
+	}
+	return prefix
+}
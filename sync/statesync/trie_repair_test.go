@@ -0,0 +1,75 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	statesyncclient "github.com/ava-labs/subnet-evm/sync/client"
+	"github.com/ava-labs/subnet-evm/sync/handlers"
+	handlerstats "github.com/ava-labs/subnet-evm/sync/handlers/stats"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairTrie(t *testing.T) {
+	rand.Seed(1)
+	serverDB := rawdb.NewMemoryDatabase()
+	serverTrieDB := trie.NewDatabase(serverDB)
+	root, _, _ := trie.GenerateTrie(t, serverTrieDB, 1000, common.HashLength)
+
+	// Copy the server database to the client and delete one of its trie nodes.
+	clientDB := rawdb.NewMemoryDatabase()
+	it := serverDB.NewIterator(nil, nil)
+	for it.Next() {
+		if err := clientDB.Put(common.CopyBytes(it.Key()), common.CopyBytes(it.Value())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	it.Release()
+
+	for _, depth := range []int{0, 1, 2, 3} {
+		tr, err := trie.New(trie.TrieID(root), serverTrieDB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var (
+			nodeIt = tr.NodeIterator(nil)
+			path   []byte
+			hash   common.Hash
+		)
+		for nodeIt.Next(true) {
+			if len(nodeIt.Path()) == depth && nodeIt.Hash() != (common.Hash{}) {
+				path, hash = common.CopyBytes(nodeIt.Path()), nodeIt.Hash()
+				break
+			}
+		}
+		if hash == (common.Hash{}) {
+			t.Fatalf("no trie node found at depth %d", depth)
+		}
+		rawdb.DeleteLegacyTrieNode(clientDB, hash)
+
+		leafsRequestHandler := handlers.NewLeafsRequestHandler(serverTrieDB, nil, message.Codec, handlerstats.NewNoopHandlerStats())
+		client := statesyncclient.NewMockClient(message.Codec, leafsRequestHandler, nil, nil)
+		leafs, err := RepairTrie(context.Background(), client, clientDB, root, common.Hash{}, path, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Positive(t, leafs)
+		assert.True(t, rawdb.HasLegacyTrieNode(clientDB, hash), "node at depth %d was not repaired", depth)
+		trie.AssertTrieConsistency(t, root, serverTrieDB, trie.NewDatabase(clientDB), nil)
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	assert.Equal(t, []byte{}, pathPrefix(nil))
+	assert.Equal(t, []byte{}, pathPrefix([]byte{0xa}))
+	assert.Equal(t, []byte{0xab}, pathPrefix([]byte{0xa, 0xb, 0xc}))
+	assert.Equal(t, []byte{0xab, 0xcd}, pathPrefix([]byte{0xa, 0xb, 0xc, 0xd, 16}))
+}
@@ -32,13 +32,35 @@ type trieSyncStats struct {
 	triesSynced      int
 	triesStartTime   time.Time
 	leafsSinceUpdate uint64
+	leafs            uint64
+	lastLeafs        time.Time
+	eta              time.Duration
 
 	remainingLeafs map[*trieSegment]uint64
 
 	// metrics
-	totalLeafs     metrics.Counter
-	triesSegmented metrics.Counter
-	leafsRateGauge metrics.Gauge
+	totalLeafs          metrics.Counter
+	triesSegmented      metrics.Counter
+	leafsRateGauge      metrics.Gauge
+	triesSyncedGauge    metrics.Gauge
+	triesRemainingGauge metrics.Gauge
+	etaGauge            metrics.Gauge
+}
+
+// Progress is a snapshot of the progress of a state sync.
+type Progress struct {
+	// MainTrieDone is true once the account trie is synced. Until then, the
+	// number of storage tries to sync is unknown.
+	MainTrieDone   bool
+	TriesSynced    int
+	TriesRemaining int
+	Leafs          uint64
+	LeafsPerSecond float64
+	// ETA is zero until the leaf rate is first measured.
+	ETA time.Duration
+	// LastLeafs is the time leafs were last received, or the zero time if none
+	// were. A sync that is not receiving leafs is stuck rather than slow.
+	LastLeafs time.Time
 }
 
 func newTrieSyncStats() *trieSyncStats {
@@ -51,6 +73,10 @@ func newTrieSyncStats() *trieSyncStats {
 		totalLeafs:     metrics.GetOrRegisterCounter("state_sync_total_leafs", nil),
 		leafsRateGauge: metrics.GetOrRegisterGauge("state_sync_leafs_per_second", nil),
 		triesSegmented: metrics.GetOrRegisterCounter("state_sync_tries_segmented", nil),
+
+		triesSyncedGauge:    metrics.GetOrRegisterGauge("state_sync_tries_synced", nil),
+		triesRemainingGauge: metrics.GetOrRegisterGauge("state_sync_tries_remaining", nil),
+		etaGauge:            metrics.GetOrRegisterGauge("state_sync_eta_seconds", nil),
 	}
 }
 
@@ -65,11 +91,13 @@ func (t *trieSyncStats) incLeafs(segment *trieSegment, count uint64, remaining u
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	now := time.Now()
 	t.totalLeafs.Inc(int64(count))
 	t.leafsSinceUpdate += count
+	t.leafs += count
+	t.lastLeafs = now
 	t.remainingLeafs[segment] = remaining
 
-	now := time.Now()
 	sinceUpdate := now.Sub(t.lastUpdated)
 	if sinceUpdate > updateFrequency {
 		t.updateETA(sinceUpdate, now)
@@ -109,6 +137,8 @@ func (t *trieSyncStats) trieDone(root common.Hash) {
 
 	t.triesSynced++
 	t.triesRemaining--
+	t.triesSyncedGauge.Update(int64(t.triesSynced))
+	t.triesRemainingGauge.Update(int64(t.triesRemaining))
 }
 
 // updateETA calculates and logs and ETA based on the number of leafs
@@ -127,11 +157,13 @@ func (t *trieSyncStats) updateETA(sinceUpdate time.Duration, now time.Time) {
 	if t.triesSynced == 0 {
 		// provide a separate ETA for the account trie syncing step since we
 		// don't know the total number of storage tries yet.
+		t.setETA(leafsTime)
 		log.Info("state sync: syncing account trie", "ETA", roundETA(leafsTime))
 		return
 	}
 
 	triesTime := now.Sub(t.triesStartTime) * time.Duration(t.triesRemaining) / time.Duration(t.triesSynced)
+	t.setETA(leafsTime + triesTime)
 	log.Info(
 		"state sync: syncing storage tries",
 		"triesRemaining", t.triesRemaining,
@@ -145,6 +177,31 @@ func (t *trieSyncStats) setTriesRemaining(triesRemaining int) {
 
 	t.triesRemaining = triesRemaining
 	t.triesStartTime = time.Now()
+	t.triesRemainingGauge.Update(int64(t.triesRemaining))
+}
+
+// setETA records [eta] as the latest estimate, assumes lock is held.
+func (t *trieSyncStats) setETA(eta time.Duration) {
+	t.eta = eta
+	t.etaGauge.Update(int64(eta.Seconds()))
+}
+
+// progress takes a lock and returns a snapshot of the leafs and tries synced.
+func (t *trieSyncStats) progress() Progress {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	progress := Progress{
+		TriesSynced:    t.triesSynced,
+		TriesRemaining: t.triesRemaining,
+		Leafs:          t.leafs,
+		ETA:            t.eta,
+		LastLeafs:      t.lastLeafs,
+	}
+	if t.leafsRate != nil {
+		progress.LeafsPerSecond = t.leafsRate.Read()
+	}
+	return progress
 }
 
 // roundETA rounds [d] to a minute and chops off the "0s" suffix
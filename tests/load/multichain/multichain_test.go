@@ -0,0 +1,123 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package multichain
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/tests/utils/runner"
+	"github.com/ethereum/go-ethereum/log"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+const (
+	// baseMetricsPort is the metrics port of the first simulator. Each concurrent
+	// simulator uses the next port.
+	baseMetricsPort = 8082
+	// maxDurationRatio bounds how much longer the slowest blockchain may take to
+	// process its load than the fastest one, since all of them receive the same load
+	// on the same nodes.
+	maxDurationRatio = 3
+)
+
+var getBlockchains func() []*runner.Subnet
+
+func init() {
+	// Two blockchains share the first subnet and a third runs on its own subnet, all
+	// validated by the same nodes.
+	getBlockchains = runner.RegisterMultiBlockchainRun([]int{2, 1})
+}
+
+func TestE2E(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "subnet-evm multi-blockchain load simulator test suite")
+}
+
+var _ = ginkgo.Describe("[Multi-Blockchain Load Simulator]", ginkgo.Ordered, func() {
+	ginkgo.It("concurrent multi-blockchain load test", ginkgo.Label("load"), func() {
+		blockchains := getBlockchains()
+
+		log.Info("Building load simulator...")
+		build := exec.Command("go", "build", "-o", "./simulator", "./main")
+		build.Dir = "./cmd/simulator"
+		out, err := build.CombinedOutput()
+		gomega.Expect(err).Should(gomega.BeNil(), string(out))
+
+		var (
+			wg           sync.WaitGroup
+			startHeights = make([]uint64, len(blockchains))
+			durations    = make([]time.Duration, len(blockchains))
+			errs         = make([]error, len(blockchains))
+		)
+		for i, blockchain := range blockchains {
+			rpcEndpoints := make([]string, 0, len(blockchain.ValidatorURIs))
+			for _, uri := range blockchain.ValidatorURIs {
+				rpcEndpoints = append(rpcEndpoints, fmt.Sprintf("%s/ext/bc/%s/rpc", uri, blockchain.BlockchainID))
+			}
+			startHeights[i] = blockHeight(rpcEndpoints[0])
+
+			wg.Add(1)
+			go func(i int, rpcEndpoints []string) {
+				defer wg.Done()
+
+				commaSeparatedRPCEndpoints := strings.Join(rpcEndpoints, ",")
+				cmd := exec.Command("./scripts/run_simulator.sh")
+				cmd.Env = append(os.Environ(),
+					"RPC_ENDPOINTS="+commaSeparatedRPCEndpoints,
+					fmt.Sprintf("METRICS_PORT=%d", baseMetricsPort+i),
+					"SKIP_SIMULATOR_BUILD=true",
+				)
+				log.Info("Running load simulator script", "blockchainID", blockchains[i].BlockchainID, "rpcEndpoints", commaSeparatedRPCEndpoints)
+
+				start := time.Now()
+				out, err := cmd.CombinedOutput()
+				durations[i] = time.Since(start)
+				fmt.Printf("\nCombined output for blockchain %s:\n\n%s\n", blockchains[i].BlockchainID, string(out))
+				errs[i] = err
+			}(i, rpcEndpoints)
+		}
+		wg.Wait()
+
+		minDuration, maxDuration := durations[0], durations[0]
+		for i, blockchain := range blockchains {
+			gomega.Expect(errs[i]).Should(gomega.BeNil(), "load simulator failed on blockchain %s", blockchain.BlockchainID)
+
+			// Every blockchain must have made progress, regardless of the load on the
+			// other blockchains running on the same nodes.
+			height := blockHeight(fmt.Sprintf("%s/ext/bc/%s/rpc", blockchain.ValidatorURIs[0], blockchain.BlockchainID))
+			gomega.Expect(height).Should(gomega.BeNumerically(">", startHeights[i]), "blockchain %s did not build any blocks", blockchain.BlockchainID)
+
+			log.Info("Load simulator completed", "blockchainID", blockchain.BlockchainID, "subnetID", blockchain.SubnetID, "duration", durations[i], "blocks", height-startHeights[i])
+			if durations[i] < minDuration {
+				minDuration = durations[i]
+			}
+			if durations[i] > maxDuration {
+				maxDuration = durations[i]
+			}
+		}
+		gomega.Expect(maxDuration).Should(gomega.BeNumerically("<=", maxDurationRatio*minDuration), "blockchains were not processed fairly")
+	})
+})
+
+// blockHeight returns the current block height of the blockchain served at [rpcEndpoint].
+func blockHeight(rpcEndpoint string) uint64 {
+	client, err := ethclient.Dial(rpcEndpoint)
+	gomega.Expect(err).Should(gomega.BeNil())
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	height, err := client.BlockNumber(ctx)
+	gomega.Expect(err).Should(gomega.BeNil())
+	return height
+}
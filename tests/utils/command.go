@@ -50,15 +50,6 @@ func RunCommand(bin string, args ...string) (*cmd.Cmd, error) {
 	return curCmd, nil
 }
 
-func RegisterPingTest() {
-	ginkgo.It("ping the network", ginkgo.Label("ping"), func() {
-		client := health.NewClient(DefaultLocalNodeURI)
-		healthy, err := client.Readiness(context.Background(), nil)
-		gomega.Expect(err).Should(gomega.BeNil())
-		gomega.Expect(healthy.Healthy).Should(gomega.BeTrue())
-	})
-}
-
 // RegisterNodeRun registers a before suite that starts an AvalancheGo process to use for the e2e tests
 // and an after suite that stops the AvalancheGo process
 func RegisterNodeRun() {
@@ -91,13 +82,11 @@ func RegisterNodeRun() {
 	})
 }
 
-// RunDefaultHardhatTests runs the hardhat tests in the given [testPath] on the blockchain with [blockchainID]
+// RunDefaultHardhatTests runs the hardhat tests in the given [testPath] on the blockchain served at [chainURI]
 // [execPath] is the path where the test command is executed
-func RunHardhatTests(ctx context.Context, blockchainID string, execPath string, testPath string) {
-	chainURI := GetDefaultChainURI(blockchainID)
+func RunHardhatTests(ctx context.Context, chainURI string, execPath string, testPath string) {
 	log.Info(
 		"Executing HardHat tests on blockchain",
-		"blockchainID", blockchainID,
 		"testPath", testPath,
 		"ChainURI", chainURI,
 	)
@@ -17,17 +17,19 @@ import (
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/subnet-evm/plugin/evm"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/onsi/ginkgo/v2"
-	"github.com/onsi/gomega"
 )
 
-// Subnet provides the basic details of a created subnet
-// Note: currently assumes one blockchain per subnet
+// Subnet provides the basic details of a created blockchain and the subnet validating it
+// Note: a subnet with multiple blockchains has one Subnet per blockchain, sharing a SubnetID
 type Subnet struct {
 	// SubnetID is the txID of the transaction that created the subnet
 	SubnetID ids.ID
-	// Current ANR assumes one blockchain per subnet, so we have a single blockchainID here
+	// BlockchainID is the txID of the transaction that created the blockchain
 	BlockchainID ids.ID
+	// BlockchainAlias is the alias of the blockchain, if any
+	BlockchainAlias string
+	// ValidatorNames is the ANR node name of each participant of the Subnet
+	ValidatorNames []string
 	// ValidatorURIs is the base URIs for each participant of the Subnet
 	ValidatorURIs []string
 }
@@ -37,6 +39,8 @@ type ANRConfig struct {
 	AvalancheGoExecPath string
 	PluginDir           string
 	GlobalNodeConfig    string
+	// SnapshotsDir is the directory where network snapshots are stored
+	SnapshotsDir string
 }
 
 // NetworkManager is a wrapper around the ANR to simplify the setup and teardown code
@@ -59,6 +63,8 @@ type NetworkManager struct {
 // the AvalancheGoExecPath and PluginDir arguments.
 // If the AVALANCHEGO_BUILD_PATH environment variable is set, it overrides the default location for
 // the AvalancheGoExecPath and PluginDir arguments.
+// Snapshots are stored in $HOME/.avalanche-network-runner/snapshots, unless overridden by the
+// ANR_SNAPSHOTS_DIR environment variable.
 func NewDefaultANRConfig() ANRConfig {
 	defaultConfig := ANRConfig{
 		LogLevel:            "info",
@@ -68,12 +74,16 @@ func NewDefaultANRConfig() ANRConfig {
 			"log-display-level":"info",
 			"proposervm-use-current-height":true
 		}`,
+		SnapshotsDir: os.ExpandEnv("$HOME/.avalanche-network-runner/snapshots"),
 	}
 	// If AVALANCHEGO_BUILD_PATH is populated, override location set by GOPATH
 	if envBuildPath, exists := os.LookupEnv("AVALANCHEGO_BUILD_PATH"); exists {
 		defaultConfig.AvalancheGoExecPath = fmt.Sprintf("%s/avalanchego", envBuildPath)
 		defaultConfig.PluginDir = fmt.Sprintf("%s/plugins", envBuildPath)
 	}
+	if envSnapshotsDir, exists := os.LookupEnv("ANR_SNAPSHOTS_DIR"); exists {
+		defaultConfig.SnapshotsDir = envSnapshotsDir
+	}
 	return defaultConfig
 }
 
@@ -115,7 +125,7 @@ func (n *NetworkManager) startServer(ctx context.Context) (<-chan struct{}, erro
 			GwDisabled:          false,
 			DialTimeout:         10 * time.Second,
 			RedirectNodesOutput: true,
-			SnapshotsDir:        "",
+			SnapshotsDir:        n.ANRConfig.SnapshotsDir,
 			LogLevel:            logLevel,
 		},
 		zapServerLog,
@@ -235,20 +245,8 @@ func (n *NetworkManager) SetupNetwork(ctx context.Context, execPath string, bloc
 		return fmt.Errorf("failed to create blockchains: %w", err)
 	}
 
-	// TODO: network runner health should imply custom VM healthiness
-	// or provide a separate API for custom VM healthiness
-	// "start" is async, so wait some time for cluster health
-	log.Info("waiting for all VMs to report healthy", "VMID", evm.ID)
-	for {
-		v, err := n.anrClient.Health(ctx)
-		log.Info("Pinged CLI Health", "result", v, "err", err)
-		if err != nil {
-			time.Sleep(1 * time.Second)
-			continue
-		} else if ctx.Err() != nil {
-			return fmt.Errorf("failed to await healthy network: %w", ctx.Err())
-		}
-		break
+	if err := n.awaitHealthy(ctx); err != nil {
+		return err
 	}
 
 	status, err := n.anrClient.Status(cctx)
@@ -269,11 +267,19 @@ func (n *NetworkManager) SetupNetwork(ctx context.Context, execPath string, bloc
 			panic(err)
 		}
 		subnet := &Subnet{
-			SubnetID:     subnetID,
-			BlockchainID: blockchainID,
+			SubnetID:        subnetID,
+			BlockchainID:    blockchainID,
+			BlockchainAlias: chainSpec.BlockchainAlias,
 		}
-		for _, nodeName := range chainSpec.SubnetSpec.Participants {
-			subnet.ValidatorURIs = append(subnet.ValidatorURIs, nodeInfos[nodeName].Uri)
+		if chainSpec.SubnetSpec != nil {
+			for _, nodeName := range chainSpec.SubnetSpec.Participants {
+				subnet.ValidatorNames = append(subnet.ValidatorNames, nodeName)
+				subnet.ValidatorURIs = append(subnet.ValidatorURIs, nodeInfos[nodeName].Uri)
+			}
+		} else if existing, ok := n.GetSubnet(subnetID); ok {
+			// The blockchain was added to an existing subnet, which has the same validators.
+			subnet.ValidatorNames = existing.ValidatorNames
+			subnet.ValidatorURIs = existing.ValidatorURIs
 		}
 		n.subnets = append(n.subnets, subnet)
 	}
@@ -281,6 +287,25 @@ func (n *NetworkManager) SetupNetwork(ctx context.Context, execPath string, bloc
 	return nil
 }
 
+// awaitHealthy blocks until the ANR reports the network as healthy.
+func (n *NetworkManager) awaitHealthy(ctx context.Context) error {
+	// TODO: network runner health should imply custom VM healthiness
+	// or provide a separate API for custom VM healthiness
+	// "start" is async, so wait some time for cluster health
+	log.Info("waiting for all VMs to report healthy", "VMID", evm.ID)
+	for {
+		v, err := n.anrClient.Health(ctx)
+		log.Info("Pinged CLI Health", "result", v, "err", err)
+		if err != nil {
+			time.Sleep(1 * time.Second)
+			continue
+		} else if ctx.Err() != nil {
+			return fmt.Errorf("failed to await healthy network: %w", ctx.Err())
+		}
+		return nil
+	}
+}
+
 // TeardownNetwork tears down the network constructed by the network manager and cleans up
 // everything associated with it.
 func (n *NetworkManager) TeardownNetwork() error {
@@ -320,6 +345,12 @@ func (n *NetworkManager) GetSubnets() []ids.ID {
 	return subnetIDs
 }
 
+// GetBlockchains returns the details of every blockchain created by the network manager,
+// in the order they were created
+func (n *NetworkManager) GetBlockchains() []*Subnet {
+	return n.subnets
+}
+
 // GetSubnet retrieves the subnet details for the requested subnetID
 func (n *NetworkManager) GetSubnet(subnetID ids.ID) (*Subnet, bool) {
 	for _, subnet := range n.subnets {
@@ -330,53 +361,66 @@ func (n *NetworkManager) GetSubnet(subnetID ids.ID) (*Subnet, bool) {
 	return nil, false
 }
 
-func RegisterFiveNodeSubnetRun() func() *Subnet {
-	var (
-		config   = NewDefaultANRConfig()
-		manager  = NewNetworkManager(config)
-		numNodes = 5
-	)
-
-	_ = ginkgo.BeforeSuite(func() {
-		// Name 10 new validators (which should have BLS key registered)
-		subnetA := make([]string, 0)
-		for i := 1; i <= numNodes; i++ {
-			subnetA = append(subnetA, fmt.Sprintf("node%d-bls", i))
-		}
+// startNetwork starts the default network and runs [setup] on it. If the
+// SnapshotNameEnvVar environment variable is set, the network is restored from
+// that snapshot instead, or saved as that snapshot after [setup] if it does not
+// exist yet.
+func (n *NetworkManager) startNetwork(ctx context.Context, setup func(ctx context.Context) error) error {
+	if snapshotName := os.Getenv(SnapshotNameEnvVar); len(snapshotName) != 0 {
+		_, err := n.StartOrLoadSnapshot(ctx, snapshotName, setup)
+		return err
+	}
+	if _, err := n.StartDefaultNetwork(ctx); err != nil {
+		return err
+	}
+	return setup(ctx)
+}
 
-		ctx := context.Background()
-		var err error
-		_, err = manager.StartDefaultNetwork(ctx)
-		gomega.Expect(err).Should(gomega.BeNil())
-		err = manager.SetupNetwork(
-			ctx,
-			config.AvalancheGoExecPath,
-			[]*rpcpb.BlockchainSpec{
+// RegisterFiveNodeSubnetRun registers a suite that runs against a single blockchain
+// validated by five nodes.
+func RegisterFiveNodeSubnetRun() func() *Subnet {
+	getBlockchains := RegisterNetworkRun([]SubnetSpec{
+		{
+			Participants: fiveNodeParticipants(),
+			Blockchains: []BlockchainSpec{
 				{
-					VmName:      evm.IDStr,
-					Genesis:     "./tests/load/genesis/genesis.json",
-					ChainConfig: "",
-					SubnetSpec: &rpcpb.SubnetSpec{
-						Participants: subnetA,
-					},
+					Alias:   "load",
+					Genesis: "./tests/load/genesis/genesis.json",
 				},
 			},
-		)
-		gomega.Expect(err).Should(gomega.BeNil())
+		},
 	})
+	return func() *Subnet {
+		return getBlockchains()[0]
+	}
+}
 
-	var _ = ginkgo.AfterSuite(func() {
-		gomega.Expect(manager).ShouldNot(gomega.BeNil())
-		gomega.Expect(manager.TeardownNetwork()).Should(gomega.BeNil())
-		// TODO: bootstrap an additional node to ensure that we can bootstrap the test data correctly
-	})
+// RegisterMultiBlockchainRun registers a suite that runs against one subnet per entry
+// of [blockchainsPerSubnet], each validated by the same five nodes and running the
+// given number of blockchains.
+func RegisterMultiBlockchainRun(blockchainsPerSubnet []int) func() []*Subnet {
+	subnets := make([]SubnetSpec, 0, len(blockchainsPerSubnet))
+	for subnetIndex, n := range blockchainsPerSubnet {
+		subnet := SubnetSpec{
+			Participants: fiveNodeParticipants(),
+		}
+		for i := 0; i < n; i++ {
+			subnet.Blockchains = append(subnet.Blockchains, BlockchainSpec{
+				Alias:   fmt.Sprintf("load-%d-%d", subnetIndex, i),
+				Genesis: "./tests/load/genesis/genesis.json",
+			})
+		}
+		subnets = append(subnets, subnet)
+	}
+	return RegisterNetworkRun(subnets)
+}
 
-	return func() *Subnet {
-		subnetIDs := manager.GetSubnets()
-		gomega.Expect(len(subnetIDs)).Should(gomega.Equal(1))
-		subnetID := subnetIDs[0]
-		subnetDetails, ok := manager.GetSubnet(subnetID)
-		gomega.Expect(ok).Should(gomega.BeTrue())
-		return subnetDetails
+// fiveNodeParticipants returns the names of five new validators (which should have
+// BLS key registered)
+func fiveNodeParticipants() []string {
+	participants := make([]string, 0, 5)
+	for i := 1; i <= 5; i++ {
+		participants = append(participants, fmt.Sprintf("node%d-bls", i))
 	}
+	return participants
 }
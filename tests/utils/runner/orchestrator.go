@@ -0,0 +1,243 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/subnet-evm/plugin/evm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+const (
+	// ANROrchestrator runs the suites against a local network started by an embedded
+	// avalanche-network-runner.
+	ANROrchestrator = "anr"
+	// ExternalOrchestrator runs the suites against a network managed outside of the
+	// test suites, such as a tmpnet network or a long-running devnet.
+	ExternalOrchestrator = "external"
+)
+
+var (
+	_ Orchestrator = (*NetworkManager)(nil)
+	_ Orchestrator = (*ExternalNetwork)(nil)
+
+	orchestratorFlag  string
+	networkConfigFlag string
+)
+
+func init() {
+	flag.StringVar(
+		&orchestratorFlag,
+		"orchestrator",
+		ANROrchestrator,
+		fmt.Sprintf("orchestrator of the network the suites run against (%q or %q)", ANROrchestrator, ExternalOrchestrator),
+	)
+	flag.StringVar(
+		&networkConfigFlag,
+		"network-config",
+		"",
+		fmt.Sprintf("path of the JSON file describing the blockchains of the network, required by the %q orchestrator", ExternalOrchestrator),
+	)
+}
+
+// BlockchainSpec describes a Subnet-EVM blockchain required by a test suite.
+type BlockchainSpec struct {
+	// Alias identifies the blockchain within the network
+	Alias string
+	// Genesis is the path of the genesis file of the blockchain
+	Genesis string
+	// ChainConfig is the path of the chain config file of the blockchain, if any
+	ChainConfig string
+}
+
+// SubnetSpec describes a subnet and the blockchains it validates.
+type SubnetSpec struct {
+	// Participants are the names of the nodes validating the subnet
+	Participants []string
+	Blockchains  []BlockchainSpec
+}
+
+// Orchestrator provides the network that a test suite runs against, so that the
+// same suite can run in any environment.
+type Orchestrator interface {
+	// Start makes the network available and ensures that a blockchain exists for each
+	// blockchain of [subnets].
+	Start(ctx context.Context, subnets []SubnetSpec) error
+	// GetBlockchains returns the details of the blockchains of the network.
+	GetBlockchains() []*Subnet
+	// TeardownNetwork releases the network once the suite is done with it.
+	TeardownNetwork() error
+}
+
+// IsExternalNetwork returns true if the suites run against an externally managed network.
+func IsExternalNetwork() bool {
+	return orchestratorFlag == ExternalOrchestrator
+}
+
+// NewOrchestrator returns the orchestrator selected by the --orchestrator flag.
+func NewOrchestrator() (Orchestrator, error) {
+	switch orchestratorFlag {
+	case ANROrchestrator:
+		return NewNetworkManager(NewDefaultANRConfig()), nil
+	case ExternalOrchestrator:
+		if len(networkConfigFlag) == 0 {
+			return nil, fmt.Errorf("--network-config is required by the %q orchestrator", ExternalOrchestrator)
+		}
+		return NewExternalNetwork(networkConfigFlag), nil
+	default:
+		return nil, fmt.Errorf("unknown orchestrator %q", orchestratorFlag)
+	}
+}
+
+// Start starts the default network and creates the blockchains of [subnets] on it.
+// The first blockchain of each subnet is created along with the subnet, and the
+// remaining blockchains are added to the created subnets afterwards, so
+// GetBlockchains returns the first blockchain of each subnet before the others.
+func (n *NetworkManager) Start(ctx context.Context, subnets []SubnetSpec) error {
+	return n.startNetwork(ctx, func(ctx context.Context) error {
+		first := make([]*rpcpb.BlockchainSpec, 0, len(subnets))
+		for _, subnet := range subnets {
+			if len(subnet.Blockchains) == 0 {
+				return fmt.Errorf("subnet with participants %v has no blockchains", subnet.Participants)
+			}
+			spec := newBlockchainSpec(subnet.Blockchains[0])
+			spec.SubnetSpec = &rpcpb.SubnetSpec{
+				Participants: subnet.Participants,
+			}
+			first = append(first, spec)
+		}
+		numCreated := len(n.subnets)
+		if err := n.SetupNetwork(ctx, n.ANRConfig.AvalancheGoExecPath, first); err != nil {
+			return err
+		}
+
+		var rest []*rpcpb.BlockchainSpec
+		for i, subnet := range subnets {
+			subnetID := n.subnets[numCreated+i].SubnetID.String()
+			for _, blockchain := range subnet.Blockchains[1:] {
+				spec := newBlockchainSpec(blockchain)
+				spec.SubnetId = &subnetID
+				rest = append(rest, spec)
+			}
+		}
+		if len(rest) == 0 {
+			return nil
+		}
+		return n.SetupNetwork(ctx, n.ANRConfig.AvalancheGoExecPath, rest)
+	})
+}
+
+func newBlockchainSpec(blockchain BlockchainSpec) *rpcpb.BlockchainSpec {
+	return &rpcpb.BlockchainSpec{
+		VmName:          evm.IDStr,
+		Genesis:         blockchain.Genesis,
+		ChainConfig:     blockchain.ChainConfig,
+		BlockchainAlias: blockchain.Alias,
+	}
+}
+
+// ExternalNetwork is an Orchestrator for a network that is already running, such as
+// one started by tmpnet. The blockchains of the network are described by a JSON file
+// holding a list of Subnet, and are matched to the requested blockchains by alias.
+// The genesis and chain config of the requested blockchains are not checked.
+type ExternalNetwork struct {
+	configPath  string
+	blockchains []*Subnet
+}
+
+// NewExternalNetwork returns an orchestrator for the network described by [configPath].
+func NewExternalNetwork(configPath string) *ExternalNetwork {
+	return &ExternalNetwork{
+		configPath: configPath,
+	}
+}
+
+// Start finds the blockchains of [subnets] in the network config and waits until
+// every validator has bootstrapped them.
+func (e *ExternalNetwork) Start(ctx context.Context, subnets []SubnetSpec) error {
+	configBytes, err := os.ReadFile(e.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read network config: %w", err)
+	}
+	var available []*Subnet
+	if err := json.Unmarshal(configBytes, &available); err != nil {
+		return fmt.Errorf("failed to unmarshal network config: %w", err)
+	}
+	byAlias := make(map[string]*Subnet, len(available))
+	for _, blockchain := range available {
+		byAlias[blockchain.BlockchainAlias] = blockchain
+	}
+
+	for _, subnet := range subnets {
+		for _, spec := range subnet.Blockchains {
+			blockchain, ok := byAlias[spec.Alias]
+			if !ok {
+				return fmt.Errorf("blockchain %q not found in network config %s", spec.Alias, e.configPath)
+			}
+			for _, uri := range blockchain.ValidatorURIs {
+				log.Info("waiting for blockchain to bootstrap", "alias", spec.Alias, "blockchainID", blockchain.BlockchainID, "uri", uri)
+				bootstrapped, err := info.AwaitBootstrapped(ctx, info.NewClient(uri), blockchain.BlockchainID.String(), time.Second)
+				if err != nil {
+					return fmt.Errorf("failed to await bootstrap of blockchain %q on %s: %w", spec.Alias, uri, err)
+				}
+				if !bootstrapped {
+					return fmt.Errorf("blockchain %q is not bootstrapped on %s", spec.Alias, uri)
+				}
+			}
+			e.blockchains = append(e.blockchains, blockchain)
+		}
+	}
+	return nil
+}
+
+// GetBlockchains returns the details of the blockchains found by Start, in the order
+// they were requested.
+func (e *ExternalNetwork) GetBlockchains() []*Subnet {
+	return e.blockchains
+}
+
+// TeardownNetwork is a no-op, since the network is managed externally.
+func (*ExternalNetwork) TeardownNetwork() error {
+	return nil
+}
+
+// RegisterNetworkRun registers a suite that runs against the network selected by the
+// --orchestrator flag, with the blockchains of [subnets].
+func RegisterNetworkRun(subnets []SubnetSpec) func() []*Subnet {
+	var (
+		orchestrator   Orchestrator
+		numBlockchains = 0
+	)
+	for _, subnet := range subnets {
+		numBlockchains += len(subnet.Blockchains)
+	}
+
+	_ = ginkgo.BeforeSuite(func() {
+		var err error
+		orchestrator, err = NewOrchestrator()
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Expect(orchestrator.Start(context.Background(), subnets)).Should(gomega.BeNil())
+	})
+
+	var _ = ginkgo.AfterSuite(func() {
+		gomega.Expect(orchestrator).ShouldNot(gomega.BeNil())
+		gomega.Expect(orchestrator.TeardownNetwork()).Should(gomega.BeNil())
+	})
+
+	return func() []*Subnet {
+		blockchains := orchestrator.GetBlockchains()
+		gomega.Expect(len(blockchains)).Should(gomega.Equal(numBlockchains))
+		return blockchains
+	}
+}
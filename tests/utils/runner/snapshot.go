@@ -0,0 +1,150 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	runner_sdk "github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SnapshotNameEnvVar is the environment variable that enables snapshots in the
+// suites registered by this package. If it is set, the network is restored from
+// the snapshot with that name if it exists, and saved under that name after
+// setup otherwise.
+const SnapshotNameEnvVar = "E2E_SNAPSHOT_NAME"
+
+// snapshotMetadataPath returns the path of the file holding the blockchains of the
+// snapshot [name]. The ANR snapshot only holds the nodes' state, so the network
+// manager stores the details of the blockchains it created alongside it.
+func (n *NetworkManager) snapshotMetadataPath(name string) string {
+	return filepath.Join(n.ANRConfig.SnapshotsDir, fmt.Sprintf("subnet-evm-%s.json", name))
+}
+
+// HasSnapshot returns true if a snapshot named [name] was saved by a network manager.
+func (n *NetworkManager) HasSnapshot(name string) bool {
+	_, err := os.Stat(n.snapshotMetadataPath(name))
+	return err == nil
+}
+
+// SaveSnapshot saves the database state of every node of the running network as
+// [name], overwriting any existing snapshot with the same name, along with the
+// details of the blockchains created by the network manager.
+// Note: the ANR stops the network when saving a snapshot, so LoadSnapshot must be
+// called to continue using it.
+func (n *NetworkManager) SaveSnapshot(ctx context.Context, name string) error {
+	if err := n.initClient(); err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(n.subnets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot metadata: %w", err)
+	}
+
+	log.Info("Saving network snapshot", "name", name)
+	if _, err := n.anrClient.SaveSnapshot(ctx, name, true); err != nil {
+		return fmt.Errorf("failed to save snapshot %q: %w", name, err)
+	}
+	if err := os.WriteFile(n.snapshotMetadataPath(name), metadata, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot starts a network from the snapshot [name] and restores the details of
+// the blockchains it contains. It replaces any blockchains currently held by the
+// network manager, and assumes no network is running.
+func (n *NetworkManager) LoadSnapshot(ctx context.Context, name string) (<-chan struct{}, error) {
+	metadata, err := os.ReadFile(n.snapshotMetadataPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot metadata: %w", err)
+	}
+	var subnets []*Subnet
+	if err := json.Unmarshal(metadata, &subnets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot metadata: %w", err)
+	}
+	if err := n.init(); err != nil {
+		return nil, err
+	}
+
+	log.Info("Loading network snapshot", "name", name, "AvalancheGoExecPath", n.ANRConfig.AvalancheGoExecPath)
+	resp, err := n.anrClient.LoadSnapshot(
+		ctx,
+		name,
+		runner_sdk.WithExecPath(n.ANRConfig.AvalancheGoExecPath),
+		runner_sdk.WithPluginDir(n.ANRConfig.PluginDir),
+		runner_sdk.WithGlobalNodeConfig(n.ANRConfig.GlobalNodeConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot %q: %w", name, err)
+	}
+	if err := n.awaitHealthy(ctx); err != nil {
+		return nil, err
+	}
+
+	// Node URIs are assigned when the nodes start, so they may differ from the ones
+	// recorded when the snapshot was saved.
+	status, err := n.anrClient.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ANR status: %w", err)
+	}
+	nodeInfos := status.GetClusterInfo().GetNodeInfos()
+	for _, subnet := range subnets {
+		subnet.ValidatorURIs = make([]string, 0, len(subnet.ValidatorNames))
+		for _, nodeName := range subnet.ValidatorNames {
+			nodeInfo, ok := nodeInfos[nodeName]
+			if !ok {
+				return nil, fmt.Errorf("validator %s of subnet %s not found in snapshot %q", nodeName, subnet.SubnetID, name)
+			}
+			subnet.ValidatorURIs = append(subnet.ValidatorURIs, nodeInfo.Uri)
+		}
+	}
+	n.subnets = subnets
+
+	log.Info("successfully loaded snapshot", "RootDataDir", resp.ClusterInfo.RootDataDir, "Subnets", resp.GetClusterInfo().GetSubnets())
+	return n.done, nil
+}
+
+// RemoveSnapshot deletes the snapshot [name].
+func (n *NetworkManager) RemoveSnapshot(ctx context.Context, name string) error {
+	if err := n.initClient(); err != nil {
+		return err
+	}
+	if _, err := n.anrClient.RemoveSnapshot(ctx, name); err != nil {
+		return fmt.Errorf("failed to remove snapshot %q: %w", name, err)
+	}
+	if err := os.Remove(n.snapshotMetadataPath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove snapshot metadata: %w", err)
+	}
+	return nil
+}
+
+// StartOrLoadSnapshot starts the network from the snapshot [name] if it exists.
+// Otherwise, it starts the default network, runs [setup] to construct the
+// blockchains and any expensive state the tests rely on, such as deployed
+// contracts or activated precompiles, and saves the result as [name] so that
+// subsequent runs skip [setup].
+func (n *NetworkManager) StartOrLoadSnapshot(ctx context.Context, name string, setup func(ctx context.Context) error) (<-chan struct{}, error) {
+	if n.HasSnapshot(name) {
+		return n.LoadSnapshot(ctx, name)
+	}
+
+	log.Info("Snapshot not found, setting up network", "name", name)
+	if _, err := n.StartDefaultNetwork(ctx); err != nil {
+		return nil, err
+	}
+	if err := setup(ctx); err != nil {
+		return nil, err
+	}
+	if err := n.SaveSnapshot(ctx, name); err != nil {
+		return nil, err
+	}
+	return n.LoadSnapshot(ctx, name)
+}
@@ -21,13 +21,22 @@ import (
 	wallet "github.com/ava-labs/avalanchego/wallet/subnet/primary"
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/plugin/evm"
+	"github.com/ava-labs/subnet-evm/tests/utils/runner"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/go-cmd/cmd"
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 )
 
+// subnetSuiteData is passed from the process that sets up the network to every
+// process running the tests.
+type subnetSuiteData struct {
+	NodeURI       string            `json:"nodeURI"`
+	BlockchainIDs map[string]string `json:"blockchainIDs"`
+}
+
 type SubnetSuite struct {
+	nodeURI       string
 	blockchainIDs map[string]string
 	lock          sync.RWMutex
 }
@@ -44,8 +53,34 @@ func (s *SubnetSuite) SetBlockchainIDs(blockchainIDs map[string]string) {
 	s.blockchainIDs = blockchainIDs
 }
 
+// GetNodeURI returns the URI of the node serving the blockchains of the suite
+func (s *SubnetSuite) GetNodeURI() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.nodeURI
+}
+
+// GetChainURI returns the RPC URI of the blockchain with [alias]
+func (s *SubnetSuite) GetChainURI(alias string) string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return fmt.Sprintf("%s/ext/bc/%s/rpc", s.nodeURI, s.blockchainIDs[alias])
+}
+
+// RegisterPingTest registers a test that checks the readiness of the node serving the
+// blockchains of the suite
+func (s *SubnetSuite) RegisterPingTest() {
+	ginkgo.It("ping the network", ginkgo.Label("ping"), func() {
+		client := health.NewClient(s.GetNodeURI())
+		healthy, err := client.Readiness(context.Background(), nil)
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Expect(healthy.Healthy).Should(gomega.BeTrue())
+	})
+}
+
 // CreateSubnetsSuite creates subnets for given [genesisFiles], and registers a before suite that starts an AvalancheGo process to use for the e2e tests.
 // genesisFiles is a map of test aliases to genesis file paths.
+// If the suite runs against an external network, the blockchains are looked up by alias in that network instead.
 func CreateSubnetsSuite(genesisFiles map[string]string) *SubnetSuite {
 	// Keep track of the AvalancheGo external bash script, it is null for most
 	// processes except the first process that starts AvalancheGo
@@ -66,6 +101,10 @@ func CreateSubnetsSuite(genesisFiles map[string]string) *SubnetSuite {
 		ctx, cancel := context.WithTimeout(context.Background(), BootAvalancheNodeTimeout)
 		defer cancel()
 
+		if runner.IsExternalNetwork() {
+			return externalSubnetsSuiteData(ctx, genesisFiles)
+		}
+
 		wd, err := os.Getwd()
 		gomega.Expect(err).Should(gomega.BeNil())
 		log.Info("Starting AvalancheGo node", "wd", wd)
@@ -86,21 +125,30 @@ func CreateSubnetsSuite(genesisFiles map[string]string) *SubnetSuite {
 			blockchainIDs[alias] = CreateNewSubnet(ctx, file)
 		}
 
-		blockchainIDsBytes, err := json.Marshal(blockchainIDs)
+		dataBytes, err := json.Marshal(subnetSuiteData{
+			NodeURI:       DefaultLocalNodeURI,
+			BlockchainIDs: blockchainIDs,
+		})
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
-		return blockchainIDsBytes
-	}, func(ctx ginkgo.SpecContext, data []byte) {
-		blockchainIDs := make(map[string]string)
-		err := json.Unmarshal(data, &blockchainIDs)
+		return dataBytes
+	}, func(ctx ginkgo.SpecContext, dataBytes []byte) {
+		var data subnetSuiteData
+		err := json.Unmarshal(dataBytes, &data)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-		globalSuite.SetBlockchainIDs(blockchainIDs)
+		globalSuite.lock.Lock()
+		globalSuite.nodeURI = data.NodeURI
+		globalSuite.lock.Unlock()
+		globalSuite.SetBlockchainIDs(data.BlockchainIDs)
 	})
 
 	// SynchronizedAfterSuite() takes two functions, the first runs after each test suite is done and the second
 	// function is executed once when all the tests are done. This function is used
 	// to gracefully shutdown the AvalancheGo node.
 	var _ = ginkgo.SynchronizedAfterSuite(func() {}, func() {
+		if runner.IsExternalNetwork() {
+			return
+		}
 		gomega.Expect(startCmd).ShouldNot(gomega.BeNil())
 		gomega.Expect(startCmd.Stop()).Should(gomega.BeNil())
 	})
@@ -108,6 +156,42 @@ func CreateSubnetsSuite(genesisFiles map[string]string) *SubnetSuite {
 	return &globalSuite
 }
 
+// externalSubnetsSuiteData returns the suite data for the blockchains of the external
+// network with the aliases of [genesisFiles].
+func externalSubnetsSuiteData(ctx context.Context, genesisFiles map[string]string) []byte {
+	orchestrator, err := runner.NewOrchestrator()
+	gomega.Expect(err).Should(gomega.BeNil())
+
+	subnets := make([]runner.SubnetSpec, 0, len(genesisFiles))
+	for alias, file := range genesisFiles {
+		subnets = append(subnets, runner.SubnetSpec{
+			Blockchains: []runner.BlockchainSpec{
+				{
+					Alias:   alias,
+					Genesis: file,
+				},
+			},
+		})
+	}
+	gomega.Expect(orchestrator.Start(ctx, subnets)).Should(gomega.BeNil())
+
+	data := subnetSuiteData{
+		BlockchainIDs: make(map[string]string),
+	}
+	for _, blockchain := range orchestrator.GetBlockchains() {
+		gomega.Expect(blockchain.ValidatorURIs).ShouldNot(gomega.BeEmpty())
+		// The hardhat tests connect to a single node, so the first validator must validate
+		// every blockchain of the suite
+		if len(data.NodeURI) == 0 {
+			data.NodeURI = blockchain.ValidatorURIs[0]
+		}
+		data.BlockchainIDs[blockchain.BlockchainAlias] = blockchain.BlockchainID.String()
+	}
+	dataBytes, err := json.Marshal(data)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	return dataBytes
+}
+
 // CreateNewSubnet creates a new subnet and Subnet-EVM blockchain with the given genesis file.
 // returns the ID of the new created blockchain.
 func CreateNewSubnet(ctx context.Context, genesisFilePath string) string {
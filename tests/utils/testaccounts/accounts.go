@@ -0,0 +1,130 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package testaccounts provides the accounts used to send transactions in load and
+// e2e tests: it derives accounts from a mnemonic, tracks their nonces concurrently
+// and tops up their balances from a faucet account.
+package testaccounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// hardenedOffset is the first index of hardened BIP-32 child keys.
+const hardenedOffset = 0x80000000
+
+var (
+	errInvalidChildKey = errors.New("invalid child key")
+
+	// masterKeySecret is the HMAC key of the BIP-32 master key derivation.
+	masterKeySecret = []byte("Bitcoin seed")
+)
+
+// Account is a key and the address it controls.
+type Account struct {
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// NewAccount returns the account controlled by [key].
+func NewAccount(key *ecdsa.PrivateKey) *Account {
+	return &Account{
+		Key:     key,
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// FromHex returns the account controlled by the hex encoded private key [hexKey].
+func FromHex(hexKey string) (*Account, error) {
+	key, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewAccount(key), nil
+}
+
+// Derive returns the first [n] accounts derived from [mnemonic] along the default
+// Ethereum derivation path (m/44'/60'/0'/0/i), as derived by common wallets.
+func Derive(mnemonic string, n int) ([]*Account, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	path := make(accounts.DerivationPath, len(accounts.DefaultBaseDerivationPath))
+	copy(path, accounts.DefaultBaseDerivationPath)
+
+	derived := make([]*Account, n)
+	for i := range derived {
+		path[len(path)-1] = uint32(i)
+		key, err := DeriveKey(seed, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account %d: %w", i, err)
+		}
+		derived[i] = NewAccount(key)
+	}
+	return derived, nil
+}
+
+// DeriveKey returns the BIP-32 key at [path] of the master key of [seed].
+func DeriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, masterKeySecret)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	if _, err := crypto.ToECDSA(key); err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+
+	for _, index := range path {
+		var err error
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+	}
+	return crypto.ToECDSA(key)
+}
+
+// deriveChild returns the private key and chain code of the child at [index] of the
+// extended private key ([key], [chainCode]).
+func deriveChild(key []byte, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0)
+		data = append(data, key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&privateKey.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, nil, fmt.Errorf("%w at index %d", errInvalidChildKey, index)
+	}
+	child := tweak.Add(tweak, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("%w at index %d", errInvalidChildKey, index)
+	}
+	return common.LeftPadBytes(child.Bytes(), 32), sum[32:], nil
+}
@@ -0,0 +1,67 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testaccounts
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerive(t *testing.T) {
+	require := require.New(t)
+
+	derived, err := Derive("test test test test test test test test test test test junk", 2)
+	require.NoError(err)
+	require.Len(derived, 2)
+	require.Equal("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", common.Bytes2Hex(crypto.FromECDSA(derived[0].Key)))
+	require.Equal(common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), derived[0].Address)
+	require.Equal(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), derived[1].Address)
+
+	_, err = Derive("test test test", 1)
+	require.Error(err)
+}
+
+type testNonceReader map[common.Address]uint64
+
+func (r testNonceReader) AcceptedNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	return r[account], nil
+}
+
+func TestNonces(t *testing.T) {
+	require := require.New(t)
+	account := common.Address{1}
+	nonces := NewNonces(testNonceReader{account: 5})
+
+	const numSenders = 10
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		seen = make(map[uint64]bool)
+	)
+	for i := 0; i < numSenders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := nonces.Next(context.Background(), account)
+			require.NoError(err)
+			lock.Lock()
+			seen[nonce] = true
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	for nonce := uint64(5); nonce < 5+numSenders; nonce++ {
+		require.True(seen[nonce], "nonce %d was not handed out", nonce)
+	}
+
+	nonces.Reset(account)
+	nonce, err := nonces.Next(context.Background(), account)
+	require.NoError(err)
+	require.Equal(uint64(5), nonce)
+}
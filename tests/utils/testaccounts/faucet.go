@@ -0,0 +1,147 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testaccounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Client is the subset of ethclient.Client used to send transactions.
+type Client interface {
+	NonceReader
+	ChainID(ctx context.Context) (*big.Int, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	EstimateBaseFee(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// Sender signs and sends transactions from test accounts, using [Nonces] so that
+// transactions can be sent concurrently.
+type Sender struct {
+	client Client
+	nonces *Nonces
+}
+
+func NewSender(client Client) *Sender {
+	return &Sender{
+		client: client,
+		nonces: NewNonces(client),
+	}
+}
+
+// Send signs the transaction returned by [newTx] for the next nonce of [from] and
+// sends it.
+func (s *Sender) Send(ctx context.Context, from *Account, newTx func(chainID *big.Int, nonce uint64) *types.Transaction) (*types.Transaction, error) {
+	chainID, err := s.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chainID: %w", err)
+	}
+	nonce, err := s.nonces.Next(ctx, from.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch nonce of %s: %w", from.Address, err)
+	}
+	signedTx, err := types.SignTx(newTx(chainID, nonce), types.LatestSignerForChainID(chainID), from.Key)
+	if err != nil {
+		s.nonces.Reset(from.Address)
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := s.client.SendTransaction(ctx, signedTx); err != nil {
+		s.nonces.Reset(from.Address)
+		return nil, fmt.Errorf("failed to send transaction %s: %w", signedTx.Hash(), err)
+	}
+	return signedTx, nil
+}
+
+// SendAndWait sends a transaction like [Send] and waits for its successful receipt.
+func (s *Sender) SendAndWait(ctx context.Context, from *Account, newTx func(chainID *big.Int, nonce uint64) *types.Transaction) (*types.Receipt, error) {
+	tx, err := s.Send(ctx, from, newTx)
+	if err != nil {
+		return nil, err
+	}
+	return s.wait(ctx, tx)
+}
+
+func (s *Sender) wait(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, s.client, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to await transaction %s: %w", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s failed", tx.Hash())
+	}
+	return receipt, nil
+}
+
+// Faucet tops up the balances of test accounts from a funded account.
+type Faucet struct {
+	account *Account
+	sender  *Sender
+}
+
+func NewFaucet(account *Account, sender *Sender) *Faucet {
+	return &Faucet{
+		account: account,
+		sender:  sender,
+	}
+}
+
+// TopUp sends [amount] to each of [accounts] with a balance below [minBalance], and
+// waits for the transfers to be accepted.
+func (f *Faucet) TopUp(ctx context.Context, accounts []*Account, minBalance *big.Int, amount *big.Int) error {
+	client := f.sender.client
+	gasFeeCap, err := client.EstimateBaseFee(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch estimated base fee: %w", err)
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch suggested gas tip: %w", err)
+	}
+	gasFeeCap.Add(gasFeeCap, gasTipCap)
+
+	var txs []*types.Transaction
+	for _, account := range accounts {
+		balance, err := client.BalanceAt(ctx, account.Address, nil)
+		if err != nil {
+			return fmt.Errorf("failed to fetch balance of %s: %w", account.Address, err)
+		}
+		if balance.Cmp(minBalance) >= 0 {
+			continue
+		}
+		to := account.Address
+		tx, err := f.sender.Send(ctx, f.account, func(chainID *big.Int, nonce uint64) *types.Transaction {
+			return types.NewTx(&types.DynamicFeeTx{
+				ChainID:   chainID,
+				Nonce:     nonce,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
+				Gas:       params.TxGas,
+				To:        &to,
+				Value:     amount,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+	}
+	for _, tx := range txs {
+		if _, err := f.sender.wait(ctx, tx); err != nil {
+			return err
+		}
+	}
+	log.Info("Topped up test accounts", "faucet", f.account.Address, "funded", len(txs), "accounts", len(accounts))
+	return nil
+}
@@ -0,0 +1,60 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testaccounts
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceReader reads the nonces of accounts from a chain.
+type NonceReader interface {
+	AcceptedNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// Nonces hands out the nonces of accounts to concurrent senders, so that each
+// transaction sent from an account gets the next nonce without waiting for the
+// previous one to be accepted.
+type Nonces struct {
+	reader NonceReader
+
+	lock sync.Mutex
+	next map[common.Address]uint64
+}
+
+func NewNonces(reader NonceReader) *Nonces {
+	return &Nonces{
+		reader: reader,
+		next:   make(map[common.Address]uint64),
+	}
+}
+
+// Next returns the next nonce of [account], reading it from the chain the first time
+// the account is used or after it is reset.
+func (n *Nonces) Next(ctx context.Context, account common.Address) (uint64, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	nonce, ok := n.next[account]
+	if !ok {
+		var err error
+		nonce, err = n.reader.AcceptedNonceAt(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+	}
+	n.next[account] = nonce + 1
+	return nonce, nil
+}
+
+// Reset forgets the nonce of [account], so that the next nonce is read from the
+// chain. It must be called when a transaction using a nonce from [Next] is not sent.
+func (n *Nonces) Reset(account common.Address) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	delete(n.next, account)
+}
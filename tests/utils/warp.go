@@ -0,0 +1,242 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/tests/utils/runner"
+	"github.com/ava-labs/subnet-evm/tests/utils/testaccounts"
+	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
+	warpBackend "github.com/ava-labs/subnet-evm/warp"
+	"github.com/ava-labs/subnet-evm/x/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+const (
+	// WarpGenesis is the genesis of the blockchains used by warp tests
+	WarpGenesis = "./tests/precompile/genesis/warp.json"
+
+	// WarpChainConfig is the chain config of the blockchains used by warp tests. It enables
+	// the warp API to aggregate signatures and the tracer API to read the output of
+	// getVerifiedWarpMessage.
+	WarpChainConfig = `{
+		"warp-api-enabled": true,
+		"eth-apis": ["eth", "eth-filter", "net", "web3", "internal-eth", "internal-blockchain", "internal-transaction", "debug-tracer"]
+	}`
+
+	// WarpFundedKeyStr is the key funded in [WarpGenesis]
+	WarpFundedKeyStr = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+
+	// warpTimeout bounds how long to wait for a transaction or block to be accepted
+	warpTimeout = 30 * time.Second
+)
+
+// ToWebsocketURI returns the websocket URI of [blockchainID] on the node at [uri]
+func ToWebsocketURI(uri string, blockchainID string) string {
+	return fmt.Sprintf("ws://%s/ext/bc/%s/ws", strings.TrimPrefix(uri, "http://"), blockchainID)
+}
+
+// RegisterTwoSubnetWarpRun registers a suite that brings up two subnets, each validated
+// by a disjoint set of 5 nodes and running a single warp enabled blockchain. It returns
+// a function to get the source and destination blockchains once the suite has started.
+func RegisterTwoSubnetWarpRun() func() (*runner.Subnet, *runner.Subnet) {
+	var (
+		orchestrator    runner.Orchestrator
+		chainConfigPath string
+	)
+
+	_ = ginkgo.BeforeSuite(func() {
+		ctx := context.Background()
+
+		f, err := os.CreateTemp(os.TempDir(), "config.json")
+		gomega.Expect(err).Should(gomega.BeNil())
+		_, err = f.Write([]byte(WarpChainConfig))
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Expect(f.Close()).Should(gomega.BeNil())
+		chainConfigPath = f.Name()
+
+		subnets := make([]runner.SubnetSpec, 0, 2)
+		for i, alias := range []string{"warp-a", "warp-b"} {
+			participants := make([]string, 0, 5)
+			for j := 1; j <= 5; j++ {
+				participants = append(participants, fmt.Sprintf("node%d-bls", 5*i+j))
+			}
+			subnets = append(subnets, runner.SubnetSpec{
+				Participants: participants,
+				Blockchains: []runner.BlockchainSpec{
+					{
+						Alias:       alias,
+						Genesis:     WarpGenesis,
+						ChainConfig: chainConfigPath,
+					},
+				},
+			})
+		}
+
+		orchestrator, err = runner.NewOrchestrator()
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Expect(orchestrator.Start(ctx, subnets)).Should(gomega.BeNil())
+
+		// Issue transactions to activate the proposerVM fork on the receiving chain
+		destination := orchestrator.GetBlockchains()[1]
+		fundedKey, err := crypto.HexToECDSA(WarpFundedKeyStr)
+		gomega.Expect(err).Should(gomega.BeNil())
+		client, err := ethclient.Dial(ToWebsocketURI(destination.ValidatorURIs[0], destination.BlockchainID.String()))
+		gomega.Expect(err).Should(gomega.BeNil())
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Expect(IssueTxsToActivateProposerVMFork(ctx, chainID, fundedKey, client)).Should(gomega.BeNil())
+	})
+
+	var _ = ginkgo.AfterSuite(func() {
+		gomega.Expect(orchestrator).ShouldNot(gomega.BeNil())
+		gomega.Expect(orchestrator.TeardownNetwork()).Should(gomega.BeNil())
+		gomega.Expect(os.Remove(chainConfigPath)).Should(gomega.BeNil())
+	})
+
+	return func() (*runner.Subnet, *runner.Subnet) {
+		blockchains := orchestrator.GetBlockchains()
+		gomega.Expect(len(blockchains)).Should(gomega.Equal(2))
+		return blockchains[0], blockchains[1]
+	}
+}
+
+// WarpRoundTrip sends a warp message with [payload] from [source] to [fundedKey]'s
+// address on [destination], aggregates the signatures of the validators of [source],
+// delivers the signed message to [destination] and asserts that getVerifiedWarpMessage
+// returns the sent message. It returns the signed warp message.
+// Both blockchains must use [WarpChainConfig] and have [fundedKey] funded.
+func WarpRoundTrip(ctx context.Context, source, destination *runner.Subnet, fundedKey *ecdsa.PrivateKey, payload []byte) *avalancheWarp.Message {
+	fundedAddress := crypto.PubkeyToAddress(fundedKey.PublicKey)
+
+	sourceClient, err := ethclient.Dial(ToWebsocketURI(source.ValidatorURIs[0], source.BlockchainID.String()))
+	gomega.Expect(err).Should(gomega.BeNil())
+	defer sourceClient.Close()
+	destinationClient, err := ethclient.Dial(ToWebsocketURI(destination.ValidatorURIs[0], destination.BlockchainID.String()))
+	gomega.Expect(err).Should(gomega.BeNil())
+	defer destinationClient.Close()
+
+	// Send the warp message from the source blockchain
+	packedInput, err := warp.PackSendWarpMessage(warp.SendWarpMessageInput{
+		DestinationChainID: common.Hash(destination.BlockchainID),
+		DestinationAddress: fundedAddress,
+		Payload:            payload,
+	})
+	gomega.Expect(err).Should(gomega.BeNil())
+	receipt := sendAndAwaitReceipt(ctx, sourceClient, fundedKey, func(chainID *big.Int, nonce uint64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			To:        &warp.Module.Address,
+			Gas:       200_000,
+			GasFeeCap: big.NewInt(225 * params.GWei),
+			GasTipCap: big.NewInt(params.GWei),
+			Value:     common.Big0,
+			Data:      packedInput,
+		})
+	})
+
+	logs, err := sourceClient.FilterLogs(ctx, interfaces.FilterQuery{
+		BlockHash: &receipt.BlockHash,
+		Addresses: []common.Address{warp.Module.Address},
+	})
+	gomega.Expect(err).Should(gomega.BeNil())
+	gomega.Expect(len(logs)).Should(gomega.Equal(1))
+	unsignedMsg, err := avalancheWarp.ParseUnsignedMessage(logs[0].Data)
+	gomega.Expect(err).Should(gomega.BeNil())
+	log.Info("Sent warp message", "messageID", unsignedMsg.ID(), "txHash", receipt.TxHash)
+
+	// Every validator must accept the block before it can sign the message
+	for _, uri := range source.ValidatorURIs {
+		client, err := ethclient.Dial(ToWebsocketURI(uri, source.BlockchainID.String()))
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Eventually(func() uint64 {
+			height, err := client.BlockNumber(ctx)
+			gomega.Expect(err).Should(gomega.BeNil())
+			return height
+		}, warpTimeout).Should(gomega.BeNumerically(">=", receipt.BlockNumber.Uint64()))
+		client.Close()
+	}
+
+	// Aggregate the signatures of every validator of the source subnet
+	warpClient, err := warpBackend.NewClient(source.ValidatorURIs[0], source.BlockchainID.String())
+	gomega.Expect(err).Should(gomega.BeNil())
+	signedMsgBytes, err := warpClient.GetAggregateSignature(ctx, unsignedMsg.ID(), params.WarpQuorumDenominator)
+	gomega.Expect(err).Should(gomega.BeNil())
+	signedMsg, err := avalancheWarp.ParseMessage(signedMsgBytes)
+	gomega.Expect(err).Should(gomega.BeNil())
+	gomega.Expect(signedMsg.UnsignedMessage.ID()).Should(gomega.Equal(unsignedMsg.ID()))
+
+	// Deliver the signed message to the destination blockchain
+	packedInput, err = warp.PackGetVerifiedWarpMessage(0)
+	gomega.Expect(err).Should(gomega.BeNil())
+	receipt = sendAndAwaitReceipt(ctx, destinationClient, fundedKey, func(chainID *big.Int, nonce uint64) *types.Transaction {
+		return predicateutils.NewPredicateTx(
+			chainID,
+			nonce,
+			&warp.Module.Address,
+			5_000_000,
+			big.NewInt(225*params.GWei),
+			big.NewInt(params.GWei),
+			common.Big0,
+			packedInput,
+			types.AccessList{},
+			warp.ContractAddress,
+			signedMsgBytes,
+		)
+	})
+
+	// Read the output of getVerifiedWarpMessage from the trace of the delivery, since the
+	// predicate results it depends on are only available to transactions in a block.
+	rpcClient, err := rpc.DialContext(ctx, ToWebsocketURI(destination.ValidatorURIs[0], destination.BlockchainID.String()))
+	gomega.Expect(err).Should(gomega.BeNil())
+	defer rpcClient.Close()
+	var trace struct {
+		Output hexutil.Bytes `json:"output"`
+	}
+	err = rpcClient.CallContext(ctx, &trace, "debug_traceTransaction", receipt.TxHash, map[string]string{"tracer": "callTracer"})
+	gomega.Expect(err).Should(gomega.BeNil())
+	output, err := warp.UnpackGetVerifiedWarpMessageOutput(trace.Output)
+	gomega.Expect(err).Should(gomega.BeNil())
+	gomega.Expect(output.Valid).Should(gomega.BeTrue())
+	gomega.Expect(output.Message).Should(gomega.Equal(warp.WarpMessage{
+		SourceChainID:       common.Hash(source.BlockchainID),
+		OriginSenderAddress: fundedAddress,
+		DestinationChainID:  common.Hash(destination.BlockchainID),
+		DestinationAddress:  fundedAddress,
+		Payload:             payload,
+	}))
+	log.Info("Delivered warp message", "messageID", unsignedMsg.ID(), "txHash", receipt.TxHash)
+
+	return signedMsg
+}
+
+// sendAndAwaitReceipt signs the transaction returned by [newTx] for the next nonce of
+// [key], sends it with [client] and waits for its successful receipt.
+func sendAndAwaitReceipt(ctx context.Context, client ethclient.Client, key *ecdsa.PrivateKey, newTx func(chainID *big.Int, nonce uint64) *types.Transaction) *types.Receipt {
+	ctx, cancel := context.WithTimeout(ctx, warpTimeout)
+	defer cancel()
+	receipt, err := testaccounts.NewSender(client).SendAndWait(ctx, testaccounts.NewAccount(key), newTx)
+	gomega.Expect(err).Should(gomega.BeNil())
+	return receipt
+}
@@ -10,10 +10,8 @@ import (
 	"fmt"
 	"math/big"
 	"os"
-	"strings"
 	"testing"
 
-	"github.com/ava-labs/avalanche-network-runner/rpcpb"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
@@ -23,7 +21,6 @@ import (
 	"github.com/ava-labs/subnet-evm/ethclient"
 	"github.com/ava-labs/subnet-evm/interfaces"
 	"github.com/ava-labs/subnet-evm/params"
-	"github.com/ava-labs/subnet-evm/plugin/evm"
 	"github.com/ava-labs/subnet-evm/tests/utils"
 	"github.com/ava-labs/subnet-evm/tests/utils/runner"
 	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
@@ -36,11 +33,8 @@ import (
 	"github.com/onsi/gomega"
 )
 
-const fundedKeyStr = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
-
 var (
-	config              = runner.NewDefaultANRConfig()
-	manager             = runner.NewNetworkManager(config)
+	orchestrator        runner.Orchestrator
 	warpChainConfigPath string
 )
 
@@ -49,10 +43,6 @@ func TestE2E(t *testing.T) {
 	ginkgo.RunSpecs(t, "subnet-evm warp e2e test")
 }
 
-func toWebsocketURI(uri string, blockchainID string) string {
-	return fmt.Sprintf("ws://%s/ext/bc/%s/ws", strings.TrimPrefix(uri, "http://"), blockchainID)
-}
-
 // BeforeSuite starts the default network and adds 10 new nodes as validators with BLS keys
 // registered on the P-Chain.
 // Adds two disjoint sets of 5 of the new validator nodes to validate two new subnets with a
@@ -73,33 +63,34 @@ var _ = ginkgo.BeforeSuite(func() {
 	}
 	f, err := os.CreateTemp(os.TempDir(), "config.json")
 	gomega.Expect(err).Should(gomega.BeNil())
-	_, err = f.Write([]byte(`{"warp-api-enabled": true}`))
+	_, err = f.Write([]byte(utils.WarpChainConfig))
 	gomega.Expect(err).Should(gomega.BeNil())
 	warpChainConfigPath = f.Name()
 
-	// Construct the network using the avalanche-network-runner
-	_, err = manager.StartDefaultNetwork(ctx)
+	// Construct the network using the orchestrator selected by flag
+	orchestrator, err = runner.NewOrchestrator()
 	gomega.Expect(err).Should(gomega.BeNil())
-	err = manager.SetupNetwork(
+	err = orchestrator.Start(
 		ctx,
-		config.AvalancheGoExecPath,
-		[]*rpcpb.BlockchainSpec{
+		[]runner.SubnetSpec{
 			{
-				VmName:      evm.IDStr,
-				Genesis:     "./tests/precompile/genesis/warp.json",
-				ChainConfig: warpChainConfigPath,
-				SubnetSpec: &rpcpb.SubnetSpec{
-					SubnetConfig: "",
-					Participants: subnetANodeNames,
+				Participants: subnetANodeNames,
+				Blockchains: []runner.BlockchainSpec{
+					{
+						Alias:       "warp-a",
+						Genesis:     utils.WarpGenesis,
+						ChainConfig: warpChainConfigPath,
+					},
 				},
 			},
 			{
-				VmName:      evm.IDStr,
-				Genesis:     "./tests/precompile/genesis/warp.json",
-				ChainConfig: warpChainConfigPath,
-				SubnetSpec: &rpcpb.SubnetSpec{
-					SubnetConfig: "",
-					Participants: subnetBNodeNames,
+				Participants: subnetBNodeNames,
+				Blockchains: []runner.BlockchainSpec{
+					{
+						Alias:       "warp-b",
+						Genesis:     utils.WarpGenesis,
+						ChainConfig: warpChainConfigPath,
+					},
 				},
 			},
 		},
@@ -108,14 +99,12 @@ var _ = ginkgo.BeforeSuite(func() {
 
 	// Issue transactions to activate the proposerVM fork on the receiving chain
 	chainID := big.NewInt(99999)
-	fundedKey, err := crypto.HexToECDSA(fundedKeyStr)
+	fundedKey, err := crypto.HexToECDSA(utils.WarpFundedKeyStr)
 	gomega.Expect(err).Should(gomega.BeNil())
-	subnetB := manager.GetSubnets()[1]
-	subnetBDetails, ok := manager.GetSubnet(subnetB)
-	gomega.Expect(ok).Should(gomega.BeTrue())
+	subnetBDetails := orchestrator.GetBlockchains()[1]
 
 	chainBID := subnetBDetails.BlockchainID
-	uri := toWebsocketURI(subnetBDetails.ValidatorURIs[0], chainBID.String())
+	uri := utils.ToWebsocketURI(subnetBDetails.ValidatorURIs[0], chainBID.String())
 	client, err := ethclient.Dial(uri)
 	gomega.Expect(err).Should(gomega.BeNil())
 
@@ -124,8 +113,8 @@ var _ = ginkgo.BeforeSuite(func() {
 })
 
 var _ = ginkgo.AfterSuite(func() {
-	gomega.Expect(manager).ShouldNot(gomega.BeNil())
-	gomega.Expect(manager.TeardownNetwork()).Should(gomega.BeNil())
+	gomega.Expect(orchestrator).ShouldNot(gomega.BeNil())
+	gomega.Expect(orchestrator.TeardownNetwork()).Should(gomega.BeNil())
 	gomega.Expect(os.Remove(warpChainConfigPath)).Should(gomega.BeNil())
 	// TODO: bootstrap an additional node to ensure that we can bootstrap the test data correctly
 })
@@ -146,38 +135,34 @@ var _ = ginkgo.Describe("[Warp]", ginkgo.Ordered, func() {
 		err                            error
 	)
 
-	fundedKey, err = crypto.HexToECDSA(fundedKeyStr)
+	fundedKey, err = crypto.HexToECDSA(utils.WarpFundedKeyStr)
 	if err != nil {
 		panic(err)
 	}
 	fundedAddress = crypto.PubkeyToAddress(fundedKey.PublicKey)
 
 	ginkgo.It("Setup URIs", ginkgo.Label("Warp", "SetupWarp"), func() {
-		subnetIDs := manager.GetSubnets()
-		gomega.Expect(len(subnetIDs)).Should(gomega.Equal(2))
+		blockchains := orchestrator.GetBlockchains()
+		gomega.Expect(len(blockchains)).Should(gomega.Equal(2))
 
-		subnetA := subnetIDs[0]
-		subnetADetails, ok := manager.GetSubnet(subnetA)
-		gomega.Expect(ok).Should(gomega.BeTrue())
+		subnetADetails := blockchains[0]
 		blockchainIDA = subnetADetails.BlockchainID
 		gomega.Expect(len(subnetADetails.ValidatorURIs)).Should(gomega.Equal(5))
 		chainAURIs = append(chainAURIs, subnetADetails.ValidatorURIs...)
 
-		subnetB := subnetIDs[1]
-		subnetBDetails, ok := manager.GetSubnet(subnetB)
-		gomega.Expect(ok).Should(gomega.BeTrue())
+		subnetBDetails := blockchains[1]
 		blockchainIDB := subnetBDetails.BlockchainID
 		gomega.Expect(len(subnetBDetails.ValidatorURIs)).Should(gomega.Equal(5))
 		chainBURIs = append(chainBURIs, subnetBDetails.ValidatorURIs...)
 
 		log.Info("Created URIs for both subnets", "ChainAURIs", chainAURIs, "ChainBURIs", chainBURIs, "blockchainIDA", blockchainIDA, "blockchainIDB", blockchainIDB)
 
-		chainAWSURI := toWebsocketURI(chainAURIs[0], blockchainIDA.String())
+		chainAWSURI := utils.ToWebsocketURI(chainAURIs[0], blockchainIDA.String())
 		log.Info("Creating ethclient for blockchainA", "wsURI", chainAWSURI)
 		chainAWSClient, err = ethclient.Dial(chainAWSURI)
 		gomega.Expect(err).Should(gomega.BeNil())
 
-		chainBWSURI := toWebsocketURI(chainBURIs[0], blockchainIDB.String())
+		chainBWSURI := utils.ToWebsocketURI(chainBURIs[0], blockchainIDB.String())
 		log.Info("Creating ethclient for blockchainB", "wsURI", chainBWSURI)
 		chainBWSClient, err = ethclient.Dial(chainBWSURI)
 		gomega.Expect(err).Should(gomega.BeNil())
@@ -249,7 +234,7 @@ var _ = ginkgo.Describe("[Warp]", ginkgo.Ordered, func() {
 		// Note: if we did not confirm this here, the next stage could be racy since it assumes every node
 		// has accepted the block.
 		for i, uri := range chainAURIs {
-			chainAWSURI := toWebsocketURI(uri, blockchainIDA.String())
+			chainAWSURI := utils.ToWebsocketURI(uri, blockchainIDA.String())
 			log.Info("Creating ethclient for blockchainA", "wsURI", chainAWSURI)
 			client, err := ethclient.Dial(chainAWSURI)
 			gomega.Expect(err).Should(gomega.BeNil())
@@ -380,4 +365,10 @@ var _ = ginkgo.Describe("[Warp]", ginkgo.Ordered, func() {
 		gomega.Expect(err).Should(gomega.BeNil())
 		gomega.Expect(receipt.Status).Should(gomega.Equal(types.ReceiptStatusSuccessful))
 	})
+
+	// Send, aggregate, deliver and verify a second message end to end with the reusable helper
+	ginkgo.It("Round trip from A to B", ginkgo.Label("Warp", "RoundTripWarp"), func() {
+		blockchains := orchestrator.GetBlockchains()
+		utils.WarpRoundTrip(context.Background(), blockchains[0], blockchains[1], fundedKey, []byte{4, 5, 6})
+	})
 })
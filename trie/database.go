@@ -31,6 +31,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"runtime"
 	"sync"
 	"time"
 
@@ -939,6 +940,20 @@ func (db *Database) saveCache(dir string, threads int) error {
 	return nil
 }
 
+// EvictClean removes the node with the given hash from the clean cache, so that
+// it is read from disk again.
+func (db *Database) EvictClean(hash common.Hash) {
+	if db.cleans != nil {
+		db.cleans.Del(hash[:])
+	}
+}
+
+// SaveCache saves the clean state cache to the given directory path using all
+// available CPU cores.
+func (db *Database) SaveCache(dir string) error {
+	return db.saveCache(dir, runtime.GOMAXPROCS(0))
+}
+
 // SaveCachePeriodically atomically saves fast cache data to the given dir with
 // the specified interval. All dump operation will only use a single CPU core.
 func (db *Database) SaveCachePeriodically(dir string, interval time.Duration, stopCh <-chan struct{}) {
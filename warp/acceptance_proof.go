@@ -0,0 +1,81 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	errProofWrongChain     = errors.New("acceptance proof message was not sent by the expected chain")
+	errProofWrongMessageID = errors.New("acceptance proof message ID does not match the signed message")
+	errProofWrongBlockHash = errors.New("acceptance proof block hash does not match the signed message")
+)
+
+// AcceptanceProof proves that a block was accepted by the validators of the chain
+// that produced it. Validators only sign the block hash message of a block after
+// accepting it, so a quorum of signatures over the message proves its acceptance.
+type AcceptanceProof struct {
+	BlockHash common.Hash `json:"blockHash"`
+	// MessageID is the ID of the unsigned warp message with the block hash payload.
+	MessageID ids.ID `json:"messageID"`
+	// SignedMessage is the warp message with the aggregate signature.
+	SignedMessage hexutil.Bytes `json:"signedMessage"`
+	// PChainHeight is the P-Chain height of the validator set that signed the
+	// message, which the proof must be verified against.
+	PChainHeight    uint64 `json:"pChainHeight"`
+	SignatureWeight uint64 `json:"signatureWeight"`
+	TotalWeight     uint64 `json:"totalWeight"`
+}
+
+// newBlockHashMessage returns the unsigned warp message signed by the validators of
+// [sourceChainID] once they accept the block with [blockHash].
+func newBlockHashMessage(networkID uint32, sourceChainID ids.ID, blockHash common.Hash) (*avalancheWarp.UnsignedMessage, error) {
+	blockHashPayload, err := warpPayload.NewBlockHashPayload(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block hash payload: %w", err)
+	}
+	return avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, blockHashPayload.Bytes())
+}
+
+// VerifyAcceptanceProof verifies that [proof] proves the acceptance of its block by
+// [quorumNum]/[quorumDen] of the stake of the validators of [sourceChainID], as
+// reported by [pChainState] at the P-Chain height of the proof.
+func VerifyAcceptanceProof(
+	ctx context.Context,
+	proof *AcceptanceProof,
+	networkID uint32,
+	sourceChainID ids.ID,
+	pChainState validators.State,
+	quorumNum uint64,
+	quorumDen uint64,
+) error {
+	msg, err := avalancheWarp.ParseMessage(proof.SignedMessage)
+	if err != nil {
+		return fmt.Errorf("failed to parse signed message: %w", err)
+	}
+	if msg.SourceChainID != sourceChainID {
+		return fmt.Errorf("%w: expected %s, got %s", errProofWrongChain, sourceChainID, msg.SourceChainID)
+	}
+	if msg.ID() != proof.MessageID {
+		return fmt.Errorf("%w: expected %s, got %s", errProofWrongMessageID, proof.MessageID, msg.ID())
+	}
+	blockHashPayload, err := warpPayload.ParseBlockHashPayload(msg.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse block hash payload: %w", err)
+	}
+	if blockHashPayload.BlockHash != proof.BlockHash {
+		return fmt.Errorf("%w: expected %s, got %s", errProofWrongBlockHash, proof.BlockHash, blockHashPayload.BlockHash)
+	}
+	return msg.Signature.Verify(ctx, &msg.UnsignedMessage, networkID, pChainState, proof.PChainHeight, quorumNum, quorumDen)
+}
@@ -0,0 +1,102 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAcceptanceProof(t *testing.T) {
+	var (
+		subnetID     = ids.GenerateTestID()
+		pChainHeight = uint64(10)
+		blockHash    = common.Hash{1, 2, 3}
+	)
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	otherSK, err := bls.NewSecretKey()
+	require.NoError(t, err)
+
+	pChainState := &validators.TestState{
+		GetSubnetIDF: func(ctx context.Context, chainID ids.ID) (ids.ID, error) {
+			return subnetID, nil
+		},
+		GetValidatorSetF: func(ctx context.Context, height uint64, requestedSubnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			require.Equal(t, pChainHeight, height)
+			require.Equal(t, subnetID, requestedSubnetID)
+			nodeID := ids.GenerateTestNodeID()
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeID: {NodeID: nodeID, PublicKey: bls.PublicFromSecretKey(sk), Weight: 100},
+			}, nil
+		},
+	}
+
+	newProof := func(t *testing.T, signer *bls.SecretKey, hash common.Hash) *AcceptanceProof {
+		unsignedMessage, err := newBlockHashMessage(networkID, sourceChainID, hash)
+		require.NoError(t, err)
+		sig := bls.Sign(signer, unsignedMessage.Bytes())
+		signature := &avalancheWarp.BitSetSignature{Signers: set.NewBits(0).Bytes()}
+		copy(signature.Signature[:], bls.SignatureToBytes(sig))
+		msg, err := avalancheWarp.NewMessage(unsignedMessage, signature)
+		require.NoError(t, err)
+		return &AcceptanceProof{
+			BlockHash:       blockHash,
+			MessageID:       unsignedMessage.ID(),
+			SignedMessage:   msg.Bytes(),
+			PChainHeight:    pChainHeight,
+			SignatureWeight: 100,
+			TotalWeight:     100,
+		}
+	}
+
+	tests := map[string]struct {
+		proof         func(t *testing.T) *AcceptanceProof
+		sourceChainID ids.ID
+		expectedErr   error
+	}{
+		"valid proof": {
+			proof:         func(t *testing.T) *AcceptanceProof { return newProof(t, sk, blockHash) },
+			sourceChainID: sourceChainID,
+		},
+		"wrong source chain": {
+			proof:         func(t *testing.T) *AcceptanceProof { return newProof(t, sk, blockHash) },
+			sourceChainID: ids.GenerateTestID(),
+			expectedErr:   errProofWrongChain,
+		},
+		"wrong message ID": {
+			proof: func(t *testing.T) *AcceptanceProof {
+				proof := newProof(t, sk, blockHash)
+				proof.MessageID = ids.GenerateTestID()
+				return proof
+			},
+			sourceChainID: sourceChainID,
+			expectedErr:   errProofWrongMessageID,
+		},
+		"wrong block hash": {
+			proof:         func(t *testing.T) *AcceptanceProof { return newProof(t, sk, common.Hash{4, 5, 6}) },
+			sourceChainID: sourceChainID,
+			expectedErr:   errProofWrongBlockHash,
+		},
+		"signed by non-validator": {
+			proof:         func(t *testing.T) *AcceptanceProof { return newProof(t, otherSK, blockHash) },
+			sourceChainID: sourceChainID,
+			expectedErr:   avalancheWarp.ErrInvalidSignature,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyAcceptanceProof(context.Background(), test.proof(t), networkID, test.sourceChainID, pChainState, 67, 100)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
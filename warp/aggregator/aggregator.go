@@ -7,6 +7,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/ava-labs/subnet-evm/params"
 
@@ -19,7 +23,31 @@ import (
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
 )
 
-var errNoValidators = errors.New("cannot aggregate signatures from subnet with no validators")
+// maxValidatorSetRefreshes is the maximum number of times the validator set is
+// re-fetched during a single aggregation because the P-Chain height advanced.
+const maxValidatorSetRefreshes = 3
+
+var (
+	errNoValidators  = errors.New("cannot aggregate signatures from subnet with no validators")
+	errInvalidQuorum = errors.New("invalid quorum")
+)
+
+// ValidateQuorum returns an error if [quorumNum]/[quorumDen] is not a valid quorum to
+// aggregate signatures for. The quorum must require at least
+// [params.WarpQuorumNumeratorMinimum]/[params.WarpQuorumDenominator] of the stake
+// and must not exceed the total stake.
+func ValidateQuorum(quorumNum uint64, quorumDen uint64) error {
+	if quorumDen == 0 || quorumNum > quorumDen {
+		return fmt.Errorf("%w: %d/%d", errInvalidQuorum, quorumNum, quorumDen)
+	}
+	// Compare quorumNum/quorumDen with the minimum quorum without overflowing.
+	scaledNum := new(big.Int).Mul(new(big.Int).SetUint64(quorumNum), new(big.Int).SetUint64(params.WarpQuorumDenominator))
+	scaledMin := new(big.Int).Mul(new(big.Int).SetUint64(params.WarpQuorumNumeratorMinimum), new(big.Int).SetUint64(quorumDen))
+	if scaledNum.Cmp(scaledMin) < 0 {
+		return fmt.Errorf("quorum %d/%d is below the minimum quorum %d/%d", quorumNum, quorumDen, params.WarpQuorumNumeratorMinimum, params.WarpQuorumDenominator)
+	}
+	return nil
+}
 
 // SignatureGetter defines the minimum network interface to perform signature aggregation
 type SignatureGetter interface {
@@ -32,10 +60,24 @@ type AggregateSignatureResult struct {
 	SignatureWeight uint64
 	// Total weight of all validators in the subnet.
 	TotalWeight uint64
+	// P-Chain height of the validator set that signed the message, which the
+	// signature must be verified against.
+	PChainHeight uint64
 	// The message with the aggregate signature.
 	Message *avalancheWarp.Message
 }
 
+// Config limits the load that aggregating signatures puts on the local node and on
+// validators, so that aggregation degrades gracefully on subnets with many validators.
+type Config struct {
+	// MaxConcurrentRequests is the maximum number of signature requests in flight at
+	// once. Zero indicates no limit.
+	MaxConcurrentRequests int
+	// RequestInterval is the minimum time between sending consecutive signature
+	// requests. Zero indicates no pacing.
+	RequestInterval time.Duration
+}
+
 // Aggregator requests signatures from validators and
 // aggregates them into a single signature.
 type Aggregator struct {
@@ -45,152 +87,350 @@ type Aggregator struct {
 	client SignatureGetter
 	// Validator state for this chain.
 	state validators.State
+	// Limits on the signature requests sent while aggregating.
+	config Config
+	// Metrics for signature aggregation.
+	stats *aggregatorStats
 }
 
 // New returns a signature aggregator for the chain with the given [state] on the
 // given [subnetID], and where [client] can be used to fetch signatures from validators.
+// Signatures are requested from all validators at once.
 func New(subnetID ids.ID, state validators.State, client SignatureGetter) *Aggregator {
+	return NewWithConfig(subnetID, state, client, Config{})
+}
+
+// NewWithConfig returns a signature aggregator like [New] that limits the signature
+// requests it sends as specified by [config].
+func NewWithConfig(subnetID ids.ID, state validators.State, client SignatureGetter, config Config) *Aggregator {
 	return &Aggregator{
 		subnetID: subnetID,
 		client:   client,
 		state:    state,
+		config:   config,
+		stats:    newAggregatorStats(),
+	}
+}
+
+type signatureFetchResult struct {
+	sig       *bls.Signature
+	index     int
+	validator *avalancheWarp.Validator
+}
+
+// fetchSignature fetches the signature of [validator], the [i]th validator of the
+// canonical validator set, over [unsignedMessage] without verifying it. Returns nil
+// if the signature could not be fetched.
+func (a *Aggregator) fetchSignature(ctx context.Context, i int, validator *avalancheWarp.Validator, unsignedMessage *avalancheWarp.UnsignedMessage) *signatureFetchResult {
+	// TODO: update from a single nodeID to the original slice and use extra nodeIDs as backup.
+	nodeID := validator.NodeIDs[0]
+	log.Debug("Fetching warp signature",
+		"nodeID", nodeID,
+		"index", i,
+		"msgID", unsignedMessage.ID(),
+	)
+
+	signature, err := a.client.GetSignature(ctx, nodeID, unsignedMessage)
+	if err != nil {
+		log.Debug("Failed to fetch warp signature",
+			"nodeID", nodeID,
+			"index", i,
+			"err", err,
+			"msgID", unsignedMessage.ID(),
+		)
+		a.stats.IncSignatureFetchFailure()
+		return nil
+	}
+
+	log.Debug("Retrieved warp signature",
+		"nodeID", nodeID,
+		"msgID", unsignedMessage.ID(),
+		"index", i,
+	)
+	return &signatureFetchResult{
+		sig:       signature,
+		index:     i,
+		validator: validator,
 	}
 }
 
+// verifySignature returns true if [result] holds a valid signature over [unsignedMessage]
+// by its validator.
+func (a *Aggregator) verifySignature(result *signatureFetchResult, unsignedMessage *avalancheWarp.UnsignedMessage) bool {
+	if !bls.Verify(result.validator.PublicKey, result.sig, unsignedMessage.Bytes()) {
+		log.Debug("Failed to verify warp signature",
+			"nodeID", result.validator.NodeIDs[0],
+			"index", result.index,
+			"msgID", unsignedMessage.ID(),
+		)
+		a.stats.IncSignatureVerifyFailure()
+		return false
+	}
+	return true
+}
+
 // Returns an aggregate signature over [unsignedMessage].
-// The returned signature's weight exceeds the threshold given by [quorumNum].
+// The returned signature's weight exceeds the threshold given by [quorumNum]
+// out of [params.WarpQuorumDenominator].
 func (a *Aggregator) AggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64) (*AggregateSignatureResult, error) {
+	return a.AggregateSignaturesWithQuorum(ctx, unsignedMessage, quorumNum, params.WarpQuorumDenominator)
+}
+
+// AggregateSignaturesWithQuorum returns an aggregate signature over [unsignedMessage]
+// whose weight exceeds the threshold given by [quorumNum]/[quorumDen]. This supports
+// aggregating for chains whose warp config uses a non-standard quorum.
+func (a *Aggregator) AggregateSignaturesWithQuorum(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64, quorumDen uint64) (*AggregateSignatureResult, error) {
 	// Note: we use the current height as a best guess of the canonical validator set when the aggregated signature will be verified
 	// by the recipient chain. If the validator set changes from [pChainHeight] to the P-Chain height that is actually specified by the
 	// ProposerVM header when this message is verified, then the aggregate signature could become outdated and require re-aggregation.
 	pChainHeight, err := a.state.GetCurrentHeight(ctx)
 	if err != nil {
+		a.stats.IncAggregationFailure()
 		return nil, err
 	}
+	return a.trackAggregation(a.aggregateSignatures(ctx, unsignedMessage, pChainHeight, true, quorumNum, quorumDen))
+}
 
-	log.Debug("Fetching signature",
-		"a.subnetID", a.subnetID,
-		"height", pChainHeight,
-	)
-	validators, totalWeight, err := avalancheWarp.GetCanonicalValidatorSet(ctx, a.state, pChainHeight, a.subnetID)
+// AggregateSignaturesAtHeight returns an aggregate signature over [unsignedMessage] by
+// the validator set at [pChainHeight], whose weight exceeds the threshold given by
+// [quorumNum]/[quorumDen]. This supports verifiers that track the validator set
+// themselves rather than reading it from the P-Chain.
+func (a *Aggregator) AggregateSignaturesAtHeight(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, pChainHeight uint64, quorumNum uint64, quorumDen uint64) (*AggregateSignatureResult, error) {
+	return a.trackAggregation(a.aggregateSignatures(ctx, unsignedMessage, pChainHeight, false, quorumNum, quorumDen))
+}
+
+func (a *Aggregator) trackAggregation(result *AggregateSignatureResult, err error) (*AggregateSignatureResult, error) {
 	if err != nil {
-		return nil, fmt.Errorf("failed to get validator set: %w", err)
-	}
-	if len(validators) == 0 {
-		return nil, fmt.Errorf("%w (SubnetID: %s, Height: %d)", errNoValidators, a.subnetID, pChainHeight)
+		a.stats.IncAggregationFailure()
+		return nil, err
 	}
+	a.stats.IncAggregationSuccess()
+	return result, nil
+}
 
-	type signatureFetchResult struct {
-		sig    *bls.Signature
-		index  int
-		weight uint64
+// aggregateSignatures aggregates signatures by the validator set at [pChainHeight]. If
+// [refresh] is true, the validator set is refreshed when the current P-Chain height
+// advances during the aggregation.
+func (a *Aggregator) aggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, pChainHeight uint64, refresh bool, quorumNum uint64, quorumDen uint64) (*AggregateSignatureResult, error) {
+	if quorumDen == 0 || quorumNum > quorumDen {
+		return nil, fmt.Errorf("%w: %d/%d", errInvalidQuorum, quorumNum, quorumDen)
 	}
+	startTime := time.Now()
 
-	// Create a child context to cancel signature fetching if we reach signature threshold.
-	signatureFetchCtx, signatureFetchCancel := context.WithCancel(ctx)
-	defer signatureFetchCancel()
-
-	// Fetch signatures from validators concurrently.
-	signatureFetchResultChan := make(chan *signatureFetchResult)
-	for i, validator := range validators {
-		var (
-			i         = i
-			validator = validator
-			// TODO: update from a single nodeID to the original slice and use extra nodeIDs as backup.
-			nodeID = validator.NodeIDs[0]
+	// Valid signatures collected so far keyed by the public key of their signer, so
+	// that they can be re-mapped onto the validator set at a later P-Chain height.
+	collected := make(map[string]*bls.Signature)
+	for refreshes := 0; ; refreshes++ {
+		log.Debug("Fetching signature",
+			"a.subnetID", a.subnetID,
+			"height", pChainHeight,
 		)
-		go func() {
-			log.Debug("Fetching warp signature",
-				"nodeID", nodeID,
-				"index", i,
-				"msgID", unsignedMessage.ID(),
-			)
+		validators, totalWeight, err := avalancheWarp.GetCanonicalValidatorSet(ctx, a.state, pChainHeight, a.subnetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator set: %w", err)
+		}
+		if len(validators) == 0 {
+			return nil, fmt.Errorf("%w (SubnetID: %s, Height: %d)", errNoValidators, a.subnetID, pChainHeight)
+		}
+
+		round := a.collectSignatures(ctx, unsignedMessage, validators, totalWeight, quorumNum, quorumDen, collected)
 
-			signature, err := a.client.GetSignature(signatureFetchCtx, nodeID, unsignedMessage)
-			if err != nil {
-				log.Debug("Failed to fetch warp signature",
-					"nodeID", nodeID,
-					"index", i,
-					"err", err,
+		// If the P-Chain height advanced while collecting signatures, the validator set
+		// and its weights may have changed, so re-fetch it and re-map the signatures
+		// collected so far before finalizing the bitset.
+		if refresh && refreshes < maxValidatorSetRefreshes && ctx.Err() == nil {
+			currentHeight, err := a.state.GetCurrentHeight(ctx)
+			if err == nil && currentHeight != pChainHeight {
+				log.Debug("P-Chain height changed during aggregation, refreshing validator set",
+					"prevHeight", pChainHeight,
+					"height", currentHeight,
 					"msgID", unsignedMessage.ID(),
 				)
-				signatureFetchResultChan <- nil
-				return
+				a.stats.IncValidatorSetRefresh()
+				pChainHeight = currentHeight
+				continue
 			}
+		}
 
-			log.Debug("Retrieved warp signature",
-				"nodeID", nodeID,
-				"msgID", unsignedMessage.ID(),
-				"index", i,
-			)
+		a.stats.UpdateSignaturesCollected(len(round.signatures))
+		a.stats.UpdateSignatureWeight(round.weight, totalWeight)
 
-			if !bls.Verify(validator.PublicKey, signature, unsignedMessage.Bytes()) {
-				log.Debug("Failed to verify warp signature",
-					"nodeID", nodeID,
-					"index", i,
-					"msgID", unsignedMessage.ID(),
-				)
-				signatureFetchResultChan <- nil
-				return
-			}
+		// If I failed to fetch sufficient signature stake, return an error
+		if !round.passedThreshold {
+			return nil, avalancheWarp.ErrInsufficientWeight
+		}
+		a.stats.UpdateTimeToQuorum(time.Since(startTime))
+		return a.finalize(unsignedMessage, round, totalWeight, pChainHeight)
+	}
+}
 
-			signatureFetchResultChan <- &signatureFetchResult{
-				sig:    signature,
-				index:  i,
-				weight: validator.Weight,
-			}
-		}()
+// collectedSignatures is the set of valid signatures collected from a canonical
+// validator set.
+type collectedSignatures struct {
+	signatures      []*bls.Signature
+	signers         set.Bits
+	weight          uint64
+	passedThreshold bool
+}
+
+// add adds [sig] from the [i]th validator of the canonical validator set to [c].
+func (c *collectedSignatures) add(i int, validator *avalancheWarp.Validator, sig *bls.Signature) {
+	c.signatures = append(c.signatures, sig)
+	c.signers.Add(i)
+	c.weight += validator.Weight
+}
+
+// collectSignatures collects signatures over [unsignedMessage] from [validators] until
+// their weight meets the threshold given by [quorumNum]/[quorumDen] or every validator
+// has been contacted. Signatures in [collected] are re-used rather than requested
+// again, and new valid signatures are added to [collected].
+func (a *Aggregator) collectSignatures(
+	ctx context.Context,
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	validators []*avalancheWarp.Validator,
+	totalWeight uint64,
+	quorumNum uint64,
+	quorumDen uint64,
+	collected map[string]*bls.Signature,
+) *collectedSignatures {
+	result := &collectedSignatures{
+		signatures: make([]*bls.Signature, 0, len(validators)),
+		signers:    set.NewBits(),
+	}
+	passedThreshold := func() bool {
+		return avalancheWarp.VerifyWeight(result.weight, totalWeight, quorumNum, quorumDen) == nil
 	}
 
+	// Re-map signatures collected from an earlier validator set onto [validators].
+	pending := make([]int, 0, len(validators))
+	for i, validator := range validators {
+		if sig, ok := collected[string(validator.PublicKeyBytes)]; ok {
+			result.add(i, validator, sig)
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if passedThreshold() {
+		result.passedThreshold = true
+		return result
+	}
+
+	// Create a child context to cancel signature fetching if we reach signature threshold.
+	signatureFetchCtx, signatureFetchCancel := context.WithCancel(ctx)
+	defer signatureFetchCancel()
+
+	// Fetch signatures from validators with a pool of workers, so that at most
+	// [MaxConcurrentRequests] requests are in flight at once.
+	a.stats.UpdateValidatorsContacted(len(pending))
+	numWorkers := len(pending)
+	if a.config.MaxConcurrentRequests > 0 && a.config.MaxConcurrentRequests < numWorkers {
+		numWorkers = a.config.MaxConcurrentRequests
+	}
 	var (
-		signatures                = make([]*bls.Signature, 0, len(validators))
-		signersBitset             = set.NewBits()
-		signaturesWeight          = uint64(0)
-		signaturesPassedThreshold = false
+		// Buffered so workers never block on results that are no longer read once
+		// the threshold is reached.
+		fetchedSignatures  = make(chan *signatureFetchResult, len(pending))
+		verifiedSignatures = make(chan *signatureFetchResult, len(pending))
+		signatureFetchJobs = make(chan int)
+		fetchWorkersDone   sync.WaitGroup
+		verifyWorkersDone  sync.WaitGroup
+		numVerifyWorkers   = runtime.NumCPU()
 	)
+	fetchWorkersDone.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer fetchWorkersDone.Done()
+			for i := range signatureFetchJobs {
+				if result := a.fetchSignature(signatureFetchCtx, i, validators[i], unsignedMessage); result != nil {
+					fetchedSignatures <- result
+				}
+			}
+		}()
+	}
+	// Verify fetched signatures in parallel, so that only valid signatures reach
+	// the accumulation loop below and verification does not delay it.
+	if numVerifyWorkers > len(pending) {
+		numVerifyWorkers = len(pending)
+	}
+	verifyWorkersDone.Add(numVerifyWorkers)
+	for w := 0; w < numVerifyWorkers; w++ {
+		go func() {
+			defer verifyWorkersDone.Done()
+			for result := range fetchedSignatures {
+				// Skip verifying signatures that arrive after the threshold is reached.
+				if signatureFetchCtx.Err() != nil {
+					continue
+				}
+				if a.verifySignature(result, unsignedMessage) {
+					verifiedSignatures <- result
+				}
+			}
+		}()
+	}
+	go func() {
+		fetchWorkersDone.Wait()
+		close(fetchedSignatures)
+		verifyWorkersDone.Wait()
+		close(verifiedSignatures)
+	}()
+	go func() {
+		defer close(signatureFetchJobs)
 
-	for i := 0; i < len(validators); i++ {
-		signatureFetchResult := <-signatureFetchResultChan
-		if signatureFetchResult == nil {
-			continue
+		var pacer <-chan time.Time
+		if a.config.RequestInterval > 0 {
+			ticker := time.NewTicker(a.config.RequestInterval)
+			defer ticker.Stop()
+			pacer = ticker.C
 		}
+		for n, i := range pending {
+			// Pace requests after the first unless aggregation has already finished,
+			// in which case the remaining requests are cancelled.
+			if pacer != nil && n > 0 {
+				select {
+				case <-pacer:
+				case <-signatureFetchCtx.Done():
+				}
+			}
+			signatureFetchJobs <- i
+		}
+	}()
 
-		signatures = append(signatures, signatureFetchResult.sig)
-		signersBitset.Add(signatureFetchResult.index)
-		signaturesWeight += signatureFetchResult.weight
+	for signatureFetchResult := range verifiedSignatures {
+		result.add(signatureFetchResult.index, signatureFetchResult.validator, signatureFetchResult.sig)
+		collected[string(signatureFetchResult.validator.PublicKeyBytes)] = signatureFetchResult.sig
+		a.stats.UpdateSignerWeight(signatureFetchResult.validator.Weight, totalWeight)
 		log.Debug("Updated weight",
-			"totalWeight", signaturesWeight,
-			"addedWeight", signatureFetchResult.weight,
+			"totalWeight", result.weight,
+			"addedWeight", signatureFetchResult.validator.Weight,
 			"msgID", unsignedMessage.ID(),
 		)
 
 		// If the signature weight meets the requested threshold, cancel signature fetching
-		if err := avalancheWarp.VerifyWeight(signaturesWeight, totalWeight, quorumNum, params.WarpQuorumDenominator); err == nil {
+		if passedThreshold() {
 			log.Debug("Verify weight passed, exiting aggregation early",
 				"quorumNum", quorumNum,
+				"quorumDen", quorumDen,
 				"totalWeight", totalWeight,
-				"signatureWeight", signaturesWeight,
+				"signatureWeight", result.weight,
 				"msgID", unsignedMessage.ID(),
 			)
-			signatureFetchCancel()
-			signaturesPassedThreshold = true
+			result.passedThreshold = true
 			break
 		}
 	}
+	return result
+}
 
-	// If I failed to fetch sufficient signature stake, return an error
-	if !signaturesPassedThreshold {
-		return nil, avalancheWarp.ErrInsufficientWeight
-	}
-
-	// Otherwise, return the aggregate signature
-	aggregateSignature, err := bls.AggregateSignatures(signatures)
+// finalize returns the result of aggregating the signatures in [collected] over
+// [unsignedMessage] by the validator set at [pChainHeight].
+func (a *Aggregator) finalize(unsignedMessage *avalancheWarp.UnsignedMessage, collected *collectedSignatures, totalWeight uint64, pChainHeight uint64) (*AggregateSignatureResult, error) {
+	aggregateSignature, err := bls.AggregateSignatures(collected.signatures)
 	if err != nil {
 		return nil, fmt.Errorf("failed to aggregate BLS signatures: %w", err)
 	}
 
 	warpSignature := &avalancheWarp.BitSetSignature{
-		Signers: signersBitset.Bytes(),
+		Signers: collected.signers.Bytes(),
 	}
 	copy(warpSignature.Signature[:], bls.SignatureToBytes(aggregateSignature))
 
@@ -201,7 +441,8 @@ func (a *Aggregator) AggregateSignatures(ctx context.Context, unsignedMessage *a
 
 	return &AggregateSignatureResult{
 		Message:         msg,
-		SignatureWeight: signaturesWeight,
+		SignatureWeight: collected.weight,
 		TotalWeight:     totalWeight,
+		PChainHeight:    pChainHeight,
 	}, nil
 }
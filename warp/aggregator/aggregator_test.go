@@ -6,12 +6,16 @@ package aggregator
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.uber.org/mock/gomock"
 
+	"github.com/ava-labs/subnet-evm/params"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
@@ -81,6 +85,7 @@ func TestAggregateSignatures(t *testing.T) {
 		aggregatorFunc  func(*gomock.Controller) *Aggregator
 		unsignedMsg     *avalancheWarp.UnsignedMessage
 		quorumNum       uint64
+		quorumDen       uint64 // Defaults to params.WarpQuorumDenominator if zero
 		expectedSigners []*avalancheWarp.Validator
 		expectedErr     error
 	}
@@ -103,7 +108,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errTest)
 				return New(subnetID, state, nil)
 			},
@@ -115,7 +120,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
 				return New(subnetID, state, nil)
 			},
@@ -128,7 +133,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -146,7 +151,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -166,7 +171,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -186,7 +191,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -207,7 +212,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -228,7 +233,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -249,7 +254,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -269,7 +274,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -289,7 +294,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -305,6 +310,60 @@ func TestAggregateSignatures(t *testing.T) {
 			expectedSigners: []*avalancheWarp.Validator{vdr3},
 			expectedErr:     nil,
 		},
+		{
+			name:        "2/3 validators reply with signature; sufficient weight for non-standard quorum",
+			contextFunc: context.Background,
+			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
+				state := validators.NewMockState(ctrl)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
+				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					vdrSet, nil,
+				)
+
+				client := NewMockSignatureGetter(ctrl)
+				client.EXPECT().GetSignature(gomock.Any(), nodeID1, gomock.Any()).Return(sig1, nil)
+				client.EXPECT().GetSignature(gomock.Any(), nodeID2, gomock.Any()).Return(sig2, nil)
+				client.EXPECT().GetSignature(gomock.Any(), nodeID3, gomock.Any()).Return(nil, errTest)
+				return New(subnetID, state, client)
+			},
+			unsignedMsg:     unsignedMsg,
+			quorumNum:       2, // Require 2/3 of weight
+			quorumDen:       3,
+			expectedSigners: []*avalancheWarp.Validator{vdr1, vdr2},
+			expectedErr:     nil,
+		},
+		{
+			name:        "2/3 validators reply with signature; insufficient weight for non-standard quorum",
+			contextFunc: context.Background,
+			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
+				state := validators.NewMockState(ctrl)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
+				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					vdrSet, nil,
+				)
+
+				client := NewMockSignatureGetter(ctrl)
+				client.EXPECT().GetSignature(gomock.Any(), nodeID1, gomock.Any()).Return(sig1, nil)
+				client.EXPECT().GetSignature(gomock.Any(), nodeID2, gomock.Any()).Return(sig2, nil)
+				client.EXPECT().GetSignature(gomock.Any(), nodeID3, gomock.Any()).Return(nil, errTest)
+				return New(subnetID, state, client)
+			},
+			unsignedMsg: unsignedMsg,
+			quorumNum:   7, // Require 7/10 of weight
+			quorumDen:   10,
+			expectedErr: avalancheWarp.ErrInsufficientWeight,
+		},
+		{
+			name:        "quorum numerator exceeds denominator",
+			contextFunc: context.Background,
+			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
+				return New(subnetID, validators.NewMockState(ctrl), nil)
+			},
+			unsignedMsg: unsignedMsg,
+			quorumNum:   4,
+			quorumDen:   3,
+			expectedErr: errInvalidQuorum,
+		},
 		{
 			name: "early termination of signature fetching on parent context cancelation",
 			contextFunc: func() context.Context {
@@ -314,7 +373,7 @@ func TestAggregateSignatures(t *testing.T) {
 			},
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -358,7 +417,7 @@ func TestAggregateSignatures(t *testing.T) {
 			contextFunc: context.Background,
 			aggregatorFunc: func(ctrl *gomock.Controller) *Aggregator {
 				state := validators.NewMockState(ctrl)
-				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil)
+				state.EXPECT().GetCurrentHeight(gomock.Any()).Return(pChainHeight, nil).AnyTimes()
 				state.EXPECT().GetValidatorSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					vdrSet, nil,
 				)
@@ -392,7 +451,15 @@ func TestAggregateSignatures(t *testing.T) {
 
 			a := tt.aggregatorFunc(ctrl)
 
-			res, err := a.AggregateSignatures(tt.contextFunc(), tt.unsignedMsg, tt.quorumNum)
+			var (
+				res *AggregateSignatureResult
+				err error
+			)
+			if tt.quorumDen == 0 {
+				res, err = a.AggregateSignatures(tt.contextFunc(), tt.unsignedMsg, tt.quorumNum)
+			} else {
+				res, err = a.AggregateSignaturesWithQuorum(tt.contextFunc(), tt.unsignedMsg, tt.quorumNum, tt.quorumDen)
+			}
 			require.ErrorIs(err, tt.expectedErr)
 			if err != nil {
 				return
@@ -423,3 +490,133 @@ func TestAggregateSignatures(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateQuorum(t *testing.T) {
+	tests := map[string]struct {
+		quorumNum   uint64
+		quorumDen   uint64
+		expectedErr bool
+	}{
+		"default quorum":              {quorumNum: params.WarpDefaultQuorumNumerator, quorumDen: params.WarpQuorumDenominator},
+		"minimum quorum":              {quorumNum: params.WarpQuorumNumeratorMinimum, quorumDen: params.WarpQuorumDenominator},
+		"non-standard denominator":    {quorumNum: 2, quorumDen: 3},
+		"all stake":                   {quorumNum: 7, quorumDen: 7},
+		"below minimum quorum":        {quorumNum: 1, quorumDen: 4, expectedErr: true},
+		"numerator above denominator": {quorumNum: 4, quorumDen: 3, expectedErr: true},
+		"zero denominator":            {quorumNum: 0, quorumDen: 0, expectedErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateQuorum(tt.quorumNum, tt.quorumDen)
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAggregateSignaturesRequestLimits(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	subnetID := ids.GenerateTestID()
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(1338, ids.GenerateTestID(), []byte("hello world"))
+	require.NoError(err)
+
+	const (
+		numValidators   = 8
+		maxConcurrent   = 2
+		requestInterval = 5 * time.Millisecond
+	)
+	var (
+		vdrSet   = make(map[ids.NodeID]*validators.GetValidatorOutput, numValidators)
+		client   = NewMockSignatureGetter(ctrl)
+		inFlight atomic.Int32
+		maxSeen  atomic.Int32
+	)
+	for i := 0; i < numValidators; i++ {
+		sk, vdr := newValidator(t, 100)
+		nodeID := vdr.NodeIDs[0]
+		vdrSet[nodeID] = &validators.GetValidatorOutput{NodeID: nodeID, PublicKey: vdr.PublicKey, Weight: vdr.Weight}
+		sig := bls.Sign(sk, unsignedMsg.Bytes())
+		client.EXPECT().GetSignature(gomock.Any(), nodeID, gomock.Any()).DoAndReturn(
+			func(context.Context, ids.NodeID, *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					seen := maxSeen.Load()
+					if n <= seen || maxSeen.CompareAndSwap(seen, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return sig, nil
+			},
+		)
+	}
+	state := validators.NewMockState(ctrl)
+	state.EXPECT().GetCurrentHeight(gomock.Any()).Return(uint64(1337), nil).AnyTimes()
+	state.EXPECT().GetValidatorSet(gomock.Any(), uint64(1337), subnetID).Return(vdrSet, nil)
+
+	a := NewWithConfig(subnetID, state, client, Config{
+		MaxConcurrentRequests: maxConcurrent,
+		RequestInterval:       requestInterval,
+	})
+	start := time.Now()
+	res, err := a.AggregateSignatures(context.Background(), unsignedMsg, params.WarpQuorumDenominator)
+	require.NoError(err)
+	require.EqualValues(numValidators*100, res.SignatureWeight)
+	require.LessOrEqual(maxSeen.Load(), int32(maxConcurrent))
+	require.GreaterOrEqual(time.Since(start), (numValidators-1)*requestInterval)
+}
+
+func TestAggregateSignaturesValidatorSetChange(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	subnetID := ids.GenerateTestID()
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(1338, ids.GenerateTestID(), []byte("hello world"))
+	require.NoError(err)
+
+	sk1, vdr1 := newValidator(t, 100)
+	sk2, vdr2 := newValidator(t, 100)
+	_, vdr3 := newValidator(t, 100)
+	sk4, vdr4 := newValidator(t, 300)
+	getValidatorOutput := func(vdr *avalancheWarp.Validator) *validators.GetValidatorOutput {
+		return &validators.GetValidatorOutput{NodeID: vdr.NodeIDs[0], PublicKey: vdr.PublicKey, Weight: vdr.Weight}
+	}
+
+	// The P-Chain height advances after signatures are collected from the validator
+	// set at height 1, in which [vdr3] is replaced by [vdr4].
+	state := validators.NewMockState(ctrl)
+	state.EXPECT().GetCurrentHeight(gomock.Any()).Return(uint64(1), nil)
+	state.EXPECT().GetCurrentHeight(gomock.Any()).Return(uint64(2), nil).AnyTimes()
+	state.EXPECT().GetValidatorSet(gomock.Any(), uint64(1), subnetID).Return(map[ids.NodeID]*validators.GetValidatorOutput{
+		vdr1.NodeIDs[0]: getValidatorOutput(vdr1),
+		vdr2.NodeIDs[0]: getValidatorOutput(vdr2),
+		vdr3.NodeIDs[0]: getValidatorOutput(vdr3),
+	}, nil)
+	state.EXPECT().GetValidatorSet(gomock.Any(), uint64(2), subnetID).Return(map[ids.NodeID]*validators.GetValidatorOutput{
+		vdr1.NodeIDs[0]: getValidatorOutput(vdr1),
+		vdr2.NodeIDs[0]: getValidatorOutput(vdr2),
+		vdr4.NodeIDs[0]: getValidatorOutput(vdr4),
+	}, nil)
+
+	// Signatures collected at height 1 are re-used at height 2 rather than requested again.
+	client := NewMockSignatureGetter(ctrl)
+	client.EXPECT().GetSignature(gomock.Any(), vdr1.NodeIDs[0], gomock.Any()).Return(bls.Sign(sk1, unsignedMsg.Bytes()), nil)
+	client.EXPECT().GetSignature(gomock.Any(), vdr2.NodeIDs[0], gomock.Any()).Return(bls.Sign(sk2, unsignedMsg.Bytes()), nil)
+	client.EXPECT().GetSignature(gomock.Any(), vdr3.NodeIDs[0], gomock.Any()).Return(nil, errors.New("unavailable")).AnyTimes()
+	client.EXPECT().GetSignature(gomock.Any(), vdr4.NodeIDs[0], gomock.Any()).Return(bls.Sign(sk4, unsignedMsg.Bytes()), nil)
+
+	a := New(subnetID, state, client)
+	res, err := a.AggregateSignatures(context.Background(), unsignedMsg, 60)
+	require.NoError(err)
+	require.EqualValues(500, res.TotalWeight)
+	require.EqualValues(500, res.SignatureWeight)
+	numSigners, err := res.Message.Signature.NumSigners()
+	require.NoError(err)
+	require.Equal(3, numSigners)
+}
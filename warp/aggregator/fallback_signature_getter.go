@@ -0,0 +1,99 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+)
+
+var _ SignatureGetter = (*FallbackSigner)(nil)
+
+// FallbackSigner fetches warp signatures with a primary SignatureGetter, such as the
+// p2p NetworkSigner, and falls back to a second SignatureGetter, such as one querying
+// the public warp APIs of validators, for validators the primary cannot reach.
+//
+// The fallback is queried once the primary fails or has not returned a signature
+// within the fallback delay, since the p2p transport retries until its context is
+// cancelled. The first signature fetched by either is returned.
+type FallbackSigner struct {
+	primary       SignatureGetter
+	fallback      SignatureGetter
+	fallbackDelay time.Duration
+	stats         *fallbackSignerStats
+}
+
+// NewFallbackSigner returns a FallbackSigner that queries [fallback] for a signature
+// if [primary] fails or has not returned it after [fallbackDelay].
+func NewFallbackSigner(primary SignatureGetter, fallback SignatureGetter, fallbackDelay time.Duration) *FallbackSigner {
+	return &FallbackSigner{
+		primary:       primary,
+		fallback:      fallback,
+		fallbackDelay: fallbackDelay,
+		stats:         newFallbackSignerStats(),
+	}
+}
+
+// GetSignature fetches the BLS signature of [unsignedWarpMessage] from [nodeID] with the
+// primary SignatureGetter and, if it fails or is slow, the fallback SignatureGetter.
+// Returns the error of the last SignatureGetter to fail if neither fetches it.
+func (s *FallbackSigner) GetSignature(ctx context.Context, nodeID ids.NodeID, unsignedWarpMessage *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+	// Cancel the outstanding request once a signature is fetched.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type fetchResult struct {
+		sig      *bls.Signature
+		err      error
+		fallback bool
+	}
+	var (
+		results         = make(chan fetchResult, 2)
+		pending         = 0
+		fallbackStarted = false
+		lastErr         error
+	)
+	fetch := func(getter SignatureGetter, fallback bool) {
+		pending++
+		go func() {
+			sig, err := getter.GetSignature(ctx, nodeID, unsignedWarpMessage)
+			results <- fetchResult{sig: sig, err: err, fallback: fallback}
+		}()
+	}
+	startFallback := func() {
+		if fallbackStarted {
+			return
+		}
+		fallbackStarted = true
+		s.stats.IncFallbackRequest()
+		fetch(s.fallback, true)
+	}
+
+	fetch(s.primary, false)
+	timer := time.NewTimer(s.fallbackDelay)
+	defer timer.Stop()
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			startFallback()
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				if result.fallback {
+					s.stats.IncFallbackSuccess()
+				}
+				return result.sig, nil
+			}
+			lastErr = result.err
+			if !result.fallback {
+				startFallback()
+			}
+		}
+	}
+	return nil, lastErr
+}
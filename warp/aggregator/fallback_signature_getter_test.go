@@ -0,0 +1,94 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+)
+
+func TestFallbackSigner(t *testing.T) {
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(1338, ids.GenerateTestID(), []byte("hello world"))
+	require.NoError(t, err)
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	sig := bls.Sign(sk, unsignedMsg.Bytes())
+	nodeID := ids.GenerateTestNodeID()
+
+	errPrimary := errors.New("primary failed")
+	errFallback := errors.New("fallback failed")
+	// blockUntilDone simulates the p2p transport retrying until its context is cancelled.
+	blockUntilDone := func(ctx context.Context, _ ids.NodeID, _ *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := map[string]struct {
+		setup       func(primary, fallback *MockSignatureGetter)
+		expectedErr error
+	}{
+		"primary succeeds": {
+			setup: func(primary, _ *MockSignatureGetter) {
+				primary.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(sig, nil)
+			},
+		},
+		"primary fails": {
+			setup: func(primary, fallback *MockSignatureGetter) {
+				primary.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(nil, errPrimary)
+				fallback.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(sig, nil)
+			},
+		},
+		"primary times out": {
+			setup: func(primary, fallback *MockSignatureGetter) {
+				primary.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).DoAndReturn(blockUntilDone)
+				fallback.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(sig, nil)
+			},
+		},
+		"primary succeeds after fallback fails": {
+			setup: func(primary, fallback *MockSignatureGetter) {
+				primary.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).DoAndReturn(
+					func(context.Context, ids.NodeID, *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+						time.Sleep(100 * time.Millisecond)
+						return sig, nil
+					},
+				)
+				fallback.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(nil, errFallback)
+			},
+		},
+		"both fail": {
+			setup: func(primary, fallback *MockSignatureGetter) {
+				primary.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(nil, errPrimary)
+				fallback.EXPECT().GetSignature(gomock.Any(), nodeID, unsignedMsg).Return(nil, errFallback)
+			},
+			expectedErr: errFallback,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			primary := NewMockSignatureGetter(ctrl)
+			fallback := NewMockSignatureGetter(ctrl)
+			tt.setup(primary, fallback)
+
+			signer := NewFallbackSigner(primary, fallback, 10*time.Millisecond)
+			res, err := signer.GetSignature(context.Background(), nodeID, unsignedMsg)
+			require.ErrorIs(err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(sig, res)
+			}
+		})
+	}
+}
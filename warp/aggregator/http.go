@@ -0,0 +1,116 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// AggregateSignaturesPath is the path of the HTTP endpoint that aggregates signatures.
+const AggregateSignaturesPath = "/aggregate-signatures"
+
+// AggregateSignaturesRequest is the body of a request to the aggregate signatures
+// HTTP endpoint.
+type AggregateSignaturesRequest struct {
+	// UnsignedMessage is the unsigned warp message to aggregate signatures over.
+	UnsignedMessage hexutil.Bytes `json:"unsignedMessage"`
+	// QuorumNum is the numerator of the fraction of stake that must sign the message,
+	// or the handler's default if zero.
+	QuorumNum uint64 `json:"quorumNum"`
+	// QuorumDen is the denominator of the fraction of stake that must sign the message,
+	// or the handler's default if zero. Must be specified with QuorumNum.
+	QuorumDen uint64 `json:"quorumDen"`
+}
+
+// AggregateSignaturesResponse is the body of a successful response of the aggregate
+// signatures HTTP endpoint.
+type AggregateSignaturesResponse struct {
+	SignedMessage   hexutil.Bytes  `json:"signedMessage"`
+	SignatureWeight hexutil.Uint64 `json:"signatureWeight"`
+	TotalWeight     hexutil.Uint64 `json:"totalWeight"`
+}
+
+type httpHandler struct {
+	aggregator       *Aggregator
+	defaultQuorumNum uint64
+	defaultQuorumDen uint64
+	timeout          time.Duration
+}
+
+// NewHTTPHandler returns an HTTP handler that serves signature aggregation by
+// [aggregator] at [AggregateSignaturesPath]. Requests that do not specify a quorum
+// use [defaultQuorumNum]/[defaultQuorumDen], and each aggregation is abandoned after
+// [timeout].
+func NewHTTPHandler(aggregator *Aggregator, defaultQuorumNum uint64, defaultQuorumDen uint64, timeout time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(AggregateSignaturesPath, &httpHandler{
+		aggregator:       aggregator,
+		defaultQuorumNum: defaultQuorumNum,
+		defaultQuorumDen: defaultQuorumDen,
+		timeout:          timeout,
+	})
+	return mux
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req AggregateSignaturesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	unsignedMessage, err := avalancheWarp.ParseUnsignedMessage(req.UnsignedMessage)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid unsigned message: %s", err), http.StatusBadRequest)
+		return
+	}
+	quorumNum, quorumDen := req.QuorumNum, req.QuorumDen
+	switch {
+	case quorumNum == 0 && quorumDen != 0:
+		http.Error(w, "quorumDen must be specified with quorumNum", http.StatusBadRequest)
+		return
+	case quorumNum == 0:
+		quorumNum, quorumDen = h.defaultQuorumNum, h.defaultQuorumDen
+	case quorumDen == 0:
+		quorumDen = params.WarpQuorumDenominator
+	}
+	if err := ValidateQuorum(quorumNum, quorumDen); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if h.timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	result, err := h.aggregator.AggregateSignaturesWithQuorum(ctx, unsignedMessage, quorumNum, quorumDen)
+	if err != nil {
+		log.Debug("Failed to aggregate signatures", "msgID", unsignedMessage.ID(), "err", err)
+		http.Error(w, fmt.Sprintf("failed to aggregate signatures: %s", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&AggregateSignaturesResponse{
+		SignedMessage:   result.Message.Bytes(),
+		SignatureWeight: hexutil.Uint64(result.SignatureWeight),
+		TotalWeight:     hexutil.Uint64(result.TotalWeight),
+	}); err != nil {
+		log.Debug("Failed to write aggregate signatures response", "err", err)
+	}
+}
@@ -0,0 +1,70 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	subnetID := ids.GenerateTestID()
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(1338, ids.GenerateTestID(), []byte("hello world"))
+	require.NoError(err)
+
+	sk, vdr := newValidator(t, 100)
+	nodeID := vdr.NodeIDs[0]
+	state := validators.NewMockState(ctrl)
+	state.EXPECT().GetCurrentHeight(gomock.Any()).Return(uint64(1337), nil).AnyTimes()
+	state.EXPECT().GetValidatorSet(gomock.Any(), uint64(1337), subnetID).Return(map[ids.NodeID]*validators.GetValidatorOutput{
+		nodeID: {NodeID: nodeID, PublicKey: vdr.PublicKey, Weight: vdr.Weight},
+	}, nil)
+	client := NewMockSignatureGetter(ctrl)
+	client.EXPECT().GetSignature(gomock.Any(), nodeID, gomock.Any()).Return(bls.Sign(sk, unsignedMsg.Bytes()), nil)
+
+	handler := NewHTTPHandler(New(subnetID, state, client), 67, 100, time.Minute)
+	post := func(req *AggregateSignaturesRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(req)
+		require.NoError(err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, AggregateSignaturesPath, bytes.NewReader(body)))
+		return recorder
+	}
+
+	recorder := post(&AggregateSignaturesRequest{UnsignedMessage: unsignedMsg.Bytes()})
+	require.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var res AggregateSignaturesResponse
+	require.NoError(json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.EqualValues(100, res.SignatureWeight)
+	require.EqualValues(100, res.TotalWeight)
+	msg, err := avalancheWarp.ParseMessage(res.SignedMessage)
+	require.NoError(err)
+	require.Equal(unsignedMsg.ID(), msg.UnsignedMessage.ID())
+
+	// Invalid requests are rejected without aggregating signatures.
+	require.Equal(http.StatusBadRequest, post(&AggregateSignaturesRequest{UnsignedMessage: []byte{1, 2, 3}}).Code)
+	require.Equal(http.StatusBadRequest, post(&AggregateSignaturesRequest{UnsignedMessage: unsignedMsg.Bytes(), QuorumNum: 101}).Code)
+	require.Equal(http.StatusBadRequest, post(&AggregateSignaturesRequest{UnsignedMessage: unsignedMsg.Bytes(), QuorumNum: 1, QuorumDen: 4}).Code)
+	require.Equal(http.StatusBadRequest, post(&AggregateSignaturesRequest{UnsignedMessage: unsignedMsg.Bytes(), QuorumDen: 4}).Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, AggregateSignaturesPath, nil))
+	require.Equal(http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -29,6 +29,15 @@ type NetworkClient interface {
 // NetworkSigner fetches warp signatures on behalf of the aggregator using VM App-Specific Messaging
 type NetworkSigner struct {
 	Client NetworkClient
+	stats  *signatureGetterStats
+}
+
+// NewNetworkSigner returns a NetworkSigner that fetches signatures using [client].
+func NewNetworkSigner(client NetworkClient) *NetworkSigner {
+	return &NetworkSigner{
+		Client: client,
+		stats:  newSignatureGetterStats(),
+	}
 }
 
 // GetSignature attempts to fetch a BLS Signature of [unsignedWarpMessage] from [nodeID] until it succeeds or receives an invalid response
@@ -48,10 +57,13 @@ func (s *NetworkSigner) GetSignature(ctx context.Context, nodeID ids.NodeID, uns
 	timer := time.NewTimer(delay)
 	defer timer.Stop()
 	for {
+		requestStart := time.Now()
 		signatureRes, err := s.Client.SendAppRequest(nodeID, signatureReqBytes)
+		s.stats.UpdateSignatureRequestDuration(time.Since(requestStart))
 		// If the client fails to retrieve a response perform an exponential backoff.
 		// Note: it is up to the caller to ensure that [ctx] is eventually cancelled
 		if err != nil {
+			s.stats.IncSignatureRequestRetry()
 			// Wait until the retry delay has elapsed before retrying.
 			if !timer.Stop() {
 				<-timer.C
@@ -60,6 +72,7 @@ func (s *NetworkSigner) GetSignature(ctx context.Context, nodeID ids.NodeID, uns
 
 			select {
 			case <-ctx.Done():
+				s.stats.IncSignatureRequestFailure()
 				return nil, ctx.Err()
 			case <-timer.C:
 			}
@@ -74,13 +87,16 @@ func (s *NetworkSigner) GetSignature(ctx context.Context, nodeID ids.NodeID, uns
 
 		var response message.SignatureResponse
 		if _, err := message.Codec.Unmarshal(signatureRes, &response); err != nil {
+			s.stats.IncSignatureRequestFailure()
 			return nil, fmt.Errorf("failed to unmarshal signature res: %w", err)
 		}
 
 		blsSignature, err := bls.SignatureFromBytes(response.Signature[:])
 		if err != nil {
+			s.stats.IncSignatureRequestFailure()
 			return nil, fmt.Errorf("failed to parse signature from res: %w", err)
 		}
+		s.stats.IncSignatureRequestSuccess()
 		return blsSignature, nil
 	}
 }
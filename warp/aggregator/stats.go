@@ -0,0 +1,129 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"time"
+
+	"github.com/ava-labs/subnet-evm/metrics"
+)
+
+// aggregatorStats tracks the latency and quorum characteristics of signature aggregation.
+type aggregatorStats struct {
+	// aggregation results
+	aggregationSuccess metrics.Counter
+	aggregationFailure metrics.Counter
+
+	// time from the start of aggregation until the requested quorum was reached
+	timeToQuorum metrics.Timer
+
+	// number of validators a signature was requested from per aggregation
+	validatorsContacted metrics.Histogram
+	// number of valid signatures collected per aggregation
+	signaturesCollected metrics.Histogram
+	// percentage of the total validator weight that signed per aggregation
+	signatureWeightPercentage metrics.Histogram
+	// percentage of the total validator weight contributed by each individual signer
+	signerWeightPercentage metrics.Histogram
+
+	// individual signature fetch results
+	signatureFetchFailure  metrics.Counter
+	signatureVerifyFailure metrics.Counter
+
+	// number of times the validator set was re-fetched because the P-Chain height
+	// advanced during aggregation
+	validatorSetRefresh metrics.Counter
+}
+
+func newAggregatorStats() *aggregatorStats {
+	return &aggregatorStats{
+		aggregationSuccess:        metrics.GetOrRegisterCounter("warp_aggregation_success", nil),
+		aggregationFailure:        metrics.GetOrRegisterCounter("warp_aggregation_failure", nil),
+		timeToQuorum:              metrics.GetOrRegisterTimer("warp_aggregation_time_to_quorum", nil),
+		validatorsContacted:       metrics.GetOrRegisterHistogram("warp_aggregation_validators_contacted", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		signaturesCollected:       metrics.GetOrRegisterHistogram("warp_aggregation_signatures_collected", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		signatureWeightPercentage: metrics.GetOrRegisterHistogram("warp_aggregation_signature_weight_percentage", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		signerWeightPercentage:    metrics.GetOrRegisterHistogram("warp_aggregation_signer_weight_percentage", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		signatureFetchFailure:     metrics.GetOrRegisterCounter("warp_aggregation_signature_fetch_failure", nil),
+		signatureVerifyFailure:    metrics.GetOrRegisterCounter("warp_aggregation_signature_verify_failure", nil),
+		validatorSetRefresh:       metrics.GetOrRegisterCounter("warp_aggregation_validator_set_refresh", nil),
+	}
+}
+
+func (s *aggregatorStats) IncAggregationSuccess()     { s.aggregationSuccess.Inc(1) }
+func (s *aggregatorStats) IncAggregationFailure()     { s.aggregationFailure.Inc(1) }
+func (s *aggregatorStats) IncSignatureFetchFailure()  { s.signatureFetchFailure.Inc(1) }
+func (s *aggregatorStats) IncSignatureVerifyFailure() { s.signatureVerifyFailure.Inc(1) }
+func (s *aggregatorStats) IncValidatorSetRefresh()    { s.validatorSetRefresh.Inc(1) }
+
+func (s *aggregatorStats) UpdateTimeToQuorum(duration time.Duration) {
+	s.timeToQuorum.Update(duration)
+}
+
+func (s *aggregatorStats) UpdateValidatorsContacted(count int) {
+	s.validatorsContacted.Update(int64(count))
+}
+
+func (s *aggregatorStats) UpdateSignaturesCollected(count int) {
+	s.signaturesCollected.Update(int64(count))
+}
+
+func (s *aggregatorStats) UpdateSignatureWeight(signatureWeight uint64, totalWeight uint64) {
+	s.signatureWeightPercentage.Update(weightPercentage(signatureWeight, totalWeight))
+}
+
+func (s *aggregatorStats) UpdateSignerWeight(signerWeight uint64, totalWeight uint64) {
+	s.signerWeightPercentage.Update(weightPercentage(signerWeight, totalWeight))
+}
+
+// weightPercentage returns [weight] as a percentage of [totalWeight].
+func weightPercentage(weight uint64, totalWeight uint64) int64 {
+	if totalWeight == 0 {
+		return 0
+	}
+	return int64(float64(weight) * 100 / float64(totalWeight))
+}
+
+// signatureGetterStats tracks the latency and reliability of fetching signatures from validators
+// over the network.
+type signatureGetterStats struct {
+	signatureRequestDuration metrics.Timer
+	signatureRequestRetry    metrics.Counter
+	signatureRequestFailure  metrics.Counter
+	signatureRequestSuccess  metrics.Counter
+}
+
+func newSignatureGetterStats() *signatureGetterStats {
+	return &signatureGetterStats{
+		signatureRequestDuration: metrics.GetOrRegisterTimer("warp_signature_getter_request_duration", nil),
+		signatureRequestRetry:    metrics.GetOrRegisterCounter("warp_signature_getter_request_retry", nil),
+		signatureRequestFailure:  metrics.GetOrRegisterCounter("warp_signature_getter_request_failure", nil),
+		signatureRequestSuccess:  metrics.GetOrRegisterCounter("warp_signature_getter_request_success", nil),
+	}
+}
+
+func (s *signatureGetterStats) IncSignatureRequestRetry()   { s.signatureRequestRetry.Inc(1) }
+func (s *signatureGetterStats) IncSignatureRequestFailure() { s.signatureRequestFailure.Inc(1) }
+func (s *signatureGetterStats) IncSignatureRequestSuccess() { s.signatureRequestSuccess.Inc(1) }
+
+func (s *signatureGetterStats) UpdateSignatureRequestDuration(duration time.Duration) {
+	s.signatureRequestDuration.Update(duration)
+}
+
+// fallbackSignerStats tracks how often signatures are fetched with the fallback
+// SignatureGetter of a FallbackSigner.
+type fallbackSignerStats struct {
+	fallbackRequest metrics.Counter
+	fallbackSuccess metrics.Counter
+}
+
+func newFallbackSignerStats() *fallbackSignerStats {
+	return &fallbackSignerStats{
+		fallbackRequest: metrics.GetOrRegisterCounter("warp_signature_fallback_request", nil),
+		fallbackSuccess: metrics.GetOrRegisterCounter("warp_signature_fallback_success", nil),
+	}
+}
+
+func (s *fallbackSignerStats) IncFallbackRequest() { s.fallbackRequest.Inc(1) }
+func (s *fallbackSignerStats) IncFallbackSuccess() { s.fallbackSuccess.Inc(1) }
@@ -4,6 +4,8 @@
 package warp
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -12,35 +14,69 @@ import (
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/sync/singleflight"
 )
 
 var _ Backend = &backend{}
 
 const batchSize = ethdb.IdealBatchSize
 
+// provenancePrefix prefixes the keys of message provenance records. Messages are keyed
+// by their 32 byte ID, so prefixed keys never collide with them.
+var provenancePrefix = []byte("provenance")
+
+// MessageProvenance records where in the chain a warp message was sent.
+type MessageProvenance struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	// TxHash and LogIndex identify the log that sent the message, and are zero
+	// for messages generated by the VM, such as block hash messages.
+	TxHash   common.Hash `json:"txHash"`
+	LogIndex uint        `json:"logIndex"`
+}
+
 // Backend tracks signature-eligible warp messages and provides an interface to fetch them.
 // The backend is also used to query for warp message signatures by the signature request handler.
 type Backend interface {
 	// AddMessage signs [unsignedMessage] and adds it to the warp backend database
 	AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) error
 
+	// AddMessageWithProvenance adds [unsignedMessage] like AddMessage and records
+	// where in the chain it was sent.
+	AddMessageWithProvenance(unsignedMessage *avalancheWarp.UnsignedMessage, provenance *MessageProvenance) error
+
 	// GetSignature returns the signature of the requested message hash.
 	GetSignature(messageHash ids.ID) ([bls.SignatureLen]byte, error)
 
 	// GetMessage retrieves the [unsignedMessage] from the warp backend database if available
 	GetMessage(messageHash ids.ID) (*avalancheWarp.UnsignedMessage, error)
 
+	// ForEachMessage calls [fn] with each message in the warp backend database and its
+	// provenance, or nil if it was not recorded. Stops at the first error returned by [fn].
+	ForEachMessage(fn func(unsignedMessage *avalancheWarp.UnsignedMessage, provenance *MessageProvenance) error) error
+
 	// Clear clears the entire db
 	Clear() error
 }
 
+// signatureKey identifies a cached signature by the message and the public key of
+// the signer, so that signatures are not served after the signing key rotates.
+type signatureKey struct {
+	messageID ids.ID
+	publicKey [bls.PublicKeyLen]byte
+}
+
 // backend implements Backend, keeps track of warp messages, and generates message signatures.
 type backend struct {
 	db             database.Database
 	warpSigner     avalancheWarp.Signer
-	signatureCache *cache.LRU[ids.ID, [bls.SignatureLen]byte]
+	signatureCache *cache.LRU[signatureKey, [bls.SignatureLen]byte]
 	messageCache   *cache.LRU[ids.ID, *avalancheWarp.UnsignedMessage]
+	// signGroup deduplicates concurrent requests to sign the same message, so that
+	// the message is only loaded and signed once.
+	signGroup singleflight.Group
 }
 
 // NewBackend creates a new Backend, and initializes the signature cache and message tracking database.
@@ -48,7 +84,7 @@ func NewBackend(warpSigner avalancheWarp.Signer, db database.Database, cacheSize
 	return &backend{
 		db:             db,
 		warpSigner:     warpSigner,
-		signatureCache: &cache.LRU[ids.ID, [bls.SignatureLen]byte]{Size: cacheSize},
+		signatureCache: &cache.LRU[signatureKey, [bls.SignatureLen]byte]{Size: cacheSize},
 		messageCache:   &cache.LRU[ids.ID, *avalancheWarp.UnsignedMessage]{Size: cacheSize},
 	}
 }
@@ -60,7 +96,20 @@ func (b *backend) Clear() error {
 }
 
 func (b *backend) AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) error {
+	return b.AddMessageWithProvenance(unsignedMessage, nil)
+}
+
+func (b *backend) AddMessageWithProvenance(unsignedMessage *avalancheWarp.UnsignedMessage, provenance *MessageProvenance) error {
 	messageID := unsignedMessage.ID()
+	if provenance != nil {
+		provenanceBytes, err := json.Marshal(provenance)
+		if err != nil {
+			return fmt.Errorf("failed to marshal warp message provenance: %w", err)
+		}
+		if err := b.db.Put(provenanceKey(messageID), provenanceBytes); err != nil {
+			return fmt.Errorf("failed to put warp message provenance in db: %w", err)
+		}
+	}
 
 	// In the case when a node restarts, and possibly changes its bls key, the cache gets emptied but the database does not.
 	// So to avoid having incorrect signatures saved in the database after a bls key change, we save the full message in the database.
@@ -69,37 +118,71 @@ func (b *backend) AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) err
 		return fmt.Errorf("failed to put warp signature in db: %w", err)
 	}
 
+	signer, key := b.activeSigner(messageID)
 	var signature [bls.SignatureLen]byte
-	sig, err := b.warpSigner.Sign(unsignedMessage)
+	sig, err := signer.Sign(unsignedMessage)
 	if err != nil {
 		return fmt.Errorf("failed to sign warp message: %w", err)
 	}
 
 	copy(signature[:], sig)
-	b.signatureCache.Put(messageID, signature)
+	b.signatureCache.Put(key, signature)
 	log.Debug("Adding warp message to backend", "messageID", messageID)
 	return nil
 }
 
 func (b *backend) GetSignature(messageID ids.ID) ([bls.SignatureLen]byte, error) {
 	log.Debug("Getting warp message from backend", "messageID", messageID)
-	if sig, ok := b.signatureCache.Get(messageID); ok {
+	signer, key := b.activeSigner(messageID)
+	if sig, ok := b.signatureCache.Get(key); ok {
 		return sig, nil
 	}
 
+	groupKey := messageID.String() + string(key.publicKey[:])
+	signature, err, _ := b.signGroup.Do(groupKey, func() (interface{}, error) {
+		// The signature may have been cached by a request that completed since the
+		// cache was checked above.
+		if sig, ok := b.signatureCache.Get(key); ok {
+			return sig, nil
+		}
+		return b.sign(signer, key)
+	})
+	if err != nil {
+		return [bls.SignatureLen]byte{}, err
+	}
+	return signature.([bls.SignatureLen]byte), nil
+}
+
+// activeSigner returns the signer to sign [messageID] with, and the key to cache the
+// signature under.
+func (b *backend) activeSigner(messageID ids.ID) (avalancheWarp.Signer, signatureKey) {
+	key := signatureKey{messageID: messageID}
+	rotatingSigner, ok := b.warpSigner.(*RotatingSigner)
+	if !ok {
+		return b.warpSigner, key
+	}
+	signer, publicKey := rotatingSigner.Active()
+	key.publicKey = publicKey
+	return signer, key
+}
+
+// sign loads the message identified by [key] from the database, signs it with
+// [signer], and caches the signature.
+func (b *backend) sign(signer avalancheWarp.Signer, key signatureKey) ([bls.SignatureLen]byte, error) {
+	messageID := key.messageID
 	unsignedMessage, err := b.GetMessage(messageID)
 	if err != nil {
 		return [bls.SignatureLen]byte{}, fmt.Errorf("failed to get warp message %s from db: %w", messageID.String(), err)
 	}
 
 	var signature [bls.SignatureLen]byte
-	sig, err := b.warpSigner.Sign(unsignedMessage)
+	sig, err := signer.Sign(unsignedMessage)
 	if err != nil {
 		return [bls.SignatureLen]byte{}, fmt.Errorf("failed to sign warp message: %w", err)
 	}
 
 	copy(signature[:], sig)
-	b.signatureCache.Put(messageID, signature)
+	b.signatureCache.Put(key, signature)
 	return signature, nil
 }
 
@@ -121,3 +204,48 @@ func (b *backend) GetMessage(messageID ids.ID) (*avalancheWarp.UnsignedMessage,
 
 	return unsignedMessage, nil
 }
+
+func (b *backend) ForEachMessage(fn func(unsignedMessage *avalancheWarp.UnsignedMessage, provenance *MessageProvenance) error) error {
+	it := b.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		// Skip provenance records, which are read along with their message.
+		if len(it.Key()) != len(ids.Empty) {
+			continue
+		}
+		unsignedMessage, err := avalancheWarp.ParseUnsignedMessage(it.Value())
+		if err != nil {
+			return fmt.Errorf("failed to parse unsigned message %x: %w", it.Key(), err)
+		}
+		provenance, err := b.getProvenance(unsignedMessage.ID())
+		if err != nil {
+			return err
+		}
+		if err := fn(unsignedMessage, provenance); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// getProvenance returns the provenance of the message with [messageID], or nil if
+// it was not recorded.
+func (b *backend) getProvenance(messageID ids.ID) (*MessageProvenance, error) {
+	provenanceBytes, err := b.db.Get(provenanceKey(messageID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warp message provenance %s from db: %w", messageID, err)
+	}
+	provenance := new(MessageProvenance)
+	if err := json.Unmarshal(provenanceBytes, provenance); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal warp message provenance %s: %w", messageID, err)
+	}
+	return provenance, nil
+}
+
+func provenanceKey(messageID ids.ID) []byte {
+	return append(append([]byte{}, provenancePrefix...), messageID[:]...)
+}
@@ -4,7 +4,10 @@
 package warp
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -127,3 +130,55 @@ func TestZeroSizedCache(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedSig, signature[:])
 }
+
+// countingSigner counts the messages signed by the wrapped signer.
+type countingSigner struct {
+	avalancheWarp.Signer
+	signs atomic.Int32
+}
+
+func (s *countingSigner) Sign(msg *avalancheWarp.UnsignedMessage) ([]byte, error) {
+	s.signs.Add(1)
+	// Widen the window for concurrent requests to overlap.
+	time.Sleep(10 * time.Millisecond)
+	return s.Signer.Sign(msg)
+}
+
+func TestConcurrentGetSignature(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	warpSigner := &countingSigner{Signer: avalancheWarp.NewSigner(sk, networkID, sourceChainID)}
+	backendIntf := NewBackend(warpSigner, memdb.New(), 500)
+	backend, ok := backendIntf.(*backend)
+	require.True(t, ok)
+
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, payload)
+	require.NoError(t, err)
+	require.NoError(t, backend.AddMessage(unsignedMsg))
+	expectedSig, err := backend.GetSignature(unsignedMsg.ID())
+	require.NoError(t, err)
+
+	// Drop the cached signature, so that the message must be signed again.
+	backend.signatureCache.Flush()
+	warpSigner.signs.Store(0)
+
+	var (
+		wg         sync.WaitGroup
+		signatures = make([][bls.SignatureLen]byte, 50)
+		errs       = make([]error, len(signatures))
+	)
+	for i := range signatures {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			signatures[i], errs[i] = backend.GetSignature(unsignedMsg.ID())
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range signatures {
+		require.NoError(t, errs[i])
+		require.Equal(t, expectedSig, signatures[i])
+	}
+	require.EqualValues(t, 1, warpSigner.signs.Load())
+}
@@ -0,0 +1,55 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CanonicalValidator is a validator of the canonical validator set, which aggregates
+// the weight of the validators registered with the same BLS public key.
+type CanonicalValidator struct {
+	PublicKey hexutil.Bytes `json:"publicKey"`
+	Weight    uint64        `json:"weight"`
+	NodeIDs   []ids.NodeID  `json:"nodeIDs"`
+}
+
+// CanonicalValidatorSet is the validator set a warp message signed at [PChainHeight] is
+// verified against. The signers of an aggregate signature are the validators whose index
+// in [Validators] is set in its bit set, and the quorum is computed over [TotalWeight].
+type CanonicalValidatorSet struct {
+	SubnetID     ids.ID `json:"subnetID"`
+	PChainHeight uint64 `json:"pChainHeight"`
+	// TotalWeight includes the weight of validators without a BLS public key, which
+	// are not in [Validators].
+	TotalWeight uint64               `json:"totalWeight"`
+	Validators  []CanonicalValidator `json:"validators"`
+}
+
+// getCanonicalValidatorSet returns the canonical validator set of [subnetID] at
+// [pChainHeight] according to [state].
+func getCanonicalValidatorSet(ctx context.Context, state avalancheWarp.ValidatorState, pChainHeight uint64, subnetID ids.ID) (*CanonicalValidatorSet, error) {
+	validators, totalWeight, err := avalancheWarp.GetCanonicalValidatorSet(ctx, state, pChainHeight, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	validatorSet := &CanonicalValidatorSet{
+		SubnetID:     subnetID,
+		PChainHeight: pChainHeight,
+		TotalWeight:  totalWeight,
+		Validators:   make([]CanonicalValidator, len(validators)),
+	}
+	for i, validator := range validators {
+		validatorSet.Validators[i] = CanonicalValidator{
+			PublicKey: validator.PublicKeyBytes,
+			Weight:    validator.Weight,
+			NodeIDs:   validator.NodeIDs,
+		}
+	}
+	return validatorSet, nil
+}
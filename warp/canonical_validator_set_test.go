@@ -0,0 +1,70 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetValidatorSet(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+	errUnknownHeight := errors.New("unknown height")
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	otherSK, err := bls.NewSecretKey()
+	require.NoError(err)
+	pk, otherPK := bls.PublicFromSecretKey(sk), bls.PublicFromSecretKey(otherSK)
+
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	pChainState := &validators.TestState{
+		GetValidatorSetF: func(ctx context.Context, height uint64, requestedSubnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			require.Equal(subnetID, requestedSubnetID)
+			if height != 10 {
+				return nil, errUnknownHeight
+			}
+			// The first two validators share a BLS key, and the last one has none.
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeIDs[0]: {NodeID: nodeIDs[0], PublicKey: pk, Weight: 100},
+				nodeIDs[1]: {NodeID: nodeIDs[1], PublicKey: pk, Weight: 50},
+				nodeIDs[2]: {NodeID: nodeIDs[2], PublicKey: otherPK, Weight: 200},
+				nodeIDs[3]: {NodeID: nodeIDs[3], Weight: 25},
+			}, nil
+		},
+	}
+	api := NewAPI(networkID, sourceChainID, subnetID, nil, pChainState, nil, nil)
+
+	validatorSet, err := api.GetValidatorSet(context.Background(), 10)
+	require.NoError(err)
+	require.Equal(subnetID, validatorSet.SubnetID)
+	require.Equal(uint64(10), validatorSet.PChainHeight)
+	require.Equal(uint64(375), validatorSet.TotalWeight)
+	require.Len(validatorSet.Validators, 2)
+	require.True(bytes.Compare(validatorSet.Validators[0].PublicKey, validatorSet.Validators[1].PublicKey) < 0)
+
+	for _, validator := range validatorSet.Validators {
+		switch {
+		case bytes.Equal(validator.PublicKey, bls.PublicKeyToBytes(pk)):
+			require.Equal(uint64(150), validator.Weight)
+			require.ElementsMatch(nodeIDs[:2], validator.NodeIDs)
+		case bytes.Equal(validator.PublicKey, bls.PublicKeyToBytes(otherPK)):
+			require.Equal(uint64(200), validator.Weight)
+			require.Equal(nodeIDs[2:3], validator.NodeIDs)
+		default:
+			require.FailNow("unexpected validator", validator.PublicKey)
+		}
+	}
+
+	_, err = api.GetValidatorSet(context.Background(), 11)
+	require.ErrorIs(err, errUnknownHeight)
+}
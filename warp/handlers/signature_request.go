@@ -16,6 +16,10 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// slowSigningThreshold is the signing latency above which a signature request is
+// logged as slow.
+const slowSigningThreshold = 100 * time.Millisecond
+
 // SignatureRequestHandler is a peer.RequestHandler for message.SignatureRequest
 // serving requested BLS signature data
 type SignatureRequestHandler interface {
@@ -45,13 +49,20 @@ func NewSignatureRequestHandler(backend warp.Backend, codec codec.Manager, stats
 func (s *signatureRequestHandler) OnSignatureRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, signatureRequest message.SignatureRequest) ([]byte, error) {
 	startTime := time.Now()
 	s.stats.IncSignatureRequest()
+	s.stats.MarkPeerSignatureRequest(nodeID)
 
 	// Always report signature request time
 	defer func() {
 		s.stats.UpdateSignatureRequestTime(time.Since(startTime))
 	}()
 
+	signingStartTime := time.Now()
 	signature, err := s.backend.GetSignature(signatureRequest.MessageID)
+	signingTime := time.Since(signingStartTime)
+	s.stats.UpdateSigningTime(signingTime)
+	if signingTime > slowSigningThreshold {
+		log.Warn("Slow warp signature request", "nodeID", nodeID, "requestID", requestID, "messageID", signatureRequest.MessageID, "duration", signingTime)
+	}
 	if err != nil {
 		log.Debug("Unknown warp signature requested", "messageID", signatureRequest.MessageID)
 		s.stats.IncSignatureMiss()
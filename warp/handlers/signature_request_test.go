@@ -36,6 +36,7 @@ func TestSignatureHandler(t *testing.T) {
 	signature, err := backend.GetSignature(messageID)
 	require.NoError(t, err)
 	unknownMessageID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
 
 	emptySignature := [bls.SignatureLen]byte{}
 	mockHandlerStats := &stats.MockSignatureRequestHandlerStats{}
@@ -56,6 +57,8 @@ func TestSignatureHandler(t *testing.T) {
 				require.EqualValues(t, 1, mockHandlerStats.SignatureRequestHit)
 				require.EqualValues(t, 0, mockHandlerStats.SignatureRequestMiss)
 				require.Greater(t, mockHandlerStats.SignatureRequestDuration, time.Duration(0))
+				require.Greater(t, mockHandlerStats.SigningDuration, time.Duration(0))
+				require.EqualValues(t, 1, mockHandlerStats.PeerSignatureRequests[nodeID])
 			},
 		},
 		"unknown": {
@@ -79,7 +82,7 @@ func TestSignatureHandler(t *testing.T) {
 
 		t.Run(name, func(t *testing.T) {
 			request, expectedResponse := test.setup()
-			responseBytes, err := signatureRequestHandler.OnSignatureRequest(context.Background(), ids.GenerateTestNodeID(), 1, request)
+			responseBytes, err := signatureRequestHandler.OnSignatureRequest(context.Background(), nodeID, 1, request)
 			require.NoError(t, err)
 
 			// If the expected response is empty, assert that the handler returns an empty response and return early.
@@ -4,9 +4,11 @@
 package stats
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-evm/metrics"
 )
 
@@ -20,6 +22,8 @@ type SignatureRequestHandlerStats interface {
 	IncSignatureHit()
 	IncSignatureMiss()
 	UpdateSignatureRequestTime(duration time.Duration)
+	UpdateSigningTime(duration time.Duration)
+	MarkPeerSignatureRequest(nodeID ids.NodeID)
 }
 
 type handlerStats struct {
@@ -28,6 +32,7 @@ type handlerStats struct {
 	signatureHit            metrics.Counter
 	signatureMiss           metrics.Counter
 	signatureProcessingTime metrics.Timer
+	signingTime             metrics.Timer
 }
 
 func NewStats() SignatureRequestHandlerStats {
@@ -36,6 +41,7 @@ func NewStats() SignatureRequestHandlerStats {
 		signatureHit:            metrics.GetOrRegisterCounter("signature_request_hit", nil),
 		signatureMiss:           metrics.GetOrRegisterCounter("signature_request_miss", nil),
 		signatureProcessingTime: metrics.GetOrRegisterTimer("signature_request_duration", nil),
+		signingTime:             metrics.GetOrRegisterTimer("signature_request_signing_duration", nil),
 	}
 }
 
@@ -45,6 +51,15 @@ func (h *handlerStats) IncSignatureMiss()    { h.signatureMiss.Inc(1) }
 func (h *handlerStats) UpdateSignatureRequestTime(duration time.Duration) {
 	h.signatureProcessingTime.Update(duration)
 }
+func (h *handlerStats) UpdateSigningTime(duration time.Duration) {
+	h.signingTime.Update(duration)
+}
+
+// MarkPeerSignatureRequest records a signature request from [nodeID] in a meter
+// tracking the request rate of that peer.
+func (h *handlerStats) MarkPeerSignatureRequest(nodeID ids.NodeID) {
+	metrics.GetOrRegisterMeter(fmt.Sprintf("signature_request_peer_%s", nodeID), nil).Mark(1)
+}
 
 // MockSignatureRequestHandlerStats is mock for capturing and asserting on handler metrics in test
 type MockSignatureRequestHandlerStats struct {
@@ -54,6 +69,8 @@ type MockSignatureRequestHandlerStats struct {
 	SignatureRequestHit,
 	SignatureRequestMiss uint32
 	SignatureRequestDuration time.Duration
+	SigningDuration          time.Duration
+	PeerSignatureRequests    map[ids.NodeID]uint32
 }
 
 func (m *MockSignatureRequestHandlerStats) Reset() {
@@ -64,6 +81,8 @@ func (m *MockSignatureRequestHandlerStats) Reset() {
 	m.SignatureRequestHit = 0
 	m.SignatureRequestMiss = 0
 	m.SignatureRequestDuration = 0
+	m.SigningDuration = 0
+	m.PeerSignatureRequests = nil
 }
 
 func (m *MockSignatureRequestHandlerStats) IncSignatureRequest() {
@@ -89,3 +108,18 @@ func (m *MockSignatureRequestHandlerStats) UpdateSignatureRequestTime(duration t
 	defer m.lock.Unlock()
 	m.SignatureRequestDuration += duration
 }
+
+func (m *MockSignatureRequestHandlerStats) UpdateSigningTime(duration time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.SigningDuration += duration
+}
+
+func (m *MockSignatureRequestHandlerStats) MarkPeerSignatureRequest(nodeID ids.NodeID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.PeerSignatureRequests == nil {
+		m.PeerSignatureRequests = make(map[ids.NodeID]uint32)
+	}
+	m.PeerSignatureRequests[nodeID]++
+}
@@ -0,0 +1,148 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/warp/aggregator"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SignHTTPPath is the path, relative to the chain's API root, of the HTTP endpoint
+// that serves warp message signatures.
+const SignHTTPPath = "/warp/sign"
+
+// maxSignResponseSize bounds the response body read by httpSignatureGetter.
+const maxSignResponseSize = 4 * 1024
+
+var _ aggregator.SignatureGetter = (*httpSignatureGetter)(nil)
+
+// SignRequest is the body of a request to the sign HTTP endpoint.
+type SignRequest struct {
+	// MessageID is the ID of the warp message to sign. The node only signs messages
+	// in its warp backend.
+	MessageID ids.ID `json:"messageID"`
+}
+
+// SignResponse is the body of a successful response of the sign HTTP endpoint.
+type SignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+type signHandler struct {
+	backend      Backend
+	authenticate func(http.Header) error
+}
+
+// NewSignHandler returns an HTTP handler that serves the signatures of the messages
+// in [backend] to requests accepted by [authenticate].
+func NewSignHandler(backend Backend, authenticate func(http.Header) error) http.Handler {
+	return &signHandler{
+		backend:      backend,
+		authenticate: authenticate,
+	}
+}
+
+func (h *signHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := h.authenticate(r.Header); err != nil {
+		http.Error(w, fmt.Sprintf("unauthorized: %s", err), http.StatusUnauthorized)
+		return
+	}
+	var req SignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	signature, err := h.backend.GetSignature(req.MessageID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get signature: %s", err), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&SignResponse{Signature: signature[:]}); err != nil {
+		log.Debug("Failed to write warp signature response", "err", err)
+	}
+}
+
+// httpSignatureGetter fetches warp signatures from the sign HTTP endpoints of
+// validators, bypassing the p2p layer.
+type httpSignatureGetter struct {
+	endpoints map[ids.NodeID]string
+	auth      rpc.HTTPAuth
+	client    *http.Client
+}
+
+// NewHTTPSignatureGetter returns a SignatureGetter that fetches signatures for messages
+// sent by [chain] from the sign HTTP endpoints of the validators in [uris], which maps
+// NodeIDs to the base URIs of their APIs. [auth] authenticates each request.
+func NewHTTPSignatureGetter(uris map[ids.NodeID]string, chain string, auth rpc.HTTPAuth) aggregator.SignatureGetter {
+	endpoints := make(map[ids.NodeID]string, len(uris))
+	for nodeID, uri := range uris {
+		endpoints[nodeID] = fmt.Sprintf("%s/ext/bc/%s%s", uri, chain, SignHTTPPath)
+	}
+	return &httpSignatureGetter{
+		endpoints: endpoints,
+		auth:      auth,
+		client:    &http.Client{},
+	}
+}
+
+func (g *httpSignatureGetter) GetSignature(ctx context.Context, nodeID ids.NodeID, unsignedWarpMessage *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+	endpoint, ok := g.endpoints[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("no warp sign endpoint for nodeID: %s", nodeID)
+	}
+	body, err := json.Marshal(&SignRequest{MessageID: unsignedWarpMessage.ID()})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.auth != nil {
+		if err := g.auth(req.Header); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request signature from %s: %w", nodeID, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxSignResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature response from %s: %w", nodeID, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get signature from %s: %s: %s", nodeID, resp.Status, bytes.TrimSpace(respBody))
+	}
+	var res SignResponse
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse signature response from %s: %w", nodeID, err)
+	}
+	signature, err := bls.SignatureFromBytes(res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signature from %s: %w", nodeID, err)
+	}
+	return signature, nil
+}
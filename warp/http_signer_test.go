@@ -0,0 +1,68 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSignatureGetter(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
+	backend := NewBackend(warpSigner, memdb.New(), 500)
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, payload)
+	require.NoError(err)
+	require.NoError(backend.AddMessage(unsignedMsg))
+
+	secret := make([]byte, rpc.JWTSecretLength)
+	secret[0] = 1
+	authenticate, err := rpc.NewJWTAuthenticator(secret)
+	require.NoError(err)
+	auth, err := rpc.NewJWTAuth(secret)
+	require.NoError(err)
+
+	chain := sourceChainID.String()
+	mux := http.NewServeMux()
+	mux.Handle("/ext/bc/"+chain+SignHTTPPath, NewSignHandler(backend, authenticate))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	nodeID := ids.GenerateTestNodeID()
+	getter := NewHTTPSignatureGetter(map[ids.NodeID]string{nodeID: server.URL}, chain, auth)
+	signature, err := getter.GetSignature(context.Background(), nodeID, unsignedMsg)
+	require.NoError(err)
+	require.True(bls.Verify(bls.PublicFromSecretKey(sk), signature, unsignedMsg.Bytes()))
+
+	// Messages missing from the backend are not signed.
+	otherMsg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, []byte("other"))
+	require.NoError(err)
+	_, err = getter.GetSignature(context.Background(), nodeID, otherMsg)
+	require.ErrorContains(err, "404")
+
+	// Unauthenticated requests are rejected.
+	unauthenticated := NewHTTPSignatureGetter(map[ids.NodeID]string{nodeID: server.URL}, chain, nil)
+	_, err = unauthenticated.GetSignature(context.Background(), nodeID, unsignedMsg)
+	require.ErrorContains(err, "401")
+
+	_, err = getter.GetSignature(context.Background(), ids.GenerateTestNodeID(), unsignedMsg)
+	require.ErrorContains(err, "no warp sign endpoint")
+
+	resp, err := http.Get(server.URL + "/ext/bc/" + chain + SignHTTPPath)
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+}
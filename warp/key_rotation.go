@@ -0,0 +1,165 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// registeredKeyRefreshInterval bounds how often the P-Chain is queried for the
+// BLS key registered for this node.
+const registeredKeyRefreshInterval = 10 * time.Second
+
+var _ avalancheWarp.Signer = (*RotatingSigner)(nil)
+
+// RotatingSigner signs warp messages with either the current or the next BLS key
+// of this node, so that validators can rotate their keys without a window of
+// failed signature requests.
+//
+// The key registered for this node on the P-Chain is used whenever it is one of
+// the two keys, since that is the key verifiers check signatures against. Otherwise,
+// such as when the P-Chain cannot be queried, the next key is used from its
+// activation time.
+type RotatingSigner struct {
+	current    avalancheWarp.Signer
+	currentKey [bls.PublicKeyLen]byte
+	next       avalancheWarp.Signer
+	nextKey    [bls.PublicKeyLen]byte
+	activation time.Time
+
+	state    validators.State
+	nodeID   ids.NodeID
+	subnetID ids.ID
+	now      func() time.Time
+
+	lock       sync.Mutex
+	useNext    bool
+	checkedAt  time.Time // zero until the registered key is first checked
+	refreshing bool      // whether the registered key is being checked
+}
+
+// NewRotatingSigner returns a RotatingSigner that switches from [current] to [next]
+// when [nextKey] is registered for [nodeID] on the P-Chain, or at [activation].
+func NewRotatingSigner(
+	current avalancheWarp.Signer,
+	currentKey *bls.PublicKey,
+	next avalancheWarp.Signer,
+	nextKey *bls.PublicKey,
+	activation time.Time,
+	state validators.State,
+	nodeID ids.NodeID,
+	subnetID ids.ID,
+) *RotatingSigner {
+	s := &RotatingSigner{
+		current:    current,
+		next:       next,
+		activation: activation,
+		state:      state,
+		nodeID:     nodeID,
+		subnetID:   subnetID,
+		now:        time.Now,
+	}
+	if currentKey != nil {
+		copy(s.currentKey[:], bls.PublicKeyToBytes(currentKey))
+	}
+	copy(s.nextKey[:], bls.PublicKeyToBytes(nextKey))
+	return s
+}
+
+func (s *RotatingSigner) Sign(msg *avalancheWarp.UnsignedMessage) ([]byte, error) {
+	signer, _ := s.Active()
+	return signer.Sign(msg)
+}
+
+// Active returns the signer to sign messages with and its public key.
+//
+// The registered key is checked by the caller that finds the last check stale, without
+// holding the lock, so that other callers keep signing with the key chosen by the last
+// check rather than waiting on the P-Chain.
+func (s *RotatingSigner) Active() (avalancheWarp.Signer, [bls.PublicKeyLen]byte) {
+	now := s.now()
+	if s.startRefresh(now) {
+		useNext, err := s.registeredNext(context.TODO())
+		if err != nil {
+			log.Debug("Failed to check registered BLS key, falling back to activation time", "err", err)
+			useNext = !now.Before(s.activation)
+		}
+		s.finishRefresh(now, useNext)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	useNext := s.useNext
+	if s.checkedAt.IsZero() {
+		// The first check is still in progress.
+		useNext = !now.Before(s.activation)
+	}
+	if useNext {
+		return s.next, s.nextKey
+	}
+	return s.current, s.currentKey
+}
+
+// startRefresh returns true if the registered key should be checked at [now], in which
+// case the caller must call finishRefresh once it is checked.
+func (s *RotatingSigner) startRefresh(now time.Time) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.refreshing || (!s.checkedAt.IsZero() && now.Sub(s.checkedAt) < registeredKeyRefreshInterval) {
+		return false
+	}
+	s.refreshing = true
+	return true
+}
+
+// finishRefresh records the result of the check of the registered key started at [now].
+func (s *RotatingSigner) finishRefresh(now time.Time, useNext bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refreshing = false
+	s.checkedAt = now
+	if useNext != s.useNext {
+		log.Info("Switching warp signing key", "next", useNext)
+		s.useNext = useNext
+	}
+}
+
+// registeredNext returns whether the next key is the one registered for this node
+// on the P-Chain. Returns an error if neither key is registered.
+func (s *RotatingSigner) registeredNext(ctx context.Context) (bool, error) {
+	height, err := s.state.GetCurrentHeight(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get current P-Chain height: %w", err)
+	}
+	vdrs, err := s.state.GetValidatorSet(ctx, height, s.subnetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get validator set at P-Chain height %d: %w", height, err)
+	}
+	vdr, ok := vdrs[s.nodeID]
+	if !ok || vdr.PublicKey == nil {
+		return false, fmt.Errorf("no BLS key registered for %s at P-Chain height %d", s.nodeID, height)
+	}
+	registered := bls.PublicKeyToBytes(vdr.PublicKey)
+	switch {
+	case bytes.Equal(registered, s.nextKey[:]):
+		return true, nil
+	case bytes.Equal(registered, s.currentKey[:]):
+		return false, nil
+	default:
+		return false, fmt.Errorf("BLS key registered for %s at P-Chain height %d is neither the current nor the next key", s.nodeID, height)
+	}
+}
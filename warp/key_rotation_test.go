@@ -0,0 +1,139 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingSigner(t *testing.T) {
+	require := require.New(t)
+	nodeID := ids.GenerateTestNodeID()
+	subnetID := ids.GenerateTestID()
+
+	currentSK, err := bls.NewSecretKey()
+	require.NoError(err)
+	nextSK, err := bls.NewSecretKey()
+	require.NoError(err)
+	currentPK := bls.PublicFromSecretKey(currentSK)
+	nextPK := bls.PublicFromSecretKey(nextSK)
+
+	var (
+		registered *bls.PublicKey
+		stateErr   error
+	)
+	state := &validators.TestState{
+		GetCurrentHeightF: func(context.Context) (uint64, error) { return 10, stateErr },
+		GetValidatorSetF: func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeID: {NodeID: nodeID, PublicKey: registered, Weight: 100},
+			}, nil
+		},
+	}
+
+	now := time.Unix(1000, 0)
+	activation := now.Add(time.Hour)
+	signer := NewRotatingSigner(
+		avalancheWarp.NewSigner(currentSK, networkID, sourceChainID),
+		currentPK,
+		avalancheWarp.NewSigner(nextSK, networkID, sourceChainID),
+		nextPK,
+		activation,
+		state,
+		nodeID,
+		subnetID,
+	)
+	signer.now = func() time.Time { return now }
+	backend := NewBackend(signer, memdb.New(), 500)
+
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, payload)
+	require.NoError(err)
+	require.NoError(backend.AddMessage(unsignedMessage))
+	requireSignedBy := func(pk *bls.PublicKey) {
+		signatureBytes, err := backend.GetSignature(unsignedMessage.ID())
+		require.NoError(err)
+		signature, err := bls.SignatureFromBytes(signatureBytes[:])
+		require.NoError(err)
+		require.True(bls.Verify(pk, signature, unsignedMessage.Bytes()))
+	}
+
+	// The registered key is used, even before the activation time.
+	registered = currentPK
+	requireSignedBy(currentPK)
+	registered = nextPK
+	now = now.Add(registeredKeyRefreshInterval)
+	requireSignedBy(nextPK)
+
+	// Without a registered key, the activation time decides.
+	stateErr = errors.New("unavailable")
+	now = now.Add(registeredKeyRefreshInterval)
+	requireSignedBy(currentPK)
+	now = activation
+	requireSignedBy(nextPK)
+}
+
+func TestRotatingSignerDoesNotWaitOnRefresh(t *testing.T) {
+	require := require.New(t)
+	nodeID := ids.GenerateTestNodeID()
+
+	currentSK, err := bls.NewSecretKey()
+	require.NoError(err)
+	nextSK, err := bls.NewSecretKey()
+	require.NoError(err)
+	currentPK := bls.PublicFromSecretKey(currentSK)
+	nextPK := bls.PublicFromSecretKey(nextSK)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	state := &validators.TestState{
+		GetCurrentHeightF: func(context.Context) (uint64, error) {
+			close(started)
+			<-release
+			return 10, nil
+		},
+		GetValidatorSetF: func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeID: {NodeID: nodeID, PublicKey: nextPK, Weight: 100},
+			}, nil
+		},
+	}
+	now := time.Unix(1000, 0)
+	signer := NewRotatingSigner(
+		avalancheWarp.NewSigner(currentSK, networkID, sourceChainID),
+		currentPK,
+		avalancheWarp.NewSigner(nextSK, networkID, sourceChainID),
+		nextPK,
+		now.Add(time.Hour),
+		state,
+		nodeID,
+		ids.GenerateTestID(),
+	)
+	signer.now = func() time.Time { return now }
+
+	done := make(chan [bls.PublicKeyLen]byte)
+	go func() {
+		_, key := signer.Active()
+		done <- key
+	}()
+	<-started
+
+	// While the P-Chain is queried, the activation time decides.
+	_, key := signer.Active()
+	require.Equal(bls.PublicKeyToBytes(currentPK), key[:])
+
+	close(release)
+	key = <-done
+	require.Equal(bls.PublicKeyToBytes(nextPK), key[:])
+	_, key = signer.Active()
+	require.Equal(bls.PublicKeyToBytes(nextPK), key[:])
+}
@@ -35,6 +35,7 @@ func init() {
 	errs.Add(
 		lc.RegisterType(&AddressedPayload{}),
 		lc.RegisterType(&BlockHashPayload{}),
+		lc.RegisterType(&ValidatorSetPayload{}),
 		c.RegisterCodec(codecVersion, lc),
 	)
 	if errs.Errored() {
@@ -0,0 +1,81 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package payload
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+// ValidatorSetEntry is a validator in a ValidatorSetPayload
+type ValidatorSetEntry struct {
+	PublicKey [bls.PublicKeyLen]byte `serialize:"true"`
+	Weight    uint64                 `serialize:"true"`
+}
+
+// ValidatorSetPayload includes the validator set of a subnet at a P-Chain height,
+// in the canonical order used to build the signer bitsets of warp signatures.
+type ValidatorSetPayload struct {
+	SubnetID     ids.ID              `serialize:"true"`
+	PChainHeight uint64              `serialize:"true"`
+	Validators   []ValidatorSetEntry `serialize:"true"`
+
+	bytes []byte
+}
+
+// NewValidatorSetPayload creates a new *ValidatorSetPayload and initializes it.
+func NewValidatorSetPayload(subnetID ids.ID, pChainHeight uint64, validators []ValidatorSetEntry) (*ValidatorSetPayload, error) {
+	vsp := &ValidatorSetPayload{
+		SubnetID:     subnetID,
+		PChainHeight: pChainHeight,
+		Validators:   validators,
+	}
+	return vsp, vsp.initialize()
+}
+
+// ParseValidatorSetPayload converts a slice of bytes into an initialized
+// ValidatorSetPayload
+func ParseValidatorSetPayload(b []byte) (*ValidatorSetPayload, error) {
+	var unmarshalledPayloadIntf any
+	if _, err := c.Unmarshal(b, &unmarshalledPayloadIntf); err != nil {
+		return nil, err
+	}
+	payload, ok := unmarshalledPayloadIntf.(*ValidatorSetPayload)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errWrongType, unmarshalledPayloadIntf)
+	}
+	payload.bytes = b
+	return payload, nil
+}
+
+// TotalWeight returns the sum of the weights of the validators in the payload.
+func (v *ValidatorSetPayload) TotalWeight() (uint64, error) {
+	var total uint64
+	for _, validator := range v.Validators {
+		if total+validator.Weight < total {
+			return 0, fmt.Errorf("total weight of validator set at P-Chain height %d overflows", v.PChainHeight)
+		}
+		total += validator.Weight
+	}
+	return total, nil
+}
+
+// initialize recalculates the result of Bytes().
+func (v *ValidatorSetPayload) initialize() error {
+	payloadIntf := any(v)
+	bytes, err := c.Marshal(codecVersion, &payloadIntf)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal validator set payload: %w", err)
+	}
+	v.bytes = bytes
+	return nil
+}
+
+// Bytes returns the binary representation of this payload. It assumes that the
+// payload is initialized from either NewValidatorSetPayload or ParseValidatorSetPayload.
+func (v *ValidatorSetPayload) Bytes() []byte {
+	return v.bytes
+}
@@ -0,0 +1,69 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SnapshotEntry is a single warp message in a snapshot of the warp backend.
+type SnapshotEntry struct {
+	MessageID       ids.ID        `json:"messageID"`
+	UnsignedMessage hexutil.Bytes `json:"unsignedMessage"`
+	// Provenance is nil for messages added before provenance was recorded.
+	Provenance *MessageProvenance `json:"provenance,omitempty"`
+}
+
+// ExportSnapshot writes every message in [backend] to [w] as newline delimited JSON
+// [SnapshotEntry] values, so that relayers can ingest the messages without scanning
+// the chain for them. Returns the number of messages written.
+func ExportSnapshot(backend Backend, w io.Writer) (int, error) {
+	var (
+		bw      = bufio.NewWriter(w)
+		encoder = json.NewEncoder(bw)
+		count   = 0
+	)
+	err := backend.ForEachMessage(func(unsignedMessage *avalancheWarp.UnsignedMessage, provenance *MessageProvenance) error {
+		count++
+		return encoder.Encode(&SnapshotEntry{
+			MessageID:       unsignedMessage.ID(),
+			UnsignedMessage: unsignedMessage.Bytes(),
+			Provenance:      provenance,
+		})
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+// ReadSnapshot calls [fn] with each entry of a snapshot written by [ExportSnapshot]
+// to [r]. Returns an error if an entry is malformed, or the first error returned by [fn].
+func ReadSnapshot(r io.Reader, fn func(entry *SnapshotEntry) error) error {
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		entry := new(SnapshotEntry)
+		if err := decoder.Decode(entry); err != nil {
+			return fmt.Errorf("failed to decode snapshot entry: %w", err)
+		}
+		unsignedMessage, err := avalancheWarp.ParseUnsignedMessage(entry.UnsignedMessage)
+		if err != nil {
+			return fmt.Errorf("failed to parse unsigned message %s: %w", entry.MessageID, err)
+		}
+		if unsignedMessage.ID() != entry.MessageID {
+			return fmt.Errorf("snapshot entry message ID %s does not match message %s", entry.MessageID, unsignedMessage.ID())
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
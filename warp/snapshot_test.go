@@ -0,0 +1,59 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	backend := NewBackend(avalancheWarp.NewSigner(sk, networkID, sourceChainID), memdb.New(), 500)
+
+	legacyMsg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, []byte("legacy"))
+	require.NoError(err)
+	require.NoError(backend.AddMessage(legacyMsg))
+	sentMsg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, []byte("sent"))
+	require.NoError(err)
+	provenance := &MessageProvenance{
+		BlockNumber: 10,
+		BlockHash:   common.Hash{1},
+		TxHash:      common.Hash{2},
+		LogIndex:    3,
+	}
+	require.NoError(backend.AddMessageWithProvenance(sentMsg, provenance))
+
+	var buf bytes.Buffer
+	count, err := ExportSnapshot(backend, &buf)
+	require.NoError(err)
+	require.Equal(2, count)
+
+	entries := make(map[ids.ID]*SnapshotEntry)
+	require.NoError(ReadSnapshot(&buf, func(entry *SnapshotEntry) error {
+		entries[entry.MessageID] = entry
+		return nil
+	}))
+	require.Len(entries, 2)
+	require.Equal([]byte(entries[legacyMsg.ID()].UnsignedMessage), legacyMsg.Bytes())
+	require.Nil(entries[legacyMsg.ID()].Provenance)
+	require.Equal([]byte(entries[sentMsg.ID()].UnsignedMessage), sentMsg.Bytes())
+	require.Equal(provenance, entries[sentMsg.ID()].Provenance)
+
+	// Entries whose message ID does not match the message are rejected.
+	buf.Reset()
+	buf.WriteString(`{"messageID":"` + ids.GenerateTestID().String() + `","unsignedMessage":"` + hexutil.Encode(sentMsg.Bytes()) + `"}`)
+	require.ErrorContains(ReadSnapshot(&buf, func(*SnapshotEntry) error { return nil }), "does not match")
+}
@@ -0,0 +1,213 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	errNoValidatorSetMessage   = errors.New("no validator set message signed yet")
+	errNotValidatorSetEpoch    = errors.New("P-Chain height is not a validator set epoch")
+	errValidatorSetWrongChain  = errors.New("validator set message was not sent by the expected chain")
+	errValidatorSetWrongSubnet = errors.New("validator set message is for a different subnet")
+	errValidatorSetNotNewer    = errors.New("validator set message is not newer than the trusted validator set")
+	errMissingBitSetSignature  = errors.New("validator set message does not have a bit set signature")
+)
+
+// ValidatorSetSigner adds warp messages summarizing the validator set of the subnet to
+// the warp backend, so that validators sign them and external light clients can follow
+// changes to the validator set from a trusted starting set.
+//
+// Messages are only created for P-Chain heights that are a multiple of the epoch, so that
+// all validators sign the same messages even though they observe the P-Chain at different
+// heights.
+type ValidatorSetSigner struct {
+	backend       Backend
+	state         avalancheWarp.ValidatorState
+	networkID     uint32
+	sourceChainID ids.ID
+	subnetID      ids.ID
+	epoch         uint64
+
+	lock   sync.Mutex
+	latest *avalancheWarp.UnsignedMessage // nil until the first message is signed
+	height uint64                         // P-Chain height of [latest]
+}
+
+func NewValidatorSetSigner(backend Backend, state avalancheWarp.ValidatorState, networkID uint32, sourceChainID ids.ID, subnetID ids.ID, epoch uint64) *ValidatorSetSigner {
+	return &ValidatorSetSigner{
+		backend:       backend,
+		state:         state,
+		networkID:     networkID,
+		sourceChainID: sourceChainID,
+		subnetID:      subnetID,
+		epoch:         epoch,
+	}
+}
+
+// Update adds the validator set message of the last epoch at or below [pChainHeight] to
+// the warp backend, if it was not added yet.
+func (s *ValidatorSetSigner) Update(ctx context.Context, pChainHeight uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	height := pChainHeight - pChainHeight%s.epoch
+	if s.latest != nil && height <= s.height {
+		return nil
+	}
+	unsignedMessage, err := s.newMessage(ctx, height)
+	if err != nil {
+		return err
+	}
+	if err := s.backend.AddMessage(unsignedMessage); err != nil {
+		return fmt.Errorf("failed to add validator set message: %w", err)
+	}
+	log.Debug("Added validator set warp message", "pChainHeight", height, "messageID", unsignedMessage.ID())
+	s.latest = unsignedMessage
+	s.height = height
+	return nil
+}
+
+// Message returns the validator set message at [pChainHeight], or the latest one if
+// [pChainHeight] is nil. Messages are only signed at the P-Chain heights this node
+// observed while running.
+func (s *ValidatorSetSigner) Message(ctx context.Context, pChainHeight *uint64) (*avalancheWarp.UnsignedMessage, error) {
+	if pChainHeight == nil {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if s.latest == nil {
+			return nil, errNoValidatorSetMessage
+		}
+		return s.latest, nil
+	}
+	if *pChainHeight%s.epoch != 0 {
+		return nil, fmt.Errorf("%w: %d is not a multiple of %d", errNotValidatorSetEpoch, *pChainHeight, s.epoch)
+	}
+	unsignedMessage, err := s.newMessage(ctx, *pChainHeight)
+	if err != nil {
+		return nil, err
+	}
+	// Validators only sign the messages in their backend, so check that this node
+	// signed the message before requesting signatures for it.
+	if _, err := s.backend.GetMessage(unsignedMessage.ID()); err != nil {
+		return nil, fmt.Errorf("validator set message at P-Chain height %d was not signed: %w", *pChainHeight, err)
+	}
+	return unsignedMessage, nil
+}
+
+// newMessage returns the unsigned validator set message at [pChainHeight].
+func (s *ValidatorSetSigner) newMessage(ctx context.Context, pChainHeight uint64) (*avalancheWarp.UnsignedMessage, error) {
+	validators, _, err := avalancheWarp.GetCanonicalValidatorSet(ctx, s.state, pChainHeight, s.subnetID)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]warpPayload.ValidatorSetEntry, len(validators))
+	for i, validator := range validators {
+		copy(entries[i].PublicKey[:], validator.PublicKeyBytes)
+		entries[i].Weight = validator.Weight
+	}
+	validatorSetPayload, err := warpPayload.NewValidatorSetPayload(s.subnetID, pChainHeight, entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator set payload: %w", err)
+	}
+	return avalancheWarp.NewUnsignedMessage(s.networkID, s.sourceChainID, validatorSetPayload.Bytes())
+}
+
+// VerifyValidatorSetMessage verifies that [signedMessageBytes] is a validator set message
+// sent by [sourceChainID] for a newer P-Chain height than [trusted], and signed by
+// [quorumNum]/[quorumDen] of the stake of [trusted]. Returns the validator set of the
+// message, which can be trusted to verify the next one.
+//
+// Unlike the verification of other warp messages, this does not query the P-Chain, so
+// that light clients can follow the validator set from a trusted starting set alone.
+func VerifyValidatorSetMessage(
+	signedMessageBytes []byte,
+	networkID uint32,
+	sourceChainID ids.ID,
+	trusted *warpPayload.ValidatorSetPayload,
+	quorumNum uint64,
+	quorumDen uint64,
+) (*warpPayload.ValidatorSetPayload, error) {
+	msg, err := avalancheWarp.ParseMessage(signedMessageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signed message: %w", err)
+	}
+	if msg.NetworkID != networkID {
+		return nil, avalancheWarp.ErrWrongNetworkID
+	}
+	if msg.SourceChainID != sourceChainID {
+		return nil, fmt.Errorf("%w: expected %s, got %s", errValidatorSetWrongChain, sourceChainID, msg.SourceChainID)
+	}
+	validatorSet, err := warpPayload.ParseValidatorSetPayload(msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse validator set payload: %w", err)
+	}
+	if validatorSet.SubnetID != trusted.SubnetID {
+		return nil, fmt.Errorf("%w: expected %s, got %s", errValidatorSetWrongSubnet, trusted.SubnetID, validatorSet.SubnetID)
+	}
+	if validatorSet.PChainHeight <= trusted.PChainHeight {
+		return nil, fmt.Errorf("%w: %d <= %d", errValidatorSetNotNewer, validatorSet.PChainHeight, trusted.PChainHeight)
+	}
+
+	signature, ok := msg.Signature.(*avalancheWarp.BitSetSignature)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errMissingBitSetSignature, msg.Signature)
+	}
+	validators := make([]*avalancheWarp.Validator, len(trusted.Validators))
+	for i, entry := range trusted.Validators {
+		publicKey, err := bls.PublicKeyFromBytes(entry.PublicKey[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of trusted validator %d: %w", i, err)
+		}
+		validators[i] = &avalancheWarp.Validator{
+			PublicKey:      publicKey,
+			PublicKeyBytes: entry.PublicKey[:],
+			Weight:         entry.Weight,
+		}
+	}
+	totalWeight, err := trusted.TotalWeight()
+	if err != nil {
+		return nil, err
+	}
+
+	signerIndices := set.BitsFromBytes(signature.Signers)
+	if len(signerIndices.Bytes()) != len(signature.Signers) {
+		return nil, avalancheWarp.ErrInvalidBitSet
+	}
+	signers, err := avalancheWarp.FilterValidators(signerIndices, validators)
+	if err != nil {
+		return nil, err
+	}
+	signatureWeight, err := avalancheWarp.SumWeight(signers)
+	if err != nil {
+		return nil, err
+	}
+	if err := avalancheWarp.VerifyWeight(signatureWeight, totalWeight, quorumNum, quorumDen); err != nil {
+		return nil, err
+	}
+	aggregateSignature, err := bls.SignatureFromBytes(signature.Signature[:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", avalancheWarp.ErrParseSignature, err)
+	}
+	aggregatePublicKey, err := avalancheWarp.AggregatePublicKeys(signers)
+	if err != nil {
+		return nil, err
+	}
+	if !bls.Verify(aggregatePublicKey, aggregateSignature, msg.UnsignedMessage.Bytes()) {
+		return nil, avalancheWarp.ErrInvalidSignature
+	}
+	return validatorSet, nil
+}
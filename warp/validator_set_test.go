@@ -0,0 +1,112 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorSetSigner(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	secretKeys := make([]*bls.SecretKey, 3)
+	for i := range secretKeys {
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+		secretKeys[i] = sk
+	}
+	// The validator set changes at P-Chain height 20.
+	validatorSets := map[uint64][]*bls.SecretKey{
+		10: secretKeys[:2],
+		20: secretKeys[1:],
+	}
+	pChainState := &validators.TestState{
+		GetValidatorSetF: func(ctx context.Context, height uint64, requestedSubnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			require.Equal(subnetID, requestedSubnetID)
+			vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput)
+			for _, sk := range validatorSets[height] {
+				nodeID := ids.GenerateTestNodeID()
+				vdrs[nodeID] = &validators.GetValidatorOutput{NodeID: nodeID, PublicKey: bls.PublicFromSecretKey(sk), Weight: 100}
+			}
+			return vdrs, nil
+		},
+	}
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	backend := NewBackend(avalancheWarp.NewSigner(sk, networkID, sourceChainID), memdb.New(), 500)
+	signer := NewValidatorSetSigner(backend, pChainState, networkID, sourceChainID, subnetID, 10)
+
+	_, err = signer.Message(context.Background(), nil)
+	require.ErrorIs(err, errNoValidatorSetMessage)
+
+	require.NoError(signer.Update(context.Background(), 15))
+	trustedMessage, err := signer.Message(context.Background(), nil)
+	require.NoError(err)
+	trusted, err := warpPayload.ParseValidatorSetPayload(trustedMessage.Payload)
+	require.NoError(err)
+	require.Equal(uint64(10), trusted.PChainHeight)
+	require.Len(trusted.Validators, 2)
+
+	// Heights in the same epoch do not sign a new message.
+	require.NoError(signer.Update(context.Background(), 19))
+	latest, err := signer.Message(context.Background(), nil)
+	require.NoError(err)
+	require.Equal(trustedMessage.ID(), latest.ID())
+
+	require.NoError(signer.Update(context.Background(), 23))
+	height := uint64(20)
+	nextMessage, err := signer.Message(context.Background(), &height)
+	require.NoError(err)
+	_, err = backend.GetMessage(nextMessage.ID())
+	require.NoError(err)
+
+	height = 25
+	_, err = signer.Message(context.Background(), &height)
+	require.ErrorIs(err, errNotValidatorSetEpoch)
+
+	// Sign the next validator set with the validators of the trusted set, in canonical order.
+	canonical, _, err := avalancheWarp.GetCanonicalValidatorSet(context.Background(), pChainState, 10, subnetID)
+	require.NoError(err)
+	signers := set.NewBits()
+	var signatures []*bls.Signature
+	for i, validator := range canonical {
+		for _, sk := range validatorSets[10] {
+			if bytes.Equal(bls.PublicKeyToBytes(bls.PublicFromSecretKey(sk)), validator.PublicKeyBytes) {
+				signers.Add(i)
+				signatures = append(signatures, bls.Sign(sk, nextMessage.Bytes()))
+			}
+		}
+	}
+	aggregateSignature, err := bls.AggregateSignatures(signatures)
+	require.NoError(err)
+	signature := &avalancheWarp.BitSetSignature{Signers: signers.Bytes()}
+	copy(signature.Signature[:], bls.SignatureToBytes(aggregateSignature))
+	signedMessage, err := avalancheWarp.NewMessage(nextMessage, signature)
+	require.NoError(err)
+
+	next, err := VerifyValidatorSetMessage(signedMessage.Bytes(), networkID, sourceChainID, trusted, 67, 100)
+	require.NoError(err)
+	require.Equal(uint64(20), next.PChainHeight)
+	require.Len(next.Validators, 2)
+
+	// The next message must not be verified against itself, since it is not newer.
+	_, err = VerifyValidatorSetMessage(signedMessage.Bytes(), networkID, sourceChainID, next, 67, 100)
+	require.ErrorIs(err, errValidatorSetNotNewer)
+
+	_, err = VerifyValidatorSetMessage(signedMessage.Bytes(), networkID, ids.GenerateTestID(), trusted, 67, 100)
+	require.ErrorIs(err, errValidatorSetWrongChain)
+}
@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+var _ validators.State = (*PChainState)(nil)
+
+// PChainState implements [validators.State] by querying the P-Chain API of a node, so
+// that validator sets can be looked up by processes that are not running a node, such
+// as a standalone signature aggregator.
+//
+// The P-Chain API reports the weights of a validator set at any height, but only the
+// BLS public keys of the current validators. Since a node's BLS key does not change
+// while it is validating, the public keys of the current primary network validators
+// are used for validator sets at past heights.
+type PChainState struct {
+	client platformvm.Client
+}
+
+// NewPChainState returns a validator state backed by the P-Chain API [client].
+func NewPChainState(client platformvm.Client) *PChainState {
+	return &PChainState{client: client}
+}
+
+// GetMinimumHeight returns the minimum height of the P-Chain, as the API serves the
+// validator sets at any height.
+func (*PChainState) GetMinimumHeight(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (s *PChainState) GetCurrentHeight(ctx context.Context) (uint64, error) {
+	return s.client.GetHeight(ctx)
+}
+
+func (s *PChainState) GetSubnetID(ctx context.Context, chainID ids.ID) (ids.ID, error) {
+	return s.client.ValidatedBy(ctx, chainID)
+}
+
+func (s *PChainState) GetValidatorSet(
+	ctx context.Context,
+	height uint64,
+	subnetID ids.ID,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	weights, err := s.client.GetValidatorsAt(ctx, subnetID, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators of subnet %s at height %d: %w", subnetID, height, err)
+	}
+	nodeIDs := make([]ids.NodeID, 0, len(weights))
+	for nodeID := range weights {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	// BLS keys are registered by primary network validators, including the validators
+	// of every subnet.
+	primaryValidators, err := s.client.GetCurrentValidators(ctx, constants.PrimaryNetworkID, nodeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BLS keys of validators: %w", err)
+	}
+	publicKeys := make(map[ids.NodeID]*bls.PublicKey, len(primaryValidators))
+	for _, validator := range primaryValidators {
+		if validator.Signer == nil {
+			continue
+		}
+		publicKey, err := bls.PublicKeyFromBytes(validator.Signer.PublicKey[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS key of validator %s: %w", validator.NodeID, err)
+		}
+		publicKeys[validator.NodeID] = publicKey
+	}
+
+	validatorSet := make(map[ids.NodeID]*validators.GetValidatorOutput, len(weights))
+	for nodeID, weight := range weights {
+		validatorSet[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: publicKeys[nodeID],
+			Weight:    weight,
+		}
+	}
+	return validatorSet, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
@@ -19,6 +20,14 @@ type Client interface {
 	GetSignature(ctx context.Context, messageID ids.ID) ([]byte, error)
 	// GetAggregateSignature requests the aggregate signature associated with messageID
 	GetAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64) ([]byte, error)
+	// GetAggregateSignatureWithQuorum requests the aggregate signature associated with messageID
+	// signed by quorumNum/quorumDen of the stake
+	GetAggregateSignatureWithQuorum(ctx context.Context, messageID ids.ID, quorumNum uint64, quorumDen uint64) ([]byte, error)
+	// GetBlockAcceptanceProof requests a proof that the block with blockHash was accepted,
+	// signed by quorumNum/quorumDen of the stake
+	GetBlockAcceptanceProof(ctx context.Context, blockHash common.Hash, quorumNum uint64, quorumDen uint64) (*AcceptanceProof, error)
+	// GetValidatorSet requests the canonical validator set of the subnet at pChainHeight
+	GetValidatorSet(ctx context.Context, pChainHeight uint64) (*CanonicalValidatorSet, error)
 }
 
 // client implementation for interacting with EVM [chain]
@@ -52,3 +61,27 @@ func (c *client) GetAggregateSignature(ctx context.Context, messageID ids.ID, qu
 	}
 	return res, nil
 }
+
+func (c *client) GetAggregateSignatureWithQuorum(ctx context.Context, messageID ids.ID, quorumNum uint64, quorumDen uint64) ([]byte, error) {
+	var res hexutil.Bytes
+	if err := c.client.CallContext(ctx, &res, "warp_getAggregateSignature", messageID, quorumNum, quorumDen); err != nil {
+		return nil, fmt.Errorf("call to warp_getAggregateSignature failed. err: %w", err)
+	}
+	return res, nil
+}
+
+func (c *client) GetBlockAcceptanceProof(ctx context.Context, blockHash common.Hash, quorumNum uint64, quorumDen uint64) (*AcceptanceProof, error) {
+	var res *AcceptanceProof
+	if err := c.client.CallContext(ctx, &res, "warp_getBlockAcceptanceProof", blockHash, quorumNum, quorumDen); err != nil {
+		return nil, fmt.Errorf("call to warp_getBlockAcceptanceProof failed. err: %w", err)
+	}
+	return res, nil
+}
+
+func (c *client) GetValidatorSet(ctx context.Context, pChainHeight uint64) (*CanonicalValidatorSet, error) {
+	var res *CanonicalValidatorSet
+	if err := c.client.CallContext(ctx, &res, "warp_getValidatorSet", pChainHeight); err != nil {
+		return nil, fmt.Errorf("call to warp_getValidatorSet failed. err: %w", err)
+	}
+	return res, nil
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/warp/aggregator"
 )
 
+var _ aggregator.SignatureGetter = (*apiFetcher)(nil)
+
 type apiFetcher struct {
 	clients map[ids.NodeID]Client
 }
@@ -39,3 +42,9 @@ func (f *apiFetcher) FetchWarpSignature(ctx context.Context, nodeID ids.NodeID,
 	}
 	return signature, nil
 }
+
+// GetSignature fetches the signature of [unsignedWarpMessage] from the warp API of
+// [nodeID], so that the fetcher can be used as the transport of an aggregator.
+func (f *apiFetcher) GetSignature(ctx context.Context, nodeID ids.NodeID, unsignedWarpMessage *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+	return f.FetchWarpSignature(ctx, nodeID, unsignedWarpMessage)
+}
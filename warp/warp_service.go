@@ -5,23 +5,37 @@ package warp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/warp/aggregator"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // API introduces snowman specific functionality to the evm
 type API struct {
-	backend    Backend
-	aggregator *aggregator.Aggregator
+	networkID      uint32
+	sourceChainID  ids.ID
+	subnetID       ids.ID
+	backend        Backend
+	validatorState avalancheWarp.ValidatorState
+	aggregator     *aggregator.Aggregator
+	validatorSets  *ValidatorSetSigner // nil if validator set messages are disabled
 }
 
-func NewAPI(backend Backend, aggregator *aggregator.Aggregator) *API {
+func NewAPI(networkID uint32, sourceChainID ids.ID, subnetID ids.ID, backend Backend, validatorState avalancheWarp.ValidatorState, aggregator *aggregator.Aggregator, validatorSets *ValidatorSetSigner) *API {
 	return &API{
-		backend:    backend,
-		aggregator: aggregator,
+		networkID:      networkID,
+		sourceChainID:  sourceChainID,
+		subnetID:       subnetID,
+		backend:        backend,
+		validatorState: validatorState,
+		aggregator:     aggregator,
+		validatorSets:  validatorSets,
 	}
 }
 
@@ -34,14 +48,16 @@ func (a *API) GetSignature(ctx context.Context, messageID ids.ID) (hexutil.Bytes
 	return signature[:], nil
 }
 
-// GetAggregateSignature fetches the aggregate signature for the requested [messageID]
-func (a *API) GetAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64) (signedMessageBytes hexutil.Bytes, err error) {
+// GetAggregateSignature fetches the aggregate signature for the requested [messageID].
+// The signers must hold [quorumNum]/[quorumDen] of the stake, where [quorumDen]
+// defaults to [params.WarpQuorumDenominator].
+func (a *API) GetAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, quorumDen *uint64) (signedMessageBytes hexutil.Bytes, err error) {
 	unsignedMessage, err := a.backend.GetMessage(messageID)
 	if err != nil {
 		return nil, err
 	}
 
-	signatureResult, err := a.aggregator.AggregateSignatures(ctx, unsignedMessage, quorumNum)
+	signatureResult, err := a.aggregate(ctx, unsignedMessage, quorumNum, quorumDen)
 	if err != nil {
 		return nil, err
 	}
@@ -50,3 +66,74 @@ func (a *API) GetAggregateSignature(ctx context.Context, messageID ids.ID, quoru
 	// gotchas that could impact signed messages becoming invalid.
 	return hexutil.Bytes(signatureResult.Message.Bytes()), nil
 }
+
+// GetBlockAcceptanceProof returns a proof that the block with [blockHash] was accepted,
+// consisting of the block hash warp message signed by [quorumNum]/[quorumDen] of the
+// stake, where [quorumDen] defaults to [params.WarpQuorumDenominator]. Proofs are only
+// available for blocks accepted while warp was enabled.
+func (a *API) GetBlockAcceptanceProof(ctx context.Context, blockHash common.Hash, quorumNum uint64, quorumDen *uint64) (*AcceptanceProof, error) {
+	unsignedMessage, err := newBlockHashMessage(a.networkID, a.sourceChainID, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	messageID := unsignedMessage.ID()
+	// Validators only sign the block hash message of accepted blocks, so check that
+	// this node accepted the block before requesting signatures for it.
+	if _, err := a.backend.GetMessage(messageID); err != nil {
+		return nil, fmt.Errorf("block %s is not accepted or was accepted while warp was disabled: %w", blockHash, err)
+	}
+
+	signatureResult, err := a.aggregate(ctx, unsignedMessage, quorumNum, quorumDen)
+	if err != nil {
+		return nil, err
+	}
+	return &AcceptanceProof{
+		BlockHash:       blockHash,
+		MessageID:       messageID,
+		SignedMessage:   signatureResult.Message.Bytes(),
+		PChainHeight:    signatureResult.PChainHeight,
+		SignatureWeight: signatureResult.SignatureWeight,
+		TotalWeight:     signatureResult.TotalWeight,
+	}, nil
+}
+
+// GetValidatorSetMessage returns the validator set message at [pChainHeight], or the
+// latest one if [pChainHeight] is nil, signed by [quorumNum]/[quorumDen] of the stake of
+// the validator set at [trustedPChainHeight], where [quorumDen] defaults to
+// [params.WarpQuorumDenominator]. Light clients verify it with [VerifyValidatorSetMessage]
+// against the validator set message at [trustedPChainHeight], which they already trust.
+func (a *API) GetValidatorSetMessage(ctx context.Context, pChainHeight *uint64, trustedPChainHeight uint64, quorumNum uint64, quorumDen *uint64) (hexutil.Bytes, error) {
+	if a.validatorSets == nil {
+		return nil, errors.New("validator set messages are disabled")
+	}
+	unsignedMessage, err := a.validatorSets.Message(ctx, pChainHeight)
+	if err != nil {
+		return nil, err
+	}
+	den := params.WarpQuorumDenominator
+	if quorumDen != nil {
+		den = *quorumDen
+	}
+	signatureResult, err := a.aggregator.AggregateSignaturesAtHeight(ctx, unsignedMessage, trustedPChainHeight, quorumNum, den)
+	if err != nil {
+		return nil, err
+	}
+	return signatureResult.Message.Bytes(), nil
+}
+
+// GetValidatorSet returns the canonical validator set of the subnet at [pChainHeight],
+// with the BLS public keys and weights that aggregate signatures over messages signed at
+// [pChainHeight] are verified against, so that they can be checked independently.
+func (a *API) GetValidatorSet(ctx context.Context, pChainHeight uint64) (*CanonicalValidatorSet, error) {
+	return getCanonicalValidatorSet(ctx, a.validatorState, pChainHeight, a.subnetID)
+}
+
+// aggregate aggregates signatures over [unsignedMessage] from [quorumNum]/[quorumDen]
+// of the stake, where [quorumDen] defaults to [params.WarpQuorumDenominator].
+func (a *API) aggregate(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64, quorumDen *uint64) (*aggregator.AggregateSignatureResult, error) {
+	den := params.WarpQuorumDenominator
+	if quorumDen != nil {
+		den = *quorumDen
+	}
+	return a.aggregator.AggregateSignaturesWithQuorum(ctx, unsignedMessage, quorumNum, den)
+}
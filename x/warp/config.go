@@ -23,6 +23,8 @@ var (
 	_ precompileconfig.Config     = &Config{}
 	_ precompileconfig.Predicater = &Config{}
 	_ precompileconfig.Accepter   = &Config{}
+
+	_ precompileconfig.PChainHeightRecorder = &Config{}
 )
 
 var (
@@ -40,6 +42,9 @@ var (
 type Config struct {
 	precompileconfig.Upgrade
 	QuorumNumerator uint64 `json:"quorumNumerator"`
+	// RecordPChainHeight records the ProposerVM P-Chain height that warp messages are verified against
+	// in the block, so that it can be read by getPChainHeight.
+	RecordPChainHeight bool `json:"recordPChainHeight,omitempty"`
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -100,9 +105,13 @@ func (c *Config) Equal(s precompileconfig.Config) bool {
 		return false
 	}
 	equals := c.Upgrade.Equal(&other.Upgrade)
-	return equals && c.QuorumNumerator == other.QuorumNumerator
+	return equals && c.QuorumNumerator == other.QuorumNumerator && c.RecordPChainHeight == other.RecordPChainHeight
 }
 
+// RecordsPChainHeight returns true if the P-Chain height that the warp predicates of a transaction are
+// verified against should be recorded in its predicate results.
+func (c *Config) RecordsPChainHeight() bool { return c.RecordPChainHeight }
+
 func (c *Config) Accept(acceptCtx *precompileconfig.AcceptContext, txHash common.Hash, logIndex int, topics []common.Hash, logData []byte) error {
 	unsignedMessage, err := warp.ParseUnsignedMessage(logData)
 	if err != nil {
@@ -72,6 +72,12 @@ func TestEqualWarpConfig(t *testing.T) {
 			Expected: false,
 		},
 
+		"different record p-chain height": {
+			Config:   NewDefaultConfig(utils.NewUint64(3)),
+			Other:    &Config{Upgrade: precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)}, RecordPChainHeight: true},
+			Expected: false,
+		},
+
 		"same default config": {
 			Config:   NewDefaultConfig(utils.NewUint64(3)),
 			Other:    NewDefaultConfig(utils.NewUint64(3)),
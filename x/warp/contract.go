@@ -4,13 +4,16 @@
 package warp
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/subnet-evm/accounts/abi"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
 
@@ -23,6 +26,7 @@ import (
 const (
 	GetVerifiedWarpMessageBaseCost uint64 = 2      // Base cost of entering getVerifiedWarpMessage
 	GetBlockchainIDGasCost         uint64 = 2      // Based on GasQuickStep used in existing EVM instructions
+	GetPChainHeightGasCost         uint64 = 2      // Based on GasQuickStep used in existing EVM instructions
 	AddWarpMessageGasCost          uint64 = 20_000 // Cost of producing and serving a BLS Signature
 	// Sum of base log gas cost, cost of producing 4 topics, and producing + serving a BLS Signature (sign + trie write)
 	// Note: using trie write for the gas cost results in a conservative overestimate since the message is stored in a
@@ -77,6 +81,11 @@ type GetVerifiedWarpMessageOutput struct {
 	Message WarpMessage
 	Valid   bool
 }
+
+type GetPChainHeightOutput struct {
+	PChainHeight uint64
+	Valid        bool
+}
 type SendWarpMessageInput struct {
 	DestinationChainID common.Hash
 	DestinationAddress common.Address
@@ -109,6 +118,56 @@ func getBlockchainID(accessibleState contract.AccessibleState, caller common.Add
 	return packedOutput, remainingGas, nil
 }
 
+// PackGetPChainHeight packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetPChainHeight() ([]byte, error) {
+	return WarpABI.Pack("getPChainHeight")
+}
+
+// PackGetPChainHeightOutput attempts to pack given [outputStruct] of type GetPChainHeightOutput
+// to conform the ABI outputs.
+func PackGetPChainHeightOutput(outputStruct GetPChainHeightOutput) ([]byte, error) {
+	return WarpABI.PackOutput("getPChainHeight",
+		outputStruct.PChainHeight,
+		outputStruct.Valid,
+	)
+}
+
+// UnpackGetPChainHeightOutput attempts to unpack [output] as GetPChainHeightOutput
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+func UnpackGetPChainHeightOutput(output []byte) (GetPChainHeightOutput, error) {
+	outputStruct := GetPChainHeightOutput{}
+	err := WarpABI.UnpackIntoInterface(&outputStruct, "getPChainHeight", output)
+
+	return outputStruct, err
+}
+
+// getPChainHeight returns the ProposerVM P-Chain height that the warp messages of the current transaction
+// were verified against. The height is only available if the transaction includes a warp predicate and
+// the precompile is configured to record it.
+func getPChainHeight(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetPChainHeightGasCost); err != nil {
+		return nil, 0, err
+	}
+	var output GetPChainHeightOutput
+	heightBytes := accessibleState.GetBlockContext().GetPredicateResults(accessibleState.GetStateDB().GetTxHash(), precompileconfig.PChainHeightResultsKey)
+	if len(heightBytes) == wrappers.LongLen {
+		output.PChainHeight = binary.BigEndian.Uint64(heightBytes)
+		output.Valid = true
+	}
+	packedOutput, err := PackGetPChainHeightOutput(output)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Return the packed output and the remaining gas
+	return packedOutput, remainingGas, nil
+}
+
+func isPChainHeightActivated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetChainConfig().IsDUpgrade(accessibleState.GetBlockContext().Timestamp())
+}
+
 // UnpackGetVerifiedWarpBlockHashInput attempts to unpack [input] into the uint32 type argument
 // assumes that [input] does not include selector (omits first 4 func signature bytes)
 func UnpackGetVerifiedWarpBlockHashInput(input []byte) (uint32, error) {
@@ -290,6 +349,10 @@ func createWarpPrecompile() contract.StatefulPrecompiledContract {
 		}
 		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
 	}
+	// getPChainHeight was added after the precompile was released, so it is activated by the
+	// DUpgrade to avoid changing the result of existing calls to its selector.
+	functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(WarpABI.Methods["getPChainHeight"].ID, getPChainHeight, isPChainHeightActivated))
+
 	// Construct the contract with no fallback function.
 	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
 	if err != nil {
@@ -4,6 +4,7 @@
 package warp
 
 import (
+	"encoding/binary"
 	"math"
 	"math/big"
 	"testing"
@@ -15,12 +16,14 @@ import (
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/precompile/contract"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
 	"github.com/ava-labs/subnet-evm/precompile/testutils"
 	predicateutils "github.com/ava-labs/subnet-evm/utils/predicate"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	warpPayload "github.com/ava-labs/subnet-evm/warp/payload"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestGetBlockchainID(t *testing.T) {
@@ -81,6 +84,65 @@ func TestGetBlockchainID(t *testing.T) {
 	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
 }
 
+func TestGetPChainHeight(t *testing.T) {
+	callerAddr := common.HexToAddress("0x0123")
+	pChainHeight := uint64(1337)
+	getPChainHeightInput, err := PackGetPChainHeight()
+	require.NoError(t, err)
+
+	tests := map[string]testutils.PrecompileTest{
+		"getPChainHeight success": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return getPChainHeightInput },
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetPredicateResults(common.Hash{}, precompileconfig.PChainHeightResultsKey).Return(binary.BigEndian.AppendUint64(nil, pChainHeight))
+			},
+			SuppliedGas: GetPChainHeightGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetPChainHeightOutput(GetPChainHeightOutput{PChainHeight: pChainHeight, Valid: true})
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+		"getPChainHeight not recorded": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return getPChainHeightInput },
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetPredicateResults(common.Hash{}, precompileconfig.PChainHeightResultsKey).Return(nil)
+			},
+			SuppliedGas: GetPChainHeightGasCost,
+			ReadOnly:    false,
+			ExpectedRes: func() []byte {
+				res, err := PackGetPChainHeightOutput(GetPChainHeightOutput{})
+				require.NoError(t, err)
+				return res
+			}(),
+		},
+		"getPChainHeight before DUpgrade": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return getPChainHeightInput },
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsDUpgrade(gomock.Any()).Return(false).AnyTimes()
+				return config
+			}(),
+			SuppliedGas: GetPChainHeightGasCost,
+			ReadOnly:    true,
+			ExpectedErr: "invalid non-activated function selector",
+		},
+		"getPChainHeight insufficient gas": {
+			Caller:      callerAddr,
+			InputFn:     func(t testing.TB) []byte { return getPChainHeightInput },
+			SuppliedGas: GetPChainHeightGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
+
+	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
+}
+
 func TestSendWarpMessage(t *testing.T) {
 	callerAddr := common.HexToAddress("0x0123")
 	receiverAddr := common.HexToAddress("0x456789")
@@ -693,3 +755,13 @@ func TestGetVerifiedWarpBlockHash(t *testing.T) {
 
 	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
 }
+
+func TestWarpReadOnly(t *testing.T) {
+	testutils.RunReadOnlyTests(t, Module, state.NewTestStateDB, testutils.ReadOnlyTest{
+		Caller: common.Address{1},
+		SetupBlockContext: func(mbc *contract.MockBlockContext) {
+			mbc.EXPECT().GetPredicateResults(gomock.Any(), ContractAddress).Return(nil).AnyTimes()
+		},
+		Functions: testutils.ABIReadOnlyFunctions(t, WarpABI),
+	})
+}